@@ -26,6 +26,7 @@ func main() {
 	app.AddCommand(console.NewMakeMiddlewareCommand(generator))
 	app.AddCommand(console.NewMakeMigrationCommand(generator))
 	app.AddCommand(console.NewMakeTestCommand(generator))
+	app.AddCommand(console.NewMakeCommandCommand(generator))
 
 	// =============================================================================
 	// go-zero 增强命令 (类似 goctl 功能)
@@ -33,6 +34,11 @@ func main() {
 	app.AddCommand(console.NewGoZeroMakeRpcCommand(goZeroGenerator))
 	app.AddCommand(console.NewGoZeroMakeApiCommand(goZeroGenerator))
 
+	// =============================================================================
+	// 微服务命令
+	// =============================================================================
+	app.AddCommand(console.NewGatewayOpenAPICommand(output))
+
 	// =============================================================================
 	// 项目维护命令
 	// =============================================================================
@@ -60,6 +66,11 @@ func main() {
 	app.AddCommand(console.NewProjectInfoCommand(output))
 	app.AddCommand(console.NewVersionCommand(output))
 
+	// =============================================================================
+	// 自定义命令 (通过 console.RegisterCommand 注册)
+	// =============================================================================
+	app.DiscoverCommands()
+
 	// 运行应用
 	if err := app.Run(os.Args[1:]); err != nil {
 		output.Error(err.Error())
@@ -4,11 +4,17 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"time"
 
 	"laravel-go/framework/event"
+	"laravel-go/framework/mail"
 )
 
+// mailer 演示使用的邮件发送器，log 驱动将邮件内容输出到控制台；
+// 生产环境可通过 mail.NewMailer 使用 smtp 驱动
+var mailer mail.Mailer = mail.NewLogMailer(os.Stdout, "Laravel-Go <noreply@example.com>")
+
 // UserRegisteredEvent 用户注册事件
 type UserRegisteredEvent struct {
 	*event.BaseEvent
@@ -59,18 +65,28 @@ func NewEmailNotificationListener() *EmailNotificationListener {
 			fmt.Printf("📧 发送邮件通知: %s\n", e.GetName())
 
 			// 根据事件类型发送不同的邮件
+			var message *mail.Message
 			switch e.GetName() {
 			case "user.registered":
 				if userEvent, ok := e.(*UserRegisteredEvent); ok {
-					fmt.Printf("   欢迎邮件发送给: %s (%s)\n", userEvent.Username, userEvent.Email)
+					message = mail.NewMessage().
+						To(fmt.Sprintf("%s <%s>", userEvent.Username, userEvent.Email)).
+						Subject("欢迎注册 Laravel-Go").
+						Text(fmt.Sprintf("%s，欢迎加入！", userEvent.Username))
 				}
 			case "order.created":
 				if orderEvent, ok := e.(*OrderCreatedEvent); ok {
-					fmt.Printf("   订单确认邮件发送给用户: %d, 订单金额: %.2f\n", orderEvent.UserID, orderEvent.Amount)
+					message = mail.NewMessage().
+						To(fmt.Sprintf("user%d@example.com", orderEvent.UserID)).
+						Subject(fmt.Sprintf("订单 #%d 确认", orderEvent.OrderID)).
+						Text(fmt.Sprintf("订单金额: %.2f，商品: %v", orderEvent.Amount, orderEvent.Products))
 				}
 			}
+			if message == nil {
+				return nil
+			}
 
-			return nil
+			return mailer.Send(message)
 		}),
 	}
 }
@@ -223,10 +239,11 @@ func main() {
 		NewUserRegisteredEvent(4, "alice_jones", "alice@example.com"),
 	}
 
-	err = event.DispatchBatch(batchEvents)
-	if err != nil {
-		log.Fatalf("Failed to dispatch batch events: %v", err)
+	result := event.DispatchBatch(batchEvents)
+	for _, failure := range result.Failed {
+		log.Printf("Failed to dispatch event %s: %v", failure.Event.GetName(), failure.Err)
 	}
+	fmt.Printf("   成功: %d, 失败: %d\n", len(result.Succeeded), len(result.Failed))
 
 	// 等待所有事件处理完成
 	time.Sleep(500 * time.Millisecond)
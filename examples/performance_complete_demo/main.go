@@ -89,7 +89,7 @@ func addAlertRules(alertSystem *performance.AlertSystem) {
 		ID:          "error_rate_high",
 		Name:        "错误率过高",
 		Description: "HTTP错误率超过5%",
-		MetricName:  "http_errors_total",
+		MetricName:  "http_error_rate",
 		Condition:   ">",
 		Threshold:   5.0,
 		Level:       performance.AlertLevelCritical,
@@ -131,16 +131,14 @@ func simulateHTTPRequests(httpMonitor *performance.HTTPMonitor) {
 		// 模拟处理时间
 		time.Sleep(time.Duration(10+i*5) * time.Millisecond)
 
-		// 记录响应
-		statusCode := 200
-		if i%10 == 0 { // 偶尔产生错误
-			statusCode = 500
-		}
-		httpMonitor.RecordResponse(method, endpoint, statusCode, 2048, time.Duration(10+i*5)*time.Millisecond)
-
-		if statusCode >= 400 {
+		// 偶尔产生没有响应的失败请求，用 RecordError 结束请求
+		if i%10 == 0 {
 			httpMonitor.RecordError(method, endpoint)
+			continue
 		}
+
+		// 记录响应
+		httpMonitor.RecordResponse(method, endpoint, 200, 2048, time.Duration(10+i*5)*time.Millisecond)
 	}
 }
 
@@ -117,7 +117,8 @@ func demonstrateHTTPMonitoring(httpMonitor *performance.HTTPMonitor, monitor per
 	time.Sleep(20 * time.Millisecond)
 	httpMonitor.RecordResponse("GET", "/api/invalid", 404, 200, 20*time.Millisecond)
 
-	// 记录错误
+	// 没有响应的失败请求
+	httpMonitor.RecordRequest("GET", "/api/error", 100)
 	httpMonitor.RecordError("GET", "/api/error")
 
 	fmt.Println("HTTP监控指标:")
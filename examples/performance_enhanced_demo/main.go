@@ -61,7 +61,6 @@ func (ehm *EnhancedHTTPMonitor) RecordResponseWithErrorHandling(method, path str
 	// 模拟错误率
 	if time.Now().UnixNano()%100 < int64(ehm.errorRate*100) {
 		statusCode = 500
-		ehm.RecordError(method, path)
 	}
 
 	ehm.RecordResponse(method, path, statusCode, size, duration)
@@ -226,7 +225,7 @@ func addEnhancedAlertRules(alertSystem *EnhancedAlertSystem) {
 		ID:          "error_rate_high",
 		Name:        "错误率过高",
 		Description: "HTTP错误率超过3%",
-		MetricName:  "http_errors_total",
+		MetricName:  "http_error_rate",
 		Condition:   ">",
 		Threshold:   3.0,
 		Level:       performance.AlertLevelCritical,
@@ -328,19 +328,17 @@ func simulateUltraHTTPRequests(httpMonitor *performance.HTTPMonitor) {
 		// 记录请求
 		httpMonitor.RecordRequest(method, path, int64(100+i*10))
 
-		// 模拟响应时间
-		responseTime := time.Duration(50+i*5) * time.Millisecond
-		statusCode := 200
+		// 模拟没有响应的失败请求，用 RecordError 结束请求
 		if i%20 == 0 {
-			statusCode = 500 // 模拟错误
+			httpMonitor.RecordError(method, path)
+			continue
 		}
 
-		// 记录响应
-		httpMonitor.RecordResponse(method, path, statusCode, int64(1024+i*100), responseTime)
+		// 模拟响应时间
+		responseTime := time.Duration(50+i*5) * time.Millisecond
 
-		if statusCode >= 400 {
-			httpMonitor.RecordError(method, path)
-		}
+		// 记录响应
+		httpMonitor.RecordResponse(method, path, 200, int64(1024+i*100), responseTime)
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -222,6 +223,185 @@ func TestResourceCollection(t *testing.T) {
 	}
 }
 
+// TestPaginateWithLinks 测试分页导航链接
+func TestPaginateWithLinks(t *testing.T) {
+	users := make([]*TestUser, 25)
+	for i := range users {
+		users[i] = &TestUser{ID: i + 1, Name: "User"}
+	}
+	collection := NewResourceCollection(users)
+	baseURL := "https://api.example.com/users?sort=name"
+	link := func(page int) string {
+		return "https://api.example.com/users?page=" + strconv.Itoa(page) + "&per_page=10&sort=name"
+	}
+
+	// 首页没有 prev
+	first := collection.PaginateWithLinks(baseURL, 1, 10)
+	if first.Links.Prev != "" || first.Links.Next != link(2) || first.Links.Self != link(1) {
+		t.Errorf("Unexpected first page links: %+v", first.Links)
+	}
+	if first.Links.First != link(1) || first.Links.Last != link(3) {
+		t.Errorf("Unexpected first/last links: %+v", first.Links)
+	}
+	if header := first.LinkHeader(); header != `<`+link(1)+`>; rel="first", <`+link(2)+`>; rel="next", <`+link(3)+`>; rel="last"` {
+		t.Errorf("Unexpected Link header: %s", header)
+	}
+
+	// 中间页同时有 prev 和 next
+	middle := collection.PaginateWithLinks(baseURL, 2, 10)
+	if middle.Links.Prev != link(1) || middle.Links.Next != link(3) {
+		t.Errorf("Unexpected middle page links: %+v", middle.Links)
+	}
+	if middle.Meta.From != 11 || middle.Meta.To != 20 || len(middle.ToArray()) != 10 {
+		t.Errorf("Unexpected middle page meta: %+v", middle.Meta)
+	}
+
+	// 末页不完整且没有 next
+	last := collection.PaginateWithLinks(baseURL, 3, 10)
+	if last.Links.Prev != link(2) || last.Links.Next != "" {
+		t.Errorf("Unexpected last page links: %+v", last.Links)
+	}
+	if last.Meta.LastPage != 3 || last.Meta.From != 21 || last.Meta.To != 25 || len(last.ToArray()) != 5 {
+		t.Errorf("Unexpected last page meta: %+v", last.Meta)
+	}
+
+	recorder := httptest.NewRecorder()
+	last.WriteHeaders(recorder.Header())
+	if recorder.Header().Get("X-Total-Count") != "25" || strings.Contains(recorder.Header().Get("Link"), `rel="next"`) {
+		t.Errorf("Unexpected headers: %v", recorder.Header())
+	}
+
+	jsonData, err := last.ToJSON()
+	if err != nil {
+		t.Fatalf("Failed to convert paginated collection to JSON: %v", err)
+	}
+	var body struct {
+		Data  []map[string]interface{} `json:"data"`
+		Links map[string]string        `json:"links"`
+		Meta  PaginationMeta           `json:"meta"`
+	}
+	if err := json.Unmarshal(jsonData, &body); err != nil {
+		t.Fatalf("Failed to unmarshal paginated JSON: %v", err)
+	}
+	if len(body.Data) != 5 || body.Links["prev"] != link(2) || body.Meta.Total != 25 {
+		t.Errorf("Unexpected paginated JSON: %s", jsonData)
+	}
+	if _, exists := body.Links["next"]; exists {
+		t.Error("Expected next link to be omitted on the last page")
+	}
+
+	// 空集合只有一页
+	empty := NewResourceCollection([]*TestUser{}).PaginateWithLinks("/users", 1, 10)
+	if empty.Meta.LastPage != 1 || empty.Meta.From != 0 || empty.Links.Next != "" || empty.Links.Prev != "" {
+		t.Errorf("Unexpected empty pagination: %+v %+v", empty.Meta, empty.Links)
+	}
+	if empty.Links.Last != "/users?page=1&per_page=10" {
+		t.Errorf("Unexpected relative link: %s", empty.Links.Last)
+	}
+}
+
+// TestConditionalRequests 测试 ETag 和 Last-Modified 条件请求
+func TestConditionalRequests(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	user := &TestUser{ID: 1, Name: "John Doe", Email: "john@example.com", UpdatedAt: updatedAt}
+	resource := NewResource(user)
+
+	if resource.ETag() != NewResource(user).ETag() {
+		t.Error("Expected ETag to be stable for the same content")
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		ServeResource(w, r, resource)
+	}
+
+	// 首次请求返回 200 并带有缓存头
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest("GET", "/users/1", nil))
+	etag := recorder.Header().Get("ETag")
+	if recorder.Code != http.StatusOK || etag == "" || recorder.Body.Len() == 0 {
+		t.Fatalf("Expected 200 with ETag and body, got %d %q", recorder.Code, etag)
+	}
+	if recorder.Header().Get("Last-Modified") != "Tue, 02 Jan 2024 03:04:05 GMT" {
+		t.Errorf("Unexpected Last-Modified: %s", recorder.Header().Get("Last-Modified"))
+	}
+
+	// 匹配的 If-None-Match 返回 304
+	request := httptest.NewRequest("GET", "/users/1", nil)
+	request.Header.Set("If-None-Match", `"stale", `+etag)
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	if recorder.Code != http.StatusNotModified || recorder.Body.Len() != 0 {
+		t.Errorf("Expected 304 without body, got %d", recorder.Code)
+	}
+
+	// 过期的 If-None-Match 返回 200 和内容
+	user.Name = "Johnny"
+	request = httptest.NewRequest("GET", "/users/1", nil)
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), "Johnny") || recorder.Header().Get("ETag") == etag {
+		t.Errorf("Expected 200 with updated body, got %d", recorder.Code)
+	}
+
+	// If-Modified-Since 不早于最后修改时间时返回 304
+	request = httptest.NewRequest("GET", "/users/1", nil)
+	request.Header.Set("If-Modified-Since", updatedAt.Format(http.TimeFormat))
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	if recorder.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for If-Modified-Since, got %d", recorder.Code)
+	}
+
+	// 集合的组合 ETag 随资源内容变化
+	users := []*TestUser{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}
+	before := NewResourceCollection(users).ETag()
+	users[1].Name = "C"
+	if after := NewResourceCollection(users).ETag(); after == before {
+		t.Error("Expected collection ETag to change with its resources")
+	}
+}
+
+// TestConditionalMiddleware 测试条件请求中间件
+func TestConditionalMiddleware(t *testing.T) {
+	body := `{"id":1}`
+	handler := NewConditionalMiddleware().Handle(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest("GET", "/items/1", nil))
+	etag := recorder.Header().Get("ETag")
+	if recorder.Code != http.StatusOK || etag == "" || recorder.Body.String() != body {
+		t.Fatalf("Expected 200 with computed ETag, got %d %q", recorder.Code, etag)
+	}
+
+	request := httptest.NewRequest("GET", "/items/1", nil)
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	if recorder.Code != http.StatusNotModified || recorder.Body.Len() != 0 {
+		t.Errorf("Expected 304, got %d", recorder.Code)
+	}
+
+	body = `{"id":1,"name":"changed"}`
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Body.String() != body {
+		t.Errorf("Expected 200 with new body for stale ETag, got %d", recorder.Code)
+	}
+
+	// 非 GET 请求不做条件处理
+	request = httptest.NewRequest("POST", "/items", nil)
+	request.Header.Set("If-None-Match", "*")
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("ETag") != "" {
+		t.Errorf("Expected POST to bypass conditional handling, got %d", recorder.Code)
+	}
+}
+
 // TestVersionManager 测试版本管理器
 func TestVersionManager(t *testing.T) {
 	vm := NewVersionManager()
@@ -608,4 +788,30 @@ func TestConvenienceFunctions(t *testing.T) {
 	if vr == nil {
 		t.Error("VersionRouter should not be nil")
 	}
-} 
\ No newline at end of file
+} 
+// TestFromPaginator 测试查询构建器分页结果转换
+func TestFromPaginator(t *testing.T) {
+	result := map[string]interface{}{
+		"data": []map[string]interface{}{
+			{"id": int64(3), "title": "c"},
+			{"id": int64(4), "title": "d"},
+		},
+		"total":        int64(5),
+		"per_page":     2,
+		"current_page": 2,
+		"last_page":    3,
+		"from":         3,
+		"to":           4,
+	}
+
+	paginated := FromPaginator(result, "/posts")
+	if paginated.Meta.Total != 5 || paginated.Meta.CurrentPage != 2 || paginated.Meta.From != 3 || paginated.Meta.To != 4 {
+		t.Errorf("Unexpected meta: %+v", paginated.Meta)
+	}
+	if paginated.Links.Prev != "/posts?page=1&per_page=2" || paginated.Links.Next != "/posts?page=3&per_page=2" {
+		t.Errorf("Unexpected links: %+v", paginated.Links)
+	}
+	if data := paginated.ToArray(); len(data) != 2 || data[0]["title"] != "c" {
+		t.Errorf("Unexpected data: %v", data)
+	}
+}
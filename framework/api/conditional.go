@@ -0,0 +1,259 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// lastModifiedFields 用于推断资源最后修改时间的字段名
+var lastModifiedFields = []string{"UpdatedAt", "ModifiedAt", "CreatedAt"}
+
+// ETag 根据资源序列化后的内容计算强 ETag
+func (r *BaseResource) ETag() string {
+	data, err := r.ToJSON()
+	if err != nil {
+		return ""
+	}
+	return computeETag(data)
+}
+
+// LastModified 获取资源最后修改时间，依次读取 UpdatedAt、ModifiedAt、CreatedAt 字段
+func (r *BaseResource) LastModified() time.Time {
+	value := reflect.ValueOf(r.data)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return time.Time{}
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		for _, name := range lastModifiedFields {
+			field := value.FieldByName(name)
+			if field.IsValid() && field.CanInterface() {
+				if t, ok := field.Interface().(time.Time); ok && !t.IsZero() {
+					return t
+				}
+			}
+		}
+	case reflect.Map:
+		if m, ok := value.Interface().(map[string]interface{}); ok {
+			for _, key := range []string{"updated_at", "modified_at", "created_at"} {
+				if t, ok := m[key].(time.Time); ok && !t.IsZero() {
+					return t
+				}
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// ETag 由集合中每个资源的 ETag 组合计算，资源内容或顺序变化都会改变 ETag
+func (c *BaseCollection) ETag() string {
+	hash := sha256.New()
+	for _, resource := range c.resources {
+		hash.Write([]byte(resource.ETag()))
+		hash.Write([]byte{'\n'})
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`
+}
+
+// LastModified 获取集合中资源最新的修改时间
+func (c *BaseCollection) LastModified() time.Time {
+	var latest time.Time
+	for _, resource := range c.resources {
+		if modifier, ok := resource.(interface{ LastModified() time.Time }); ok {
+			if t := modifier.LastModified(); t.After(latest) {
+				latest = t
+			}
+		}
+	}
+	return latest
+}
+
+// ETag 分页集合的 ETag 同时包含分页数据、链接和元数据
+func (p *PaginatedCollection) ETag() string {
+	data, err := p.ToJSON()
+	if err != nil {
+		return ""
+	}
+	return computeETag(data)
+}
+
+// LastModified 获取当前页资源最新的修改时间
+func (p *PaginatedCollection) LastModified() time.Time {
+	if modifier, ok := p.Collection.(interface{ LastModified() time.Time }); ok {
+		return modifier.LastModified()
+	}
+	return time.Time{}
+}
+
+// computeETag 计算内容的强 ETag
+func computeETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// CacheableResource 支持条件请求的资源，Resource 和 Collection 都满足该接口
+type CacheableResource interface {
+	ToJSON() ([]byte, error)
+	ETag() string
+}
+
+// ServeResource 以 JSON 输出资源，设置 ETag 和 Last-Modified 响应头，
+// 客户端缓存仍然有效时返回 304 Not Modified
+func ServeResource(w http.ResponseWriter, r *http.Request, resource CacheableResource) {
+	data, err := resource.ToJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var lastModified time.Time
+	if modifier, ok := resource.(interface{ LastModified() time.Time }); ok {
+		lastModified = modifier.LastModified()
+	}
+	setCacheHeaders(w.Header(), resource.ETag(), lastModified)
+
+	if isNotModified(r, w.Header()) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(data)
+	}
+}
+
+// ConditionalMiddleware 条件请求中间件
+//
+// 对 GET 和 HEAD 请求缓冲 200 响应，处理程序未设置 ETag 时根据响应内容计算，
+// 然后按 If-None-Match 和 If-Modified-Since 判断是否返回 304 Not Modified。
+type ConditionalMiddleware struct{}
+
+// NewConditionalMiddleware 创建条件请求中间件
+func NewConditionalMiddleware() *ConditionalMiddleware {
+	return &ConditionalMiddleware{}
+}
+
+// Handle 处理条件请求
+func (cm *ConditionalMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next(w, r)
+			return
+		}
+
+		recorder := &bufferedResponseWriter{header: make(http.Header), status: http.StatusOK}
+		next(recorder, r)
+
+		header := w.Header()
+		for key, values := range recorder.header {
+			header[key] = values
+		}
+
+		if recorder.status == http.StatusOK {
+			if header.Get("ETag") == "" {
+				header.Set("ETag", computeETag(recorder.body.Bytes()))
+			}
+			if isNotModified(r, header) {
+				header.Del("Content-Type")
+				header.Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+
+		w.WriteHeader(recorder.status)
+		if r.Method != http.MethodHead {
+			w.Write(recorder.body.Bytes())
+		}
+	}
+}
+
+// bufferedResponseWriter 缓冲响应的 ResponseWriter
+type bufferedResponseWriter struct {
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(data)
+}
+
+// setCacheHeaders 设置 ETag 和 Last-Modified 响应头
+func setCacheHeaders(header http.Header, etag string, lastModified time.Time) {
+	if etag != "" {
+		header.Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// isNotModified 判断客户端缓存是否仍然有效，If-None-Match 优先于 If-Modified-Since
+func isNotModified(r *http.Request, header http.Header) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, header.Get("ETag"))
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	lastModified := header.Get("Last-Modified")
+	if ims == "" || lastModified == "" {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// etagMatches 按弱比较规则判断 If-None-Match 是否匹配当前 ETag
+func etagMatches(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	current := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == current {
+			return true
+		}
+	}
+	return false
+}
@@ -145,6 +145,7 @@ type MediaType struct {
 
 // Schema 模式
 type Schema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
 	Type                 string                 `json:"type,omitempty"`
 	Format               string                 `json:"format,omitempty"`
 	Description          string                 `json:"description,omitempty"`
@@ -165,6 +166,7 @@ type Schema struct {
 	Not                  *Schema                `json:"not,omitempty"`
 	AdditionalProperties *Schema                `json:"additionalProperties,omitempty"`
 	Discriminator        *Discriminator         `json:"discriminator,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
 }
 
 // XML XML 信息
@@ -298,6 +300,11 @@ func NewAPIDocumentation(title, version, description string) *APIDocumentation {
 	}
 }
 
+// Spec 获取生成的 OpenAPI 规范
+func (ad *APIDocumentation) Spec() *OpenAPISpec {
+	return ad.spec
+}
+
 // SetBasePath 设置基础路径
 func (ad *APIDocumentation) SetBasePath(basePath string) *APIDocumentation {
 	ad.basePath = basePath
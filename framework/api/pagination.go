@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PaginationLinks 分页导航链接，首页没有 prev，末页没有 next
+type PaginationLinks struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Self  string `json:"self"`
+}
+
+// PaginationMeta 分页元数据
+type PaginationMeta struct {
+	CurrentPage int `json:"current_page"`
+	PerPage     int `json:"per_page"`
+	Total       int `json:"total"`
+	LastPage    int `json:"last_page"`
+	From        int `json:"from"`
+	To          int `json:"to"`
+}
+
+// PaginatedCollection 带导航链接的分页集合
+type PaginatedCollection struct {
+	Collection
+	Links PaginationLinks
+	Meta  PaginationMeta
+}
+
+// PaginateWithLinks 分页并生成导航链接
+//
+// 链接基于 baseURL 生成，保留其中已有的查询参数，并设置 page 和 per_page 参数。
+func (c *BaseCollection) PaginateWithLinks(baseURL string, page, perPage int) *PaginatedCollection {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	total := len(c.resources)
+	lastPage := (total + perPage - 1) / perPage
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	meta := PaginationMeta{
+		CurrentPage: page,
+		PerPage:     perPage,
+		Total:       total,
+		LastPage:    lastPage,
+	}
+	if start := (page - 1) * perPage; start < total {
+		meta.From = start + 1
+		meta.To = start + perPage
+		if meta.To > total {
+			meta.To = total
+		}
+	}
+
+	return &PaginatedCollection{
+		Collection: c.Paginate(page, perPage),
+		Links:      paginationLinks(baseURL, meta),
+		Meta:       meta,
+	}
+}
+
+// FromPaginator 将查询构建器 Paginate 返回的分页结果转换为带导航链接的分页集合
+func FromPaginator(result map[string]interface{}, baseURL string) *PaginatedCollection {
+	meta := PaginationMeta{
+		CurrentPage: paginatorInt(result["current_page"]),
+		PerPage:     paginatorInt(result["per_page"]),
+		Total:       paginatorInt(result["total"]),
+		LastPage:    paginatorInt(result["last_page"]),
+		From:        paginatorInt(result["from"]),
+		To:          paginatorInt(result["to"]),
+	}
+	if meta.LastPage < 1 {
+		meta.LastPage = 1
+	}
+
+	return &PaginatedCollection{
+		Collection: NewCollectionFromData(result["data"]),
+		Links:      paginationLinks(baseURL, meta),
+		Meta:       meta,
+	}
+}
+
+// paginationLinks 根据分页元数据生成导航链接
+func paginationLinks(baseURL string, meta PaginationMeta) PaginationLinks {
+	pageURL := func(p int) string {
+		return buildPageURL(baseURL, p, meta.PerPage)
+	}
+	links := PaginationLinks{
+		First: pageURL(1),
+		Last:  pageURL(meta.LastPage),
+		Self:  pageURL(meta.CurrentPage),
+	}
+	if meta.CurrentPage > 1 {
+		prev := meta.CurrentPage - 1
+		if prev > meta.LastPage {
+			prev = meta.LastPage
+		}
+		links.Prev = pageURL(prev)
+	}
+	if meta.CurrentPage < meta.LastPage {
+		links.Next = pageURL(meta.CurrentPage + 1)
+	}
+	return links
+}
+
+// paginatorInt 读取分页结果中的整数
+func paginatorInt(value interface{}) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// ToResponse 转换为包含 data、links 和 meta 的响应结构
+func (p *PaginatedCollection) ToResponse() map[string]interface{} {
+	return map[string]interface{}{
+		"data":  p.Collection.ToArray(),
+		"links": p.Links,
+		"meta":  p.Meta,
+	}
+}
+
+// ToJSON 转换为包含 data、links 和 meta 的 JSON
+func (p *PaginatedCollection) ToJSON() ([]byte, error) {
+	return json.Marshal(p.ToResponse())
+}
+
+// LinkHeader 生成 RFC 5988 格式的 Link 响应头
+func (p *PaginatedCollection) LinkHeader() string {
+	relations := []struct {
+		rel string
+		url string
+	}{
+		{"first", p.Links.First},
+		{"prev", p.Links.Prev},
+		{"next", p.Links.Next},
+		{"last", p.Links.Last},
+	}
+
+	parts := make([]string, 0, len(relations))
+	for _, relation := range relations {
+		if relation.url != "" {
+			parts = append(parts, fmt.Sprintf("<%s>; rel=%q", relation.url, relation.rel))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// WriteHeaders 设置 Link 和 X-Total-Count 响应头
+func (p *PaginatedCollection) WriteHeaders(header http.Header) {
+	header.Set("Link", p.LinkHeader())
+	header.Set("X-Total-Count", strconv.Itoa(p.Meta.Total))
+}
+
+// buildPageURL 生成指定页码的链接
+func buildPageURL(baseURL string, page, perPage int) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Sprintf("%s?page=%d&per_page=%d", baseURL, page, perPage)
+	}
+
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(perPage))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
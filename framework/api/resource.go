@@ -21,6 +21,8 @@ type Resource interface {
 	When(condition bool, fields ...string) Resource
 	// Merge 合并其他资源
 	Merge(resource Resource) Resource
+	// ETag 根据序列化内容计算的实体标签
+	ETag() string
 }
 
 // Collection 集合转换器接口
@@ -41,6 +43,8 @@ type Collection interface {
 	Filter(fn func(Resource) bool) Collection
 	// Paginate 分页
 	Paginate(page, perPage int) Collection
+	// ETag 由所有资源组合计算的实体标签
+	ETag() string
 }
 
 // BaseResource 基础资源转换器
@@ -73,6 +77,20 @@ func (r *BaseResource) ToArray() map[string]interface{} {
 		v = v.Elem()
 	}
 	
+	// 查询构建器返回的行数据
+	if row, ok := r.data.(map[string]interface{}); ok {
+		for fieldName, fieldValue := range row {
+			if r.shouldHide(fieldName) || !r.shouldInclude(fieldName) {
+				continue
+			}
+			result[fieldName] = fieldValue
+		}
+		for key, value := range r.additional {
+			result[key] = value
+		}
+		return result
+	}
+	
 	if v.Kind() != reflect.Struct {
 		// 如果不是结构体，直接返回
 		return result
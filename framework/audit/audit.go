@@ -0,0 +1,376 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"laravel-go/framework/auth"
+	"laravel-go/framework/database"
+	"laravel-go/framework/event"
+)
+
+// 审计动作
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+	ActionDeleted = "deleted"
+	ActionLogin   = "auth.login"
+	ActionLogout  = "auth.logout"
+	ActionFailed  = "auth.failed"
+)
+
+// Redacted 脱敏后的字段值
+const Redacted = "[REDACTED]"
+
+// ErrNoSink 未配置审计日志输出
+var ErrNoSink = errors.New("audit sink not configured")
+
+// Change 字段变更前后的值
+type Change struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Entry 审计日志条目
+type Entry struct {
+	ID        string                 `json:"id"`
+	Actor     string                 `json:"actor"`
+	Action    string                 `json:"action"`
+	Subject   string                 `json:"subject"`
+	SubjectID string                 `json:"subject_id,omitempty"`
+	Changes   map[string]Change      `json:"changes,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	IP        string                 `json:"ip,omitempty"`
+	UserAgent string                 `json:"user_agent,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// Sink 审计日志输出
+type Sink interface {
+	Write(entry *Entry) error
+}
+
+// Actor 操作者，由 ActorResolver 提供
+type Actor struct {
+	ID        string
+	IP        string
+	UserAgent string
+}
+
+// ActorResolver 解析当前操作者，没有已认证用户时返回空的 ID
+type ActorResolver func() Actor
+
+// Auditor 审计记录器
+//
+// 模型事件自动记录为 created、updated、deleted 条目，更新时只记录发生变化的列。
+// password、remember_token 以及使用 encrypted 类型转换的列默认脱敏，
+// 时间戳列默认不计入差异。
+type Auditor struct {
+	sink     Sink
+	resolver ActorResolver
+	redact   map[string]bool
+	ignore   map[string]bool
+	mu       sync.RWMutex
+}
+
+// NewAuditor 创建审计记录器
+func NewAuditor(sink Sink) *Auditor {
+	return &Auditor{
+		sink:   sink,
+		redact: map[string]bool{"password": true, "remember_token": true},
+		ignore: map[string]bool{"created_at": true, "updated_at": true},
+	}
+}
+
+// Redact 设置需要脱敏的列，变更仍会记录，但前后的值替换为 Redacted
+func (a *Auditor) Redact(columns ...string) *Auditor {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, column := range columns {
+		a.redact[column] = true
+	}
+	return a
+}
+
+// Ignore 设置不计入差异的列
+func (a *Auditor) Ignore(columns ...string) *Auditor {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, column := range columns {
+		a.ignore[column] = true
+	}
+	return a
+}
+
+// SetActorResolver 设置操作者解析函数
+func (a *Auditor) SetActorResolver(resolver ActorResolver) *Auditor {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.resolver = resolver
+	return a
+}
+
+// UseGuard 使用认证守卫的当前用户作为操作者
+func (a *Auditor) UseGuard(guard auth.Guard) *Auditor {
+	return a.SetActorResolver(func() Actor {
+		if id := guard.ID(); id != nil {
+			return Actor{ID: fmt.Sprint(id)}
+		}
+		return Actor{}
+	})
+}
+
+// Observe 监听模型的 created、updated、deleted 事件，dispatcher 为 nil 时使用全局分发器
+func (a *Auditor) Observe(dispatcher event.Dispatcher, models ...interface{}) {
+	listen := event.Listen
+	if dispatcher != nil {
+		listen = dispatcher.Listen
+	}
+
+	listener := event.NewListener("audit.model", a.handleModelEvent)
+	for _, model := range models {
+		listen(database.ModelEventName(model, database.ModelCreated), listener)
+		listen(database.ModelEventName(model, database.ModelUpdated), listener)
+		listen(database.ModelEventName(model, database.ModelDeleted), listener)
+	}
+}
+
+// Log 记录手动审计条目，actor 为空时使用当前操作者
+func (a *Auditor) Log(actor, action, subject string, details map[string]interface{}) error {
+	entry := a.newEntry(action, subject)
+	if actor != "" {
+		entry.Actor = actor
+	}
+	entry.Details = a.redactDetails(details)
+	return a.write(entry)
+}
+
+// LogRequest 记录手动审计条目，操作者、IP 和 User-Agent 取自请求
+//
+// 操作者为认证中间件保存到请求 context 中的用户，没有时使用当前操作者。
+func (a *Auditor) LogRequest(r *http.Request, action, subject string, details map[string]interface{}) error {
+	entry := a.newEntry(action, subject)
+	if user := auth.UserFromContext(r.Context()); user != nil {
+		entry.Actor = fmt.Sprint(user.GetID())
+	}
+	entry.IP = clientIP(r)
+	entry.UserAgent = r.UserAgent()
+	entry.Details = a.redactDetails(details)
+	return a.write(entry)
+}
+
+// handleModelEvent 记录模型事件
+func (a *Auditor) handleModelEvent(ev event.Event) error {
+	model := ev.GetPayload()
+	name := ev.GetName()
+	action := name[strings.LastIndex(name, ".")+1:]
+
+	attributes, err := database.Attributes(model)
+	if err != nil {
+		return err
+	}
+	entry := a.newEntry(action, fmt.Sprint(ev.GetDataByKey("model")))
+	entry.SubjectID = subjectID(model, attributes)
+
+	switch action {
+	case database.ModelCreated:
+		entry.Changes = a.diff(model, nil, attributes)
+	case database.ModelUpdated:
+		original, _ := ev.GetDataByKey("original").(map[string]interface{})
+		entry.Changes = a.diff(model, original, attributes)
+		if len(entry.Changes) == 0 {
+			return nil
+		}
+	case database.ModelDeleted:
+		entry.Changes = a.diff(model, attributes, nil)
+		if softDeleted, _ := ev.GetDataByKey("soft_deleted").(bool); softDeleted {
+			entry.Details = map[string]interface{}{"soft_deleted": true}
+		}
+	}
+	return a.write(entry)
+}
+
+// diff 比较变更前后的列值，before 或 after 为 nil 时记录全部列
+func (a *Auditor) diff(model interface{}, before, after map[string]interface{}) map[string]Change {
+	encrypted := encryptedColumns(model)
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	changes := make(map[string]Change)
+	record := func(column string, oldValue, newValue interface{}) {
+		if a.ignore[column] {
+			return
+		}
+		oldValue, newValue = normalize(oldValue), normalize(newValue)
+		if before != nil && after != nil {
+			// encrypted 列每次保存都会重新加密，无法通过密文判断是否变化
+			if encrypted[column] || fmt.Sprint(oldValue) == fmt.Sprint(newValue) {
+				return
+			}
+		}
+		if a.redact[column] || encrypted[column] {
+			if oldValue != nil {
+				oldValue = Redacted
+			}
+			if newValue != nil {
+				newValue = Redacted
+			}
+		}
+		changes[column] = Change{Old: oldValue, New: newValue}
+	}
+
+	for column, value := range after {
+		record(column, before[column], value)
+	}
+	for column, value := range before {
+		if _, ok := after[column]; !ok && (after == nil || value != nil) {
+			record(column, value, nil)
+		}
+	}
+	return changes
+}
+
+// redactDetails 脱敏手动条目中的敏感字段
+func (a *Auditor) redactDetails(details map[string]interface{}) map[string]interface{} {
+	if len(details) == 0 {
+		return details
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	result := make(map[string]interface{}, len(details))
+	for key, value := range details {
+		if a.redact[key] {
+			value = Redacted
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// newEntry 创建条目并填写当前操作者
+func (a *Auditor) newEntry(action, subject string) *Entry {
+	a.mu.RLock()
+	resolver := a.resolver
+	a.mu.RUnlock()
+
+	entry := &Entry{
+		ID:        uuid.New().String(),
+		Action:    action,
+		Subject:   subject,
+		CreatedAt: time.Now(),
+	}
+	if resolver != nil {
+		actor := resolver()
+		entry.Actor = actor.ID
+		entry.IP = actor.IP
+		entry.UserAgent = actor.UserAgent
+	}
+	return entry
+}
+
+// write 写入条目
+func (a *Auditor) write(entry *Entry) error {
+	if a.sink == nil {
+		return ErrNoSink
+	}
+	return a.sink.Write(entry)
+}
+
+// encryptedColumns 获取使用 encrypted 类型转换的列
+func encryptedColumns(model interface{}) map[string]bool {
+	columns := make(map[string]bool)
+	if c, ok := model.(database.Casts); ok {
+		for column, castType := range c.Casts() {
+			if strings.EqualFold(castType, "encrypted") {
+				columns[column] = true
+			}
+		}
+	}
+	return columns
+}
+
+// subjectID 获取模型主键的值
+func subjectID(model interface{}, attributes map[string]interface{}) string {
+	pk := "id"
+	if p, ok := model.(interface{ PrimaryKey() string }); ok {
+		pk = p.PrimaryKey()
+	}
+	if id, ok := attributes[pk]; ok && id != nil {
+		return fmt.Sprint(id)
+	}
+	return ""
+}
+
+// normalize 统一数据库读取的值和模型的值，便于比较和序列化
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	case bool:
+		if v {
+			return int64(1)
+		}
+		return int64(0)
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
+
+// clientIP 获取请求的客户端 IP
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+var (
+	defaultAuditor *Auditor
+	defaultMutex   sync.Mutex
+)
+
+// SetDefault 设置全局审计记录器
+func SetDefault(auditor *Auditor) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultAuditor = auditor
+}
+
+// Default 获取全局审计记录器，未设置时返回没有输出的记录器
+func Default() *Auditor {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	if defaultAuditor == nil {
+		defaultAuditor = NewAuditor(nil)
+	}
+	return defaultAuditor
+}
+
+// Log 使用全局审计记录器记录手动审计条目
+func Log(actor, action, subject string, details map[string]interface{}) error {
+	return Default().Log(actor, action, subject, details)
+}
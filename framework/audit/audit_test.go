@@ -0,0 +1,193 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"laravel-go/framework/auth"
+	"laravel-go/framework/database"
+	"laravel-go/framework/event"
+)
+
+type Invoice struct {
+	database.Model
+	Number   string  `db:"number"`
+	Amount   float64 `db:"amount"`
+	ApiToken string  `db:"api_token"`
+	Note     string  `db:"note"`
+}
+
+func (i *Invoice) TableName() string {
+	return "invoices"
+}
+
+func (i *Invoice) Casts() map[string]string {
+	return map[string]string{"note": "encrypted"}
+}
+
+func newTestSink(t *testing.T) (database.Connection, *DatabaseSink) {
+	conn, err := database.NewConnection(&database.ConnectionConfig{
+		Driver:   database.SQLite,
+		Database: filepath.Join(t.TempDir(), "audit.db"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	sink := NewDatabaseSink(conn, "")
+	if err := sink.CreateTable(); err != nil {
+		t.Fatalf("Failed to create audit table: %v", err)
+	}
+	return conn, sink
+}
+
+func TestModelUpdateRecordsDiff(t *testing.T) {
+	t.Setenv("APP_KEY", "base64:"+base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+
+	conn, sink := newTestSink(t)
+	if _, err := conn.Exec(`CREATE TABLE invoices (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		number TEXT,
+		amount REAL,
+		api_token TEXT,
+		note TEXT,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	dispatcher := event.NewEventDispatcher(event.NewMemoryEventQueue())
+	defer dispatcher.Close()
+	database.SetEventDispatcher(dispatcher)
+	defer database.SetEventDispatcher(nil)
+
+	provider := auth.NewMemoryUserProvider()
+	alice := &auth.BaseUser{ID: 7, Email: "alice@example.com", Password: "secret"}
+	provider.AddUser(alice)
+
+	auditor := NewAuditor(sink).Redact("api_token")
+	guard := auditor.WatchGuard(auth.NewSessionGuard(provider, auth.NewMemorySessionStore()))
+	auditor.Observe(dispatcher, &Invoice{})
+
+	model := &database.Model{}
+	invoice := &Invoice{Number: "INV-1", Amount: 100, ApiToken: "tok-1", Note: "first"}
+	if err := model.Save(conn, invoice); err != nil {
+		t.Fatalf("Failed to create invoice: %v", err)
+	}
+
+	if err := guard.Login(alice); err != nil {
+		t.Fatalf("Failed to login: %v", err)
+	}
+	invoice.Amount = 250
+	invoice.ApiToken = "tok-2"
+	invoice.Note = "second"
+	if err := model.Save(conn, invoice); err != nil {
+		t.Fatalf("Failed to update invoice: %v", err)
+	}
+	// 没有变化的保存不产生条目
+	if err := model.Save(conn, invoice); err != nil {
+		t.Fatalf("Failed to save invoice: %v", err)
+	}
+
+	entries, err := sink.Entries("invoice", "1")
+	if err != nil {
+		t.Fatalf("Failed to read entries: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected created and updated entries, got %+v", entries)
+	}
+
+	created := entries[0]
+	if created.Action != ActionCreated || created.Actor != "" || created.Changes["number"].New != "INV-1" {
+		t.Errorf("Unexpected created entry: %+v", created)
+	}
+
+	updated := entries[1]
+	if updated.Action != ActionUpdated || updated.Actor != "7" {
+		t.Errorf("Expected update attributed to user 7, got %+v", updated)
+	}
+	if change := updated.Changes["amount"]; change.Old != float64(100) || change.New != float64(250) {
+		t.Errorf("Unexpected amount change: %+v", change)
+	}
+	if change := updated.Changes["api_token"]; change.Old != Redacted || change.New != Redacted {
+		t.Errorf("Expected api_token to be redacted, got %+v", change)
+	}
+	for _, column := range []string{"number", "note", "updated_at"} {
+		if _, ok := updated.Changes[column]; ok {
+			t.Errorf("Expected %s not to be in the diff, got %+v", column, updated.Changes)
+		}
+	}
+
+	if err := model.Delete(conn, invoice); err != nil {
+		t.Fatalf("Failed to delete invoice: %v", err)
+	}
+	entries, _ = sink.Entries("invoice", "1")
+	if deleted := entries[len(entries)-1]; deleted.Action != ActionDeleted || deleted.Actor != "7" || deleted.Details["soft_deleted"] != true {
+		t.Errorf("Unexpected deleted entry: %+v", deleted)
+	}
+
+	if err := guard.Logout(); err != nil {
+		t.Fatalf("Failed to logout: %v", err)
+	}
+	if _, err := guard.Authenticate(map[string]interface{}{"email": "alice@example.com", "password": "wrong"}); err == nil {
+		t.Fatal("Expected authentication to fail")
+	}
+	entries, _ = sink.Entries("auth", "")
+	if len(entries) != 3 || entries[0].Action != ActionLogin || entries[1].Action != ActionLogout || entries[2].Action != ActionFailed {
+		t.Fatalf("Expected login, logout and failed entries, got %+v", entries)
+	}
+	if entries[2].Details["password"] != Redacted || entries[2].Details["email"] != "alice@example.com" {
+		t.Errorf("Expected password to be redacted, got %+v", entries[2].Details)
+	}
+}
+
+func TestManualLogToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	SetDefault(NewAuditor(NewFileSink(path)))
+	defer SetDefault(nil)
+
+	if err := Log("admin", "export", "report", map[string]interface{}{"format": "csv", "password": "x"}); err != nil {
+		t.Fatalf("Failed to log: %v", err)
+	}
+
+	request := httptest.NewRequest("POST", "/reports/export", nil)
+	request.RemoteAddr = "203.0.113.9:51234"
+	request.Header.Set("User-Agent", "curl/8.0")
+	request = request.WithContext(auth.WithUser(request.Context(), "web", &auth.BaseUser{ID: 3}))
+	if err := Default().LogRequest(request, "export", "report", nil); err != nil {
+		t.Fatalf("Failed to log request: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to decode entry: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Actor != "admin" || entries[0].Details["format"] != "csv" || entries[0].Details["password"] != Redacted {
+		t.Errorf("Unexpected manual entry: %+v", entries[0])
+	}
+	if entries[1].Actor != "3" || entries[1].IP != "203.0.113.9" || entries[1].UserAgent != "curl/8.0" {
+		t.Errorf("Unexpected request entry: %+v", entries[1])
+	}
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"fmt"
+
+	"laravel-go/framework/auth"
+)
+
+// auditedGuard 记录登录、登出和认证失败的认证守卫
+type auditedGuard struct {
+	auth.Guard
+	auditor *Auditor
+}
+
+// WatchGuard 包装认证守卫，记录登录、登出和认证失败，并使用守卫的当前用户作为操作者
+func (a *Auditor) WatchGuard(guard auth.Guard) auth.Guard {
+	a.UseGuard(guard)
+	return &auditedGuard{Guard: guard, auditor: a}
+}
+
+// Authenticate 认证用户，失败时记录 auth.failed，凭据中的敏感字段会被脱敏
+func (g *auditedGuard) Authenticate(credentials map[string]interface{}) (auth.User, error) {
+	user, err := g.Guard.Authenticate(credentials)
+	if err != nil {
+		details := make(map[string]interface{}, len(credentials)+1)
+		for key, value := range credentials {
+			details[key] = value
+		}
+		details["error"] = err.Error()
+		g.auditor.Log("", ActionFailed, "auth", details)
+		return nil, err
+	}
+	return user, nil
+}
+
+// Login 登录用户并记录 auth.login
+func (g *auditedGuard) Login(user auth.User, remember ...bool) error {
+	if err := g.Guard.Login(user, remember...); err != nil {
+		return err
+	}
+	return g.auditor.Log(fmt.Sprint(user.GetID()), ActionLogin, "auth", nil)
+}
+
+// LoginWithRemember 登录并记住用户
+func (g *auditedGuard) LoginWithRemember(user auth.User) error {
+	return g.Login(user, true)
+}
+
+// Logout 登出用户并记录 auth.logout
+func (g *auditedGuard) Logout() error {
+	actor := ""
+	if id := g.Guard.ID(); id != nil {
+		actor = fmt.Sprint(id)
+	}
+	if err := g.Guard.Logout(); err != nil {
+		return err
+	}
+	if actor == "" {
+		return nil
+	}
+	return g.auditor.Log(actor, ActionLogout, "auth", nil)
+}
@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"laravel-go/framework/database"
+)
+
+// DatabaseSink 将审计日志写入数据库表
+type DatabaseSink struct {
+	connection database.Connection
+	table      string
+}
+
+// NewDatabaseSink 创建数据库输出，table 为空时使用 audit_logs 表
+func NewDatabaseSink(connection database.Connection, table string) *DatabaseSink {
+	if table == "" {
+		table = "audit_logs"
+	}
+	return &DatabaseSink{
+		connection: connection,
+		table:      table,
+	}
+}
+
+// CreateTable 创建审计日志表
+func (s *DatabaseSink) CreateTable() error {
+	_, err := s.connection.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id VARCHAR(36) NOT NULL PRIMARY KEY,
+		actor VARCHAR(191) NOT NULL,
+		action VARCHAR(191) NOT NULL,
+		subject VARCHAR(191) NOT NULL,
+		subject_id VARCHAR(191) NOT NULL,
+		changes TEXT,
+		details TEXT,
+		ip VARCHAR(45),
+		user_agent TEXT,
+		created_at TIMESTAMP NOT NULL
+	)`, s.table))
+	return err
+}
+
+// Write 写入审计日志
+func (s *DatabaseSink) Write(entry *Entry) error {
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.NewQueryBuilder(s.connection).Statement(
+		fmt.Sprintf("INSERT INTO %s (id, actor, action, subject, subject_id, changes, details, ip, user_agent, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", s.table),
+		entry.ID, entry.Actor, entry.Action, entry.Subject, entry.SubjectID,
+		string(changes), string(details), entry.IP, entry.UserAgent, entry.CreatedAt,
+	)
+	return err
+}
+
+// Entries 按时间顺序获取主体的审计日志，subjectID 为空时返回该类主体的全部日志
+func (s *DatabaseSink) Entries(subject, subjectID string) ([]*Entry, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE subject = ?", s.table)
+	args := []interface{}{subject}
+	if subjectID != "" {
+		query += " AND subject_id = ?"
+		args = append(args, subjectID)
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := database.NewQueryBuilder(s.connection).Raw(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(rows))
+	for _, row := range rows {
+		entry := &Entry{
+			ID:        text(row["id"]),
+			Actor:     text(row["actor"]),
+			Action:    text(row["action"]),
+			Subject:   text(row["subject"]),
+			SubjectID: text(row["subject_id"]),
+			IP:        text(row["ip"]),
+			UserAgent: text(row["user_agent"]),
+		}
+		if changes := text(row["changes"]); changes != "" {
+			if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+				return nil, err
+			}
+		}
+		if details := text(row["details"]); details != "" {
+			if err := json.Unmarshal([]byte(details), &entry.Details); err != nil {
+				return nil, err
+			}
+		}
+		if createdAt, ok := row["created_at"].(time.Time); ok {
+			entry.CreatedAt = createdAt
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// text 将数据库值转换为字符串，NULL 为空字符串
+func text(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// FileSink 将审计日志以 JSON Lines 格式追加到文件
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSink 创建文件输出
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Write 追加一行 JSON
+func (s *FileSink) Write(entry *Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
@@ -1,7 +1,11 @@
 package auth
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"sync"
 	"time"
 )
 
@@ -27,8 +31,8 @@ type Guard interface {
 	User() User
 	// 获取用户ID
 	ID() interface{}
-	// 登录用户
-	Login(user User) error
+	// 登录用户，remember 为 true 时记住用户
+	Login(user User, remember ...bool) error
 	// 登录用户并记住
 	LoginWithRemember(user User) error
 	// 登出用户
@@ -60,6 +64,7 @@ type AuthManager struct {
 	guards    map[string]Guard
 	providers map[string]UserProvider
 	defaultGuard string
+	mu        sync.RWMutex
 }
 
 // NewAuthManager 创建认证管理器
@@ -73,37 +78,154 @@ func NewAuthManager() *AuthManager {
 
 // Guard 获取指定的守卫
 func (am *AuthManager) Guard(name string) Guard {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
 	if guard, exists := am.guards[name]; exists {
 		return guard
 	}
 	return am.guards[am.defaultGuard]
 }
 
+// HasGuard 检查守卫是否已注册
+func (am *AuthManager) HasGuard(name string) bool {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+
+	_, exists := am.guards[name]
+	return exists
+}
+
 // DefaultGuard 获取默认守卫
 func (am *AuthManager) DefaultGuard() Guard {
-	return am.Guard(am.defaultGuard)
+	return am.Guard(am.DefaultGuardName())
+}
+
+// DefaultGuardName 获取默认守卫名称
+func (am *AuthManager) DefaultGuardName() string {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
+	return am.defaultGuard
 }
 
 // SetDefaultGuard 设置默认守卫
 func (am *AuthManager) SetDefaultGuard(name string) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
 	am.defaultGuard = name
 }
 
 // ExtendGuard 扩展守卫
 func (am *AuthManager) ExtendGuard(name string, guard Guard) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
 	am.guards[name] = guard
 }
 
 // ExtendProvider 扩展用户提供者
 func (am *AuthManager) ExtendProvider(name string, provider UserProvider) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
 	am.providers[name] = provider
 }
 
 // GetProvider 获取用户提供者
 func (am *AuthManager) GetProvider(name string) UserProvider {
+	am.mu.RLock()
+	defer am.mu.RUnlock()
 	return am.providers[name]
 }
 
+// ShouldUse 指定请求使用的守卫，之后 User(r) 和 ActiveGuard(r) 都基于该守卫
+func (am *AuthManager) ShouldUse(r *http.Request, name string) *http.Request {
+	ctx := context.WithValue(r.Context(), guardNameContextKey, name)
+	return r.WithContext(ctx)
+}
+
+// ActiveGuard 获取请求当前使用的守卫，未指定时使用默认守卫
+func (am *AuthManager) ActiveGuard(r *http.Request) Guard {
+	if name := GuardNameFromContext(r.Context()); name != "" {
+		return am.Guard(name)
+	}
+	return am.DefaultGuard()
+}
+
+// User 获取请求当前守卫认证的用户
+func (am *AuthManager) User(r *http.Request) User {
+	if user := UserFromContext(r.Context()); user != nil {
+		return user
+	}
+	if guard := am.ActiveGuard(r); guard != nil && guard.Check() {
+		return guard.User()
+	}
+	return nil
+}
+
+// AuthenticateRequest 依次尝试指定的守卫认证请求，类似 Laravel 的 auth:api,web
+//
+// 未指定守卫时使用默认守卫。第一个认证成功的守卫及其用户会保存到返回请求的
+// context 中，后续通过 User(r) 或 UserFromContext 获取。
+func (am *AuthManager) AuthenticateRequest(r *http.Request, names ...string) (*http.Request, error) {
+	if len(names) == 0 {
+		names = []string{am.DefaultGuardName()}
+	}
+
+	for _, name := range names {
+		am.mu.RLock()
+		guard, exists := am.guards[name]
+		am.mu.RUnlock()
+		if !exists {
+			return r, fmt.Errorf("%w: %s", ErrGuardNotFound, name)
+		}
+
+		user, err := authenticateWithGuard(guard, r)
+		if err != nil {
+			continue
+		}
+
+		return r.WithContext(WithUser(r.Context(), name, user)), nil
+	}
+
+	return r, ErrUserNotAuthenticated
+}
+
+// authenticateWithGuard 使用守卫认证请求
+func authenticateWithGuard(guard Guard, r *http.Request) (User, error) {
+	if authenticator, ok := guard.(RequestAuthenticator); ok {
+		return authenticator.AuthenticateRequest(r)
+	}
+	if guard.Check() {
+		return guard.User(), nil
+	}
+	return nil, ErrUserNotAuthenticated
+}
+
+// contextKey 请求 context 的键类型
+type contextKey int
+
+const (
+	guardNameContextKey contextKey = iota
+	userContextKey
+)
+
+// WithUser 将认证的守卫名称和用户保存到 context
+func WithUser(ctx context.Context, guardName string, user User) context.Context {
+	ctx = context.WithValue(ctx, guardNameContextKey, guardName)
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// UserFromContext 获取 context 中已认证的用户
+func UserFromContext(ctx context.Context) User {
+	user, _ := ctx.Value(userContextKey).(User)
+	return user
+}
+
+// GuardNameFromContext 获取 context 中认证请求的守卫名称
+func GuardNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(guardNameContextKey).(string)
+	return name
+}
+
 // 认证相关错误
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
@@ -111,6 +233,7 @@ var (
 	ErrUserNotAuthenticated = errors.New("user not authenticated")
 	ErrInvalidToken       = errors.New("invalid token")
 	ErrTokenExpired       = errors.New("token expired")
+	ErrGuardNotFound      = errors.New("guard not found")
 )
 
 // SessionGuard Session认证守卫
@@ -118,6 +241,13 @@ type SessionGuard struct {
 	provider UserProvider
 	user     User
 	session  SessionStore
+	// 记住我
+	recaller       *Recaller
+	recallerCookie string
+	// 本次请求签发或轮换得到的记住我令牌，登出时一并删除
+	issuedCookie string
+	queuedCookie *http.Cookie
+	viaRemember    bool
 }
 
 // NewSessionGuard 创建Session认证守卫
@@ -149,21 +279,72 @@ func (sg *SessionGuard) Check() bool {
 		return true
 	}
 
-	// 从session中获取用户ID
+	// 从session中获取用户ID，Session 中没有时尝试记住我Cookie
 	userID := sg.session.Get("auth_user_id")
 	if userID == nil {
+		return sg.userFromRecaller()
+	}
+
+	user, err := sg.provider.RetrieveById(userID)
+	if err != nil {
+		return false
+	}
+
+	sg.user = user
+	return true
+}
+
+// userFromRecaller 通过记住我Cookie登录，令牌使用后轮换
+func (sg *SessionGuard) userFromRecaller() bool {
+	if sg.recaller == nil || sg.recallerCookie == "" {
+		return false
+	}
+
+	value := sg.recallerCookie
+	sg.recallerCookie = ""
+
+	userID, rotated, err := sg.recaller.Recall(value)
+	if err != nil {
+		sg.queuedCookie = sg.recaller.ExpiredCookie()
 		return false
 	}
 
 	user, err := sg.provider.RetrieveById(userID)
 	if err != nil {
+		sg.queuedCookie = sg.recaller.ExpiredCookie()
 		return false
 	}
 
 	sg.user = user
+	sg.viaRemember = true
+	sg.session.Put("auth_user_id", user.GetID())
+	sg.issuedCookie = rotated
+	sg.queuedCookie = sg.recaller.Cookie(rotated)
 	return true
 }
 
+// SetRecaller 设置记住我令牌签发器
+func (sg *SessionGuard) SetRecaller(recaller *Recaller) {
+	sg.recaller = recaller
+}
+
+// SetRecallerCookie 设置请求中的记住我Cookie值
+func (sg *SessionGuard) SetRecallerCookie(value string) {
+	sg.recallerCookie = value
+}
+
+// QueuedCookie 获取需要写入响应的记住我Cookie，获取后清空
+func (sg *SessionGuard) QueuedCookie() *http.Cookie {
+	cookie := sg.queuedCookie
+	sg.queuedCookie = nil
+	return cookie
+}
+
+// ViaRemember 检查当前用户是否通过记住我Cookie登录
+func (sg *SessionGuard) ViaRemember() bool {
+	return sg.viaRemember
+}
+
 // User 获取当前用户
 func (sg *SessionGuard) User() User {
 	if !sg.Check() {
@@ -180,31 +361,66 @@ func (sg *SessionGuard) ID() interface{} {
 	return nil
 }
 
-// Login 登录用户
-func (sg *SessionGuard) Login(user User) error {
+// Login 登录用户，remember 为 true 时签发记住我令牌
+func (sg *SessionGuard) Login(user User, remember ...bool) error {
+	// 登录时重新生成Session ID，防止Session固定攻击
+	if regenerator, ok := sg.session.(SessionRegenerator); ok {
+		if err := regenerator.Regenerate(); err != nil {
+			return err
+		}
+	}
+
 	sg.user = user
 	sg.session.Put("auth_user_id", user.GetID())
+
+	if len(remember) > 0 && remember[0] {
+		return sg.remember(user)
+	}
 	return nil
 }
 
-// LoginWithRemember 登录并记住用户
-func (sg *SessionGuard) LoginWithRemember(user User) error {
-	// 生成记住令牌
-	token := generateRememberToken()
-	user.SetRememberToken(token)
-	
-	// 更新用户的记住令牌
-	if err := sg.provider.UpdateRememberToken(user, token); err != nil {
+// remember 记住用户，未设置 Recaller 时使用用户提供者的记住令牌
+func (sg *SessionGuard) remember(user User) error {
+	if sg.recaller == nil {
+		// 生成记住令牌
+		token := generateRememberToken()
+		user.SetRememberToken(token)
+
+		// 更新用户的记住令牌
+		return sg.provider.UpdateRememberToken(user, token)
+	}
+
+	value, err := sg.recaller.Issue(user.GetID())
+	if err != nil {
 		return err
 	}
+	sg.issuedCookie = value
+	sg.queuedCookie = sg.recaller.Cookie(value)
+	return nil
+}
 
-	return sg.Login(user)
+// LoginWithRemember 登录并记住用户
+func (sg *SessionGuard) LoginWithRemember(user User) error {
+	return sg.Login(user, true)
 }
 
-// Logout 登出用户
+// Logout 登出用户，同时删除记住我令牌
 func (sg *SessionGuard) Logout() error {
 	sg.user = nil
+	sg.viaRemember = false
 	sg.session.Forget("auth_user_id")
+
+	if sg.recaller != nil {
+		// 请求中的令牌可能已在本次请求中轮换，两个令牌都需要删除
+		for _, value := range []string{sg.recallerCookie, sg.issuedCookie} {
+			if value != "" {
+				sg.recaller.Forget(value)
+			}
+		}
+		sg.recallerCookie = ""
+		sg.issuedCookie = ""
+		sg.queuedCookie = sg.recaller.ExpiredCookie()
+	}
 	return nil
 }
 
@@ -235,6 +451,11 @@ type SessionStore interface {
 	Has(key string) bool
 }
 
+// SessionRegenerator 支持重新生成Session ID的Session存储
+type SessionRegenerator interface {
+	Regenerate() error
+}
+
 // 生成记住令牌
 func generateRememberToken() string {
 	// 这里应该使用更安全的随机字符串生成
@@ -413,4 +413,65 @@ func TestBaseUser(t *testing.T) {
 	if user.GetAuthPassword() != "password" {
 		t.Errorf("Expected auth password 'password', got: %s", user.GetAuthPassword())
 	}
-} 
\ No newline at end of file
+} 
+func TestAuthManagerMultipleGuards(t *testing.T) {
+	provider := NewMemoryUserProvider()
+	webUser := &BaseUser{ID: "web-1", Email: "web@example.com", Password: "password"}
+	apiUser := &BaseUser{ID: "api-2", Email: "api@example.com", Password: "password"}
+	provider.AddUser(webUser)
+	provider.AddUser(apiUser)
+
+	webGuard := NewSessionGuard(provider, NewMemorySessionStore())
+	webGuard.Login(webUser)
+	apiGuard := NewJWTGuard(provider, "test-secret", time.Hour)
+	token, err := apiGuard.GenerateToken(apiUser)
+	if err != nil {
+		t.Fatalf("Expected no error generating token, got: %v", err)
+	}
+
+	manager := NewAuthManager()
+	manager.ExtendGuard("web", webGuard)
+	manager.ExtendGuard("api", apiGuard)
+
+	handler := func(guards ...string) http.HandlerFunc {
+		return NewGuardMiddleware(manager, guards...).Handle(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s:%v", GuardNameFromContext(r.Context()), manager.User(r).GetID())
+		})
+	}
+
+	tests := []struct {
+		name   string
+		guards []string
+		bearer string
+		status int
+		body   string
+	}{
+		{"api guard with token", []string{"api"}, token, http.StatusOK, "api:api-2"},
+		{"api guard ignores session", []string{"api"}, "", http.StatusUnauthorized, ""},
+		{"default web guard", nil, "", http.StatusOK, "web:web-1"},
+		{"fallback to web guard", []string{"api", "web"}, "", http.StatusOK, "web:web-1"},
+		{"unknown guard", []string{"admin"}, "", http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.bearer != "" {
+			request.Header.Set("Authorization", "Bearer "+tt.bearer)
+		}
+		recorder := httptest.NewRecorder()
+		handler(tt.guards...)(recorder, request)
+
+		if recorder.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, recorder.Code)
+		}
+		if tt.body != "" && recorder.Body.String() != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.name, tt.body, recorder.Body.String())
+		}
+	}
+
+	// 未经过中间件时，User 使用 ShouldUse 指定的守卫
+	request := manager.ShouldUse(httptest.NewRequest(http.MethodGet, "/", nil), "web")
+	if user := manager.User(request); user == nil || user.GetID() != "web-1" {
+		t.Errorf("Expected web user, got %v", user)
+	}
+}
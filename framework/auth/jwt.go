@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -69,7 +71,7 @@ func (jg *JWTGuard) ID() interface{} {
 }
 
 // Login 登录用户
-func (jg *JWTGuard) Login(user User) error {
+func (jg *JWTGuard) Login(user User, remember ...bool) error {
 	jg.user = user
 	return nil
 }
@@ -104,6 +106,24 @@ func (jg *JWTGuard) GetProvider() UserProvider {
 	return jg.provider
 }
 
+// AuthenticateRequest 从请求的 Authorization 头认证用户
+func (jg *JWTGuard) AuthenticateRequest(r *http.Request) (User, error) {
+	jg.user = nil
+
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, ErrMissingBearerToken
+	}
+
+	user, err := jg.GetUserFromToken(strings.TrimPrefix(authHeader, "Bearer "))
+	if err != nil {
+		return nil, err
+	}
+
+	jg.user = user
+	return user, nil
+}
+
 // GenerateToken 生成JWT令牌
 func (jg *JWTGuard) GenerateToken(user User) (string, error) {
 	claims := JWTClaims{
@@ -1,10 +1,16 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 )
 
+// RequestAuthenticator 从请求中认证用户的守卫，例如 OIDCGuard
+type RequestAuthenticator interface {
+	AuthenticateRequest(r *http.Request) (User, error)
+}
+
 // AuthMiddleware 认证中间件
 type AuthMiddleware struct {
 	guard Guard
@@ -20,6 +26,14 @@ func NewAuthMiddleware(guard Guard) *AuthMiddleware {
 // Handle 处理HTTP请求
 func (am *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// 基于请求的守卫先从请求中认证用户
+		if authenticator, ok := am.guard.(RequestAuthenticator); ok {
+			if _, err := authenticator.AuthenticateRequest(r); err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
 		// 检查用户是否已认证
 		if !am.guard.Check() {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
@@ -30,6 +44,40 @@ func (am *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// GuardMiddleware 指定守卫的认证中间件
+//
+// 依次尝试指定的守卫，第一个认证成功的守卫和用户保存在请求 context 中，
+// 例如 API 路由使用 "api" 守卫，Web 路由使用 "web" 守卫。
+type GuardMiddleware struct {
+	manager *AuthManager
+	guards  []string
+}
+
+// NewGuardMiddleware 创建指定守卫的认证中间件，未指定守卫时使用默认守卫
+func NewGuardMiddleware(manager *AuthManager, guards ...string) *GuardMiddleware {
+	return &GuardMiddleware{
+		manager: manager,
+		guards:  guards,
+	}
+}
+
+// Handle 处理HTTP请求
+func (gm *GuardMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authenticated, err := gm.manager.AuthenticateRequest(r, gm.guards...)
+		if errors.Is(err, ErrGuardNotFound) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		next(w, authenticated)
+	}
+}
+
 // JWTMiddleware JWT认证中间件
 type JWTMiddleware struct {
 	guard Guard
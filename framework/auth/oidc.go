@@ -0,0 +1,428 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// OIDC 相关错误
+var (
+	ErrMissingBearerToken = errors.New("missing bearer token")
+	ErrUnknownSigningKey  = errors.New("unknown signing key")
+)
+
+// OIDCUser OIDC令牌中的用户
+type OIDCUser struct {
+	Subject string
+	Email   string
+	Name    string
+	Claims  jwt.MapClaims
+}
+
+// GetID 获取用户ID
+func (u *OIDCUser) GetID() interface{} {
+	return u.Subject
+}
+
+// GetEmail 获取用户邮箱
+func (u *OIDCUser) GetEmail() string {
+	return u.Email
+}
+
+// GetPassword 获取用户密码，OIDC用户没有本地密码
+func (u *OIDCUser) GetPassword() string {
+	return ""
+}
+
+// GetRememberToken 获取记住令牌
+func (u *OIDCUser) GetRememberToken() string {
+	return ""
+}
+
+// SetRememberToken 设置记住令牌，OIDC用户不支持
+func (u *OIDCUser) SetRememberToken(token string) {}
+
+// GetAuthIdentifierName 获取认证标识符名称
+func (u *OIDCUser) GetAuthIdentifierName() string {
+	return "sub"
+}
+
+// GetAuthIdentifier 获取认证标识符
+func (u *OIDCUser) GetAuthIdentifier() interface{} {
+	return u.Subject
+}
+
+// GetAuthPassword 获取认证密码
+func (u *OIDCUser) GetAuthPassword() string {
+	return ""
+}
+
+// UserResolver 将令牌声明映射为用户
+type UserResolver func(claims jwt.MapClaims) (User, error)
+
+// OIDCGuard OIDC认证守卫
+//
+// 通过 issuer 的 /.well-known/openid-configuration 获取 JWKS 地址并缓存公钥，
+// 校验 Bearer 令牌的签名、issuer、audience 和有效期。令牌使用未知的 kid 时
+// 重新获取 JWKS，以支持身份提供者轮换密钥。
+type OIDCGuard struct {
+	issuer   string
+	audience string
+	jwksURL  string
+	client   *http.Client
+	leeway   time.Duration
+	resolver UserResolver
+	user     User
+
+	keys            map[string]interface{}
+	fetchedAt       time.Time
+	cacheTTL        time.Duration
+	refreshInterval time.Duration
+	mu              sync.RWMutex
+}
+
+// NewOIDCGuard 创建OIDC认证守卫
+func NewOIDCGuard(issuerURL, audience string) *OIDCGuard {
+	return &OIDCGuard{
+		issuer:          strings.TrimSuffix(issuerURL, "/"),
+		audience:        audience,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		leeway:          time.Minute,
+		resolver:        defaultOIDCUser,
+		keys:            make(map[string]interface{}),
+		cacheTTL:        time.Hour,
+		refreshInterval: 10 * time.Second,
+	}
+}
+
+// SetJWKSURL 设置 JWKS 地址，跳过 OIDC 发现
+func (og *OIDCGuard) SetJWKSURL(url string) {
+	og.mu.Lock()
+	defer og.mu.Unlock()
+	og.jwksURL = url
+}
+
+// SetHTTPClient 设置获取 JWKS 使用的 HTTP 客户端
+func (og *OIDCGuard) SetHTTPClient(client *http.Client) {
+	og.client = client
+}
+
+// SetClockSkew 设置校验时间类声明时允许的时钟偏差，默认 1 分钟
+func (og *OIDCGuard) SetClockSkew(leeway time.Duration) {
+	og.leeway = leeway
+}
+
+// SetRefreshInterval 设置遇到未知 kid 时重新获取 JWKS 的最小间隔，默认 10 秒
+func (og *OIDCGuard) SetRefreshInterval(interval time.Duration) {
+	og.refreshInterval = interval
+}
+
+// SetUserResolver 设置声明到用户的映射，例如按邮箱查找本地用户
+func (og *OIDCGuard) SetUserResolver(resolver UserResolver) {
+	og.resolver = resolver
+}
+
+// ValidateToken 校验令牌并返回声明
+func (og *OIDCGuard) ValidateToken(tokenString string) (jwt.MapClaims, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(og.issuer),
+		jwt.WithAudience(og.audience),
+		jwt.WithLeeway(og.leeway),
+		jwt.WithExpirationRequired(),
+	)
+
+	claims := jwt.MapClaims{}
+	token, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return og.key(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// GetUserFromToken 从令牌获取用户
+func (og *OIDCGuard) GetUserFromToken(tokenString string) (User, error) {
+	claims, err := og.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return og.resolver(claims)
+}
+
+// AuthenticateRequest 从请求的 Authorization 头认证用户
+func (og *OIDCGuard) AuthenticateRequest(r *http.Request) (User, error) {
+	og.user = nil
+
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, ErrMissingBearerToken
+	}
+
+	user, err := og.GetUserFromToken(strings.TrimPrefix(authHeader, "Bearer "))
+	if err != nil {
+		return nil, err
+	}
+
+	og.user = user
+	return user, nil
+}
+
+// Authenticate 使用 credentials["token"] 认证用户
+func (og *OIDCGuard) Authenticate(credentials map[string]interface{}) (User, error) {
+	token, _ := credentials["token"].(string)
+	if token == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	user, err := og.GetUserFromToken(token)
+	if err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	og.user = user
+	return user, nil
+}
+
+// Check 检查是否已认证
+func (og *OIDCGuard) Check() bool {
+	return og.user != nil
+}
+
+// User 获取当前用户
+func (og *OIDCGuard) User() User {
+	return og.user
+}
+
+// ID 获取用户ID
+func (og *OIDCGuard) ID() interface{} {
+	if og.user != nil {
+		return og.user.GetID()
+	}
+	return nil
+}
+
+// Login 登录用户，OIDC 令牌由身份提供者签发，remember 不适用
+func (og *OIDCGuard) Login(user User, remember ...bool) error {
+	og.user = user
+	return nil
+}
+
+// LoginWithRemember 登录用户（OIDC中不适用，但保持接口一致）
+func (og *OIDCGuard) LoginWithRemember(user User) error {
+	return og.Login(user)
+}
+
+// Logout 登出用户
+func (og *OIDCGuard) Logout() error {
+	og.user = nil
+	return nil
+}
+
+// Validate 验证凭据
+func (og *OIDCGuard) Validate(credentials map[string]interface{}) bool {
+	token, _ := credentials["token"].(string)
+	_, err := og.ValidateToken(token)
+	return err == nil
+}
+
+// SetUser 设置用户
+func (og *OIDCGuard) SetUser(user User) {
+	og.user = user
+}
+
+// GetProvider 获取用户提供者，OIDC 守卫不使用本地用户提供者
+func (og *OIDCGuard) GetProvider() UserProvider {
+	return nil
+}
+
+// key 获取 kid 对应的公钥，缓存过期或 kid 未知时重新获取 JWKS
+func (og *OIDCGuard) key(kid string) (interface{}, error) {
+	og.mu.RLock()
+	key, exists := og.keys[kid]
+	fetchedAt := og.fetchedAt
+	og.mu.RUnlock()
+
+	expired := time.Since(fetchedAt) > og.cacheTTL
+	if exists && !expired {
+		return key, nil
+	}
+
+	// 未知 kid 时限制刷新频率，避免伪造的令牌频繁请求身份提供者
+	if !expired && time.Since(fetchedAt) < og.refreshInterval {
+		return nil, ErrUnknownSigningKey
+	}
+
+	if err := og.refreshKeys(); err != nil {
+		return nil, err
+	}
+
+	og.mu.RLock()
+	defer og.mu.RUnlock()
+	if key, exists := og.keys[kid]; exists {
+		return key, nil
+	}
+	return nil, ErrUnknownSigningKey
+}
+
+// refreshKeys 重新获取 JWKS
+func (og *OIDCGuard) refreshKeys() error {
+	jwksURL, err := og.discoverJWKSURL()
+	if err != nil {
+		return err
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := og.getJSON(jwksURL, &jwks); err != nil {
+		return fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	og.mu.Lock()
+	defer og.mu.Unlock()
+	og.keys = keys
+	og.fetchedAt = time.Now()
+	return nil
+}
+
+// discoverJWKSURL 通过 OIDC 发现获取 JWKS 地址
+func (og *OIDCGuard) discoverJWKSURL() (string, error) {
+	og.mu.RLock()
+	jwksURL := og.jwksURL
+	og.mu.RUnlock()
+	if jwksURL != "" {
+		return jwksURL, nil
+	}
+
+	var configuration struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := og.getJSON(og.issuer+"/.well-known/openid-configuration", &configuration); err != nil {
+		return "", fmt.Errorf("failed to discover openid configuration: %w", err)
+	}
+	if configuration.JWKSURI == "" {
+		return "", errors.New("openid configuration has no jwks_uri")
+	}
+
+	og.mu.Lock()
+	og.jwksURL = configuration.JWKSURI
+	og.mu.Unlock()
+	return configuration.JWKSURI, nil
+}
+
+// getJSON 获取并解析 JSON
+func (og *OIDCGuard) getJSON(url string, target interface{}) error {
+	resp, err := og.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// defaultOIDCUser 默认的声明映射
+func defaultOIDCUser(claims jwt.MapClaims) (User, error) {
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, ErrInvalidToken
+	}
+
+	email, _ := claims["email"].(string)
+	name, _ := claims["name"].(string)
+	return &OIDCUser{Subject: subject, Email: email, Name: name, Claims: claims}, nil
+}
+
+// jsonWebKey JWKS 中的公钥
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey 转换为 RSA 或 ECDSA 公钥
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+}
+
+// decodeBigInt 解码 base64url 编码的大整数
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testOIDCProvider 本地身份提供者，提供 OIDC 发现和 JWKS
+type testOIDCProvider struct {
+	server  *httptest.Server
+	keys    map[string]*rsa.PrivateKey
+	fetches int
+	mu      sync.Mutex
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	provider := &testOIDCProvider{keys: make(map[string]*rsa.PrivateKey)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   provider.server.URL,
+			"jwks_uri": provider.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+		provider.fetches++
+
+		keys := []map[string]string{}
+		for kid, key := range provider.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"kid": kid,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+
+	provider.server = httptest.NewServer(mux)
+	t.Cleanup(provider.server.Close)
+	return provider
+}
+
+// rotate 替换身份提供者的签名密钥
+func (p *testOIDCProvider) rotate(t *testing.T, kid string) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = map[string]*rsa.PrivateKey{kid: key}
+	return key
+}
+
+// sign 签发令牌
+func (p *testOIDCProvider) sign(t *testing.T, key *rsa.PrivateKey, kid string, overrides jwt.MapClaims) string {
+	claims := jwt.MapClaims{
+		"iss":   p.server.URL,
+		"aud":   "api",
+		"sub":   "user-1",
+		"email": "user@example.com",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range overrides {
+		claims[name] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+func TestOIDCGuardValidatesToken(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	key := provider.rotate(t, "key-1")
+
+	guard := NewOIDCGuard(provider.server.URL, "api")
+	guard.SetClockSkew(time.Minute)
+
+	user, err := guard.GetUserFromToken(provider.sign(t, key, "key-1", nil))
+	if err != nil {
+		t.Fatalf("Expected valid token, got: %v", err)
+	}
+	if user.GetID() != "user-1" || user.GetEmail() != "user@example.com" {
+		t.Errorf("Unexpected user: %+v", user)
+	}
+
+	// 时钟偏差范围内过期的令牌仍然有效
+	if _, err := guard.ValidateToken(provider.sign(t, key, "key-1", jwt.MapClaims{"exp": time.Now().Add(-30 * time.Second).Unix()})); err != nil {
+		t.Errorf("Expected token within clock skew to be valid, got: %v", err)
+	}
+
+	forged, _ := rsa.GenerateKey(rand.Reader, 2048)
+	invalid := map[string]string{
+		"wrong audience": provider.sign(t, key, "key-1", jwt.MapClaims{"aud": "other"}),
+		"wrong issuer":   provider.sign(t, key, "key-1", jwt.MapClaims{"iss": "https://evil.example.com"}),
+		"expired":        provider.sign(t, key, "key-1", jwt.MapClaims{"exp": time.Now().Add(-2 * time.Minute).Unix()}),
+		"forged":         provider.sign(t, forged, "key-1", nil),
+	}
+	for name, token := range invalid {
+		if _, err := guard.ValidateToken(token); err == nil {
+			t.Errorf("Expected %s token to be rejected", name)
+		}
+	}
+}
+
+func TestOIDCGuardKeyRotation(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	oldKey := provider.rotate(t, "key-1")
+
+	guard := NewOIDCGuard(provider.server.URL, "api")
+	guard.SetRefreshInterval(0)
+
+	if _, err := guard.ValidateToken(provider.sign(t, oldKey, "key-1", nil)); err != nil {
+		t.Fatalf("Expected valid token, got: %v", err)
+	}
+	// 缓存的公钥不会重复获取
+	if _, err := guard.ValidateToken(provider.sign(t, oldKey, "key-1", nil)); err != nil {
+		t.Fatalf("Expected valid token, got: %v", err)
+	}
+	if provider.fetches != 1 {
+		t.Errorf("Expected JWKS to be cached, fetched %d times", provider.fetches)
+	}
+
+	// 身份提供者轮换密钥后，新的 kid 触发重新获取 JWKS
+	newKey := provider.rotate(t, "key-2")
+	if _, err := guard.ValidateToken(provider.sign(t, newKey, "key-2", nil)); err != nil {
+		t.Fatalf("Expected token signed with rotated key to be valid, got: %v", err)
+	}
+	if provider.fetches != 2 {
+		t.Errorf("Expected JWKS to be refreshed, fetched %d times", provider.fetches)
+	}
+}
+
+func TestAuthMiddlewareWithOIDCGuard(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	key := provider.rotate(t, "key-1")
+
+	guard := NewOIDCGuard(provider.server.URL, "api")
+	handler := NewAuthMiddleware(guard).Handle(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(guard.ID().(string)))
+	})
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without token, got %d", recorder.Code)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Authorization", "Bearer "+provider.sign(t, key, "key-1", nil))
+	recorder = httptest.NewRecorder()
+	handler(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "user-1" {
+		t.Errorf("Expected authenticated request, got %d %q", recorder.Code, recorder.Body.String())
+	}
+}
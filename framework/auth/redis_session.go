@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrSessionDecryption Session解密失败
+var ErrSessionDecryption = errors.New("session payload decryption failed")
+
+// RedisSessionClient Redis Session存储使用的客户端接口，*redis.Client 实现了该接口
+type RedisSessionClient interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd
+}
+
+// RedisSessionStore Redis Session存储
+//
+// 每个存储实例对应一个Session，数据使用 gob 序列化后以 prefix+ID 为键写入
+// Redis，并在每次写入时刷新过期时间。保存自定义结构体前需要 gob.Register。
+type RedisSessionStore struct {
+	client RedisSessionClient
+	prefix string
+	ttl    time.Duration
+	id     string
+	data   map[string]interface{}
+	aead   cipher.AEAD
+	err    error
+	mu     sync.RWMutex
+}
+
+// NewRedisSessionStore 创建Redis Session存储，默认有效期为 120 分钟
+func NewRedisSessionStore(client RedisSessionClient, prefix string, ttl time.Duration) *RedisSessionStore {
+	if ttl <= 0 {
+		ttl = 120 * time.Minute
+	}
+
+	return &RedisSessionStore{
+		client: client,
+		prefix: prefix,
+		ttl:    ttl,
+		id:     generateSessionID(),
+		data:   make(map[string]interface{}),
+	}
+}
+
+// SetEncryptionKey 设置Session数据的加密密钥，使用 AES-GCM 加密
+// 密钥长度必须为 16、24 或 32 字节
+func (rss *RedisSessionStore) SetEncryptionKey(key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	rss.mu.Lock()
+	defer rss.mu.Unlock()
+	rss.aead = aead
+	return nil
+}
+
+// ID 获取Session ID
+func (rss *RedisSessionStore) ID() string {
+	rss.mu.RLock()
+	defer rss.mu.RUnlock()
+	return rss.id
+}
+
+// Load 从Redis加载Session
+// Session不存在或已过期时生成新的ID，不会沿用客户端提供的ID
+func (rss *RedisSessionStore) Load(id string) error {
+	ctx := context.Background()
+
+	rss.mu.Lock()
+	defer rss.mu.Unlock()
+
+	payload, err := rss.client.Get(ctx, rss.prefix+id).Bytes()
+	if err == redis.Nil {
+		rss.id = generateSessionID()
+		rss.data = make(map[string]interface{})
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	data, err := rss.decode(payload)
+	if err != nil {
+		return err
+	}
+
+	rss.id = id
+	rss.data = data
+	return rss.client.Expire(ctx, rss.prefix+id, rss.ttl).Err()
+}
+
+// Get 获取Session值
+func (rss *RedisSessionStore) Get(key string) interface{} {
+	rss.mu.RLock()
+	defer rss.mu.RUnlock()
+	return rss.data[key]
+}
+
+// Put 设置Session值并写入Redis
+func (rss *RedisSessionStore) Put(key string, value interface{}) {
+	rss.mu.Lock()
+	defer rss.mu.Unlock()
+	rss.data[key] = value
+	rss.err = rss.save(rss.id)
+}
+
+// Forget 删除Session值并写入Redis
+func (rss *RedisSessionStore) Forget(key string) {
+	rss.mu.Lock()
+	defer rss.mu.Unlock()
+	delete(rss.data, key)
+	rss.err = rss.save(rss.id)
+}
+
+// Has 检查Session值是否存在
+func (rss *RedisSessionStore) Has(key string) bool {
+	rss.mu.RLock()
+	defer rss.mu.RUnlock()
+	_, exists := rss.data[key]
+	return exists
+}
+
+// Err 获取最近一次写入Redis的错误
+func (rss *RedisSessionStore) Err() error {
+	rss.mu.RLock()
+	defer rss.mu.RUnlock()
+	return rss.err
+}
+
+// Regenerate 生成新的Session ID并保留数据，用于登录后防止Session固定攻击
+func (rss *RedisSessionStore) Regenerate() error {
+	rss.mu.Lock()
+	defer rss.mu.Unlock()
+
+	oldID := rss.id
+	newID := generateSessionID()
+	if err := rss.save(newID); err != nil {
+		return err
+	}
+	rss.id = newID
+
+	return rss.client.Del(context.Background(), rss.prefix+oldID).Err()
+}
+
+// Destroy 删除Session数据并生成新的Session ID
+func (rss *RedisSessionStore) Destroy() error {
+	rss.mu.Lock()
+	defer rss.mu.Unlock()
+
+	oldID := rss.id
+	rss.id = generateSessionID()
+	rss.data = make(map[string]interface{})
+
+	return rss.client.Del(context.Background(), rss.prefix+oldID).Err()
+}
+
+// save 将Session数据写入Redis
+func (rss *RedisSessionStore) save(id string) error {
+	payload, err := rss.encode(rss.data)
+	if err != nil {
+		return err
+	}
+
+	if err := rss.client.Set(context.Background(), rss.prefix+id, payload, rss.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// encode 序列化并加密Session数据，密文格式为 nonce+ciphertext
+func (rss *RedisSessionStore) encode(data map[string]interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	if rss.aead == nil {
+		return buffer.Bytes(), nil
+	}
+
+	nonce := make([]byte, rss.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return rss.aead.Seal(nonce, nonce, buffer.Bytes(), nil), nil
+}
+
+// decode 解密并反序列化Session数据
+func (rss *RedisSessionStore) decode(payload []byte) (map[string]interface{}, error) {
+	if rss.aead != nil {
+		nonceSize := rss.aead.NonceSize()
+		if len(payload) < nonceSize {
+			return nil, ErrSessionDecryption
+		}
+
+		plaintext, err := rss.aead.Open(nil, payload[:nonceSize], payload[nonceSize:], nil)
+		if err != nil {
+			return nil, ErrSessionDecryption
+		}
+		payload = plaintext
+	}
+
+	data := make(map[string]interface{})
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// fakeRedisClient 内存实现的Redis客户端
+type fakeRedisClient struct {
+	values map[string]string
+	mu     sync.Mutex
+}
+
+func newFakeRedisClient() *fakeRedisClient {
+	return &fakeRedisClient{values: make(map[string]string)}
+}
+
+func (f *fakeRedisClient) Get(ctx context.Context, key string) *redis.StringCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	value, exists := f.values[key]
+	if !exists {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(value, nil)
+}
+
+func (f *fakeRedisClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[key] = string(value.([]byte))
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (f *fakeRedisClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var deleted int64
+	for _, key := range keys {
+		if _, exists := f.values[key]; exists {
+			delete(f.values, key)
+			deleted++
+		}
+	}
+	return redis.NewIntResult(deleted, nil)
+}
+
+func (f *fakeRedisClient) Expire(ctx context.Context, key string, expiration time.Duration) *redis.BoolCmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, exists := f.values[key]
+	return redis.NewBoolResult(exists, nil)
+}
+
+func TestRedisSessionStorePersistence(t *testing.T) {
+	client := newFakeRedisClient()
+	key := []byte("0123456789abcdef0123456789abcdef")
+
+	store := NewRedisSessionStore(client, "session:", time.Hour)
+	if err := store.SetEncryptionKey(key); err != nil {
+		t.Fatalf("Expected valid key, got: %v", err)
+	}
+	store.Put("cart", "apples")
+	if err := store.Err(); err != nil {
+		t.Fatalf("Expected no error saving session, got: %v", err)
+	}
+
+	// 存储的数据已加密
+	if payload := client.values["session:"+store.ID()]; payload == "" || strings.Contains(payload, "apples") {
+		t.Errorf("Expected encrypted payload, got %q", payload)
+	}
+
+	// 新的存储实例加载同一个Session
+	restored := NewRedisSessionStore(client, "session:", time.Hour)
+	restored.SetEncryptionKey(key)
+	if err := restored.Load(store.ID()); err != nil {
+		t.Fatalf("Expected no error loading session, got: %v", err)
+	}
+	if restored.ID() != store.ID() {
+		t.Errorf("Expected session ID %s, got %s", store.ID(), restored.ID())
+	}
+	if value := restored.Get("cart"); value != "apples" {
+		t.Errorf("Expected 'apples', got %v", value)
+	}
+
+	// 错误的密钥无法解密
+	other := NewRedisSessionStore(client, "session:", time.Hour)
+	other.SetEncryptionKey([]byte("fedcba9876543210fedcba9876543210"))
+	if err := other.Load(store.ID()); err != ErrSessionDecryption {
+		t.Errorf("Expected ErrSessionDecryption, got: %v", err)
+	}
+
+	// 不存在的Session不会沿用客户端提供的ID
+	unknown := NewRedisSessionStore(client, "session:", time.Hour)
+	if err := unknown.Load("attacker-chosen"); err != nil {
+		t.Fatalf("Expected no error loading missing session, got: %v", err)
+	}
+	if unknown.ID() == "attacker-chosen" {
+		t.Error("Expected a fresh session ID for missing session")
+	}
+}
+
+func TestRedisSessionGuardLoginRotatesID(t *testing.T) {
+	client := newFakeRedisClient()
+
+	provider := NewMemoryUserProvider()
+	user := &BaseUser{ID: 1, Email: "test@example.com", Password: "password"}
+	provider.AddUser(user)
+
+	store := NewRedisSessionStore(client, "session:", time.Hour)
+	store.Put("locale", "en")
+	before := store.ID()
+
+	guard := NewSessionGuard(provider, store)
+	if err := guard.Login(user); err != nil {
+		t.Fatalf("Expected no error during login, got: %v", err)
+	}
+
+	if store.ID() == before {
+		t.Error("Expected login to rotate the session ID")
+	}
+	if _, exists := client.values["session:"+before]; exists {
+		t.Error("Expected old session to be deleted")
+	}
+
+	// 新请求通过新的Session ID恢复登录状态
+	restored := NewRedisSessionStore(client, "session:", time.Hour)
+	if err := restored.Load(store.ID()); err != nil {
+		t.Fatalf("Expected no error loading session, got: %v", err)
+	}
+	if restored.Get("locale") != "en" {
+		t.Error("Expected session data to survive regeneration")
+	}
+
+	restoredGuard := NewSessionGuard(provider, restored)
+	if !restoredGuard.Check() {
+		t.Fatal("Expected user to be authenticated from restored session")
+	}
+	if restoredGuard.ID() != 1 {
+		t.Errorf("Expected user ID 1, got: %v", restoredGuard.ID())
+	}
+}
@@ -0,0 +1,310 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 记住我相关错误
+var (
+	ErrInvalidRememberToken = errors.New("invalid remember token")
+	ErrRememberTokenExpired = errors.New("remember token expired")
+)
+
+// DefaultRecallerCookie 默认的记住我Cookie名称
+const DefaultRecallerCookie = "remember_web"
+
+// RememberToken 记住我令牌
+//
+// 令牌由 selector 和 validator 两部分组成，存储中只保存 validator 的哈希，
+// 泄露存储内容也无法伪造Cookie。
+type RememberToken struct {
+	Selector      string
+	ValidatorHash string
+	UserID        interface{}
+	ExpiresAt     time.Time
+}
+
+// RememberTokenStore 记住我令牌存储接口
+type RememberTokenStore interface {
+	Save(token *RememberToken) error
+	Find(selector string) (*RememberToken, error)
+	Delete(selector string) error
+}
+
+// MemoryRememberTokenStore 内存记住我令牌存储
+type MemoryRememberTokenStore struct {
+	tokens map[string]*RememberToken
+	mu     sync.RWMutex
+}
+
+// NewMemoryRememberTokenStore 创建内存记住我令牌存储
+func NewMemoryRememberTokenStore() *MemoryRememberTokenStore {
+	return &MemoryRememberTokenStore{
+		tokens: make(map[string]*RememberToken),
+	}
+}
+
+// Save 保存令牌
+func (s *MemoryRememberTokenStore) Save(token *RememberToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token.Selector] = token
+	return nil
+}
+
+// Find 查找令牌
+func (s *MemoryRememberTokenStore) Find(selector string) (*RememberToken, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if token, exists := s.tokens[selector]; exists {
+		return token, nil
+	}
+	return nil, ErrInvalidRememberToken
+}
+
+// Delete 删除令牌
+func (s *MemoryRememberTokenStore) Delete(selector string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, selector)
+	return nil
+}
+
+// Recaller 记住我令牌签发器
+//
+// Cookie 的值为 base64(selector:validator).签名，每次使用后轮换令牌，
+// 旧令牌立即失效，以限制被窃取Cookie的重放。
+type Recaller struct {
+	store      RememberTokenStore
+	secret     []byte
+	ttl        time.Duration
+	CookieName string
+	Secure     bool
+}
+
+// NewRecaller 创建记住我令牌签发器，默认有效期为 30 天
+func NewRecaller(store RememberTokenStore, secret string, ttl time.Duration) *Recaller {
+	if ttl <= 0 {
+		ttl = 30 * 24 * time.Hour
+	}
+
+	return &Recaller{
+		store:      store,
+		secret:     []byte(secret),
+		ttl:        ttl,
+		CookieName: DefaultRecallerCookie,
+	}
+}
+
+// Issue 为用户签发新的记住我令牌，返回Cookie值
+func (r *Recaller) Issue(userID interface{}) (string, error) {
+	selector, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	validator, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	token := &RememberToken{
+		Selector:      selector,
+		ValidatorHash: hashValidator(validator),
+		UserID:        userID,
+		ExpiresAt:     time.Now().Add(r.ttl),
+	}
+	if err := r.store.Save(token); err != nil {
+		return "", err
+	}
+
+	return r.sign(selector + ":" + validator), nil
+}
+
+// Recall 校验Cookie值并轮换令牌，返回用户ID和新的Cookie值
+func (r *Recaller) Recall(value string) (interface{}, string, error) {
+	selector, validator, err := r.parse(value)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := r.store.Find(selector)
+	if err != nil {
+		return nil, "", ErrInvalidRememberToken
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token.ValidatorHash), []byte(hashValidator(validator))) != 1 {
+		// selector 正确但 validator 错误，可能是令牌已被轮换后的重放，删除令牌
+		r.store.Delete(selector)
+		return nil, "", ErrInvalidRememberToken
+	}
+
+	if err := r.store.Delete(selector); err != nil {
+		return nil, "", err
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, "", ErrRememberTokenExpired
+	}
+
+	rotated, err := r.Issue(token.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	return token.UserID, rotated, nil
+}
+
+// Forget 删除Cookie值对应的令牌
+func (r *Recaller) Forget(value string) error {
+	selector, _, err := r.parse(value)
+	if err != nil {
+		return err
+	}
+	return r.store.Delete(selector)
+}
+
+// Cookie 创建记住我Cookie
+func (r *Recaller) Cookie(value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     r.CookieName,
+		Value:    value,
+		Path:     "/",
+		Expires:  time.Now().Add(r.ttl),
+		MaxAge:   int(r.ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   r.Secure,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// ExpiredCookie 创建删除记住我Cookie的Cookie
+func (r *Recaller) ExpiredCookie() *http.Cookie {
+	return &http.Cookie{
+		Name:     r.CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.Secure,
+	}
+}
+
+// sign 对Cookie值签名
+func (r *Recaller) sign(payload string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + r.signature(encoded)
+}
+
+// parse 校验签名并解析Cookie值
+func (r *Recaller) parse(value string) (string, string, error) {
+	parts := strings.SplitN(value, ".", 2)
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(r.signature(parts[0]))) {
+		return "", "", ErrInvalidRememberToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", "", ErrInvalidRememberToken
+	}
+
+	pair := strings.SplitN(string(payload), ":", 2)
+	if len(pair) != 2 {
+		return "", "", ErrInvalidRememberToken
+	}
+	return pair[0], pair[1], nil
+}
+
+// signature 计算 HMAC-SHA256 签名
+func (r *Recaller) signature(data string) string {
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// RememberMiddleware 记住我中间件
+//
+// 读取请求中的记住我Cookie交给 SessionGuard，Session 过期时由 Check 透明地
+// 重新登录；令牌轮换或登出产生的Cookie在写入响应头之前自动设置。
+type RememberMiddleware struct {
+	guard *SessionGuard
+}
+
+// NewRememberMiddleware 创建记住我中间件
+func NewRememberMiddleware(guard *SessionGuard) *RememberMiddleware {
+	return &RememberMiddleware{
+		guard: guard,
+	}
+}
+
+// Handle 处理HTTP请求
+func (rm *RememberMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if rm.guard.recaller != nil {
+			if cookie, err := r.Cookie(rm.guard.recaller.CookieName); err == nil {
+				rm.guard.SetRecallerCookie(cookie.Value)
+			}
+		}
+
+		// 尝试通过记住我Cookie登录
+		rm.guard.Check()
+
+		writer := &recallerResponseWriter{ResponseWriter: w, guard: rm.guard}
+		next(writer, r)
+
+		// 处理器没有写入响应时，在返回前设置Cookie
+		if !writer.wroteHeader {
+			if cookie := rm.guard.QueuedCookie(); cookie != nil {
+				http.SetCookie(w, cookie)
+			}
+		}
+	}
+}
+
+// recallerResponseWriter 在写入响应头之前设置记住我Cookie
+type recallerResponseWriter struct {
+	http.ResponseWriter
+	guard       *SessionGuard
+	wroteHeader bool
+}
+
+// WriteHeader 写入响应头
+func (w *recallerResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if cookie := w.guard.QueuedCookie(); cookie != nil {
+			http.SetCookie(w.ResponseWriter, cookie)
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write 写入响应体
+func (w *recallerResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// randomHex 生成随机十六进制字符串
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashValidator 计算 validator 的哈希
+func hashValidator(validator string) string {
+	sum := sha256.Sum256([]byte(validator))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRememberMeAfterSessionLoss(t *testing.T) {
+	provider := NewMemoryUserProvider()
+	user := &BaseUser{ID: 1, Email: "test@example.com", Password: "password"}
+	provider.AddUser(user)
+
+	recaller := NewRecaller(NewMemoryRememberTokenStore(), "app-key", time.Hour)
+
+	guard := NewSessionGuard(provider, NewMemorySessionStore())
+	guard.SetRecaller(recaller)
+	if err := guard.Login(user, true); err != nil {
+		t.Fatalf("Expected no error during login, got: %v", err)
+	}
+
+	issued := guard.QueuedCookie()
+	if issued == nil || issued.Name != DefaultRecallerCookie || issued.Value == "" {
+		t.Fatalf("Expected recaller cookie, got %+v", issued)
+	}
+
+	// Session 丢失后通过记住我Cookie重新登录
+	restored := NewSessionGuard(provider, NewMemorySessionStore())
+	restored.SetRecaller(recaller)
+	restored.SetRecallerCookie(issued.Value)
+	if !restored.Check() {
+		t.Fatal("Expected user to be authenticated via remember token")
+	}
+	if !restored.ViaRemember() || restored.ID() != 1 {
+		t.Errorf("Expected user 1 via remember, got %v", restored.ID())
+	}
+
+	rotated := restored.QueuedCookie()
+	if rotated == nil || rotated.Value == issued.Value {
+		t.Fatal("Expected remember token to be rotated")
+	}
+
+	// 轮换后旧令牌失效
+	replay := NewSessionGuard(provider, NewMemorySessionStore())
+	replay.SetRecaller(recaller)
+	replay.SetRecallerCookie(issued.Value)
+	if replay.Check() {
+		t.Error("Expected old remember token to be rejected")
+	}
+	if cookie := replay.QueuedCookie(); cookie == nil || cookie.MaxAge >= 0 {
+		t.Error("Expected rejected recaller cookie to be cleared")
+	}
+
+	// 篡改签名的Cookie无效
+	if _, _, err := recaller.Recall(rotated.Value + "x"); err != ErrInvalidRememberToken {
+		t.Errorf("Expected ErrInvalidRememberToken, got: %v", err)
+	}
+}
+
+func TestLogoutRevokesRotatedRememberToken(t *testing.T) {
+	provider := NewMemoryUserProvider()
+	user := &BaseUser{ID: 1, Email: "test@example.com", Password: "password"}
+	provider.AddUser(user)
+
+	recaller := NewRecaller(NewMemoryRememberTokenStore(), "app-key", time.Hour)
+	value, err := recaller.Issue(user.GetID())
+	if err != nil {
+		t.Fatalf("Expected no error issuing token, got: %v", err)
+	}
+
+	guard := NewSessionGuard(provider, NewMemorySessionStore())
+	guard.SetRecaller(recaller)
+	guard.SetRecallerCookie(value)
+	if !guard.Check() {
+		t.Fatal("Expected user to be authenticated via remember token")
+	}
+	rotated := guard.QueuedCookie()
+	if rotated == nil || rotated.Value == value {
+		t.Fatal("Expected remember token to be rotated")
+	}
+
+	// 同一请求中登出，轮换后的令牌也要失效
+	guard.Logout()
+	if cookie := guard.QueuedCookie(); cookie == nil || cookie.MaxAge >= 0 {
+		t.Error("Expected recaller cookie to be cleared on logout")
+	}
+	if _, _, err := recaller.Recall(rotated.Value); err == nil {
+		t.Error("Expected rotated remember token to be revoked on logout")
+	}
+}
+
+func TestRememberMiddleware(t *testing.T) {
+	provider := NewMemoryUserProvider()
+	user := &BaseUser{ID: 1, Email: "test@example.com", Password: "password"}
+	provider.AddUser(user)
+
+	recaller := NewRecaller(NewMemoryRememberTokenStore(), "app-key", time.Hour)
+	value, err := recaller.Issue(user.GetID())
+	if err != nil {
+		t.Fatalf("Expected no error issuing token, got: %v", err)
+	}
+
+	guard := NewSessionGuard(provider, NewMemorySessionStore())
+	guard.SetRecaller(recaller)
+
+	handler := NewRememberMiddleware(guard).Handle(func(w http.ResponseWriter, r *http.Request) {
+		if !guard.Check() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.AddCookie(&http.Cookie{Name: DefaultRecallerCookie, Value: value})
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != DefaultRecallerCookie || cookies[0].Value == value {
+		t.Errorf("Expected rotated recaller cookie, got %v", cookies)
+	}
+}
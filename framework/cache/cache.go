@@ -1,7 +1,10 @@
 package cache
 
 import (
+	"sync"
 	"time"
+
+	"laravel-go/framework/lock"
 )
 
 // Store 缓存存储接口
@@ -81,6 +84,16 @@ type Manager struct {
 	stores       map[string]Store
 	defaultStore string
 	config       map[string]interface{}
+
+	// 缓存失效总线
+	bus      InvalidationBus
+	nodeID   string
+	busMutex sync.RWMutex
+
+	// 默认写入选项和缓存锁驱动
+	defaults     setOptions
+	locker       lock.Locker
+	optionsMutex sync.RWMutex
 }
 
 // NewManager 创建新的缓存管理器
@@ -115,9 +128,13 @@ func (m *Manager) SetDefaultStore(name string) {
 	m.defaultStore = name
 }
 
-// Get 获取缓存值
+// Get 获取缓存值，缓存的“不存在”返回 ErrNotFound
 func (m *Manager) Get(key string) (interface{}, error) {
-	return m.DefaultStore().Get(key)
+	value, err := m.DefaultStore().Get(key)
+	if err == nil && isNotFoundSentinel(value) {
+		return nil, ErrNotFound
+	}
+	return value, err
 }
 
 // GetString 获取字符串缓存值
@@ -146,48 +163,75 @@ func (m *Manager) GetBytes(key string) ([]byte, error) {
 }
 
 // Set 设置缓存值
-func (m *Manager) Set(key string, value interface{}, ttl time.Duration) error {
-	return m.DefaultStore().Set(key, value, ttl)
+func (m *Manager) Set(key string, value interface{}, ttl time.Duration, options ...SetOption) error {
+	if err := m.DefaultStore().Set(key, value, m.ttl(ttl, options)); err != nil {
+		return err
+	}
+	return m.invalidate(key)
 }
 
 // SetString 设置字符串缓存值
-func (m *Manager) SetString(key string, value string, ttl time.Duration) error {
-	return m.DefaultStore().SetString(key, value, ttl)
+func (m *Manager) SetString(key string, value string, ttl time.Duration, options ...SetOption) error {
+	if err := m.DefaultStore().SetString(key, value, m.ttl(ttl, options)); err != nil {
+		return err
+	}
+	return m.invalidate(key)
 }
 
 // SetInt 设置整数缓存值
-func (m *Manager) SetInt(key string, value int, ttl time.Duration) error {
-	return m.DefaultStore().SetInt(key, value, ttl)
+func (m *Manager) SetInt(key string, value int, ttl time.Duration, options ...SetOption) error {
+	if err := m.DefaultStore().SetInt(key, value, m.ttl(ttl, options)); err != nil {
+		return err
+	}
+	return m.invalidate(key)
 }
 
 // SetFloat 设置浮点数缓存值
-func (m *Manager) SetFloat(key string, value float64, ttl time.Duration) error {
-	return m.DefaultStore().SetFloat(key, value, ttl)
+func (m *Manager) SetFloat(key string, value float64, ttl time.Duration, options ...SetOption) error {
+	if err := m.DefaultStore().SetFloat(key, value, m.ttl(ttl, options)); err != nil {
+		return err
+	}
+	return m.invalidate(key)
 }
 
 // SetBool 设置布尔值缓存值
-func (m *Manager) SetBool(key string, value bool, ttl time.Duration) error {
-	return m.DefaultStore().SetBool(key, value, ttl)
+func (m *Manager) SetBool(key string, value bool, ttl time.Duration, options ...SetOption) error {
+	if err := m.DefaultStore().SetBool(key, value, m.ttl(ttl, options)); err != nil {
+		return err
+	}
+	return m.invalidate(key)
 }
 
 // SetBytes 设置字节数组缓存值
-func (m *Manager) SetBytes(key string, value []byte, ttl time.Duration) error {
-	return m.DefaultStore().SetBytes(key, value, ttl)
+func (m *Manager) SetBytes(key string, value []byte, ttl time.Duration, options ...SetOption) error {
+	if err := m.DefaultStore().SetBytes(key, value, m.ttl(ttl, options)); err != nil {
+		return err
+	}
+	return m.invalidate(key)
 }
 
 // Delete 删除缓存
 func (m *Manager) Delete(key string) error {
-	return m.DefaultStore().Delete(key)
+	if err := m.DefaultStore().Delete(key); err != nil {
+		return err
+	}
+	return m.invalidate(key)
 }
 
 // DeleteMultiple 批量删除缓存
 func (m *Manager) DeleteMultiple(keys []string) error {
-	return m.DefaultStore().DeleteMultiple(keys)
+	if err := m.DefaultStore().DeleteMultiple(keys); err != nil {
+		return err
+	}
+	return m.invalidate(keys...)
 }
 
 // Clear 清空所有缓存
 func (m *Manager) Clear() error {
-	return m.DefaultStore().Clear()
+	if err := m.DefaultStore().Clear(); err != nil {
+		return err
+	}
+	return m.invalidateAll()
 }
 
 // Has 检查缓存是否存在
@@ -202,17 +246,30 @@ func (m *Manager) Missing(key string) bool {
 
 // Increment 递增缓存值
 func (m *Manager) Increment(key string, value int) (int, error) {
-	return m.DefaultStore().Increment(key, value)
+	result, err := m.DefaultStore().Increment(key, value)
+	if err != nil {
+		return result, err
+	}
+	return result, m.invalidate(key)
 }
 
 // Decrement 递减缓存值
 func (m *Manager) Decrement(key string, value int) (int, error) {
-	return m.DefaultStore().Decrement(key, value)
+	result, err := m.DefaultStore().Decrement(key, value)
+	if err != nil {
+		return result, err
+	}
+	return result, m.invalidate(key)
 }
 
 // Remember 记住缓存值
-func (m *Manager) Remember(key string, ttl time.Duration, callback func() (interface{}, error)) (interface{}, error) {
-	return m.DefaultStore().Remember(key, ttl, callback)
+func (m *Manager) Remember(key string, ttl time.Duration, callback func() (interface{}, error), options ...SetOption) (interface{}, error) {
+	return m.DefaultStore().Remember(key, m.ttl(ttl, options), callback)
+}
+
+// RememberWithNegative 记住缓存值，并以 negativeTTL 缓存加载函数返回的 ErrNotFound
+func (m *Manager) RememberWithNegative(key string, ttl, negativeTTL time.Duration, callback func() (interface{}, error), options ...SetOption) (interface{}, error) {
+	return rememberWithNegative(m.DefaultStore(), key, m.ttl(ttl, options), m.ttl(negativeTTL, options), callback)
 }
 
 // RememberForever 永久记住缓存值
@@ -227,7 +284,10 @@ func (m *Manager) Tags(names ...string) TaggedStore {
 
 // Flush 刷新缓存
 func (m *Manager) Flush() error {
-	return m.DefaultStore().Flush()
+	if err := m.DefaultStore().Flush(); err != nil {
+		return err
+	}
+	return m.invalidateAll()
 }
 
 // Cache 全局缓存实例
@@ -249,8 +309,8 @@ func Get(key string) (interface{}, error) {
 }
 
 // Set 全局设置缓存值
-func Set(key string, value interface{}, ttl time.Duration) error {
-	return Cache.Set(key, value, ttl)
+func Set(key string, value interface{}, ttl time.Duration, options ...SetOption) error {
+	return Cache.Set(key, value, ttl, options...)
 }
 
 // Delete 全局删除缓存
@@ -264,6 +324,11 @@ func Has(key string) bool {
 }
 
 // Remember 全局记住缓存值
-func Remember(key string, ttl time.Duration, callback func() (interface{}, error)) (interface{}, error) {
-	return Cache.Remember(key, ttl, callback)
+func Remember(key string, ttl time.Duration, callback func() (interface{}, error), options ...SetOption) (interface{}, error) {
+	return Cache.Remember(key, ttl, callback, options...)
+}
+
+// RememberWithNegative 全局记住缓存值，并以 negativeTTL 缓存加载函数返回的 ErrNotFound
+func RememberWithNegative(key string, ttl, negativeTTL time.Duration, callback func() (interface{}, error), options ...SetOption) (interface{}, error) {
+	return Cache.RememberWithNegative(key, ttl, negativeTTL, callback, options...)
 }
@@ -1,8 +1,22 @@
 package cache
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"laravel-go/framework/container"
+	"laravel-go/framework/event"
+	"laravel-go/framework/lock"
+	"laravel-go/framework/performance"
+	"laravel-go/framework/scheduler"
 )
 
 func TestNewManager(t *testing.T) {
@@ -569,3 +583,657 @@ func TestCacheGetBytes(t *testing.T) {
 		t.Error("Bytes should not be empty")
 	}
 }
+
+// Warmer 可以直接作为定时任务的处理器
+var _ scheduler.TaskHandler = (*Warmer)(nil)
+
+func TestWarmer(t *testing.T) {
+	store := NewMemoryStore()
+	warmer := NewWarmer(store).SetConcurrency(2)
+
+	var running, maxRunning int32
+	slowLoader := func(value string) WarmLoader {
+		return func(ctx context.Context) (interface{}, error) {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				peak := atomic.LoadInt32(&maxRunning)
+				if current <= peak || atomic.CompareAndSwapInt32(&maxRunning, peak, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return value, nil
+		}
+	}
+	warmer.Register("settings", time.Minute, slowLoader("site settings"))
+	warmer.Register("menu", time.Minute, slowLoader("main menu"))
+	warmer.Register("popular", time.Minute, slowLoader("popular posts"))
+	warmer.Register("broken", time.Minute, func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("database unavailable")
+	})
+	warmer.Register("panicking", time.Minute, func(ctx context.Context) (interface{}, error) {
+		panic("nil map")
+	})
+
+	report := warmer.WarmAll(context.Background())
+
+	// 失败的提供者不影响其他键
+	if warmed := report.Warmed(); len(warmed) != 3 {
+		t.Errorf("Expected 3 warmed keys, got %v", warmed)
+	}
+	failed := report.Failed()
+	if failed["broken"] != "database unavailable" || failed["panicking"] == "" {
+		t.Errorf("Unexpected failures: %v", failed)
+	}
+	if report.Err() == nil {
+		t.Error("Expected report error for failed keys")
+	}
+	if value, _ := store.GetString("menu"); value != "main menu" {
+		t.Errorf("Expected warmed value in store, got %q", value)
+	}
+	if store.Has("broken") {
+		t.Error("Expected failed key not to be stored")
+	}
+	if maxRunning > 2 {
+		t.Errorf("Expected at most 2 concurrent loaders, got %d", maxRunning)
+	}
+	for _, result := range report.Results {
+		if result.Key == "settings" && result.Duration < 20*time.Millisecond {
+			t.Errorf("Expected loader timing to be recorded, got %s", result.Duration)
+		}
+	}
+
+	// 启动容器时执行预热
+	bootStore := NewMemoryStore()
+	bootWarmer := NewWarmer(bootStore).Register("settings", time.Minute, slowLoader("site settings"))
+	c := container.NewContainer()
+	c.Register(&WarmerServiceProvider{Warmer: bootWarmer})
+	if bootStore.Has("settings") {
+		t.Error("Expected cache to be warmed on boot, not on register")
+	}
+	c.Boot()
+	if !bootStore.Has("settings") {
+		t.Error("Expected cache to be warmed on boot")
+	}
+	if resolved, err := container.Make[*Warmer](c); err != nil || resolved != bootWarmer {
+		t.Errorf("Expected warmer to be bound in container, got %v, %v", resolved, err)
+	}
+}
+
+func TestRememberWithNegative(t *testing.T) {
+	manager := NewManager()
+	manager.Extend("memory", NewMemoryStore())
+
+	var calls int
+	loader := func() (interface{}, error) {
+		calls++
+		return nil, ErrNotFound
+	}
+
+	// 不存在的结果在 negativeTTL 内被缓存
+	for i := 0; i < 3; i++ {
+		if _, err := manager.RememberWithNegative("user:42", time.Minute, 50*time.Millisecond, loader); !IsNotFound(err) {
+			t.Fatalf("Expected ErrNotFound, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected loader to run once within negative TTL, ran %d times", calls)
+	}
+
+	// Get 区分缓存的不存在和未命中
+	if _, err := manager.Get("user:42"); !IsNotFound(err) {
+		t.Errorf("Expected cached absence to return ErrNotFound, got %v", err)
+	}
+	if _, err := manager.Get("user:43"); err == nil || IsNotFound(err) {
+		t.Errorf("Expected a plain miss for unknown key, got %v", err)
+	}
+
+	// negativeTTL 过期后重新加载，存在的结果使用正常的 ttl
+	time.Sleep(80 * time.Millisecond)
+	value, err := manager.RememberWithNegative("user:42", time.Minute, 50*time.Millisecond, func() (interface{}, error) {
+		calls++
+		return "alice", nil
+	})
+	if err != nil || value != "alice" || calls != 2 {
+		t.Errorf("Expected loader to run again after negative TTL, got %v, %v (%d calls)", value, err, calls)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if value, err := manager.Get("user:42"); err != nil || value != "alice" {
+		t.Errorf("Expected positive result to outlive negative TTL, got %v, %v", value, err)
+	}
+
+	// 其他错误不被缓存
+	failing := func() (interface{}, error) {
+		calls++
+		return nil, errors.New("database unavailable")
+	}
+	manager.RememberWithNegative("user:44", time.Minute, time.Minute, failing)
+	manager.RememberWithNegative("user:44", time.Minute, time.Minute, failing)
+	if calls != 4 {
+		t.Errorf("Expected other errors not to be cached, got %d calls", calls)
+	}
+}
+
+func TestShardedMemoryStore(t *testing.T) {
+	store := NewShardedMemoryStore(8)
+	defer store.Close()
+	store.SetPrefix("app:")
+
+	// 键分布在不同分片上
+	keys := make([]string, 200)
+	used := make(map[*MemoryStore]bool)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key:%d", i)
+		store.Set(keys[i], i, time.Minute)
+		shard, _ := store.shard(keys[i])
+		used[shard] = true
+	}
+	if len(used) != store.ShardCount() {
+		t.Errorf("Expected keys to span all %d shards, used %d", store.ShardCount(), len(used))
+	}
+	for i, key := range keys {
+		if value, err := store.GetInt(key); err != nil || value != i {
+			t.Fatalf("Expected %s = %d, got %d, %v", key, i, value, err)
+		}
+	}
+
+	// 并发递增保持原子性
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				store.Increment("counter", 1)
+			}
+		}()
+	}
+	wg.Wait()
+	if value, _ := store.GetInt("counter"); value != 800 {
+		t.Errorf("Expected counter 800, got %d", value)
+	}
+
+	// 批量删除跨分片的键
+	store.DeleteMultiple(keys[:100])
+	for i, key := range keys {
+		if store.Has(key) != (i >= 100) {
+			t.Fatalf("Unexpected presence for %s after DeleteMultiple", key)
+		}
+	}
+
+	// 统计信息汇总所有分片
+	stats := store.GetStats()
+	if stats["items"] != 101 || stats["sets"] != 200 || stats["deletes"] != 100 {
+		t.Errorf("Unexpected aggregated stats: %v", stats)
+	}
+
+	// 过期和前缀
+	store.Set("short", "value", 20*time.Millisecond)
+	time.Sleep(40 * time.Millisecond)
+	if store.Has("short") {
+		t.Error("Expected key to expire")
+	}
+	if _, err := store.shards[0].Get("key:150"); err == nil {
+		t.Error("Expected keys to be stored with prefix")
+	}
+
+	store.Clear()
+	if stats := store.GetStats(); stats["items"] != 0 {
+		t.Errorf("Expected all shards to be cleared, got %d items", stats["items"])
+	}
+}
+
+func TestInvalidationBus(t *testing.T) {
+	newNode := func(bus InvalidationBus) (*Manager, *MemoryStore) {
+		manager := NewManager()
+		store := NewMemoryStore()
+		manager.Extend("memory", store)
+		if err := manager.EnableInvalidationBus(bus); err != nil {
+			t.Fatalf("Failed to enable invalidation bus: %v", err)
+		}
+		return manager, store
+	}
+
+	bus := NewMemoryInvalidationBus()
+	nodeA, storeA := newNode(bus)
+	nodeB, storeB := newNode(bus)
+	if nodeA.NodeID() == "" || nodeA.NodeID() == nodeB.NodeID() {
+		t.Fatalf("Expected distinct node IDs, got %q and %q", nodeA.NodeID(), nodeB.NodeID())
+	}
+
+	// 两个节点都缓存了旧值
+	storeA.Set("user:1", "old", time.Hour)
+	storeB.Set("user:1", "old", time.Hour)
+
+	// 节点 A 更新后，节点 B 的本地副本被删除，节点 A 保留新值
+	if err := nodeA.Set("user:1", "new", time.Hour); err != nil {
+		t.Fatalf("Failed to set: %v", err)
+	}
+	if storeB.Has("user:1") {
+		t.Error("Expected stale copy on node B to be evicted")
+	}
+	if value, _ := nodeA.Get("user:1"); value != "new" {
+		t.Errorf("Expected origin node to keep its fresh write, got %v", value)
+	}
+
+	// 删除同样广播
+	storeB.Set("user:2", "value", time.Hour)
+	storeB.Set("user:3", "value", time.Hour)
+	nodeA.DeleteMultiple([]string{"user:2", "user:3"})
+	if storeB.Has("user:2") || storeB.Has("user:3") {
+		t.Error("Expected deleted keys to be evicted on node B")
+	}
+
+	nodeB.Increment("counter", 1)
+	storeA.Set("counter", 10, time.Hour)
+	nodeB.Increment("counter", 1)
+	if storeA.Has("counter") {
+		t.Error("Expected incremented key to be evicted on node A")
+	}
+	if value, _ := nodeB.GetInt("counter"); value != 2 {
+		t.Errorf("Expected counter on node B to be 2, got %d", value)
+	}
+
+	// 清空广播到所有节点
+	storeB.Set("other", "value", time.Hour)
+	nodeA.Clear()
+	if storeB.Has("other") {
+		t.Error("Expected clear to be broadcast to node B")
+	}
+
+	// 共享存储不会因失效消息被删除
+	shared := NewMemoryStore()
+	nodeC := NewManager()
+	nodeC.Extend("memory", &sharedStore{shared})
+	nodeC.EnableInvalidationBus(bus)
+	shared.Set("user:1", "shared", time.Hour)
+	nodeA.Set("user:1", "newer", time.Hour)
+	if !shared.Has("user:1") {
+		t.Error("Expected shared store not to be evicted")
+	}
+
+	// 基于事件系统的总线
+	dispatcher := event.NewEventDispatcher(nil)
+	defer dispatcher.Close()
+	nodeD, _ := newNode(NewEventInvalidationBus(dispatcher))
+	_, storeE := newNode(NewEventInvalidationBus(dispatcher))
+	storeE.Set("post:1", "old", time.Hour)
+	nodeD.Delete("post:1")
+	if storeE.Has("post:1") {
+		t.Error("Expected event bus to evict the key on the other node")
+	}
+}
+
+func TestTTLJitter(t *testing.T) {
+	manager := NewManager()
+	store := NewMemoryStore()
+	manager.Extend("memory", store)
+
+	expiries := func(prefix string, options ...SetOption) (time.Duration, time.Duration) {
+		min, max := time.Duration(1<<62), time.Duration(0)
+		for i := 0; i < 200; i++ {
+			key := fmt.Sprintf("%s:%d", prefix, i)
+			if err := manager.Set(key, i, 100*time.Second, options...); err != nil {
+				t.Fatalf("Failed to set: %v", err)
+			}
+			store.mutex.RLock()
+			ttl := time.Until(store.items[key].Expiration)
+			store.mutex.RUnlock()
+			if ttl < min {
+				min = ttl
+			}
+			if ttl > max {
+				max = ttl
+			}
+		}
+		return min, max
+	}
+
+	// 没有抖动时过期时间相同
+	if min, max := expiries("plain"); max-min > time.Second {
+		t.Errorf("Expected no spread without jitter, got %v..%v", min, max)
+	}
+
+	// 单次调用的抖动
+	min, max := expiries("call", WithJitter(0.2))
+	if min < 79*time.Second || max > 120*time.Second {
+		t.Errorf("Expected expiries within ±20%%, got %v..%v", min, max)
+	}
+	if max-min < 20*time.Second {
+		t.Errorf("Expected expiries to spread out, got %v..%v", min, max)
+	}
+
+	// 全局默认抖动，单次调用可以关闭
+	manager.SetDefaultOptions(WithJitter(0.1))
+	if min, max := expiries("default"); min < 89*time.Second || max > 110*time.Second || max-min < 10*time.Second {
+		t.Errorf("Expected default jitter within ±10%%, got %v..%v", min, max)
+	}
+	if min, max := expiries("disabled", WithJitter(0)); max-min > time.Second {
+		t.Errorf("Expected per-call option to override default, got %v..%v", min, max)
+	}
+
+	// 抖动后的 TTL 始终为正，不过期的键不加入抖动
+	for i := 0; i < 1000; i++ {
+		if ttl := jitterTTL(time.Nanosecond, 1); ttl <= 0 {
+			t.Fatalf("Expected positive ttl, got %v", ttl)
+		}
+	}
+	if ttl := jitterTTL(0, 0.5); ttl != 0 {
+		t.Errorf("Expected forever ttl to be kept, got %v", ttl)
+	}
+
+	value, err := manager.Remember("remember", time.Minute, func() (interface{}, error) {
+		return "value", nil
+	}, WithJitter(0.5))
+	if err != nil || value != "value" {
+		t.Errorf("Expected remember with jitter to work, got %v, %v", value, err)
+	}
+}
+
+func TestTypedCache(t *testing.T) {
+	type profile struct {
+		ID    int64     `json:"id"`
+		Name  string    `json:"name"`
+		Tags  []string  `json:"tags"`
+		Since time.Time `json:"since"`
+	}
+
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := profile{ID: 42, Name: "Alice", Tags: []string{"admin", "ops"}, Since: since}
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   NewFileStore(t.TempDir()),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			profiles := Typed[profile](store)
+
+			if _, ok, err := profiles.Get("profile:42"); ok || err != nil {
+				t.Fatalf("Expected miss, got ok=%v err=%v", ok, err)
+			}
+
+			if err := profiles.Set("profile:42", want, time.Hour); err != nil {
+				t.Fatalf("Failed to set: %v", err)
+			}
+			got, ok, err := profiles.Get("profile:42")
+			if err != nil || !ok {
+				t.Fatalf("Expected hit, got ok=%v err=%v", ok, err)
+			}
+			if got.ID != want.ID || got.Name != want.Name || len(got.Tags) != 2 || !got.Since.Equal(since) {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+
+			// 以其他类型读取返回明确的错误
+			_, _, err = Typed[int](store).Get("profile:42")
+			if !errors.Is(err, ErrTypeMismatch) {
+				t.Fatalf("Expected ErrTypeMismatch, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "profile") || !strings.Contains(err.Error(), "int") {
+				t.Errorf("Expected error to name both types, got %v", err)
+			}
+
+			store.Set("plain", "text", time.Hour)
+			if _, _, err := profiles.Get("plain"); !errors.Is(err, ErrTypeMismatch) {
+				t.Errorf("Expected ErrTypeMismatch for untyped value, got %v", err)
+			}
+
+			calls := 0
+			for i := 0; i < 2; i++ {
+				value, err := Typed[[]int](store).Remember("numbers", time.Hour, func() ([]int, error) {
+					calls++
+					return []int{1, 2, 3}, nil
+				})
+				if err != nil || len(value) != 3 {
+					t.Fatalf("Expected remembered value, got %v, %v", value, err)
+				}
+			}
+			if calls != 1 {
+				t.Errorf("Expected callback to run once, ran %d times", calls)
+			}
+		})
+	}
+}
+
+func TestRememberDistributed(t *testing.T) {
+	// 两个节点共享同一个存储和锁驱动，相当于共享同一个 Redis
+	shared := NewMemoryStore()
+	locker := lock.NewMemoryLocker()
+	newNode := func() *Manager {
+		manager := NewManager()
+		manager.Extend("memory", &sharedStore{shared})
+		manager.SetLocker(locker)
+		return manager
+	}
+	nodes := []*Manager{newNode(), newNode()}
+
+	var calls int32
+	loader := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(100 * time.Millisecond)
+		return "report", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan interface{}, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(node *Manager) {
+			defer wg.Done()
+			value, err := node.RememberDistributed("report", time.Hour, time.Second, loader)
+			if err != nil {
+				t.Errorf("Failed to remember: %v", err)
+			}
+			results <- value
+		}(nodes[i%2])
+	}
+	wg.Wait()
+	close(results)
+
+	if calls != 1 {
+		t.Errorf("Expected loader to run once cluster-wide, ran %d times", calls)
+	}
+	for value := range results {
+		if value != "report" {
+			t.Errorf("Expected every node to get the loaded value, got %v", value)
+		}
+	}
+
+	// 锁被长期占用时，等待超时后在本地加载
+	mutex, err := nodes[0].Lock("slow", time.Minute)
+	if err != nil {
+		t.Fatalf("Failed to lock: %v", err)
+	}
+	if _, err := nodes[1].Lock("slow", time.Minute); !errors.Is(err, ErrLockHeld) {
+		t.Errorf("Expected ErrLockHeld, got %v", err)
+	}
+	start := time.Now()
+	value, err := nodes[1].RememberDistributed("slow", time.Hour, 50*time.Millisecond, func() (interface{}, error) {
+		return "local", nil
+	})
+	if err != nil || value != "local" {
+		t.Errorf("Expected fallback to local loader, got %v, %v", value, err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("Expected to wait about 50ms before falling back, waited %v", elapsed)
+	}
+	mutex.Unlock(context.Background())
+	if mutex, err := nodes[1].Lock("slow", time.Minute); err != nil {
+		t.Errorf("Expected lock to be free after unlock, got %v", err)
+	} else {
+		mutex.Unlock(context.Background())
+	}
+}
+
+func TestSweeper(t *testing.T) {
+	memory := NewMemoryStore()
+	defer memory.Close()
+	memory.Set("expired", "value", 10*time.Millisecond)
+	memory.Set("fresh", "value", time.Hour)
+
+	files := NewFileStore(t.TempDir())
+	files.Set("expired", "value", time.Millisecond)
+	files.Set("fresh", "value", time.Hour)
+	os.WriteFile(filepath.Join(files.directory, "corrupt.cache"), []byte("{"), 0644)
+
+	time.Sleep(30 * time.Millisecond)
+
+	// 过期后从未读取的项被清理
+	if swept, err := memory.Sweep(); err != nil || swept != 1 {
+		t.Errorf("Expected 1 memory entry swept, got %d, %v", swept, err)
+	}
+	if stats := memory.GetStats(); stats["items"] != 1 || stats["swept"] != 1 {
+		t.Errorf("Expected 1 item left and 1 swept, got %v", stats)
+	}
+	if swept, err := files.Sweep(); err != nil || swept != 2 {
+		t.Errorf("Expected expired and corrupt files swept, got %d, %v", swept, err)
+	}
+	if _, err := os.Stat(files.getFilePath("expired")); !os.IsNotExist(err) {
+		t.Error("Expected expired file to be deleted")
+	}
+	if !files.Has("fresh") || files.Swept() != 2 {
+		t.Errorf("Expected fresh file to be kept and 2 swept, got %d", files.Swept())
+	}
+
+	// 后台清理
+	memory.StartGC(10 * time.Millisecond)
+	memory.Set("short", "value", 5*time.Millisecond)
+	files.StartGC(10 * time.Millisecond)
+	defer files.StopGC()
+	files.Set("short", "value", time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	if stats := memory.GetStats(); stats["swept"] != 2 {
+		t.Errorf("Expected background sweep of memory store, got %v", stats)
+	}
+	if files.Swept() != 3 {
+		t.Errorf("Expected background sweep of file store, got %d", files.Swept())
+	}
+
+	// 定时任务处理器
+	sharded := NewShardedMemoryStore(4)
+	defer sharded.Close()
+	for i := 0; i < 10; i++ {
+		sharded.Set(fmt.Sprintf("key:%d", i), i, 10*time.Millisecond)
+	}
+	time.Sleep(30 * time.Millisecond)
+	handler := NewSweepHandler(sharded, files)
+	if err := handler.Handle(context.Background()); err != nil {
+		t.Fatalf("Failed to sweep: %v", err)
+	}
+	if stats := sharded.GetStats(); stats["items"] != 0 || stats["swept"] != 10 {
+		t.Errorf("Expected handler to sweep sharded store, got %v", stats)
+	}
+	if handler.GetName() != "cache:gc" {
+		t.Errorf("Unexpected handler name %s", handler.GetName())
+	}
+}
+
+func TestMemoryPressureEviction(t *testing.T) {
+	store := NewMemoryStore()
+	defer store.Close()
+	for i := 0; i < 6; i++ {
+		store.Set(fmt.Sprintf("live:%d", i), i, time.Hour)
+	}
+	store.Set("expired:0", 0, time.Millisecond)
+	store.Set("expired:1", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	// live:0 最久未使用，live:5 最近使用
+	for i := 0; i < 6; i++ {
+		store.items[fmt.Sprintf("live:%d", i)].lastAccess = int64(i + 1)
+	}
+
+	monitor := performance.NewPerformanceMonitor()
+	heap := performance.NewGauge("go_heap_alloc", nil)
+	total := performance.NewGauge("memory_total", nil)
+	monitor.RegisterMetric(heap)
+	monitor.RegisterMetric(total)
+	total.Set(1000)
+
+	evictor := NewMemoryPressureEvictor(0.8, store)
+	evictor.SetFraction(0.5)
+
+	// 内存正常时不淘汰
+	heap.Set(500)
+	evictor.Collect(monitor)
+	if stats := store.GetStats(); stats["items"] != 8 || stats["evicted"] != 0 {
+		t.Fatalf("Expected cache to stay intact under normal memory, got %v", stats)
+	}
+
+	// 超过高水位时淘汰一半：先淘汰过期项，再淘汰最久未使用的项
+	heap.Set(900)
+	evictor.Collect(monitor)
+	if stats := store.GetStats(); stats["items"] != 4 || stats["evicted"] != 4 {
+		t.Fatalf("Expected half of the entries to be evicted, got %v", stats)
+	}
+	for _, key := range []string{"expired:0", "expired:1", "live:0", "live:1"} {
+		if _, exists := store.items[key]; exists {
+			t.Errorf("Expected %s to be evicted", key)
+		}
+	}
+	for _, key := range []string{"live:2", "live:3", "live:4", "live:5"} {
+		if !store.Has(key) {
+			t.Errorf("Expected recently used %s to be kept", key)
+		}
+	}
+	if evictor.Evicted() != 4 || evictor.LastEviction().IsZero() {
+		t.Errorf("Unexpected evictor stats: %d", evictor.Evicted())
+	}
+
+	// 注册到系统监控器后淘汰全局缓存管理器中的内存存储
+	previous := Cache
+	defer func() { Cache = previous }()
+	sharded := NewShardedMemoryStore(4)
+	defer sharded.Close()
+	for i := 0; i < 40; i++ {
+		sharded.Set(fmt.Sprintf("key:%d", i), i, time.Hour)
+	}
+	Cache = NewManager()
+	Cache.Extend("memory", sharded)
+
+	global := EnableMemoryPressureEviction(performance.NewSystemMonitor(monitor), 0.8)
+	global.Collect(monitor)
+	if stats := sharded.GetStats(); stats["items"] >= 40 || stats["evicted"] == 0 {
+		t.Errorf("Expected global memory stores to be evicted, got %v", stats)
+	}
+}
+
+// sharedStore 模拟多个节点共享的存储
+type sharedStore struct {
+	Store
+}
+
+// benchmarkConcurrentStore 并发读写混合负载，读写比例 9:1
+func benchmarkConcurrentStore(b *testing.B, store Store) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key:%d", i)
+		store.Set(keys[i], i, time.Hour)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			if i%10 == 0 {
+				store.Set(key, i, time.Hour)
+			} else {
+				store.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkMemoryStoreConcurrent(b *testing.B) {
+	store := NewMemoryStore()
+	defer store.Close()
+	benchmarkConcurrentStore(b, store)
+}
+
+func BenchmarkShardedMemoryStoreConcurrent(b *testing.B) {
+	store := NewShardedMemoryStore(32)
+	defer store.Close()
+	benchmarkConcurrentStore(b, store)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -25,6 +26,11 @@ func (item *FileItem) IsExpired() bool {
 type FileStore struct {
 	directory string
 	prefix    string
+
+	// 过期文件清理
+	gcStop  chan struct{}
+	gcMutex sync.Mutex
+	swept   int64
 }
 
 // NewFileStore 创建新的文件缓存存储
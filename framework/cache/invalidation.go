@@ -0,0 +1,218 @@
+package cache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"laravel-go/framework/event"
+)
+
+// InvalidationEventName 通过事件系统广播缓存失效消息时使用的事件名称
+const InvalidationEventName = "cache.invalidated"
+
+// InvalidationMessage 缓存失效消息
+type InvalidationMessage struct {
+	// Origin 发出消息的节点 ID，节点忽略自己发出的消息
+	Origin string `json:"origin"`
+	// Keys 失效的缓存键
+	Keys []string `json:"keys,omitempty"`
+	// Clear 是否清空所有缓存
+	Clear bool `json:"clear,omitempty"`
+}
+
+// InvalidationBus 缓存失效消息总线
+//
+// 多实例部署时各节点共享同一条总线：一个节点修改缓存后广播失效的键，其他节点从本地存储中
+// 删除这些键，避免继续读取旧值。
+type InvalidationBus interface {
+	// Publish 广播失效消息
+	Publish(message InvalidationMessage) error
+	// Subscribe 订阅失效消息
+	Subscribe(handler func(InvalidationMessage)) error
+}
+
+// EnableInvalidationBus 启用缓存失效总线
+//
+// 启用后通过管理器执行的 Set、Delete、Increment、Clear 等写操作会广播失效消息，收到其他节点
+// 的消息时删除本地存储（内存、分片内存和文件存储）中对应的键。Redis 等共享存储的数据对所有
+// 节点可见，不会因失效消息被删除。节点忽略自己发出的消息，刚写入的新值不会被删除。
+func (m *Manager) EnableInvalidationBus(bus InvalidationBus) error {
+	if bus == nil {
+		return fmt.Errorf("invalidation bus is nil")
+	}
+
+	m.busMutex.Lock()
+	if m.nodeID == "" {
+		m.nodeID = generateNodeID()
+	}
+	m.bus = bus
+	m.busMutex.Unlock()
+
+	return bus.Subscribe(m.handleInvalidation)
+}
+
+// NodeID 获取节点 ID，未启用失效总线时为空
+func (m *Manager) NodeID() string {
+	m.busMutex.RLock()
+	defer m.busMutex.RUnlock()
+	return m.nodeID
+}
+
+// invalidate 广播缓存键失效
+func (m *Manager) invalidate(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return m.publish(InvalidationMessage{Keys: keys})
+}
+
+// invalidateAll 广播清空所有缓存
+func (m *Manager) invalidateAll() error {
+	return m.publish(InvalidationMessage{Clear: true})
+}
+
+// publish 在启用失效总线时广播消息
+func (m *Manager) publish(message InvalidationMessage) error {
+	m.busMutex.RLock()
+	bus, nodeID := m.bus, m.nodeID
+	m.busMutex.RUnlock()
+
+	if bus == nil {
+		return nil
+	}
+	message.Origin = nodeID
+	if err := bus.Publish(message); err != nil {
+		return fmt.Errorf("failed to publish cache invalidation: %w", err)
+	}
+	return nil
+}
+
+// handleInvalidation 处理其他节点发出的失效消息，只删除本地存储中的数据
+func (m *Manager) handleInvalidation(message InvalidationMessage) {
+	if message.Origin == m.NodeID() {
+		return
+	}
+
+	for _, store := range m.stores {
+		if !isLocalStore(store) {
+			continue
+		}
+		if message.Clear {
+			store.Clear()
+			continue
+		}
+		if len(message.Keys) > 0 {
+			store.DeleteMultiple(message.Keys)
+		}
+	}
+}
+
+// isLocalStore 检查存储是否只保存在当前节点
+func isLocalStore(store Store) bool {
+	switch store.(type) {
+	case *MemoryStore, *ShardedMemoryStore, *FileStore:
+		return true
+	default:
+		return false
+	}
+}
+
+// generateNodeID 生成节点 ID
+func generateNodeID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// MemoryInvalidationBus 进程内缓存失效总线
+//
+// 消息同步投递给所有订阅者，适用于单进程内的多个缓存实例和测试。
+type MemoryInvalidationBus struct {
+	mutex    sync.RWMutex
+	handlers []func(InvalidationMessage)
+}
+
+// NewMemoryInvalidationBus 创建进程内缓存失效总线
+func NewMemoryInvalidationBus() *MemoryInvalidationBus {
+	return &MemoryInvalidationBus{}
+}
+
+// Publish 广播失效消息
+func (bus *MemoryInvalidationBus) Publish(message InvalidationMessage) error {
+	bus.mutex.RLock()
+	handlers := append([]func(InvalidationMessage){}, bus.handlers...)
+	bus.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		handler(message)
+	}
+	return nil
+}
+
+// Subscribe 订阅失效消息
+func (bus *MemoryInvalidationBus) Subscribe(handler func(InvalidationMessage)) error {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.handlers = append(bus.handlers, handler)
+	return nil
+}
+
+// EventInvalidationBus 基于事件系统的缓存失效总线
+//
+// 失效消息作为 cache.invalidated 事件分发，事件分发器使用跨进程的队列时即可在节点间广播。
+type EventInvalidationBus struct {
+	dispatcher event.Dispatcher
+}
+
+// NewEventInvalidationBus 创建基于事件系统的缓存失效总线
+func NewEventInvalidationBus(dispatcher event.Dispatcher) *EventInvalidationBus {
+	return &EventInvalidationBus{
+		dispatcher: dispatcher,
+	}
+}
+
+// Publish 广播失效消息
+func (bus *EventInvalidationBus) Publish(message InvalidationMessage) error {
+	return bus.dispatcher.Dispatch(event.NewEvent(InvalidationEventName, message))
+}
+
+// Subscribe 订阅失效消息
+func (bus *EventInvalidationBus) Subscribe(handler func(InvalidationMessage)) error {
+	bus.dispatcher.Listen(InvalidationEventName, event.NewListener("cache.invalidation", func(e event.Event) error {
+		message, err := decodeInvalidationMessage(e.GetPayload())
+		if err != nil {
+			return err
+		}
+		handler(message)
+		return nil
+	}))
+	return nil
+}
+
+// decodeInvalidationMessage 解析事件载荷，经过序列化的事件载荷为 map
+func decodeInvalidationMessage(payload interface{}) (InvalidationMessage, error) {
+	switch p := payload.(type) {
+	case InvalidationMessage:
+		return p, nil
+	case *InvalidationMessage:
+		return *p, nil
+	}
+
+	var message InvalidationMessage
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return message, fmt.Errorf("invalid cache invalidation payload: %w", err)
+	}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return message, fmt.Errorf("invalid cache invalidation payload: %w", err)
+	}
+	return message, nil
+}
+
+// EnableInvalidationBus 为全局缓存启用缓存失效总线
+func EnableInvalidationBus(bus InvalidationBus) error {
+	return Cache.EnableInvalidationBus(bus)
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"math/rand"
+	"time"
+)
+
+// SetOption 缓存写入选项
+type SetOption func(*setOptions)
+
+// setOptions 缓存写入配置
+type setOptions struct {
+	jitter float64
+}
+
+// WithJitter 为过期时间加入 ±fraction 比例的随机抖动
+//
+// 同一时间以相同 TTL 写入的大量键（例如启动预热）会在同一时刻过期，引发缓存击穿。加入抖动后
+// 过期时间分散在 [ttl*(1-fraction), ttl*(1+fraction)] 区间内。fraction 限制在 [0, 1) 之间，
+// 抖动后的 TTL 始终为正；TTL 不大于 0（永不过期）时不加入抖动。
+func WithJitter(fraction float64) SetOption {
+	return func(o *setOptions) {
+		o.jitter = fraction
+	}
+}
+
+// SetDefaultOptions 设置管理器默认的写入选项，单次调用传入的选项优先
+func (m *Manager) SetDefaultOptions(options ...SetOption) {
+	m.optionsMutex.Lock()
+	defer m.optionsMutex.Unlock()
+	for _, option := range options {
+		option(&m.defaults)
+	}
+}
+
+// ttl 按默认选项和单次调用的选项计算实际写入的 TTL
+func (m *Manager) ttl(ttl time.Duration, options []SetOption) time.Duration {
+	m.optionsMutex.RLock()
+	config := m.defaults
+	m.optionsMutex.RUnlock()
+
+	for _, option := range options {
+		option(&config)
+	}
+	return jitterTTL(ttl, config.jitter)
+}
+
+// jitterTTL 为 TTL 加入随机抖动
+func jitterTTL(ttl time.Duration, fraction float64) time.Duration {
+	if ttl <= 0 || fraction <= 0 {
+		return ttl
+	}
+	if fraction >= 1 {
+		fraction = 0.99
+	}
+
+	jittered := time.Duration(float64(ttl) * (1 + fraction*(2*rand.Float64()-1)))
+	if jittered <= 0 {
+		return ttl
+	}
+	return jittered
+}
+
+// SetDefaultOptions 设置全局缓存默认的写入选项
+func SetDefaultOptions(options ...SetOption) {
+	Cache.SetDefaultOptions(options...)
+}
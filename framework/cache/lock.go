@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"laravel-go/framework/lock"
+)
+
+// ErrLockHeld 缓存锁已被其他持有者占用
+var ErrLockHeld = errors.New("cache: lock is held by another owner")
+
+// lockKeyPrefix 缓存锁键的前缀，避免与缓存键冲突
+const lockKeyPrefix = "cache:lock:"
+
+// lockPollInterval 等待其他节点写入缓存时的轮询间隔
+const lockPollInterval = 20 * time.Millisecond
+
+// SetLocker 设置缓存锁使用的锁驱动
+//
+// 多实例部署时应使用所有节点共享的锁驱动，例如 lock.NewRedisLocker；未设置时使用
+// lock.DefaultLocker()。
+func (m *Manager) SetLocker(locker lock.Locker) {
+	m.optionsMutex.Lock()
+	defer m.optionsMutex.Unlock()
+	m.locker = locker
+}
+
+// Locker 获取缓存锁使用的锁驱动
+func (m *Manager) Locker() lock.Locker {
+	m.optionsMutex.RLock()
+	defer m.optionsMutex.RUnlock()
+	if m.locker == nil {
+		return lock.DefaultLocker()
+	}
+	return m.locker
+}
+
+// Lock 获取缓存锁，锁已被占用时返回 ErrLockHeld
+//
+// 获取成功后锁自动续期，使用完毕后调用 Unlock 释放：
+//
+//	mutex, err := cache.Lock("report", 10*time.Second)
+//	if err != nil {
+//		return err
+//	}
+//	defer mutex.Unlock(context.Background())
+func (m *Manager) Lock(key string, ttl time.Duration) (*lock.Mutex, error) {
+	mutex := lock.NewMutex(m.Locker(), lockKeyPrefix+key, ttl)
+	acquired, err := mutex.TryLock(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, ErrLockHeld
+	}
+	return mutex, nil
+}
+
+// RememberDistributed 记住缓存值，集群中只有一个节点执行加载函数
+//
+// 缓存未命中时先获取缓存锁，获取成功的节点执行加载函数并写入缓存，其他节点轮询等待缓存
+// 出现。持有锁的节点加载失败时，等待中的节点会接手获取锁；等待超过 wait 或锁驱动不可用时
+// 在本地执行加载函数。
+func (m *Manager) RememberDistributed(key string, ttl, wait time.Duration, callback func() (interface{}, error), options ...SetOption) (interface{}, error) {
+	store := m.DefaultStore()
+	if value, err := store.Get(key); err == nil {
+		return value, nil
+	}
+
+	leaseTTL := wait
+	if leaseTTL < time.Second {
+		leaseTTL = time.Second
+	}
+	mutex := lock.NewMutex(m.Locker(), lockKeyPrefix+key, leaseTTL)
+
+	deadline := time.Now().Add(wait)
+	for {
+		acquired, err := mutex.TryLock(context.Background())
+		if err != nil {
+			// 锁驱动不可用时退化为本地加载
+			break
+		}
+		if acquired {
+			defer mutex.Unlock(context.Background())
+			// 获取锁之前其他节点可能已经写入
+			if value, err := store.Get(key); err == nil {
+				return value, nil
+			}
+			break
+		}
+
+		if !time.Now().Before(deadline) {
+			break
+		}
+		time.Sleep(lockPollInterval)
+		if value, err := store.Get(key); err == nil {
+			return value, nil
+		}
+	}
+
+	value, err := callback()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Set(key, value, ttl, options...); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Lock 获取全局缓存的缓存锁
+func Lock(key string, ttl time.Duration) (*lock.Mutex, error) {
+	return Cache.Lock(key, ttl)
+}
+
+// RememberDistributed 全局记住缓存值，集群中只有一个节点执行加载函数
+func RememberDistributed(key string, ttl, wait time.Duration, callback func() (interface{}, error), options ...SetOption) (interface{}, error) {
+	return Cache.RememberDistributed(key, ttl, wait, callback, options...)
+}
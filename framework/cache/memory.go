@@ -15,6 +15,8 @@ type MemoryItem struct {
 	Expiration time.Time
 	// 添加原子计数器用于引用计数
 	refCount int32
+	// 最近一次读写的时间（UnixNano），内存压力淘汰时优先淘汰最久未使用的项
+	lastAccess int64
 }
 
 // IsExpired 检查是否过期
@@ -37,6 +39,16 @@ func (item *MemoryItem) GetRefCount() int32 {
 	return atomic.LoadInt32(&item.refCount)
 }
 
+// touch 记录访问时间
+func (item *MemoryItem) touch() {
+	atomic.StoreInt64(&item.lastAccess, time.Now().UnixNano())
+}
+
+// LastAccess 获取最近一次读写的时间
+func (item *MemoryItem) LastAccess() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&item.lastAccess))
+}
+
 // MemoryStore 内存缓存存储
 type MemoryStore struct {
 	items  map[string]*MemoryItem
@@ -48,6 +60,8 @@ type MemoryStore struct {
 		misses  int64
 		sets    int64
 		deletes int64
+		swept   int64
+		evicted int64
 	}
 	// 添加清理控制
 	cleanupTicker *time.Ticker
@@ -81,8 +95,8 @@ func (store *MemoryStore) cleanupExpired() {
 	}
 }
 
-// cleanupExpiredItems 清理过期项的具体实现
-func (store *MemoryStore) cleanupExpiredItems() {
+// cleanupExpiredItems 清理过期项的具体实现，返回清理的数量
+func (store *MemoryStore) cleanupExpiredItems() int {
 	store.mutex.Lock()
 	defer store.mutex.Unlock()
 
@@ -99,6 +113,9 @@ func (store *MemoryStore) cleanupExpiredItems() {
 		delete(store.items, key)
 		atomic.AddInt64(&store.stats.deletes, 1)
 	}
+	atomic.AddInt64(&store.stats.swept, int64(len(expiredKeys)))
+
+	return len(expiredKeys)
 }
 
 // Get 获取缓存值
@@ -123,6 +140,7 @@ func (store *MemoryStore) Get(key string) (interface{}, error) {
 
 	// 增加引用计数
 	item.IncrementRef()
+	item.touch()
 	atomic.AddInt64(&store.stats.hits, 1)
 
 	return item.Value, nil
@@ -248,6 +266,7 @@ func (store *MemoryStore) Set(key string, value interface{}, ttl time.Duration)
 		Expiration: expiration,
 		refCount:   1,
 	}
+	item.touch()
 
 	store.items[store.prefix+key] = item
 	atomic.AddInt64(&store.stats.sets, 1)
@@ -369,9 +388,11 @@ func (store *MemoryStore) Increment(key string, value int) (int, error) {
 	}
 
 	newValue := current + value
-	store.items[store.prefix+key] = &MemoryItem{
+	item = &MemoryItem{
 		Value: newValue,
 	}
+	item.touch()
+	store.items[store.prefix+key] = item
 
 	return newValue, nil
 }
@@ -429,12 +450,18 @@ func (store *MemoryStore) SetPrefix(prefix string) {
 
 // GetStats 获取缓存统计信息
 func (store *MemoryStore) GetStats() map[string]int64 {
+	store.mutex.RLock()
+	items := len(store.items)
+	store.mutex.RUnlock()
+
 	return map[string]int64{
 		"hits":    atomic.LoadInt64(&store.stats.hits),
 		"misses":  atomic.LoadInt64(&store.stats.misses),
 		"sets":    atomic.LoadInt64(&store.stats.sets),
 		"deletes": atomic.LoadInt64(&store.stats.deletes),
-		"items":   int64(len(store.items)),
+		"swept":   atomic.LoadInt64(&store.stats.swept),
+		"evicted": atomic.LoadInt64(&store.stats.evicted),
+		"items":   int64(items),
 	}
 }
 
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound 数据不存在
+//
+// 加载函数返回该错误时，RememberWithNegative 会在较短的时间内缓存“不存在”这一结果；
+// Manager.Get 读取到缓存的“不存在”时也返回该错误，以区别于缓存未命中。
+var ErrNotFound = errors.New("cache: value not found")
+
+// notFoundSentinel 缓存“不存在”时写入的标记值，使用字符串以兼容需要序列化的驱动
+const notFoundSentinel = "__laravel_go_cache_not_found__"
+
+// IsNotFound 检查错误是否表示数据不存在（而不是缓存未命中）
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// isNotFoundSentinel 检查缓存值是否为“不存在”标记
+func isNotFoundSentinel(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == notFoundSentinel
+	case []byte:
+		return string(v) == notFoundSentinel
+	default:
+		return false
+	}
+}
+
+// rememberWithNegative 记住缓存值，加载函数返回 ErrNotFound 时以 negativeTTL 缓存“不存在”
+//
+// 在 negativeTTL 内重复查找不存在的数据时直接返回 ErrNotFound，不再执行加载函数。
+// negativeTTL 应短于 ttl，使新创建的数据能尽快被读取到；negativeTTL 不大于 0 时不缓存“不存在”。
+func rememberWithNegative(store Store, key string, ttl, negativeTTL time.Duration, callback func() (interface{}, error)) (interface{}, error) {
+	if value, err := store.Get(key); err == nil {
+		if isNotFoundSentinel(value) {
+			return nil, ErrNotFound
+		}
+		return value, nil
+	}
+
+	value, err := callback()
+	if errors.Is(err, ErrNotFound) {
+		if negativeTTL > 0 {
+			if setErr := store.Set(key, notFoundSentinel, negativeTTL); setErr != nil {
+				return nil, setErr
+			}
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Set(key, value, ttl); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
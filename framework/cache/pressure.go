@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"math"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"laravel-go/framework/performance"
+)
+
+// MemoryEvicter 可以在内存压力下按比例淘汰缓存项的存储
+type MemoryEvicter interface {
+	// EvictFraction 淘汰 fraction（0-1）比例的缓存项，返回淘汰的数量
+	EvictFraction(fraction float64) int
+}
+
+// EvictFraction 淘汰 fraction 比例的缓存项
+//
+// 过期项总是全部淘汰并计入数量，不足时按最近访问时间淘汰最久未使用的项。
+func (store *MemoryStore) EvictFraction(fraction float64) int {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if len(store.items) == 0 || fraction <= 0 {
+		return 0
+	}
+	target := int(math.Ceil(float64(len(store.items)) * math.Min(fraction, 1)))
+
+	type candidate struct {
+		key        string
+		lastAccess int64
+	}
+	live := make([]candidate, 0, len(store.items))
+	evicted := 0
+	for key, item := range store.items {
+		if item.IsExpired() {
+			delete(store.items, key)
+			evicted++
+			continue
+		}
+		live = append(live, candidate{key: key, lastAccess: atomic.LoadInt64(&item.lastAccess)})
+	}
+
+	if evicted < target {
+		sort.Slice(live, func(i, j int) bool {
+			return live[i].lastAccess < live[j].lastAccess
+		})
+		for _, c := range live[:min(target-evicted, len(live))] {
+			delete(store.items, c.key)
+			evicted++
+		}
+	}
+
+	atomic.AddInt64(&store.stats.deletes, int64(evicted))
+	atomic.AddInt64(&store.stats.evicted, int64(evicted))
+	return evicted
+}
+
+// EvictFraction 每个分片淘汰 fraction 比例的缓存项
+func (store *ShardedMemoryStore) EvictFraction(fraction float64) int {
+	evicted := 0
+	for _, shard := range store.shards {
+		evicted += shard.EvictFraction(fraction)
+	}
+	return evicted
+}
+
+// MemoryPressureEvictor 内存压力淘汰器
+//
+// 作为 SystemMonitor 的收集器运行，在内存监控器之后读取 go_heap_alloc 指标，
+// 堆内存占内存上限的比例达到高水位时从各个内存缓存中淘汰一部分缓存项。
+// 内存上限优先使用 GOMEMLIMIT（debug.SetMemoryLimit），未设置时使用 memory_total 指标。
+type MemoryPressureEvictor struct {
+	highWatermark float64
+	fraction      float64
+	stores        []MemoryEvicter
+	lastEviction  time.Time
+	evicted       int64
+	mu            sync.Mutex
+}
+
+// NewMemoryPressureEvictor 创建内存压力淘汰器，highWatermark 为 0-1 之间的比例
+//
+// 未指定 stores 时淘汰全局缓存管理器中所有支持 MemoryEvicter 的存储。每次淘汰 25% 的缓存项。
+func NewMemoryPressureEvictor(highWatermark float64, stores ...MemoryEvicter) *MemoryPressureEvictor {
+	return &MemoryPressureEvictor{
+		highWatermark: highWatermark,
+		fraction:      0.25,
+		stores:        stores,
+	}
+}
+
+// SetFraction 设置每次淘汰的比例
+func (e *MemoryPressureEvictor) SetFraction(fraction float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.fraction = fraction
+}
+
+// Name 收集器名称
+func (e *MemoryPressureEvictor) Name() string {
+	return "cache_memory_pressure"
+}
+
+// Collect 检查内存使用率，达到高水位时淘汰缓存项
+func (e *MemoryPressureEvictor) Collect(monitor performance.Monitor) error {
+	heap, ok := gaugeValue(monitor, "go_heap_alloc")
+	if !ok {
+		return nil
+	}
+
+	limit := float64(0)
+	if memoryLimit := debug.SetMemoryLimit(-1); memoryLimit != math.MaxInt64 {
+		limit = float64(memoryLimit)
+	} else if limit, ok = gaugeValue(monitor, "memory_total"); !ok || limit <= 0 {
+		return nil
+	}
+
+	if heap/limit >= e.highWatermark {
+		e.Evict()
+	}
+	return nil
+}
+
+// Evict 立即从所有存储中淘汰缓存项，返回淘汰的数量
+func (e *MemoryPressureEvictor) Evict() int {
+	e.mu.Lock()
+	fraction := e.fraction
+	stores := e.stores
+	e.mu.Unlock()
+
+	if len(stores) == 0 {
+		stores = managerEvicters(Cache)
+	}
+
+	evicted := 0
+	for _, store := range stores {
+		evicted += store.EvictFraction(fraction)
+	}
+
+	e.mu.Lock()
+	e.lastEviction = time.Now()
+	e.evicted += int64(evicted)
+	e.mu.Unlock()
+	return evicted
+}
+
+// Evicted 获取累计淘汰的数量
+func (e *MemoryPressureEvictor) Evicted() int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.evicted
+}
+
+// LastEviction 获取最近一次淘汰的时间
+func (e *MemoryPressureEvictor) LastEviction() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.lastEviction
+}
+
+// EnableMemoryPressureEviction 在系统监控器中注册内存压力淘汰器
+//
+// 系统监控器每次收集内存指标后检查堆内存使用率，达到 highWatermark（0-1）时
+// 从全局缓存管理器的内存存储中淘汰缓存项，优先淘汰过期项和最久未使用的项。
+func EnableMemoryPressureEviction(systemMonitor *performance.SystemMonitor, highWatermark float64) *MemoryPressureEvictor {
+	evictor := NewMemoryPressureEvictor(highWatermark)
+	systemMonitor.AddCollector(evictor)
+	return evictor
+}
+
+// managerEvicters 获取缓存管理器中支持淘汰的存储
+func managerEvicters(manager *Manager) []MemoryEvicter {
+	if manager == nil {
+		return nil
+	}
+	evicters := make([]MemoryEvicter, 0, len(manager.stores))
+	for _, store := range manager.stores {
+		if evicter, ok := store.(MemoryEvicter); ok {
+			evicters = append(evicters, evicter)
+		}
+	}
+	return evicters
+}
+
+// gaugeValue 读取监控器中的数值指标
+func gaugeValue(monitor performance.Monitor, name string) (float64, bool) {
+	metric := monitor.GetMetric(name)
+	if metric == nil {
+		return 0, false
+	}
+	switch v := metric.Value().(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
@@ -388,3 +388,51 @@ func (store *RedisStore) SetTTL(key string, ttl time.Duration) error {
 
 	return nil
 }
+
+// RedisInvalidationBus 基于 Redis 发布订阅的缓存失效总线
+type RedisInvalidationBus struct {
+	client  *redis.Client
+	channel string
+}
+
+// NewRedisInvalidationBus 创建基于 Redis 发布订阅的缓存失效总线，channel 为空时使用 cache:invalidation
+func NewRedisInvalidationBus(client *redis.Client, channel string) *RedisInvalidationBus {
+	if channel == "" {
+		channel = "cache:invalidation"
+	}
+	return &RedisInvalidationBus{
+		client:  client,
+		channel: channel,
+	}
+}
+
+// Publish 广播失效消息
+func (bus *RedisInvalidationBus) Publish(message InvalidationMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal invalidation message: %w", err)
+	}
+	return bus.client.Publish(context.Background(), bus.channel, data).Err()
+}
+
+// Subscribe 订阅失效消息，在后台 goroutine 中接收消息
+func (bus *RedisInvalidationBus) Subscribe(handler func(InvalidationMessage)) error {
+	ctx := context.Background()
+	pubsub := bus.client.Subscribe(ctx, bus.channel)
+	// 等待订阅确认，确保返回后发布的消息不会丢失
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe invalidation channel: %w", err)
+	}
+
+	go func() {
+		for msg := range pubsub.Channel() {
+			var message InvalidationMessage
+			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+				continue
+			}
+			handler(message)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,226 @@
+package cache
+
+import "time"
+
+// ShardedMemoryStore 分片内存缓存存储
+//
+// 键按哈希分布到多个独立加锁的 MemoryStore 分片上，高并发下不同分片的读写互不阻塞。
+// 每个分片独立清理过期项，统计信息汇总所有分片。
+type ShardedMemoryStore struct {
+	shards []*MemoryStore
+	prefix string
+}
+
+// NewShardedMemoryStore 创建分片内存缓存存储，shards 不大于 0 时使用 16 个分片
+func NewShardedMemoryStore(shards int) *ShardedMemoryStore {
+	if shards <= 0 {
+		shards = 16
+	}
+
+	store := &ShardedMemoryStore{
+		shards: make([]*MemoryStore, shards),
+	}
+	for i := range store.shards {
+		store.shards[i] = NewMemoryStore()
+	}
+	return store
+}
+
+// shard 获取键所在的分片和带前缀的完整键
+func (store *ShardedMemoryStore) shard(key string) (*MemoryStore, string) {
+	fullKey := store.prefix + key
+
+	// FNV-1a 哈希，直接计算以避免每次调用分配哈希对象
+	hash := uint32(2166136261)
+	for i := 0; i < len(fullKey); i++ {
+		hash ^= uint32(fullKey[i])
+		hash *= 16777619
+	}
+	return store.shards[hash%uint32(len(store.shards))], fullKey
+}
+
+// Get 获取缓存值
+func (store *ShardedMemoryStore) Get(key string) (interface{}, error) {
+	shard, fullKey := store.shard(key)
+	return shard.Get(fullKey)
+}
+
+// GetString 获取字符串缓存值
+func (store *ShardedMemoryStore) GetString(key string) (string, error) {
+	shard, fullKey := store.shard(key)
+	return shard.GetString(fullKey)
+}
+
+// GetInt 获取整数缓存值
+func (store *ShardedMemoryStore) GetInt(key string) (int, error) {
+	shard, fullKey := store.shard(key)
+	return shard.GetInt(fullKey)
+}
+
+// GetFloat 获取浮点数缓存值
+func (store *ShardedMemoryStore) GetFloat(key string) (float64, error) {
+	shard, fullKey := store.shard(key)
+	return shard.GetFloat(fullKey)
+}
+
+// GetBool 获取布尔值缓存值
+func (store *ShardedMemoryStore) GetBool(key string) (bool, error) {
+	shard, fullKey := store.shard(key)
+	return shard.GetBool(fullKey)
+}
+
+// GetBytes 获取字节数组缓存值
+func (store *ShardedMemoryStore) GetBytes(key string) ([]byte, error) {
+	shard, fullKey := store.shard(key)
+	return shard.GetBytes(fullKey)
+}
+
+// Set 设置缓存值
+func (store *ShardedMemoryStore) Set(key string, value interface{}, ttl time.Duration) error {
+	shard, fullKey := store.shard(key)
+	return shard.Set(fullKey, value, ttl)
+}
+
+// SetString 设置字符串缓存值
+func (store *ShardedMemoryStore) SetString(key string, value string, ttl time.Duration) error {
+	return store.Set(key, value, ttl)
+}
+
+// SetInt 设置整数缓存值
+func (store *ShardedMemoryStore) SetInt(key string, value int, ttl time.Duration) error {
+	return store.Set(key, value, ttl)
+}
+
+// SetFloat 设置浮点数缓存值
+func (store *ShardedMemoryStore) SetFloat(key string, value float64, ttl time.Duration) error {
+	return store.Set(key, value, ttl)
+}
+
+// SetBool 设置布尔值缓存值
+func (store *ShardedMemoryStore) SetBool(key string, value bool, ttl time.Duration) error {
+	return store.Set(key, value, ttl)
+}
+
+// SetBytes 设置字节数组缓存值
+func (store *ShardedMemoryStore) SetBytes(key string, value []byte, ttl time.Duration) error {
+	return store.Set(key, value, ttl)
+}
+
+// Delete 删除缓存项
+func (store *ShardedMemoryStore) Delete(key string) error {
+	shard, fullKey := store.shard(key)
+	return shard.Delete(fullKey)
+}
+
+// DeleteMultiple 批量删除缓存项，按分片分组后每个分片只加锁一次
+func (store *ShardedMemoryStore) DeleteMultiple(keys []string) error {
+	groups := make(map[*MemoryStore][]string)
+	for _, key := range keys {
+		shard, fullKey := store.shard(key)
+		groups[shard] = append(groups[shard], fullKey)
+	}
+
+	for shard, fullKeys := range groups {
+		if err := shard.DeleteMultiple(fullKeys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear 清空所有分片
+func (store *ShardedMemoryStore) Clear() error {
+	for _, shard := range store.shards {
+		if err := shard.Clear(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Has 检查键是否存在
+func (store *ShardedMemoryStore) Has(key string) bool {
+	shard, fullKey := store.shard(key)
+	return shard.Has(fullKey)
+}
+
+// Missing 检查缓存是否不存在
+func (store *ShardedMemoryStore) Missing(key string) bool {
+	return !store.Has(key)
+}
+
+// Increment 递增缓存值
+func (store *ShardedMemoryStore) Increment(key string, value int) (int, error) {
+	shard, fullKey := store.shard(key)
+	return shard.Increment(fullKey, value)
+}
+
+// Decrement 递减缓存值
+func (store *ShardedMemoryStore) Decrement(key string, value int) (int, error) {
+	return store.Increment(key, -value)
+}
+
+// Remember 记住缓存值
+func (store *ShardedMemoryStore) Remember(key string, ttl time.Duration, callback func() (interface{}, error)) (interface{}, error) {
+	shard, fullKey := store.shard(key)
+	return shard.Remember(fullKey, ttl, callback)
+}
+
+// RememberForever 永久记住缓存值
+func (store *ShardedMemoryStore) RememberForever(key string, callback func() (interface{}, error)) (interface{}, error) {
+	return store.Remember(key, 0, callback)
+}
+
+// Tags 获取标签管理器
+func (store *ShardedMemoryStore) Tags(names ...string) TaggedStore {
+	return NewMemoryTaggedStore(store, names...)
+}
+
+// Flush 刷新缓存
+func (store *ShardedMemoryStore) Flush() error {
+	return store.Clear()
+}
+
+// GetPrefix 获取缓存键前缀
+func (store *ShardedMemoryStore) GetPrefix() string {
+	return store.prefix
+}
+
+// SetPrefix 设置缓存键前缀
+func (store *ShardedMemoryStore) SetPrefix(prefix string) {
+	store.prefix = prefix
+}
+
+// ShardCount 分片数量
+func (store *ShardedMemoryStore) ShardCount() int {
+	return len(store.shards)
+}
+
+// GetStats 获取所有分片汇总的统计信息
+func (store *ShardedMemoryStore) GetStats() map[string]int64 {
+	stats := map[string]int64{
+		"hits":    0,
+		"misses":  0,
+		"sets":    0,
+		"deletes": 0,
+		"swept":   0,
+		"evicted": 0,
+		"items":   0,
+	}
+	for _, shard := range store.shards {
+		for name, value := range shard.GetStats() {
+			stats[name] += value
+		}
+	}
+	return stats
+}
+
+// Close 关闭所有分片
+func (store *ShardedMemoryStore) Close() error {
+	for _, shard := range store.shards {
+		if err := shard.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
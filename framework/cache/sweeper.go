@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// Sweeper 可以清理过期项的缓存存储
+//
+// 内存和文件驱动只在读取时检查过期，过期后从未读取的项会一直占用内存或磁盘，
+// 需要定期清理。
+type Sweeper interface {
+	// Sweep 清理过期项，返回清理的数量
+	Sweep() (int, error)
+}
+
+// Sweep 清理过期项，返回清理的数量
+func (store *MemoryStore) Sweep() (int, error) {
+	return store.cleanupExpiredItems(), nil
+}
+
+// StartGC 修改后台清理过期项的间隔，默认每分钟清理一次
+func (store *MemoryStore) StartGC(interval time.Duration) {
+	if interval > 0 {
+		store.cleanupTicker.Reset(interval)
+	}
+}
+
+// Sweep 清理所有分片的过期项，返回清理的数量
+func (store *ShardedMemoryStore) Sweep() (int, error) {
+	swept := 0
+	for _, shard := range store.shards {
+		swept += shard.cleanupExpiredItems()
+	}
+	return swept, nil
+}
+
+// StartGC 修改所有分片后台清理过期项的间隔
+func (store *ShardedMemoryStore) StartGC(interval time.Duration) {
+	for _, shard := range store.shards {
+		shard.StartGC(interval)
+	}
+}
+
+// Sweep 删除已过期和无法解析的缓存文件，返回删除的数量
+func (store *FileStore) Sweep() (int, error) {
+	files, err := filepath.Glob(filepath.Join(store.directory, "*.cache"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list cache files: %w", err)
+	}
+
+	swept := 0
+	var errs []error
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			// 文件可能已被并发删除
+			if !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		var item FileItem
+		if err := json.Unmarshal(data, &item); err == nil && !item.IsExpired() {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			if !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		swept++
+	}
+
+	atomic.AddInt64(&store.swept, int64(swept))
+	return swept, errors.Join(errs...)
+}
+
+// Swept 获取累计清理的文件数量
+func (store *FileStore) Swept() int64 {
+	return atomic.LoadInt64(&store.swept)
+}
+
+// StartGC 启动后台清理，每隔 interval 删除一次过期文件
+//
+// 重复调用时按新的间隔重新启动。
+func (store *FileStore) StartGC(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	store.gcMutex.Lock()
+	defer store.gcMutex.Unlock()
+
+	if store.gcStop != nil {
+		close(store.gcStop)
+	}
+	stop := make(chan struct{})
+	store.gcStop = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := store.Sweep(); err != nil {
+					log.Printf("Failed to sweep file cache: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopGC 停止后台清理
+func (store *FileStore) StopGC() {
+	store.gcMutex.Lock()
+	defer store.gcMutex.Unlock()
+
+	if store.gcStop != nil {
+		close(store.gcStop)
+		store.gcStop = nil
+	}
+}
+
+// SweepHandler 清理过期项的定时任务处理器
+//
+//	s.Add(scheduler.NewTask("cache-gc", "清理过期缓存", "0 */5 * * * *", cache.NewSweepHandler(fileStore)))
+type SweepHandler struct {
+	stores []Sweeper
+}
+
+// NewSweepHandler 创建清理过期项的定时任务处理器
+func NewSweepHandler(stores ...Sweeper) *SweepHandler {
+	return &SweepHandler{
+		stores: stores,
+	}
+}
+
+// Handle 清理所有存储的过期项
+func (h *SweepHandler) Handle(ctx context.Context) error {
+	swept := 0
+	var errs []error
+	for _, store := range h.stores {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		count, err := store.Sweep()
+		swept += count
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	log.Printf("Cache swept %d expired entries", swept)
+	return errors.Join(errs...)
+}
+
+// GetName 定时任务处理器名称
+func (h *SweepHandler) GetName() string {
+	return "cache:gc"
+}
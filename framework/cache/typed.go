@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrTypeMismatch 缓存值的类型与读取的类型不一致
+var ErrTypeMismatch = errors.New("cache: type mismatch")
+
+// typedPrefix 类型化缓存值的前缀，值格式为 前缀 + 类型名 + 换行 + JSON
+//
+// 带前缀的值不是合法的 JSON，Redis、数据库等会尝试解析 JSON 的驱动会原样返回字符串。
+const typedPrefix = "__laravel_go_typed__:"
+
+// TypedCache 类型化缓存
+//
+// 值以 JSON 编码后写入底层存储，读取时解码为 T，调用方不需要再做类型断言。
+// 底层存储可以是任意驱动，包括 Redis 等只能保存字符串的驱动。
+type TypedCache[T any] struct {
+	store    Store
+	typeName string
+}
+
+// Typed 创建类型化缓存
+func Typed[T any](store Store) *TypedCache[T] {
+	return &TypedCache[T]{
+		store:    store,
+		typeName: reflect.TypeOf((*T)(nil)).Elem().String(),
+	}
+}
+
+// Get 获取缓存值，键不存在时返回 false
+//
+// 缓存值由其他类型的类型化缓存写入时返回 ErrTypeMismatch。
+func (c *TypedCache[T]) Get(key string) (T, bool, error) {
+	var zero T
+	if !c.store.Has(key) {
+		return zero, false, nil
+	}
+
+	value, err := c.store.Get(key)
+	if err != nil {
+		// 检查后过期
+		if !c.store.Has(key) {
+			return zero, false, nil
+		}
+		return zero, false, err
+	}
+
+	result, err := c.decode(key, value)
+	if err != nil {
+		return zero, false, err
+	}
+	return result, true, nil
+}
+
+// Set 设置缓存值
+func (c *TypedCache[T]) Set(key string, value T, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache value: %w", err)
+	}
+	return c.store.Set(key, typedPrefix+c.typeName+"\n"+string(data), ttl)
+}
+
+// Delete 删除缓存
+func (c *TypedCache[T]) Delete(key string) error {
+	return c.store.Delete(key)
+}
+
+// Remember 获取缓存值，不存在时调用 callback 并写入缓存
+func (c *TypedCache[T]) Remember(key string, ttl time.Duration, callback func() (T, error)) (T, error) {
+	if value, ok, err := c.Get(key); err != nil || ok {
+		return value, err
+	}
+
+	value, err := callback()
+	if err != nil {
+		return value, err
+	}
+	if err := c.Set(key, value, ttl); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+// decode 解码底层存储中的值
+func (c *TypedCache[T]) decode(key string, value interface{}) (T, error) {
+	var result T
+
+	var raw string
+	switch v := value.(type) {
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	}
+
+	if !strings.HasPrefix(raw, typedPrefix) {
+		// 内存等驱动可能保存着直接写入的原始值
+		if v, ok := value.(T); ok {
+			return v, nil
+		}
+		return result, fmt.Errorf("%w: key %s holds %T, want %s", ErrTypeMismatch, key, value, c.typeName)
+	}
+
+	typeName, data, _ := strings.Cut(strings.TrimPrefix(raw, typedPrefix), "\n")
+	if typeName != c.typeName {
+		return result, fmt.Errorf("%w: key %s holds %s, want %s", ErrTypeMismatch, key, typeName, c.typeName)
+	}
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		return result, fmt.Errorf("failed to unmarshal cache value for key %s: %w", key, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,248 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"laravel-go/framework/container"
+)
+
+// WarmLoader 预热加载函数，返回要写入缓存的值
+type WarmLoader func(ctx context.Context) (interface{}, error)
+
+// warmProvider 已注册的预热提供者
+type warmProvider struct {
+	key    string
+	ttl    time.Duration
+	loader WarmLoader
+}
+
+// WarmResult 单个键的预热结果
+type WarmResult struct {
+	Key      string        `json:"key"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// WarmReport 预热报告
+type WarmReport struct {
+	Results  []WarmResult  `json:"results"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Warmed 成功预热的键
+func (r *WarmReport) Warmed() []string {
+	keys := make([]string, 0, len(r.Results))
+	for _, result := range r.Results {
+		if result.Error == "" {
+			keys = append(keys, result.Key)
+		}
+	}
+	return keys
+}
+
+// Failed 预热失败的键及错误信息
+func (r *WarmReport) Failed() map[string]string {
+	failed := make(map[string]string)
+	for _, result := range r.Results {
+		if result.Error != "" {
+			failed[result.Key] = result.Error
+		}
+	}
+	return failed
+}
+
+// Err 存在失败的键时返回汇总错误
+func (r *WarmReport) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(failed))
+	for key, message := range failed {
+		messages = append(messages, fmt.Sprintf("%s: %s", key, message))
+	}
+	sort.Strings(messages)
+	return fmt.Errorf("cache warm up failed for %d keys: %s", len(failed), strings.Join(messages, "; "))
+}
+
+// Warmer 缓存预热注册表
+//
+// 组件在启动阶段注册键和加载函数，WarmAll 在接收流量前并发执行加载函数并写入缓存，
+// 单个加载函数失败或 panic 不影响其他键。Warmer 实现了定时器的 TaskHandler 接口，
+// 可以直接作为定时任务的处理器定期重新预热。
+type Warmer struct {
+	store       Store
+	concurrency int
+	providers   map[string]*warmProvider
+	mu          sync.RWMutex
+}
+
+// NewWarmer 创建缓存预热注册表，store 为 nil 时使用全局缓存的默认存储
+func NewWarmer(store Store) *Warmer {
+	return &Warmer{
+		store:       store,
+		concurrency: 4,
+		providers:   make(map[string]*warmProvider),
+	}
+}
+
+// SetConcurrency 设置同时执行的加载函数数量
+func (w *Warmer) SetConcurrency(concurrency int) *Warmer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if concurrency > 0 {
+		w.concurrency = concurrency
+	}
+	return w
+}
+
+// Register 注册预热提供者，同一个键重复注册时替换
+func (w *Warmer) Register(key string, ttl time.Duration, loader WarmLoader) *Warmer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.providers[key] = &warmProvider{key: key, ttl: ttl, loader: loader}
+	return w
+}
+
+// Unregister 注销预热提供者
+func (w *Warmer) Unregister(key string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.providers, key)
+}
+
+// Keys 已注册的键
+func (w *Warmer) Keys() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	keys := make([]string, 0, len(w.providers))
+	for key := range w.providers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WarmAll 并发执行所有加载函数并写入缓存
+func (w *Warmer) WarmAll(ctx context.Context) *WarmReport {
+	w.mu.RLock()
+	providers := make([]*warmProvider, 0, len(w.providers))
+	for _, provider := range w.providers {
+		providers = append(providers, provider)
+	}
+	concurrency := w.concurrency
+	store := w.store
+	w.mu.RUnlock()
+
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].key < providers[j].key
+	})
+	if store == nil && Cache != nil {
+		store = Cache.DefaultStore()
+	}
+
+	start := time.Now()
+	results := make([]WarmResult, len(providers))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func(i int, provider *warmProvider) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				results[i] = WarmResult{Key: provider.key, Error: ctx.Err().Error()}
+				return
+			}
+			results[i] = warm(ctx, store, provider)
+		}(i, provider)
+	}
+	wg.Wait()
+
+	return &WarmReport{
+		Results:  results,
+		Duration: time.Since(start),
+	}
+}
+
+// warm 执行单个加载函数并写入缓存，panic 视为失败
+func warm(ctx context.Context, store Store, provider *warmProvider) (result WarmResult) {
+	start := time.Now()
+	result.Key = provider.key
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result.Error = fmt.Sprintf("loader panicked: %v", recovered)
+		}
+		result.Duration = time.Since(start)
+	}()
+
+	if store == nil {
+		result.Error = "cache store is not configured"
+		return
+	}
+
+	value, err := provider.loader(ctx)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	if err := store.Set(provider.key, value, provider.ttl); err != nil {
+		result.Error = err.Error()
+	}
+	return
+}
+
+// Handle 执行预热，作为定时任务的处理器使用
+func (w *Warmer) Handle(ctx context.Context) error {
+	report := w.WarmAll(ctx)
+	log.Printf("Cache warmed %d keys in %s", len(report.Warmed()), report.Duration)
+	return report.Err()
+}
+
+// GetName 定时任务处理器名称
+func (w *Warmer) GetName() string {
+	return "cache:warm"
+}
+
+// WarmerServiceProvider 在容器启动时执行缓存预热的服务提供者
+type WarmerServiceProvider struct {
+	Warmer *Warmer
+}
+
+// Register 将预热注册表绑定到容器
+func (p *WarmerServiceProvider) Register(c container.Container) {
+	c.BindCallback((*Warmer)(nil), func(container.Container) interface{} {
+		return p.Warmer
+	})
+}
+
+// Boot 执行缓存预热，失败的键只记录日志，不阻止应用启动
+func (p *WarmerServiceProvider) Boot(c container.Container) {
+	if err := p.Warmer.Handle(context.Background()); err != nil {
+		log.Printf("%v", err)
+	}
+}
+
+// DefaultWarmer 全局缓存预热注册表，使用全局缓存的默认存储
+var DefaultWarmer = NewWarmer(nil)
+
+// RegisterWarmer 向全局预热注册表注册预热提供者
+func RegisterWarmer(key string, ttl time.Duration, loader WarmLoader) {
+	DefaultWarmer.Register(key, ttl, loader)
+}
+
+// WarmAll 执行全局预热注册表中的所有加载函数
+func WarmAll(ctx context.Context) *WarmReport {
+	return DefaultWarmer.WarmAll(ctx)
+}
@@ -0,0 +1,56 @@
+package cluster
+
+import (
+	"context"
+	"time"
+)
+
+// Cluster 集群接口，队列和调度器的分布式实现共用
+type Cluster interface {
+	// 节点管理
+	Register(nodeID string, info NodeInfo) error
+	Unregister(nodeID string) error
+	GetNodes() ([]NodeInfo, error)
+
+	// 分布式锁
+	AcquireLock(key string, ttl time.Duration) (bool, error)
+	ReleaseLock(key string) error
+
+	// 选举
+	StartElection(callback func(bool)) error
+	StopElection() error
+
+	// 消息广播
+	Broadcast(msg ClusterMessage) error
+	Subscribe(callback func(ClusterMessage)) error
+
+	// 成员变更
+	OnNodeJoin(callback func(NodeInfo)) error
+	OnNodeLeave(callback func(NodeInfo)) error
+}
+
+// ContextSubscriber 支持取消订阅的集群，Cluster 的可选接口
+//
+// SubscribeContext 阻塞直到 ctx 结束或集群关闭，返回前移除回调。
+type ContextSubscriber interface {
+	SubscribeContext(ctx context.Context, callback func(ClusterMessage)) error
+}
+
+// NodeInfo 节点信息
+type NodeInfo struct {
+	ID        string            `json:"id"`
+	Address   string            `json:"address"`
+	Port      int               `json:"port"`
+	Status    string            `json:"status"` // online, offline, leader
+	StartedAt time.Time         `json:"started_at"`
+	LastSeen  time.Time         `json:"last_seen"`
+	Metadata  map[string]string `json:"metadata"`
+}
+
+// ClusterMessage 集群消息
+type ClusterMessage struct {
+	Type      string    `json:"type"`
+	NodeID    string    `json:"node_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      []byte    `json:"data"`
+}
@@ -0,0 +1,91 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMembershipSync(t *testing.T) {
+	var m Membership
+	var joined, left []string
+	m.AddJoin(func(node NodeInfo) { joined = append(joined, node.ID) })
+	m.AddLeave(func(node NodeInfo) { left = append(left, node.ID) })
+
+	// 首次同步只记录快照
+	m.Sync([]NodeInfo{{ID: "node-1"}, {ID: "node-2"}})
+	if len(joined) != 0 || len(left) != 0 {
+		t.Fatalf("Expected no events on first sync, got joined=%v left=%v", joined, left)
+	}
+
+	m.Sync([]NodeInfo{{ID: "node-2"}, {ID: "node-3"}})
+	if len(joined) != 1 || joined[0] != "node-3" {
+		t.Errorf("Expected node-3 to join, got %v", joined)
+	}
+	if len(left) != 1 || left[0] != "node-1" {
+		t.Errorf("Expected node-1 to leave, got %v", left)
+	}
+}
+
+func TestMemoryClusterMembership(t *testing.T) {
+	hub := NewMemoryClusterHub()
+	node1 := NewMemoryCluster(MemoryClusterConfig{NodeID: "node-1", Hub: hub})
+	node2 := NewMemoryCluster(MemoryClusterConfig{NodeID: "node-2", Hub: hub})
+	defer node1.Close()
+
+	var joined, left []string
+	node1.OnNodeJoin(func(node NodeInfo) { joined = append(joined, node.ID) })
+	node1.OnNodeLeave(func(node NodeInfo) { left = append(left, node.ID) })
+
+	node1.Register("node-1", NodeInfo{})
+	node2.Register("node-2", NodeInfo{})
+	// 心跳重复注册不触发加入事件
+	node2.Register("node-2", NodeInfo{})
+	if len(joined) != 2 {
+		t.Errorf("Expected 2 join events, got %v", joined)
+	}
+
+	node2.Close()
+	if len(left) != 1 || left[0] != "node-2" {
+		t.Errorf("Expected node-2 to leave, got %v", left)
+	}
+
+	nodes, _ := node1.GetNodes()
+	if len(nodes) != 1 || nodes[0].ID != "node-1" {
+		t.Errorf("Expected only node-1 to remain, got %v", nodes)
+	}
+}
+
+func TestMemoryClusterSubscribeContext(t *testing.T) {
+	hub := NewMemoryClusterHub()
+	node1 := NewMemoryCluster(MemoryClusterConfig{NodeID: "node-1", Hub: hub})
+	node2 := NewMemoryCluster(MemoryClusterConfig{NodeID: "node-2", Hub: hub})
+
+	received := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		node1.SubscribeContext(ctx, func(msg ClusterMessage) { received <- msg.Type })
+		close(done)
+	}()
+
+	// 等待订阅生效
+	for i := 0; i < 100 && len(received) == 0; i++ {
+		node2.Broadcast(ClusterMessage{Type: "ping"})
+		time.Sleep(time.Millisecond)
+	}
+	if len(received) == 0 {
+		t.Fatal("Expected subscriber to receive messages")
+	}
+
+	// 取消后 SubscribeContext 返回并移除回调
+	cancel()
+	<-done
+	for len(received) > 0 {
+		<-received
+	}
+	node2.Broadcast(ClusterMessage{Type: "ping"})
+	if len(received) != 0 {
+		t.Error("Expected no messages after the subscription is cancelled")
+	}
+}
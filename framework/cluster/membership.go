@@ -0,0 +1,78 @@
+package cluster
+
+import "sync"
+
+// Membership 集群成员变更回调，集群实现持有它来管理 OnNodeJoin 和 OnNodeLeave 回调
+type Membership struct {
+	mu          sync.Mutex
+	onJoin      []func(NodeInfo)
+	onLeave     []func(NodeInfo)
+	known       map[string]NodeInfo
+	initialized bool
+}
+
+// AddJoin 添加节点加入回调
+func (m *Membership) AddJoin(callback func(NodeInfo)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onJoin = append(m.onJoin, callback)
+}
+
+// AddLeave 添加节点离开回调
+func (m *Membership) AddLeave(callback func(NodeInfo)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onLeave = append(m.onLeave, callback)
+}
+
+// EmitJoin 触发节点加入回调
+func (m *Membership) EmitJoin(node NodeInfo) {
+	m.mu.Lock()
+	callbacks := append([]func(NodeInfo){}, m.onJoin...)
+	m.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(node)
+	}
+}
+
+// EmitLeave 触发节点离开回调
+func (m *Membership) EmitLeave(node NodeInfo) {
+	m.mu.Lock()
+	callbacks := append([]func(NodeInfo){}, m.onLeave...)
+	m.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(node)
+	}
+}
+
+// Sync 对比最新的节点列表并触发变更回调，首次调用只记录快照
+func (m *Membership) Sync(nodes []NodeInfo) {
+	current := make(map[string]NodeInfo, len(nodes))
+	for _, node := range nodes {
+		current[node.ID] = node
+	}
+
+	m.mu.Lock()
+	previous := m.known
+	initialized := m.initialized
+	m.known = current
+	m.initialized = true
+	m.mu.Unlock()
+
+	if !initialized {
+		return
+	}
+
+	for id, node := range current {
+		if _, exists := previous[id]; !exists {
+			m.EmitJoin(node)
+		}
+	}
+	for id, node := range previous {
+		if _, exists := current[id]; !exists {
+			m.EmitLeave(node)
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"laravel-go/framework/lock"
+)
+
+// MemoryClusterHub 内存集群共享状态，同一个 hub 上的 MemoryCluster 视为同一集群的成员
+type MemoryClusterHub struct {
+	mu      sync.Mutex
+	nodes   map[string]NodeInfo
+	locker  *lock.MemoryLocker
+	leader  string
+	members map[string]*MemoryCluster
+}
+
+// NewMemoryClusterHub 创建内存集群共享状态
+func NewMemoryClusterHub() *MemoryClusterHub {
+	return &MemoryClusterHub{
+		nodes:   make(map[string]NodeInfo),
+		locker:  lock.NewMemoryLocker(),
+		members: make(map[string]*MemoryCluster),
+	}
+}
+
+// MemoryCluster 内存集群实现，适用于单进程多节点场景和测试
+type MemoryCluster struct {
+	hub              *MemoryClusterHub
+	nodeID           string
+	electionInterval time.Duration
+	nodeTimeout      time.Duration
+	subscribers      []memorySubscriber
+	nextSubscriberID uint64
+	subMu            sync.RWMutex
+	membership       Membership
+	sweepOnce        sync.Once
+	stopChan         chan struct{}
+	stopOnce         sync.Once
+}
+
+// memorySubscriber 内存集群的消息订阅者
+type memorySubscriber struct {
+	id       uint64
+	callback func(ClusterMessage)
+}
+
+// MemoryClusterConfig 内存集群配置
+type MemoryClusterConfig struct {
+	NodeID           string
+	Hub              *MemoryClusterHub
+	ElectionInterval time.Duration
+	NodeTimeout      time.Duration
+}
+
+// NewMemoryCluster 创建内存集群
+func NewMemoryCluster(config MemoryClusterConfig) *MemoryCluster {
+	if config.Hub == nil {
+		config.Hub = NewMemoryClusterHub()
+	}
+	if config.ElectionInterval == 0 {
+		config.ElectionInterval = 5 * time.Second
+	}
+	if config.NodeTimeout == 0 {
+		config.NodeTimeout = 30 * time.Second
+	}
+
+	mc := &MemoryCluster{
+		hub:              config.Hub,
+		nodeID:           config.NodeID,
+		electionInterval: config.ElectionInterval,
+		nodeTimeout:      config.NodeTimeout,
+		stopChan:         make(chan struct{}),
+	}
+
+	mc.hub.mu.Lock()
+	mc.hub.members[mc.nodeID] = mc
+	mc.hub.mu.Unlock()
+
+	return mc
+}
+
+// Register 注册节点
+func (mc *MemoryCluster) Register(nodeID string, info NodeInfo) error {
+	mc.hub.mu.Lock()
+	_, exists := mc.hub.nodes[nodeID]
+	info.ID = nodeID
+	info.LastSeen = time.Now()
+	mc.hub.nodes[nodeID] = info
+	mc.hub.mu.Unlock()
+
+	// 心跳重复注册时不触发加入事件
+	if !exists {
+		mc.hub.notifyJoin(info)
+	}
+	return nil
+}
+
+// Unregister 注销节点
+func (mc *MemoryCluster) Unregister(nodeID string) error {
+	mc.hub.removeNode(nodeID)
+	return nil
+}
+
+// GetNodes 获取所有节点
+func (mc *MemoryCluster) GetNodes() ([]NodeInfo, error) {
+	mc.hub.expireNodes(mc.nodeTimeout)
+
+	mc.hub.mu.Lock()
+	defer mc.hub.mu.Unlock()
+
+	nodes := make([]NodeInfo, 0, len(mc.hub.nodes))
+	for _, node := range mc.hub.nodes {
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// AcquireLock 获取分布式锁
+func (mc *MemoryCluster) AcquireLock(key string, ttl time.Duration) (bool, error) {
+	return mc.hub.locker.TryLock(context.Background(), key, mc.nodeID, ttl)
+}
+
+// ReleaseLock 释放分布式锁
+func (mc *MemoryCluster) ReleaseLock(key string) error {
+	return mc.hub.locker.ForceUnlock(context.Background(), key)
+}
+
+// Locker 获取集群共享的锁驱动
+func (mc *MemoryCluster) Locker() lock.Locker {
+	return mc.hub.locker
+}
+
+// StartElection 启动选举
+func (mc *MemoryCluster) StartElection(callback func(bool)) error {
+	go mc.runElection(callback)
+	return nil
+}
+
+// StopElection 停止选举
+func (mc *MemoryCluster) StopElection() error {
+	mc.stopOnce.Do(func() {
+		close(mc.stopChan)
+	})
+
+	mc.hub.mu.Lock()
+	if mc.hub.leader == mc.nodeID {
+		mc.hub.leader = ""
+	}
+	mc.hub.mu.Unlock()
+	return nil
+}
+
+// runElection 运行选举
+func (mc *MemoryCluster) runElection(callback func(bool)) {
+	ticker := time.NewTicker(mc.electionInterval)
+	defer ticker.Stop()
+
+	callback(mc.tryBecomeLeader())
+	for {
+		select {
+		case <-ticker.C:
+			callback(mc.tryBecomeLeader())
+		case <-mc.stopChan:
+			return
+		}
+	}
+}
+
+// tryBecomeLeader 尝试成为领导者
+func (mc *MemoryCluster) tryBecomeLeader() bool {
+	mc.hub.mu.Lock()
+	defer mc.hub.mu.Unlock()
+
+	// 领导者已注销时允许重新选举
+	if _, exists := mc.hub.nodes[mc.hub.leader]; !exists {
+		mc.hub.leader = ""
+	}
+	if mc.hub.leader == "" {
+		mc.hub.leader = mc.nodeID
+	}
+
+	return mc.hub.leader == mc.nodeID
+}
+
+// Broadcast 广播消息
+func (mc *MemoryCluster) Broadcast(msg ClusterMessage) error {
+	mc.hub.mu.Lock()
+	members := make([]*MemoryCluster, 0, len(mc.hub.members))
+	for _, member := range mc.hub.members {
+		// 忽略自己发送的消息
+		if member.nodeID == msg.NodeID {
+			continue
+		}
+		members = append(members, member)
+	}
+	mc.hub.mu.Unlock()
+
+	for _, member := range members {
+		member.deliver(msg)
+	}
+	return nil
+}
+
+// Subscribe 订阅消息
+func (mc *MemoryCluster) Subscribe(callback func(ClusterMessage)) error {
+	mc.addSubscriber(callback)
+	return nil
+}
+
+// SubscribeContext 订阅消息直到 ctx 结束，返回前取消订阅
+func (mc *MemoryCluster) SubscribeContext(ctx context.Context, callback func(ClusterMessage)) error {
+	id := mc.addSubscriber(callback)
+	defer mc.removeSubscriber(id)
+
+	<-ctx.Done()
+	return nil
+}
+
+// addSubscriber 添加订阅者，返回订阅者ID
+func (mc *MemoryCluster) addSubscriber(callback func(ClusterMessage)) uint64 {
+	mc.subMu.Lock()
+	defer mc.subMu.Unlock()
+
+	mc.nextSubscriberID++
+	mc.subscribers = append(mc.subscribers, memorySubscriber{id: mc.nextSubscriberID, callback: callback})
+	return mc.nextSubscriberID
+}
+
+// removeSubscriber 移除订阅者
+func (mc *MemoryCluster) removeSubscriber(id uint64) {
+	mc.subMu.Lock()
+	defer mc.subMu.Unlock()
+
+	for i, subscriber := range mc.subscribers {
+		if subscriber.id == id {
+			mc.subscribers = append(mc.subscribers[:i:i], mc.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// deliver 投递消息给本节点的订阅者
+func (mc *MemoryCluster) deliver(msg ClusterMessage) {
+	mc.subMu.RLock()
+	subscribers := make([]memorySubscriber, len(mc.subscribers))
+	copy(subscribers, mc.subscribers)
+	mc.subMu.RUnlock()
+
+	for _, subscriber := range subscribers {
+		subscriber.callback(msg)
+	}
+}
+
+// OnNodeJoin 注册节点加入回调
+func (mc *MemoryCluster) OnNodeJoin(callback func(NodeInfo)) error {
+	mc.membership.AddJoin(callback)
+	return nil
+}
+
+// OnNodeLeave 注册节点离开回调
+func (mc *MemoryCluster) OnNodeLeave(callback func(NodeInfo)) error {
+	mc.membership.AddLeave(callback)
+	mc.sweepOnce.Do(func() {
+		go mc.sweepNodes()
+	})
+	return nil
+}
+
+// sweepNodes 定期清理心跳超时的节点
+func (mc *MemoryCluster) sweepNodes() {
+	ticker := time.NewTicker(mc.nodeTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			mc.hub.expireNodes(mc.nodeTimeout)
+		case <-mc.stopChan:
+			return
+		}
+	}
+}
+
+// removeNode 移除节点并通知成员
+func (h *MemoryClusterHub) removeNode(nodeID string) {
+	h.mu.Lock()
+	node, exists := h.nodes[nodeID]
+	delete(h.nodes, nodeID)
+	if h.leader == nodeID {
+		h.leader = ""
+	}
+	h.mu.Unlock()
+
+	if exists {
+		h.notifyLeave(node)
+	}
+}
+
+// expireNodes 移除超过 timeout 未心跳的节点
+func (h *MemoryClusterHub) expireNodes(timeout time.Duration) {
+	h.mu.Lock()
+	var expired []NodeInfo
+	for id, node := range h.nodes {
+		if time.Since(node.LastSeen) > timeout {
+			expired = append(expired, node)
+			delete(h.nodes, id)
+			if h.leader == id {
+				h.leader = ""
+			}
+		}
+	}
+	h.mu.Unlock()
+
+	for _, node := range expired {
+		h.notifyLeave(node)
+	}
+}
+
+// notifyJoin 通知所有成员节点加入
+func (h *MemoryClusterHub) notifyJoin(node NodeInfo) {
+	for _, member := range h.snapshotMembers() {
+		member.membership.EmitJoin(node)
+	}
+}
+
+// notifyLeave 通知所有成员节点离开
+func (h *MemoryClusterHub) notifyLeave(node NodeInfo) {
+	for _, member := range h.snapshotMembers() {
+		member.membership.EmitLeave(node)
+	}
+}
+
+// snapshotMembers 获取成员快照
+func (h *MemoryClusterHub) snapshotMembers() []*MemoryCluster {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	members := make([]*MemoryCluster, 0, len(h.members))
+	for _, member := range h.members {
+		members = append(members, member)
+	}
+	return members
+}
+
+// GetLeader 获取当前领导者
+func (mc *MemoryCluster) GetLeader() (string, error) {
+	mc.hub.mu.Lock()
+	defer mc.hub.mu.Unlock()
+	return mc.hub.leader, nil
+}
+
+// IsLeader 检查是否为领导者
+func (mc *MemoryCluster) IsLeader() bool {
+	leaderID, _ := mc.GetLeader()
+	return leaderID == mc.nodeID
+}
+
+// Close 关闭集群连接
+func (mc *MemoryCluster) Close() error {
+	mc.StopElection()
+
+	mc.hub.mu.Lock()
+	delete(mc.hub.members, mc.nodeID)
+	mc.hub.mu.Unlock()
+
+	mc.hub.removeNode(mc.nodeID)
+	return nil
+}
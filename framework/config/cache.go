@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CachePath config:cache 生成的编译配置文件的默认路径
+var CachePath = "storage/framework/config.json"
+
+// Cache 将当前所有配置写入一个编译后的 JSON 文件
+//
+// 写入的是已经合并了环境变量的配置，之后修改 .env 或配置文件需要重新生成。
+func (c *Config) Cache(path string) error {
+	data, err := json.MarshalIndent(c.All(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	// 先写入临时文件再重命名，避免应用读到写了一半的文件
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadCached 优先加载编译后的配置文件，不存在时加载配置目录
+//
+// 返回是否使用了编译后的配置文件。使用编译文件时不会再读取配置目录和用环境变量覆盖。
+func (c *Config) LoadCached(dir, cachePath string) (bool, error) {
+	data, err := os.ReadFile(cachePath)
+	if os.IsNotExist(err) {
+		return false, c.LoadDir(dir)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	values := make(map[string]interface{})
+	if err := decoder.Decode(&values); err != nil {
+		return false, fmt.Errorf("failed to decode cached config %s: %v", cachePath, err)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for namespace, value := range values {
+		c.data[namespace] = normalizeNumbers(value)
+	}
+	return true, nil
+}
+
+// normalizeNumbers 将 json.Number 转换为 int 或 float64
+func normalizeNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeNumbers(item)
+		}
+	}
+	return value
+}
+
+// ClearCache 删除编译后的配置文件
+func ClearCache(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -15,6 +15,11 @@ type Config struct {
 	data  map[string]interface{}
 	env   *Env
 	mutex sync.RWMutex
+	// 环境变量前缀别名，例如 database -> DB
+	envAliases map[string]string
+	// 已加载的配置文件，用于热重载
+	files     map[string]*configFile
+	watchStop chan struct{}
 }
 
 // Env 环境变量管理器
@@ -28,6 +33,11 @@ func NewConfig() *Config {
 	return &Config{
 		data: make(map[string]interface{}),
 		env:  &Env{},
+		envAliases: map[string]string{
+			"database": "DB",
+			"logging":  "LOG",
+		},
+		files: make(map[string]*configFile),
 	}
 }
 
@@ -65,12 +75,13 @@ func (c *Config) LoadEnv(filenames ...string) error {
 		}
 
 		// 解析键值对
+		line = strings.TrimPrefix(line, "export ")
 		if parts := strings.SplitN(line, "=", 2); len(parts) == 2 {
 			key := strings.TrimSpace(parts[0])
 			value := strings.TrimSpace(parts[1])
 
 			// 移除引号
-			if len(value) >= 2 && (value[0] == '"' && value[len(value)-1] == '"') {
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
 				value = value[1 : len(value)-1]
 			}
 
@@ -262,10 +273,7 @@ func (c *Config) LoadFromStruct(config interface{}) error {
 
 // getEnvValue 从环境变量获取值
 func (c *Config) getEnvValue(key string) interface{} {
-	// 将配置键转换为环境变量格式
-	envKey := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
-
-	if value := os.Getenv(envKey); value != "" {
+	if value := os.Getenv(c.envKey(key)); value != "" {
 		return value
 	}
 
@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -215,3 +217,146 @@ func TestValidate(t *testing.T) {
 		t.Fatalf("Validate() should not return error: %v", err)
 	}
 }
+
+func TestLoadFileEnvPrecedence(t *testing.T) {
+	dir := t.TempDir()
+
+	database := `{"host": "127.0.0.1", "port": 3306, "debug": false}`
+	if err := os.WriteFile(filepath.Join(dir, "database.json"), []byte(database), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cache := `# 缓存配置
+default: file
+stores:
+  redis:
+    host: "127.0.0.1"
+    port: 6379
+  tags: [a, b]
+`
+	if err := os.WriteFile(filepath.Join(dir, "cache.yaml"), []byte(cache), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	t.Setenv("DB_HOST", "db.internal")
+	t.Setenv("DB_PORT", "5432")
+	t.Setenv("CACHE_STORES_REDIS_PORT", "6380")
+
+	config := NewConfig()
+	if err := config.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir() should not return error: %v", err)
+	}
+
+	// 环境变量优先于配置文件
+	if host := config.GetString("database.host"); host != "db.internal" {
+		t.Fatalf("Expected 'db.internal', got '%s'", host)
+	}
+	if port := config.GetInt("database.port"); port != 5432 {
+		t.Fatalf("Expected 5432, got %d", port)
+	}
+	if debug := config.GetBool("database.debug", true); debug {
+		t.Fatal("Expected false, got true")
+	}
+
+	if driver := config.GetString("cache.default"); driver != "file" {
+		t.Fatalf("Expected 'file', got '%s'", driver)
+	}
+	if port := config.Get("cache.stores.redis.port"); port != 6380 {
+		t.Fatalf("Expected 6380, got %v", port)
+	}
+	if tags := config.GetStringSlice("cache.stores.tags"); len(tags) != 2 || tags[1] != "b" {
+		t.Fatalf("Expected [a b], got %v", tags)
+	}
+}
+
+func TestWatchReload(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.json")
+	if err := os.WriteFile(filename, []byte(`{"title": "before"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	config := NewConfig()
+	if err := config.LoadFile(filename); err != nil {
+		t.Fatalf("LoadFile() should not return error: %v", err)
+	}
+
+	reloaded := make(chan error, 1)
+	config.Watch(10*time.Millisecond, func(name string, err error) {
+		reloaded <- err
+	})
+	defer config.StopWatch()
+
+	if err := os.WriteFile(filename, []byte(`{"title": "after"}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	// 确保修改时间发生变化
+	future := time.Now().Add(time.Second)
+	os.Chtimes(filename, future, future)
+
+	select {
+	case err := <-reloaded:
+		if err != nil {
+			t.Fatalf("Reload should not return error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected reload callback")
+	}
+
+	if name := config.GetString("app.title"); name != "after" {
+		t.Fatalf("Expected 'after', got '%s'", name)
+	}
+}
+
+func TestConfigCache(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "database.json"), []byte(`{"host": "127.0.0.1", "port": 3306}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "site.yaml"), []byte("title: demo\ndebug: true\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	t.Setenv("DB_HOST", "db.internal")
+
+	source := NewConfig()
+	if err := source.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir() should not return error: %v", err)
+	}
+
+	cachePath := filepath.Join(t.TempDir(), "framework", "config.json")
+	if err := source.Cache(cachePath); err != nil {
+		t.Fatalf("Cache() should not return error: %v", err)
+	}
+
+	// 编译后的配置包含所有命名空间和环境变量覆盖，不再读取配置目录
+	os.WriteFile(filepath.Join(dir, "site.yaml"), []byte("title: changed\n"), 0644)
+	cached := NewConfig()
+	used, err := cached.LoadCached(dir, cachePath)
+	if err != nil || !used {
+		t.Fatalf("Expected cached config to be used, got %v, %v", used, err)
+	}
+	if host := cached.GetString("database.host"); host != "db.internal" {
+		t.Fatalf("Expected 'db.internal', got '%s'", host)
+	}
+	if port := cached.Get("database.port"); port != 3306 {
+		t.Fatalf("Expected 3306, got %#v", port)
+	}
+	if name := cached.GetString("site.title"); name != "demo" {
+		t.Fatalf("Expected 'demo', got '%s'", name)
+	}
+	if !cached.GetBool("site.debug") {
+		t.Fatal("Expected site.debug to be true")
+	}
+
+	// 清除后恢复按文件加载
+	if err := ClearCache(cachePath); err != nil {
+		t.Fatalf("ClearCache() should not return error: %v", err)
+	}
+	reloaded := NewConfig()
+	used, err = reloaded.LoadCached(dir, cachePath)
+	if err != nil || used {
+		t.Fatalf("Expected per-file loading, got %v, %v", used, err)
+	}
+	if name := reloaded.GetString("site.title"); name != "changed" {
+		t.Fatalf("Expected 'changed', got '%s'", name)
+	}
+}
@@ -0,0 +1,57 @@
+package config
+
+import "time"
+
+// defaultConfig 全局配置实例
+var defaultConfig = NewConfig()
+
+// Default 获取全局配置实例
+func Default() *Config {
+	return defaultConfig
+}
+
+// Load 加载 .env 文件和配置目录到全局配置
+// 通常在应用启动时调用：config.Load(".env", "config")
+// 存在 config:cache 生成的编译配置文件（CachePath）时只读取该文件
+func Load(envFile, dir string) error {
+	if err := defaultConfig.LoadEnv(envFile); err != nil {
+		return err
+	}
+	_, err := defaultConfig.LoadCached(dir, CachePath)
+	return err
+}
+
+// Watch 监听全局配置的文件变化
+func Watch(interval time.Duration, callback ReloadCallback) {
+	defaultConfig.Watch(interval, callback)
+}
+
+// Get 从全局配置获取值
+func Get(key string, defaultValue ...interface{}) interface{} {
+	return defaultConfig.Get(key, defaultValue...)
+}
+
+// Set 设置全局配置值
+func Set(key string, value interface{}) {
+	defaultConfig.Set(key, value)
+}
+
+// GetString 从全局配置获取字符串
+func GetString(key string, defaultValue ...string) string {
+	return defaultConfig.GetString(key, defaultValue...)
+}
+
+// GetInt 从全局配置获取整数
+func GetInt(key string, defaultValue ...int) int {
+	return defaultConfig.GetInt(key, defaultValue...)
+}
+
+// GetBool 从全局配置获取布尔值
+func GetBool(key string, defaultValue ...bool) bool {
+	return defaultConfig.GetBool(key, defaultValue...)
+}
+
+// GetFloat 从全局配置获取浮点数
+func GetFloat(key string, defaultValue ...float64) float64 {
+	return defaultConfig.GetFloat(key, defaultValue...)
+}
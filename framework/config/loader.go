@@ -0,0 +1,220 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// configFile 已加载的配置文件
+type configFile struct {
+	namespace string
+	modTime   time.Time
+}
+
+// ReloadCallback 配置文件重新加载回调，解析失败时 err 不为空且保留旧配置
+type ReloadCallback func(filename string, err error)
+
+// LoadFile 加载 JSON 或 YAML 配置文件
+//
+// 配置以文件名为命名空间，例如 config/database.json 中的 host 通过
+// database.host 读取。加载后会用环境变量覆盖同名配置，优先级为
+// Set > 环境变量 > 配置文件。
+func (c *Config) LoadFile(filename string) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	values, err := parseConfigFile(filename)
+	if err != nil {
+		return err
+	}
+
+	base := filepath.Base(filename)
+	namespace := strings.TrimSuffix(base, filepath.Ext(base))
+	c.overlayEnv(namespace, values)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.data[namespace] = values
+	c.files[filename] = &configFile{namespace: namespace, modTime: info.ModTime()}
+
+	return nil
+}
+
+// LoadDir 加载目录下所有 JSON 和 YAML 配置文件
+func (c *Config) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !isConfigFile(entry.Name()) {
+			continue
+		}
+		if err := c.LoadFile(filepath.Join(dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to load %s: %v", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// SetEnvAlias 设置命名空间的环境变量前缀
+// 默认 database 使用 DB 前缀（DB_HOST 覆盖 database.host），logging 使用 LOG 前缀
+func (c *Config) SetEnvAlias(namespace, prefix string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.envAliases[namespace] = prefix
+}
+
+// Watch 定时检查已加载的配置文件，文件修改后重新加载并调用回调
+// 重新加载会替换整个命名空间，通过 Set 设置的值会被覆盖
+func (c *Config) Watch(interval time.Duration, callback ReloadCallback) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	c.mutex.Lock()
+	if c.watchStop != nil {
+		close(c.watchStop)
+	}
+	stop := make(chan struct{})
+	c.watchStop = stop
+	c.mutex.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				for _, filename := range c.changedFiles() {
+					err := c.LoadFile(filename)
+					if callback != nil {
+						callback(filename, err)
+					}
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopWatch 停止监听配置文件
+func (c *Config) StopWatch() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.watchStop != nil {
+		close(c.watchStop)
+		c.watchStop = nil
+	}
+}
+
+// changedFiles 获取修改时间发生变化的配置文件
+// 同时记录新的修改时间，解析失败的文件不会在每次检查时重复回调
+func (c *Config) changedFiles() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var changed []string
+	for filename, file := range c.files {
+		info, err := os.Stat(filename)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(file.modTime) {
+			file.modTime = info.ModTime()
+			changed = append(changed, filename)
+		}
+	}
+	return changed
+}
+
+// envKey 将配置键转换为环境变量名，例如 database.host -> DB_HOST
+func (c *Config) envKey(key string) string {
+	keys := strings.SplitN(key, ".", 2)
+	if prefix, ok := c.envAliases[keys[0]]; ok {
+		keys[0] = prefix
+	}
+	return strings.ToUpper(strings.ReplaceAll(strings.Join(keys, "_"), ".", "_"))
+}
+
+// overlayEnv 用环境变量覆盖配置值，并按原值类型转换
+func (c *Config) overlayEnv(prefix string, values map[string]interface{}) {
+	for key, value := range values {
+		fullKey := prefix + "." + key
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			c.overlayEnv(fullKey, nested)
+			continue
+		}
+
+		c.mutex.RLock()
+		envKey := c.envKey(fullKey)
+		c.mutex.RUnlock()
+
+		if envValue, exists := os.LookupEnv(envKey); exists {
+			values[key] = convertEnvValue(envValue, value)
+		}
+	}
+}
+
+// convertEnvValue 将环境变量转换为与配置文件中相同的类型
+func convertEnvValue(envValue string, original interface{}) interface{} {
+	switch original.(type) {
+	case bool:
+		if boolValue, err := strconv.ParseBool(envValue); err == nil {
+			return boolValue
+		}
+	case int:
+		if intValue, err := strconv.Atoi(envValue); err == nil {
+			return intValue
+		}
+	case float64:
+		if floatValue, err := strconv.ParseFloat(envValue, 64); err == nil {
+			return floatValue
+		}
+	}
+	return envValue
+}
+
+// parseConfigFile 按扩展名解析配置文件
+func parseConfigFile(filename string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		values := make(map[string]interface{})
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+		return values, nil
+	case ".yaml", ".yml":
+		return parseYAML(data)
+	}
+
+	return nil, fmt.Errorf("unsupported config format: %s", filename)
+}
+
+// isConfigFile 检查是否为支持的配置文件
+func isConfigFile(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
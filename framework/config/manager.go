@@ -273,7 +273,7 @@ func (cm *ConfigManager) setNestedValue(data interface{}, key string, value inte
 	}
 
 	current := cm.configs
-	for i, k := range keys[:len(keys)-1] {
+	for _, k := range keys[:len(keys)-1] {
 		if _, exists := current[k]; !exists {
 			current[k] = make(map[string]interface{})
 		}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML 解析 YAML 配置，顶层必须是映射
+func parseYAML(data []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return normalizeYAMLMap(values), nil
+}
+
+// normalizeYAMLMap 将 YAML 解析出的嵌套映射统一为 map[string]interface{}，与 JSON 配置的结构一致
+func normalizeYAMLMap(values map[string]interface{}) map[string]interface{} {
+	for key, value := range values {
+		values[key] = normalizeYAMLValue(value)
+	}
+	return values
+}
+
+// normalizeYAMLValue 转换 YAML 值，非字符串键的映射转换为字符串键
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return normalizeYAMLMap(v)
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = normalizeYAMLValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAMLValue(item)
+		}
+		return v
+	}
+	return value
+}
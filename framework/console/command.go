@@ -1,12 +1,17 @@
 package console
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"sort"
+	"strconv"
 	"strings"
-	"text/tabwriter"
+	"sync"
+	"syscall"
+	"time"
 )
 
 // Command 命令接口
@@ -110,6 +115,45 @@ func (app *Application) GetCommands() map[string]Command {
 	return app.commands
 }
 
+// CommandFactory 创建命令的工厂函数
+type CommandFactory func() Command
+
+var (
+	registeredCommands []CommandFactory
+	registryMutex      sync.Mutex
+)
+
+// RegisterCommand 注册命令，通常在命令所在包的 init 中调用
+func RegisterCommand(factory CommandFactory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registeredCommands = append(registeredCommands, factory)
+}
+
+// RegisteredCommands 创建所有已注册的命令
+func RegisteredCommands() []Command {
+	registryMutex.Lock()
+	factories := make([]CommandFactory, len(registeredCommands))
+	copy(factories, registeredCommands)
+	registryMutex.Unlock()
+
+	commands := make([]Command, 0, len(factories))
+	for _, factory := range factories {
+		commands = append(commands, factory())
+	}
+	return commands
+}
+
+// DiscoverCommands 添加所有通过 RegisterCommand 注册的命令
+//
+// 命令包需要被匿名导入，例如 import _ "your-app/app/console/commands"，
+// 这样包中的 init 才会执行。
+func (app *Application) DiscoverCommands() {
+	for _, command := range RegisteredCommands() {
+		app.AddCommand(command)
+	}
+}
+
 // SetInteractive 设置交互模式
 func (app *Application) SetInteractive(interactive bool) {
 	app.interactive = interactive
@@ -140,15 +184,112 @@ func (app *Application) Run(args []string) error {
 		return app.showHelp()
 	}
 
+	// 解析 --timeout，命令自己定义了 timeout 选项时由命令处理
+	timeout, args, err := extractTimeout(args[1:], command)
+	if err != nil {
+		app.output.Error(fmt.Sprintf("Error parsing input: %v", err))
+		return err
+	}
+
 	// 解析输入
-	input, err := app.parseInput(args[1:], command)
+	input, err := app.parseInput(args, command)
 	if err != nil {
 		app.output.Error(fmt.Sprintf("Error parsing input: %v", err))
 		return err
 	}
 
 	// 执行命令
-	return command.Execute(input)
+	return app.execute(command, input, timeout)
+}
+
+// execute 执行命令，命令的 context 在收到 SIGINT、SIGTERM 或超时后取消
+func (app *Application) execute(command Command, input Input, timeout time.Duration) error {
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 第一次中断后恢复默认的信号处理，不响应 context 的命令可以再按一次 Ctrl+C 强制退出
+	go func() {
+		<-signalCtx.Done()
+		stop()
+	}()
+
+	ctx := signalCtx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if consoleInput, ok := input.(*ConsoleInput); ok {
+		consoleInput.ctx = ctx
+	}
+
+	start := time.Now()
+	err := command.Execute(input)
+
+	// 报告中断，命令自己负责输出已完成的进度
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		app.output.Warning(fmt.Sprintf("Command '%s' timed out after %s", command.GetName(), time.Since(start).Round(time.Millisecond)))
+	case context.Canceled:
+		app.output.Warning(fmt.Sprintf("Command '%s' interrupted after %s", command.GetName(), time.Since(start).Round(time.Millisecond)))
+	}
+	return err
+}
+
+// extractTimeout 从参数中取出 --timeout，支持 30s、1m 等时长或秒数
+func extractTimeout(args []string, command Command) (time.Duration, []string, error) {
+	for _, opt := range command.GetOptions() {
+		if opt.Name == "timeout" {
+			return 0, args, nil
+		}
+	}
+
+	var timeout time.Duration
+	remaining := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var value string
+		switch {
+		case strings.HasPrefix(arg, "--timeout="):
+			value = strings.TrimPrefix(arg, "--timeout=")
+		case arg == "--timeout" && i+1 < len(args):
+			i++
+			value = args[i]
+		default:
+			remaining = append(remaining, arg)
+			continue
+		}
+
+		duration, err := parseTimeout(value)
+		if err != nil {
+			return 0, nil, err
+		}
+		timeout = duration
+	}
+	return timeout, remaining, nil
+}
+
+// parseTimeout 解析超时时间，纯数字按秒处理
+func parseTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q", value)
+	}
+	return duration, nil
+}
+
+// InputContext 获取命令的 context，输入不支持 context 时返回 context.Background()
+//
+// 长时间运行的命令应在处理过程中检查 ctx.Done()，在中断或超时后尽快退出。
+func InputContext(input Input) context.Context {
+	if provider, ok := input.(interface{ Context() context.Context }); ok {
+		return provider.Context()
+	}
+	return context.Background()
 }
 
 // showHelp 显示帮助信息
@@ -343,6 +484,15 @@ func isBoolOption(name string, options []Option) bool {
 type ConsoleInput struct {
 	arguments map[string]interface{}
 	options   map[string]interface{}
+	ctx       context.Context
+}
+
+// Context 获取命令的 context，收到中断信号或超时后取消
+func (input *ConsoleInput) Context() context.Context {
+	if input.ctx == nil {
+		return context.Background()
+	}
+	return input.ctx
 }
 
 // GetArgument 获取参数值
@@ -370,70 +520,3 @@ func (input *ConsoleInput) GetArguments() map[string]interface{} {
 func (input *ConsoleInput) GetOptions() map[string]interface{} {
 	return input.options
 }
-
-// ConsoleOutput 控制台输出实现
-type ConsoleOutput struct {
-	writer *tabwriter.Writer
-}
-
-// NewConsoleOutput 创建新的控制台输出
-func NewConsoleOutput() *ConsoleOutput {
-	return &ConsoleOutput{
-		writer: tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0),
-	}
-}
-
-// Write 写入内容
-func (output *ConsoleOutput) Write(content string) {
-	fmt.Print(content)
-}
-
-// WriteLine 写入一行
-func (output *ConsoleOutput) WriteLine(content string) {
-	fmt.Println(content)
-}
-
-// Error 输出错误信息
-func (output *ConsoleOutput) Error(message string) {
-	fmt.Fprintf(os.Stderr, "\033[31m%s\033[0m\n", message)
-}
-
-// Success 输出成功信息
-func (output *ConsoleOutput) Success(message string) {
-	fmt.Printf("\033[32m%s\033[0m\n", message)
-}
-
-// Warning 输出警告信息
-func (output *ConsoleOutput) Warning(message string) {
-	fmt.Printf("\033[33m%s\033[0m\n", message)
-}
-
-// Info 输出信息
-func (output *ConsoleOutput) Info(message string) {
-	fmt.Printf("\033[34m%s\033[0m\n", message)
-}
-
-// Table 输出表格
-func (output *ConsoleOutput) Table(headers []string, rows [][]string) {
-	// 写入表头
-	for _, header := range headers {
-		fmt.Fprintf(output.writer, "%s\t", header)
-	}
-	fmt.Fprintln(output.writer)
-
-	// 写入分隔线
-	for range headers {
-		fmt.Fprintf(output.writer, "----\t")
-	}
-	fmt.Fprintln(output.writer)
-
-	// 写入数据行
-	for _, row := range rows {
-		for _, cell := range row {
-			fmt.Fprintf(output.writer, "%s\t", cell)
-		}
-		fmt.Fprintln(output.writer)
-	}
-
-	output.writer.Flush()
-}
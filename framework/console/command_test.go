@@ -1,8 +1,29 @@
 package console
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"time"
+
+	"laravel-go/framework/cache"
+	"laravel-go/framework/container"
+	"laravel-go/framework/database"
+	"laravel-go/framework/queue"
+	"laravel-go/framework/scheduler"
 )
 
 func TestNewApplication(t *testing.T) {
@@ -170,13 +191,16 @@ func TestMakeModelCommand(t *testing.T) {
 
 	// 验证选项
 	opts := cmd.GetOptions()
-	if len(opts) != 1 {
-		t.Errorf("Expected 1 option, got %d", len(opts))
+	if len(opts) != 2 {
+		t.Fatalf("Expected 2 options, got %d", len(opts))
 	}
 
 	if opts[0].Name != "fields" {
 		t.Errorf("Expected option name 'fields', got %s", opts[0].Name)
 	}
+	if opts[1].Name != "from-table" {
+		t.Errorf("Expected option name 'from-table', got %s", opts[1].Name)
+	}
 }
 
 func TestMakeMiddlewareCommand(t *testing.T) {
@@ -437,6 +461,56 @@ func TestGeneratorGenerateModel(t *testing.T) {
 	}
 }
 
+func TestGeneratorGenerateModelFromTable(t *testing.T) {
+	tempDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tempDir)
+	defer os.Chdir(originalDir)
+
+	conn, err := database.NewConnection(&database.ConnectionConfig{Driver: database.SQLite, Database: filepath.Join(tempDir, "app.db")})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, email VARCHAR(255) NOT NULL, age INT, verified_at DATETIME, created_at DATETIME, updated_at DATETIME)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	columns, err := database.Introspect(conn, "users")
+	if err != nil {
+		t.Fatalf("Failed to introspect table: %v", err)
+	}
+
+	generator := NewGenerator(NewConsoleOutput())
+	if err := generator.GenerateModelFromTable("user", "users", columns); err != nil {
+		t.Fatalf("GenerateModelFromTable should not return error: %v", err)
+	}
+
+	model, err := os.ReadFile("app/models/user.go")
+	if err != nil {
+		t.Fatalf("Model file should be created: %v", err)
+	}
+	for _, field := range []string{"Email string `db:\"email\" json:\"email\"`", "Age int64 `db:\"age\" json:\"age\"`", "VerifiedAt *time.Time", `return "users"`} {
+		if !strings.Contains(string(model), field) {
+			t.Errorf("Model should contain %q:\n%s", field, model)
+		}
+	}
+	if strings.Contains(string(model), "CreatedAt") {
+		t.Errorf("Model should not repeat columns from database.Model:\n%s", model)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "user.go", model, 0); err != nil {
+		t.Errorf("Generated model should be valid Go: %v", err)
+	}
+
+	resource, err := os.ReadFile("app/resources/user_resource.go")
+	if err != nil {
+		t.Fatalf("Resource file should be created: %v", err)
+	}
+	if !strings.Contains(string(resource), `"email": m.Email`) {
+		t.Errorf("Resource should map introspected columns:\n%s", resource)
+	}
+}
+
 func TestGeneratorGenerateMiddleware(t *testing.T) {
 	// 创建临时目录
 	tempDir := t.TempDir()
@@ -510,3 +584,628 @@ func TestGeneratorGenerateTest(t *testing.T) {
 		t.Errorf("Test file should be created: %s", filePath)
 	}
 }
+
+// runGeneratedGatewayHelpers 生成网关代码，取出其中的常量、变量和指定的函数与类型（连同方法），
+// 与 body 组成独立程序运行，返回程序输出的各行
+func runGeneratedGatewayHelpers(t *testing.T, imports []string, body string, names ...string) []string {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	cmd := NewInitCommand(NewConsoleOutput())
+	if err := cmd.generateAPIGatewayFiles(&ProjectConfig{}, tempDir); err != nil {
+		t.Fatalf("generateAPIGatewayFiles should not return error: %v", err)
+	}
+
+	source, err := os.ReadFile(filepath.Join(tempDir, "gateway", "main.go"))
+	if err != nil {
+		t.Fatalf("Gateway main.go should be created: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", source, 0)
+	if err != nil {
+		t.Fatalf("Generated gateway should be valid Go: %v", err)
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var helpers bytes.Buffer
+	found := make(map[string]bool)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			name := decl.Name.Name
+			if decl.Recv != nil {
+				receiver := decl.Recv.List[0].Type
+				if star, ok := receiver.(*ast.StarExpr); ok {
+					receiver = star.X
+				}
+				name = receiver.(*ast.Ident).Name
+			}
+			if !wanted[name] {
+				continue
+			}
+			found[name] = true
+		case *ast.GenDecl:
+			switch decl.Tok {
+			case token.CONST, token.VAR:
+			case token.TYPE:
+				name := decl.Specs[0].(*ast.TypeSpec).Name.Name
+				if !wanted[name] {
+					continue
+				}
+				found[name] = true
+			default:
+				continue
+			}
+		default:
+			continue
+		}
+		printer.Fprint(&helpers, fset, decl)
+		helpers.WriteString("\n\n")
+	}
+	for _, name := range names {
+		if !found[name] {
+			t.Fatalf("Generated gateway should define %s", name)
+		}
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+	program := "package main\n\nimport (\n"
+	for _, path := range imports {
+		program += "\t" + strconv.Quote(path) + "\n"
+	}
+	program += ")\n\n" + helpers.String() + `
+func main() {
+` + body + `
+}
+`
+	programPath := filepath.Join(tempDir, "helpers.go")
+	if err := os.WriteFile(programPath, []byte(program), 0644); err != nil {
+		t.Fatalf("Failed to write program: %v", err)
+	}
+
+	run := exec.Command(goBin, "run", programPath)
+	run.Dir = tempDir
+	run.Env = append(os.Environ(), "GOWORK=off", "GOFLAGS=")
+	out, err := run.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to run generated helpers: %v\n%s", err, out)
+	}
+	return strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+}
+
+func TestGenerateAPIGatewayParsesUserID(t *testing.T) {
+	lines := runGeneratedGatewayHelpers(t, []string{"encoding/json", "fmt", "net/http", "net/http/httptest", "strconv"}, `
+	for _, value := range []string{"42", "abc", "0", "-5"} {
+		recorder := httptest.NewRecorder()
+		id, ok := parseUserID(recorder, value)
+		fmt.Printf("%s %d %t %d %s\n", value, id, ok, recorder.Code, recorder.Header().Get("Content-Type"))
+	}`, "parseUserID", "writeJSONError")
+
+	expected := []string{
+		"42 42 true 200 ",
+		"abc 0 false 400 application/json",
+		"0 0 false 400 application/json",
+		"-5 0 false 400 application/json",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d results, got %q", len(expected), lines)
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], line)
+		}
+	}
+}
+
+func TestGenerateAPIGatewayParsesPagination(t *testing.T) {
+	lines := runGeneratedGatewayHelpers(t, []string{"encoding/json", "fmt", "net/http", "net/http/httptest", "net/url", "strconv"}, `
+	for _, query := range []string{"", "page=3&page_size=20", "page_size=500", "page=abc", "page_size=0"} {
+		values, _ := url.ParseQuery(query)
+		recorder := httptest.NewRecorder()
+		page, pageSize, ok := parsePagination(recorder, values)
+		fmt.Printf("%q %d %d %t %d\n", query, page, pageSize, ok, recorder.Code)
+	}`, "parsePagination", "writeJSONError")
+
+	expected := []string{
+		`"" 1 10 true 200`,
+		`"page=3&page_size=20" 3 20 true 200`,
+		`"page_size=500" 1 100 true 200`,
+		`"page=abc" 0 0 false 400`,
+		`"page_size=0" 0 0 false 400`,
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d results, got %q", len(expected), lines)
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], line)
+		}
+	}
+}
+
+func TestGenerateAPIGatewayLogsRequests(t *testing.T) {
+	lines := runGeneratedGatewayHelpers(t, []string{"bytes", "encoding/json", "fmt", "io", "log", "net/http", "net/http/httptest", "strconv", "strings", "time"}, `
+	for _, logBodies := range []bool{true, false} {
+		var out bytes.Buffer
+		handler := newLoggingMiddleware(log.New(&out, "", 0), logBodies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("{\"id\":1,\"access_token\":\"t-123\",\"received\":" + strconv.Itoa(len(body)) + "}"))
+		}))
+
+		request := httptest.NewRequest("POST", "/api/v1/users", strings.NewReader("{\"name\":\"alice\",\"password\":\"secret123\"}"))
+		request.Header.Set("X-Request-ID", "req-1")
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		fmt.Print(out.String())
+	}`, "newLoggingMiddleware", "loggingResponseWriter", "replayBody", "redactBody", "redactValue", "isSensitiveField")
+
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %q", lines)
+	}
+	for i, line := range lines {
+		if strings.Contains(line, "secret123") || strings.Contains(line, "t-123") {
+			t.Errorf("Sensitive fields should be redacted: %s", line)
+		}
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Log line should be JSON: %v: %s", err, line)
+		}
+		body := `{"id":1,"access_token":"t-123","received":39}`
+		if entry["status"] != float64(201) || entry["size"] != float64(len(body)) {
+			t.Errorf("Expected status 201 and size %d, got %v and %v", len(body), entry["status"], entry["size"])
+		}
+		if entry["request_id"] != "req-1" || entry["method"] != "POST" || entry["path"] != "/api/v1/users" {
+			t.Errorf("Expected request details to be logged, got %v", entry)
+		}
+
+		if i == 1 {
+			if _, exists := entry["request_body"]; exists {
+				t.Errorf("Bodies should only be logged in debug mode, got %v", entry)
+			}
+			continue
+		}
+		request, _ := entry["request_body"].(map[string]interface{})
+		if request["password"] != "[REDACTED]" || request["name"] != "alice" {
+			t.Errorf("Expected password to be masked in request body, got %v", entry["request_body"])
+		}
+		response, _ := entry["response_body"].(map[string]interface{})
+		if response["access_token"] != "[REDACTED]" || response["received"] != float64(39) {
+			t.Errorf("Expected token masked and request body restored for handler, got %v", entry["response_body"])
+		}
+	}
+}
+
+func TestGenerateAPIGatewayLogsLargeBodies(t *testing.T) {
+	lines := runGeneratedGatewayHelpers(t, []string{"bytes", "encoding/json", "fmt", "io", "log", "net/http", "net/http/httptest", "strconv", "strings", "time"}, `
+	for _, knownLength := range []bool{true, false} {
+		var out bytes.Buffer
+		handler := newLoggingMiddleware(log.New(&out, "", 0), true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			// 分块写入大于记录上限的响应
+			chunk := []byte(strings.Repeat("b", 3000))
+			for i := 0; i < 4; i++ {
+				w.Write(chunk)
+			}
+			fmt.Println(len(body), w.(*loggingResponseWriter).body.Len())
+		}))
+
+		request := httptest.NewRequest("POST", "/api/v1/users", strings.NewReader(strings.Repeat("a", 10000)))
+		if !knownLength {
+			request.ContentLength = -1
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+		fmt.Print(out.String())
+	}`, "newLoggingMiddleware", "loggingResponseWriter", "replayBody", "redactBody", "redactValue", "isSensitiveField")
+
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 output lines, got %q", lines)
+	}
+	for i, requestBody := range []string{"[body omitted: 10000 bytes]", "[body omitted: more than 4096 bytes]"} {
+		// 处理器读到完整的请求体，响应体最多保存记录上限的字节数
+		if lines[i*2] != "10000 4096" {
+			t.Errorf("Expected full request body and capped response capture, got %q", lines[i*2])
+		}
+
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[i*2+1]), &entry); err != nil {
+			t.Fatalf("Log line should be JSON: %v: %s", err, lines[i*2+1])
+		}
+		if entry["request_body"] != requestBody {
+			t.Errorf("Expected request body %q, got %v", requestBody, entry["request_body"])
+		}
+		if entry["response_body"] != "[body omitted: 12000 bytes]" || entry["size"] != float64(12000) {
+			t.Errorf("Expected response body omitted with its real size, got %v and %v", entry["response_body"], entry["size"])
+		}
+	}
+}
+
+func TestGenerateAPIGatewayRequiresAuth(t *testing.T) {
+	lines := runGeneratedGatewayHelpers(t, []string{"context", "encoding/json", "errors", "fmt", "net/http", "net/http/httptest", "strings"}, `
+	validate := func(token string) (string, error) {
+		if token != "valid" {
+			return "", errors.New("invalid")
+		}
+		return "42", nil
+	}
+	handler := newAuthMiddleware(validate, "/health")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := r.Context().Value(userIDKey{}).(string)
+		fmt.Fprint(w, userID)
+	}))
+
+	for _, request := range []struct{ path, header string }{
+		{"/health", ""},
+		{"/api/v1/users", ""},
+		{"/api/v1/users/1", "Bearer invalid"},
+		{"/api/v1/users/1", "Bearer valid"},
+	} {
+		req := httptest.NewRequest("GET", request.path, nil)
+		if request.header != "" {
+			req.Header.Set("Authorization", request.header)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		fmt.Printf("%s %d %q\n", request.path, recorder.Code, strings.TrimSpace(recorder.Body.String()))
+	}`, "newAuthMiddleware", "userIDKey", "writeJSONError")
+
+	expected := []string{
+		`/health 200 ""`,
+		`/api/v1/users 401 "{\"error\":\"missing bearer token\",\"status\":401}"`,
+		`/api/v1/users/1 401 "{\"error\":\"invalid token\",\"status\":401}"`,
+		`/api/v1/users/1 200 "42"`,
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d results, got %q", len(expected), lines)
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("Expected %q, got %q", expected[i], line)
+		}
+	}
+}
+
+func TestScheduleRunCommand(t *testing.T) {
+	s := scheduler.NewScheduler(scheduler.NewMemoryStore())
+
+	var dueRuns, laterRuns int32
+	// 新建任务的下次运行时间在未来，到期按调度表达式是否匹配当前这一分钟判断
+	due := scheduler.NewTask("due", "Due task", "0 * * * * *", scheduler.NewFuncHandler("due", func(ctx context.Context) error {
+		atomic.AddInt32(&dueRuns, 1)
+		return nil
+	}))
+
+	laterMinute := (time.Now().Minute() + 30) % 60
+	later := scheduler.NewTask("later", "Later task", fmt.Sprintf("0 %d * * * *", laterMinute), scheduler.NewFuncHandler("later", func(ctx context.Context) error {
+		atomic.AddInt32(&laterRuns, 1)
+		return nil
+	}))
+
+	for _, task := range []*scheduler.DefaultTask{due, later} {
+		if err := s.Add(task); err != nil {
+			t.Fatalf("Failed to add task: %v", err)
+		}
+	}
+
+	cmd := NewScheduleRunCommand(s, NewConsoleOutput())
+	if cmd.GetName() != "schedule:run" {
+		t.Errorf("Expected command name 'schedule:run', got %s", cmd.GetName())
+	}
+	if err := cmd.Execute(&ConsoleInput{}); err != nil {
+		t.Fatalf("Failed to run schedule: %v", err)
+	}
+
+	if atomic.LoadInt32(&dueRuns) != 1 {
+		t.Errorf("Expected due task to run once, got %d", dueRuns)
+	}
+	if atomic.LoadInt32(&laterRuns) != 0 {
+		t.Errorf("Expected later task to be skipped, got %d runs", laterRuns)
+	}
+	if !due.GetNextRunAt().After(time.Now()) {
+		t.Error("Expected due task's next run to move forward")
+	}
+}
+
+// bufferOutput 把输出写入缓冲区的 Output 实现
+type bufferOutput struct {
+	bytes.Buffer
+}
+
+func (o *bufferOutput) Write(content string)     { o.WriteString(content) }
+func (o *bufferOutput) WriteLine(content string) { o.WriteString(content + "\n") }
+func (o *bufferOutput) Error(message string)     { o.WriteString("error: " + message + "\n") }
+func (o *bufferOutput) Success(message string)   { o.WriteString(message + "\n") }
+func (o *bufferOutput) Warning(message string)   { o.WriteString(message + "\n") }
+func (o *bufferOutput) Info(message string)      { o.WriteString(message + "\n") }
+func (o *bufferOutput) Table(headers []string, rows [][]string) {
+	for _, row := range rows {
+		o.WriteString(strings.Join(row, " ") + "\n")
+	}
+}
+
+func TestTinkerCommand(t *testing.T) {
+	type userRepository struct{ names []string }
+
+	c := container.NewContainer()
+	users := &userRepository{names: []string{"alice", "bob"}}
+	c.BindCallback((*userRepository)(nil), func(container.Container) interface{} {
+		return users
+	})
+
+	output := &bufferOutput{}
+	cmd := NewTinkerCommand(c, output)
+	cmd.Register("users.all", "List all users", func(c container.Container, args []string) (interface{}, error) {
+		return c.Make((*userRepository)(nil)).(*userRepository).names, nil
+	})
+	cmd.Register("echo", "Echo arguments", func(c container.Container, args []string) (interface{}, error) {
+		return strings.Join(args, " "), nil
+	})
+	cmd.SetInput(strings.NewReader("users.all\necho hello tinker\n!!\nmissing\nhistory\nexit\necho unreachable\n"))
+
+	if err := cmd.Execute(&ConsoleInput{}); err != nil {
+		t.Fatalf("Failed to run tinker: %v", err)
+	}
+
+	result := output.String()
+	for _, expected := range []string{
+		"[\n  \"alice\",\n  \"bob\"\n]",
+		"hello tinker\n",
+		"error: Unknown action: missing",
+		"   3  echo hello tinker",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, result)
+		}
+	}
+	if strings.Contains(result, "unreachable") {
+		t.Error("Expected tinker to stop at exit")
+	}
+	if len(cmd.History()) != 5 {
+		t.Errorf("Expected 5 history entries, got %v", cmd.History())
+	}
+}
+
+func TestQueueWorkCommand(t *testing.T) {
+	manager := queue.NewManager()
+	manager.Extend("emails", queue.NewMemoryQueue())
+	store := cache.NewMemoryStore()
+
+	cmd := NewQueueWorkCommand(manager, store, NewConsoleOutput())
+	cmd.pollInterval = 10 * time.Millisecond
+
+	// 解析选项
+	app := NewApplication("test-app", "1.0.0")
+	input, err := app.parseInput([]string{"--queue=emails", "--tries=5", "--timeout=2"}, cmd)
+	if err != nil {
+		t.Fatalf("Failed to parse input: %v", err)
+	}
+	if input.GetOption("queue") != "emails" || input.GetOption("tries") != 5 || input.GetOption("timeout") != 2 {
+		t.Errorf("Unexpected options: %v", input.GetOptions())
+	}
+	if input.GetOption("sleep") != 3 {
+		t.Errorf("Expected default sleep 3, got %v", input.GetOption("sleep"))
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Execute(input)
+	}()
+
+	// queue:restart 发出的信号使工作进程退出
+	time.Sleep(50 * time.Millisecond)
+	if err := NewQueueRestartCommand(store, NewConsoleOutput()).Execute(&ConsoleInput{}); err != nil {
+		t.Fatalf("Failed to broadcast restart: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected worker to stop cleanly, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected worker to observe the restart signal")
+	}
+}
+
+func TestMakeCommandAndDiscovery(t *testing.T) {
+	tempDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	os.Chdir(tempDir)
+	defer os.Chdir(originalDir)
+
+	generator := NewGenerator(NewConsoleOutput())
+	app := NewApplication("test-app", "1.0.0")
+	app.AddCommand(NewMakeCommandCommand(generator))
+
+	if err := app.Run([]string{"make:command", "send_emails"}); err != nil {
+		t.Fatalf("Failed to run make:command: %v", err)
+	}
+
+	filePath := filepath.Join("app", "console", "commands", "send_emails_command.go")
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Expected command file to be created: %v", err)
+	}
+
+	// 生成的文件必须是合法的 Go 代码，并实现 Command 接口
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, content, 0)
+	if err != nil {
+		t.Fatalf("Generated command is not valid Go: %v", err)
+	}
+	if file.Name.Name != "commands" {
+		t.Errorf("Expected package commands, got %s", file.Name.Name)
+	}
+
+	methods := make(map[string]bool)
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+			methods[fn.Name.Name] = true
+		}
+	}
+	for _, name := range []string{"GetName", "GetDescription", "GetSignature", "GetArguments", "GetOptions", "Execute", "Handle"} {
+		if !methods[name] {
+			t.Errorf("Expected generated command to define %s", name)
+		}
+	}
+
+	for _, want := range []string{
+		"type SendEmailsCommand struct",
+		`return "app:send-emails"`,
+		"func (cmd *SendEmailsCommand) Execute(input console.Input) error",
+		"console.RegisterCommand(func() console.Command",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected generated command to contain %q", want)
+		}
+	}
+
+	// 通过 init 注册的命令会被 DiscoverCommands 添加
+	RegisterCommand(func() Command {
+		return NewClearCacheCommand(NewConsoleOutput())
+	})
+	discovered := NewApplication("test-app", "1.0.0")
+	discovered.DiscoverCommands()
+	if _, exists := discovered.GetCommand("cache:clear"); !exists {
+		t.Error("Expected discovered application to contain cache:clear")
+	}
+}
+
+func TestConsoleOutputColorAndProgress(t *testing.T) {
+	var out, errOut bytes.Buffer
+	output := NewConsoleOutputWriter(&out, &errOut)
+
+	// 输出不是终端时不带颜色
+	output.Success("done")
+	output.Error("failed")
+	if out.String() != "done\n" || errOut.String() != "failed\n" {
+		t.Errorf("Expected plain output, got %q and %q", out.String(), errOut.String())
+	}
+
+	output.SetColor(true)
+	out.Reset()
+	output.Info("colored")
+	if out.String() != "\033[34mcolored\033[0m\n" {
+		t.Errorf("Expected colored output, got %q", out.String())
+	}
+	output.SetColor(false)
+
+	// 进度条到达 100%
+	out.Reset()
+	bar := output.ProgressBar(4)
+	for i := 0; i < 3; i++ {
+		bar.Advance()
+	}
+	if bar.Percent() != 75 {
+		t.Errorf("Expected 75%%, got %d%%", bar.Percent())
+	}
+	bar.Finish()
+	if bar.Current() != 4 || bar.Percent() != 100 {
+		t.Errorf("Expected progress bar to finish, got %d (%d%%)", bar.Current(), bar.Percent())
+	}
+	if strings.Contains(out.String(), "\r") {
+		t.Errorf("Expected no carriage returns when not a TTY, got %q", out.String())
+	}
+	if !strings.HasSuffix(out.String(), "4/4 [============================] 100%\n") {
+		t.Errorf("Unexpected progress output: %q", out.String())
+	}
+
+	// 表格按列对齐，超出宽度时截断
+	out.Reset()
+	output.SetWidth(20)
+	output.Table([]string{"Name", "Description"}, [][]string{
+		{"migrate", "Run the database migrations"},
+		{"名称", "ok"},
+	})
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 table lines, got %q", out.String())
+	}
+	if lines[0] != "Name     Description" {
+		t.Errorf("Unexpected header line: %q", lines[0])
+	}
+	if lines[2] != "migrate  Run the da…" {
+		t.Errorf("Expected truncated row, got %q", lines[2])
+	}
+	if lines[3] != "名称     ok" {
+		t.Errorf("Expected wide characters to be aligned, got %q", lines[3])
+	}
+}
+
+// contextCommand 等待 context 取消的测试命令
+type contextCommand struct {
+	started chan struct{}
+	err     error
+}
+
+func (cmd *contextCommand) GetName() string          { return "long:run" }
+func (cmd *contextCommand) GetDescription() string   { return "Long running command" }
+func (cmd *contextCommand) GetSignature() string     { return "long:run" }
+func (cmd *contextCommand) GetArguments() []Argument { return []Argument{} }
+func (cmd *contextCommand) GetOptions() []Option     { return []Option{} }
+
+func (cmd *contextCommand) Execute(input Input) error {
+	close(cmd.started)
+	select {
+	case <-InputContext(input).Done():
+		cmd.err = InputContext(input).Err()
+		return cmd.err
+	case <-time.After(2 * time.Second):
+		return nil
+	}
+}
+
+func TestCommandContextCancellation(t *testing.T) {
+	app := NewApplication("test-app", "1.0.0")
+	output := &bufferOutput{}
+	app.output = output
+
+	// 中断信号取消命令的 context
+	cmd := &contextCommand{started: make(chan struct{})}
+	app.AddCommand(cmd)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.Run([]string{"long:run"})
+	}()
+
+	<-cmd.started
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("Failed to send interrupt: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+		if !strings.Contains(output.String(), "Command 'long:run' interrupted") {
+			t.Errorf("Expected interruption to be reported, got %q", output.String())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected command to observe the interrupt")
+	}
+
+	// --timeout 到期后取消 context
+	cmd = &contextCommand{started: make(chan struct{})}
+	app.AddCommand(cmd)
+	if err := app.Run([]string{"long:run", "--timeout=50ms"}); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if !strings.Contains(output.String(), "Command 'long:run' timed out") {
+		t.Errorf("Expected timeout to be reported, got %q", output.String())
+	}
+
+	if _, _, err := extractTimeout([]string{"--timeout", "soon"}, cmd); err == nil {
+		t.Error("Expected invalid timeout to be rejected")
+	}
+	timeout, args, err := extractTimeout([]string{"name", "--timeout", "30"}, cmd)
+	if err != nil || timeout != 30*time.Second || len(args) != 1 || args[0] != "name" {
+		t.Errorf("Unexpected timeout parsing: %v %v %v", timeout, args, err)
+	}
+}
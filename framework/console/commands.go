@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"laravel-go/framework/database"
 )
 
 // MakeControllerCommand 生成控制器命令
@@ -91,7 +94,7 @@ func (cmd *MakeModelCommand) GetDescription() string {
 
 // GetSignature 获取命令签名
 func (cmd *MakeModelCommand) GetSignature() string {
-	return "make:model <name> [--fields=]"
+	return "make:model <name> [--fields=] [--from-table=]"
 }
 
 // GetArguments 获取命令参数
@@ -116,6 +119,14 @@ func (cmd *MakeModelCommand) GetOptions() []Option {
 			Default:     "",
 			Type:        "string",
 		},
+		{
+			Name:        "from-table",
+			ShortName:   "t",
+			Description: "Build the model and resource from an existing table (uses DB_CONNECTION and DB_* settings)",
+			Required:    false,
+			Default:     "",
+			Type:        "string",
+		},
 	}
 }
 
@@ -124,6 +135,20 @@ func (cmd *MakeModelCommand) Execute(input Input) error {
 	name := input.GetArgument("name").(string)
 	fieldsStr := input.GetOption("fields").(string)
 
+	if table, _ := input.GetOption("from-table").(string); table != "" {
+		conn, err := database.NewConnection(databaseConfigFromEnv())
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer conn.Close()
+
+		columns, err := database.Introspect(conn, table)
+		if err != nil {
+			return err
+		}
+		return cmd.generator.GenerateModelFromTable(name, table, columns)
+	}
+
 	var fields []string
 	if fieldsStr != "" {
 		fields = strings.Split(fieldsStr, ",")
@@ -132,6 +157,38 @@ func (cmd *MakeModelCommand) Execute(input Input) error {
 	return cmd.generator.GenerateModel(name, fields)
 }
 
+// databaseConfigFromEnv 根据 DB_* 环境变量创建数据库连接配置
+func databaseConfigFromEnv() *database.ConnectionConfig {
+	env := func(key, defaultValue string) string {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+		return defaultValue
+	}
+
+	driver := database.Driver(env("DB_CONNECTION", "sqlite"))
+	switch driver {
+	case "pgsql", "postgresql":
+		driver = database.PostgreSQL
+	case "mssql":
+		driver = database.SQLServer
+	}
+	port, _ := strconv.Atoi(os.Getenv("DB_PORT"))
+	if port == 0 {
+		port = map[database.Driver]int{database.MySQL: 3306, database.PostgreSQL: 5432, database.SQLServer: 1433}[driver]
+	}
+
+	return &database.ConnectionConfig{
+		Driver:   driver,
+		Host:     env("DB_HOST", "127.0.0.1"),
+		Port:     port,
+		Database: env("DB_DATABASE", "app.db"),
+		Username: os.Getenv("DB_USERNAME"),
+		Password: os.Getenv("DB_PASSWORD"),
+		Charset:  os.Getenv("DB_CHARSET"),
+	}
+}
+
 // MakeMiddlewareCommand 生成中间件命令
 type MakeMiddlewareCommand struct {
 	generator *Generator
@@ -318,6 +375,65 @@ func (cmd *MakeTestCommand) Execute(input Input) error {
 	return cmd.generator.GenerateTest(name, type_)
 }
 
+// MakeCommandCommand 生成命令行命令
+type MakeCommandCommand struct {
+	generator *Generator
+}
+
+// NewMakeCommandCommand 创建新的生成命令行命令
+func NewMakeCommandCommand(generator *Generator) *MakeCommandCommand {
+	return &MakeCommandCommand{
+		generator: generator,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *MakeCommandCommand) GetName() string {
+	return "make:command"
+}
+
+// GetDescription 获取命令描述
+func (cmd *MakeCommandCommand) GetDescription() string {
+	return "Create a new console command"
+}
+
+// GetSignature 获取命令签名
+func (cmd *MakeCommandCommand) GetSignature() string {
+	return "make:command <name> [--command=]"
+}
+
+// GetArguments 获取命令参数
+func (cmd *MakeCommandCommand) GetArguments() []Argument {
+	return []Argument{
+		{
+			Name:        "name",
+			Description: "The name of the command",
+			Required:    true,
+		},
+	}
+}
+
+// GetOptions 获取命令选项
+func (cmd *MakeCommandCommand) GetOptions() []Option {
+	return []Option{
+		{
+			Name:        "command",
+			ShortName:   "c",
+			Description: "The terminal command that should be assigned, defaults to app:<name>",
+			Required:    false,
+			Default:     "",
+			Type:        "string",
+		},
+	}
+}
+
+// Execute 执行命令
+func (cmd *MakeCommandCommand) Execute(input Input) error {
+	name := input.GetArgument("name").(string)
+	commandName, _ := input.GetOption("command").(string)
+	return cmd.generator.GenerateCommand(name, commandName)
+}
+
 // InitCommand 项目初始化命令
 type InitCommand struct {
 	output Output
@@ -2897,19 +3013,27 @@ func (cmd *InitCommand) generateAPIGatewayFiles(config *ProjectConfig, projectDi
 		"gateway/main.go": `package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/coien1983/laravel-go/framework/auth"
 	"github.com/gorilla/mux"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	pb "` + projectDir + `/proto/user"
 )
 
@@ -2917,10 +3041,19 @@ import (
 type Gateway struct {
 	userClient pb.UserServiceClient
 	router     *mux.Router
+	logger     *log.Logger
+	logBodies  bool
+	guard      *auth.JWTGuard
 }
 
 // NewGateway 创建网关实例
 func NewGateway() (*Gateway, error) {
+	// 接口使用 JWT 认证，密钥与签发令牌的服务保持一致
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, fmt.Errorf("JWT_SECRET is required")
+	}
+
 	// 连接gRPC服务
 	conn, err := grpc.Dial("localhost:9090", grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -2933,6 +3066,10 @@ func NewGateway() (*Gateway, error) {
 	gateway := &Gateway{
 		userClient: userClient,
 		router:     router,
+		logger:     log.New(os.Stdout, "", 0),
+		// 调试时设置 GATEWAY_LOG_BODIES=true 记录脱敏后的请求体和响应体
+		logBodies: os.Getenv("GATEWAY_LOG_BODIES") == "true",
+		guard:     auth.NewJWTGuard(nil, secret, time.Hour),
 	}
 
 	// 注册路由
@@ -2946,6 +3083,8 @@ func (gateway *Gateway) registerRoutes() {
 	// 中间件
 	gateway.router.Use(gateway.loggingMiddleware)
 	gateway.router.Use(gateway.corsMiddleware)
+	// 除健康检查外的接口都需要 Bearer 令牌
+	gateway.router.Use(newAuthMiddleware(gateway.validateToken, "/health"))
 
 	// API路由
 	api := gateway.router.PathPrefix("/api/v1").Subrouter()
@@ -2961,13 +3100,221 @@ func (gateway *Gateway) registerRoutes() {
 	gateway.router.HandleFunc("/health", gateway.healthCheck).Methods("GET")
 }
 
-// loggingMiddleware 日志中间件
+// loggingMiddleware 日志中间件，每个请求输出一行 JSON 日志
 func (gateway *Gateway) loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-		next.ServeHTTP(w, r)
-		log.Printf("API Gateway: %s %s %v", r.Method, r.URL.Path, time.Since(start))
-	})
+	return newLoggingMiddleware(gateway.logger, gateway.logBodies)(next)
+}
+
+// validateToken 校验 JWT 令牌，返回令牌中的用户 ID
+func (gateway *Gateway) validateToken(token string) (string, error) {
+	claims, err := gateway.guard.ValidateToken(token)
+	if err != nil {
+		return "", err
+	}
+	if claims.UserID == nil {
+		return "", errors.New("token has no user id")
+	}
+	return fmt.Sprint(claims.UserID), nil
+}
+
+// userIDMetadataKey 转发给后端的已认证用户 ID 的 gRPC 元数据键
+const userIDMetadataKey = "x-user-id"
+
+// userIDKey 请求 context 中已认证用户 ID 的键
+type userIDKey struct{}
+
+// newAuthMiddleware 创建 Bearer 令牌认证中间件，publicPaths 中的路径不需要认证
+//
+// validate 校验令牌并返回用户 ID，缺少令牌或校验失败时返回 401。认证通过后用户 ID 写入请求 context，
+// 由 requestContext 作为 x-user-id 元数据转发给后端。
+func newAuthMiddleware(validate func(token string) (string, error), publicPaths ...string) func(http.Handler) http.Handler {
+	public := make(map[string]bool, len(publicPaths))
+	for _, path := range publicPaths {
+		public[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if public[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, "Bearer ") {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeJSONError(w, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+
+			userID, err := validate(strings.TrimPrefix(header, "Bearer "))
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer error=\"invalid_token\"")
+				writeJSONError(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userIDKey{}, userID)))
+		})
+	}
+}
+
+// requestContext 创建调用后端的 context，客户端断开时一起取消，并转发已认证的用户 ID
+func requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	ctx := r.Context()
+	if userID, ok := ctx.Value(userIDKey{}).(string); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, userIDMetadataKey, userID)
+	}
+	return context.WithTimeout(ctx, 10*time.Second)
+}
+
+// maxLoggedBodySize 调试日志中记录的请求体和响应体的最大长度
+const maxLoggedBodySize = 4096
+
+// sensitiveFields 日志中需要脱敏的字段，字段名包含其中任意一个时被遮盖
+var sensitiveFields = []string{"password", "token", "secret"}
+
+// loggingResponseWriter 记录状态码、响应大小和响应体的 ResponseWriter
+type loggingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+	body   *bytes.Buffer
+}
+
+// WriteHeader 记录状态码
+func (w *loggingResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write 记录响应大小，需要时保存响应体，最多保存 maxLoggedBodySize 字节
+func (w *loggingResponseWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.size += n
+	if w.body != nil {
+		captured := data[:n]
+		if remaining := maxLoggedBodySize - w.body.Len(); len(captured) > remaining {
+			captured = captured[:remaining]
+		}
+		w.body.Write(captured)
+	}
+	return n, err
+}
+
+// replayBody 已读取的部分放回请求体前面，关闭时关闭原请求体
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// newLoggingMiddleware 创建结构化日志中间件，logBodies 为 true 时同时记录脱敏后的请求体和响应体
+func newLoggingMiddleware(logger *log.Logger, logBodies bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := r.Header.Get("X-Request-ID")
+			if requestID == "" {
+				requestID = strconv.FormatInt(start.UnixNano(), 36)
+				r.Header.Set("X-Request-ID", requestID)
+			}
+			w.Header().Set("X-Request-ID", requestID)
+
+			entry := map[string]interface{}{
+				"time":        start.Format(time.RFC3339),
+				"request_id":  requestID,
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"remote_addr": r.RemoteAddr,
+			}
+
+			recorder := &loggingResponseWriter{ResponseWriter: w}
+			if logBodies {
+				recorder.body = &bytes.Buffer{}
+				if r.Body != nil {
+					// 最多多读一个字节，用于判断请求体是否超过记录上限
+					body, err := io.ReadAll(io.LimitReader(r.Body, maxLoggedBodySize+1))
+					// 已读取的部分放回请求体前面，后续处理器仍能完整读取
+					r.Body = replayBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+					if err == nil && len(body) > 0 {
+						size := int64(len(body))
+						if size > maxLoggedBodySize {
+							size = r.ContentLength
+						}
+						entry["request_body"] = redactBody(body, size)
+					}
+				}
+			}
+
+			next.ServeHTTP(recorder, r)
+
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+			entry["status"] = recorder.status
+			entry["size"] = recorder.size
+			entry["duration_ms"] = float64(time.Since(start).Microseconds()) / 1000
+			if recorder.body != nil && recorder.body.Len() > 0 {
+				entry["response_body"] = redactBody(recorder.body.Bytes(), int64(recorder.size))
+			}
+
+			data, _ := json.Marshal(entry)
+			logger.Println(string(data))
+		})
+	}
+}
+
+// redactBody 把 JSON 内容中的敏感字段遮盖后返回，过大或不是 JSON 的内容只记录长度，避免泄露敏感数据
+//
+// body 为截取的内容，size 为内容的实际长度，未知时为 -1。
+func redactBody(body []byte, size int64) interface{} {
+	if size > maxLoggedBodySize || len(body) > maxLoggedBodySize {
+		if size < 0 {
+			return "[body omitted: more than " + strconv.Itoa(maxLoggedBodySize) + " bytes]"
+		}
+		return "[body omitted: " + strconv.FormatInt(size, 10) + " bytes]"
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "[non-JSON body: " + strconv.Itoa(len(body)) + " bytes]"
+	}
+	return redactValue(value)
+}
+
+// redactValue 递归遮盖敏感字段
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveField(key) {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = redactValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// isSensitiveField 判断字段是否需要脱敏
+func isSensitiveField(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range sensitiveFields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
 }
 
 // corsMiddleware CORS中间件
@@ -3000,12 +3347,14 @@ func (gateway *Gateway) healthCheck(w http.ResponseWriter, r *http.Request) {
 
 // getUsers 获取用户列表
 func (gateway *Gateway) getUsers(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	// 从查询参数获取分页信息
-	page := int32(1)
-	pageSize := int32(10)
+	page, pageSize, ok := parsePagination(w, r.URL.Query())
+	if !ok {
+		return
+	}
 	search := r.URL.Query().Get("search")
 
 	resp, err := gateway.userClient.ListUsers(ctx, &pb.ListUsersRequest{
@@ -3023,15 +3372,70 @@ func (gateway *Gateway) getUsers(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
+// 分页参数的默认值和上限
+const (
+	defaultPageSize = 10
+	maxPageSize     = 100
+)
+
+// parsePagination 解析 page 和 page_size 查询参数，page_size 超过上限时按上限处理，
+// 参数不是正整数时返回 400
+func parsePagination(w http.ResponseWriter, query url.Values) (int32, int32, bool) {
+	page, pageSize := int64(1), int64(defaultPageSize)
+	params := []struct {
+		name   string
+		target *int64
+	}{{"page", &page}, {"page_size", &pageSize}}
+	for _, param := range params {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || parsed <= 0 {
+			writeJSONError(w, http.StatusBadRequest, param.name+" must be a positive integer")
+			return 0, 0, false
+		}
+		*param.target = parsed
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return int32(page), int32(pageSize), true
+}
+
+// parseUserID 解析路径中的用户ID，ID 不是正整数时返回 400
+func parseUserID(w http.ResponseWriter, value string) (int64, bool) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid user id: "+value)
+		return 0, false
+	}
+	if id <= 0 {
+		writeJSONError(w, http.StatusBadRequest, "user id must be positive")
+		return 0, false
+	}
+	return id, true
+}
+
+// writeJSONError 返回 JSON 格式的错误
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  message,
+		"status": status,
+	})
+}
+
 // getUser 获取单个用户
 func (gateway *Gateway) getUser(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["id"]
-	
-	// TODO: 解析用户ID
-	id := int64(1) // 示例
+	id, ok := parseUserID(w, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	resp, err := gateway.userClient.GetUser(ctx, &pb.GetUserRequest{Id: id})
@@ -3058,7 +3462,7 @@ func (gateway *Gateway) createUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	resp, err := gateway.userClient.CreateUser(ctx, &pb.CreateUserRequest{
@@ -3080,11 +3484,10 @@ func (gateway *Gateway) createUser(w http.ResponseWriter, r *http.Request) {
 
 // updateUser 更新用户
 func (gateway *Gateway) updateUser(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["id"]
-	
-	// TODO: 解析用户ID
-	id := int64(1) // 示例
+	id, ok := parseUserID(w, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
 
 	var req struct {
 		Name   string ` + "`json:\"name\"`" + `
@@ -3098,7 +3501,7 @@ func (gateway *Gateway) updateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	resp, err := gateway.userClient.UpdateUser(ctx, &pb.UpdateUserRequest{
@@ -3120,13 +3523,12 @@ func (gateway *Gateway) updateUser(w http.ResponseWriter, r *http.Request) {
 
 // deleteUser 删除用户
 func (gateway *Gateway) deleteUser(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["id"]
-	
-	// TODO: 解析用户ID
-	id := int64(1) // 示例
+	id, ok := parseUserID(w, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	ctx, cancel := requestContext(r)
 	defer cancel()
 
 	resp, err := gateway.userClient.DeleteUser(ctx, &pb.DeleteUserRequest{Id: id})
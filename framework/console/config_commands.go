@@ -0,0 +1,152 @@
+package console
+
+import (
+	"fmt"
+
+	"laravel-go/framework/config"
+)
+
+// ConfigCacheCommand 将所有配置编译为一个文件，加快生产环境启动
+type ConfigCacheCommand struct {
+	output Output
+}
+
+// NewConfigCacheCommand 创建 config:cache 命令
+func NewConfigCacheCommand(output Output) *ConfigCacheCommand {
+	return &ConfigCacheCommand{
+		output: output,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *ConfigCacheCommand) GetName() string {
+	return "config:cache"
+}
+
+// GetDescription 获取命令描述
+func (cmd *ConfigCacheCommand) GetDescription() string {
+	return "Create a cache file for faster configuration loading"
+}
+
+// GetSignature 获取命令签名
+func (cmd *ConfigCacheCommand) GetSignature() string {
+	return "config:cache [--env=] [--dir=] [--path=]"
+}
+
+// GetArguments 获取命令参数
+func (cmd *ConfigCacheCommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *ConfigCacheCommand) GetOptions() []Option {
+	return []Option{
+		{
+			Name:        "env",
+			Description: "The environment file to load",
+			Default:     ".env",
+			Type:        "string",
+		},
+		{
+			Name:        "dir",
+			Description: "The configuration directory",
+			Default:     "config",
+			Type:        "string",
+		},
+		{
+			Name:        "path",
+			Description: "The compiled configuration file",
+			Default:     config.CachePath,
+			Type:        "string",
+		},
+	}
+}
+
+// Execute 执行命令
+func (cmd *ConfigCacheCommand) Execute(input Input) error {
+	envFile := stringOption(input, "env", ".env")
+	dir := stringOption(input, "dir", "config")
+	path := stringOption(input, "path", config.CachePath)
+
+	// 先删除旧的编译文件，确保从配置目录重新加载
+	if err := config.ClearCache(path); err != nil {
+		return fmt.Errorf("failed to clear cached config: %w", err)
+	}
+
+	cfg := config.NewConfig()
+	if err := cfg.LoadEnv(envFile); err != nil {
+		return fmt.Errorf("failed to load %s: %w", envFile, err)
+	}
+	if err := cfg.LoadDir(dir); err != nil {
+		return fmt.Errorf("failed to load config directory %s: %w", dir, err)
+	}
+	if err := cfg.Cache(path); err != nil {
+		return fmt.Errorf("failed to write cached config: %w", err)
+	}
+
+	cmd.output.Success(fmt.Sprintf("Configuration cached successfully: %s", path))
+	return nil
+}
+
+// ConfigClearCommand 删除编译后的配置文件
+type ConfigClearCommand struct {
+	output Output
+}
+
+// NewConfigClearCommand 创建 config:clear 命令
+func NewConfigClearCommand(output Output) *ConfigClearCommand {
+	return &ConfigClearCommand{
+		output: output,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *ConfigClearCommand) GetName() string {
+	return "config:clear"
+}
+
+// GetDescription 获取命令描述
+func (cmd *ConfigClearCommand) GetDescription() string {
+	return "Remove the configuration cache file"
+}
+
+// GetSignature 获取命令签名
+func (cmd *ConfigClearCommand) GetSignature() string {
+	return "config:clear [--path=]"
+}
+
+// GetArguments 获取命令参数
+func (cmd *ConfigClearCommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *ConfigClearCommand) GetOptions() []Option {
+	return []Option{
+		{
+			Name:        "path",
+			Description: "The compiled configuration file",
+			Default:     config.CachePath,
+			Type:        "string",
+		},
+	}
+}
+
+// Execute 执行命令
+func (cmd *ConfigClearCommand) Execute(input Input) error {
+	path := stringOption(input, "path", config.CachePath)
+	if err := config.ClearCache(path); err != nil {
+		return fmt.Errorf("failed to clear cached config: %w", err)
+	}
+
+	cmd.output.Success("Configuration cache cleared successfully")
+	return nil
+}
+
+// stringOption 获取字符串选项，未设置时返回默认值
+func stringOption(input Input, name, defaultValue string) string {
+	if value, ok := input.GetOption(name).(string); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
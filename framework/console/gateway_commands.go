@@ -0,0 +1,117 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"laravel-go/framework/microservice"
+)
+
+// GatewayOpenAPICommand 根据 proto 描述符和网关路由生成 OpenAPI 文档
+//
+// 在网关包中加入 go:generate 指令，构建前执行 go generate 即可保持文档与 proto 同步：
+//
+//	//go:generate protoc --include_imports --descriptor_set_out=api.pb -I ../proto ../proto/user/user.proto
+//	//go:generate artisan gateway:openapi --descriptor=api.pb --routes=routes.json --output=openapi.json
+type GatewayOpenAPICommand struct {
+	output Output
+}
+
+// NewGatewayOpenAPICommand 创建网关 OpenAPI 文档生成命令
+func NewGatewayOpenAPICommand(output Output) *GatewayOpenAPICommand {
+	return &GatewayOpenAPICommand{
+		output: output,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *GatewayOpenAPICommand) GetName() string {
+	return "gateway:openapi"
+}
+
+// GetDescription 获取命令描述
+func (cmd *GatewayOpenAPICommand) GetDescription() string {
+	return "Generate OpenAPI documentation from proto descriptors and gateway routes"
+}
+
+// GetSignature 获取命令签名
+func (cmd *GatewayOpenAPICommand) GetSignature() string {
+	return "gateway:openapi [--descriptor=] [--routes=] [--output=] [--title=] [--api-version=]"
+}
+
+// GetArguments 获取命令参数
+func (cmd *GatewayOpenAPICommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *GatewayOpenAPICommand) GetOptions() []Option {
+	return []Option{
+		{
+			Name:        "descriptor",
+			ShortName:   "d",
+			Description: "Descriptor set generated by protoc --include_imports --descriptor_set_out",
+			Default:     "api.pb",
+			Type:        "string",
+		},
+		{
+			Name:        "routes",
+			ShortName:   "r",
+			Description: "Gateway routes in JSON format",
+			Default:     "routes.json",
+			Type:        "string",
+		},
+		{
+			Name:        "output",
+			ShortName:   "o",
+			Description: "Output file for the OpenAPI document",
+			Default:     "openapi.json",
+			Type:        "string",
+		},
+		{
+			Name:        "title",
+			Description: "API title",
+			Default:     "API Gateway",
+			Type:        "string",
+		},
+		{
+			Name:        "api-version",
+			Description: "API version",
+			Default:     "1.0.0",
+			Type:        "string",
+		},
+	}
+}
+
+// Execute 执行命令
+func (cmd *GatewayOpenAPICommand) Execute(input Input) error {
+	files, err := microservice.LoadDescriptorSet(input.GetOption("descriptor").(string))
+	if err != nil {
+		return err
+	}
+	routes, err := microservice.LoadGatewayRoutes(input.GetOption("routes").(string))
+	if err != nil {
+		return err
+	}
+
+	doc, err := microservice.GenerateGatewayOpenAPI(files, routes, input.GetOption("title").(string), input.GetOption("api-version").(string))
+	if err != nil {
+		return err
+	}
+	data, err := doc.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode OpenAPI document: %w", err)
+	}
+
+	output := input.GetOption("output").(string)
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", output, err)
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+
+	cmd.output.Success(fmt.Sprintf("OpenAPI document generated: %s (%d routes)", output, len(routes)))
+	return nil
+}
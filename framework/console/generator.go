@@ -10,6 +10,8 @@ import (
 	"strings"
 	"text/template"
 	"time"
+
+	"laravel-go/framework/database"
 )
 
 // Generator 代码生成器
@@ -204,6 +206,119 @@ func New{{ .ModelName }}() *{{ .ModelName }} {
 	return nil
 }
 
+// GenerateModelFromTable 根据数据表结构生成模型和资源
+//
+// database.Model 已包含的 id、created_at、updated_at、deleted_at 列不会重复生成。
+func (g *Generator) GenerateModelFromTable(name, table string, columns []database.Column) error {
+	modelName := g.toPascalCase(name)
+	fileName := strings.ToLower(name) + ".go"
+
+	var fields []map[string]string
+	needsTime := false
+	for _, column := range columns {
+		switch column.Name {
+		case "id", "created_at", "updated_at", "deleted_at":
+			continue
+		}
+		goType := column.GoType()
+		if strings.Contains(goType, "time.Time") {
+			needsTime = true
+		}
+		fields = append(fields, map[string]string{
+			"Name":   g.toPascalCase(column.Name),
+			"Type":   goType,
+			"Column": column.Name,
+		})
+	}
+
+	data := map[string]interface{}{
+		"ModelName":   modelName,
+		"TableName":   table,
+		"Fields":      fields,
+		"NeedsTime":   needsTime,
+		"ProjectName": g.getProjectName(),
+	}
+
+	modelTemplate := `package models
+
+import (
+{{- if .NeedsTime }}
+	"time"
+{{ end }}
+	"github.com/coien1983/laravel-go/framework/database"
+)
+
+// {{ .ModelName }} 模型，根据数据表 {{ .TableName }} 生成
+type {{ .ModelName }} struct {
+	database.Model
+{{- range .Fields }}
+	{{ .Name }} {{ .Type }} ` + "`" + `db:"{{ .Column }}" json:"{{ .Column }}"` + "`" + `
+{{- end }}
+}
+
+// TableName 获取表名
+func (m *{{ .ModelName }}) TableName() string {
+	return "{{ .TableName }}"
+}
+
+// New{{ .ModelName }} 创建新的模型实例
+func New{{ .ModelName }}() *{{ .ModelName }} {
+	return &{{ .ModelName }}{}
+}
+`
+
+	resourceTemplate := `package resources
+
+import (
+	"github.com/coien1983/laravel-go/framework/api"
+	"{{ .ProjectName }}/app/models"
+)
+
+// New{{ .ModelName }}Resource 创建 {{ .ModelName }} 资源
+func New{{ .ModelName }}Resource(m *models.{{ .ModelName }}) *api.BaseResource {
+	return api.NewResource(map[string]interface{}{
+		"id": m.ID,
+{{- range .Fields }}
+		"{{ .Column }}": m.{{ .Name }},
+{{- end }}
+		"created_at": m.CreatedAt,
+		"updated_at": m.UpdatedAt,
+	})
+}
+`
+
+	files := []struct {
+		dir, name, kind, label, content string
+	}{
+		{filepath.Join("app", "models"), fileName, "model", "Model", modelTemplate},
+		{filepath.Join("app", "resources"), strings.ToLower(name) + "_resource.go", "resource", "Resource", resourceTemplate},
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(f.dir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s directory: %w", f.kind, err)
+		}
+
+		tmpl, err := template.New(f.kind).Parse(f.content)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s template: %w", f.kind, err)
+		}
+
+		filePath := filepath.Join(f.dir, f.name)
+		file, err := os.Create(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to create %s file: %w", f.kind, err)
+		}
+		err = tmpl.Execute(file, data)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to execute %s template: %w", f.kind, err)
+		}
+
+		g.output.Success(fmt.Sprintf("%s created successfully: %s", f.label, filePath))
+	}
+	return nil
+}
+
 // GenerateMiddleware 生成中间件
 func (g *Generator) GenerateMiddleware(name string) error {
 	// 创建中间件目录
@@ -436,6 +551,115 @@ func Benchmark{{ .TestName }}(b *testing.B) {
 	return nil
 }
 
+// GenerateCommand 生成命令行命令
+//
+// 生成的命令在 init 中调用 console.RegisterCommand 注册自己，
+// 入口文件只需匿名导入 app/console/commands 包并调用 DiscoverCommands。
+func (g *Generator) GenerateCommand(name, commandName string) error {
+	// 创建命令目录
+	commandDir := filepath.Join("app", "console", "commands")
+	if err := os.MkdirAll(commandDir, 0755); err != nil {
+		return fmt.Errorf("failed to create command directory: %w", err)
+	}
+
+	// 生成命令文件名
+	structName := g.toPascalCase(name) + "Command"
+	fileName := strings.ToLower(name) + "_command.go"
+	filePath := filepath.Join(commandDir, fileName)
+
+	if commandName == "" {
+		commandName = "app:" + strings.NewReplacer("_", "-", " ", "-").Replace(strings.ToLower(name))
+	}
+
+	// 命令模板
+	commandTemplate := `package commands
+
+import (
+	"github.com/coien1983/laravel-go/framework/console"
+)
+
+func init() {
+	console.RegisterCommand(func() console.Command {
+		return New{{ .StructName }}(console.NewConsoleOutput())
+	})
+}
+
+// {{ .StructName }} {{ .CommandName }} 命令
+type {{ .StructName }} struct {
+	output console.Output
+}
+
+// New{{ .StructName }} 创建新的命令实例
+func New{{ .StructName }}(output console.Output) *{{ .StructName }} {
+	return &{{ .StructName }}{
+		output: output,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *{{ .StructName }}) GetName() string {
+	return "{{ .CommandName }}"
+}
+
+// GetDescription 获取命令描述
+func (cmd *{{ .StructName }}) GetDescription() string {
+	return "Command description"
+}
+
+// GetSignature 获取命令签名
+func (cmd *{{ .StructName }}) GetSignature() string {
+	return "{{ .CommandName }}"
+}
+
+// GetArguments 获取命令参数
+func (cmd *{{ .StructName }}) GetArguments() []console.Argument {
+	return []console.Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *{{ .StructName }}) GetOptions() []console.Option {
+	return []console.Option{}
+}
+
+// Execute 执行命令
+func (cmd *{{ .StructName }}) Execute(input console.Input) error {
+	return cmd.Handle(input)
+}
+
+// Handle 命令逻辑
+func (cmd *{{ .StructName }}) Handle(input console.Input) error {
+	cmd.output.Info("{{ .CommandName }} executed")
+	return nil
+}
+`
+
+	// 解析模板
+	tmpl, err := template.New("command").Parse(commandTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse command template: %w", err)
+	}
+
+	// 创建文件
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create command file: %w", err)
+	}
+	defer file.Close()
+
+	// 执行模板
+	data := map[string]interface{}{
+		"StructName":  structName,
+		"CommandName": commandName,
+	}
+
+	if err := tmpl.Execute(file, data); err != nil {
+		return fmt.Errorf("failed to execute command template: %w", err)
+	}
+
+	g.output.Success(fmt.Sprintf("Command created successfully: %s", filePath))
+	return nil
+}
+
 // toPascalCase 转换为PascalCase
 func (g *Generator) toPascalCase(s string) string {
 	words := strings.FieldsFunc(s, func(r rune) bool {
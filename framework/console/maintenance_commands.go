@@ -0,0 +1,157 @@
+package console
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"laravel-go/framework/http/middleware"
+)
+
+// DownCommand 让应用进入维护模式
+type DownCommand struct {
+	output Output
+}
+
+// NewDownCommand 创建 down 命令
+func NewDownCommand(output Output) *DownCommand {
+	return &DownCommand{
+		output: output,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *DownCommand) GetName() string {
+	return "down"
+}
+
+// GetDescription 获取命令描述
+func (cmd *DownCommand) GetDescription() string {
+	return "Put the application into maintenance mode"
+}
+
+// GetSignature 获取命令签名
+func (cmd *DownCommand) GetSignature() string {
+	return "down [--message=] [--retry=] [--secret=] [--allow=] [--file=]"
+}
+
+// GetArguments 获取命令参数
+func (cmd *DownCommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *DownCommand) GetOptions() []Option {
+	return []Option{
+		{
+			Name:        "message",
+			Description: "The message shown to users during maintenance",
+			Type:        "string",
+		},
+		{
+			Name:        "retry",
+			Description: "The number of seconds after which the request may be retried",
+			Default:     0,
+			Type:        "int",
+		},
+		{
+			Name:        "secret",
+			Description: "The secret path that may be used to bypass maintenance mode",
+			Type:        "string",
+		},
+		{
+			Name:        "allow",
+			Description: "Comma separated IP addresses or CIDR ranges allowed to access the application",
+			Type:        "string",
+		},
+		{
+			Name:        "file",
+			Description: "The maintenance file",
+			Default:     middleware.DefaultMaintenanceFile,
+			Type:        "string",
+		},
+	}
+}
+
+// Execute 执行命令
+func (cmd *DownCommand) Execute(input Input) error {
+	state := &middleware.MaintenanceState{
+		Time: time.Now().Unix(),
+	}
+	state.Message, _ = input.GetOption("message").(string)
+	state.RetryAfter, _ = input.GetOption("retry").(int)
+	state.Secret, _ = input.GetOption("secret").(string)
+	if allow, _ := input.GetOption("allow").(string); allow != "" {
+		for _, ip := range strings.Split(allow, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				state.AllowedIPs = append(state.AllowedIPs, ip)
+			}
+		}
+	}
+
+	file := stringOption(input, "file", middleware.DefaultMaintenanceFile)
+	if err := middleware.WriteMaintenanceState(file, state); err != nil {
+		return fmt.Errorf("failed to enable maintenance mode: %w", err)
+	}
+
+	cmd.output.Warning("Application is now in maintenance mode")
+	if state.Secret != "" {
+		cmd.output.Info(fmt.Sprintf("Bypass maintenance mode by visiting /%s", state.Secret))
+	}
+	return nil
+}
+
+// UpCommand 让应用退出维护模式
+type UpCommand struct {
+	output Output
+}
+
+// NewUpCommand 创建 up 命令
+func NewUpCommand(output Output) *UpCommand {
+	return &UpCommand{
+		output: output,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *UpCommand) GetName() string {
+	return "up"
+}
+
+// GetDescription 获取命令描述
+func (cmd *UpCommand) GetDescription() string {
+	return "Bring the application out of maintenance mode"
+}
+
+// GetSignature 获取命令签名
+func (cmd *UpCommand) GetSignature() string {
+	return "up [--file=]"
+}
+
+// GetArguments 获取命令参数
+func (cmd *UpCommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *UpCommand) GetOptions() []Option {
+	return []Option{
+		{
+			Name:        "file",
+			Description: "The maintenance file",
+			Default:     middleware.DefaultMaintenanceFile,
+			Type:        "string",
+		},
+	}
+}
+
+// Execute 执行命令
+func (cmd *UpCommand) Execute(input Input) error {
+	file := stringOption(input, "file", middleware.DefaultMaintenanceFile)
+	if err := middleware.RemoveMaintenanceState(file); err != nil {
+		return fmt.Errorf("failed to disable maintenance mode: %w", err)
+	}
+
+	cmd.output.Success("Application is now live")
+	return nil
+}
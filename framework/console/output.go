@@ -0,0 +1,328 @@
+package console
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ANSI 颜色代码
+const (
+	colorRed    = "31"
+	colorGreen  = "32"
+	colorYellow = "33"
+	colorBlue   = "34"
+)
+
+// defaultTerminalWidth 无法获取终端宽度时使用的默认宽度
+const defaultTerminalWidth = 80
+
+// ConsoleOutput 控制台输出实现
+//
+// 输出不是终端或设置了 NO_COLOR 环境变量时自动关闭颜色。
+type ConsoleOutput struct {
+	out    io.Writer
+	errOut io.Writer
+	color  bool
+	width  int
+}
+
+// NewConsoleOutput 创建新的控制台输出
+func NewConsoleOutput() *ConsoleOutput {
+	return NewConsoleOutputWriter(os.Stdout, os.Stderr)
+}
+
+// NewConsoleOutputWriter 创建写入指定位置的控制台输出，错误信息写入 errOut
+func NewConsoleOutputWriter(out, errOut io.Writer) *ConsoleOutput {
+	return &ConsoleOutput{
+		out:    out,
+		errOut: errOut,
+		color:  os.Getenv("NO_COLOR") == "" && isTerminal(out),
+	}
+}
+
+// SetColor 强制开启或关闭颜色
+func (output *ConsoleOutput) SetColor(enabled bool) {
+	output.color = enabled
+}
+
+// SetWidth 设置表格使用的终端宽度，0 表示自动检测
+func (output *ConsoleOutput) SetWidth(width int) {
+	output.width = width
+}
+
+// Write 写入内容
+func (output *ConsoleOutput) Write(content string) {
+	fmt.Fprint(output.out, content)
+}
+
+// WriteLine 写入一行
+func (output *ConsoleOutput) WriteLine(content string) {
+	fmt.Fprintln(output.out, content)
+}
+
+// Error 输出错误信息
+func (output *ConsoleOutput) Error(message string) {
+	fmt.Fprintln(output.errOut, output.colorize(colorRed, message))
+}
+
+// Success 输出成功信息
+func (output *ConsoleOutput) Success(message string) {
+	fmt.Fprintln(output.out, output.colorize(colorGreen, message))
+}
+
+// Warning 输出警告信息
+func (output *ConsoleOutput) Warning(message string) {
+	fmt.Fprintln(output.out, output.colorize(colorYellow, message))
+}
+
+// Info 输出信息
+func (output *ConsoleOutput) Info(message string) {
+	fmt.Fprintln(output.out, output.colorize(colorBlue, message))
+}
+
+// Table 输出表格，列按显示宽度对齐，超出终端宽度时截断最宽的列
+func (output *ConsoleOutput) Table(headers []string, rows [][]string) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = displayWidth(header)
+	}
+	for _, row := range rows {
+		for i := 0; i < len(row) && i < len(widths); i++ {
+			if w := displayWidth(row[i]); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	fitColumns(widths, output.terminalWidth())
+
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("-", width)
+	}
+
+	output.writeRow(headers, widths)
+	output.writeRow(separators, widths)
+	for _, row := range rows {
+		output.writeRow(row, widths)
+	}
+}
+
+// ProgressBar 创建写入当前输出的进度条
+func (output *ConsoleOutput) ProgressBar(total int) *ProgressBar {
+	return NewProgressBar(output.out, total)
+}
+
+// writeRow 输出表格的一行
+func (output *ConsoleOutput) writeRow(cells []string, widths []int) {
+	var line strings.Builder
+	for i, width := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = truncate(cells[i], width)
+		}
+		line.WriteString(cell)
+		if i < len(widths)-1 {
+			line.WriteString(strings.Repeat(" ", width-displayWidth(cell)+2))
+		}
+	}
+	fmt.Fprintln(output.out, line.String())
+}
+
+// colorize 为文本添加颜色
+func (output *ConsoleOutput) colorize(code, message string) string {
+	if !output.color {
+		return message
+	}
+	return "\033[" + code + "m" + message + "\033[0m"
+}
+
+// terminalWidth 获取终端宽度，优先使用 COLUMNS 环境变量
+func (output *ConsoleOutput) terminalWidth() int {
+	if output.width > 0 {
+		return output.width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultTerminalWidth
+}
+
+// ProgressBar 进度条
+//
+// 输出是终端时在同一行刷新，否则每前进 10% 输出一行，避免日志中出现大量控制字符。
+type ProgressBar struct {
+	out         io.Writer
+	total       int
+	current     int
+	width       int
+	redraw      bool
+	lastPercent int
+	mutex       sync.Mutex
+}
+
+// NewProgressBar 创建新的进度条
+func NewProgressBar(out io.Writer, total int) *ProgressBar {
+	return &ProgressBar{
+		out:    out,
+		total:  total,
+		width:  28,
+		redraw: isTerminal(out),
+	}
+}
+
+// Advance 前进指定步数，默认一步
+func (bar *ProgressBar) Advance(steps ...int) {
+	bar.mutex.Lock()
+	defer bar.mutex.Unlock()
+
+	step := 1
+	if len(steps) > 0 {
+		step = steps[0]
+	}
+	bar.current += step
+	if bar.current > bar.total {
+		bar.current = bar.total
+	}
+	bar.render()
+}
+
+// Finish 将进度设为 100% 并换行
+func (bar *ProgressBar) Finish() {
+	bar.mutex.Lock()
+	defer bar.mutex.Unlock()
+
+	bar.current = bar.total
+	bar.render()
+	if bar.redraw {
+		fmt.Fprintln(bar.out)
+	}
+}
+
+// Current 获取当前进度
+func (bar *ProgressBar) Current() int {
+	bar.mutex.Lock()
+	defer bar.mutex.Unlock()
+	return bar.current
+}
+
+// Percent 获取完成百分比
+func (bar *ProgressBar) Percent() int {
+	bar.mutex.Lock()
+	defer bar.mutex.Unlock()
+	return bar.percent()
+}
+
+// percent 计算完成百分比
+func (bar *ProgressBar) percent() int {
+	if bar.total <= 0 {
+		return 100
+	}
+	return bar.current * 100 / bar.total
+}
+
+// render 输出当前进度
+func (bar *ProgressBar) render() {
+	percent := bar.percent()
+	if !bar.redraw && (percent == bar.lastPercent || percent < 100 && percent < bar.lastPercent+10) {
+		return
+	}
+	bar.lastPercent = percent
+
+	filled := bar.width * percent / 100
+	digits := len(strconv.Itoa(bar.total))
+	line := fmt.Sprintf("%*d/%d [%s%s] %3d%%", digits, bar.current, bar.total,
+		strings.Repeat("=", filled), strings.Repeat("-", bar.width-filled), percent)
+
+	if bar.redraw {
+		fmt.Fprint(bar.out, "\r"+line)
+		return
+	}
+	fmt.Fprintln(bar.out, line)
+}
+
+// isTerminal 检查是否输出到终端
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// fitColumns 缩小最宽的列，直到表格不超过终端宽度
+func fitColumns(widths []int, maxWidth int) {
+	const minColumnWidth = 4
+
+	total := 2 * (len(widths) - 1)
+	for _, width := range widths {
+		total += width
+	}
+
+	for total > maxWidth {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// truncate 将文本截断到指定显示宽度，被截断时以省略号结尾
+func truncate(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+
+	var result strings.Builder
+	used := 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > width-1 {
+			break
+		}
+		result.WriteRune(r)
+		used += w
+	}
+	result.WriteString("…")
+	return result.String()
+}
+
+// displayWidth 计算文本在终端中的显示宽度
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// runeWidth 计算字符的显示宽度，中日韩文字和全角符号占两列
+func runeWidth(r rune) int {
+	switch {
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1FAFF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
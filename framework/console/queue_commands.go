@@ -0,0 +1,207 @@
+package console
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"laravel-go/framework/cache"
+	"laravel-go/framework/queue"
+)
+
+// QueueRestartKey queue:restart 写入重启信号的缓存键
+const QueueRestartKey = "laravel-go:queue:restart"
+
+// QueueWorkCommand 在前台运行队列工作进程
+//
+// 收到 SIGINT、SIGTERM 或 queue:restart 发出的重启信号后停止领取新任务，
+// 等待当前任务处理完成后退出，由 supervisor 等进程管理工具重新启动。
+type QueueWorkCommand struct {
+	manager      *queue.Manager
+	store        cache.Store
+	output       Output
+	pollInterval time.Duration
+}
+
+// NewQueueWorkCommand 创建 queue:work 命令，store 用于读取重启信号，可以为 nil
+func NewQueueWorkCommand(manager *queue.Manager, store cache.Store, output Output) *QueueWorkCommand {
+	return &QueueWorkCommand{
+		manager:      manager,
+		store:        store,
+		output:       output,
+		pollInterval: 3 * time.Second,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *QueueWorkCommand) GetName() string {
+	return "queue:work"
+}
+
+// GetDescription 获取命令描述
+func (cmd *QueueWorkCommand) GetDescription() string {
+	return "Start processing jobs on the queue"
+}
+
+// GetSignature 获取命令签名
+func (cmd *QueueWorkCommand) GetSignature() string {
+	return "queue:work [--queue=] [--tries=] [--timeout=] [--sleep=]"
+}
+
+// GetArguments 获取命令参数
+func (cmd *QueueWorkCommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *QueueWorkCommand) GetOptions() []Option {
+	return []Option{
+		{
+			Name:        "queue",
+			Description: "The queue to work, defaults to the manager's default queue",
+			Type:        "string",
+		},
+		{
+			Name:        "tries",
+			Description: "Number of times to attempt a job before failing it",
+			Default:     3,
+			Type:        "int",
+		},
+		{
+			Name:        "timeout",
+			Description: "Number of seconds a job may run",
+			Default:     60,
+			Type:        "int",
+		},
+		{
+			Name:        "sleep",
+			Description: "Number of seconds to sleep when no job is available",
+			Default:     3,
+			Type:        "int",
+		},
+	}
+}
+
+// Execute 执行命令
+func (cmd *QueueWorkCommand) Execute(input Input) error {
+	queueName, _ := input.GetOption("queue").(string)
+	q, err := cmd.manager.GetQueue(queueName)
+	if err != nil {
+		return fmt.Errorf("failed to get queue %q: %w", queueName, err)
+	}
+
+	worker := queue.NewWorker(q, queueName)
+	if tries, ok := input.GetOption("tries").(int); ok && tries > 0 {
+		worker.SetMaxAttempts(tries)
+	}
+	timeout := 60 * time.Second
+	if seconds, ok := input.GetOption("timeout").(int); ok && seconds > 0 {
+		timeout = time.Duration(seconds) * time.Second
+	}
+	worker.SetTimeout(timeout)
+	if sleep, ok := input.GetOption("sleep").(int); ok && sleep > 0 {
+		worker.SetSleep(time.Duration(sleep) * time.Second)
+	}
+
+	// 只响应启动之后发出的重启信号
+	lastRestart := cmd.restartSignal()
+
+	if err := worker.Start(); err != nil {
+		return fmt.Errorf("failed to start worker: %w", err)
+	}
+	cmd.output.Info("Queue worker started, press Ctrl+C to stop")
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+
+	ticker := time.NewTicker(cmd.pollInterval)
+	defer ticker.Stop()
+
+wait:
+	for {
+		select {
+		case <-quit:
+			break wait
+		case <-ticker.C:
+			if cmd.restartSignal() != lastRestart {
+				cmd.output.Info("Restart signal received")
+				break wait
+			}
+		}
+	}
+
+	// 等待当前任务处理完成
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := worker.Drain(ctx); err != nil {
+		return fmt.Errorf("failed to stop worker gracefully: %w", err)
+	}
+	cmd.output.Success("Queue worker stopped")
+	return nil
+}
+
+// restartSignal 读取最近一次 queue:restart 的时间，没有信号时返回空字符串
+func (cmd *QueueWorkCommand) restartSignal() string {
+	if cmd.store == nil {
+		return ""
+	}
+	value, err := cmd.store.GetString(QueueRestartKey)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// QueueRestartCommand 通知所有工作进程在处理完当前任务后重启
+type QueueRestartCommand struct {
+	store  cache.Store
+	output Output
+}
+
+// NewQueueRestartCommand 创建 queue:restart 命令，store 需要与工作进程使用同一个缓存
+func NewQueueRestartCommand(store cache.Store, output Output) *QueueRestartCommand {
+	return &QueueRestartCommand{
+		store:  store,
+		output: output,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *QueueRestartCommand) GetName() string {
+	return "queue:restart"
+}
+
+// GetDescription 获取命令描述
+func (cmd *QueueRestartCommand) GetDescription() string {
+	return "Restart queue worker processes after their current job"
+}
+
+// GetSignature 获取命令签名
+func (cmd *QueueRestartCommand) GetSignature() string {
+	return "queue:restart"
+}
+
+// GetArguments 获取命令参数
+func (cmd *QueueRestartCommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *QueueRestartCommand) GetOptions() []Option {
+	return []Option{}
+}
+
+// Execute 执行命令
+func (cmd *QueueRestartCommand) Execute(input Input) error {
+	value := strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := cmd.store.SetString(QueueRestartKey, value, 0); err != nil {
+		return fmt.Errorf("failed to broadcast restart signal: %w", err)
+	}
+	cmd.output.Success("Broadcasting queue restart signal")
+	return nil
+}
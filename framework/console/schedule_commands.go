@@ -0,0 +1,122 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"laravel-go/framework/scheduler"
+)
+
+// ScheduleRunCommand 运行到期的计划任务后退出，由系统 cron 每分钟调用
+type ScheduleRunCommand struct {
+	scheduler scheduler.DueRunner
+	output    Output
+}
+
+// NewScheduleRunCommand 创建 schedule:run 命令
+func NewScheduleRunCommand(s scheduler.DueRunner, output Output) *ScheduleRunCommand {
+	return &ScheduleRunCommand{
+		scheduler: s,
+		output:    output,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *ScheduleRunCommand) GetName() string {
+	return "schedule:run"
+}
+
+// GetDescription 获取命令描述
+func (cmd *ScheduleRunCommand) GetDescription() string {
+	return "Run the scheduled tasks that are due"
+}
+
+// GetSignature 获取命令签名
+func (cmd *ScheduleRunCommand) GetSignature() string {
+	return "schedule:run"
+}
+
+// GetArguments 获取命令参数
+func (cmd *ScheduleRunCommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *ScheduleRunCommand) GetOptions() []Option {
+	return []Option{}
+}
+
+// Execute 执行命令
+func (cmd *ScheduleRunCommand) Execute(input Input) error {
+	count, err := cmd.scheduler.RunDue()
+	if err != nil {
+		return fmt.Errorf("failed to run scheduled tasks: %w", err)
+	}
+
+	if count == 0 {
+		cmd.output.Info("No scheduled tasks are due")
+		return nil
+	}
+	cmd.output.Success(fmt.Sprintf("Ran %d scheduled task(s)", count))
+	return nil
+}
+
+// ScheduleWorkCommand 在前台运行调度循环，收到 SIGINT 或 SIGTERM 后停止
+type ScheduleWorkCommand struct {
+	scheduler scheduler.Scheduler
+	output    Output
+}
+
+// NewScheduleWorkCommand 创建 schedule:work 命令
+func NewScheduleWorkCommand(s scheduler.Scheduler, output Output) *ScheduleWorkCommand {
+	return &ScheduleWorkCommand{
+		scheduler: s,
+		output:    output,
+	}
+}
+
+// GetName 获取命令名称
+func (cmd *ScheduleWorkCommand) GetName() string {
+	return "schedule:work"
+}
+
+// GetDescription 获取命令描述
+func (cmd *ScheduleWorkCommand) GetDescription() string {
+	return "Start the schedule worker in the foreground"
+}
+
+// GetSignature 获取命令签名
+func (cmd *ScheduleWorkCommand) GetSignature() string {
+	return "schedule:work"
+}
+
+// GetArguments 获取命令参数
+func (cmd *ScheduleWorkCommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *ScheduleWorkCommand) GetOptions() []Option {
+	return []Option{}
+}
+
+// Execute 执行命令
+func (cmd *ScheduleWorkCommand) Execute(input Input) error {
+	if err := cmd.scheduler.Start(); err != nil {
+		return fmt.Errorf("failed to start scheduler: %w", err)
+	}
+	cmd.output.Info("Schedule worker started, press Ctrl+C to stop")
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+	<-quit
+
+	if err := cmd.scheduler.Stop(); err != nil {
+		return fmt.Errorf("failed to stop scheduler: %w", err)
+	}
+	cmd.output.Success("Schedule worker stopped")
+	return nil
+}
@@ -0,0 +1,203 @@
+package console
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"laravel-go/framework/container"
+)
+
+// TinkerAction tinker 中可以调用的操作，args 为操作名称后以空格分隔的参数
+type TinkerAction func(c container.Container, args []string) (interface{}, error)
+
+// tinkerAction 已注册的操作
+type tinkerAction struct {
+	description string
+	handler     TinkerAction
+}
+
+// TinkerCommand 交互式调试命令
+//
+// Go 不支持运行时求值，tinker 只能调用预先注册的操作，例如 users.all、cache.get <key>。
+// 操作通过容器获取服务，结果为字符串时直接输出，否则以 JSON 格式输出。
+// 内置命令：help 列出操作，history 列出历史记录，!! 重复上一条命令，!n 重复第 n 条命令，exit 退出。
+type TinkerCommand struct {
+	container container.Container
+	output    Output
+	input     io.Reader
+	actions   map[string]tinkerAction
+	history   []string
+}
+
+// NewTinkerCommand 创建 tinker 命令
+func NewTinkerCommand(c container.Container, output Output) *TinkerCommand {
+	return &TinkerCommand{
+		container: c,
+		output:    output,
+		input:     os.Stdin,
+		actions:   make(map[string]tinkerAction),
+	}
+}
+
+// Register 注册操作
+func (cmd *TinkerCommand) Register(name, description string, action TinkerAction) {
+	cmd.actions[name] = tinkerAction{description: description, handler: action}
+}
+
+// SetInput 设置输入来源，默认为标准输入
+func (cmd *TinkerCommand) SetInput(input io.Reader) {
+	cmd.input = input
+}
+
+// History 获取历史记录
+func (cmd *TinkerCommand) History() []string {
+	return cmd.history
+}
+
+// GetName 获取命令名称
+func (cmd *TinkerCommand) GetName() string {
+	return "tinker"
+}
+
+// GetDescription 获取命令描述
+func (cmd *TinkerCommand) GetDescription() string {
+	return "Interact with your application"
+}
+
+// GetSignature 获取命令签名
+func (cmd *TinkerCommand) GetSignature() string {
+	return "tinker"
+}
+
+// GetArguments 获取命令参数
+func (cmd *TinkerCommand) GetArguments() []Argument {
+	return []Argument{}
+}
+
+// GetOptions 获取命令选项
+func (cmd *TinkerCommand) GetOptions() []Option {
+	return []Option{}
+}
+
+// Execute 执行命令，读取到 exit 或输入结束时退出
+func (cmd *TinkerCommand) Execute(input Input) error {
+	cmd.output.Info("Laravel-Go tinker, type 'help' for available actions, 'exit' to quit")
+
+	scanner := bufio.NewScanner(cmd.input)
+	for {
+		cmd.output.Write(">>> ")
+		if !scanner.Scan() {
+			cmd.output.WriteLine("")
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "exit" || line == "quit" {
+			return nil
+		}
+
+		line, ok := cmd.expandHistory(line)
+		if !ok {
+			continue
+		}
+		cmd.history = append(cmd.history, line)
+		cmd.eval(line)
+	}
+}
+
+// expandHistory 展开 !! 和 !n 历史引用
+func (cmd *TinkerCommand) expandHistory(line string) (string, bool) {
+	if !strings.HasPrefix(line, "!") {
+		return line, true
+	}
+
+	index := len(cmd.history)
+	if line != "!!" {
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			cmd.output.Error(fmt.Sprintf("Invalid history reference: %s", line))
+			return "", false
+		}
+		index = n
+	}
+	if index < 1 || index > len(cmd.history) {
+		cmd.output.Error(fmt.Sprintf("No such history entry: %s", line))
+		return "", false
+	}
+
+	line = cmd.history[index-1]
+	cmd.output.WriteLine(line)
+	return line, true
+}
+
+// eval 执行一行输入
+func (cmd *TinkerCommand) eval(line string) {
+	fields := strings.Fields(line)
+	name, args := fields[0], fields[1:]
+
+	switch name {
+	case "help":
+		cmd.showActions()
+		return
+	case "history":
+		for i, entry := range cmd.history {
+			cmd.output.WriteLine(fmt.Sprintf("%4d  %s", i+1, entry))
+		}
+		return
+	}
+
+	action, exists := cmd.actions[name]
+	if !exists {
+		cmd.output.Error(fmt.Sprintf("Unknown action: %s", name))
+		return
+	}
+
+	result, err := action.handler(cmd.container, args)
+	if err != nil {
+		cmd.output.Error(err.Error())
+		return
+	}
+	cmd.output.WriteLine(formatTinkerResult(result))
+}
+
+// showActions 列出已注册的操作
+func (cmd *TinkerCommand) showActions() {
+	names := make([]string, 0, len(cmd.actions))
+	for name := range cmd.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([][]string, len(names))
+	for i, name := range names {
+		rows[i] = []string{name, cmd.actions[name].description}
+	}
+	cmd.output.Table([]string{"Action", "Description"}, rows)
+}
+
+// formatTinkerResult 格式化操作结果
+func formatTinkerResult(result interface{}) string {
+	switch value := result.(type) {
+	case nil:
+		return "null"
+	case string:
+		return value
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", result)
+	}
+	return string(data)
+}
@@ -3,6 +3,7 @@ package container
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 )
 
@@ -12,11 +13,17 @@ type Container interface {
 	Bind(abstract interface{}, concrete interface{})
 	BindSingleton(abstract interface{}, concrete interface{})
 	BindCallback(abstract interface{}, callback func(Container) interface{})
+	Singleton(abstract interface{}, concrete interface{})
 
 	// 解析服务
 	Make(abstract interface{}) interface{}
+	Get(abstract interface{}) (interface{}, error)
 	Resolve(abstract interface{}) error
 
+	// 服务提供者
+	Register(providers ...ServiceProvider)
+	Boot()
+
 	// 检查服务是否存在
 	Has(abstract interface{}) bool
 
@@ -38,7 +45,9 @@ type container struct {
 	bindings   map[reflect.Type]*binding
 	singletons map[reflect.Type]interface{}
 	instances  map[reflect.Type]interface{}
-	resolving  map[reflect.Type]bool
+	resolving  []reflect.Type
+	providers  []ServiceProvider
+	booted     bool
 	mutex      sync.RWMutex
 }
 
@@ -55,10 +64,18 @@ func NewContainer() Container {
 		bindings:   make(map[reflect.Type]*binding),
 		singletons: make(map[reflect.Type]interface{}),
 		instances:  make(map[reflect.Type]interface{}),
-		resolving:  make(map[reflect.Type]bool),
 	}
 }
 
+// abstractTypeOf 获取服务的键类型，指向接口的指针使用接口类型
+func abstractTypeOf(abstract interface{}) reflect.Type {
+	abstractType := reflect.TypeOf(abstract)
+	if abstractType != nil && abstractType.Kind() == reflect.Ptr && abstractType.Elem().Kind() == reflect.Interface {
+		abstractType = abstractType.Elem()
+	}
+	return abstractType
+}
+
 // Bind 注册服务
 func (c *container) Bind(abstract interface{}, concrete interface{}) {
 	c.mutex.Lock()
@@ -107,38 +124,133 @@ func (c *container) BindCallback(abstract interface{}, callback func(Container)
 	}
 }
 
-// Make 解析服务
+// Singleton 注册单例服务，BindSingleton 的别名
+func (c *container) Singleton(abstract interface{}, concrete interface{}) {
+	c.BindSingleton(abstract, concrete)
+}
+
+// Make 解析服务，解析失败时 panic
 func (c *container) Make(abstract interface{}) interface{} {
-	abstractType := reflect.TypeOf(abstract)
-	if abstractType.Kind() == reflect.Ptr && abstractType.Elem().Kind() == reflect.Interface {
-		// 如果 abstract 是指向接口的指针，使用接口类型
-		abstractType = abstractType.Elem()
+	instance, err := c.Get(abstract)
+	if err != nil {
+		panic(err.Error())
+	}
+	return instance
+}
+
+// Get 解析服务并返回错误
+func (c *container) Get(abstract interface{}) (interface{}, error) {
+	abstractType := abstractTypeOf(abstract)
+	instance, err := c.get(abstractType)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve %v: %v", abstractType, err)
 	}
+	return instance, nil
+}
 
+// get 解析服务，复用已解析的实例和单例
+func (c *container) get(abstractType reflect.Type) (interface{}, error) {
+	c.mutex.RLock()
 	// 检查是否已经解析过
 	if instance, exists := c.instances[abstractType]; exists {
-		return instance
+		c.mutex.RUnlock()
+		return instance, nil
 	}
 
 	// 检查单例
 	if singleton, exists := c.singletons[abstractType]; exists {
-		return singleton
+		c.mutex.RUnlock()
+		return singleton, nil
 	}
+	c.mutex.RUnlock()
 
 	// 解析服务
 	instance, err := c.resolve(abstractType)
 	if err != nil {
-		panic(fmt.Sprintf("Unable to resolve %v: %v", abstractType, err))
+		return nil, err
 	}
 
 	// 如果是单例，保存实例
 	c.mutex.Lock()
+	defer c.mutex.Unlock()
 	if binding, exists := c.bindings[abstractType]; exists && binding.shared {
+		// 并发解析时保留先保存的实例
+		if singleton, exists := c.singletons[abstractType]; exists {
+			return singleton, nil
+		}
 		c.singletons[abstractType] = instance
 	}
+
+	return instance, nil
+}
+
+// Make 按类型从容器解析服务
+//
+//	service, err := container.Make[UserService](c)
+//
+// T 为接口时按接口绑定解析，其他类型按 T 本身解析（通常是结构体指针）
+func Make[T any](c Container) (T, error) {
+	var zero T
+	targetType := reflect.TypeOf((*T)(nil)).Elem()
+
+	var abstract interface{} = zero
+	if targetType.Kind() == reflect.Interface {
+		abstract = (*T)(nil)
+	}
+
+	instance, err := c.Get(abstract)
+	if err != nil {
+		return zero, err
+	}
+
+	result, ok := instance.(T)
+	if !ok {
+		return zero, fmt.Errorf("resolved %T is not %v", instance, targetType)
+	}
+	return result, nil
+}
+
+// MustMake 按类型从容器解析服务，解析失败时 panic
+func MustMake[T any](c Container) T {
+	result, err := Make[T](c)
+	if err != nil {
+		panic(err.Error())
+	}
+	return result
+}
+
+// Register 注册服务提供者，容器已启动时立即启动新的提供者
+func (c *container) Register(providers ...ServiceProvider) {
+	for _, provider := range providers {
+		provider.Register(c)
+
+		c.mutex.Lock()
+		c.providers = append(c.providers, provider)
+		booted := c.booted
+		c.mutex.Unlock()
+
+		if booted {
+			provider.Boot(c)
+		}
+	}
+}
+
+// Boot 启动所有已注册的服务提供者，只执行一次
+// 所有提供者注册完成后再启动，Boot 中可以解析其他提供者注册的服务
+func (c *container) Boot() {
+	c.mutex.Lock()
+	if c.booted {
+		c.mutex.Unlock()
+		return
+	}
+	c.booted = true
+	providers := make([]ServiceProvider, len(c.providers))
+	copy(providers, c.providers)
 	c.mutex.Unlock()
 
-	return instance
+	for _, provider := range providers {
+		provider.Boot(c)
+	}
 }
 
 // Resolve 解析服务并返回错误
@@ -180,7 +292,7 @@ func (c *container) Call(callback interface{}, parameters ...interface{}) ([]int
 			args[i] = reflect.ValueOf(parameters[i])
 		} else {
 			// 从容器解析依赖
-			instance, err := c.resolve(paramType)
+			instance, err := c.get(paramType)
 			if err != nil {
 				return nil, fmt.Errorf("unable to resolve dependency %v: %v", paramType, err)
 			}
@@ -241,22 +353,34 @@ func (c *container) resolve(abstractType reflect.Type) (interface{}, error) {
 	c.mutex.Lock()
 
 	// 检查循环依赖
-	if c.resolving[abstractType] {
-		c.mutex.Unlock()
-		return nil, fmt.Errorf("circular dependency detected for %v", abstractType)
+	for i, resolving := range c.resolving {
+		if resolving == abstractType {
+			chain := make([]string, 0, len(c.resolving)-i+1)
+			for _, t := range c.resolving[i:] {
+				chain = append(chain, t.String())
+			}
+			chain = append(chain, abstractType.String())
+			c.mutex.Unlock()
+			return nil, fmt.Errorf("circular dependency detected: %s", strings.Join(chain, " -> "))
+		}
 	}
 
-	c.resolving[abstractType] = true
+	c.resolving = append(c.resolving, abstractType)
+	binding, exists := c.bindings[abstractType]
 	c.mutex.Unlock()
 
 	defer func() {
 		c.mutex.Lock()
-		delete(c.resolving, abstractType)
+		for i := len(c.resolving) - 1; i >= 0; i-- {
+			if c.resolving[i] == abstractType {
+				c.resolving = append(c.resolving[:i], c.resolving[i+1:]...)
+				break
+			}
+		}
 		c.mutex.Unlock()
 	}()
 
 	// 查找绑定
-	binding, exists := c.bindings[abstractType]
 	if !exists {
 		// 尝试直接实例化
 		return c.build(abstractType)
@@ -274,18 +398,33 @@ func (c *container) resolve(abstractType reflect.Type) (interface{}, error) {
 			return binding.concrete, nil
 		}
 
-		// 如果是函数，调用它
-		results := reflect.ValueOf(binding.concrete).Call(nil)
-		if len(results) > 0 {
-			return results[0].Interface(), nil
-		}
-		return nil, nil
+		// 如果是函数，作为构造函数调用并注入参数
+		return c.construct(binding.concrete)
 	}
 
 	// 尝试直接实例化
 	return c.build(abstractType)
 }
 
+// construct 调用构造函数，参数从容器解析
+// 构造函数可以返回 (T) 或 (T, error)
+func (c *container) construct(constructor interface{}) (interface{}, error) {
+	results, err := c.Call(constructor)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	if len(results) > 1 {
+		if err, ok := results[len(results)-1].(error); ok && err != nil {
+			return nil, err
+		}
+	}
+	return results[0], nil
+}
+
 // build 构建实例
 func (c *container) build(concreteType reflect.Type) (interface{}, error) {
 	// 检查是否是接口
@@ -338,7 +477,7 @@ func (c *container) injectDependencies(instance interface{}) error {
 				if fieldType.Kind() == reflect.Interface {
 					// 对于接口类型，尝试从容器解析
 					var err error
-					dependency, err = c.resolve(fieldType)
+					dependency, err = c.get(fieldType)
 					if err != nil {
 						// 如果解析失败，跳过这个字段
 						continue
@@ -1,6 +1,7 @@
 package container
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -125,3 +126,105 @@ func TestBindCallback(t *testing.T) {
 		t.Fatalf("Expected name 'callback', got '%s'", resultService.Name)
 	}
 }
+
+// Greeter 测试接口
+type Greeter interface {
+	Greet() string
+}
+
+// TestGreeter 依赖 TestService 的实现
+type TestGreeter struct {
+	service *TestService
+}
+
+func (g *TestGreeter) Greet() string {
+	return "hello " + g.service.Name
+}
+
+// TestProvider 测试服务提供者
+type TestProvider struct {
+	booted bool
+}
+
+func (p *TestProvider) Register(c Container) {
+	c.Singleton((*TestService)(nil), func() *TestService {
+		return &TestService{Name: "provider"}
+	})
+	c.Bind((*Greeter)(nil), func(service *TestService) Greeter {
+		return &TestGreeter{service: service}
+	})
+}
+
+func (p *TestProvider) Boot(c Container) {
+	p.booted = true
+}
+
+func TestSingletonAndTransient(t *testing.T) {
+	container := NewContainer()
+
+	container.Singleton((*TestService)(nil), func() *TestService {
+		return &TestService{Name: "shared"}
+	})
+	container.Bind((*TestServiceWithDependency)(nil), func(service *TestService) *TestServiceWithDependency {
+		return &TestServiceWithDependency{Service: service}
+	})
+
+	first := MustMake[*TestServiceWithDependency](container)
+	second := MustMake[*TestServiceWithDependency](container)
+
+	// 瞬态服务每次创建新实例，注入的单例保持同一个
+	if first == second {
+		t.Fatal("Transient binding should create a new instance")
+	}
+	if first.Service != second.Service {
+		t.Fatal("Singleton dependency should be reused")
+	}
+	if first.Service.Name != "shared" {
+		t.Fatalf("Expected name 'shared', got '%s'", first.Service.Name)
+	}
+}
+
+func TestServiceProvider(t *testing.T) {
+	container := NewContainer()
+	provider := &TestProvider{}
+
+	container.Register(provider)
+	container.Boot()
+
+	if !provider.booted {
+		t.Fatal("Boot() should boot registered providers")
+	}
+
+	greeter, err := Make[Greeter](container)
+	if err != nil {
+		t.Fatalf("Make() should not return error: %v", err)
+	}
+	if greeting := greeter.Greet(); greeting != "hello provider" {
+		t.Fatalf("Expected 'hello provider', got '%s'", greeting)
+	}
+}
+
+// CircularA 循环依赖测试服务
+type CircularA struct{}
+
+// CircularB 循环依赖测试服务
+type CircularB struct{}
+
+func TestCircularDependency(t *testing.T) {
+	container := NewContainer()
+
+	container.Bind((*CircularA)(nil), func(b *CircularB) *CircularA {
+		return &CircularA{}
+	})
+	container.Bind((*CircularB)(nil), func(a *CircularA) *CircularB {
+		return &CircularB{}
+	})
+
+	_, err := Make[*CircularA](container)
+	if err == nil {
+		t.Fatal("Expected circular dependency error")
+	}
+	if !strings.Contains(err.Error(), "*container.CircularA -> *container.CircularB -> *container.CircularA") {
+		t.Fatalf("Expected dependency chain in error, got %v", err)
+	}
+}
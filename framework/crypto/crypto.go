@@ -0,0 +1,227 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// KeySize AES-256 密钥长度
+const KeySize = 32
+
+var (
+	// ErrMissingKey 未配置应用密钥
+	ErrMissingKey = errors.New("APP_KEY is not set")
+	// ErrDecryption 密文无效、被篡改或不是由已知密钥加密
+	ErrDecryption = errors.New("failed to decrypt value")
+)
+
+// Encrypter AES-256-GCM 加密器
+//
+// 密文为 base64 编码的 nonce+密文。解密时先使用当前密钥，失败后依次尝试旧密钥，
+// 因此轮换密钥后旧数据仍可读取，新数据始终使用当前密钥加密。
+type Encrypter struct {
+	keys  [][]byte
+	aeads []cipher.AEAD
+}
+
+// NewEncrypter 创建加密器，key 为当前密钥，previous 为轮换前的旧密钥，每个密钥必须为 32 字节
+func NewEncrypter(key []byte, previous ...[]byte) (*Encrypter, error) {
+	e := &Encrypter{}
+	for _, k := range append([][]byte{key}, previous...) {
+		if len(k) != KeySize {
+			return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(k))
+		}
+		block, err := aes.NewCipher(k)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		e.keys = append(e.keys, k)
+		e.aeads = append(e.aeads, aead)
+	}
+	return e, nil
+}
+
+// Encrypt 使用当前密钥加密
+func (e *Encrypter) Encrypt(plaintext string) (string, error) {
+	aead := e.aeads[0]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解密，依次尝试当前密钥和旧密钥，全部失败时返回 ErrDecryption
+func (e *Encrypter) Decrypt(ciphertext string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrDecryption
+	}
+	for _, aead := range e.aeads {
+		if len(data) < aead.NonceSize() {
+			continue
+		}
+		nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+		if plaintext, err := aead.Open(nil, nonce, sealed, nil); err == nil {
+			return string(plaintext), nil
+		}
+	}
+	return "", ErrDecryption
+}
+
+// Hash 使用当前密钥计算 HMAC-SHA256，返回十六进制字符串
+//
+// 用于令牌、签名等需要防篡改的值，不适合保存密码。
+func (e *Encrypter) Hash(value string) string {
+	return hex.EncodeToString(mac(e.keys[0], value))
+}
+
+// HashEquals 以固定时间比较 value 的 HMAC 和 hash，旧密钥计算的 hash 同样有效
+func (e *Encrypter) HashEquals(value, hash string) bool {
+	expected, err := hex.DecodeString(hash)
+	if err != nil {
+		return false
+	}
+	for _, key := range e.keys {
+		if hmac.Equal(mac(key, value), expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// Key 获取当前密钥
+func (e *Encrypter) Key() []byte {
+	return e.keys[0]
+}
+
+// mac 计算 HMAC-SHA256
+func mac(key []byte, value string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(value))
+	return h.Sum(nil)
+}
+
+// ParseKey 解析应用密钥，使用 base64: 前缀时解码，否则按原始字节使用
+func ParseKey(appKey string) ([]byte, error) {
+	if strings.HasPrefix(appKey, "base64:") {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(appKey, "base64:"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid application key: %w", err)
+		}
+		return decoded, nil
+	}
+	return []byte(appKey), nil
+}
+
+// GenerateKey 生成随机密钥，返回可写入 .env 的 base64: 格式
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	return "base64:" + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// FromEnv 使用 APP_KEY 和 APP_PREVIOUS_KEYS（逗号分隔）创建加密器
+func FromEnv() (*Encrypter, error) {
+	appKey := os.Getenv("APP_KEY")
+	if appKey == "" {
+		return nil, ErrMissingKey
+	}
+	key, err := ParseKey(appKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var previous [][]byte
+	for _, raw := range strings.Split(os.Getenv("APP_PREVIOUS_KEYS"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		old, err := ParseKey(raw)
+		if err != nil {
+			return nil, err
+		}
+		previous = append(previous, old)
+	}
+	return NewEncrypter(key, previous...)
+}
+
+var (
+	defaultEncrypter *Encrypter
+	defaultMutex     sync.Mutex
+)
+
+// SetDefault 设置全局加密器
+func SetDefault(encrypter *Encrypter) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultEncrypter = encrypter
+}
+
+// Default 获取全局加密器，未设置时根据环境变量创建（见 FromEnv）
+func Default() (*Encrypter, error) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	if defaultEncrypter == nil {
+		encrypter, err := FromEnv()
+		if err != nil {
+			return nil, err
+		}
+		defaultEncrypter = encrypter
+	}
+	return defaultEncrypter, nil
+}
+
+// Encrypt 使用全局加密器加密
+func Encrypt(plaintext string) (string, error) {
+	encrypter, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return encrypter.Encrypt(plaintext)
+}
+
+// Decrypt 使用全局加密器解密
+func Decrypt(ciphertext string) (string, error) {
+	encrypter, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return encrypter.Decrypt(ciphertext)
+}
+
+// Hash 使用全局加密器计算 HMAC
+func Hash(value string) (string, error) {
+	encrypter, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return encrypter.Hash(value), nil
+}
+
+// HashEquals 使用全局加密器比较 HMAC，未配置密钥时返回 false
+func HashEquals(value, hash string) bool {
+	encrypter, err := Default()
+	if err != nil {
+		return false
+	}
+	return encrypter.HashEquals(value, hash)
+}
@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+var (
+	currentKey  = bytes.Repeat([]byte("a"), KeySize)
+	previousKey = bytes.Repeat([]byte("b"), KeySize)
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	encrypter, err := NewEncrypter(currentKey)
+	if err != nil {
+		t.Fatalf("Failed to create encrypter: %v", err)
+	}
+
+	ciphertext, err := encrypter.Encrypt("s3cret value")
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	if ciphertext == "s3cret value" {
+		t.Fatal("Expected ciphertext to differ from plaintext")
+	}
+	// 每次加密使用随机 nonce
+	if again, _ := encrypter.Encrypt("s3cret value"); again == ciphertext {
+		t.Error("Expected different ciphertext for each encryption")
+	}
+
+	plaintext, err := encrypter.Decrypt(ciphertext)
+	if err != nil || plaintext != "s3cret value" {
+		t.Errorf("Expected round-trip to succeed, got %q, %v", plaintext, err)
+	}
+
+	if _, err := NewEncrypter([]byte("short")); err == nil {
+		t.Error("Expected error for invalid key length")
+	}
+}
+
+func TestTamperDetection(t *testing.T) {
+	encrypter, _ := NewEncrypter(currentKey)
+	ciphertext, _ := encrypter.Encrypt("amount=100")
+
+	data, _ := base64.StdEncoding.DecodeString(ciphertext)
+	data[len(data)-1] ^= 0x01
+	if _, err := encrypter.Decrypt(base64.StdEncoding.EncodeToString(data)); err != ErrDecryption {
+		t.Errorf("Expected ErrDecryption for tampered ciphertext, got %v", err)
+	}
+	if _, err := encrypter.Decrypt("not base64!"); err != ErrDecryption {
+		t.Errorf("Expected ErrDecryption for malformed ciphertext, got %v", err)
+	}
+
+	// 其他密钥加密的数据无法解密
+	other, _ := NewEncrypter(previousKey)
+	foreign, _ := other.Encrypt("amount=100")
+	if _, err := encrypter.Decrypt(foreign); err != ErrDecryption {
+		t.Errorf("Expected ErrDecryption for foreign key, got %v", err)
+	}
+
+	hash := encrypter.Hash("token-123")
+	if !encrypter.HashEquals("token-123", hash) {
+		t.Error("Expected hash to match")
+	}
+	if encrypter.HashEquals("token-124", hash) || encrypter.HashEquals("token-123", other.Hash("token-123")) {
+		t.Error("Expected hash mismatch")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	old, _ := NewEncrypter(previousKey)
+	ciphertext, _ := old.Encrypt("legacy")
+	hash := old.Hash("legacy")
+
+	rotated, err := NewEncrypter(currentKey, previousKey)
+	if err != nil {
+		t.Fatalf("Failed to create encrypter: %v", err)
+	}
+	if plaintext, err := rotated.Decrypt(ciphertext); err != nil || plaintext != "legacy" {
+		t.Errorf("Expected rotated key to decrypt old data, got %q, %v", plaintext, err)
+	}
+	if !rotated.HashEquals("legacy", hash) {
+		t.Error("Expected hash from previous key to match")
+	}
+
+	// 新数据使用当前密钥加密，旧密钥无法解密
+	fresh, _ := rotated.Encrypt("new")
+	if _, err := old.Decrypt(fresh); err != ErrDecryption {
+		t.Errorf("Expected new data to be encrypted with current key, got %v", err)
+	}
+
+	// 通过环境变量配置
+	t.Setenv("APP_KEY", "base64:"+base64.StdEncoding.EncodeToString(currentKey))
+	t.Setenv("APP_PREVIOUS_KEYS", "base64:"+base64.StdEncoding.EncodeToString(previousKey))
+	SetDefault(nil)
+	defer SetDefault(nil)
+	if plaintext, err := Decrypt(ciphertext); err != nil || plaintext != "legacy" {
+		t.Errorf("Expected default encrypter to use previous keys, got %q, %v", plaintext, err)
+	}
+}
@@ -0,0 +1,299 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"laravel-go/framework/crypto"
+)
+
+// Casts 属性类型转换约定
+//
+// 返回列名到类型的映射，支持 json、bool、int、float、string、datetime 和 encrypted。
+// 读取时把数据库值转换为 Go 值，保存时再转换回数据库可存储的值。
+type Casts interface {
+	Casts() map[string]string
+}
+
+// 访问器和修改器按列名的大驼峰形式查找，例如 first_name 对应
+// GetFirstNameAttribute(value interface{}) interface{} 和 SetFirstNameAttribute(value interface{}) interface{}。
+// 访问器在类型转换之后调用，修改器在类型转换之前调用。
+
+// Encrypter encrypted 类型转换使用的加密器
+type Encrypter interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+var (
+	attributeEncrypter Encrypter
+	encrypterMutex     sync.RWMutex
+)
+
+// SetEncrypter 设置 encrypted 类型转换使用的加密器，未设置时使用 crypto 包的全局加密器（APP_KEY）
+func SetEncrypter(encrypter Encrypter) {
+	encrypterMutex.Lock()
+	defer encrypterMutex.Unlock()
+	attributeEncrypter = encrypter
+}
+
+// getEncrypter 获取加密器
+func getEncrypter() (Encrypter, error) {
+	encrypterMutex.RLock()
+	encrypter := attributeEncrypter
+	encrypterMutex.RUnlock()
+	if encrypter != nil {
+		return encrypter, nil
+	}
+	encrypter, err := crypto.Default()
+	if err != nil {
+		return nil, err
+	}
+	return encrypter, nil
+}
+
+// attributeCaster 模型属性转换器
+type attributeCaster struct {
+	model reflect.Value
+	casts map[string]string
+}
+
+// newAttributeCaster 创建模型的属性转换器
+func newAttributeCaster(model interface{}) *attributeCaster {
+	caster := &attributeCaster{model: reflect.ValueOf(model)}
+	if c, ok := model.(Casts); ok {
+		caster.casts = c.Casts()
+	}
+	return caster
+}
+
+// get 将数据库值转换为字段值：先做类型转换，再调用访问器
+func (c *attributeCaster) get(column string, value interface{}, target reflect.Type) (interface{}, error) {
+	if castType, ok := c.casts[column]; ok {
+		casted, err := castFromDatabase(castType, value, target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cast %s: %w", column, err)
+		}
+		value = casted
+	}
+	return c.callAttributeMethod("Get"+studlyCase(column)+"Attribute", value), nil
+}
+
+// set 将字段值转换为数据库值：先调用修改器，再做类型转换
+func (c *attributeCaster) set(column string, value interface{}) (interface{}, error) {
+	value = c.callAttributeMethod("Set"+studlyCase(column)+"Attribute", value)
+	if castType, ok := c.casts[column]; ok {
+		casted, err := castToDatabase(castType, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cast %s: %w", column, err)
+		}
+		value = casted
+	}
+	return value, nil
+}
+
+// callAttributeMethod 调用访问器或修改器，方法不存在或签名不匹配时原样返回
+func (c *attributeCaster) callAttributeMethod(name string, value interface{}) interface{} {
+	if !c.model.IsValid() {
+		return value
+	}
+	method := c.model.MethodByName(name)
+	if !method.IsValid() {
+		return value
+	}
+
+	methodType := method.Type()
+	if methodType.NumIn() != 1 || methodType.NumOut() != 1 {
+		return value
+	}
+	arg := reflect.ValueOf(value)
+	if !arg.IsValid() {
+		arg = reflect.Zero(methodType.In(0))
+	}
+	if !arg.Type().AssignableTo(methodType.In(0)) {
+		if !arg.Type().ConvertibleTo(methodType.In(0)) {
+			return value
+		}
+		arg = arg.Convert(methodType.In(0))
+	}
+	return method.Call([]reflect.Value{arg})[0].Interface()
+}
+
+// Attributes 返回模型保存到数据库的列值，已应用修改器和类型转换
+func Attributes(model interface{}) (map[string]interface{}, error) {
+	return castAttributes(model, structToMap(model))
+}
+
+// castAttributes 对待保存的数据应用修改器和类型转换
+func castAttributes(model interface{}, data map[string]interface{}) (map[string]interface{}, error) {
+	caster := newAttributeCaster(model)
+	for column, value := range data {
+		casted, err := caster.set(column, value)
+		if err != nil {
+			return nil, err
+		}
+		data[column] = casted
+	}
+	return data, nil
+}
+
+// castFromDatabase 将数据库值转换为 Go 值，target 为 nil 时返回通用类型
+func castFromDatabase(castType string, value interface{}, target reflect.Type) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch strings.ToLower(castType) {
+	case "json", "array", "object":
+		return decodeJSONValue(rawString(value), target)
+	case "bool", "boolean":
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case int64:
+			return v != 0, nil
+		case float64:
+			return v != 0, nil
+		default:
+			return strconv.ParseBool(rawString(value))
+		}
+	case "int", "integer":
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case float64:
+			return int64(v), nil
+		default:
+			return strconv.ParseInt(rawString(value), 10, 64)
+		}
+	case "float", "double", "decimal":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		default:
+			return strconv.ParseFloat(rawString(value), 64)
+		}
+	case "string":
+		return rawString(value), nil
+	case "datetime", "date", "timestamp":
+		if t, ok := value.(time.Time); ok {
+			return t, nil
+		}
+		return parseDateTime(rawString(value))
+	case "encrypted":
+		encrypter, err := getEncrypter()
+		if err != nil {
+			return nil, err
+		}
+		plaintext, err := encrypter.Decrypt(rawString(value))
+		if err != nil {
+			return nil, err
+		}
+		if target == nil || target.Kind() == reflect.String {
+			return plaintext, nil
+		}
+		return decodeJSONValue(plaintext, target)
+	}
+	return nil, fmt.Errorf("unsupported cast type: %s", castType)
+}
+
+// castToDatabase 将 Go 值转换为数据库可存储的值
+func castToDatabase(castType string, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch strings.ToLower(castType) {
+	case "json", "array", "object":
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	case "bool", "boolean":
+		if b, ok := value.(bool); ok {
+			if b {
+				return 1, nil
+			}
+			return 0, nil
+		}
+		return value, nil
+	case "encrypted":
+		plaintext, ok := value.(string)
+		if !ok {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			plaintext = string(data)
+		}
+		encrypter, err := getEncrypter()
+		if err != nil {
+			return nil, err
+		}
+		return encrypter.Encrypt(plaintext)
+	case "int", "integer", "float", "double", "decimal", "string", "datetime", "date", "timestamp":
+		return value, nil
+	}
+	return nil, fmt.Errorf("unsupported cast type: %s", castType)
+}
+
+// decodeJSONValue 解码 JSON，target 为具体类型时解码到该类型
+func decodeJSONValue(data string, target reflect.Type) (interface{}, error) {
+	if target == nil || target.Kind() == reflect.Interface {
+		var value interface{}
+		if err := json.Unmarshal([]byte(data), &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	ptr := reflect.New(target)
+	if err := json.Unmarshal([]byte(data), ptr.Interface()); err != nil {
+		return nil, err
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// rawString 将数据库值转换为字符串
+func rawString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseDateTime 解析常见的日期时间格式
+func parseDateTime(value string) (time.Time, error) {
+	layouts := []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999-07:00", "2006-01-02 15:04:05", "2006-01-02"}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid datetime: %s", value)
+}
+
+// studlyCase 将列名转换为大驼峰形式
+func studlyCase(column string) string {
+	var builder strings.Builder
+	for _, part := range strings.Split(column, "_") {
+		if part == "" {
+			continue
+		}
+		builder.WriteString(strings.ToUpper(part[:1]))
+		builder.WriteString(part[1:])
+	}
+	return builder.String()
+}
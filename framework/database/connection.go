@@ -181,6 +181,11 @@ func (c *connection) Stats() sql.DBStats {
 	return c.db.Stats()
 }
 
+// Driver 获取连接的驱动类型
+func (c *connection) Driver() Driver {
+	return c.config.Driver
+}
+
 // buildDSN 构建数据库连接字符串
 func buildDSN(config *ConnectionConfig) (string, error) {
 	switch config.Driver {
@@ -287,6 +292,7 @@ type ConnectionManager struct {
 	// 添加健康检查和清理机制
 	healthTicker *time.Ticker
 	stopChan     chan struct{}
+	retryPolicy  RetryPolicy
 }
 
 // NewConnectionManager 创建连接管理器
@@ -296,6 +302,7 @@ func NewConnectionManager() *ConnectionManager {
 		configs:      make(map[string]*ConnectionConfig),
 		healthTicker: time.NewTicker(60 * time.Second), // 每分钟检查一次
 		stopChan:     make(chan struct{}),
+		retryPolicy:  DefaultRetryPolicy(),
 	}
 
 	// 启动健康检查协程
@@ -331,6 +338,10 @@ func (cm *ConnectionManager) checkConnectionsHealth() {
 		err := conn.PingContext(ctx)
 		cancel()
 
+		// 自动重连的连接在 Ping 时已经尝试过重连
+		if _, ok := conn.(*ReconnectingConnection); ok {
+			continue
+		}
 		if err != nil {
 			// 连接不健康，尝试重新连接
 			go cm.reconnectConnection(name)
@@ -364,6 +375,28 @@ func (cm *ConnectionManager) reconnectConnection(name string) {
 	cm.connections[name] = conn
 }
 
+// SetRetryPolicy 设置建立连接和断线重连时的重试策略
+func (cm *ConnectionManager) SetRetryPolicy(policy RetryPolicy) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	cm.retryPolicy = policy
+}
+
+// State 获取连接状态，尚未建立的连接为 disconnected
+func (cm *ConnectionManager) State(name string) ConnectionState {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+
+	conn, exists := cm.connections[name]
+	if !exists {
+		return StateDisconnected
+	}
+	if rc, ok := conn.(*ReconnectingConnection); ok {
+		return rc.State()
+	}
+	return StateConnected
+}
+
 // AddConnection 添加连接配置
 func (cm *ConnectionManager) AddConnection(name string, config *ConnectionConfig) {
 	cm.mutex.Lock()
@@ -386,7 +419,7 @@ func (cm *ConnectionManager) GetConnectionWithTimeout(name string, timeout time.
 	errChan := make(chan error, 1)
 
 	go func() {
-		conn, err := cm.getConnectionInternal(name)
+		conn, err := cm.getConnectionInternal(ctx, name)
 		if err != nil {
 			errChan <- err
 			return
@@ -405,11 +438,16 @@ func (cm *ConnectionManager) GetConnectionWithTimeout(name string, timeout time.
 }
 
 // getConnectionInternal 内部获取连接方法
-func (cm *ConnectionManager) getConnectionInternal(name string) (Connection, error) {
+func (cm *ConnectionManager) getConnectionInternal(ctx context.Context, name string) (Connection, error) {
 	cm.mutex.RLock()
 	conn, exists := cm.connections[name]
 	cm.mutex.RUnlock()
 
+	if rc, ok := conn.(*ReconnectingConnection); ok && exists {
+		// 自动重连的连接在使用时自行恢复
+		return rc, nil
+	}
+
 	if exists {
 		// 检查连接是否健康
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -441,7 +479,7 @@ func (cm *ConnectionManager) getConnectionInternal(name string) (Connection, err
 		return nil, errors.New("connection config not found: " + name)
 	}
 
-	conn, err := NewConnection(config)
+	conn, err := NewReconnectingConnection(ctx, config, cm.retryPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,17 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"laravel-go/framework/config"
+	"laravel-go/framework/event"
 )
 
 func TestNewConnection(t *testing.T) {
@@ -339,6 +346,76 @@ func TestTransaction(t *testing.T) {
 	}
 }
 
+func TestTransactionAfterCommit(t *testing.T) {
+	conn, err := NewConnection(&ConnectionConfig{
+		Driver:   SQLite,
+		Database: filepath.Join(t.TempDir(), "after_commit.db"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	dispatcher := event.NewEventDispatcher(nil)
+	defer dispatcher.Close()
+	manager := event.NewEventManager(dispatcher, nil)
+	var received []string
+	dispatcher.Listen("user.registered", event.NewListener("welcome", func(e event.Event) error {
+		received = append(received, e.GetPayload().(string))
+		return nil
+	}))
+
+	// 回滚的事务不分发缓存的事件
+	rollbackErr := errors.New("insert failed")
+	err = Transaction(context.Background(), conn, func(tx *Tx) error {
+		tx.Exec("INSERT INTO users (id, name) VALUES (?, ?)", 1, "alice")
+		manager.DispatchAfterCommit(tx, event.NewEvent("user.registered", "alice"))
+		return rollbackErr
+	})
+	if !errors.Is(err, rollbackErr) {
+		t.Fatalf("Expected transaction error, got %v", err)
+	}
+	if len(received) != 0 {
+		t.Errorf("Expected rolled back transaction to suppress events, got %v", received)
+	}
+
+	// 提交后按顺序分发
+	err = Transaction(context.Background(), conn, func(tx *Tx) error {
+		if _, err := tx.Exec("INSERT INTO users (id, name) VALUES (?, ?)", 2, "bob"); err != nil {
+			return err
+		}
+		manager.DispatchAfterCommit(tx, event.NewEvent("user.registered", "bob"))
+		manager.DispatchAfterCommit(tx, event.NewEvent("user.registered", "carol"))
+		if len(received) != 0 {
+			t.Error("Expected events to be buffered until commit")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+	if len(received) != 2 || received[0] != "bob" || received[1] != "carol" {
+		t.Errorf("Expected buffered events in order after commit, got %v", received)
+	}
+
+	// 回滚回调
+	tx, err := BeginTx(context.Background(), conn, nil)
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	rolledBack := false
+	tx.AfterRollback(func() { rolledBack = true })
+	manager.DispatchAfterCommit(tx, event.NewEvent("user.registered", "dave"))
+	tx.Rollback()
+	if !rolledBack || len(received) != 2 {
+		t.Errorf("Expected rollback callback only, got rolledBack=%v received=%v", rolledBack, received)
+	}
+}
+
 func BenchmarkConnectionCreation(b *testing.B) {
 	config := &ConnectionConfig{
 		Driver:   SQLite,
@@ -373,3 +450,99 @@ func BenchmarkConnectionManager(b *testing.B) {
 		conn.Ping()
 	}
 }
+
+// flakyConnection 查询时返回连接断开错误的连接
+type flakyConnection struct {
+	Connection
+}
+
+func (c *flakyConnection) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, driver.ErrBadConn
+}
+
+func TestConnectWithRetry(t *testing.T) {
+	config := &ConnectionConfig{
+		Driver:   SQLite,
+		Database: filepath.Join(t.TempDir(), "retry.db"),
+	}
+	policy := RetryPolicy{MaxAttempts: 5, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond, Multiplier: 2}
+
+	// 前 3 次失败，第 4 次成功
+	attempts := 0
+	connect := func(config *ConnectionConfig) (Connection, error) {
+		attempts++
+		if attempts <= 3 {
+			return nil, fmt.Errorf("dial tcp: connection refused")
+		}
+		return NewConnection(config)
+	}
+
+	conn, err := connectWithRetry(context.Background(), config, policy, connect)
+	if err != nil {
+		t.Fatalf("Expected connection to succeed after retries: %v", err)
+	}
+	defer conn.Close()
+	if attempts != 4 {
+		t.Errorf("Expected 4 attempts, got %d", attempts)
+	}
+
+	// 超过最大次数后放弃
+	attempts = 0
+	_, err = connectWithRetry(context.Background(), config, RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond}, func(*ConnectionConfig) (Connection, error) {
+		attempts++
+		return nil, fmt.Errorf("dial tcp: connection refused")
+	})
+	if err == nil || attempts != 2 {
+		t.Errorf("Expected failure after 2 attempts, got %d attempts and %v", attempts, err)
+	}
+}
+
+func TestReconnectingConnection(t *testing.T) {
+	config := &ConnectionConfig{
+		Driver:   SQLite,
+		Database: filepath.Join(t.TempDir(), "reconnect.db"),
+	}
+	policy := RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, Multiplier: 2}
+
+	// 第一个连接在查询时断开，重连后得到正常连接
+	connects := 0
+	connect := func(config *ConnectionConfig) (Connection, error) {
+		connects++
+		conn, err := NewConnection(config)
+		if err != nil {
+			return nil, err
+		}
+		if connects == 1 {
+			return &flakyConnection{Connection: conn}, nil
+		}
+		return conn, nil
+	}
+
+	conn, err := newReconnectingConnection(context.Background(), config, policy, connect)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	rows, err := conn.QueryContext(context.Background(), "SELECT 1")
+	if err != nil {
+		t.Fatalf("Expected query to succeed after reconnect: %v", err)
+	}
+	rows.Close()
+
+	if connects != 2 {
+		t.Errorf("Expected one reconnect, got %d connects", connects)
+	}
+	if conn.State() != StateConnected {
+		t.Errorf("Expected connected state, got %s", conn.State())
+	}
+
+	// 关闭后不再重连
+	conn.Close()
+	if err := conn.Ping(); err != ErrConnectionClosed {
+		t.Errorf("Expected closed connection error, got %v", err)
+	}
+	if connects != 2 {
+		t.Errorf("Closed connection should not reconnect, got %d connects", connects)
+	}
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"laravel-go/framework/event"
+)
+
+// 模型事件动作
+const (
+	ModelCreating = "creating"
+	ModelCreated  = "created"
+	ModelUpdating = "updating"
+	ModelUpdated  = "updated"
+	ModelDeleting = "deleting"
+	ModelDeleted  = "deleted"
+)
+
+// DispatchesEvents 模型事件开关，返回 false 的模型不会分发模型事件
+type DispatchesEvents interface {
+	DispatchesEvents() bool
+}
+
+var (
+	modelEventDispatcher event.Dispatcher
+	modelEventMutex      sync.RWMutex
+)
+
+// SetEventDispatcher 设置模型事件使用的分发器，传入 nil 时使用 event 包的全局分发器
+func SetEventDispatcher(dispatcher event.Dispatcher) {
+	modelEventMutex.Lock()
+	defer modelEventMutex.Unlock()
+	modelEventDispatcher = dispatcher
+}
+
+// ModelEventName 返回模型事件名称，格式为 model.<name>.<action>
+//
+// name 默认为结构体名的蛇形形式（BlogPost 为 blog_post），实现 ModelName() string 可以覆盖。
+func ModelEventName(model interface{}, action string) string {
+	return "model." + modelName(model) + "." + action
+}
+
+// modelName 获取模型名称
+func modelName(model interface{}) string {
+	if n, ok := model.(interface{ ModelName() string }); ok {
+		return n.ModelName()
+	}
+
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var builder strings.Builder
+	for i, r := range t.Name() {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// modelEventsEnabled 检查模型是否分发事件
+func modelEventsEnabled(model interface{}) bool {
+	if d, ok := model.(DispatchesEvents); ok {
+		return d.DispatchesEvents()
+	}
+	return true
+}
+
+// hasModelListeners 检查模型事件是否有监听器
+func hasModelListeners(model interface{}, actions ...string) bool {
+	if !modelEventsEnabled(model) {
+		return false
+	}
+
+	modelEventMutex.RLock()
+	dispatcher := modelEventDispatcher
+	modelEventMutex.RUnlock()
+
+	for _, action := range actions {
+		name := ModelEventName(model, action)
+		if dispatcher != nil && dispatcher.HasListeners(name) {
+			return true
+		}
+		if dispatcher == nil && event.HasListeners(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// fireModelEvent 分发模型事件，事件负载为模型本身，data 写入事件附加数据
+func fireModelEvent(model interface{}, action string, data map[string]interface{}) error {
+	if !modelEventsEnabled(model) {
+		return nil
+	}
+
+	e := event.NewEvent(ModelEventName(model, action), model)
+	e.SetData("model", modelName(model))
+	e.SetData("table", getTableName(model))
+	for key, value := range data {
+		e.SetData(key, value)
+	}
+
+	modelEventMutex.RLock()
+	dispatcher := modelEventDispatcher
+	modelEventMutex.RUnlock()
+
+	if dispatcher != nil {
+		return dispatcher.Dispatch(e)
+	}
+	if !event.HasListeners(e.GetName()) {
+		return nil
+	}
+	return event.Dispatch(e)
+}
@@ -0,0 +1,148 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"laravel-go/framework/errors"
+)
+
+// Column 数据表列信息
+type Column struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Nullable   bool   `json:"nullable"`
+	PrimaryKey bool   `json:"primary_key"`
+	Key        string `json:"key"` // 索引类型：PRI、UNI、MUL，没有索引时为空
+}
+
+// GoType 根据数据库类型推断对应的 Go 类型，可为空的日期时间列使用 *time.Time
+func (c Column) GoType() string {
+	t := strings.ToLower(c.Type)
+	switch {
+	case t == "tinyint(1)" || strings.HasPrefix(t, "bool") || t == "bit":
+		return "bool"
+	case strings.Contains(t, "int") || t == "serial" || t == "bigserial":
+		return "int64"
+	case strings.Contains(t, "float") || strings.Contains(t, "double") || strings.Contains(t, "real") ||
+		strings.Contains(t, "decimal") || strings.Contains(t, "numeric") || strings.Contains(t, "money"):
+		return "float64"
+	case strings.Contains(t, "date") || strings.Contains(t, "time"):
+		if c.Nullable {
+			return "*time.Time"
+		}
+		return "time.Time"
+	case strings.Contains(t, "blob") || strings.Contains(t, "binary") || t == "bytea" || t == "image":
+		return "[]byte"
+	}
+	return "string"
+}
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Introspect 读取数据表的列信息
+//
+// SQLite 使用 PRAGMA table_info，其他驱动查询 information_schema。
+// 连接无法识别驱动时按 SQLite 处理。数据表不存在时返回错误。
+func Introspect(conn Connection, table string) ([]Column, error) {
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name: %s", table)
+	}
+
+	var columns []Column
+	var err error
+	switch connectionDriver(conn) {
+	case MySQL:
+		columns, err = introspectInformationSchema(conn,
+			`SELECT column_name, column_type, is_nullable, column_key FROM information_schema.columns
+			WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position`, table)
+	case PostgreSQL:
+		columns, err = introspectInformationSchema(conn,
+			`SELECT c.column_name, c.data_type, c.is_nullable,
+				CASE WHEN EXISTS (
+					SELECT 1 FROM information_schema.table_constraints tc
+					JOIN information_schema.key_column_usage k ON k.constraint_name = tc.constraint_name AND k.table_schema = tc.table_schema
+					WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = c.table_schema
+						AND tc.table_name = c.table_name AND k.column_name = c.column_name
+				) THEN 'PRI' ELSE '' END
+			FROM information_schema.columns c
+			WHERE c.table_schema = current_schema() AND c.table_name = $1 ORDER BY c.ordinal_position`, table)
+	case SQLServer:
+		columns, err = introspectInformationSchema(conn,
+			`SELECT c.COLUMN_NAME, c.DATA_TYPE, c.IS_NULLABLE,
+				CASE WHEN EXISTS (
+					SELECT 1 FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+					JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE k ON k.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+					WHERE tc.CONSTRAINT_TYPE = 'PRIMARY KEY' AND tc.TABLE_NAME = c.TABLE_NAME AND k.COLUMN_NAME = c.COLUMN_NAME
+				) THEN 'PRI' ELSE '' END
+			FROM INFORMATION_SCHEMA.COLUMNS c
+			WHERE c.TABLE_NAME = @p1 ORDER BY c.ORDINAL_POSITION`, table)
+	default:
+		columns, err = introspectSQLite(conn, table)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to introspect table "+table)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s does not exist or has no columns", table)
+	}
+	return columns, nil
+}
+
+// introspectSQLite 使用 PRAGMA table_info 读取 SQLite 列信息
+func introspectSQLite(conn Connection, table string) ([]Column, error) {
+	rows, err := conn.Query(fmt.Sprintf(`PRAGMA table_info("%s")`, table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, colType    string
+			defaultValue     interface{}
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		column := Column{
+			Name:       name,
+			Type:       colType,
+			Nullable:   notNull == 0 && pk == 0,
+			PrimaryKey: pk > 0,
+		}
+		if column.PrimaryKey {
+			column.Key = "PRI"
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// introspectInformationSchema 从 information_schema 查询列信息，查询返回列名、类型、是否可为空和索引类型
+func introspectInformationSchema(conn Connection, query, table string) ([]Column, error) {
+	rows, err := conn.Query(query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var name, colType, nullable, key string
+		if err := rows.Scan(&name, &colType, &nullable, &key); err != nil {
+			return nil, err
+		}
+		columns = append(columns, Column{
+			Name:       name,
+			Type:       colType,
+			Nullable:   strings.EqualFold(nullable, "YES"),
+			PrimaryKey: key == "PRI",
+			Key:        key,
+		})
+	}
+	return columns, rows.Err()
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -211,6 +212,14 @@ func (mm *MigrationManager) GetNextBatchNumber() (int, error) {
 
 // RunMigrations 运行迁移
 func (mm *MigrationManager) RunMigrations() error {
+	return mm.RunMigrationsContext(context.Background())
+}
+
+// RunMigrationsContext 运行迁移，ctx 取消后停止执行后续迁移
+//
+// 正在执行的迁移完成时如果 ctx 已被取消，会调用 Down 撤销该迁移且不记录执行，
+// 已完成的迁移保持不变，返回的错误中包含已完成的数量。
+func (mm *MigrationManager) RunMigrationsContext(ctx context.Context) error {
 	// 确保迁移表存在
 	if err := mm.CreateMigrationTable(); err != nil {
 		return err
@@ -236,9 +245,16 @@ func (mm *MigrationManager) RunMigrations() error {
 	sort.Strings(versions)
 
 	// 执行未执行的迁移
+	var pending []string
 	for _, version := range versions {
-		if executed[version] {
-			continue
+		if !executed[version] {
+			pending = append(pending, version)
+		}
+	}
+
+	for completed, version := range pending {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("migration interrupted after %d of %d migrations: %w", completed, len(pending), err)
 		}
 
 		migration := mm.migrations[version]
@@ -256,6 +272,16 @@ func (mm *MigrationManager) RunMigrations() error {
 			return fmt.Errorf("failed to run migration %s: %w", version, err)
 		}
 
+		// 执行过程中被中断，撤销当前迁移
+		if err := ctx.Err(); err != nil {
+			tx.Rollback()
+			if downErr := migration.Down(mm.conn); downErr != nil {
+				return fmt.Errorf("migration %s interrupted and rollback failed: %v (%w)", version, downErr, err)
+			}
+			fmt.Printf("✗ Migration rolled back: %s\n", migration.GetName())
+			return fmt.Errorf("migration interrupted after %d of %d migrations: %w", completed, len(pending), err)
+		}
+
 		// 记录迁移执行
 		insertQuery := `
 			INSERT INTO migrations (version, name, description, batch)
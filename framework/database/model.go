@@ -200,8 +200,15 @@ func (m *Model) Save(conn Connection, model interface{}) error {
 			updatedAtField.Set(reflect.ValueOf(&now))
 		}
 
+		if err := fireModelEvent(model, ModelCreating, nil); err != nil {
+			return err
+		}
+
 		// 构建插入SQL
-		data := structToMap(model)
+		data, err := castAttributes(model, structToMap(model))
+		if err != nil {
+			return err
+		}
 		columns := make([]string, 0, len(data))
 		values := make([]interface{}, 0, len(data))
 		placeholders := make([]string, 0, len(data))
@@ -230,14 +237,30 @@ func (m *Model) Save(conn Connection, model interface{}) error {
 				pkField.SetInt(id)
 			}
 		}
+
+		if err := fireModelEvent(model, ModelCreated, nil); err != nil {
+			return err
+		}
 	} else {
 		// 更新记录
 		if updatedAtField.IsValid() {
 			updatedAtField.Set(reflect.ValueOf(&now))
 		}
 
+		// 有监听器时读取更新前的记录，随事件一起分发
+		var original map[string]interface{}
+		if hasModelListeners(model, ModelUpdating, ModelUpdated) {
+			original, _ = NewQueryBuilder(conn).Table(table).WithTrashed().WhereEq(pk, pkValue).First()
+		}
+		if err := fireModelEvent(model, ModelUpdating, map[string]interface{}{"original": original}); err != nil {
+			return err
+		}
+
 		// 构建更新SQL
-		data := structToMap(model)
+		data, err := castAttributes(model, structToMap(model))
+		if err != nil {
+			return err
+		}
 		sets := make([]string, 0, len(data))
 		values := make([]interface{}, 0, len(data))
 
@@ -252,10 +275,14 @@ func (m *Model) Save(conn Connection, model interface{}) error {
 		sqlStr := fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?",
 			table, strings.Join(sets, ", "), pk)
 
-		_, err := conn.Exec(sqlStr, values...)
+		_, err = conn.Exec(sqlStr, values...)
 		if err != nil {
 			return err
 		}
+
+		if err := fireModelEvent(model, ModelUpdated, map[string]interface{}{"original": original}); err != nil {
+			return err
+		}
 	}
 
 	// 调用 AfterSave 钩子
@@ -303,6 +330,10 @@ func (m *Model) Delete(conn Connection, model interface{}) error {
 		return errors.New("primary key field must be int or int64")
 	}
 
+	if err := fireModelEvent(model, ModelDeleting, nil); err != nil {
+		return err
+	}
+
 	if deletedAtField.IsValid() {
 		// 软删除：设置 deleted_at 字段
 		now := time.Now()
@@ -322,6 +353,10 @@ func (m *Model) Delete(conn Connection, model interface{}) error {
 		}
 	}
 
+	if err := fireModelEvent(model, ModelDeleted, map[string]interface{}{"id": pkValue, "soft_deleted": deletedAtField.IsValid()}); err != nil {
+		return err
+	}
+
 	// 调用 AfterDelete 钩子
 	return callHook(model, "AfterDelete", conn)
 }
@@ -586,6 +621,7 @@ func mapToStruct(data map[string]interface{}, dest interface{}) error {
 		return errors.New("dest must be a pointer")
 	}
 
+	caster := newAttributeCaster(dest)
 	destVal = destVal.Elem()
 	destType := destVal.Type()
 
@@ -609,10 +645,24 @@ func mapToStruct(data map[string]interface{}, dest interface{}) error {
 		}
 
 		if value, exists := data[dbTag]; exists && value != nil {
+			// 类型转换和访问器
+			value, err := caster.get(dbTag, value, field.Type())
+			if err != nil {
+				return err
+			}
+			if value == nil {
+				continue
+			}
+
 			// 设置字段值
 			fieldVal := reflect.ValueOf(value)
 			if fieldVal.Type().ConvertibleTo(field.Type()) {
 				field.Set(fieldVal.Convert(field.Type()))
+			} else if field.Kind() == reflect.Ptr && fieldVal.Type().ConvertibleTo(field.Type().Elem()) {
+				// 指针字段（如 *time.Time）
+				ptr := reflect.New(field.Type().Elem())
+				ptr.Elem().Set(fieldVal.Convert(field.Type().Elem()))
+				field.Set(ptr)
 			}
 		}
 	}
@@ -1,10 +1,16 @@
 package database
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
+
+	"laravel-go/framework/event"
 )
 
 // 测试模型结构体
@@ -879,3 +885,317 @@ func BenchmarkModelFind(b *testing.B) {
 		}
 	}
 }
+
+// 不分发模型事件的模型
+type QuietUser struct {
+	Model
+	Name string `db:"name"`
+}
+
+func (u *QuietUser) TableName() string {
+	return "quiet_users"
+}
+
+func (u *QuietUser) DispatchesEvents() bool {
+	return false
+}
+
+// 测试模型事件
+func TestModelEvents(t *testing.T) {
+	dispatcher := event.NewEventDispatcher(event.NewMemoryEventQueue())
+	defer dispatcher.Close()
+	SetEventDispatcher(dispatcher)
+	defer SetEventDispatcher(nil)
+
+	conn, err := NewConnection(&ConnectionConfig{
+		Driver:   SQLite,
+		Database: filepath.Join(t.TempDir(), "events.db"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer conn.Close()
+
+	for _, table := range []string{"users", "quiet_users"} {
+		_, err = conn.Exec(fmt.Sprintf(`CREATE TABLE %s (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			email TEXT,
+			age INTEGER,
+			created_at DATETIME,
+			updated_at DATETIME,
+			deleted_at DATETIME
+		)`, table))
+		if err != nil {
+			t.Fatalf("Failed to create table: %v", err)
+		}
+	}
+
+	var fired []event.Event
+	for _, action := range []string{ModelCreating, ModelCreated, ModelUpdated, ModelDeleted} {
+		dispatcher.Listen(ModelEventName(&User{}, action), event.NewListener("recorder", func(e event.Event) error {
+			fired = append(fired, e)
+			return nil
+		}))
+	}
+
+	model := &Model{}
+	user := &User{Name: "Event User", Email: "event@example.com", Age: 20}
+	if err := model.Save(conn, user); err != nil {
+		t.Fatalf("Failed to save user: %v", err)
+	}
+
+	if len(fired) != 2 || fired[0].GetName() != "model.user.creating" || fired[1].GetName() != "model.user.created" {
+		t.Fatalf("Expected creating and created events, got %v", fired)
+	}
+	created, ok := fired[1].GetPayload().(*User)
+	if !ok || created.ID == 0 || created.Name != "Event User" {
+		t.Errorf("Expected created event to carry the saved record, got %#v", fired[1].GetPayload())
+	}
+
+	user.Age = 21
+	if err := model.Save(conn, user); err != nil {
+		t.Fatalf("Failed to update user: %v", err)
+	}
+	original, _ := fired[2].GetDataByKey("original").(map[string]interface{})
+	if fired[2].GetName() != "model.user.updated" || original["age"] != int64(20) {
+		t.Errorf("Expected updated event with the original record, got %s %v", fired[2].GetName(), original)
+	}
+
+	if err := model.Delete(conn, user); err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+	if fired[3].GetName() != "model.user.deleted" || fired[3].GetDataByKey("id") != user.ID {
+		t.Errorf("Expected deleted event for user %d, got %s %v", user.ID, fired[3].GetName(), fired[3].GetData())
+	}
+
+	// 关闭事件的模型不分发事件
+	quiet := &QuietUser{Name: "Quiet"}
+	dispatcher.Listen(ModelEventName(quiet, ModelCreated), event.NewListener("quiet", func(e event.Event) error {
+		t.Error("Quiet model should not dispatch events")
+		return nil
+	}))
+	if err := model.Save(conn, quiet); err != nil {
+		t.Fatalf("Failed to save quiet user: %v", err)
+	}
+}
+
+// 带类型转换的模型
+type Account struct {
+	Model
+	Name     string                 `db:"name"`
+	Settings map[string]interface{} `db:"settings"`
+	IsActive bool                   `db:"is_active"`
+	Secret   string                 `db:"secret"`
+}
+
+func (a *Account) TableName() string {
+	return "accounts"
+}
+
+func (a *Account) Casts() map[string]string {
+	return map[string]string{
+		"settings":  "json",
+		"is_active": "bool",
+		"secret":    "encrypted",
+	}
+}
+
+// SetNameAttribute 保存前去掉首尾空格
+func (a *Account) SetNameAttribute(value interface{}) interface{} {
+	return strings.TrimSpace(value.(string))
+}
+
+// GetNameAttribute 读取时转换为大写
+func (a *Account) GetNameAttribute(value interface{}) interface{} {
+	return strings.ToUpper(value.(string))
+}
+
+// 测试属性类型转换和访问器/修改器
+func TestModelAttributeCasts(t *testing.T) {
+	t.Setenv("APP_KEY", "base64:"+base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+
+	conn, err := NewConnection(&ConnectionConfig{
+		Driver:   SQLite,
+		Database: filepath.Join(t.TempDir(), "casts.db"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Exec(`CREATE TABLE accounts (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT,
+		settings TEXT,
+		is_active TINYINT,
+		secret TEXT,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	model := &Model{}
+	account := &Account{
+		Name:     "  alice ",
+		Settings: map[string]interface{}{"theme": "dark", "limits": map[string]interface{}{"daily": float64(10)}},
+		IsActive: true,
+		Secret:   "s3cret",
+	}
+	if err := model.Save(conn, account); err != nil {
+		t.Fatalf("Failed to save account: %v", err)
+	}
+
+	// 数据库中保存的是转换后的值
+	raw, err := Raw(conn, "SELECT name, settings, is_active, secret FROM accounts WHERE id = ?", account.ID)
+	if err != nil || len(raw) != 1 {
+		t.Fatalf("Failed to read raw row: %v", err)
+	}
+	if raw[0]["name"] != "alice" || raw[0]["is_active"] != int64(1) || raw[0]["secret"] == "s3cret" {
+		t.Errorf("Unexpected stored row: %v", raw[0])
+	}
+	if !strings.Contains(raw[0]["settings"].(string), `"theme":"dark"`) {
+		t.Errorf("Expected settings to be stored as JSON, got %v", raw[0]["settings"])
+	}
+
+	// 读取时还原为 Go 值
+	loaded := &Account{}
+	if err := model.Find(conn, account.ID, loaded); err != nil {
+		t.Fatalf("Failed to find account: %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Settings, account.Settings) {
+		t.Errorf("Expected settings %v, got %v", account.Settings, loaded.Settings)
+	}
+	if !loaded.IsActive || loaded.Secret != "s3cret" || loaded.Name != "ALICE" {
+		t.Errorf("Unexpected loaded account: %+v", loaded)
+	}
+
+	// 0 转换为 false
+	if _, err := conn.Exec(`UPDATE accounts SET is_active = 0 WHERE id = ?`, account.ID); err != nil {
+		t.Fatalf("Failed to update account: %v", err)
+	}
+	loaded = &Account{}
+	if err := model.Find(conn, account.ID, loaded); err != nil {
+		t.Fatalf("Failed to find account: %v", err)
+	}
+	if loaded.IsActive {
+		t.Error("Expected is_active 0 to cast to false")
+	}
+
+	// 查询构建器结果同样可以应用类型转换
+	rows, err := NewQueryBuilder(conn).Table("accounts").WithCasts(map[string]string{"is_active": "bool", "settings": "json"}).Get()
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("Failed to query accounts: %v", err)
+	}
+	if rows[0]["is_active"] != false || rows[0]["settings"].(map[string]interface{})["theme"] != "dark" {
+		t.Errorf("Unexpected casted row: %v", rows[0])
+	}
+}
+
+func TestRepository(t *testing.T) {
+	dispatcher := event.NewEventDispatcher(event.NewMemoryEventQueue())
+	defer dispatcher.Close()
+	SetEventDispatcher(dispatcher)
+	defer SetEventDispatcher(nil)
+
+	conn, err := NewConnection(&ConnectionConfig{
+		Driver:   SQLite,
+		Database: filepath.Join(t.TempDir(), "repository.db"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.Exec(`CREATE TABLE users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		email TEXT,
+		age INTEGER,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	var fired []string
+	for _, action := range []string{ModelCreated, ModelUpdated, ModelDeleted} {
+		dispatcher.Listen(ModelEventName(&User{}, action), event.NewListener("recorder", func(e event.Event) error {
+			fired = append(fired, e.GetName())
+			return nil
+		}))
+	}
+
+	repo := NewRepository[User](conn)
+	for i, name := range []string{"Alice", "Bob", "Carol"} {
+		if err := repo.Create(&User{Name: name, Email: strings.ToLower(name) + "@example.com", Age: 20 + i*10}); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	alice, err := repo.Find(1)
+	if err != nil {
+		t.Fatalf("Failed to find user: %v", err)
+	}
+	if alice.Name != "Alice" || alice.CreatedAt == nil {
+		t.Errorf("Unexpected user: %+v", alice)
+	}
+
+	alice.Age = 21
+	if err := repo.Update(alice); err != nil {
+		t.Fatalf("Failed to update user: %v", err)
+	}
+	if err := repo.Create(alice); err == nil {
+		t.Error("Expected Create to reject an existing model")
+	}
+
+	older, err := repo.Where("age", ">", 25)
+	if err != nil {
+		t.Fatalf("Failed to query users: %v", err)
+	}
+	if len(older) != 2 || older[0].Name != "Bob" {
+		t.Errorf("Unexpected Where result: %+v", older)
+	}
+
+	if err := repo.Delete(2); err != nil {
+		t.Fatalf("Failed to delete user: %v", err)
+	}
+	all, err := repo.All()
+	if err != nil {
+		t.Fatalf("Failed to list users: %v", err)
+	}
+	if len(all) != 2 || all[0].Age != 21 {
+		t.Errorf("Expected soft-deleted user to be excluded, got %+v", all)
+	}
+	if trashed, _ := repo.Query().WithTrashed().Count(); trashed != 3 {
+		t.Errorf("Expected soft-deleted user to stay in the table, got %d rows", trashed)
+	}
+
+	page, err := repo.Paginate(2, 1)
+	if err != nil {
+		t.Fatalf("Failed to paginate users: %v", err)
+	}
+	users, ok := page["data"].([]User)
+	if !ok || len(users) != 1 || users[0].Name != "Carol" || page["total"] != int64(2) {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+
+	_, err = repo.Find(2)
+	var notFound *ModelNotFoundError
+	if !errors.As(err, &notFound) || notFound.ID != 2 || !IsModelNotFound(err) {
+		t.Errorf("Expected ModelNotFoundError for soft-deleted user, got %v", err)
+	}
+	if err := repo.Delete(99); !IsModelNotFound(err) {
+		t.Errorf("Expected ModelNotFoundError when deleting a missing user, got %v", err)
+	}
+
+	expected := []string{"model.user.created", "model.user.created", "model.user.created", "model.user.updated", "model.user.deleted"}
+	if !reflect.DeepEqual(fired, expected) {
+		t.Errorf("Expected events %v, got %v", expected, fired)
+	}
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SimplePaginate 简单分页，不统计总数
+//
+// 多查询一条记录判断是否还有下一页，适合大表或只需要"上一页/下一页"的场景。
+// 返回 data、per_page、current_page、has_more。
+func (qb *QueryBuilder) SimplePaginate(page, perPage int) (map[string]interface{}, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 15
+	}
+
+	data, err := qb.Offset((page - 1) * perPage).Limit(perPage + 1).Get()
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(data) > perPage
+	if hasMore {
+		data = data[:perPage]
+	}
+
+	return map[string]interface{}{
+		"data":         data,
+		"per_page":     perPage,
+		"current_page": page,
+		"has_more":     hasMore,
+	}, nil
+}
+
+// CursorPaginate 游标分页，按 column 排序并从 cursor 之后继续查询
+//
+// cursor 为上一页返回的 next_cursor，首页传空字符串。column 的值必须唯一，
+// 通常使用自增主键；已有 column 的降序排序时向更小的值翻页。column 必须是第一个排序列，
+// 未指定时自动插入到其他排序之前，其他排序只用于同值记录之间。
+// 翻页不使用 OFFSET，深分页的性能不会随页数下降。返回 data、per_page、next_cursor、has_more。
+func (qb *QueryBuilder) CursorPaginate(column, cursor string, perPage int) (map[string]interface{}, error) {
+	if perPage < 1 {
+		perPage = 15
+	}
+
+	// 游标条件只对第一排序列成立，排在前面的其他排序会导致跳过或重复记录
+	direction := "ASC"
+	position := -1
+	for i, order := range qb.orders {
+		if order.Column == column {
+			position = i
+			direction = order.Direction
+			break
+		}
+	}
+	switch {
+	case position < 0:
+		qb.orders = append([]OrderBy{{Column: column, Direction: direction}}, qb.orders...)
+	case position > 0:
+		return nil, fmt.Errorf("cursor column %s must be the first order by column", column)
+	}
+
+	if cursor != "" {
+		value, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		operator := ">"
+		if direction == "DESC" {
+			operator = "<"
+		}
+		qb.Where(column, operator, value)
+	}
+
+	data, err := qb.Limit(perPage + 1).Get()
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(data) > perPage
+	nextCursor := ""
+	if hasMore {
+		data = data[:perPage]
+
+		// 结果中的键不带表名前缀
+		key := column[strings.LastIndex(column, ".")+1:]
+		nextCursor, err = encodeCursor(data[len(data)-1][key])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		"data":        data,
+		"per_page":    perPage,
+		"next_cursor": nextCursor,
+		"has_more":    hasMore,
+	}, nil
+}
+
+// encodeCursor 将游标值编码为可以放在 URL 中的字符串
+func encodeCursor(value interface{}) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor 解码游标，整数游标还原为 int64
+func decodeCursor(cursor string) (interface{}, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		return v.Float64()
+	case string, bool:
+		return v, nil
+	}
+	return nil, fmt.Errorf("invalid cursor: unsupported value")
+}
@@ -26,6 +26,8 @@ type QueryBuilder struct {
 	lock       string
 	ctx        context.Context
 	withTrashed bool // 是否包含软删除的记录
+	err        error // 构建过程中的错误，执行查询时返回
+	casts      map[string]string // 扫描结果时应用的类型转换
 }
 
 // WhereCondition WHERE 条件
@@ -188,8 +190,9 @@ func (qb *QueryBuilder) WhereNotBetween(column string, min, max interface{}) *Qu
 	return qb.WhereRaw(fmt.Sprintf("%s NOT BETWEEN ? AND ?", column), min, max)
 }
 
-// WhereRaw 原始 WHERE 条件
+// WhereRaw 原始 WHERE 条件，支持 ? 位置参数和 :name 命名参数
 func (qb *QueryBuilder) WhereRaw(sql string, args ...interface{}) *QueryBuilder {
+	sql, args = qb.bindRaw(sql, args)
 	qb.wheres = append(qb.wheres, WhereCondition{
 		Raw:     true,
 		RawSQL:  sql,
@@ -212,6 +215,7 @@ func (qb *QueryBuilder) OrWhere(column string, operator string, value interface{
 
 // OrWhereRaw OR 原始 WHERE 条件
 func (qb *QueryBuilder) OrWhereRaw(sql string, args ...interface{}) *QueryBuilder {
+	sql, args = qb.bindRaw(sql, args)
 	qb.wheres = append(qb.wheres, WhereCondition{
 		Raw:     true,
 		RawSQL:  sql,
@@ -292,6 +296,7 @@ func (qb *QueryBuilder) Having(column string, operator string, value interface{}
 
 // HavingRaw 原始 HAVING 条件
 func (qb *QueryBuilder) HavingRaw(sql string, args ...interface{}) *QueryBuilder {
+	sql, args = qb.bindRaw(sql, args)
 	qb.having = append(qb.having, WhereCondition{
 		Raw:     true,
 		RawSQL:  sql,
@@ -347,11 +352,20 @@ func (qb *QueryBuilder) WithTrashed() *QueryBuilder {
 	return qb
 }
 
+// WithCasts 设置扫描结果时应用的类型转换，类型同模型的 Casts 约定
+func (qb *QueryBuilder) WithCasts(casts map[string]string) *QueryBuilder {
+	qb.casts = casts
+	return qb
+}
+
 // Get 执行查询并返回结果
 func (qb *QueryBuilder) Get() ([]map[string]interface{}, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
 	query, args := qb.buildSelectQuery()
 	
-	rows, err := qb.connection.QueryContext(qb.ctx, query, args...)
+	rows, err := qb.connection.QueryContext(qb.ctx, qb.rebind(query), args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to execute query")
 	}
@@ -381,6 +395,10 @@ func (qb *QueryBuilder) Find(id interface{}) (map[string]interface{}, error) {
 
 // Count 统计记录数
 func (qb *QueryBuilder) Count() (int64, error) {
+	if qb.err != nil {
+		return 0, qb.err
+	}
+	
 	// 保存原始查询
 	originalSelects := qb.selects
 	originalDistinct := qb.distinct
@@ -392,7 +410,7 @@ func (qb *QueryBuilder) Count() (int64, error) {
 	query, args := qb.buildSelectQuery()
 	
 	var count int64
-	err := qb.connection.QueryRowContext(qb.ctx, query, args...).Scan(&count)
+	err := qb.connection.QueryRowContext(qb.ctx, qb.rebind(query), args...).Scan(&count)
 	
 	// 恢复原始查询
 	qb.selects = originalSelects
@@ -445,6 +463,10 @@ func (qb *QueryBuilder) Max(column string) (float64, error) {
 
 // aggregate 聚合函数
 func (qb *QueryBuilder) aggregate(function, column string) (float64, error) {
+	if qb.err != nil {
+		return 0, qb.err
+	}
+	
 	// 保存原始查询
 	originalSelects := qb.selects
 	originalDistinct := qb.distinct
@@ -456,7 +478,7 @@ func (qb *QueryBuilder) aggregate(function, column string) (float64, error) {
 	query, args := qb.buildSelectQuery()
 	
 	var result sql.NullFloat64
-	err := qb.connection.QueryRowContext(qb.ctx, query, args...).Scan(&result)
+	err := qb.connection.QueryRowContext(qb.ctx, qb.rebind(query), args...).Scan(&result)
 	
 	// 恢复原始查询
 	qb.selects = originalSelects
@@ -498,6 +520,13 @@ func (qb *QueryBuilder) Paginate(page, perPage int) (map[string]interface{}, err
 	
 	// 计算分页信息
 	lastPage := int((total + int64(perPage) - 1) / int64(perPage))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+	from, to := 0, 0
+	if len(data) > 0 {
+		from, to = offset+1, offset+len(data)
+	}
 	
 	return map[string]interface{}{
 		"data":        data,
@@ -505,8 +534,8 @@ func (qb *QueryBuilder) Paginate(page, perPage int) (map[string]interface{}, err
 		"per_page":    perPage,
 		"current_page": page,
 		"last_page":   lastPage,
-		"from":        offset + 1,
-		"to":          offset + len(data),
+		"from":        from,
+		"to":          to,
 	}, nil
 }
 
@@ -673,6 +702,15 @@ func (qb *QueryBuilder) scanRows(rows *sql.Rows) ([]map[string]interface{}, erro
 		for i, column := range columns {
 			val := values[i]
 			
+			if castType, ok := qb.casts[column]; ok {
+				casted, err := castFromDatabase(castType, val, nil)
+				if err != nil {
+					return nil, errors.Wrap(err, fmt.Sprintf("failed to cast %s", column))
+				}
+				result[column] = casted
+				continue
+			}
+			
 			// 处理特殊类型
 			switch v := val.(type) {
 			case []byte:
@@ -1,6 +1,9 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -547,4 +550,256 @@ func BenchmarkQueryBuilder(b *testing.B) {
 		
 		_, _ = qb.buildSelectQuery()
 	}
-} 
\ No newline at end of file
+} 
+// recordingConnection 记录执行过的查询
+type recordingConnection struct {
+	Connection
+	queries []string
+}
+
+func (c *recordingConnection) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.queries = append(c.queries, query)
+	return c.Connection.QueryContext(ctx, query, args...)
+}
+
+func (c *recordingConnection) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.queries = append(c.queries, query)
+	return c.Connection.QueryRowContext(ctx, query, args...)
+}
+
+func TestQueryBuilderSimpleAndCursorPaginate(t *testing.T) {
+	config := &ConnectionConfig{
+		Driver:   SQLite,
+		Database: filepath.Join(t.TempDir(), "pagination.db"),
+	}
+	base, err := NewConnection(config)
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer base.Close()
+
+	if _, err := base.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY, title TEXT NOT NULL, deleted_at DATETIME)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := base.Exec(`INSERT INTO posts (id, title) VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')`); err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+	conn := &recordingConnection{Connection: base}
+
+	// 简单分页通过多查询一条判断是否还有下一页，不执行 COUNT
+	page, err := NewQueryBuilder(conn).Table("posts").OrderByAsc("id").SimplePaginate(2, 2)
+	if err != nil {
+		t.Fatalf("Failed to simple paginate: %v", err)
+	}
+	data := page["data"].([]map[string]interface{})
+	if len(data) != 2 || data[0]["id"] != int64(3) || page["has_more"] != true {
+		t.Errorf("Unexpected second page: %v", page)
+	}
+	for _, query := range conn.queries {
+		if strings.Contains(strings.ToUpper(query), "COUNT(") {
+			t.Errorf("Expected no COUNT query, got %s", query)
+		}
+	}
+
+	page, err = NewQueryBuilder(conn).Table("posts").OrderByAsc("id").SimplePaginate(3, 2)
+	if err != nil {
+		t.Fatalf("Failed to simple paginate: %v", err)
+	}
+	if data := page["data"].([]map[string]interface{}); len(data) != 1 || page["has_more"] != false {
+		t.Errorf("Unexpected last page: %v", page)
+	}
+
+	// 游标分页从上一页的游标继续
+	var ids []int64
+	cursor := ""
+	for i := 0; i < 5; i++ {
+		page, err := NewQueryBuilder(conn).Table("posts").CursorPaginate("id", cursor, 2)
+		if err != nil {
+			t.Fatalf("Failed to cursor paginate: %v", err)
+		}
+		for _, row := range page["data"].([]map[string]interface{}) {
+			ids = append(ids, row["id"].(int64))
+		}
+		if page["has_more"] != true {
+			if page["next_cursor"] != "" {
+				t.Errorf("Expected no cursor on the last page, got %v", page["next_cursor"])
+			}
+			break
+		}
+		cursor = page["next_cursor"].(string)
+	}
+	if len(ids) != 5 || ids[0] != 1 || ids[2] != 3 || ids[4] != 5 {
+		t.Errorf("Expected cursor pagination to visit every row once, got %v", ids)
+	}
+
+	// 降序游标向更小的值翻页
+	first, err := NewQueryBuilder(conn).Table("posts").OrderByDesc("id").CursorPaginate("id", "", 2)
+	if err != nil {
+		t.Fatalf("Failed to cursor paginate: %v", err)
+	}
+	second, err := NewQueryBuilder(conn).Table("posts").OrderByDesc("id").CursorPaginate("id", first["next_cursor"].(string), 2)
+	if err != nil {
+		t.Fatalf("Failed to cursor paginate: %v", err)
+	}
+	if data := second["data"].([]map[string]interface{}); len(data) != 2 || data[0]["id"] != int64(3) {
+		t.Errorf("Unexpected descending page: %v", second)
+	}
+
+	// 游标列自动排在已有排序之前
+	page, err = NewQueryBuilder(conn).Table("posts").OrderByDesc("title").CursorPaginate("id", "", 2)
+	if err != nil {
+		t.Fatalf("Failed to cursor paginate: %v", err)
+	}
+	if data := page["data"].([]map[string]interface{}); len(data) != 2 || data[0]["id"] != int64(1) {
+		t.Errorf("Expected cursor column to be ordered first, got %v", page)
+	}
+	if _, err := NewQueryBuilder(conn).Table("posts").OrderByAsc("title").OrderByAsc("id").CursorPaginate("id", "", 2); err == nil {
+		t.Error("Expected a leading order on another column to be rejected")
+	}
+
+	if _, err := NewQueryBuilder(conn).Table("posts").CursorPaginate("id", "not a cursor!", 2); err == nil {
+		t.Error("Expected invalid cursor to be rejected")
+	}
+}
+
+func TestQueryBuilderRawAndNamedBindings(t *testing.T) {
+	config := &ConnectionConfig{
+		Driver:   SQLite,
+		Database: filepath.Join(t.TempDir(), "raw.db"),
+	}
+	conn, err := NewConnection(config)
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec(`CREATE TABLE products (id INTEGER PRIMARY KEY, name TEXT NOT NULL, price INTEGER, category TEXT, deleted_at DATETIME)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := conn.Exec(`INSERT INTO products (id, name, price, category) VALUES (1, 'pen', 5, 'office'), (2, 'desk', 150, 'office'), (3, 'lamp', 120, 'home'), (4, 'chair', 90, 'office')`); err != nil {
+		t.Fatalf("Failed to insert test data: %v", err)
+	}
+
+	// 位置参数
+	rows, err := Raw(conn, "SELECT name FROM products WHERE price > ? AND category = ? ORDER BY id", 100, "office")
+	if err != nil {
+		t.Fatalf("Failed to execute raw query: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["name"] != "desk" {
+		t.Errorf("Unexpected raw result: %v", rows)
+	}
+
+	// 命名参数，参数值不会被当作 SQL 拼接
+	rows, err = NewQueryBuilder(conn).Raw("SELECT name FROM products WHERE name = :name OR price > :price ORDER BY id", map[string]interface{}{
+		"name":  "pen' OR '1'='1",
+		"price": 100,
+	})
+	if err != nil {
+		t.Fatalf("Failed to execute named raw query: %v", err)
+	}
+	if len(rows) != 2 || rows[0]["name"] != "desk" || rows[1]["name"] != "lamp" {
+		t.Errorf("Unexpected named raw result: %v", rows)
+	}
+
+	if _, err := NewQueryBuilder(conn).Statement("UPDATE products SET price = :price WHERE id = :id", sql.Named("price", 6), sql.Named("id", 1)); err != nil {
+		t.Fatalf("Failed to execute raw statement: %v", err)
+	}
+
+	// WhereRaw 与链式条件组合
+	results, err := NewQueryBuilder(conn).Table("products").
+		Select("id", "name").
+		WhereEq("category", "office").
+		WhereRaw("price > :min AND price < :max", map[string]interface{}{"min": 5, "max": 150}).
+		OrderByAsc("id").
+		Get()
+	if err != nil {
+		t.Fatalf("Failed to execute query: %v", err)
+	}
+	if len(results) != 2 || results[0]["name"] != "pen" || results[1]["name"] != "chair" {
+		t.Errorf("Unexpected WhereRaw result: %v", results)
+	}
+
+	if _, err := NewQueryBuilder(conn).Table("products").WhereRaw("price > :missing", map[string]interface{}{"price": 1}).Get(); err == nil {
+		t.Error("Expected missing named parameter to be reported")
+	}
+}
+
+func TestRebindPlaceholders(t *testing.T) {
+	query, args, err := bindNamed("SELECT * FROM t WHERE a = :a AND b::text = ':a' AND c = :c AND d = :a", []interface{}{map[string]interface{}{"a": 1, "c": 2}})
+	if err != nil {
+		t.Fatalf("Failed to bind named parameters: %v", err)
+	}
+	if query != "SELECT * FROM t WHERE a = ? AND b::text = ':a' AND c = ? AND d = ?" {
+		t.Errorf("Unexpected bound query: %s", query)
+	}
+	if len(args) != 3 || args[0] != 1 || args[1] != 2 || args[2] != 1 {
+		t.Errorf("Unexpected bound args: %v", args)
+	}
+
+	if got := rebind(PostgreSQL, "a = ? AND b = '?' AND c = ?"); got != "a = $1 AND b = '?' AND c = $2" {
+		t.Errorf("Unexpected PostgreSQL query: %s", got)
+	}
+	if got := rebind(SQLServer, "a = ? AND c = ?"); got != "a = @p1 AND c = @p2" {
+		t.Errorf("Unexpected SQL Server query: %s", got)
+	}
+	if got := rebind(MySQL, "a = ?"); got != "a = ?" {
+		t.Errorf("Unexpected MySQL query: %s", got)
+	}
+}
+
+func TestIntrospect(t *testing.T) {
+	conn, err := NewConnection(&ConnectionConfig{Driver: SQLite, Database: filepath.Join(t.TempDir(), "introspect.db")})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec(`CREATE TABLE users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(255) NOT NULL,
+		age INT,
+		balance DECIMAL(10,2) NOT NULL,
+		active BOOLEAN NOT NULL,
+		avatar BLOB,
+		verified_at DATETIME
+	)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	columns, err := Introspect(conn, "users")
+	if err != nil {
+		t.Fatalf("Failed to introspect table: %v", err)
+	}
+
+	expected := []struct {
+		name, goType      string
+		nullable, primary bool
+	}{
+		{"id", "int64", false, true},
+		{"name", "string", false, false},
+		{"age", "int64", true, false},
+		{"balance", "float64", false, false},
+		{"active", "bool", false, false},
+		{"avatar", "[]byte", true, false},
+		{"verified_at", "*time.Time", true, false},
+	}
+	if len(columns) != len(expected) {
+		t.Fatalf("Expected %d columns, got %d: %v", len(expected), len(columns), columns)
+	}
+	for i, want := range expected {
+		column := columns[i]
+		if column.Name != want.name || column.GoType() != want.goType || column.Nullable != want.nullable || column.PrimaryKey != want.primary {
+			t.Errorf("Unexpected column %d: %+v (go type %s)", i, column, column.GoType())
+		}
+	}
+	if columns[0].Key != "PRI" {
+		t.Errorf("Expected primary key column to have key PRI, got %q", columns[0].Key)
+	}
+
+	if _, err := Introspect(conn, "missing"); err == nil {
+		t.Error("Expected error for missing table")
+	}
+	if _, err := Introspect(conn, "users; DROP TABLE users"); err == nil {
+		t.Error("Expected error for invalid table name")
+	}
+}
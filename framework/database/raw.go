@@ -0,0 +1,185 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"laravel-go/framework/errors"
+)
+
+// Raw 使用连接执行原始 SQL 查询，参见 QueryBuilder.Raw
+func Raw(conn Connection, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return NewQueryBuilder(conn).Raw(query, args...)
+}
+
+// Raw 执行原始 SQL 查询
+//
+// args 可以是与 ? 占位符一一对应的位置参数，也可以是 :name 形式的命名参数，
+// 命名参数通过单个 map[string]interface{} 或一组 sql.Named 传入。
+// 占位符会按连接的驱动转换，参数始终以绑定方式传给驱动，不会拼接到 SQL 中。
+func (qb *QueryBuilder) Raw(query string, args ...interface{}) ([]map[string]interface{}, error) {
+	query, args, err := bindNamed(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := qb.connection.QueryContext(qb.ctx, qb.rebind(query), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute raw query")
+	}
+	defer rows.Close()
+
+	return qb.scanRows(rows)
+}
+
+// Statement 执行原始 SQL 命令（INSERT、UPDATE、DELETE 等），参数规则同 Raw
+func (qb *QueryBuilder) Statement(query string, args ...interface{}) (sql.Result, error) {
+	query, args, err := bindNamed(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := qb.connection.Exec(qb.rebind(query), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute raw statement")
+	}
+	return result, nil
+}
+
+// bindRaw 解析原始条件中的命名参数，解析失败时记录错误并在执行查询时返回
+func (qb *QueryBuilder) bindRaw(query string, args []interface{}) (string, []interface{}) {
+	bound, boundArgs, err := bindNamed(query, args)
+	if err != nil {
+		if qb.err == nil {
+			qb.err = err
+		}
+		return query, args
+	}
+	return bound, boundArgs
+}
+
+// rebind 将 ? 占位符转换为当前驱动的格式
+func (qb *QueryBuilder) rebind(query string) string {
+	return rebind(connectionDriver(qb.connection), query)
+}
+
+// connectionDriver 获取连接的驱动类型，无法识别时返回空字符串
+func connectionDriver(conn Connection) Driver {
+	if c, ok := conn.(interface{ Driver() Driver }); ok {
+		return c.Driver()
+	}
+	return ""
+}
+
+// namedArgs 提取命名参数，位置参数返回 false
+func namedArgs(args []interface{}) (map[string]interface{}, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	if len(args) == 1 {
+		if named, ok := args[0].(map[string]interface{}); ok {
+			return named, true
+		}
+	}
+
+	named := make(map[string]interface{}, len(args))
+	for _, arg := range args {
+		n, ok := arg.(sql.NamedArg)
+		if !ok {
+			return nil, false
+		}
+		named[n.Name] = n.Value
+	}
+	return named, true
+}
+
+// bindNamed 将 :name 命名参数替换为 ? 占位符，并按出现顺序生成位置参数
+//
+// 引号内的内容和 PostgreSQL 的 :: 类型转换保持不变。
+func bindNamed(query string, args []interface{}) (string, []interface{}, error) {
+	named, ok := namedArgs(args)
+	if !ok {
+		return query, args, nil
+	}
+
+	var builder strings.Builder
+	var bound []interface{}
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			builder.WriteString("::")
+			i++
+			continue
+		case c == ':' && i+1 < len(query) && isNameStart(query[i+1]):
+			end := i + 1
+			for end < len(query) && isNamePart(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			value, exists := named[name]
+			if !exists {
+				return "", nil, fmt.Errorf("missing named parameter :%s", name)
+			}
+			builder.WriteByte('?')
+			bound = append(bound, value)
+			i = end - 1
+			continue
+		}
+		builder.WriteByte(c)
+	}
+
+	return builder.String(), bound, nil
+}
+
+// rebind 将 ? 占位符转换为驱动的位置占位符：PostgreSQL 使用 $1，SQL Server 使用 @p1
+func rebind(driver Driver, query string) string {
+	var prefix string
+	switch driver {
+	case PostgreSQL:
+		prefix = "$"
+	case SQLServer:
+		prefix = "@p"
+	default:
+		return query
+	}
+
+	var builder strings.Builder
+	var quote byte
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+			builder.WriteString(prefix)
+			builder.WriteString(strconv.Itoa(n))
+			continue
+		}
+		builder.WriteByte(c)
+	}
+	return builder.String()
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,173 @@
+package database
+
+import (
+	"database/sql"
+	stderrors "errors"
+	"fmt"
+	"reflect"
+)
+
+// ModelNotFoundError 根据主键找不到模型
+type ModelNotFoundError struct {
+	Model string
+	ID    interface{}
+}
+
+// Error 实现 error 接口
+func (e *ModelNotFoundError) Error() string {
+	return fmt.Sprintf("%s with id %v not found", e.Model, e.ID)
+}
+
+// IsModelNotFound 检查错误是否为 ModelNotFoundError
+func IsModelNotFound(err error) bool {
+	var notFound *ModelNotFoundError
+	return stderrors.As(err, &notFound)
+}
+
+// Repository 模型仓库，基于查询构建器提供通用的增删改查
+//
+// T 为模型结构体类型（非指针），表名和主键按模型的 TableName、PrimaryKey 确定。
+// 模型带有 DeletedAt 字段时查询自动排除软删除的记录，删除为软删除；
+// 创建、更新和删除通过 Model.Save、Model.Delete 完成，会调用钩子并分发模型事件。
+type Repository[T any] struct {
+	conn        Connection
+	table       string
+	primaryKey  string
+	softDeletes bool
+}
+
+// NewRepository 创建模型仓库
+func NewRepository[T any](conn Connection) *Repository[T] {
+	model := new(T)
+	return &Repository[T]{
+		conn:        conn,
+		table:       getTableName(model),
+		primaryKey:  getPrimaryKey(model),
+		softDeletes: hasSoftDeletes(reflect.TypeOf(model).Elem()),
+	}
+}
+
+// Query 创建指向模型表的查询构建器
+func (r *Repository[T]) Query() *QueryBuilder {
+	qb := NewQueryBuilder(r.conn).Table(r.table)
+	if !r.softDeletes {
+		qb.WithTrashed()
+	}
+	return qb
+}
+
+// Find 根据主键查找，记录不存在时返回 *ModelNotFoundError
+func (r *Repository[T]) Find(id interface{}) (*T, error) {
+	row, err := r.Query().WhereEq(r.primaryKey, id).First()
+	if stderrors.Is(err, sql.ErrNoRows) {
+		return nil, &ModelNotFoundError{Model: modelName(new(T)), ID: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	model := new(T)
+	if err := mapToStruct(row, model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// All 获取所有记录
+func (r *Repository[T]) All() ([]T, error) {
+	return r.Get(r.Query())
+}
+
+// Where 条件查询
+func (r *Repository[T]) Where(column string, operator string, value interface{}) ([]T, error) {
+	return r.Get(r.Query().Where(column, operator, value))
+}
+
+// Get 执行查询并映射为模型，qb 通常由 Query 创建
+func (r *Repository[T]) Get(qb *QueryBuilder) ([]T, error) {
+	rows, err := qb.Get()
+	if err != nil {
+		return nil, err
+	}
+	return r.hydrate(rows)
+}
+
+// Create 创建记录，成功后模型的主键和时间戳会被填充
+func (r *Repository[T]) Create(model *T) error {
+	if id := modelID(model); id != 0 {
+		return fmt.Errorf("%s already exists with id %d", modelName(model), id)
+	}
+	return (&Model{}).Save(r.conn, model)
+}
+
+// Update 更新记录，模型的主键不能为空
+func (r *Repository[T]) Update(model *T) error {
+	if modelID(model) == 0 {
+		return fmt.Errorf("cannot update %s without primary key", modelName(model))
+	}
+	return (&Model{}).Save(r.conn, model)
+}
+
+// Delete 根据主键删除记录，记录不存在时返回 *ModelNotFoundError
+func (r *Repository[T]) Delete(id interface{}) error {
+	model, err := r.Find(id)
+	if err != nil {
+		return err
+	}
+	return (&Model{}).Delete(r.conn, model)
+}
+
+// Paginate 分页查询，返回结构同 QueryBuilder.Paginate，data 为 []T
+func (r *Repository[T]) Paginate(page, perPage int) (map[string]interface{}, error) {
+	return r.PaginateQuery(r.Query(), page, perPage)
+}
+
+// PaginateQuery 对指定查询分页，qb 通常由 Query 创建
+func (r *Repository[T]) PaginateQuery(qb *QueryBuilder, page, perPage int) (map[string]interface{}, error) {
+	result, err := qb.Paginate(page, perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	models, err := r.hydrate(result["data"].([]map[string]interface{}))
+	if err != nil {
+		return nil, err
+	}
+	result["data"] = models
+	return result, nil
+}
+
+// hydrate 将查询结果映射为模型切片
+func (r *Repository[T]) hydrate(rows []map[string]interface{}) ([]T, error) {
+	models := make([]T, 0, len(rows))
+	for _, row := range rows {
+		var model T
+		if err := mapToStruct(row, &model); err != nil {
+			return nil, err
+		}
+		models = append(models, model)
+	}
+	return models, nil
+}
+
+// hasSoftDeletes 检查模型是否支持软删除
+func hasSoftDeletes(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := t.FieldByName("DeletedAt")
+	return ok
+}
+
+// modelID 获取模型的主键值，无法识别时返回 0
+func modelID(model interface{}) int64 {
+	modelVal := reflect.ValueOf(model).Elem()
+	pkField := modelVal.FieldByName("ID")
+	if modelField := modelVal.FieldByName("Model"); modelField.IsValid() {
+		pkField = modelField.FieldByName("ID")
+	}
+	if pkField.IsValid() && (pkField.Kind() == reflect.Int64 || pkField.Kind() == reflect.Int) {
+		return pkField.Int()
+	}
+	return 0
+}
@@ -0,0 +1,338 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrConnectionClosed 连接已被关闭
+var ErrConnectionClosed = stderrors.New("database connection is closed")
+
+// ConnectionState 连接状态
+type ConnectionState string
+
+// 连接状态
+const (
+	StateConnected    ConnectionState = "connected"
+	StateReconnecting ConnectionState = "reconnecting"
+	StateDisconnected ConnectionState = "disconnected"
+)
+
+// RetryPolicy 连接重试策略
+type RetryPolicy struct {
+	MaxAttempts  int           // 最大尝试次数，包含第一次
+	InitialDelay time.Duration // 第一次重试前的等待时间
+	MaxDelay     time.Duration // 等待时间上限
+	Multiplier   float64       // 每次重试等待时间的增长倍数
+}
+
+// DefaultRetryPolicy 默认重试策略：最多尝试 5 次，等待时间从 200ms 翻倍增长到 5s
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  5,
+		InitialDelay: 200 * time.Millisecond,
+		MaxDelay:     5 * time.Second,
+		Multiplier:   2,
+	}
+}
+
+// delay 第 attempt 次重试前的等待时间，attempt 从 1 开始
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	delay := p.InitialDelay
+	for i := 1; i < attempt; i++ {
+		delay = time.Duration(float64(delay) * p.Multiplier)
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			return p.MaxDelay
+		}
+	}
+	return delay
+}
+
+// connector 连接创建函数
+type connector func(config *ConnectionConfig) (Connection, error)
+
+// ConnectWithRetry 按重试策略建立连接，数据库短暂不可用时不会立即失败
+func ConnectWithRetry(ctx context.Context, config *ConnectionConfig, policy RetryPolicy) (Connection, error) {
+	return connectWithRetry(ctx, config, policy, NewConnection)
+}
+
+// connectWithRetry 使用指定的连接函数按重试策略建立连接
+func connectWithRetry(ctx context.Context, config *ConnectionConfig, policy RetryPolicy, connect connector) (Connection, error) {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		conn, err := connect(config)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+
+		if attempt == attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("database connection aborted after %d attempts: %w", attempt, ctx.Err())
+		case <-time.After(policy.delay(attempt)):
+		}
+	}
+
+	return nil, fmt.Errorf("database connection failed after %d attempts: %w", attempts, lastErr)
+}
+
+// isConnectionError 判断错误是否由连接断开引起
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if stderrors.Is(err, driver.ErrBadConn) || stderrors.Is(err, sql.ErrConnDone) || stderrors.Is(err, io.EOF) ||
+		stderrors.Is(err, io.ErrUnexpectedEOF) || stderrors.Is(err, syscall.ECONNREFUSED) ||
+		stderrors.Is(err, syscall.ECONNRESET) || stderrors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	if stderrors.As(err, &netErr) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, fragment := range []string{"bad connection", "connection refused", "connection reset", "broken pipe", "server has gone away", "database is closed"} {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconnectingConnection 自动重连的数据库连接
+//
+// 操作因连接断开失败时，先按重试策略重新建立连接，再重试一次该操作；
+// 重连失败时返回原始错误。已经关闭的连接不会再重连。
+type ReconnectingConnection struct {
+	config  *ConnectionConfig
+	policy  RetryPolicy
+	connect connector
+
+	mu     sync.RWMutex
+	conn   Connection
+	state  ConnectionState
+	closed bool
+}
+
+// NewReconnectingConnection 按重试策略建立自动重连的数据库连接
+func NewReconnectingConnection(ctx context.Context, config *ConnectionConfig, policy RetryPolicy) (*ReconnectingConnection, error) {
+	return newReconnectingConnection(ctx, config, policy, NewConnection)
+}
+
+// newReconnectingConnection 使用指定的连接函数建立自动重连的连接
+func newReconnectingConnection(ctx context.Context, config *ConnectionConfig, policy RetryPolicy, connect connector) (*ReconnectingConnection, error) {
+	conn, err := connectWithRetry(ctx, config, policy, connect)
+	if err != nil {
+		return nil, err
+	}
+	return &ReconnectingConnection{
+		config:  config,
+		policy:  policy,
+		connect: connect,
+		conn:    conn,
+		state:   StateConnected,
+	}, nil
+}
+
+// State 获取连接状态
+func (c *ReconnectingConnection) State() ConnectionState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state
+}
+
+// Reconnect 关闭当前连接并重新建立连接
+func (c *ReconnectingConnection) Reconnect(ctx context.Context) error {
+	return c.reconnect(ctx, c.current())
+}
+
+// reconnect 替换失效的连接，其他协程已经完成重连时直接返回
+func (c *ReconnectingConnection) reconnect(ctx context.Context, failed Connection) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrConnectionClosed
+	}
+	if c.conn != failed {
+		return nil
+	}
+
+	// 重连失败时保留已关闭的旧连接，下次操作前会再次尝试重连
+	c.state = StateReconnecting
+	c.conn.Close()
+
+	conn, err := connectWithRetry(ctx, c.config, c.policy, c.connect)
+	if err != nil {
+		c.state = StateDisconnected
+		return err
+	}
+	c.conn = conn
+	c.state = StateConnected
+	return nil
+}
+
+// current 获取当前连接
+func (c *ReconnectingConnection) current() Connection {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.conn
+}
+
+// acquire 获取当前连接，上次重连失败时先重连
+func (c *ReconnectingConnection) acquire(ctx context.Context) (Connection, error) {
+	c.mu.RLock()
+	conn, state, closed := c.conn, c.state, c.closed
+	c.mu.RUnlock()
+
+	if closed {
+		return nil, ErrConnectionClosed
+	}
+	if state == StateDisconnected {
+		if err := c.reconnect(ctx, conn); err != nil {
+			return nil, err
+		}
+		return c.current(), nil
+	}
+	return conn, nil
+}
+
+// do 执行操作，连接断开时重连并重试一次
+func (c *ReconnectingConnection) do(ctx context.Context, op func(conn Connection) error) error {
+	conn, err := c.acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = op(conn)
+	if !isConnectionError(err) {
+		return err
+	}
+
+	if reconnectErr := c.reconnect(ctx, conn); reconnectErr != nil {
+		return err
+	}
+	if conn, err = c.acquire(ctx); err != nil {
+		return err
+	}
+	return op(conn)
+}
+
+// DB 获取当前的原始数据库连接
+func (c *ReconnectingConnection) DB() *sql.DB {
+	return c.current().DB()
+}
+
+// Query 执行查询
+func (c *ReconnectingConnection) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext 执行查询（带上下文）
+func (c *ReconnectingConnection) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := c.do(ctx, func(conn Connection) error {
+		var err error
+		rows, err = conn.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRow 执行单行查询
+func (c *ReconnectingConnection) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext 执行单行查询（带上下文）
+//
+// 无法重连时使用已失效的连接查询，错误在 Scan 时返回。
+func (c *ReconnectingConnection) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	c.do(ctx, func(conn Connection) error {
+		row = conn.QueryRowContext(ctx, query, args...)
+		return row.Err()
+	})
+	if row == nil {
+		row = c.current().QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
+
+// Exec 执行命令
+func (c *ReconnectingConnection) Exec(query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := c.do(context.Background(), func(conn Connection) error {
+		var err error
+		result, err = conn.Exec(query, args...)
+		return err
+	})
+	return result, err
+}
+
+// Begin 开始事务
+func (c *ReconnectingConnection) Begin() (*sql.Tx, error) {
+	return c.BeginTx(context.Background(), nil)
+}
+
+// BeginTx 开始事务（带上下文），只在开始事务时重连，事务内的操作不会重试
+func (c *ReconnectingConnection) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	var tx *sql.Tx
+	err := c.do(ctx, func(conn Connection) error {
+		var err error
+		tx, err = conn.BeginTx(ctx, opts)
+		return err
+	})
+	return tx, err
+}
+
+// Close 关闭连接，关闭后不再重连
+func (c *ReconnectingConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	c.state = StateDisconnected
+	return c.conn.Close()
+}
+
+// Ping 检查连接状态
+func (c *ReconnectingConnection) Ping() error {
+	return c.PingContext(context.Background())
+}
+
+// PingContext 检查连接状态（带上下文），连接断开时会尝试重连
+func (c *ReconnectingConnection) PingContext(ctx context.Context) error {
+	return c.do(ctx, func(conn Connection) error {
+		return conn.PingContext(ctx)
+	})
+}
+
+// Stats 获取连接统计信息
+func (c *ReconnectingConnection) Stats() sql.DBStats {
+	return c.current().Stats()
+}
+
+// Driver 获取连接的驱动类型
+func (c *ReconnectingConnection) Driver() Driver {
+	return c.config.Driver
+}
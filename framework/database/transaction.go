@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// Tx 支持提交和回滚回调的事务
+//
+// AfterCommit 注册的回调在事务提交成功后按注册顺序执行，事务回滚时丢弃；
+// AfterRollback 注册的回调只在回滚后执行。
+type Tx struct {
+	*sql.Tx
+
+	mu            sync.Mutex
+	afterCommit   []func()
+	afterRollback []func()
+}
+
+// BeginTx 开始支持回调的事务
+func BeginTx(ctx context.Context, conn Connection, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{Tx: tx}, nil
+}
+
+// AfterCommit 注册事务提交成功后执行的回调
+func (tx *Tx) AfterCommit(fn func()) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.afterCommit = append(tx.afterCommit, fn)
+}
+
+// AfterRollback 注册事务回滚后执行的回调
+func (tx *Tx) AfterRollback(fn func()) {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.afterRollback = append(tx.afterRollback, fn)
+}
+
+// Commit 提交事务，成功后执行提交回调
+func (tx *Tx) Commit() error {
+	if err := tx.Tx.Commit(); err != nil {
+		return err
+	}
+	for _, fn := range tx.takeCallbacks(true) {
+		fn()
+	}
+	return nil
+}
+
+// Rollback 回滚事务，丢弃提交回调并执行回滚回调
+func (tx *Tx) Rollback() error {
+	err := tx.Tx.Rollback()
+	if err == sql.ErrTxDone {
+		return err
+	}
+	for _, fn := range tx.takeCallbacks(false) {
+		fn()
+	}
+	return err
+}
+
+// takeCallbacks 取出需要执行的回调并清空所有回调，回调只执行一次
+func (tx *Tx) takeCallbacks(committed bool) []func() {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	callbacks := tx.afterRollback
+	if committed {
+		callbacks = tx.afterCommit
+	}
+	tx.afterCommit = nil
+	tx.afterRollback = nil
+	return callbacks
+}
+
+// Transaction 在事务中执行 fn
+//
+// fn 返回 nil 时提交事务，返回错误或 panic 时回滚事务。
+func Transaction(ctx context.Context, conn Connection, fn func(tx *Tx) error) (err error) {
+	tx, err := BeginTx(ctx, conn, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
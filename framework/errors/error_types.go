@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -163,6 +164,7 @@ type ValidationError struct {
 	Message   string      `json:"message"`
 	Value     interface{} `json:"value,omitempty"`
 	Rule      string      `json:"rule,omitempty"`
+	Code      string      `json:"code,omitempty"`
 	Expected  interface{} `json:"expected,omitempty"`
 	Actual    interface{} `json:"actual,omitempty"`
 	Timestamp time.Time   `json:"timestamp"`
@@ -180,12 +182,24 @@ func (e *ValidationError) WithValue(value interface{}) *ValidationError {
 	return e
 }
 
-// WithRule 设置验证规则
+// WithRule 设置验证规则，同时设置对应的错误码
 func (e *ValidationError) WithRule(rule string) *ValidationError {
 	e.Rule = rule
+	e.Code = ValidationCode(rule)
 	return e
 }
 
+// WithCode 设置错误码
+func (e *ValidationError) WithCode(code string) *ValidationError {
+	e.Code = code
+	return e
+}
+
+// ValidationCode 根据验证规则生成稳定的错误码，例如 required => VALIDATION_REQUIRED
+func ValidationCode(rule string) string {
+	return "VALIDATION_" + strings.ToUpper(strings.ReplaceAll(rule, "-", "_"))
+}
+
 // WithExpected 设置期望值
 func (e *ValidationError) WithExpected(expected interface{}) *ValidationError {
 	e.Expected = expected
@@ -247,6 +261,12 @@ func (e *ValidationErrors) AddWithRule(field, message, rule string) {
 	*e = append(*e, err)
 }
 
+// AddRuleError 添加带规则、错误码和值的验证错误
+func (e *ValidationErrors) AddRuleError(field, message, rule string, value interface{}) {
+	err := NewValidationError(field, message).WithRule(rule).WithValue(value)
+	*e = append(*e, err)
+}
+
 // HasErrors 检查是否有错误
 func (e ValidationErrors) HasErrors() bool {
 	return len(e) > 0
@@ -277,6 +297,22 @@ func (e ValidationErrors) ToMap() map[string][]string {
 	return result
 }
 
+// ToCodeMap 转换为字段错误码映射，前端可以根据错误码本地化提示
+func (e ValidationErrors) ToCodeMap() map[string][]string {
+	result := make(map[string][]string)
+	for _, err := range e {
+		result[err.Field] = append(result[err.Field], err.Code)
+	}
+	return result
+}
+
+// ToJSON 转换为 {"errors": {"field": ["message"]}} 格式的 JSON
+func (e ValidationErrors) ToJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"errors": e.ToMap(),
+	})
+}
+
 // SecurityError 安全错误
 type SecurityError struct {
 	Code      ErrorCode `json:"code"`
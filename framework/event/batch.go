@@ -0,0 +1,101 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BatchFailure 批量分发中失败的事件
+type BatchFailure struct {
+	// Index 事件在批次中的位置
+	Index int
+	Event Event
+	Err   error
+}
+
+// BatchResult 批量分发结果
+//
+// 批量分发会尝试分发每一个事件，单个事件失败不影响其他事件。
+type BatchResult struct {
+	// Succeeded 分发成功的事件，保持批次中的顺序
+	Succeeded []Event
+	// Failed 分发失败的事件及原因，保持批次中的顺序
+	Failed []BatchFailure
+}
+
+// Total 批次中的事件数量
+func (r *BatchResult) Total() int {
+	return len(r.Succeeded) + len(r.Failed)
+}
+
+// Err 存在失败的事件时返回汇总的错误，否则返回 nil
+func (r *BatchResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+
+	errs := make([]error, 0, len(r.Failed))
+	for _, failure := range r.Failed {
+		errs = append(errs, fmt.Errorf("event %d [%s]: %w", failure.Index, failure.Event.GetName(), failure.Err))
+	}
+	return fmt.Errorf("%d of %d events failed: %w", len(r.Failed), r.Total(), errors.Join(errs...))
+}
+
+// newBatchResult 按事件顺序汇总分发结果
+func newBatchResult(events []Event, errs []error) *BatchResult {
+	result := &BatchResult{}
+	for i, event := range events {
+		if errs[i] != nil {
+			result.Failed = append(result.Failed, BatchFailure{Index: i, Event: event, Err: errs[i]})
+		} else {
+			result.Succeeded = append(result.Succeeded, event)
+		}
+	}
+	return result
+}
+
+// DispatchBatch 批量分发事件
+//
+// 依次分发每一个事件，监听器返回错误或 panic 的事件记录在结果的 Failed 中，
+// 不会中断后续事件的分发。
+func (d *EventDispatcher) DispatchBatch(events []Event) *BatchResult {
+	errs := make([]error, len(events))
+	for i, event := range events {
+		errs[i] = d.dispatch(event)
+	}
+	return newBatchResult(events, errs)
+}
+
+// DispatchBatchAsync 并发分发一批事件，等待全部完成后返回结果
+//
+// 最多同时分发 workers 个事件，workers 不大于 0 时使用分发器的工作进程数量。
+func (d *EventDispatcher) DispatchBatchAsync(events []Event, workers int) *BatchResult {
+	if workers <= 0 {
+		workers = d.workerCount
+	}
+	if workers > len(events) {
+		workers = len(events)
+	}
+
+	errs := make([]error, len(events))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = d.dispatch(events[i])
+			}
+		}()
+	}
+
+	for i := range events {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return newBatchResult(events, errs)
+}
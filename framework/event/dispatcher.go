@@ -2,10 +2,13 @@ package event
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
 	"sort"
 	"sync"
+	"sync/atomic"
 )
 
 // EventDispatcher 事件分发器实现
@@ -19,6 +22,7 @@ type EventDispatcher struct {
 	workerCount int
 	ctx         context.Context
 	cancel      context.CancelFunc
+	failedStore FailedEventStore
 }
 
 // NewEventDispatcher 创建事件分发器
@@ -94,6 +98,47 @@ func (d *EventDispatcher) Forget(eventName string, listenerName string) {
 	d.listeners[eventName] = newListeners
 }
 
+// ForgetListener 移除指定的监听器实例，同名的其他监听器不受影响
+func (d *EventDispatcher) ForgetListener(eventName string, listener Listener) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	listeners, exists := d.listeners[eventName]
+	if !exists {
+		return
+	}
+
+	newListeners := make([]Listener, 0, len(listeners))
+	for _, registered := range listeners {
+		if !sameListener(registered, listener) {
+			newListeners = append(newListeners, registered)
+		}
+	}
+
+	if len(newListeners) == 0 {
+		delete(d.listeners, eventName)
+		return
+	}
+	d.listeners[eventName] = newListeners
+}
+
+// ListenOnce 监听事件，第一次处理后自动移除
+//
+// 返回的监听器可以传给 ForgetListener 提前移除。并发分发同一事件时处理函数也只执行一次。
+func (d *EventDispatcher) ListenOnce(eventName string, handler func(Event) error) Listener {
+	listener := &onceListener{}
+	listener.BaseListener = NewListener(fmt.Sprintf("once:%s:%p", eventName, listener), func(event Event) error {
+		if !atomic.CompareAndSwapInt32(&listener.fired, 0, 1) {
+			return nil
+		}
+		d.ForgetListener(eventName, listener)
+		return handler(event)
+	})
+
+	d.Listen(eventName, listener)
+	return listener
+}
+
 // ForgetMany 忘记多个事件
 func (d *EventDispatcher) ForgetMany(eventNames []string) {
 	d.mu.Lock()
@@ -109,11 +154,23 @@ func (d *EventDispatcher) ForgetMany(eventNames []string) {
 }
 
 // Dispatch 分发事件
+//
+// 监听器的错误只记录日志，不会返回给调用方。
 func (d *EventDispatcher) Dispatch(event Event) error {
 	if d.closed {
 		return ErrDispatcherClosed
 	}
 
+	d.dispatch(event)
+	return nil
+}
+
+// dispatch 分发事件，返回所有监听器的错误（内部方法）
+func (d *EventDispatcher) dispatch(event Event) error {
+	if d.closed {
+		return ErrDispatcherClosed
+	}
+
 	// 标记事件为已传播
 	event.SetPropagated(true)
 
@@ -132,10 +189,14 @@ func (d *EventDispatcher) Dispatch(event Event) error {
 		}
 	}
 
+	var errs []error
+
 	// 同步处理监听器
 	for _, listener := range syncListeners {
 		if err := d.handleListener(listener, event); err != nil {
 			log.Printf("Listener %s failed to handle event %s: %v", listener.GetName(), event.GetName(), err)
+			d.recordFailure(event, listener, err)
+			errs = append(errs, &ListenerError{ListenerName: listener.GetName(), EventName: event.GetName(), Message: "handle failed", Err: err})
 		}
 	}
 
@@ -143,10 +204,11 @@ func (d *EventDispatcher) Dispatch(event Event) error {
 	for _, listener := range queuedListeners {
 		if err := d.queueListener(listener, event); err != nil {
 			log.Printf("Failed to queue listener %s for event %s: %v", listener.GetName(), event.GetName(), err)
+			errs = append(errs, &ListenerError{ListenerName: listener.GetName(), EventName: event.GetName(), Message: "queue failed", Err: err})
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // DispatchAsync 异步分发事件
@@ -163,16 +225,6 @@ func (d *EventDispatcher) DispatchAsync(event Event) error {
 	}
 }
 
-// DispatchBatch 批量分发事件
-func (d *EventDispatcher) DispatchBatch(events []Event) error {
-	for _, event := range events {
-		if err := d.Dispatch(event); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // Subscribe 订阅事件
 func (d *EventDispatcher) Subscribe(subscriber EventSubscriber) {
 	d.mu.Lock()
@@ -284,6 +336,15 @@ func (d *EventDispatcher) SetWorkerCount(count int) {
 	d.workerCount = count
 }
 
+// sameListener 判断是否为同一个监听器实例（内部方法）
+func sameListener(a, b Listener) bool {
+	// 不可比较的监听器类型（例如包含函数字段的结构体值）直接比较会 panic
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
 // getListeners 获取监听器（内部方法）
 func (d *EventDispatcher) getListeners(eventName string) []Listener {
 	d.mu.RLock()
@@ -300,10 +361,11 @@ func (d *EventDispatcher) getListeners(eventName string) []Listener {
 }
 
 // handleListener 处理监听器（内部方法）
-func (d *EventDispatcher) handleListener(listener Listener, event Event) error {
+func (d *EventDispatcher) handleListener(listener Listener, event Event) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Printf("Listener %s panicked while handling event %s: %v", listener.GetName(), event.GetName(), r)
+			err = fmt.Errorf("listener panicked: %v", r)
 		}
 	}()
 
@@ -45,12 +45,22 @@ type Dispatcher interface {
 	Listen(eventName string, listener Listener)
 	ListenMany(eventNames []string, listener Listener)
 	Forget(eventName string, listenerName string)
+	ForgetListener(eventName string, listener Listener)
+	ListenOnce(eventName string, handler func(Event) error) Listener
 	ForgetMany(eventNames []string)
 
 	// 事件分发
 	Dispatch(event Event) error
 	DispatchAsync(event Event) error
-	DispatchBatch(events []Event) error
+	DispatchBatch(events []Event) *BatchResult
+	DispatchBatchAsync(events []Event, workers int) *BatchResult
+	DispatchUntil(event Event, predicate func(result interface{}) bool) (interface{}, error)
+	DispatchCollect(event Event) ([]interface{}, error)
+
+	// 失败事件
+	SetFailedEventStore(store FailedEventStore)
+	GetFailedEvents(limit int) ([]*FailedEvent, error)
+	ReplayFailedEvent(id string) error
 
 	// 事件订阅
 	Subscribe(subscriber EventSubscriber)
@@ -170,6 +180,16 @@ func (em *EventManager) ListenMany(eventNames []string, listener Listener) {
 	em.dispatcher.ListenMany(eventNames, listener)
 }
 
+// ListenOnce 监听事件，第一次处理后自动移除
+func (em *EventManager) ListenOnce(eventName string, handler func(Event) error) Listener {
+	return em.dispatcher.ListenOnce(eventName, handler)
+}
+
+// ForgetListener 移除指定的监听器实例
+func (em *EventManager) ForgetListener(eventName string, listener Listener) {
+	em.dispatcher.ForgetListener(eventName, listener)
+}
+
 // Dispatch 分发事件
 func (em *EventManager) Dispatch(event Event) error {
 	em.stats.TotalEvents++
@@ -186,6 +206,62 @@ func (em *EventManager) DispatchAsync(event Event) error {
 	return em.dispatcher.DispatchAsync(event)
 }
 
+// DispatchBatch 批量分发事件，每个事件单独计入统计
+func (em *EventManager) DispatchBatch(events []Event) *BatchResult {
+	result := em.dispatcher.DispatchBatch(events)
+	em.recordBatch(result)
+	return result
+}
+
+// DispatchBatchAsync 并发分发一批事件，每个事件单独计入统计
+func (em *EventManager) DispatchBatchAsync(events []Event, workers int) *BatchResult {
+	result := em.dispatcher.DispatchBatchAsync(events, workers)
+	em.recordBatch(result)
+	return result
+}
+
+// DispatchUntil 分发事件，返回第一个满足 predicate 的监听器结果
+func (em *EventManager) DispatchUntil(event Event, predicate func(result interface{}) bool) (interface{}, error) {
+	em.stats.TotalEvents++
+	em.stats.DispatchedEvents++
+	em.stats.LastEventAt = time.Now()
+	return em.dispatcher.DispatchUntil(event, predicate)
+}
+
+// DispatchCollect 分发事件，返回所有监听器的结果
+func (em *EventManager) DispatchCollect(event Event) ([]interface{}, error) {
+	em.stats.TotalEvents++
+	em.stats.DispatchedEvents++
+	em.stats.LastEventAt = time.Now()
+	return em.dispatcher.DispatchCollect(event)
+}
+
+// SetFailedEventStore 设置失败事件存储
+func (em *EventManager) SetFailedEventStore(store FailedEventStore) {
+	em.dispatcher.SetFailedEventStore(store)
+}
+
+// GetFailedEvents 获取最近的失败事件
+func (em *EventManager) GetFailedEvents(limit int) ([]*FailedEvent, error) {
+	return em.dispatcher.GetFailedEvents(limit)
+}
+
+// ReplayFailedEvent 重新调用失败的监听器处理事件
+func (em *EventManager) ReplayFailedEvent(id string) error {
+	return em.dispatcher.ReplayFailedEvent(id)
+}
+
+// recordBatch 统计批量分发的结果
+func (em *EventManager) recordBatch(result *BatchResult) {
+	if result.Total() == 0 {
+		return
+	}
+	em.stats.TotalEvents += int64(result.Total())
+	em.stats.DispatchedEvents += int64(len(result.Succeeded))
+	em.stats.FailedEvents += int64(len(result.Failed))
+	em.stats.LastEventAt = time.Now()
+}
+
 // Queue 队列事件
 func (em *EventManager) Queue(event Event, queue string) error {
 	em.stats.TotalEvents++
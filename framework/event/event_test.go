@@ -2,10 +2,20 @@ package event
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/go-redis/redis/v8"
 )
 
+// *redis.Client 可以直接用于 Redis 失败事件存储
+var _ RedisFailedEventClient = (*redis.Client)(nil)
+
 func TestBaseEvent(t *testing.T) {
 	// 测试创建事件
 	payload := map[string]interface{}{"key": "value"}
@@ -147,8 +157,7 @@ func TestEventDispatcher(t *testing.T) {
 		NewEvent("test.event", nil),
 		NewEvent("test.event", nil),
 	}
-	err = dispatcher.DispatchBatch(events)
-	if err != nil {
+	if err := dispatcher.DispatchBatch(events).Err(); err != nil {
 		t.Errorf("Failed to dispatch batch events: %v", err)
 	}
 
@@ -163,6 +172,301 @@ func TestEventDispatcher(t *testing.T) {
 	}
 }
 
+func TestDispatchBatchPartialFailure(t *testing.T) {
+	dispatcher := NewEventDispatcher(nil)
+	defer dispatcher.Close()
+	manager := NewEventManager(dispatcher, nil)
+
+	var mu sync.Mutex
+	var handled []int
+	boom := errors.New("boom")
+	dispatcher.Listen("order.created", NewListener("order.listener", func(event Event) error {
+		id := event.GetPayload().(int)
+		if id == 2 {
+			return boom
+		}
+		if id == 4 {
+			panic("listener crashed")
+		}
+		mu.Lock()
+		handled = append(handled, id)
+		mu.Unlock()
+		return nil
+	}))
+
+	events := make([]Event, 5)
+	for i := range events {
+		events[i] = NewEvent("order.created", i+1)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		dispatch func() *BatchResult
+	}{
+		{"sequential", func() *BatchResult { return manager.DispatchBatch(events) }},
+		{"async", func() *BatchResult { return manager.DispatchBatchAsync(events, 2) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handled = nil
+			result := tt.dispatch()
+
+			// 中间事件失败不影响其他事件
+			sort.Ints(handled)
+			if fmt.Sprint(handled) != "[1 3 5]" {
+				t.Errorf("Expected events 1, 3, 5 to be handled, got %v", handled)
+			}
+			if len(result.Succeeded) != 3 || result.Total() != 5 {
+				t.Errorf("Expected 3 of 5 events to succeed, got %d of %d", len(result.Succeeded), result.Total())
+			}
+			if len(result.Failed) != 2 || result.Failed[0].Index != 1 || result.Failed[1].Index != 3 {
+				t.Fatalf("Expected events at index 1 and 3 to fail, got %+v", result.Failed)
+			}
+			if !errors.Is(result.Failed[0].Err, boom) {
+				t.Errorf("Expected listener error to be reported, got %v", result.Failed[0].Err)
+			}
+			var listenerErr *ListenerError
+			if !errors.As(result.Err(), &listenerErr) || listenerErr.ListenerName != "order.listener" {
+				t.Errorf("Expected aggregated error to name the listener, got %v", result.Err())
+			}
+		})
+	}
+
+	// 每个事件单独计入统计
+	stats := manager.GetStats()
+	if stats.TotalEvents != 10 || stats.DispatchedEvents != 6 || stats.FailedEvents != 4 {
+		t.Errorf("Expected per-event stats 10/6/4, got %+v", stats)
+	}
+
+	if result := manager.DispatchBatch([]Event{NewEvent("order.created", 1)}); result.Err() != nil {
+		t.Errorf("Expected successful batch to have no error, got %v", result.Err())
+	}
+}
+
+func TestDispatchResults(t *testing.T) {
+	dispatcher := NewEventDispatcher(nil)
+	defer dispatcher.Close()
+
+	var called []string
+	record := func(name string, result interface{}, err error) *BaseResultListener {
+		return NewResultListener(name, func(event Event) (interface{}, error) {
+			called = append(called, name)
+			return result, err
+		})
+	}
+
+	first := record("first", nil, nil)
+	first.SetPriority(30)
+	second := record("second", "discount:10", nil)
+	second.SetPriority(20)
+	third := record("third", "discount:20", nil)
+	third.SetPriority(10)
+	dispatcher.Listen("price.resolve", third)
+	dispatcher.Listen("price.resolve", first)
+	dispatcher.Listen("price.resolve", second)
+	// 普通监听器的结果为 nil
+	dispatcher.Listen("price.resolve", NewListener("plain", func(event Event) error {
+		called = append(called, "plain")
+		return nil
+	}))
+
+	// 第一个非 nil 结果胜出，后续监听器不再调用
+	result, err := dispatcher.DispatchUntil(NewEvent("price.resolve", nil), NonNil)
+	if err != nil || result != "discount:10" {
+		t.Errorf("Expected first non-nil result, got %v, %v", result, err)
+	}
+	if fmt.Sprint(called) != "[first second]" {
+		t.Errorf("Expected dispatch to stop after match, called %v", called)
+	}
+
+	called = nil
+	result, err = dispatcher.DispatchUntil(NewEvent("price.resolve", nil), func(result interface{}) bool {
+		return result == "missing"
+	})
+	if err != nil || result != nil || len(called) != 4 {
+		t.Errorf("Expected nil after calling every listener, got %v, %v, called %v", result, err, called)
+	}
+
+	// 收集所有结果
+	called = nil
+	results, err := dispatcher.DispatchCollect(NewEvent("price.resolve", nil))
+	if err != nil || fmt.Sprint(results) != "[<nil> discount:10 discount:20 <nil>]" {
+		t.Errorf("Expected all results in priority order, got %v, %v", results, err)
+	}
+
+	// 错误：Until 立即停止，Collect 继续并汇总错误
+	boom := errors.New("boom")
+	failing := record("failing", nil, boom)
+	failing.SetPriority(25)
+	dispatcher.Listen("price.resolve", failing)
+
+	called = nil
+	if _, err := dispatcher.DispatchUntil(NewEvent("price.resolve", nil), NonNil); !errors.Is(err, boom) {
+		t.Errorf("Expected listener error, got %v", err)
+	}
+	if fmt.Sprint(called) != "[first failing]" {
+		t.Errorf("Expected until to stop at failing listener, called %v", called)
+	}
+
+	results, err = dispatcher.DispatchCollect(NewEvent("price.resolve", nil))
+	var listenerErr *ListenerError
+	if !errors.As(err, &listenerErr) || listenerErr.ListenerName != "failing" {
+		t.Errorf("Expected aggregated listener error, got %v", err)
+	}
+	if len(results) != 4 {
+		t.Errorf("Expected results from the other listeners, got %v", results)
+	}
+}
+
+func TestListenOnceAndForgetListener(t *testing.T) {
+	dispatcher := NewEventDispatcher(nil)
+	defer dispatcher.Close()
+
+	// 并发分发时一次性监听器也只执行一次
+	var onceCalls int32
+	dispatcher.ListenOnce("user.login", func(event Event) error {
+		atomic.AddInt32(&onceCalls, 1)
+		return nil
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			dispatcher.Dispatch(NewEvent("user.login", nil))
+		}()
+	}
+	wg.Wait()
+	if onceCalls != 1 {
+		t.Errorf("Expected once listener to fire exactly once, fired %d times", onceCalls)
+	}
+	if dispatcher.HasListeners("user.login") {
+		t.Error("Expected once listener to be removed after firing")
+	}
+
+	// 移除指定实例，同名的其他监听器不受影响
+	var first, second int32
+	firstListener := NewListener("audit", func(event Event) error {
+		atomic.AddInt32(&first, 1)
+		return nil
+	})
+	secondListener := NewListener("audit", func(event Event) error {
+		atomic.AddInt32(&second, 1)
+		return nil
+	})
+	dispatcher.Listen("user.logout", firstListener)
+	dispatcher.Listen("user.logout", secondListener)
+	dispatcher.Dispatch(NewEvent("user.logout", nil))
+
+	dispatcher.ForgetListener("user.logout", firstListener)
+	dispatcher.Dispatch(NewEvent("user.logout", nil))
+	if first != 1 || second != 2 {
+		t.Errorf("Expected forgotten listener to stop receiving events, got first=%d second=%d", first, second)
+	}
+
+	// 未触发的一次性监听器可以提前移除
+	var cancelled int32
+	listener := dispatcher.ListenOnce("user.logout", func(event Event) error {
+		atomic.AddInt32(&cancelled, 1)
+		return nil
+	})
+	dispatcher.ForgetListener("user.logout", listener)
+	dispatcher.Forget("user.logout", "audit")
+	dispatcher.Dispatch(NewEvent("user.logout", nil))
+	if cancelled != 0 || second != 2 || dispatcher.HasListeners("user.logout") {
+		t.Errorf("Expected no deliveries after forgetting, got cancelled=%d second=%d", cancelled, second)
+	}
+
+	// 注册、移除与分发并发执行
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			l := NewListener("concurrent", func(event Event) error { return nil })
+			dispatcher.Listen("concurrent", l)
+			dispatcher.ForgetListener("concurrent", l)
+		}()
+		go func() {
+			defer wg.Done()
+			dispatcher.Dispatch(NewEvent("concurrent", nil))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFailedEventStore(t *testing.T) {
+	dispatcher := NewEventDispatcher(nil)
+	defer dispatcher.Close()
+	store := NewMemoryFailedEventStore()
+	dispatcher.SetFailedEventStore(store)
+
+	var mu sync.Mutex
+	healthy := false
+	var delivered []interface{}
+	dispatcher.Listen("invoice.paid", NewListener("send.receipt", func(event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if !healthy {
+			return errors.New("smtp unavailable")
+		}
+		delivered = append(delivered, event.GetPayload())
+		return nil
+	}))
+	dispatcher.Listen("invoice.paid", NewListener("audit", func(event Event) error {
+		return nil
+	}))
+
+	paid := NewEvent("invoice.paid", 1001)
+	dispatcher.Dispatch(paid)
+	dispatcher.Dispatch(NewEvent("invoice.paid", 1002))
+
+	// 失败的事件连同错误、监听器和失败次数一起记录
+	failed, err := dispatcher.GetFailedEvents(10)
+	if err != nil || len(failed) != 2 {
+		t.Fatalf("Expected 2 failed events, got %d, %v", len(failed), err)
+	}
+	record, err := store.Find(failedEventID(paid.GetID(), "send.receipt"))
+	if err != nil {
+		t.Fatalf("Expected failed event to be recorded: %v", err)
+	}
+	if record.Listener != "send.receipt" || record.Error != "smtp unavailable" || record.Attempts != 1 || record.EventName != "invoice.paid" {
+		t.Errorf("Unexpected failed event record %+v", record)
+	}
+	if limited, _ := dispatcher.GetFailedEvents(1); len(limited) != 1 || limited[0].Payload != 1002 {
+		t.Errorf("Expected newest failed event first, got %+v", limited)
+	}
+
+	// 重放仍然失败时累加失败次数
+	if err := dispatcher.ReplayFailedEvent(record.ID); err == nil {
+		t.Error("Expected replay to fail while listener is unhealthy")
+	}
+	if record, _ := store.Find(record.ID); record.Attempts != 2 {
+		t.Errorf("Expected attempts to be 2, got %d", record.Attempts)
+	}
+
+	// 修复后重放重新调用失败的监听器并删除记录
+	mu.Lock()
+	healthy = true
+	mu.Unlock()
+	if err := dispatcher.ReplayFailedEvent(record.ID); err != nil {
+		t.Fatalf("Failed to replay event: %v", err)
+	}
+	if len(delivered) != 1 || delivered[0] != 1001 {
+		t.Errorf("Expected replay to re-invoke the listener, delivered %v", delivered)
+	}
+	if _, err := store.Find(record.ID); !errors.Is(err, ErrFailedEventNotFound) {
+		t.Errorf("Expected replayed event to be removed, got %v", err)
+	}
+	if err := dispatcher.ReplayFailedEvent(record.ID); !errors.Is(err, ErrFailedEventNotFound) {
+		t.Errorf("Expected ErrFailedEventNotFound, got %v", err)
+	}
+
+	// 从外部存储读取的记录重建事件
+	rebuilt := (&FailedEvent{EventID: "evt-1", EventName: "invoice.paid", Payload: 7, Data: map[string]interface{}{"k": "v"}}).Event()
+	if rebuilt.GetID() != "evt-1" || rebuilt.GetPayload() != 7 || rebuilt.GetDataByKey("k") != "v" {
+		t.Errorf("Unexpected rebuilt event %v", rebuilt)
+	}
+}
+
 func TestEventQueue(t *testing.T) {
 	// 创建内存队列
 	queue := NewMemoryEventQueue()
@@ -0,0 +1,214 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrFailedEventNotFound 失败事件不存在
+var ErrFailedEventNotFound = errors.New("failed event not found")
+
+// FailedEvent 监听器处理失败的事件
+//
+// 同一个事件在同一个监听器上的失败只保留一条记录，重复失败时累加 Attempts。
+type FailedEvent struct {
+	ID        string                 `json:"id"`
+	EventID   string                 `json:"event_id"`
+	EventName string                 `json:"event_name"`
+	Payload   interface{}            `json:"payload"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+	Listener  string                 `json:"listener"`
+	Error     string                 `json:"error"`
+	Attempts  int                    `json:"attempts"`
+	FailedAt  time.Time              `json:"failed_at"`
+
+	// event 原始事件，只在内存存储中保留
+	event Event
+}
+
+// newFailedEvent 根据失败的事件和监听器创建记录
+func newFailedEvent(event Event, listener string, err error) *FailedEvent {
+	return &FailedEvent{
+		ID:        failedEventID(event.GetID(), listener),
+		EventID:   event.GetID(),
+		EventName: event.GetName(),
+		Payload:   event.GetPayload(),
+		Data:      event.GetData(),
+		Listener:  listener,
+		Error:     err.Error(),
+		Attempts:  1,
+		FailedAt:  time.Now(),
+		event:     event,
+	}
+}
+
+// failedEventID 失败记录的 ID
+func failedEventID(eventID, listener string) string {
+	return eventID + ":" + listener
+}
+
+// Event 获取用于重放的事件
+//
+// 内存存储返回原始事件；从 Redis 等存储读取的记录重建为 BaseEvent，
+// 载荷为 JSON 解码后的值。
+func (f *FailedEvent) Event() Event {
+	if f.event != nil {
+		return f.event
+	}
+
+	event := NewEvent(f.EventName, f.Payload)
+	event.ID = f.EventID
+	for key, value := range f.Data {
+		event.SetData(key, value)
+	}
+	return event
+}
+
+// FailedEventStore 失败事件存储
+type FailedEventStore interface {
+	// Record 记录失败事件，ID 相同的记录累加失败次数
+	Record(failed *FailedEvent) error
+	// List 按失败时间从新到旧列出失败事件，limit 不大于 0 时返回全部
+	List(limit int) ([]*FailedEvent, error)
+	// Find 查找失败事件，不存在时返回 ErrFailedEventNotFound
+	Find(id string) (*FailedEvent, error)
+	// Delete 删除失败事件
+	Delete(id string) error
+}
+
+// MemoryFailedEventStore 内存失败事件存储
+type MemoryFailedEventStore struct {
+	mu     sync.RWMutex
+	events map[string]*FailedEvent
+}
+
+// NewMemoryFailedEventStore 创建内存失败事件存储
+func NewMemoryFailedEventStore() *MemoryFailedEventStore {
+	return &MemoryFailedEventStore{
+		events: make(map[string]*FailedEvent),
+	}
+}
+
+// Record 记录失败事件
+func (s *MemoryFailedEventStore) Record(failed *FailedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := *failed
+	if existing, ok := s.events[failed.ID]; ok {
+		record.Attempts = existing.Attempts + failed.Attempts
+	}
+	s.events[failed.ID] = &record
+	return nil
+}
+
+// List 按失败时间从新到旧列出失败事件
+func (s *MemoryFailedEventStore) List(limit int) ([]*FailedEvent, error) {
+	s.mu.RLock()
+	events := make([]*FailedEvent, 0, len(s.events))
+	for _, failed := range s.events {
+		record := *failed
+		events = append(events, &record)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].FailedAt.After(events[j].FailedAt)
+	})
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// Find 查找失败事件
+func (s *MemoryFailedEventStore) Find(id string) (*FailedEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	failed, ok := s.events[id]
+	if !ok {
+		return nil, ErrFailedEventNotFound
+	}
+	record := *failed
+	return &record, nil
+}
+
+// Delete 删除失败事件
+func (s *MemoryFailedEventStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.events, id)
+	return nil
+}
+
+// SetFailedEventStore 设置失败事件存储，设置后监听器处理失败的事件会被记录
+func (d *EventDispatcher) SetFailedEventStore(store FailedEventStore) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failedStore = store
+}
+
+// failedEventStore 获取失败事件存储（内部方法）
+func (d *EventDispatcher) failedEventStore() FailedEventStore {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.failedStore
+}
+
+// recordFailure 记录监听器处理失败的事件（内部方法）
+func (d *EventDispatcher) recordFailure(event Event, listener Listener, err error) {
+	store := d.failedEventStore()
+	if store == nil {
+		return
+	}
+	if recordErr := store.Record(newFailedEvent(event, listener.GetName(), err)); recordErr != nil {
+		log.Printf("Failed to record failed event %s: %v", event.GetName(), recordErr)
+	}
+}
+
+// GetFailedEvents 获取最近的失败事件
+func (d *EventDispatcher) GetFailedEvents(limit int) ([]*FailedEvent, error) {
+	store := d.failedEventStore()
+	if store == nil {
+		return nil, fmt.Errorf("failed event store not configured")
+	}
+	return store.List(limit)
+}
+
+// ReplayFailedEvent 重新调用失败的监听器处理事件
+//
+// 只调用记录中失败的那个监听器，处理成功后删除记录；再次失败时累加失败次数并返回错误。
+func (d *EventDispatcher) ReplayFailedEvent(id string) error {
+	store := d.failedEventStore()
+	if store == nil {
+		return fmt.Errorf("failed event store not configured")
+	}
+
+	failed, err := store.Find(id)
+	if err != nil {
+		return err
+	}
+
+	var target Listener
+	for _, listener := range d.getListeners(failed.EventName) {
+		if listener.GetName() == failed.Listener {
+			target = listener
+			break
+		}
+	}
+	if target == nil {
+		return &ListenerError{ListenerName: failed.Listener, EventName: failed.EventName, Message: "replay failed", Err: ErrListenerNotFound}
+	}
+
+	event := failed.Event()
+	if err := d.handleListener(target, event); err != nil {
+		d.recordFailure(event, target, err)
+		return &ListenerError{ListenerName: failed.Listener, EventName: failed.EventName, Message: "replay failed", Err: err}
+	}
+	return store.Delete(id)
+}
@@ -0,0 +1,132 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisFailedEventClient Redis 失败事件存储使用的客户端接口，*redis.Client 实现了该接口
+type RedisFailedEventClient interface {
+	HGet(ctx context.Context, key, field string) *redis.StringCmd
+	HMGet(ctx context.Context, key string, fields ...string) *redis.SliceCmd
+	ZRevRange(ctx context.Context, key string, start, stop int64) *redis.StringSliceCmd
+	TxPipeline() redis.Pipeliner
+}
+
+// RedisFailedEventStore Redis 失败事件存储
+//
+// 记录保存在哈希 {prefix}failed_events 中，有序集合 {prefix}failed_events:index 按失败时间索引。
+type RedisFailedEventStore struct {
+	client RedisFailedEventClient
+	prefix string
+}
+
+// NewRedisFailedEventStore 创建 Redis 失败事件存储，prefix 会添加到所有键前
+func NewRedisFailedEventStore(client RedisFailedEventClient, prefix string) *RedisFailedEventStore {
+	return &RedisFailedEventStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// hashKey 保存失败记录的哈希键
+func (s *RedisFailedEventStore) hashKey() string {
+	return s.prefix + "failed_events"
+}
+
+// indexKey 按失败时间排序的索引键
+func (s *RedisFailedEventStore) indexKey() string {
+	return s.prefix + "failed_events:index"
+}
+
+// Record 记录失败事件
+func (s *RedisFailedEventStore) Record(failed *FailedEvent) error {
+	ctx := context.Background()
+
+	record := *failed
+	if existing, err := s.Find(failed.ID); err == nil {
+		record.Attempts = existing.Attempts + failed.Attempts
+	}
+
+	data, err := json.Marshal(&record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal failed event: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, s.hashKey(), record.ID, data)
+	pipe.ZAdd(ctx, s.indexKey(), &redis.Z{Score: float64(record.FailedAt.UnixNano()), Member: record.ID})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record failed event: %w", err)
+	}
+	return nil
+}
+
+// List 按失败时间从新到旧列出失败事件
+func (s *RedisFailedEventStore) List(limit int) ([]*FailedEvent, error) {
+	ctx := context.Background()
+
+	stop := int64(limit) - 1
+	if limit <= 0 {
+		stop = -1
+	}
+	ids, err := s.client.ZRevRange(ctx, s.indexKey(), 0, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed events: %w", err)
+	}
+	if len(ids) == 0 {
+		return []*FailedEvent{}, nil
+	}
+
+	values, err := s.client.HMGet(ctx, s.hashKey(), ids...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list failed events: %w", err)
+	}
+
+	events := make([]*FailedEvent, 0, len(values))
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		failed := &FailedEvent{}
+		if err := json.Unmarshal([]byte(data), failed); err != nil {
+			continue
+		}
+		events = append(events, failed)
+	}
+	return events, nil
+}
+
+// Find 查找失败事件
+func (s *RedisFailedEventStore) Find(id string) (*FailedEvent, error) {
+	data, err := s.client.HGet(context.Background(), s.hashKey(), id).Result()
+	if err == redis.Nil {
+		return nil, ErrFailedEventNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find failed event: %w", err)
+	}
+
+	failed := &FailedEvent{}
+	if err := json.Unmarshal([]byte(data), failed); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal failed event: %w", err)
+	}
+	return failed, nil
+}
+
+// Delete 删除失败事件
+func (s *RedisFailedEventStore) Delete(id string) error {
+	ctx := context.Background()
+
+	pipe := s.client.TxPipeline()
+	pipe.HDel(ctx, s.hashKey(), id)
+	pipe.ZRem(ctx, s.indexKey(), id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to delete failed event: %w", err)
+	}
+	return nil
+}
@@ -86,6 +86,12 @@ func (l *BaseListener) String() string {
 	return fmt.Sprintf("Listener{Name: %s, Priority: %d, Queue: %s}", l.name, l.priority, l.queue)
 }
 
+// onceListener 只处理一次事件的监听器
+type onceListener struct {
+	*BaseListener
+	fired int32
+}
+
 // FunctionListener 函数监听器
 type FunctionListener struct {
 	*BaseListener
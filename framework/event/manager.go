@@ -43,6 +43,22 @@ func Forget(eventName string, listenerName string) {
 	GlobalEventManager.dispatcher.Forget(eventName, listenerName)
 }
 
+// ListenOnce 监听事件，第一次处理后自动移除
+func ListenOnce(eventName string, handler func(Event) error) Listener {
+	if GlobalEventManager == nil {
+		Init()
+	}
+	return GlobalEventManager.ListenOnce(eventName, handler)
+}
+
+// ForgetListener 移除指定的监听器实例
+func ForgetListener(eventName string, listener Listener) {
+	if GlobalEventManager == nil {
+		return
+	}
+	GlobalEventManager.ForgetListener(eventName, listener)
+}
+
 // ForgetMany 忘记多个事件
 func ForgetMany(eventNames []string) {
 	if GlobalEventManager == nil {
@@ -68,11 +84,59 @@ func DispatchAsync(event Event) error {
 }
 
 // DispatchBatch 批量分发事件
-func DispatchBatch(events []Event) error {
+func DispatchBatch(events []Event) *BatchResult {
+	if GlobalEventManager == nil {
+		Init()
+	}
+	return GlobalEventManager.DispatchBatch(events)
+}
+
+// DispatchBatchAsync 并发分发一批事件
+func DispatchBatchAsync(events []Event, workers int) *BatchResult {
+	if GlobalEventManager == nil {
+		Init()
+	}
+	return GlobalEventManager.DispatchBatchAsync(events, workers)
+}
+
+// DispatchUntil 分发事件，返回第一个满足 predicate 的监听器结果
+func DispatchUntil(event Event, predicate func(result interface{}) bool) (interface{}, error) {
+	if GlobalEventManager == nil {
+		Init()
+	}
+	return GlobalEventManager.DispatchUntil(event, predicate)
+}
+
+// DispatchCollect 分发事件，返回所有监听器的结果
+func DispatchCollect(event Event) ([]interface{}, error) {
+	if GlobalEventManager == nil {
+		Init()
+	}
+	return GlobalEventManager.DispatchCollect(event)
+}
+
+// SetFailedEventStore 设置全局事件管理器的失败事件存储
+func SetFailedEventStore(store FailedEventStore) {
+	if GlobalEventManager == nil {
+		Init()
+	}
+	GlobalEventManager.SetFailedEventStore(store)
+}
+
+// GetFailedEvents 获取最近的失败事件
+func GetFailedEvents(limit int) ([]*FailedEvent, error) {
+	if GlobalEventManager == nil {
+		Init()
+	}
+	return GlobalEventManager.GetFailedEvents(limit)
+}
+
+// ReplayFailedEvent 重新调用失败的监听器处理事件
+func ReplayFailedEvent(id string) error {
 	if GlobalEventManager == nil {
 		Init()
 	}
-	return GlobalEventManager.dispatcher.DispatchBatch(events)
+	return GlobalEventManager.ReplayFailedEvent(id)
 }
 
 // Queue 队列事件
@@ -0,0 +1,115 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ResultListener 返回处理结果的监听器
+//
+// DispatchUntil 和 DispatchCollect 通过 HandleResult 获取监听器的返回值，
+// 普通监听器的结果为 nil。
+type ResultListener interface {
+	Listener
+	HandleResult(event Event) (interface{}, error)
+}
+
+// BaseResultListener 基础结果监听器实现
+type BaseResultListener struct {
+	*BaseListener
+	resultHandler func(Event) (interface{}, error)
+}
+
+// NewResultListener 创建返回处理结果的监听器
+func NewResultListener(name string, handler func(Event) (interface{}, error)) *BaseResultListener {
+	listener := &BaseResultListener{
+		resultHandler: handler,
+	}
+	listener.BaseListener = NewListener(name, func(event Event) error {
+		_, err := listener.HandleResult(event)
+		return err
+	})
+	return listener
+}
+
+// HandleResult 处理事件并返回结果
+func (l *BaseResultListener) HandleResult(event Event) (interface{}, error) {
+	if l.resultHandler == nil {
+		return nil, fmt.Errorf("listener handler is nil")
+	}
+	return l.resultHandler(event)
+}
+
+// DispatchUntil 按优先级依次调用监听器，返回第一个满足 predicate 的结果
+//
+// 找到满足条件的结果后不再调用后续监听器；所有结果都不满足时返回 nil。监听器返回错误或
+// panic 时立即停止并返回该错误。队列监听器不产生结果，不会被调用。
+func (d *EventDispatcher) DispatchUntil(event Event, predicate func(result interface{}) bool) (interface{}, error) {
+	if d.closed {
+		return nil, ErrDispatcherClosed
+	}
+	event.SetPropagated(true)
+
+	for _, listener := range d.getListeners(event.GetName()) {
+		if listener.ShouldQueue() {
+			continue
+		}
+
+		result, err := d.handleListenerResult(listener, event)
+		if err != nil {
+			return nil, &ListenerError{ListenerName: listener.GetName(), EventName: event.GetName(), Message: "handle failed", Err: err}
+		}
+		if predicate(result) {
+			return result, nil
+		}
+	}
+	return nil, nil
+}
+
+// DispatchCollect 按优先级调用所有监听器，返回所有结果
+//
+// 单个监听器返回错误或 panic 不会中断其他监听器，结果中只包含成功的监听器的返回值，
+// 失败的监听器的错误汇总后与结果一起返回。队列监听器不产生结果，不会被调用。
+func (d *EventDispatcher) DispatchCollect(event Event) ([]interface{}, error) {
+	if d.closed {
+		return nil, ErrDispatcherClosed
+	}
+	event.SetPropagated(true)
+
+	results := make([]interface{}, 0)
+	var errs []error
+	for _, listener := range d.getListeners(event.GetName()) {
+		if listener.ShouldQueue() {
+			continue
+		}
+
+		result, err := d.handleListenerResult(listener, event)
+		if err != nil {
+			errs = append(errs, &ListenerError{ListenerName: listener.GetName(), EventName: event.GetName(), Message: "handle failed", Err: err})
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+// handleListenerResult 调用监听器并获取结果（内部方法）
+func (d *EventDispatcher) handleListenerResult(listener Listener, event Event) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Listener %s panicked while handling event %s: %v", listener.GetName(), event.GetName(), r)
+			result, err = nil, fmt.Errorf("listener panicked: %v", r)
+		}
+	}()
+
+	if resultListener, ok := listener.(ResultListener); ok {
+		return resultListener.HandleResult(event)
+	}
+	return nil, listener.Handle(event)
+}
+
+// NonNil DispatchUntil 的条件函数，匹配第一个非 nil 的结果
+func NonNil(result interface{}) bool {
+	return result != nil
+}
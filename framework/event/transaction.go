@@ -0,0 +1,29 @@
+package event
+
+import "log"
+
+// CommitHooks 支持提交回调的事务，例如 *database.Tx
+type CommitHooks interface {
+	// AfterCommit 注册事务提交成功后执行的回调，事务回滚时回调被丢弃
+	AfterCommit(fn func())
+}
+
+// DispatchAfterCommit 在事务提交后分发事件
+//
+// 事件先缓存在事务中，提交成功后按调用顺序分发；事务回滚时事件被丢弃，
+// 避免“欢迎邮件已发送但用户记录被回滚”的问题。
+func (em *EventManager) DispatchAfterCommit(tx CommitHooks, event Event) {
+	tx.AfterCommit(func() {
+		if err := em.Dispatch(event); err != nil {
+			log.Printf("Failed to dispatch event %s after commit: %v", event.GetName(), err)
+		}
+	})
+}
+
+// DispatchAfterCommit 在事务提交后分发事件，事务回滚时事件被丢弃
+func DispatchAfterCommit(tx CommitHooks, event Event) {
+	if GlobalEventManager == nil {
+		Init()
+	}
+	GlobalEventManager.DispatchAfterCommit(tx, event)
+}
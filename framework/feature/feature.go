@@ -0,0 +1,222 @@
+package feature
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sync"
+
+	"laravel-go/framework/auth"
+)
+
+// ErrFlagNotFound 功能开关不存在
+var ErrFlagNotFound = errors.New("feature flag not found")
+
+// Flag 功能开关
+//
+// 关闭的开关对所有人返回 false。开启时先匹配定向规则，命中任意一条返回 true，
+// 否则按 Percentage 放量：用户 ID 和开关名称的哈希决定用户所在的分桶，
+// 同一用户的结果在比例不变时始终一致。Percentage 为 100 时对所有人开启，为 0 时只对定向用户开启。
+type Flag struct {
+	Name       string `json:"name"`
+	Enabled    bool   `json:"enabled"`
+	Percentage int    `json:"percentage"`
+	Rules      []Rule `json:"rules,omitempty"`
+}
+
+// Rule 定向规则，Attribute 的值在 Values 中时命中
+//
+// Attribute 为 user_id 时匹配用户 ID，为 role 时匹配用户的任意角色，其他值匹配 Context.Attributes。
+type Rule struct {
+	Attribute string   `json:"attribute"`
+	Values    []string `json:"values"`
+}
+
+// Boolean 创建对所有人开启或关闭的开关
+func Boolean(name string, enabled bool) *Flag {
+	return &Flag{Name: name, Enabled: enabled, Percentage: 100}
+}
+
+// Rollout 创建按比例放量的开关
+func Rollout(name string, percentage int) *Flag {
+	return &Flag{Name: name, Enabled: true, Percentage: percentage}
+}
+
+// Target 添加定向规则
+func (f *Flag) Target(attribute string, values ...string) *Flag {
+	f.Rules = append(f.Rules, Rule{Attribute: attribute, Values: values})
+	return f
+}
+
+// Context 开关的求值上下文
+type Context struct {
+	UserID     string
+	Roles      []string
+	Attributes map[string]interface{}
+}
+
+// Evaluate 对上下文求值
+func (f *Flag) Evaluate(ctx Context) bool {
+	if !f.Enabled {
+		return false
+	}
+	for _, rule := range f.Rules {
+		if rule.matches(ctx) {
+			return true
+		}
+	}
+
+	switch {
+	case f.Percentage >= 100:
+		return true
+	case f.Percentage <= 0 || ctx.UserID == "":
+		return false
+	}
+	return bucket(f.Name, ctx.UserID) < f.Percentage
+}
+
+// matches 检查上下文是否命中规则
+func (r Rule) matches(ctx Context) bool {
+	var actual []string
+	switch r.Attribute {
+	case "user_id":
+		actual = []string{ctx.UserID}
+	case "role":
+		actual = ctx.Roles
+	default:
+		value, ok := ctx.Attributes[r.Attribute]
+		if !ok {
+			return false
+		}
+		actual = []string{fmt.Sprint(value)}
+	}
+
+	for _, value := range actual {
+		for _, expected := range r.Values {
+			if value == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bucket 计算用户在开关中的分桶（0-99），不同开关的分桶相互独立
+func bucket(flag, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(flag + ":" + userID))
+	return int(h.Sum32() % 100)
+}
+
+// Store 功能开关存储，修改在下一次求值时生效
+type Store interface {
+	// Get 获取开关，不存在时返回 ErrFlagNotFound
+	Get(name string) (*Flag, error)
+	// Set 创建或替换开关
+	Set(flag *Flag) error
+	// Delete 删除开关
+	Delete(name string) error
+	// All 获取全部开关
+	All() ([]*Flag, error)
+}
+
+// Manager 功能开关管理器
+type Manager struct {
+	store Store
+}
+
+// NewManager 创建功能开关管理器
+func NewManager(store Store) *Manager {
+	return &Manager{store: store}
+}
+
+// Store 获取存储
+func (m *Manager) Store() Store {
+	return m.store
+}
+
+// Evaluate 对上下文求值，开关不存在时返回 ErrFlagNotFound
+func (m *Manager) Evaluate(name string, ctx Context) (bool, error) {
+	flag, err := m.store.Get(name)
+	if err != nil {
+		return false, err
+	}
+	return flag.Evaluate(ctx), nil
+}
+
+// IsEnabled 检查开关是否对上下文开启，开关不存在或读取失败时返回 false
+func (m *Manager) IsEnabled(name string, ctx Context) bool {
+	enabled, _ := m.Evaluate(name, ctx)
+	return enabled
+}
+
+// ContextResolver 从请求解析求值上下文
+type ContextResolver func(r *http.Request) Context
+
+// Middleware 要求开关开启的路由中间件，未开启时返回 404，resolve 为 nil 时使用 RequestContext
+func (m *Manager) Middleware(name string, resolve ContextResolver) func(http.Handler) http.Handler {
+	if resolve == nil {
+		resolve = RequestContext
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !m.IsEnabled(name, resolve(r)) {
+				http.NotFound(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestContext 使用认证中间件保存到请求 context 中的用户作为求值上下文
+//
+// 用户实现 GetRoles() []string 时同时读取角色。
+func RequestContext(r *http.Request) Context {
+	user := auth.UserFromContext(r.Context())
+	if user == nil {
+		return Context{}
+	}
+	ctx := Context{UserID: fmt.Sprint(user.GetID())}
+	if roles, ok := user.(interface{ GetRoles() []string }); ok {
+		ctx.Roles = roles.GetRoles()
+	}
+	return ctx
+}
+
+var (
+	defaultManager *Manager
+	defaultMutex   sync.Mutex
+)
+
+// SetDefault 设置全局功能开关管理器
+func SetDefault(manager *Manager) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultManager = manager
+}
+
+// Default 获取全局功能开关管理器，未设置时使用内存存储
+func Default() *Manager {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	if defaultManager == nil {
+		defaultManager = NewManager(NewMemoryStore())
+	}
+	return defaultManager
+}
+
+// IsEnabled 使用全局管理器检查开关是否开启
+func IsEnabled(name string, ctx Context) bool {
+	return Default().IsEnabled(name, ctx)
+}
+
+// Require 使用全局管理器的路由中间件
+func Require(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Default().Middleware(name, nil)(next).ServeHTTP(w, r)
+		})
+	}
+}
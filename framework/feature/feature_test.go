@@ -0,0 +1,183 @@
+package feature
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"laravel-go/framework/auth"
+	"laravel-go/framework/database"
+)
+
+func TestPercentageRolloutIsStable(t *testing.T) {
+	manager := NewManager(NewMemoryStore(Rollout("new-checkout", 50)))
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		ctx := Context{UserID: strconv.Itoa(i)}
+		first := manager.IsEnabled("new-checkout", ctx)
+		for j := 0; j < 5; j++ {
+			if manager.IsEnabled("new-checkout", ctx) != first {
+				t.Fatalf("Expected user %d to be consistently binned", i)
+			}
+		}
+		if first {
+			enabled++
+		}
+	}
+	if enabled < 400 || enabled > 600 {
+		t.Errorf("Expected about half of the users to be enabled, got %d", enabled)
+	}
+
+	// 扩大比例时已开启的用户保持开启
+	before := make(map[int]bool)
+	for i := 0; i < 1000; i++ {
+		before[i] = manager.IsEnabled("new-checkout", Context{UserID: strconv.Itoa(i)})
+	}
+	manager.Store().Set(Rollout("new-checkout", 80))
+	for i := 0; i < 1000; i++ {
+		if before[i] && !manager.IsEnabled("new-checkout", Context{UserID: strconv.Itoa(i)}) {
+			t.Fatalf("Expected user %d to stay enabled after increasing the rollout", i)
+		}
+	}
+
+	// 没有用户 ID 时无法分桶
+	if manager.IsEnabled("new-checkout", Context{}) {
+		t.Error("Expected anonymous context to be excluded from a partial rollout")
+	}
+	if manager.IsEnabled("missing", Context{UserID: "1"}) {
+		t.Error("Expected missing flag to be disabled")
+	}
+}
+
+func TestTargetingRules(t *testing.T) {
+	store := NewMemoryStore(
+		Rollout("beta-dashboard", 0).Target("role", "admin", "staff").Target("plan", "enterprise"),
+		Boolean("dark-mode", true),
+		Boolean("legacy-export", false).Target("role", "admin"),
+	)
+	manager := NewManager(store)
+
+	cases := []struct {
+		flag     string
+		ctx      Context
+		expected bool
+	}{
+		{"beta-dashboard", Context{UserID: "1", Roles: []string{"editor", "admin"}}, true},
+		{"beta-dashboard", Context{UserID: "2", Roles: []string{"editor"}}, false},
+		{"beta-dashboard", Context{UserID: "3", Attributes: map[string]interface{}{"plan": "enterprise"}}, true},
+		{"beta-dashboard", Context{UserID: "4", Attributes: map[string]interface{}{"plan": "free"}}, false},
+		{"dark-mode", Context{}, true},
+		{"legacy-export", Context{Roles: []string{"admin"}}, false},
+	}
+	for _, c := range cases {
+		if got := manager.IsEnabled(c.flag, c.ctx); got != c.expected {
+			t.Errorf("IsEnabled(%s, %+v) = %v, expected %v", c.flag, c.ctx, got, c.expected)
+		}
+	}
+
+	// 修改在运行时生效
+	store.Set(Boolean("dark-mode", false))
+	if manager.IsEnabled("dark-mode", Context{}) {
+		t.Error("Expected flag change to take effect immediately")
+	}
+
+	path := filepath.Join(t.TempDir(), "features.json")
+	os.WriteFile(path, []byte(`[{"name": "search-v2", "enabled": true}, {"name": "chat", "enabled": true, "percentage": 0, "rules": [{"attribute": "user_id", "values": ["42"]}]}]`), 0644)
+	if err := store.LoadFile(path); err != nil {
+		t.Fatalf("Failed to load flags: %v", err)
+	}
+	if !manager.IsEnabled("search-v2", Context{UserID: "1"}) {
+		t.Error("Expected flag without percentage to be enabled for everyone")
+	}
+	if !manager.IsEnabled("chat", Context{UserID: "42"}) || manager.IsEnabled("chat", Context{UserID: "43"}) {
+		t.Error("Expected chat to be enabled only for user 42")
+	}
+}
+
+type roleUser struct {
+	auth.BaseUser
+	roles []string
+}
+
+func (u *roleUser) GetRoles() []string {
+	return u.roles
+}
+
+func TestMiddleware(t *testing.T) {
+	SetDefault(NewManager(NewMemoryStore(Rollout("reports", 0).Target("role", "admin"))))
+	defer SetDefault(nil)
+
+	handler := Require("reports")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+
+	send := func(user auth.User) int {
+		request := httptest.NewRequest(http.MethodGet, "/reports", nil)
+		if user != nil {
+			request = request.WithContext(auth.WithUser(request.Context(), "web", user))
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	if code := send(&roleUser{BaseUser: auth.BaseUser{ID: 1}, roles: []string{"admin"}}); code != http.StatusOK {
+		t.Errorf("Expected admin to pass, got %d", code)
+	}
+	if code := send(&roleUser{BaseUser: auth.BaseUser{ID: 2}, roles: []string{"member"}}); code != http.StatusNotFound {
+		t.Errorf("Expected member to be rejected, got %d", code)
+	}
+	if code := send(nil); code != http.StatusNotFound {
+		t.Errorf("Expected guest to be rejected, got %d", code)
+	}
+}
+
+func TestDatabaseStore(t *testing.T) {
+	conn, err := database.NewConnection(&database.ConnectionConfig{
+		Driver:   database.SQLite,
+		Database: filepath.Join(t.TempDir(), "features.db"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer conn.Close()
+
+	store := NewDatabaseStore(conn, "")
+	if err := store.CreateTable(); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := store.Get("beta"); err != ErrFlagNotFound {
+		t.Errorf("Expected ErrFlagNotFound, got %v", err)
+	}
+
+	if err := store.Set(Rollout("beta", 30).Target("role", "admin")); err != nil {
+		t.Fatalf("Failed to save flag: %v", err)
+	}
+	if err := store.Set(Boolean("beta", true)); err != nil {
+		t.Fatalf("Failed to update flag: %v", err)
+	}
+	store.Set(Boolean("alpha", false))
+
+	flag, err := store.Get("beta")
+	if err != nil || !flag.Enabled || flag.Percentage != 100 || len(flag.Rules) != 0 {
+		t.Errorf("Unexpected flag: %+v, %v", flag, err)
+	}
+	if flags, _ := store.All(); len(flags) != 2 || flags[0].Name != "alpha" {
+		t.Errorf("Unexpected flags: %+v", flags)
+	}
+
+	store.Set(Rollout("beta", 0).Target("role", "admin"))
+	manager := NewManager(store)
+	if !manager.IsEnabled("beta", Context{Roles: []string{"admin"}}) || manager.IsEnabled("beta", Context{UserID: "1"}) {
+		t.Error("Expected targeting rules to be loaded from the database")
+	}
+
+	store.Delete("beta")
+	if manager.IsEnabled("beta", Context{Roles: []string{"admin"}}) {
+		t.Error("Expected deleted flag to be disabled")
+	}
+}
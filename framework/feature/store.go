@@ -0,0 +1,250 @@
+package feature
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"laravel-go/framework/database"
+)
+
+// MemoryStore 内存功能开关存储，可以从 JSON 配置文件加载
+type MemoryStore struct {
+	flags map[string]Flag
+	mu    sync.RWMutex
+}
+
+// NewMemoryStore 创建内存存储
+func NewMemoryStore(flags ...*Flag) *MemoryStore {
+	s := &MemoryStore{flags: make(map[string]Flag)}
+	for _, flag := range flags {
+		s.flags[flag.Name] = *flag
+	}
+	return s
+}
+
+// LoadFile 从 JSON 文件加载开关，文件内容为开关数组，已有的同名开关会被替换
+//
+// 未指定 percentage 的开关视为 100，即开启时对所有人生效。
+func (s *MemoryStore) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to parse feature flags %s: %w", path, err)
+	}
+
+	flags := make([]Flag, 0, len(raw))
+	for _, item := range raw {
+		flag := Flag{Percentage: 100}
+		if err := json.Unmarshal(item, &flag); err != nil {
+			return fmt.Errorf("failed to parse feature flags %s: %w", path, err)
+		}
+		flags = append(flags, flag)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, flag := range flags {
+		s.flags[flag.Name] = flag
+	}
+	return nil
+}
+
+// Get 获取开关
+func (s *MemoryStore) Get(name string) (*Flag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	flag, ok := s.flags[name]
+	if !ok {
+		return nil, ErrFlagNotFound
+	}
+	return &flag, nil
+}
+
+// Set 创建或替换开关
+func (s *MemoryStore) Set(flag *Flag) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flags[flag.Name] = *flag
+	return nil
+}
+
+// Delete 删除开关
+func (s *MemoryStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.flags, name)
+	return nil
+}
+
+// All 按名称顺序获取全部开关
+func (s *MemoryStore) All() ([]*Flag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	flags := make([]*Flag, 0, len(s.flags))
+	for _, flag := range s.flags {
+		flag := flag
+		flags = append(flags, &flag)
+	}
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name < flags[j].Name
+	})
+	return flags, nil
+}
+
+// DatabaseStore 数据库功能开关存储，多个实例共享同一张表，修改对所有实例立即生效
+type DatabaseStore struct {
+	connection database.Connection
+	table      string
+}
+
+// NewDatabaseStore 创建数据库存储，table 为空时使用 feature_flags 表
+func NewDatabaseStore(connection database.Connection, table string) *DatabaseStore {
+	if table == "" {
+		table = "feature_flags"
+	}
+	return &DatabaseStore{
+		connection: connection,
+		table:      table,
+	}
+}
+
+// CreateTable 创建功能开关表
+func (s *DatabaseStore) CreateTable() error {
+	_, err := s.connection.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		name VARCHAR(191) NOT NULL PRIMARY KEY,
+		enabled BOOLEAN NOT NULL DEFAULT 0,
+		percentage INTEGER NOT NULL DEFAULT 100,
+		rules TEXT
+	)`, s.table))
+	return err
+}
+
+// Get 获取开关
+func (s *DatabaseStore) Get(name string) (*Flag, error) {
+	rows, err := database.NewQueryBuilder(s.connection).Raw(
+		fmt.Sprintf("SELECT name, enabled, percentage, rules FROM %s WHERE name = ?", s.table), name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrFlagNotFound
+	}
+	return flagFromRow(rows[0])
+}
+
+// Set 创建或替换开关
+func (s *DatabaseStore) Set(flag *Flag) error {
+	rules, err := json.Marshal(flag.Rules)
+	if err != nil {
+		return err
+	}
+
+	qb := database.NewQueryBuilder(s.connection)
+	result, err := qb.Statement(
+		fmt.Sprintf("UPDATE %s SET enabled = ?, percentage = ?, rules = ? WHERE name = ?", s.table),
+		flag.Enabled, flag.Percentage, string(rules), flag.Name,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+		return nil
+	}
+
+	_, err = qb.Statement(
+		fmt.Sprintf("INSERT INTO %s (name, enabled, percentage, rules) VALUES (?, ?, ?, ?)", s.table),
+		flag.Name, flag.Enabled, flag.Percentage, string(rules),
+	)
+	return err
+}
+
+// Delete 删除开关
+func (s *DatabaseStore) Delete(name string) error {
+	_, err := database.NewQueryBuilder(s.connection).Statement(fmt.Sprintf("DELETE FROM %s WHERE name = ?", s.table), name)
+	return err
+}
+
+// All 按名称顺序获取全部开关
+func (s *DatabaseStore) All() ([]*Flag, error) {
+	rows, err := database.NewQueryBuilder(s.connection).Raw(
+		fmt.Sprintf("SELECT name, enabled, percentage, rules FROM %s ORDER BY name", s.table),
+	)
+	if err != nil {
+		return nil, err
+	}
+	flags := make([]*Flag, 0, len(rows))
+	for _, row := range rows {
+		flag, err := flagFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// flagFromRow 将数据库行转换为开关
+func flagFromRow(row map[string]interface{}) (*Flag, error) {
+	flag := &Flag{
+		Name:       fmt.Sprint(row["name"]),
+		Enabled:    truthy(row["enabled"]),
+		Percentage: int(integer(row["percentage"])),
+	}
+
+	var rules string
+	switch v := row["rules"].(type) {
+	case string:
+		rules = v
+	case []byte:
+		rules = string(v)
+	}
+	if rules != "" && rules != "null" {
+		if err := json.Unmarshal([]byte(rules), &flag.Rules); err != nil {
+			return nil, fmt.Errorf("invalid rules for feature flag %s: %w", flag.Name, err)
+		}
+	}
+	return flag, nil
+}
+
+// truthy 将数据库中的布尔值转换为 bool
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case []byte:
+		return string(v) == "1" || string(v) == "true"
+	case string:
+		return v == "1" || v == "true"
+	}
+	return integer(value) != 0
+}
+
+// integer 将数据库中的数值转换为 int64
+func integer(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case []byte:
+		var n int64
+		fmt.Sscan(string(v), &n)
+		return n
+	case string:
+		var n int64
+		fmt.Sscan(v, &n)
+		return n
+	}
+	return 0
+}
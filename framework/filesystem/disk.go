@@ -0,0 +1,172 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// 文件系统相关错误
+var (
+	ErrInvalidPath  = errors.New("invalid file path")
+	ErrFileNotFound = errors.New("file not found")
+)
+
+// Disk 文件存储磁盘接口
+type Disk interface {
+	// Put 写入文件，内容以流的方式写入
+	Put(path string, content io.Reader) error
+	// Get 读取文件，调用方负责关闭
+	Get(path string) (io.ReadCloser, error)
+	// Exists 检查文件是否存在
+	Exists(path string) (bool, error)
+	// Delete 删除文件
+	Delete(path string) error
+	// Copy 复制文件
+	Copy(from, to string) error
+	// Move 移动文件
+	Move(from, to string) error
+	// Size 获取文件大小
+	Size(path string) (int64, error)
+	// URL 获取文件访问地址，私有存储返回预签名地址
+	URL(path string) string
+}
+
+// DiskConfig 磁盘配置
+type DiskConfig struct {
+	Driver string // local 或 s3
+	// 本地磁盘
+	Root string
+	// 公开访问地址前缀
+	URL string
+	// S3 兼容存储
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// DiskConfigFromMap 从配置项创建磁盘配置，例如 config.GetMap("filesystems.disks.s3")
+func DiskConfigFromMap(values map[string]interface{}) DiskConfig {
+	get := func(key string) string {
+		if value, ok := values[key]; ok && value != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return ""
+	}
+
+	return DiskConfig{
+		Driver:    get("driver"),
+		Root:      get("root"),
+		URL:       get("url"),
+		Endpoint:  get("endpoint"),
+		Region:    get("region"),
+		Bucket:    get("bucket"),
+		AccessKey: get("key"),
+		SecretKey: get("secret"),
+	}
+}
+
+// NewDisk 根据配置创建磁盘
+func NewDisk(config DiskConfig) (Disk, error) {
+	switch config.Driver {
+	case "local", "":
+		if config.Root == "" {
+			return nil, errors.New("local disk requires a root directory")
+		}
+		return NewLocalDisk(config.Root, config.URL), nil
+	case "s3":
+		if config.Endpoint == "" || config.Bucket == "" {
+			return nil, errors.New("s3 disk requires an endpoint and bucket")
+		}
+		return NewS3Disk(S3Config{
+			Endpoint:  config.Endpoint,
+			Region:    config.Region,
+			Bucket:    config.Bucket,
+			AccessKey: config.AccessKey,
+			SecretKey: config.SecretKey,
+			PublicURL: config.URL,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported filesystem driver: %s", config.Driver)
+	}
+}
+
+// Manager 磁盘管理器
+type Manager struct {
+	disks       map[string]Disk
+	defaultDisk string
+}
+
+// NewManager 创建磁盘管理器
+func NewManager() *Manager {
+	return &Manager{
+		disks:       make(map[string]Disk),
+		defaultDisk: "local",
+	}
+}
+
+// NewManagerFromConfig 根据配置创建磁盘管理器
+func NewManagerFromConfig(configs map[string]DiskConfig, defaultDisk string) (*Manager, error) {
+	manager := NewManager()
+	for name, config := range configs {
+		disk, err := NewDisk(config)
+		if err != nil {
+			return nil, fmt.Errorf("disk %s: %w", name, err)
+		}
+		manager.Extend(name, disk)
+	}
+
+	if defaultDisk != "" {
+		manager.SetDefaultDisk(defaultDisk)
+	}
+	return manager, nil
+}
+
+// Disk 获取指定的磁盘，不存在时返回默认磁盘
+func (m *Manager) Disk(name string) Disk {
+	if disk, exists := m.disks[name]; exists {
+		return disk
+	}
+	return m.disks[m.defaultDisk]
+}
+
+// DefaultDisk 获取默认磁盘
+func (m *Manager) DefaultDisk() Disk {
+	return m.Disk(m.defaultDisk)
+}
+
+// Extend 注册磁盘
+func (m *Manager) Extend(name string, disk Disk) {
+	m.disks[name] = disk
+}
+
+// SetDefaultDisk 设置默认磁盘
+func (m *Manager) SetDefaultDisk(name string) {
+	m.defaultDisk = name
+}
+
+// CleanPath 规范化磁盘内的相对路径
+//
+// 统一使用 / 分隔，去掉开头的 /，拒绝空路径和跳出磁盘根目录的路径。
+func CleanPath(name string) (string, error) {
+	name = strings.ReplaceAll(name, "\\", "/")
+	if name == "" || strings.ContainsRune(name, 0) {
+		return "", ErrInvalidPath
+	}
+
+	for _, segment := range strings.Split(name, "/") {
+		if segment == ".." {
+			return "", ErrInvalidPath
+		}
+	}
+
+	cleaned := strings.TrimPrefix(path.Clean("/"+name), "/")
+	if cleaned == "" {
+		return "", ErrInvalidPath
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,171 @@
+package filesystem
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalDisk 本地磁盘
+type LocalDisk struct {
+	root    string
+	baseURL string
+}
+
+// NewLocalDisk 创建本地磁盘，baseURL 用于生成文件访问地址
+func NewLocalDisk(root, baseURL string) *LocalDisk {
+	return &LocalDisk{
+		root:    root,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Root 获取根目录
+func (d *LocalDisk) Root() string {
+	return d.root
+}
+
+// Put 写入文件，先写入临时文件再重命名，避免读到不完整的文件
+func (d *LocalDisk) Put(name string, content io.Reader) error {
+	fullPath, err := d.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+// Get 读取文件
+func (d *LocalDisk) Get(name string) (io.ReadCloser, error) {
+	fullPath, err := d.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(fullPath)
+	if os.IsNotExist(err) {
+		return nil, ErrFileNotFound
+	}
+	return file, err
+}
+
+// Exists 检查文件是否存在
+func (d *LocalDisk) Exists(name string) (bool, error) {
+	fullPath, err := d.path(name)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = os.Stat(fullPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete 删除文件
+func (d *LocalDisk) Delete(name string) error {
+	fullPath, err := d.path(name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(fullPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Copy 复制文件
+func (d *LocalDisk) Copy(from, to string) error {
+	source, err := d.Get(from)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	return d.Put(to, source)
+}
+
+// Move 移动文件，同一文件系统内直接重命名
+func (d *LocalDisk) Move(from, to string) error {
+	sourcePath, err := d.path(from)
+	if err != nil {
+		return err
+	}
+	targetPath, err := d.path(to)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		return ErrFileNotFound
+	}
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(sourcePath, targetPath); err == nil {
+		return nil
+	}
+
+	// 跨设备时退回到复制后删除
+	if err := d.Copy(from, to); err != nil {
+		return err
+	}
+	return d.Delete(from)
+}
+
+// Size 获取文件大小
+func (d *LocalDisk) Size(name string) (int64, error) {
+	fullPath, err := d.path(name)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if os.IsNotExist(err) {
+		return 0, ErrFileNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// URL 获取文件访问地址
+func (d *LocalDisk) URL(name string) string {
+	cleaned, err := CleanPath(name)
+	if err != nil {
+		return ""
+	}
+	return d.baseURL + "/" + cleaned
+}
+
+// path 获取文件在本地的完整路径
+func (d *LocalDisk) path(name string) (string, error) {
+	cleaned, err := CleanPath(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d.root, filepath.FromSlash(cleaned)), nil
+}
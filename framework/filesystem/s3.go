@@ -0,0 +1,421 @@
+package filesystem
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unsignedPayload 流式上传时不对请求体签名
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config S3 兼容存储配置
+type S3Config struct {
+	Endpoint  string // 例如 https://s3.amazonaws.com 或 http://localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PublicURL 公开访问地址前缀，为空时 URL 返回预签名地址
+	PublicURL string
+	// URLExpiry 预签名地址有效期，默认 1 小时
+	URLExpiry time.Duration
+}
+
+// S3Disk S3 兼容存储磁盘
+//
+// 使用路径风格的地址（Endpoint/Bucket/Key）和 AWS Signature V4 签名，
+// 兼容 AWS S3、MinIO 以及其他 S3 兼容的对象存储。
+type S3Disk struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Disk 创建 S3 兼容存储磁盘
+func NewS3Disk(config S3Config) *S3Disk {
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.URLExpiry <= 0 {
+		config.URLExpiry = time.Hour
+	}
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+
+	return &S3Disk{
+		config: config,
+		client: &http.Client{},
+		now:    time.Now,
+	}
+}
+
+// SetHTTPClient 设置HTTP客户端
+func (d *S3Disk) SetHTTPClient(client *http.Client) {
+	d.client = client
+}
+
+// Put 上传文件
+//
+// 内容以流的方式发送。S3 要求请求带有 Content-Length，无法获取长度的内容
+// 先写入临时文件，不会整体读入内存。
+func (d *S3Disk) Put(name string, content io.Reader) error {
+	body, size, cleanup, err := sizedReader(content)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	req, err := d.newRequest(http.MethodPut, name, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+
+	resp, err := d.send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkS3Response(resp)
+}
+
+// Get 下载文件，返回的内容以流的方式读取
+func (d *S3Disk) Get(name string) (io.ReadCloser, error) {
+	resp, err := d.request(http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkS3Response(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Exists 检查文件是否存在
+func (d *S3Disk) Exists(name string) (bool, error) {
+	_, err := d.Size(name)
+	if err == ErrFileNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete 删除文件
+func (d *S3Disk) Delete(name string) error {
+	resp, err := d.request(http.MethodDelete, name, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return checkS3Response(resp)
+}
+
+// Copy 在存储桶内复制文件，不经过本地
+func (d *S3Disk) Copy(from, to string) error {
+	source, err := CleanPath(from)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.request(http.MethodPut, to, map[string]string{
+		"X-Amz-Copy-Source": "/" + d.config.Bucket + "/" + escapeKey(source),
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return checkS3Response(resp)
+}
+
+// Move 移动文件
+func (d *S3Disk) Move(from, to string) error {
+	if err := d.Copy(from, to); err != nil {
+		return err
+	}
+	return d.Delete(from)
+}
+
+// Size 获取文件大小
+func (d *S3Disk) Size(name string) (int64, error) {
+	resp, err := d.request(http.MethodHead, name, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkS3Response(resp); err != nil {
+		return 0, err
+	}
+	return resp.ContentLength, nil
+}
+
+// URL 获取文件访问地址，未配置 PublicURL 时返回预签名地址
+func (d *S3Disk) URL(name string) string {
+	key, err := CleanPath(name)
+	if err != nil {
+		return ""
+	}
+
+	if d.config.PublicURL != "" {
+		return strings.TrimRight(d.config.PublicURL, "/") + "/" + escapeKey(key)
+	}
+
+	signed, err := d.TemporaryURL(key, d.config.URLExpiry)
+	if err != nil {
+		return ""
+	}
+	return signed
+}
+
+// TemporaryURL 生成有效期为 expires 的预签名下载地址
+func (d *S3Disk) TemporaryURL(name string, expires time.Duration) (string, error) {
+	key, err := CleanPath(name)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint, err := url.Parse(d.config.Endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	now := d.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/" + d.config.Region + "/s3/aws4_request"
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", d.config.AccessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	canonicalQuery := encodeQuery(query)
+
+	canonicalPath := d.canonicalPath(key)
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		canonicalPath,
+		canonicalQuery,
+		"host:" + endpoint.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	signature := d.signature(now, scope, canonicalRequest)
+	return d.config.Endpoint + canonicalPath + "?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+// request 发送不带请求体的签名请求
+func (d *S3Disk) request(method, name string, headers map[string]string) (*http.Response, error) {
+	req, err := d.newRequest(method, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return d.send(req)
+}
+
+// newRequest 创建对象请求
+func (d *S3Disk) newRequest(method, name string, body io.Reader) (*http.Request, error) {
+	key, err := CleanPath(name)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, d.config.Endpoint+d.canonicalPath(key), body)
+	if err != nil {
+		return nil, err
+	}
+	// 签名使用的路径必须与实际发送的一致
+	req.URL.RawPath = d.canonicalPath(key)
+	return req, nil
+}
+
+// send 签名并发送请求
+func (d *S3Disk) send(req *http.Request) (*http.Response, error) {
+	payloadHash := unsignedPayload
+	if req.Body == nil || req.ContentLength == 0 {
+		payloadHash = sha256Hex(nil)
+	}
+	d.sign(req, payloadHash)
+	return d.client.Do(req)
+}
+
+// sign 使用 AWS Signature V4 签名请求，签名 host 和所有 x-amz-* 请求头
+func (d *S3Disk) sign(req *http.Request, payloadHash string) {
+	now := d.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/" + d.config.Region + "/s3/aws4_request"
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	signedHeaders := make([]string, 0, len(headers))
+	for name := range headers {
+		signedHeaders = append(signedHeaders, name)
+	}
+	sort.Strings(signedHeaders)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		d.config.AccessKey, scope, strings.Join(signedHeaders, ";"), d.signature(now, scope, canonicalRequest),
+	))
+}
+
+// signature 计算 Signature V4 签名
+func (d *S3Disk) signature(now time.Time, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+d.config.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, d.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalPath 获取对象的请求路径
+func (d *S3Disk) canonicalPath(key string) string {
+	return "/" + d.config.Bucket + "/" + escapeKey(key)
+}
+
+// sizedReader 获取内容长度，无法获取时写入临时文件
+func sizedReader(content io.Reader) (io.Reader, int64, func(), error) {
+	noop := func() {}
+
+	if seeker, ok := content.(io.Seeker); ok {
+		current, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			end, err := seeker.Seek(0, io.SeekEnd)
+			if err == nil {
+				if _, err := seeker.Seek(current, io.SeekStart); err == nil {
+					return content, end - current, noop, nil
+				}
+			}
+		}
+	}
+
+	tmp, err := os.CreateTemp("", "s3-upload-*")
+	if err != nil {
+		return nil, 0, noop, err
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+
+	size, err := io.Copy(tmp, content)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return nil, 0, noop, err
+	}
+	return tmp, size, cleanup, nil
+}
+
+// checkS3Response 检查 S3 响应状态
+func checkS3Response(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrFileNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+// escapeKey 按 S3 的规则转义对象键，保留路径分隔符
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// encodeQuery 按 S3 的规则编码查询参数
+func encodeQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, uriEncode(key)+"="+uriEncode(values.Get(key)))
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode 除 A-Z a-z 0-9 - _ . ~ 外全部百分号编码
+func uriEncode(value string) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"laravel-go/framework/database"
+	"laravel-go/framework/queue"
+)
+
+// PingCheck 通过 ping 函数检查依赖服务是否可用
+//
+// 数据库连接可以直接传入 conn.PingContext，Redis 客户端可以传入
+// func(ctx context.Context) error { return client.Ping(ctx).Err() }。
+func PingCheck(ping func(ctx context.Context) error) Checker {
+	return CheckerFunc(func(ctx context.Context) (Status, string) {
+		if err := ping(ctx); err != nil {
+			return StatusDown, err.Error()
+		}
+		return StatusUp, ""
+	})
+}
+
+// DatabaseCheck 检查连接管理器中的数据库连接，正在重连时为 degraded
+func DatabaseCheck(manager *database.ConnectionManager, name string) Checker {
+	return CheckerFunc(func(ctx context.Context) (Status, string) {
+		if state := manager.State(name); state == database.StateReconnecting {
+			return StatusDegraded, string(state)
+		}
+
+		conn, err := manager.GetConnection(name)
+		if err != nil {
+			return StatusDown, err.Error()
+		}
+		if err := conn.PingContext(ctx); err != nil {
+			return StatusDown, err.Error()
+		}
+		return StatusUp, string(manager.State(name))
+	})
+}
+
+// QueueDepthCheck 检查队列积压的任务数，超过 warning 时为 degraded，超过 critical 时为 down
+//
+// 阈值小于等于 0 表示不检查该级别。
+func QueueDepthCheck(q queue.Queue, warning, critical int) Checker {
+	return CheckerFunc(func(ctx context.Context) (Status, string) {
+		size, err := q.Size()
+		if err != nil {
+			return StatusDown, err.Error()
+		}
+
+		detail := fmt.Sprintf("%d pending jobs", size)
+		switch {
+		case critical > 0 && size >= critical:
+			return StatusDown, detail
+		case warning > 0 && size >= warning:
+			return StatusDegraded, detail
+		default:
+			return StatusUp, detail
+		}
+	})
+}
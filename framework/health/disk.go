@@ -0,0 +1,24 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// DiskSpaceCheck 检查路径所在磁盘的剩余空间，低于 minFreeBytes 时为 down
+func DiskSpaceCheck(path string, minFreeBytes uint64) Checker {
+	return CheckerFunc(func(ctx context.Context) (Status, string) {
+		usage, err := disk.UsageWithContext(ctx, path)
+		if err != nil {
+			return StatusDown, err.Error()
+		}
+
+		detail := fmt.Sprintf("%d MB free (%.1f%% used)", usage.Free/1024/1024, usage.UsedPercent)
+		if usage.Free < minFreeBytes {
+			return StatusDown, detail
+		}
+		return StatusUp, detail
+	})
+}
@@ -0,0 +1,265 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status 健康状态
+type Status string
+
+// 健康状态
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Checker 健康检查探针
+type Checker interface {
+	// Check 执行检查，返回状态和说明
+	Check(ctx context.Context) (Status, string)
+}
+
+// CheckerFunc 函数形式的健康检查探针
+type CheckerFunc func(ctx context.Context) (Status, string)
+
+// Check 执行检查
+func (f CheckerFunc) Check(ctx context.Context) (Status, string) {
+	return f(ctx)
+}
+
+// CheckResult 单个检查的结果
+type CheckResult struct {
+	Status   Status  `json:"status"`
+	Detail   string  `json:"detail,omitempty"`
+	Critical bool    `json:"critical"`
+	Duration float64 `json:"duration_ms"`
+}
+
+// Report 汇总的健康检查报告
+type Report struct {
+	Status    Status                 `json:"status"`
+	Timestamp time.Time              `json:"timestamp"`
+	Checks    map[string]CheckResult `json:"checks"`
+}
+
+// HTTPStatus 报告对应的 HTTP 状态码，关键检查失败时返回 503
+func (r *Report) HTTPStatus() int {
+	if r.Status == StatusDown {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// check 已注册的检查
+type check struct {
+	name     string
+	checker  Checker
+	critical bool
+	liveness bool
+	timeout  time.Duration
+}
+
+// CheckOption 检查选项
+type CheckOption func(*check)
+
+// WithCritical 设置检查是否为关键检查，关键检查失败时整体状态为 down，默认为关键检查
+func WithCritical(critical bool) CheckOption {
+	return func(c *check) {
+		c.critical = critical
+	}
+}
+
+// WithTimeout 设置检查超时时间
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(c *check) {
+		c.timeout = timeout
+	}
+}
+
+// WithLiveness 将检查同时用于存活检查，默认只用于就绪检查
+//
+// 存活检查只应包含进程自身的状态（例如死锁检测），依赖服务故障不应导致进程被重启。
+func WithLiveness() CheckOption {
+	return func(c *check) {
+		c.liveness = true
+	}
+}
+
+// Registry 健康检查注册表
+type Registry struct {
+	checks  map[string]*check
+	timeout time.Duration
+	mu      sync.RWMutex
+}
+
+// NewRegistry 创建健康检查注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		checks:  make(map[string]*check),
+		timeout: 5 * time.Second,
+	}
+}
+
+// SetTimeout 设置检查的默认超时时间
+func (r *Registry) SetTimeout(timeout time.Duration) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeout = timeout
+	return r
+}
+
+// Register 注册检查，同名检查会被替换
+func (r *Registry) Register(name string, checker Checker, options ...CheckOption) {
+	c := &check{
+		name:     name,
+		checker:  checker,
+		critical: true,
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = c
+}
+
+// RegisterFunc 注册函数形式的检查
+func (r *Registry) RegisterFunc(name string, fn func(ctx context.Context) (Status, string), options ...CheckOption) {
+	r.Register(name, CheckerFunc(fn), options...)
+}
+
+// Unregister 注销检查
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.checks, name)
+}
+
+// Liveness 执行存活检查，没有存活检查时只要进程在运行即为 up
+func (r *Registry) Liveness(ctx context.Context) *Report {
+	return r.run(ctx, func(c *check) bool { return c.liveness })
+}
+
+// Readiness 执行就绪检查，包含所有已注册的检查
+func (r *Registry) Readiness(ctx context.Context) *Report {
+	return r.run(ctx, func(c *check) bool { return true })
+}
+
+// LivenessHandler 存活检查的 HTTP 处理程序
+func (r *Registry) LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		writeReport(w, r.Liveness(req.Context()))
+	}
+}
+
+// ReadinessHandler 就绪检查的 HTTP 处理程序
+func (r *Registry) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		writeReport(w, r.Readiness(req.Context()))
+	}
+}
+
+// Mount 在 mux 上注册 /health/live 和 /health/ready，/health 等同于就绪检查
+func (r *Registry) Mount(mux *http.ServeMux) {
+	mux.HandleFunc("/health", r.ReadinessHandler())
+	mux.HandleFunc("/health/live", r.LivenessHandler())
+	mux.HandleFunc("/health/ready", r.ReadinessHandler())
+}
+
+// run 并发执行符合条件的检查并汇总结果
+func (r *Registry) run(ctx context.Context, include func(*check) bool) *Report {
+	r.mu.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for _, c := range r.checks {
+		if include(c) {
+			checks = append(checks, c)
+		}
+	}
+	defaultTimeout := r.timeout
+	r.mu.RUnlock()
+
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].name < checks[j].name
+	})
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			timeout := c.timeout
+			if timeout <= 0 {
+				timeout = defaultTimeout
+			}
+			results[i] = runCheck(ctx, c, timeout)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := &Report{
+		Status:    StatusUp,
+		Timestamp: time.Now(),
+		Checks:    make(map[string]CheckResult, len(checks)),
+	}
+	for i, c := range checks {
+		result := results[i]
+		report.Checks[c.name] = result
+
+		switch {
+		case result.Status == StatusDown && result.Critical:
+			report.Status = StatusDown
+		case result.Status != StatusUp && report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// runCheck 在超时时间内执行单个检查，panic 和超时视为失败
+func runCheck(ctx context.Context, c *check, timeout time.Duration) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan CheckResult, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- CheckResult{Status: StatusDown, Detail: fmt.Sprintf("check panicked: %v", recovered)}
+			}
+		}()
+		status, detail := c.checker.Check(ctx)
+		done <- CheckResult{Status: status, Detail: detail}
+	}()
+
+	var result CheckResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = CheckResult{Status: StatusDown, Detail: fmt.Sprintf("check timed out after %s", timeout)}
+	}
+
+	if result.Status != StatusUp && result.Status != StatusDegraded {
+		result.Status = StatusDown
+	}
+	result.Critical = c.critical
+	result.Duration = float64(time.Since(start).Microseconds()) / 1000
+	return result
+}
+
+// writeReport 输出 JSON 格式的报告
+func writeReport(w http.ResponseWriter, report *Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(report.HTTPStatus())
+	json.NewEncoder(w).Encode(report)
+}
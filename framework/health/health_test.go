@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"laravel-go/framework/queue"
+)
+
+func TestReadinessRollup(t *testing.T) {
+	registry := NewRegistry()
+	databaseErr := error(nil)
+	registry.Register("database", PingCheck(func(ctx context.Context) error { return databaseErr }))
+	registry.RegisterFunc("cache", func(ctx context.Context) (Status, string) {
+		return StatusDown, "connection refused"
+	}, WithCritical(false))
+
+	mux := http.NewServeMux()
+	registry.Mount(mux)
+	get := func(path string) (*httptest.ResponseRecorder, Report) {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+		var report Report
+		if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+			t.Fatalf("Expected JSON report, got: %s", recorder.Body.String())
+		}
+		return recorder, report
+	}
+
+	// 非关键检查失败只降级，不影响就绪状态
+	recorder, report := get("/health/ready")
+	if recorder.Code != http.StatusOK || report.Status != StatusDegraded {
+		t.Errorf("Expected 200 degraded, got %d %s", recorder.Code, report.Status)
+	}
+	if report.Checks["cache"].Status != StatusDown || report.Checks["cache"].Detail != "connection refused" || report.Checks["database"].Status != StatusUp {
+		t.Errorf("Unexpected check results: %+v", report.Checks)
+	}
+
+	// 关键检查失败时整体为 down 并返回 503
+	databaseErr = errors.New("database is unreachable")
+	recorder, report = get("/health")
+	if recorder.Code != http.StatusServiceUnavailable || report.Status != StatusDown {
+		t.Errorf("Expected 503 down, got %d %s", recorder.Code, report.Status)
+	}
+	if report.Checks["database"].Detail != "database is unreachable" || !report.Checks["database"].Critical {
+		t.Errorf("Unexpected database result: %+v", report.Checks["database"])
+	}
+
+	// 存活检查不包含依赖服务
+	recorder, report = get("/health/live")
+	if recorder.Code != http.StatusOK || report.Status != StatusUp || len(report.Checks) != 0 {
+		t.Errorf("Expected liveness to ignore dependency checks, got %d %+v", recorder.Code, report)
+	}
+}
+
+func TestLivenessChecks(t *testing.T) {
+	registry := NewRegistry()
+	registry.RegisterFunc("deadlock", func(ctx context.Context) (Status, string) {
+		panic("worker pool stuck")
+	}, WithLiveness())
+	registry.RegisterFunc("slow", func(ctx context.Context) (Status, string) {
+		<-ctx.Done()
+		return StatusUp, ""
+	}, WithTimeout(20*time.Millisecond))
+
+	live := registry.Liveness(context.Background())
+	if live.Status != StatusDown || live.HTTPStatus() != http.StatusServiceUnavailable || len(live.Checks) != 1 {
+		t.Errorf("Expected panicking liveness check to fail, got %+v", live)
+	}
+
+	ready := registry.Readiness(context.Background())
+	if ready.Checks["slow"].Status != StatusDown {
+		t.Errorf("Expected timed out check to be down, got %+v", ready.Checks["slow"])
+	}
+}
+
+func TestQueueDepthCheck(t *testing.T) {
+	q := queue.NewMemoryQueue()
+	check := QueueDepthCheck(q, 2, 3)
+
+	expected := []Status{StatusUp, StatusUp, StatusDegraded, StatusDown}
+	for i, status := range expected {
+		if i > 0 {
+			q.Push(queue.NewJob([]byte("job"), "default"))
+		}
+		if actual, detail := check.Check(context.Background()); actual != status {
+			t.Errorf("With %d jobs expected %s, got %s (%s)", i, status, actual, detail)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"laravel-go/framework/cache"
+	"laravel-go/framework/lock"
+)
+
+// 幂等键相关的请求头
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotencyConfig 幂等中间件配置
+type idempotencyConfig struct {
+	ttl          time.Duration
+	lockTTL      time.Duration
+	wait         time.Duration
+	methods      map[string]bool
+	locker       lock.Locker
+	prefix       string
+	pollInterval time.Duration
+}
+
+// IdempotencyOption 幂等中间件选项
+type IdempotencyOption func(*idempotencyConfig)
+
+// WithIdempotencyTTL 设置响应的保存时间，默认 24 小时
+func WithIdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithIdempotencyLocker 设置检测并发重复请求的锁驱动，多实例部署时应使用分布式锁驱动
+func WithIdempotencyLocker(locker lock.Locker) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.locker = locker
+	}
+}
+
+// WithIdempotencyWait 设置重复请求等待首个请求完成的时间
+//
+// 默认不等待，首个请求仍在处理时直接返回 409；等待超时后同样返回 409。
+func WithIdempotencyWait(wait time.Duration) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.wait = wait
+	}
+}
+
+// WithIdempotencyMethods 设置需要幂等处理的请求方法，默认为 POST 和 PATCH
+func WithIdempotencyMethods(methods ...string) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.methods = make(map[string]bool, len(methods))
+		for _, method := range methods {
+			c.methods[strings.ToUpper(method)] = true
+		}
+	}
+}
+
+// idempotentResponse 保存的响应
+type idempotentResponse struct {
+	Fingerprint string      `json:"fingerprint"`
+	StatusCode  int         `json:"status_code"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+}
+
+// Idempotency 创建幂等键中间件
+//
+// 请求携带 Idempotency-Key 头时，首次请求的响应按请求方法、路径和幂等键保存到缓存中，
+// 之后相同键的请求直接重放保存的响应而不再执行处理器。同一个键用于不同的请求体时返回 422；
+// 首个请求仍在处理时，重复请求返回 409 或按 WithIdempotencyWait 等待其完成。
+// 5xx 响应不会被保存，客户端可以使用相同的键重试。
+func Idempotency(store cache.Store, options ...IdempotencyOption) Middleware {
+	config := &idempotencyConfig{
+		ttl:          24 * time.Hour,
+		lockTTL:      time.Minute,
+		methods:      map[string]bool{http.MethodPost: true, http.MethodPatch: true},
+		locker:       lock.DefaultLocker(),
+		prefix:       "idempotency:",
+		pollInterval: 20 * time.Millisecond,
+	}
+	for _, option := range options {
+		option(config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || !config.methods[r.Method] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "Failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			cacheKey := config.prefix + r.Method + ":" + r.URL.Path + ":" + key
+			fingerprint := requestFingerprint(body)
+
+			if response, ok := loadIdempotentResponse(store, cacheKey); ok {
+				replayIdempotentResponse(w, response, fingerprint)
+				return
+			}
+
+			// 同一个键同时只允许一个请求执行处理器
+			owner := uuid.New().String()
+			acquired, err := config.acquire(r.Context(), store, cacheKey, owner)
+			if err != nil {
+				http.Error(w, "Failed to acquire idempotency lock", http.StatusInternalServerError)
+				return
+			}
+			if !acquired {
+				if response, ok := loadIdempotentResponse(store, cacheKey); ok {
+					replayIdempotentResponse(w, response, fingerprint)
+					return
+				}
+				http.Error(w, "A request with the same idempotency key is in progress", http.StatusConflict)
+				return
+			}
+			defer config.locker.Unlock(context.Background(), cacheKey, owner)
+
+			// 获取锁期间首个请求可能已经完成
+			if response, ok := loadIdempotentResponse(store, cacheKey); ok {
+				replayIdempotentResponse(w, response, fingerprint)
+				return
+			}
+
+			recorder := &recordingResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			if recorder.statusCode >= 500 {
+				return
+			}
+			data, err := json.Marshal(&idempotentResponse{
+				Fingerprint: fingerprint,
+				StatusCode:  recorder.statusCode,
+				Header:      w.Header().Clone(),
+				Body:        recorder.body.Bytes(),
+			})
+			if err == nil {
+				store.SetBytes(cacheKey, data, config.ttl)
+			}
+		})
+	}
+}
+
+// acquire 获取幂等键的锁，配置了等待时间时在锁被占用期间等待，直到获取锁或响应已保存
+func (c *idempotencyConfig) acquire(ctx context.Context, store cache.Store, key, owner string) (bool, error) {
+	deadline := time.Now().Add(c.wait)
+	for {
+		acquired, err := c.locker.TryLock(ctx, key, owner, c.lockTTL)
+		if err != nil || acquired {
+			return acquired, err
+		}
+		if !time.Now().Before(deadline) || store.Has(key) {
+			return false, nil
+		}
+
+		select {
+		case <-time.After(c.pollInterval):
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+// loadIdempotentResponse 从缓存读取保存的响应
+func loadIdempotentResponse(store cache.Store, key string) (*idempotentResponse, bool) {
+	data, err := store.GetBytes(key)
+	if err != nil {
+		return nil, false
+	}
+
+	var response idempotentResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, false
+	}
+	return &response, true
+}
+
+// replayIdempotentResponse 重放保存的响应，请求体与首次请求不同时返回 422
+func replayIdempotentResponse(w http.ResponseWriter, response *idempotentResponse, fingerprint string) {
+	if response.Fingerprint != fingerprint {
+		http.Error(w, "Idempotency key was already used with a different request", http.StatusUnprocessableEntity)
+		return
+	}
+
+	for name, values := range response.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotencyReplayedHeader, "true")
+	w.WriteHeader(response.StatusCode)
+	w.Write(response.Body)
+}
+
+// requestFingerprint 请求体的指纹
+func requestFingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordingResponseWriter 写入响应的同时记录状态码和响应体
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+// WriteHeader 写入状态码
+func (rw *recordingResponseWriter) WriteHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.statusCode = statusCode
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write 写入响应
+func (rw *recordingResponseWriter) Write(data []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	rw.body.Write(data)
+	return rw.ResponseWriter.Write(data)
+}
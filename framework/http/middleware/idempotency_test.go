@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"laravel-go/framework/cache"
+	"laravel-go/framework/lock"
+)
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Location", "/orders/1")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"order":%d}`, n)
+	})
+	mux.HandleFunc("/payments", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte("paid"))
+	})
+
+	handler := Idempotency(cache.NewMemoryStore(), WithIdempotencyTTL(100*time.Millisecond))(mux)
+	send := func(path, key, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	first := send("/orders", "key-1", `{"item":1}`)
+	second := send("/orders", "key-1", `{"item":1}`)
+	if calls != 1 {
+		t.Fatalf("Expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() || second.Header().Get("Location") != "/orders/1" {
+		t.Errorf("Expected stored response to be replayed, got %d %s", second.Code, second.Body.String())
+	}
+	if second.Header().Get(IdempotencyReplayedHeader) != "true" || first.Header().Get(IdempotencyReplayedHeader) != "" {
+		t.Error("Expected only the replayed response to be marked")
+	}
+
+	// 同一个键用于不同的请求体
+	if recorder := send("/orders", "key-1", `{"item":2}`); recorder.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for reused key, got %d", recorder.Code)
+	}
+
+	// 键按路由隔离，没有键的请求不做处理
+	send("/payments", "key-1", `{"item":1}`)
+	send("/orders", "", `{"item":1}`)
+	send("/orders", "", `{"item":1}`)
+	if calls != 4 {
+		t.Errorf("Expected other routes and requests without key to execute, got %d calls", calls)
+	}
+
+	// 过期后重新执行
+	time.Sleep(150 * time.Millisecond)
+	send("/orders", "key-1", `{"item":1}`)
+	if calls != 5 {
+		t.Errorf("Expected handler to run again after TTL, got %d calls", calls)
+	}
+}
+
+func TestIdempotencyConcurrentDuplicates(t *testing.T) {
+	var calls int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		w.Write([]byte("done"))
+	})
+
+	store := cache.NewMemoryStore()
+	locker := lock.NewMemoryLocker()
+	send := func(handler http.Handler) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/import", strings.NewReader("data"))
+		req.Header.Set(IdempotencyKeyHeader, "import-1")
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- send(Idempotency(store, WithIdempotencyLocker(locker))(slow))
+	}()
+	<-started
+
+	// 首个请求未完成时重复请求返回 409
+	if recorder := send(Idempotency(store, WithIdempotencyLocker(locker))(slow)); recorder.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for in-flight duplicate, got %d", recorder.Code)
+	}
+
+	// 配置等待时间时等待首个请求完成后重放
+	waiting := make(chan *httptest.ResponseRecorder)
+	go func() {
+		waiting <- send(Idempotency(store, WithIdempotencyLocker(locker), WithIdempotencyWait(time.Second))(slow))
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if recorder := <-done; recorder.Body.String() != "done" {
+		t.Errorf("Expected first request to complete, got %s", recorder.Body.String())
+	}
+	if recorder := <-waiting; recorder.Code != http.StatusOK || recorder.Body.String() != "done" || recorder.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Errorf("Expected waiting duplicate to replay response, got %d %s", recorder.Code, recorder.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("Expected handler to run once, ran %d times", calls)
+	}
+}
@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaintenanceFile down 命令写入的维护模式文件的默认路径
+const DefaultMaintenanceFile = "storage/framework/down"
+
+// MaintenanceCookie 使用 secret 绕过维护模式后设置的 Cookie 名称
+const MaintenanceCookie = "laravel_go_maintenance"
+
+// MaintenanceState 维护模式文件的内容
+type MaintenanceState struct {
+	Time       int64    `json:"time"`
+	Message    string   `json:"message,omitempty"`
+	RetryAfter int      `json:"retry,omitempty"`
+	Secret     string   `json:"secret,omitempty"`
+	AllowedIPs []string `json:"allowed,omitempty"`
+}
+
+// WriteMaintenanceState 写入维护模式文件，应用进入维护模式
+func WriteMaintenanceState(path string, state *MaintenanceState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadMaintenanceState 读取维护模式文件，文件不存在时返回 nil
+func ReadMaintenanceState(path string) (*MaintenanceState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := &MaintenanceState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid maintenance file %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// RemoveMaintenanceState 删除维护模式文件，应用退出维护模式
+func RemoveMaintenanceState(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// MaintenanceConfig 维护模式中间件配置
+type MaintenanceConfig struct {
+	// File 维护模式文件路径，默认为 DefaultMaintenanceFile
+	File string
+	// AllowedIPs 允许访问的 IP 或 CIDR，与维护模式文件中的列表合并
+	AllowedIPs []string
+	// Message 默认的提示信息，维护模式文件中的设置优先
+	Message string
+	// RetryAfter 默认的 Retry-After 秒数，维护模式文件中的设置优先
+	RetryAfter int
+	// TrustForwardedFor 是否使用 X-Forwarded-For 获取客户端 IP，只应在可信代理之后开启
+	TrustForwardedFor bool
+}
+
+// MaintenanceMode 维护模式中间件
+//
+// 维护模式文件存在时返回 503，允许列表中的 IP 和持有 bypass Cookie 的请求正常处理。
+// 设置了 secret 时访问 /<secret> 会设置 bypass Cookie 并重定向到首页。
+func MaintenanceMode(config MaintenanceConfig) Middleware {
+	if config.File == "" {
+		config.File = DefaultMaintenanceFile
+	}
+	if config.Message == "" {
+		config.Message = "Service Unavailable"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state, err := ReadMaintenanceState(config.File)
+			if err != nil || state == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if state.Secret != "" {
+				if r.URL.Path == "/"+state.Secret {
+					http.SetCookie(w, &http.Cookie{
+						Name:     MaintenanceCookie,
+						Value:    maintenanceToken(state.Secret),
+						Path:     "/",
+						HttpOnly: true,
+					})
+					http.Redirect(w, r, "/", http.StatusFound)
+					return
+				}
+				if hasMaintenanceBypass(r, state.Secret) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			ip := clientIP(r, config.TrustForwardedFor)
+			if ipAllowed(ip, config.AllowedIPs) || ipAllowed(ip, state.AllowedIPs) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			message := config.Message
+			if state.Message != "" {
+				message = state.Message
+			}
+			retryAfter := config.RetryAfter
+			if state.RetryAfter > 0 {
+				retryAfter = state.RetryAfter
+			}
+			writeMaintenanceResponse(w, r, message, retryAfter)
+		})
+	}
+}
+
+// writeMaintenanceResponse 返回 503 响应，请求接受 JSON 时返回 JSON
+func writeMaintenanceResponse(w http.ResponseWriter, r *http.Request, message string, retryAfter int) {
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{"message": message})
+		return
+	}
+	http.Error(w, message, http.StatusServiceUnavailable)
+}
+
+// maintenanceToken 根据 secret 生成 bypass Cookie 的值，避免 Cookie 中直接出现 secret
+func maintenanceToken(secret string) string {
+	sum := sha256.Sum256([]byte("maintenance:" + secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// hasMaintenanceBypass 检查请求是否携带有效的 bypass Cookie
+func hasMaintenanceBypass(r *http.Request, secret string) bool {
+	cookie, err := r.Cookie(MaintenanceCookie)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(maintenanceToken(secret))) == 1
+}
+
+// clientIP 获取客户端 IP
+func clientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// ipAllowed 检查 IP 是否在允许列表中，列表项可以是 IP 或 CIDR
+func ipAllowed(ip string, allowed []string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, entry := range allowed {
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(parsed) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(entry); allowedIP != nil && allowedIP.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "framework", "down")
+	handler := MaintenanceMode(MaintenanceConfig{
+		File:       file,
+		AllowedIPs: []string{"10.0.0.0/8"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+
+	send := func(remoteAddr string, configure func(*http.Request)) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+		req.RemoteAddr = remoteAddr
+		if configure != nil {
+			configure(req)
+		}
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// 未进入维护模式时正常处理
+	if recorder := send("203.0.113.5:1234", nil); recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200 when up, got %d", recorder.Code)
+	}
+
+	err := WriteMaintenanceState(file, &MaintenanceState{
+		Time:       time.Now().Unix(),
+		Message:    "Upgrading, back soon",
+		RetryAfter: 60,
+		Secret:     "let-me-in",
+		AllowedIPs: []string{"198.51.100.7"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to write maintenance state: %v", err)
+	}
+
+	// 普通请求返回 503
+	recorder := send("203.0.113.5:1234", nil)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 when down, got %d", recorder.Code)
+	}
+	if recorder.Header().Get("Retry-After") != "60" {
+		t.Errorf("Expected Retry-After 60, got %q", recorder.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(recorder.Body.String(), "Upgrading, back soon") {
+		t.Errorf("Expected custom message, got %q", recorder.Body.String())
+	}
+
+	recorder = send("203.0.113.5:1234", func(r *http.Request) { r.Header.Set("Accept", "application/json") })
+	if recorder.Body.String() != "{\"message\":\"Upgrading, back soon\"}\n" {
+		t.Errorf("Expected JSON message, got %q", recorder.Body.String())
+	}
+
+	// 允许列表中的 IP 正常处理
+	if recorder := send("198.51.100.7:1234", nil); recorder.Code != http.StatusOK {
+		t.Errorf("Expected allowlisted IP to pass, got %d", recorder.Code)
+	}
+	if recorder := send("10.1.2.3:1234", nil); recorder.Code != http.StatusOK {
+		t.Errorf("Expected allowlisted CIDR to pass, got %d", recorder.Code)
+	}
+
+	// 未开启 TrustForwardedFor 时不能伪造 IP
+	spoofed := send("203.0.113.5:1234", func(r *http.Request) { r.Header.Set("X-Forwarded-For", "198.51.100.7") })
+	if spoofed.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected forwarded IP to be ignored, got %d", spoofed.Code)
+	}
+
+	// 访问 secret 路径后获得 bypass Cookie
+	bypass := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/let-me-in", nil)
+	handler.ServeHTTP(bypass, req)
+	if bypass.Code != http.StatusFound {
+		t.Fatalf("Expected redirect from secret path, got %d", bypass.Code)
+	}
+	cookies := bypass.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != MaintenanceCookie {
+		t.Fatalf("Expected bypass cookie, got %v", cookies)
+	}
+	if recorder := send("203.0.113.5:1234", func(r *http.Request) { r.AddCookie(cookies[0]) }); recorder.Code != http.StatusOK {
+		t.Errorf("Expected bypass cookie to pass, got %d", recorder.Code)
+	}
+	forged := &http.Cookie{Name: MaintenanceCookie, Value: "let-me-in"}
+	if recorder := send("203.0.113.5:1234", func(r *http.Request) { r.AddCookie(forged) }); recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected invalid bypass cookie to be rejected, got %d", recorder.Code)
+	}
+
+	// 退出维护模式
+	if err := RemoveMaintenanceState(file); err != nil {
+		t.Fatalf("Failed to remove maintenance state: %v", err)
+	}
+	if recorder := send("203.0.113.5:1234", nil); recorder.Code != http.StatusOK {
+		t.Errorf("Expected 200 after up, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"laravel-go/framework/performance"
+)
+
+// Metrics 创建自动记录HTTP指标的中间件
+//
+// 记录请求方法、路径、状态码、请求和响应大小以及响应时间。路径经过
+// HTTPMonitor 的路径模板折叠（见 HTTPMonitor.SetPathTemplate）。4xx/5xx 响应由
+// RecordResponse 计入错误数，处理器 panic 时按 500 响应记录后继续向上抛出。
+func Metrics(monitor *performance.HTTPMonitor) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			method := r.Method
+			path := r.URL.Path
+
+			requestSize := r.ContentLength
+			if requestSize < 0 {
+				requestSize = 0
+			}
+			monitor.RecordRequest(method, path, requestSize)
+
+			// 包装响应写入器以捕获状态码和响应大小
+			responseWriter := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			completed := false
+			defer func() {
+				if !completed {
+					monitor.RecordResponse(method, path, http.StatusInternalServerError, responseWriter.size, time.Since(start))
+				}
+			}()
+
+			next.ServeHTTP(responseWriter, r)
+			completed = true
+
+			monitor.RecordResponse(method, path, responseWriter.statusCode, responseWriter.size, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"laravel-go/framework/performance"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	monitor := performance.NewPerformanceMonitor()
+	httpMonitor := performance.NewHTTPMonitor(monitor)
+	httpMonitor.SetPathTemplate(performance.IDPathTemplate)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+	mux.HandleFunc("/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	handler := Metrics(httpMonitor)(mux)
+
+	for _, path := range []string{"/users/1", "/users/2", "/fail"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, strings.NewReader("body")))
+	}
+
+	// panic 继续向上抛出，并记录为错误
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic to propagate")
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	}()
+
+	counters := map[string]int64{
+		"http_requests_total":  4,
+		"http_responses_total": 4,
+		"http_errors_total":    2,
+	}
+	for name, expected := range counters {
+		if value := monitor.GetMetric(name).Value().(int64); value != expected {
+			t.Errorf("Expected %s to be %d, got %d", name, expected, value)
+		}
+	}
+
+	// 5xx 响应和 panic 各计一次错误
+	if errorRate := monitor.GetMetric("http_error_rate").Value().(float64); errorRate != 50 {
+		t.Errorf("Expected error rate 50%%, got %v", errorRate)
+	}
+
+	if active := monitor.GetMetric("http_active_connections").Value().(float64); active != 0 {
+		t.Errorf("Expected no active connections, got %v", active)
+	}
+
+	responseTime := monitor.GetMetric("http_response_time").Value().(map[string]interface{})
+	if count := responseTime["count"].(int64); count != 4 {
+		t.Errorf("Expected 4 response time observations, got %d", count)
+	}
+
+	responseSize := monitor.GetMetric("http_response_size").Value().(map[string]interface{})
+	if sum := responseSize["sum"].(float64); sum != 10 {
+		t.Errorf("Expected response size sum 10, got %v", sum)
+	}
+
+	requestSize := monitor.GetMetric("http_request_size").Value().(map[string]interface{})
+	if sum := requestSize["sum"].(float64); sum != 12 {
+		t.Errorf("Expected request size sum 12, got %v", sum)
+	}
+
+	routes := httpMonitor.RouteStats()
+	if users := routes["POST /users/:id"]; users.Count != 2 || users.ErrorCount != 0 {
+		t.Errorf("Unexpected users route stats: %+v", users)
+	}
+	if fail := routes["POST /fail"]; fail.Count != 1 || fail.ErrorCount != 1 {
+		t.Errorf("Unexpected fail route stats: %+v", fail)
+	}
+	if panicked := routes["GET /panic"]; panicked.Count != 1 || panicked.ErrorCount != 1 {
+		t.Errorf("Unexpected panic route stats: %+v", panicked)
+	}
+}
@@ -239,6 +239,7 @@ func (pm *PerformanceMiddleware) Disable() {
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
+	size       int64
 }
 
 // WriteHeader 写入状态码
@@ -249,5 +250,7 @@ func (rw *responseWriter) WriteHeader(statusCode int) {
 
 // Write 写入响应
 func (rw *responseWriter) Write(data []byte) (int, error) {
-	return rw.ResponseWriter.Write(data)
+	n, err := rw.ResponseWriter.Write(data)
+	rw.size += int64(n)
+	return n, err
 }
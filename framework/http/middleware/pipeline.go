@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Middleware 标准 net/http 中间件
+type Middleware func(http.Handler) http.Handler
+
+// Pipeline 中间件管道
+//
+// 管道是不可变的，Use 返回新的管道，因此同一个分组管道可以安全地附加到多个
+// 路由上并分别追加路由中间件。中间件按添加顺序执行，第一个添加的中间件
+// 最先收到请求；中间件不调用 next 即可中断后续执行（例如认证失败返回 401）。
+type Pipeline struct {
+	middlewares []Middleware
+}
+
+// NewPipeline 创建中间件管道
+func NewPipeline(middlewares ...Middleware) *Pipeline {
+	return &Pipeline{
+		middlewares: append([]Middleware(nil), middlewares...),
+	}
+}
+
+// Use 返回追加了中间件的新管道
+func (p *Pipeline) Use(middlewares ...Middleware) *Pipeline {
+	combined := make([]Middleware, 0, len(p.middlewares)+len(middlewares))
+	combined = append(combined, p.middlewares...)
+	combined = append(combined, middlewares...)
+	return &Pipeline{middlewares: combined}
+}
+
+// Append 返回追加了另一个管道的新管道
+func (p *Pipeline) Append(other *Pipeline) *Pipeline {
+	return p.Use(other.middlewares...)
+}
+
+// Then 将中间件链应用到处理器
+func (p *Pipeline) Then(handler http.Handler) http.Handler {
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+
+	for i := len(p.middlewares) - 1; i >= 0; i-- {
+		handler = p.middlewares[i](handler)
+	}
+	return handler
+}
+
+// ThenFunc 将中间件链应用到处理器函数
+func (p *Pipeline) ThenFunc(handler http.HandlerFunc) http.Handler {
+	return p.Then(handler)
+}
+
+// Len 获取中间件数量
+func (p *Pipeline) Len() int {
+	return len(p.middlewares)
+}
+
+// Registry 中间件注册表
+//
+// 与 Laravel 的 HTTP Kernel 相同，注册表维护全局中间件、中间件分组（如 web、
+// api）和路由中间件别名（如 auth）。路由通过名称引用分组或别名，组合顺序为：
+// 全局中间件、按引用顺序展开的分组和别名、路由自身的中间件。
+type Registry struct {
+	global  []Middleware
+	groups  map[string][]Middleware
+	aliases map[string]Middleware
+	mutex   sync.RWMutex
+}
+
+// NewRegistry 创建中间件注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		groups:  make(map[string][]Middleware),
+		aliases: make(map[string]Middleware),
+	}
+}
+
+// Use 添加全局中间件
+func (r *Registry) Use(middlewares ...Middleware) *Registry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.global = append(r.global, middlewares...)
+	return r
+}
+
+// Group 向中间件分组追加中间件
+func (r *Registry) Group(name string, middlewares ...Middleware) *Registry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.groups[name] = append(r.groups[name], middlewares...)
+	return r
+}
+
+// Alias 注册路由中间件别名
+func (r *Registry) Alias(name string, middleware Middleware) *Registry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.aliases[name] = middleware
+	return r
+}
+
+// Pipeline 按名称组合管道，名称可以是分组或别名
+//
+//	registry.Pipeline("api", "auth").Use(throttle).Then(handler)
+func (r *Registry) Pipeline(names ...string) (*Pipeline, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	middlewares := append([]Middleware(nil), r.global...)
+	for _, name := range names {
+		if group, exists := r.groups[name]; exists {
+			middlewares = append(middlewares, group...)
+			continue
+		}
+		if alias, exists := r.aliases[name]; exists {
+			middlewares = append(middlewares, alias)
+			continue
+		}
+		return nil, fmt.Errorf("middleware %q is not registered", name)
+	}
+
+	return &Pipeline{middlewares: middlewares}, nil
+}
+
+// MustPipeline 按名称组合管道，名称未注册时 panic
+func (r *Registry) MustPipeline(names ...string) *Pipeline {
+	pipeline, err := r.Pipeline(names...)
+	if err != nil {
+		panic(err.Error())
+	}
+	return pipeline
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// recordMiddleware 记录执行顺序的中间件
+func recordMiddleware(name string, order *[]string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*order = append(*order, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestPipelineOrder(t *testing.T) {
+	var order []string
+
+	handler := NewPipeline(recordMiddleware("first", &order)).
+		Use(recordMiddleware("second", &order)).
+		Use(recordMiddleware("third", &order)).
+		ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+			order = append(order, "handler")
+		})
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := strings.Join(order, ","); got != "first,second,third,handler" {
+		t.Errorf("Unexpected middleware order: %s", got)
+	}
+}
+
+func TestRegistryGroups(t *testing.T) {
+	var order []string
+
+	auth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			order = append(order, "auth")
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	registry := NewRegistry().
+		Use(recordMiddleware("global", &order)).
+		Group("api", recordMiddleware("api", &order)).
+		Alias("auth", auth)
+
+	api := registry.MustPipeline("api", "auth")
+
+	// 同一分组附加到多个路由，路由中间件互不影响
+	users := api.Use(recordMiddleware("users", &order)).ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "users handler")
+	})
+	posts := api.ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "posts handler")
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/users", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	users.ServeHTTP(httptest.NewRecorder(), request)
+	if got := strings.Join(order, ","); got != "global,api,auth,users,users handler" {
+		t.Errorf("Unexpected users order: %s", got)
+	}
+
+	order = nil
+	request = httptest.NewRequest(http.MethodGet, "/posts", nil)
+	request.Header.Set("Authorization", "Bearer token")
+	posts.ServeHTTP(httptest.NewRecorder(), request)
+	if got := strings.Join(order, ","); got != "global,api,auth,posts handler" {
+		t.Errorf("Unexpected posts order: %s", got)
+	}
+
+	// 认证失败时中断后续中间件和处理器
+	order = nil
+	recorder := httptest.NewRecorder()
+	users.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users", nil))
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", recorder.Code)
+	}
+	if got := strings.Join(order, ","); got != "global,api,auth" {
+		t.Errorf("Expected chain to stop at auth, got %s", got)
+	}
+
+	if _, err := registry.Pipeline("web"); err == nil {
+		t.Error("Expected error for unknown group")
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"crypto/sha1"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	const secret = "whsec_test"
+	var received string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.Write([]byte("ok"))
+	})
+
+	send := func(handler http.Handler, body string, headers map[string]string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/payments", strings.NewReader(body))
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	handler := VerifyWebhookSignature(secret, "X-Signature", nil)(next)
+	body := `{"event":"payment.succeeded","amount":100}`
+	signature := "sha256=" + SignWebhook(secret, nil, "", []byte(body))
+
+	// 签名正确时通过，后续处理器可以读取完整的请求体
+	recorder := send(handler, body, map[string]string{"X-Signature": signature})
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected valid signature to pass, got %d", recorder.Code)
+	}
+	if received != body {
+		t.Errorf("Expected handler to receive the body, got %q", received)
+	}
+
+	// 请求体被篡改
+	tampered := strings.Replace(body, "100", "1000", 1)
+	if recorder := send(handler, tampered, map[string]string{"X-Signature": signature}); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected tampered body to fail, got %d", recorder.Code)
+	}
+
+	// 缺少签名
+	if recorder := send(handler, body, nil); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected missing signature to fail, got %d", recorder.Code)
+	}
+
+	// 其他哈希算法
+	sha1Handler := VerifyWebhookSignature(secret, "X-Hub-Signature", sha1.New)(next)
+	if recorder := send(sha1Handler, body, map[string]string{"X-Hub-Signature": "sha1=" + SignWebhook(secret, sha1.New, "", []byte(body))}); recorder.Code != http.StatusOK {
+		t.Errorf("Expected SHA-1 signature to pass, got %d", recorder.Code)
+	}
+
+	// 时间戳防重放
+	timed := VerifyWebhookSignature(secret, "X-Signature", nil, WithWebhookTimestamp("X-Timestamp", 5*time.Minute))(next)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	recorder = send(timed, body, map[string]string{
+		"X-Timestamp": now,
+		"X-Signature": SignWebhook(secret, nil, now, []byte(body)),
+	})
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected fresh timestamp to pass, got %d", recorder.Code)
+	}
+
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	recorder = send(timed, body, map[string]string{
+		"X-Timestamp": stale,
+		"X-Signature": SignWebhook(secret, nil, stale, []byte(body)),
+	})
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected stale timestamp to fail, got %d", recorder.Code)
+	}
+
+	// 时间戳参与签名，不能替换为新的时间戳重放
+	recorder = send(timed, body, map[string]string{
+		"X-Timestamp": now,
+		"X-Signature": SignWebhook(secret, nil, stale, []byte(body)),
+	})
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected replayed signature with new timestamp to fail, got %d", recorder.Code)
+	}
+}
@@ -0,0 +1,226 @@
+package http
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"laravel-go/framework/errors"
+	"laravel-go/framework/filesystem"
+	"laravel-go/framework/validation"
+)
+
+// DefaultMaxUploadMemory 解析上传文件时保存在内存中的最大字节数，超出部分写入临时文件
+const DefaultMaxUploadMemory = 32 << 20
+
+// UploadedFile 上传的文件
+type UploadedFile struct {
+	header *multipart.FileHeader
+}
+
+// StoredFile 已保存的文件
+type StoredFile struct {
+	Path         string `json:"path"`
+	URL          string `json:"url"`
+	Size         int64  `json:"size"`
+	Hash         string `json:"hash"` // SHA-256
+	MimeType     string `json:"mime_type"`
+	OriginalName string `json:"original_name"`
+}
+
+// NewUploadedFile 创建上传的文件
+func NewUploadedFile(header *multipart.FileHeader) *UploadedFile {
+	return &UploadedFile{
+		header: header,
+	}
+}
+
+// RequestFile 获取请求中的上传文件
+func RequestFile(req Request, key string) (*UploadedFile, error) {
+	files, err := RequestFiles(req, key)
+	if err != nil {
+		return nil, err
+	}
+	return files[0], nil
+}
+
+// RequestFiles 获取请求中同名字段的所有上传文件
+func RequestFiles(req Request, key string) ([]*UploadedFile, error) {
+	if err := parseMultipart(req); err != nil {
+		return nil, err
+	}
+
+	headers := req.Raw().MultipartForm.File[key]
+	if len(headers) == 0 {
+		return nil, errors.New("no file uploaded for field " + key)
+	}
+
+	files := make([]*UploadedFile, len(headers))
+	for i, header := range headers {
+		files[i] = NewUploadedFile(header)
+	}
+	return files, nil
+}
+
+// ValidateUpload 验证请求的表单字段和上传文件
+//
+// 规则使用 validation 包的语法，例如 "file|max:2048|mimes:png,jpg"，max 对文件按 KB 计算。
+func ValidateUpload(req Request, rules map[string]string) error {
+	if err := parseMultipart(req); err != nil {
+		return err
+	}
+
+	form := req.Raw().MultipartForm
+	data := make(map[string]interface{})
+	for key, values := range form.Value {
+		if len(values) > 0 {
+			data[key] = values[0]
+		}
+	}
+	for key, headers := range form.File {
+		if len(headers) > 0 {
+			data[key] = headers[0]
+		}
+	}
+
+	return validation.NewValidator().Validate(data, rules)
+}
+
+// OriginalName 获取客户端提供的文件名（已去除路径）
+func (f *UploadedFile) OriginalName() string {
+	return path.Base(strings.ReplaceAll(f.header.Filename, "\\", "/"))
+}
+
+// Extension 获取小写的文件扩展名，不含点，只保留字母和数字
+func (f *UploadedFile) Extension() string {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(f.OriginalName()), "."))
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, ext)
+}
+
+// Size 获取文件大小
+func (f *UploadedFile) Size() int64 {
+	return f.header.Size
+}
+
+// MimeType 根据文件内容检测类型
+func (f *UploadedFile) MimeType() string {
+	mimeType, err := validation.DetectMimeType(f.header)
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mimeType
+}
+
+// Header 获取原始的文件头
+func (f *UploadedFile) Header() *multipart.FileHeader {
+	return f.header
+}
+
+// Open 打开文件
+func (f *UploadedFile) Open() (multipart.File, error) {
+	return f.header.Open()
+}
+
+// Store 使用随机文件名保存到磁盘的指定目录
+func (f *UploadedFile) Store(disk filesystem.Disk, dir string) (*StoredFile, error) {
+	name, err := randomFilename()
+	if err != nil {
+		return nil, err
+	}
+	if ext := f.Extension(); ext != "" {
+		name += "." + ext
+	}
+	return f.StoreAs(disk, dir, name)
+}
+
+// StoreAs 使用指定文件名保存到磁盘的指定目录，文件名中的路径会被去除
+func (f *UploadedFile) StoreAs(disk filesystem.Disk, dir, name string) (*StoredFile, error) {
+	name = SanitizeFilename(name)
+	if name == "" {
+		return nil, filesystem.ErrInvalidPath
+	}
+
+	target, err := filesystem.CleanPath(path.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	counter := &countingReader{reader: io.TeeReader(file, hash)}
+	if err := disk.Put(target, counter); err != nil {
+		return nil, err
+	}
+
+	return &StoredFile{
+		Path:         target,
+		URL:          disk.URL(target),
+		Size:         counter.n,
+		Hash:         hex.EncodeToString(hash.Sum(nil)),
+		MimeType:     f.MimeType(),
+		OriginalName: f.OriginalName(),
+	}, nil
+}
+
+// SanitizeFilename 清理文件名，去除路径并只保留安全字符
+func SanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+
+	name = strings.TrimLeft(name, ".")
+	if name == "" || name == "_" {
+		return ""
+	}
+	return name
+}
+
+// parseMultipart 解析 multipart 请求
+func parseMultipart(req Request) error {
+	raw := req.Raw()
+	if raw.MultipartForm != nil {
+		return nil
+	}
+	return raw.ParseMultipartForm(DefaultMaxUploadMemory)
+}
+
+// randomFilename 生成随机文件名
+func randomFilename() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// countingReader 统计读取的字节数
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"laravel-go/framework/errors"
+	"laravel-go/framework/filesystem"
+)
+
+// pngHeader PNG 文件头，用于内容类型检测
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// newUploadRequest 创建包含单个文件的 multipart 请求
+func newUploadRequest(t *testing.T, field, filename string, content []byte) Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		t.Fatalf("Failed to create form file: %v", err)
+	}
+	part.Write(content)
+	writer.WriteField("title", "avatar")
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return NewRequest(req)
+}
+
+func TestUploadValidAndStore(t *testing.T) {
+	content := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 1024)...)
+	req := newUploadRequest(t, "avatar", "../../me.png", content)
+
+	rules := map[string]string{"avatar": "required|file|max:2048|mimes:png,jpg", "title": "required|string"}
+	if err := ValidateUpload(req, rules); err != nil {
+		t.Fatalf("Expected valid upload, got: %v", err)
+	}
+
+	file, err := RequestFile(req, "avatar")
+	if err != nil {
+		t.Fatalf("Expected uploaded file, got: %v", err)
+	}
+	if file.OriginalName() != "me.png" || file.Extension() != "png" || file.MimeType() != "image/png" {
+		t.Errorf("Unexpected file info: %s %s %s", file.OriginalName(), file.Extension(), file.MimeType())
+	}
+
+	root := t.TempDir()
+	disk := filesystem.NewLocalDisk(root, "/storage")
+	stored, err := file.Store(disk, "avatars")
+	if err != nil {
+		t.Fatalf("Expected file to be stored, got: %v", err)
+	}
+	if stored.Size != int64(len(content)) || len(stored.Hash) != 64 || filepath.Ext(stored.Path) != ".png" {
+		t.Errorf("Unexpected stored file: %+v", stored)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, stored.Path)); !bytes.Equal(data, content) {
+		t.Error("Expected stored content to match upload")
+	}
+
+	// 指定的文件名不能跳出目标目录
+	stored, err = file.StoreAs(disk, "avatars", "../../../etc/passwd")
+	if err != nil {
+		t.Fatalf("Expected file to be stored, got: %v", err)
+	}
+	if stored.Path != "avatars/passwd" {
+		t.Errorf("Expected sanitized path avatars/passwd, got %s", stored.Path)
+	}
+}
+
+func TestUploadRejectsOversizedFile(t *testing.T) {
+	content := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 3*1024)...)
+	req := newUploadRequest(t, "avatar", "me.png", content)
+
+	err := ValidateUpload(req, map[string]string{"avatar": "file|max:2|mimes:png"})
+	validationErrors, ok := err.(errors.ValidationErrors)
+	if !ok || len(validationErrors.GetErrorsByField("avatar")) != 1 {
+		t.Fatalf("Expected size validation error, got: %v", err)
+	}
+}
+
+func TestUploadRejectsDisallowedMime(t *testing.T) {
+	tests := map[string][]byte{
+		"script.exe": []byte("MZ\x90\x00"),
+		"fake.png":   []byte("<html><script>alert(1)</script></html>"),
+	}
+
+	for filename, content := range tests {
+		req := newUploadRequest(t, "avatar", filename, content)
+		if err := ValidateUpload(req, map[string]string{"avatar": "file|mimes:png,jpg"}); err == nil {
+			t.Errorf("Expected %s to be rejected", filename)
+		}
+	}
+
+	file, _ := RequestFile(newUploadRequest(t, "avatar", "a.png", pngHeader), "avatar")
+	reader, _ := file.Open()
+	defer reader.Close()
+	if data, _ := io.ReadAll(reader); !bytes.Equal(data, pngHeader) {
+		t.Error("Expected file to be readable after validation")
+	}
+}
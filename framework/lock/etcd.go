@@ -0,0 +1,99 @@
+package lock
+
+import (
+	"context"
+	"math"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdLocker 基于 etcd 租约的锁驱动
+//
+// 锁的键绑定到一个以 ttl 为期限的租约上，续期通过刷新租约实现，
+// 释放锁时撤销租约，键随之删除。
+type EtcdLocker struct {
+	client *clientv3.Client
+	prefix string
+}
+
+// NewEtcdLocker 创建 etcd 锁驱动，prefix 会添加到所有锁的键前
+func NewEtcdLocker(client *clientv3.Client, prefix string) *EtcdLocker {
+	return &EtcdLocker{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// TryLock 尝试获取锁
+func (l *EtcdLocker) TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	lockKey := l.prefix + key
+
+	lease, err := l.client.Grant(ctx, leaseSeconds(ttl))
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := l.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(lockKey), "=", 0)).
+		Then(clientv3.OpPut(lockKey, owner, clientv3.WithLease(lease.ID))).
+		Commit()
+	if err != nil || !resp.Succeeded {
+		l.client.Revoke(context.Background(), lease.ID)
+		return false, err
+	}
+	return true, nil
+}
+
+// Refresh 延长锁的租期
+//
+// etcd 租约的期限在创建时确定，续期会将租约重置为获取锁时的 ttl。
+func (l *EtcdLocker) Refresh(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	leaseID, err := l.ownedLease(ctx, key, owner)
+	if err != nil || leaseID == clientv3.NoLease {
+		return false, err
+	}
+
+	if _, err := l.client.KeepAliveOnce(ctx, leaseID); err != nil {
+		if err == context.Canceled || err == context.DeadlineExceeded {
+			return false, err
+		}
+		// 租约已过期
+		return false, nil
+	}
+	return true, nil
+}
+
+// Unlock 释放锁
+func (l *EtcdLocker) Unlock(ctx context.Context, key, owner string) error {
+	leaseID, err := l.ownedLease(ctx, key, owner)
+	if err != nil || leaseID == clientv3.NoLease {
+		return err
+	}
+
+	_, err = l.client.Revoke(ctx, leaseID)
+	return err
+}
+
+// ForceUnlock 不检查持有者直接释放锁
+func (l *EtcdLocker) ForceUnlock(ctx context.Context, key string) error {
+	_, err := l.client.Delete(ctx, l.prefix+key)
+	return err
+}
+
+// ownedLease 获取 owner 持有的锁绑定的租约，锁不属于 owner 时返回 NoLease
+func (l *EtcdLocker) ownedLease(ctx context.Context, key, owner string) (clientv3.LeaseID, error) {
+	resp, err := l.client.Get(ctx, l.prefix+key)
+	if err != nil {
+		return clientv3.NoLease, err
+	}
+	if len(resp.Kvs) == 0 || string(resp.Kvs[0].Value) != owner {
+		return clientv3.NoLease, nil
+	}
+	return clientv3.LeaseID(resp.Kvs[0].Lease), nil
+}
+
+// leaseSeconds 将 ttl 向上取整为租约秒数，最少 1 秒
+func leaseSeconds(ttl time.Duration) int64 {
+	return int64(math.Max(1, math.Ceil(ttl.Seconds())))
+}
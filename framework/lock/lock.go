@@ -0,0 +1,244 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// 分布式锁错误定义
+var (
+	ErrNotHeld  = errors.New("lock is not held")
+	ErrLockLost = errors.New("lock lease was lost")
+)
+
+// Locker 分布式锁驱动
+//
+// 锁通过 owner 标识持有者，只有持有者可以续期和释放。锁不可重入，
+// 同一个 owner 在锁未过期前再次获取也会失败。
+type Locker interface {
+	// TryLock 尝试获取锁，锁已被占用时返回 false
+	TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+
+	// Refresh 延长锁的租期，锁已过期或被其他持有者占用时返回 false
+	Refresh(ctx context.Context, key, owner string, ttl time.Duration) (bool, error)
+
+	// Unlock 释放锁，锁不属于 owner 时不做任何操作
+	Unlock(ctx context.Context, key, owner string) error
+
+	// ForceUnlock 不检查持有者直接释放锁，用于管理操作
+	ForceUnlock(ctx context.Context, key string) error
+}
+
+// Mutex 带自动续期的分布式互斥锁
+//
+// 获取锁后每隔 ttl/3 续期一次，临界区执行时间超过 ttl 时锁也不会过期；
+// 续期失败时 Lost 通道关闭，持有者应尽快停止临界区的操作。
+type Mutex struct {
+	locker        Locker
+	key           string
+	owner         string
+	ttl           time.Duration
+	retryInterval time.Duration
+	held          bool
+	lost          chan struct{}
+	stopRenew     chan struct{}
+	renewDone     chan struct{}
+	mu            sync.Mutex
+}
+
+// MutexOption 互斥锁选项
+type MutexOption func(*Mutex)
+
+// WithOwner 设置锁的持有者标识，默认为随机生成的 UUID
+func WithOwner(owner string) MutexOption {
+	return func(m *Mutex) {
+		m.owner = owner
+	}
+}
+
+// WithRetryInterval 设置 Lock 等待锁时的重试间隔
+func WithRetryInterval(interval time.Duration) MutexOption {
+	return func(m *Mutex) {
+		m.retryInterval = interval
+	}
+}
+
+// NewMutex 创建分布式互斥锁
+func NewMutex(locker Locker, key string, ttl time.Duration, options ...MutexOption) *Mutex {
+	m := &Mutex{
+		locker:        locker,
+		key:           key,
+		owner:         uuid.New().String(),
+		ttl:           ttl,
+		retryInterval: 50 * time.Millisecond,
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// Key 锁的键
+func (m *Mutex) Key() string {
+	return m.key
+}
+
+// Owner 锁的持有者标识
+func (m *Mutex) Owner() string {
+	return m.owner
+}
+
+// TryLock 尝试获取锁，成功后开始自动续期
+func (m *Mutex) TryLock(ctx context.Context) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.held {
+		return false, nil
+	}
+
+	acquired, err := m.locker.TryLock(ctx, m.key, m.owner, m.ttl)
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	m.held = true
+	m.lost = make(chan struct{})
+	m.stopRenew = make(chan struct{})
+	m.renewDone = make(chan struct{})
+	go m.renew(m.lost, m.stopRenew, m.renewDone)
+	return true, nil
+}
+
+// Lock 等待直到获取锁或 ctx 结束
+func (m *Mutex) Lock(ctx context.Context) error {
+	for {
+		acquired, err := m.TryLock(ctx)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+
+		select {
+		case <-time.After(m.retryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Unlock 停止续期并释放锁
+func (m *Mutex) Unlock(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.held {
+		m.mu.Unlock()
+		return ErrNotHeld
+	}
+	m.held = false
+	close(m.stopRenew)
+	renewDone := m.renewDone
+	m.mu.Unlock()
+
+	<-renewDone
+	return m.locker.Unlock(ctx, m.key, m.owner)
+}
+
+// Lost 续期失败时关闭的通道，未持有锁时返回 nil
+func (m *Mutex) Lost() <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.held {
+		return nil
+	}
+	return m.lost
+}
+
+// renew 定期续期，直到 Unlock 或续期失败
+func (m *Mutex) renew(lost, stop, done chan struct{}) {
+	defer close(done)
+
+	interval := m.ttl / 3
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			ok, err := m.locker.Refresh(ctx, m.key, m.owner, m.ttl)
+			cancel()
+			if err != nil || !ok {
+				close(lost)
+				return
+			}
+		}
+	}
+}
+
+var (
+	defaultLocker   Locker = NewMemoryLocker()
+	defaultLockerMu sync.RWMutex
+)
+
+// SetDefaultLocker 设置 WithLock 使用的默认锁驱动
+func SetDefaultLocker(locker Locker) {
+	defaultLockerMu.Lock()
+	defer defaultLockerMu.Unlock()
+	defaultLocker = locker
+}
+
+// DefaultLocker 获取默认锁驱动，未设置时为进程内的内存锁
+func DefaultLocker() Locker {
+	defaultLockerMu.RLock()
+	defer defaultLockerMu.RUnlock()
+	return defaultLocker
+}
+
+// WithLock 使用默认锁驱动获取锁后执行 fn，执行结束或 panic 时释放锁
+func WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	return WithLocker(ctx, DefaultLocker(), key, ttl, fn)
+}
+
+// WithLocker 使用指定锁驱动获取锁后执行 fn，执行结束或 panic 时释放锁
+//
+// 传给 fn 的 context 在锁丢失时取消。
+func WithLocker(ctx context.Context, locker Locker, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	mutex := NewMutex(locker, key, ttl)
+	if err := mutex.Lock(ctx); err != nil {
+		return err
+	}
+	defer mutex.Unlock(context.Background())
+
+	fnCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lost := mutex.Lost()
+	go func() {
+		select {
+		case <-lost:
+			cancel()
+		case <-fnCtx.Done():
+		}
+	}()
+
+	if err := fn(fnCtx); err != nil {
+		return err
+	}
+
+	select {
+	case <-lost:
+		return ErrLockLost
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMutexLeaseRenewal(t *testing.T) {
+	locker := NewMemoryLocker()
+	ctx := context.Background()
+
+	holder := NewMutex(locker, "report", 60*time.Millisecond)
+	if acquired, err := holder.TryLock(ctx); err != nil || !acquired {
+		t.Fatalf("Expected to acquire lock, got %v, %v", acquired, err)
+	}
+
+	// 临界区执行时间远超 ttl，续期使锁保持被占用
+	for i := 0; i < 5; i++ {
+		time.Sleep(40 * time.Millisecond)
+		if acquired, _ := locker.TryLock(ctx, "report", "other", time.Minute); acquired {
+			t.Fatalf("Expected lock to stay held after %d renewals", i+1)
+		}
+	}
+	select {
+	case <-holder.Lost():
+		t.Fatal("Expected lease not to be lost")
+	default:
+	}
+
+	if err := holder.Unlock(ctx); err != nil {
+		t.Fatalf("Failed to unlock: %v", err)
+	}
+	if err := holder.Unlock(ctx); !errors.Is(err, ErrNotHeld) {
+		t.Errorf("Expected ErrNotHeld, got %v", err)
+	}
+	if acquired, _ := locker.TryLock(ctx, "report", "other", time.Minute); !acquired {
+		t.Error("Expected lock to be free after unlock")
+	}
+
+	// 锁被强制释放后续期失败
+	locker.ForceUnlock(ctx, "report")
+	lost := NewMutex(locker, "report", 30*time.Millisecond)
+	lost.TryLock(ctx)
+	locker.ForceUnlock(ctx, "report")
+	select {
+	case <-lost.Lost():
+	case <-time.After(time.Second):
+		t.Error("Expected lease to be lost after force unlock")
+	}
+}
+
+func TestWithLockContention(t *testing.T) {
+	locker := NewMemoryLocker()
+	SetDefaultLocker(locker)
+	defer SetDefaultLocker(NewMemoryLocker())
+	ctx := context.Background()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	go WithLock(ctx, "import", time.Second, func(ctx context.Context) error {
+		close(entered)
+		<-release
+		return nil
+	})
+	<-entered
+
+	// 第二个获取者等待第一个释放
+	var mu sync.Mutex
+	var order []string
+	done := make(chan error, 1)
+	go func() {
+		done <- WithLock(ctx, "import", time.Second, func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, "second")
+			mu.Unlock()
+			return nil
+		})
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	mu.Lock()
+	order = append(order, "first released")
+	mu.Unlock()
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Expected second acquirer to succeed, got %v", err)
+	}
+	if len(order) != 2 || order[0] != "first released" {
+		t.Errorf("Expected second acquirer to block until release, got %v", order)
+	}
+
+	// 等待超时
+	mutex := NewMutex(locker, "import", time.Second)
+	mutex.TryLock(ctx)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := WithLock(timeoutCtx, "import", time.Second, func(ctx context.Context) error { return nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	mutex.Unlock(ctx)
+
+	// panic 时释放锁
+	func() {
+		defer func() { recover() }()
+		WithLock(ctx, "import", time.Second, func(ctx context.Context) error {
+			panic("boom")
+		})
+	}()
+	if acquired, _ := locker.TryLock(ctx, "import", "other", time.Second); !acquired {
+		t.Error("Expected lock to be released after panic")
+	}
+}
@@ -0,0 +1,79 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryEntry 内存锁记录
+type memoryEntry struct {
+	owner     string
+	expiresAt time.Time
+}
+
+// MemoryLocker 进程内的锁驱动，适用于单进程多节点场景和测试
+type MemoryLocker struct {
+	mu    sync.Mutex
+	locks map[string]memoryEntry
+}
+
+// NewMemoryLocker 创建内存锁驱动
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{
+		locks: make(map[string]memoryEntry),
+	}
+}
+
+// TryLock 尝试获取锁
+func (l *MemoryLocker) TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if entry, exists := l.locks[key]; exists && now.Before(entry.expiresAt) {
+		return false, nil
+	}
+
+	l.locks[key] = memoryEntry{
+		owner:     owner,
+		expiresAt: now.Add(ttl),
+	}
+	return true, nil
+}
+
+// Refresh 延长锁的租期
+func (l *MemoryLocker) Refresh(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	entry, exists := l.locks[key]
+	if !exists || entry.owner != owner || !now.Before(entry.expiresAt) {
+		return false, nil
+	}
+
+	entry.expiresAt = now.Add(ttl)
+	l.locks[key] = entry
+	return true, nil
+}
+
+// Unlock 释放锁
+func (l *MemoryLocker) Unlock(ctx context.Context, key, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry, exists := l.locks[key]; exists && entry.owner == owner {
+		delete(l.locks, key)
+	}
+	return nil
+}
+
+// ForceUnlock 不检查持有者直接释放锁
+func (l *MemoryLocker) ForceUnlock(ctx context.Context, key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.locks, key)
+	return nil
+}
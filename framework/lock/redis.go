@@ -0,0 +1,61 @@
+package lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 只有持有者才能续期和释放锁，检查和修改需要在同一个脚本中原子执行
+var (
+	redisRefreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	redisUnlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// RedisLocker 基于 Redis SET NX PX 的锁驱动
+type RedisLocker struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisLocker 创建 Redis 锁驱动，prefix 会添加到所有锁的键前
+func NewRedisLocker(client redis.UniversalClient, prefix string) *RedisLocker {
+	return &RedisLocker{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// TryLock 尝试获取锁
+func (l *RedisLocker) TryLock(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	return l.client.SetNX(ctx, l.prefix+key, owner, ttl).Result()
+}
+
+// Refresh 延长锁的租期
+func (l *RedisLocker) Refresh(ctx context.Context, key, owner string, ttl time.Duration) (bool, error) {
+	result, err := redisRefreshScript.Run(ctx, l.client, []string{l.prefix + key}, owner, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+// Unlock 释放锁
+func (l *RedisLocker) Unlock(ctx context.Context, key, owner string) error {
+	return redisUnlockScript.Run(ctx, l.client, []string{l.prefix + key}, owner).Err()
+}
+
+// ForceUnlock 不检查持有者直接释放锁
+func (l *RedisLocker) ForceUnlock(ctx context.Context, key string) error {
+	return l.client.Del(ctx, l.prefix+key).Err()
+}
@@ -0,0 +1,206 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"laravel-go/framework/queue"
+	"laravel-go/framework/template"
+)
+
+// fakeSMTPServer 记录收到邮件的本地 SMTP 服务
+type fakeSMTPServer struct {
+	listener   net.Listener
+	mu         sync.Mutex
+	from       string
+	recipients []string
+	data       string
+	auth       string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		s.mu.Lock()
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(command, "AUTH PLAIN"):
+			s.auth = strings.TrimSpace(line[len("AUTH PLAIN"):])
+			reply("235 Authentication successful")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.recipients = append(s.recipients, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil || dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			s.mu.Unlock()
+			return
+		default:
+			reply("250 OK")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	host, portText, _ := net.SplitHostPort(server.listener.Addr().String())
+	port, _ := strconv.Atoi(portText)
+
+	views := t.TempDir()
+	os.WriteFile(filepath.Join(views, "welcome.blade.php"), []byte("<p>欢迎, {{ $name }}</p>"), 0644)
+
+	mailer, err := NewMailer(Config{Driver: "smtp", Host: host, Port: port, Username: "user", Password: "secret", From: "App <noreply@example.com>"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	message := NewMessage().
+		To("Alice <alice@example.com>").
+		Cc("bob@example.com").
+		Bcc("audit@example.com").
+		Subject("欢迎注册").
+		Text("Welcome, Alice").
+		View(template.NewEngine(views, "", false), "welcome", template.Data{"name": "Alice"}).
+		Attach("report.csv", []byte("id,name\n1,Alice\n"))
+
+	if err := mailer.Send(message); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if server.from != "noreply@example.com" || server.auth == "" {
+		t.Errorf("Expected authenticated sender noreply@example.com, got %q (auth %q)", server.from, server.auth)
+	}
+	if strings.Join(server.recipients, ",") != "alice@example.com,bob@example.com,audit@example.com" {
+		t.Errorf("Unexpected recipients: %v", server.recipients)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(server.data))
+	if err != nil {
+		t.Fatalf("Expected valid MIME message, got: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if subject != "欢迎注册" {
+		t.Errorf("Expected decoded subject, got %q", subject)
+	}
+	if parsed.Header.Get("Bcc") != "" || strings.Contains(server.data, "audit@example.com") {
+		t.Error("Expected Bcc to be omitted from headers")
+	}
+	if !strings.HasPrefix(parsed.Header.Get("Content-Type"), "multipart/mixed") {
+		t.Errorf("Expected multipart/mixed message, got %s", parsed.Header.Get("Content-Type"))
+	}
+	for _, expected := range []string{"multipart/alternative", "text/plain", "text/html", `filename=report.csv`, "Welcome, Alice"} {
+		if !strings.Contains(server.data, expected) {
+			t.Errorf("Expected message to contain %q", expected)
+		}
+	}
+}
+
+func TestMessageValidation(t *testing.T) {
+	if err := NewMessage().From("a@example.com").Validate(); err != ErrNoRecipients {
+		t.Errorf("Expected ErrNoRecipients, got: %v", err)
+	}
+	if err := NewMessage().To("a@example.com").Validate(); err != ErrNoSender {
+		t.Errorf("Expected ErrNoSender, got: %v", err)
+	}
+	if err := NewMessage().From("a@example.com").To("not an address").Validate(); err == nil {
+		t.Error("Expected invalid recipient error")
+	}
+}
+
+func TestQueueSend(t *testing.T) {
+	mailer := NewArrayMailer("noreply@example.com")
+	RegisterQueueHandler(mailer)
+
+	q := queue.NewMemoryQueue()
+	message := NewMessage().To("alice@example.com").Subject("订单确认").HTML("<p>订单已创建</p>").Attach("a.txt", []byte("x"))
+	if err := QueueSend(q, message); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(mailer.Messages()) != 0 {
+		t.Fatal("Expected mail not to be sent before the worker runs")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	job, err := q.Pop(ctx)
+	if err != nil {
+		t.Fatalf("Expected queued job, got: %v", err)
+	}
+	if err := queue.NewWorker(q, DefaultQueue).Process(job); err != nil {
+		t.Fatalf("Expected worker to send mail, got: %v", err)
+	}
+
+	sent := mailer.Messages()
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 sent message, got %d", len(sent))
+	}
+	if sent[0].GetSubject() != "订单确认" || sent[0].GetFrom() != "noreply@example.com" || len(sent[0].GetAttachments()) != 1 {
+		t.Errorf("Unexpected sent message: %+v", sent[0])
+	}
+
+	if err := QueueSend(q, NewMessage().Subject("no recipients")); err != ErrNoRecipients {
+		t.Errorf("Expected ErrNoRecipients, got: %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package mail
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 邮件相关错误
+var (
+	ErrNoSender     = errors.New("mail sender is required")
+	ErrNoRecipients = errors.New("mail requires at least one recipient")
+)
+
+// Mailer 邮件发送器接口
+type Mailer interface {
+	Send(message *Message) error
+}
+
+// Config 邮件配置
+type Config struct {
+	Driver   string // smtp、log 或 array
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// Encryption 加密方式：tls 表示直接使用 TLS 连接（通常是 465 端口），
+	// 其他值在服务器支持时使用 STARTTLS
+	Encryption string
+	// From 默认发件人
+	From    string
+	Timeout time.Duration
+}
+
+// NewMailer 根据配置创建邮件发送器
+func NewMailer(config Config) (Mailer, error) {
+	switch config.Driver {
+	case "smtp", "":
+		if config.Host == "" {
+			return nil, errors.New("smtp mailer requires a host")
+		}
+		return NewSMTPMailer(config), nil
+	case "log":
+		return NewLogMailer(os.Stdout, config.From), nil
+	case "array":
+		return NewArrayMailer(config.From), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", config.Driver)
+	}
+}
+
+// SMTPMailer SMTP 邮件发送器
+type SMTPMailer struct {
+	config Config
+}
+
+// NewSMTPMailer 创建 SMTP 邮件发送器
+func NewSMTPMailer(config Config) *SMTPMailer {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 30 * time.Second
+	}
+
+	return &SMTPMailer{
+		config: config,
+	}
+}
+
+// Send 发送邮件
+func (m *SMTPMailer) Send(message *Message) error {
+	if message.from == "" {
+		message.From(m.config.From)
+	}
+
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	recipients, err := message.Recipients()
+	if err != nil {
+		return err
+	}
+	sender, err := mail.ParseAddress(message.from)
+	if err != nil {
+		return err
+	}
+
+	client, err := m.dial()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Mail(sender.Address); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("recipient %s rejected: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// dial 连接 SMTP 服务器，按配置使用 TLS 并认证
+func (m *SMTPMailer) dial() (*smtp.Client, error) {
+	address := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: m.config.Timeout}
+	if strings.EqualFold(m.config.Encryption, "tls") {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(m.config.Timeout))
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if _, isTLS := conn.(*tls.Conn); !isTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+
+	if m.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			client.Close()
+			return nil, errors.New("smtp server does not support authentication")
+		}
+		auth := smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// LogMailer 日志邮件发送器，将邮件写入日志而不实际发送，适用于开发环境
+type LogMailer struct {
+	writer io.Writer
+	from   string
+	mu     sync.Mutex
+}
+
+// NewLogMailer 创建日志邮件发送器
+func NewLogMailer(writer io.Writer, from string) *LogMailer {
+	return &LogMailer{
+		writer: writer,
+		from:   from,
+	}
+}
+
+// Send 将邮件内容写入日志
+func (m *LogMailer) Send(message *Message) error {
+	if message.from == "" {
+		message.From(m.from)
+	}
+
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err = fmt.Fprintf(m.writer, "[mail] %s\n%s\n", time.Now().Format(time.RFC3339), data)
+	return err
+}
+
+// ArrayMailer 内存邮件发送器，保存已发送的邮件，适用于测试
+type ArrayMailer struct {
+	from     string
+	messages []*Message
+	mu       sync.Mutex
+}
+
+// NewArrayMailer 创建内存邮件发送器
+func NewArrayMailer(from string) *ArrayMailer {
+	return &ArrayMailer{
+		from: from,
+	}
+}
+
+// Send 保存邮件
+func (m *ArrayMailer) Send(message *Message) error {
+	if message.from == "" {
+		message.From(m.from)
+	}
+	if err := message.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+// Messages 获取已发送的邮件
+func (m *ArrayMailer) Messages() []*Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Message(nil), m.messages...)
+}
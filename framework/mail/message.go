@@ -0,0 +1,473 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"laravel-go/framework/template"
+)
+
+// Attachment 邮件附件
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
+}
+
+// ViewRenderer 邮件模板渲染器，*template.Engine 实现了该接口
+type ViewRenderer interface {
+	Render(name string, data template.Data) (string, error)
+}
+
+// Message 邮件消息
+type Message struct {
+	from        string
+	to          []string
+	cc          []string
+	bcc         []string
+	replyTo     string
+	subject     string
+	text        string
+	html        string
+	attachments []Attachment
+	headers     map[string]string
+	err         error
+}
+
+// messagePayload 消息的序列化格式，排队发送时作为任务载荷
+type messagePayload struct {
+	From        string            `json:"from"`
+	To          []string          `json:"to,omitempty"`
+	Cc          []string          `json:"cc,omitempty"`
+	Bcc         []string          `json:"bcc,omitempty"`
+	ReplyTo     string            `json:"reply_to,omitempty"`
+	Subject     string            `json:"subject"`
+	Text        string            `json:"text,omitempty"`
+	HTML        string            `json:"html,omitempty"`
+	Attachments []Attachment      `json:"attachments,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+// NewMessage 创建邮件消息
+func NewMessage() *Message {
+	return &Message{
+		headers: make(map[string]string),
+	}
+}
+
+// MarshalJSON 序列化消息
+func (m *Message) MarshalJSON() ([]byte, error) {
+	return json.Marshal(messagePayload{
+		From:        m.from,
+		To:          m.to,
+		Cc:          m.cc,
+		Bcc:         m.bcc,
+		ReplyTo:     m.replyTo,
+		Subject:     m.subject,
+		Text:        m.text,
+		HTML:        m.html,
+		Attachments: m.attachments,
+		Headers:     m.headers,
+	})
+}
+
+// UnmarshalJSON 反序列化消息
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var payload messagePayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+
+	*m = Message{
+		from:        payload.From,
+		to:          payload.To,
+		cc:          payload.Cc,
+		bcc:         payload.Bcc,
+		replyTo:     payload.ReplyTo,
+		subject:     payload.Subject,
+		text:        payload.Text,
+		html:        payload.HTML,
+		attachments: payload.Attachments,
+		headers:     payload.Headers,
+	}
+	return nil
+}
+
+// GetFrom 获取发件人
+func (m *Message) GetFrom() string {
+	return m.from
+}
+
+// GetTo 获取收件人
+func (m *Message) GetTo() []string {
+	return m.to
+}
+
+// GetCc 获取抄送
+func (m *Message) GetCc() []string {
+	return m.cc
+}
+
+// GetBcc 获取密送
+func (m *Message) GetBcc() []string {
+	return m.bcc
+}
+
+// GetSubject 获取主题
+func (m *Message) GetSubject() string {
+	return m.subject
+}
+
+// GetText 获取纯文本正文
+func (m *Message) GetText() string {
+	return m.text
+}
+
+// GetHTML 获取 HTML 正文
+func (m *Message) GetHTML() string {
+	return m.html
+}
+
+// GetAttachments 获取附件
+func (m *Message) GetAttachments() []Attachment {
+	return m.attachments
+}
+
+// From 设置发件人
+func (m *Message) From(address string) *Message {
+	m.from = address
+	return m
+}
+
+// To 添加收件人
+func (m *Message) To(addresses ...string) *Message {
+	m.to = append(m.to, addresses...)
+	return m
+}
+
+// Cc 添加抄送
+func (m *Message) Cc(addresses ...string) *Message {
+	m.cc = append(m.cc, addresses...)
+	return m
+}
+
+// Bcc 添加密送，密送地址不会出现在邮件头中
+func (m *Message) Bcc(addresses ...string) *Message {
+	m.bcc = append(m.bcc, addresses...)
+	return m
+}
+
+// ReplyTo 设置回复地址
+func (m *Message) ReplyTo(address string) *Message {
+	m.replyTo = address
+	return m
+}
+
+// Subject 设置主题
+func (m *Message) Subject(subject string) *Message {
+	m.subject = subject
+	return m
+}
+
+// Text 设置纯文本正文
+func (m *Message) Text(body string) *Message {
+	m.text = body
+	return m
+}
+
+// HTML 设置 HTML 正文
+func (m *Message) HTML(body string) *Message {
+	m.html = body
+	return m
+}
+
+// View 使用模板渲染 HTML 正文
+func (m *Message) View(renderer ViewRenderer, name string, data template.Data) *Message {
+	body, err := renderer.Render(name, data)
+	if err != nil {
+		m.err = fmt.Errorf("failed to render mail view %s: %w", name, err)
+		return m
+	}
+	m.html = body
+	return m
+}
+
+// TextView 使用模板渲染纯文本正文
+func (m *Message) TextView(renderer ViewRenderer, name string, data template.Data) *Message {
+	body, err := renderer.Render(name, data)
+	if err != nil {
+		m.err = fmt.Errorf("failed to render mail view %s: %w", name, err)
+		return m
+	}
+	m.text = body
+	return m
+}
+
+// Attach 添加附件，contentType 为空时根据文件名推断
+func (m *Message) Attach(filename string, content []byte, contentType ...string) *Message {
+	attachment := Attachment{
+		Filename: filepath.Base(filename),
+		Content:  content,
+	}
+	if len(contentType) > 0 && contentType[0] != "" {
+		attachment.ContentType = contentType[0]
+	} else if detected := mime.TypeByExtension(filepath.Ext(filename)); detected != "" {
+		attachment.ContentType = detected
+	} else {
+		attachment.ContentType = "application/octet-stream"
+	}
+
+	m.attachments = append(m.attachments, attachment)
+	return m
+}
+
+// AttachFile 添加本地文件作为附件
+func (m *Message) AttachFile(path string) *Message {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		m.err = fmt.Errorf("failed to read attachment %s: %w", path, err)
+		return m
+	}
+	return m.Attach(path, content)
+}
+
+// Header 设置自定义邮件头
+func (m *Message) Header(key, value string) *Message {
+	if m.headers == nil {
+		m.headers = make(map[string]string)
+	}
+	m.headers[key] = value
+	return m
+}
+
+// Recipients 获取所有收件地址，包括抄送和密送
+func (m *Message) Recipients() ([]string, error) {
+	var recipients []string
+	for _, list := range [][]string{m.to, m.cc, m.bcc} {
+		for _, address := range list {
+			parsed, err := mail.ParseAddress(address)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recipient %q: %w", address, err)
+			}
+			recipients = append(recipients, parsed.Address)
+		}
+	}
+	return recipients, nil
+}
+
+// Validate 验证消息是否可以发送
+func (m *Message) Validate() error {
+	if m.err != nil {
+		return m.err
+	}
+	if len(m.to)+len(m.cc)+len(m.bcc) == 0 {
+		return ErrNoRecipients
+	}
+	if _, err := m.Recipients(); err != nil {
+		return err
+	}
+	if m.from == "" {
+		return ErrNoSender
+	}
+	if _, err := mail.ParseAddress(m.from); err != nil {
+		return fmt.Errorf("invalid sender %q: %w", m.from, err)
+	}
+	return nil
+}
+
+// Bytes 生成 MIME 格式的邮件内容
+func (m *Message) Bytes() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	header("From", formatAddresses(m.from))
+	if len(m.to) > 0 {
+		header("To", formatAddresses(m.to...))
+	}
+	if len(m.cc) > 0 {
+		header("Cc", formatAddresses(m.cc...))
+	}
+	if m.replyTo != "" {
+		header("Reply-To", formatAddresses(m.replyTo))
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", m.subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", messageID(m.from))
+	header("MIME-Version", "1.0")
+	for key, value := range m.headers {
+		header(textproto.CanonicalMIMEHeaderKey(key), mime.QEncoding.Encode("utf-8", value))
+	}
+
+	bodyHeader, body, err := m.body()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.attachments) == 0 {
+		writeHeader(&buf, bodyHeader)
+		buf.WriteString("\r\n")
+		buf.Write(body)
+		return buf.Bytes(), nil
+	}
+
+	var content bytes.Buffer
+	mixed := multipart.NewWriter(&content)
+
+	part, err := mixed.CreatePart(bodyHeader)
+	if err != nil {
+		return nil, err
+	}
+	part.Write(body)
+
+	for _, attachment := range m.attachments {
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		writeBase64(part, attachment.Content)
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+
+	header("Content-Type", "multipart/mixed; boundary="+mixed.Boundary())
+	buf.WriteString("\r\n")
+	buf.Write(content.Bytes())
+	return buf.Bytes(), nil
+}
+
+// body 生成正文的头和内容，同时有纯文本和 HTML 时使用 multipart/alternative
+func (m *Message) body() (textproto.MIMEHeader, []byte, error) {
+	if m.text == "" || m.html == "" {
+		contentType, body := "text/plain; charset=utf-8", m.text
+		if m.html != "" {
+			contentType, body = "text/html; charset=utf-8", m.html
+		}
+
+		encoded, err := quotedPrintable(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		return textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		}, encoded, nil
+	}
+
+	var content bytes.Buffer
+	alternative := multipart.NewWriter(&content)
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", m.text},
+		{"text/html; charset=utf-8", m.html},
+	} {
+		writer, err := alternative.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded, err := quotedPrintable(part.body)
+		if err != nil {
+			return nil, nil, err
+		}
+		writer.Write(encoded)
+	}
+	if err := alternative.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alternative.Boundary()},
+	}, content.Bytes(), nil
+}
+
+// writeHeader 按固定顺序写入邮件头
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+}
+
+// formatAddresses 格式化邮件地址，显示名称按 RFC 2047 编码
+func formatAddresses(addresses ...string) string {
+	formatted := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if parsed, err := mail.ParseAddress(address); err == nil {
+			formatted = append(formatted, parsed.String())
+		} else {
+			formatted = append(formatted, address)
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// messageID 生成 Message-ID
+func messageID(from string) string {
+	domain := "localhost"
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndex(parsed.Address, "@"); i >= 0 {
+			domain = parsed.Address[i+1:]
+		}
+	}
+
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// quotedPrintable 使用 quoted-printable 编码正文
+func quotedPrintable(body string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := quotedprintable.NewWriter(&buf)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64 按 76 字符换行写入 base64 内容
+func writeBase64(w io.Writer, content []byte) {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 76 {
+		w.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
@@ -0,0 +1,40 @@
+package mail
+
+import (
+	"encoding/json"
+
+	"laravel-go/framework/queue"
+)
+
+// QueueJobName 排队发送邮件的任务处理器名称
+const QueueJobName = "mail.send"
+
+// DefaultQueue 排队发送邮件使用的队列名称
+const DefaultQueue = "mail"
+
+// QueueSend 将邮件推送到队列，由工作进程异步发送
+//
+// 工作进程需要先调用 RegisterQueueHandler 注册发送器。
+func QueueSend(q queue.Queue, message *Message) error {
+	if err := message.Validate(); err != nil && err != ErrNoSender {
+		return err
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return q.Push(queue.NewHandlerJob(QueueJobName, payload, DefaultQueue))
+}
+
+// RegisterQueueHandler 注册发送排队邮件的任务处理器
+func RegisterQueueHandler(mailer Mailer) {
+	queue.RegisterHandler(QueueJobName, func(job queue.Job) error {
+		message := NewMessage()
+		if err := json.Unmarshal(job.GetPayload(), message); err != nil {
+			return err
+		}
+		return mailer.Send(message)
+	})
+}
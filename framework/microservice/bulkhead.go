@@ -0,0 +1,111 @@
+package microservice
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBulkheadFull 并发调用数已达上限，请求被拒绝
+var ErrBulkheadFull = errors.New("bulkhead is full")
+
+// Bulkhead 舱壁，限制对单个服务的并发调用数
+//
+// 一个依赖变慢时，等待它的调用最多占用 maxConcurrent 个并发名额，超出的调用被拒绝或在
+// maxWait 内排队，避免请求堆积耗尽调用方的资源。
+type Bulkhead struct {
+	slots    chan struct{}
+	maxWait  time.Duration
+	rejected int64
+}
+
+// BulkheadStats 舱壁的使用情况
+type BulkheadStats struct {
+	MaxConcurrent int     `json:"max_concurrent"`
+	InFlight      int     `json:"in_flight"`
+	Rejected      int64   `json:"rejected"`
+	Utilization   float64 `json:"utilization"`
+}
+
+// NewBulkhead 创建舱壁，maxWait 为 0 时超出并发上限的调用立即被拒绝
+func NewBulkhead(maxConcurrent int, maxWait time.Duration) *Bulkhead {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &Bulkhead{
+		slots:   make(chan struct{}, maxConcurrent),
+		maxWait: maxWait,
+	}
+}
+
+// Acquire 获取并发名额，成功后必须调用 Release 归还
+func (b *Bulkhead) Acquire(ctx context.Context) error {
+	select {
+	case b.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if b.maxWait > 0 {
+		timer := time.NewTimer(b.maxWait)
+		defer timer.Stop()
+		select {
+		case b.slots <- struct{}{}:
+			return nil
+		case <-timer.C:
+		case <-ctx.Done():
+			atomic.AddInt64(&b.rejected, 1)
+			return ctx.Err()
+		}
+	}
+
+	atomic.AddInt64(&b.rejected, 1)
+	return ErrBulkheadFull
+}
+
+// Release 归还并发名额
+func (b *Bulkhead) Release() {
+	<-b.slots
+}
+
+// Stats 获取舱壁的使用情况
+func (b *Bulkhead) Stats() BulkheadStats {
+	inFlight := len(b.slots)
+	return BulkheadStats{
+		MaxConcurrent: cap(b.slots),
+		InFlight:      inFlight,
+		Rejected:      atomic.LoadInt64(&b.rejected),
+		Utilization:   float64(inFlight) / float64(cap(b.slots)),
+	}
+}
+
+// WithBulkhead 限制对服务的并发调用数，超出的调用立即返回 ErrBulkheadFull
+func (c *ServiceClient) WithBulkhead(serviceName string, maxConcurrent int) *ServiceClient {
+	return c.WithBulkheadWait(serviceName, maxConcurrent, 0)
+}
+
+// WithBulkheadWait 限制对服务的并发调用数，超出的调用最多排队等待 maxWait，
+// 仍没有名额时返回 ErrBulkheadFull
+func (c *ServiceClient) WithBulkheadWait(serviceName string, maxConcurrent int, maxWait time.Duration) *ServiceClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bulkheads[serviceName] = NewBulkhead(maxConcurrent, maxWait)
+	return c
+}
+
+// BulkheadStats 获取服务舱壁的使用情况，服务没有设置舱壁时返回 false
+func (c *ServiceClient) BulkheadStats(serviceName string) (BulkheadStats, bool) {
+	bulkhead := c.bulkhead(serviceName)
+	if bulkhead == nil {
+		return BulkheadStats{}, false
+	}
+	return bulkhead.Stats(), true
+}
+
+// bulkhead 获取服务的舱壁
+func (c *ServiceClient) bulkhead(serviceName string) *Bulkhead {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.bulkheads[serviceName]
+}
@@ -0,0 +1,126 @@
+package microservice
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CanaryLoadBalancer 金丝雀负载均衡器，按实例元数据中的版本分配流量比例
+//
+// 例如权重 {"v1": 95, "v2": 5} 将 5% 的请求发往 version=v2 的实例。某个版本没有健康实例时，
+// 它的流量按权重比例分给其余版本；所有配置的版本都没有健康实例时，在其余健康实例中随机选择。
+type CanaryLoadBalancer struct {
+	key     string
+	weights map[string]int
+	random  *rand.Rand
+	mu      sync.Mutex
+}
+
+// NewCanaryLoadBalancer 创建金丝雀负载均衡器，weights 为版本到流量权重的映射
+//
+// 版本默认取自元数据中的 version 字段，元数据中没有时使用 ServiceInfo.Version。
+func NewCanaryLoadBalancer(weights map[string]int) *CanaryLoadBalancer {
+	lb := &CanaryLoadBalancer{
+		key:    "version",
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	lb.SetWeights(weights)
+	return lb
+}
+
+// WithKey 设置区分版本的元数据字段
+func (lb *CanaryLoadBalancer) WithKey(key string) *CanaryLoadBalancer {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.key = key
+	return lb
+}
+
+// SetWeights 调整各版本的流量权重，可在发布过程中逐步增加新版本的比例
+func (lb *CanaryLoadBalancer) SetWeights(weights map[string]int) {
+	copied := make(map[string]int, len(weights))
+	for version, weight := range weights {
+		if weight > 0 {
+			copied[version] = weight
+		}
+	}
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	lb.weights = copied
+}
+
+// Weights 获取各版本的流量权重
+func (lb *CanaryLoadBalancer) Weights() map[string]int {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	weights := make(map[string]int, len(lb.weights))
+	for version, weight := range lb.weights {
+		weights[version] = weight
+	}
+	return weights
+}
+
+// Select 按版本权重选择服务
+func (lb *CanaryLoadBalancer) Select(services []*ServiceInfo) *ServiceInfo {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	// 按版本对健康实例分组
+	byVersion := make(map[string][]*ServiceInfo)
+	var unweighted []*ServiceInfo
+	for _, service := range services {
+		if service.Health != "healthy" {
+			continue
+		}
+		version := lb.version(service)
+		if _, weighted := lb.weights[version]; weighted {
+			byVersion[version] = append(byVersion[version], service)
+		} else {
+			unweighted = append(unweighted, service)
+		}
+	}
+
+	// 只在有健康实例的版本之间按权重分配，没有实例的版本的流量按比例分给其余版本
+	total := 0
+	for version := range byVersion {
+		total += lb.weights[version]
+	}
+	if total == 0 {
+		if len(unweighted) == 0 {
+			return nil
+		}
+		return unweighted[lb.random.Intn(len(unweighted))]
+	}
+
+	// 按版本名排序后遍历，使相同随机数总是落在同一个版本上
+	versions := make([]string, 0, len(byVersion))
+	for version := range byVersion {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	point := lb.random.Intn(total)
+	for _, version := range versions {
+		point -= lb.weights[version]
+		if point < 0 {
+			instances := byVersion[version]
+			return instances[lb.random.Intn(len(instances))]
+		}
+	}
+	return nil
+}
+
+// version 获取实例的版本
+func (lb *CanaryLoadBalancer) version(service *ServiceInfo) string {
+	if version, exists := service.Metadata[lb.key]; exists {
+		return version
+	}
+	if lb.key == "version" {
+		return service.Version
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+package microservice
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"laravel-go/framework/event"
+)
+
+// EventCircuitBreakerStateChanged 熔断器状态变化时分发的事件名称
+const EventCircuitBreakerStateChanged = "circuit_breaker.state_changed"
+
+// CircuitBreakerStateChange 熔断器状态变化事件的载荷
+type CircuitBreakerStateChange struct {
+	Name  string              `json:"name"`
+	From  CircuitBreakerState `json:"from"`
+	To    CircuitBreakerState `json:"to"`
+	Error string              `json:"error,omitempty"`
+	Time  time.Time           `json:"time"`
+}
+
+// CircuitBreakerStatus 熔断器的状态快照
+type CircuitBreakerStatus struct {
+	Name string `json:"name"`
+	CircuitBreakerStats
+}
+
+// CircuitBreakerManager 熔断器管理器，按名称管理熔断器并在状态变化时分发事件
+type CircuitBreakerManager struct {
+	factory    func(name string) CircuitBreaker
+	breakers   map[string]CircuitBreaker
+	dispatcher event.Dispatcher
+	mu         sync.RWMutex
+}
+
+// NewCircuitBreakerManager 创建熔断器管理器，factory 用于按需创建熔断器
+func NewCircuitBreakerManager(factory func(name string) CircuitBreaker) *CircuitBreakerManager {
+	return &CircuitBreakerManager{
+		factory:  factory,
+		breakers: make(map[string]CircuitBreaker),
+	}
+}
+
+// SetDispatcher 设置事件分发器，熔断器状态变化时分发 circuit_breaker.state_changed 事件
+func (m *CircuitBreakerManager) SetDispatcher(dispatcher event.Dispatcher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dispatcher = dispatcher
+}
+
+// Get 获取熔断器，不存在时使用 factory 创建，没有 factory 时返回 nil
+func (m *CircuitBreakerManager) Get(name string) CircuitBreaker {
+	m.mu.RLock()
+	breaker, exists := m.breakers[name]
+	m.mu.RUnlock()
+	if exists || m.factory == nil {
+		return breaker
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if breaker, exists = m.breakers[name]; !exists {
+		breaker = m.factory(name)
+		m.register(name, breaker)
+	}
+	return breaker
+}
+
+// Register 注册熔断器，同名熔断器会被替换
+func (m *CircuitBreakerManager) Register(name string, breaker CircuitBreaker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.register(name, breaker)
+}
+
+// register 注册熔断器并订阅其状态变化，调用方需持有锁
+func (m *CircuitBreakerManager) register(name string, breaker CircuitBreaker) {
+	if observable, ok := breaker.(interface {
+		OnStateChange(func(from, to CircuitBreakerState, err error))
+	}); ok {
+		observable.OnStateChange(func(from, to CircuitBreakerState, err error) {
+			m.dispatch(name, from, to, err)
+		})
+	}
+	m.breakers[name] = breaker
+}
+
+// Statuses 获取所有熔断器的状态，按名称排序
+func (m *CircuitBreakerManager) Statuses() []CircuitBreakerStatus {
+	m.mu.RLock()
+	names := make([]string, 0, len(m.breakers))
+	breakers := make(map[string]CircuitBreaker, len(m.breakers))
+	for name, breaker := range m.breakers {
+		names = append(names, name)
+		breakers[name] = breaker
+	}
+	m.mu.RUnlock()
+	sort.Strings(names)
+
+	statuses := make([]CircuitBreakerStatus, 0, len(names))
+	for _, name := range names {
+		status := CircuitBreakerStatus{Name: name}
+		switch breaker := breakers[name].(type) {
+		case interface{ Stats() CircuitBreakerStats }:
+			status.CircuitBreakerStats = breaker.Stats()
+		default:
+			status.State = CircuitBreakerClosed
+			if breaker.IsOpen() {
+				status.State = CircuitBreakerOpen
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// dispatch 分发熔断器状态变化事件
+func (m *CircuitBreakerManager) dispatch(name string, from, to CircuitBreakerState, err error) {
+	m.mu.RLock()
+	dispatcher := m.dispatcher
+	m.mu.RUnlock()
+	if dispatcher == nil {
+		return
+	}
+
+	change := CircuitBreakerStateChange{Name: name, From: from, To: to, Time: time.Now()}
+	if err != nil {
+		change.Error = err.Error()
+	}
+
+	e := event.NewEvent(EventCircuitBreakerStateChanged, change)
+	e.SetData("name", name)
+	e.SetData("from", string(from))
+	e.SetData("to", string(to))
+	if err != nil {
+		e.SetData("error", change.Error)
+	}
+	dispatcher.Dispatch(e)
+}
+
+// BreakerStatusHandler 以 JSON 输出所有熔断器的状态、失败计数、最近一次熔断时间和最近的失败信息
+func BreakerStatusHandler(manager *CircuitBreakerManager) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"breakers": manager.Statuses(),
+		})
+	})
+}
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 )
@@ -18,8 +21,19 @@ type ServiceClient struct {
 	timeout    time.Duration
 	retryCount int
 	retryDelay time.Duration
+	hedgeDelay time.Duration
+
+	breakers           *CircuitBreakerManager
+	bulkheads          map[string]*Bulkhead
+	retryBudgetFactory func() *RetryBudget
+	retryBudgets       map[string]*RetryBudget
+	fallbacks          map[string]FallbackFunc
+	mu                 sync.RWMutex
 }
 
+// FallbackFunc 降级函数，服务不可用时生成默认或缓存的响应
+type FallbackFunc func(ctx context.Context) ([]byte, error)
+
 // NewServiceClient 创建服务通信客户端
 func NewServiceClient(discovery ServiceDiscovery, options ...ServiceClientOption) *ServiceClient {
 	client := &ServiceClient{
@@ -30,6 +44,9 @@ func NewServiceClient(discovery ServiceDiscovery, options ...ServiceClientOption
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		bulkheads:    make(map[string]*Bulkhead),
+		retryBudgets: make(map[string]*RetryBudget),
+		fallbacks:    make(map[string]FallbackFunc),
 	}
 
 	// 应用选项
@@ -66,59 +83,218 @@ func WithHTTPClient(httpClient *http.Client) ServiceClientOption {
 	}
 }
 
+// WithHedging 为 GET 请求启用对冲请求
+//
+// 第一个实例在 delay 内没有响应时，向另一个实例发送相同的请求并采用先返回的响应，
+// 较慢的请求会被取消。对冲请求计入重试次数，只用于幂等的 GET 请求。
+func WithHedging(delay time.Duration) ServiceClientOption {
+	return func(c *ServiceClient) {
+		c.hedgeDelay = delay
+	}
+}
+
+// WithCircuitBreaker 为每个服务创建独立的熔断器
+func WithCircuitBreaker(failureThreshold int, timeout time.Duration) ServiceClientOption {
+	return func(c *ServiceClient) {
+		c.breakers = NewCircuitBreakerManager(func(serviceName string) CircuitBreaker {
+			return NewSimpleCircuitBreaker(failureThreshold, timeout)
+		})
+	}
+}
+
+// WithCircuitBreakerManager 使用指定的熔断器管理器，多个客户端可以共享同一个管理器
+func WithCircuitBreakerManager(manager *CircuitBreakerManager) ServiceClientOption {
+	return func(c *ServiceClient) {
+		c.breakers = manager
+	}
+}
+
+// WithFallback 设置服务的降级函数
+//
+// 服务的熔断器开启、舱壁已满或服务发现找不到可用实例时，调用降级函数生成响应而不是直接返回错误。
+func (c *ServiceClient) WithFallback(serviceName string, fn FallbackFunc) *ServiceClient {
+	return c.WithMethodFallback(serviceName, "", "", fn)
+}
+
+// WithMethodFallback 设置服务指定请求方法和路径的降级函数，优先于服务级别的降级函数
+//
+// path 为空时匹配该请求方法的所有路径。
+func (c *ServiceClient) WithMethodFallback(serviceName, method, path string, fn FallbackFunc) *ServiceClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fallbacks[fallbackKey(serviceName, method, path)] = fn
+	return c
+}
+
+// CircuitBreaker 获取服务的熔断器，未启用熔断器时返回 nil
+func (c *ServiceClient) CircuitBreaker(serviceName string) CircuitBreaker {
+	if c.breakers == nil {
+		return nil
+	}
+	return c.breakers.Get(serviceName)
+}
+
+// CircuitBreakerManager 获取熔断器管理器，未启用熔断器时返回 nil
+func (c *ServiceClient) CircuitBreakerManager() *CircuitBreakerManager {
+	return c.breakers
+}
+
 // Call 调用服务
+//
+// 调用耗时受 ctx 的截止时间和客户端超时时间中较早的一个限制，截止时间通过 X-Request-Deadline
+// 请求头传递给下游服务，使整条调用链共享同一个时间预算。ctx 已经过期时直接返回，不再发送请求。
 func (c *ServiceClient) Call(ctx context.Context, serviceName, method, path string, data interface{}) ([]byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("request deadline exceeded before calling service %s: %w", serviceName, err)
+	}
+
+	var response []byte
+	call := func() error {
+		var err error
+		response, err = c.call(ctx, serviceName, method, path, data)
+		return err
+	}
+
+	// 舱壁在熔断器之外，并发名额不足导致的拒绝不计入熔断器的失败
+	var err error
+	breaker := c.CircuitBreaker(serviceName)
+	bulkhead := c.bulkhead(serviceName)
+	if bulkhead != nil {
+		err = bulkhead.Acquire(ctx)
+	}
+	if err == nil {
+		if breaker != nil {
+			err = breaker.Execute(ctx, call)
+		} else {
+			err = call()
+		}
+		if bulkhead != nil {
+			bulkhead.Release()
+		}
+	}
+	if err == nil {
+		return response, nil
+	}
+
+	// 熔断、舱壁已满或没有可用实例时使用降级响应
+	var unavailable *serviceUnavailableError
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrBulkheadFull) || errors.As(err, &unavailable) {
+		if fallback := c.fallback(serviceName, method, path); fallback != nil {
+			log.Printf("Service %s unavailable for %s %s, using fallback: %v", serviceName, method, path, err)
+			if recorder, ok := breaker.(interface{ RecordFallback() }); ok {
+				recorder.RecordFallback()
+			}
+			return fallback(ctx)
+		}
+	}
+
+	return nil, err
+}
+
+// fallback 按方法和路径、方法、服务的顺序查找降级函数
+func (c *ServiceClient) fallback(serviceName, method, path string) FallbackFunc {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, key := range []string{
+		fallbackKey(serviceName, method, path),
+		fallbackKey(serviceName, method, ""),
+		fallbackKey(serviceName, "", ""),
+	} {
+		if fn, exists := c.fallbacks[key]; exists {
+			return fn
+		}
+	}
+	return nil
+}
+
+// fallbackKey 降级函数的键
+func fallbackKey(serviceName, method, path string) string {
+	return serviceName + " " + strings.ToUpper(method) + " " + path
+}
+
+// serviceUnavailableError 服务发现找不到可用实例
+type serviceUnavailableError struct {
+	serviceName string
+	err         error
+}
+
+func (e *serviceUnavailableError) Error() string {
+	return fmt.Sprintf("failed to discover service %s: %v", e.serviceName, e.err)
+}
+
+func (e *serviceUnavailableError) Unwrap() error {
+	return e.err
+}
+
+// call 发现服务实例并发送请求
+func (c *ServiceClient) call(ctx context.Context, serviceName, method, path string, data interface{}) ([]byte, error) {
 	// 发现服务
 	service, err := c.discovery.DiscoverOne(ctx, serviceName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to discover service %s: %w", serviceName, err)
+		return nil, &serviceUnavailableError{serviceName: serviceName, err: err}
 	}
 
-	// 构建请求 URL
-	url := fmt.Sprintf("%s://%s:%d%s", service.Protocol, service.Address, service.Port, path)
-
 	// 序列化请求数据
-	var body io.Reader
+	var payload []byte
 	if data != nil {
-		jsonData, err := json.Marshal(data)
+		payload, err = json.Marshal(data)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request data: %w", err)
 		}
-		body = bytes.NewBuffer(jsonData)
 	}
 
-	// 创建请求
-	req, err := http.NewRequestWithContext(ctx, method, url, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// 启用重试预算时，每次调用计入请求数，每次重试消耗预算
+	budget := c.retryBudget(serviceName)
+	if budget != nil {
+		budget.RecordRequest()
 	}
 
-	// 设置请求头
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "laravel-go-microservice-client")
+	// 幂等请求使用对冲请求
+	if c.hedgeDelay > 0 && strings.EqualFold(method, http.MethodGet) {
+		return c.callHedged(ctx, serviceName, service, method, path, payload, budget)
+	}
 
-	// 添加服务元数据到请求头
-	for key, value := range service.Metadata {
-		req.Header.Set(fmt.Sprintf("X-Service-%s", key), value)
+	req, err := newServiceRequest(ctx, service, method, path, payload)
+	if err != nil {
+		return nil, err
 	}
 
 	// 执行请求（带重试）
 	var resp *http.Response
 	var lastErr error
+	attempts := 0
 
 	for i := 0; i <= c.retryCount; i++ {
+		attempts++
 		resp, lastErr = c.httpClient.Do(req)
 		if lastErr == nil && resp.StatusCode < 500 {
 			break
 		}
 
-		if i < c.retryCount {
-			time.Sleep(c.retryDelay)
+		// 截止时间已过或重试预算耗尽时不再重试
+		if ctx.Err() != nil || i == c.retryCount {
+			break
+		}
+		if budget != nil && !budget.TryRetry() {
+			break
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(c.retryDelay):
+		case <-ctx.Done():
 		}
 	}
 
 	if lastErr != nil {
-		return nil, fmt.Errorf("failed to call service after %d retries: %w", c.retryCount, lastErr)
+		return nil, fmt.Errorf("failed to call service after %d attempts: %w", attempts, lastErr)
 	}
 
 	defer resp.Body.Close()
@@ -137,6 +313,153 @@ func (c *ServiceClient) Call(ctx context.Context, serviceName, method, path stri
 	return responseBody, nil
 }
 
+// newServiceRequest 创建发往服务实例的请求
+func newServiceRequest(ctx context.Context, service *ServiceInfo, method, path string, payload []byte) (*http.Request, error) {
+	// 构建请求 URL
+	url := fmt.Sprintf("%s://%s:%d%s", service.Protocol, service.Address, service.Port, path)
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	// 创建请求
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// 设置请求头
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "laravel-go-microservice-client")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set(RequestDeadlineHeader, FormatRequestDeadline(deadline))
+	}
+
+	// 添加服务元数据到请求头
+	for key, value := range service.Metadata {
+		req.Header.Set(fmt.Sprintf("X-Service-%s", key), value)
+	}
+
+	return req, nil
+}
+
+// attemptResult 单次请求的结果
+type attemptResult struct {
+	body      []byte
+	err       error
+	retryable bool
+}
+
+// callHedged 发送对冲请求
+//
+// 第一个请求在 hedgeDelay 内没有返回时向另一个实例发送对冲请求，失败的请求在重试次数内
+// 换一个实例重试，先成功的响应会取消其余请求。整体耗时受 ctx 的截止时间限制。
+func (c *ServiceClient) callHedged(ctx context.Context, serviceName string, primary *ServiceInfo, method, path string, payload []byte, budget *RetryBudget) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan attemptResult, c.retryCount+1)
+	used := map[string]bool{}
+	attempts, inFlight := 0, 0
+	launch := func(service *ServiceInfo) {
+		used[service.ID] = true
+		attempts++
+		inFlight++
+		go func() {
+			results <- c.attempt(ctx, service, method, path, payload)
+		}()
+	}
+
+	launch(primary)
+	hedge := time.NewTimer(c.hedgeDelay)
+	defer hedge.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case <-hedge.C:
+			if attempts <= c.retryCount {
+				if service := c.nextInstance(ctx, serviceName, used); service != nil && (budget == nil || budget.TryRetry()) {
+					launch(service)
+				}
+			}
+		case result := <-results:
+			inFlight--
+			if result.err == nil {
+				return result.body, nil
+			}
+			if !result.retryable {
+				return nil, result.err
+			}
+			lastErr = result.err
+			if inFlight > 0 {
+				continue
+			}
+			if attempts > c.retryCount || (budget != nil && !budget.TryRetry()) {
+				return nil, fmt.Errorf("failed to call service after %d attempts: %w", attempts, lastErr)
+			}
+
+			select {
+			case <-time.After(c.retryDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			service := c.nextInstance(ctx, serviceName, used)
+			if service == nil {
+				service = primary
+			}
+			launch(service)
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// nextInstance 选择一个尚未请求过的实例，没有时返回 nil
+func (c *ServiceClient) nextInstance(ctx context.Context, serviceName string, used map[string]bool) *ServiceInfo {
+	services, err := c.discovery.Discover(ctx, serviceName)
+	if err != nil {
+		return nil
+	}
+	for _, service := range services {
+		if !used[service.ID] {
+			return service
+		}
+	}
+	return nil
+}
+
+// attempt 向单个实例发送一次请求
+func (c *ServiceClient) attempt(ctx context.Context, service *ServiceInfo, method, path string, payload []byte) attemptResult {
+	req, err := newServiceRequest(ctx, service, method, path, payload)
+	if err != nil {
+		return attemptResult{err: err}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return attemptResult{err: err, retryable: ctx.Err() == nil}
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return attemptResult{err: fmt.Errorf("failed to read response body: %w", err), retryable: true}
+	}
+	if resp.StatusCode >= 400 {
+		return attemptResult{
+			err:       fmt.Errorf("service returned error status %d: %s", resp.StatusCode, string(responseBody)),
+			retryable: resp.StatusCode >= 500,
+		}
+	}
+
+	return attemptResult{body: responseBody}
+}
+
 // CallJSON 调用服务并解析 JSON 响应
 func (c *ServiceClient) CallJSON(ctx context.Context, serviceName, method, path string, requestData, responseData interface{}) error {
 	responseBody, err := c.Call(ctx, serviceName, method, path, requestData)
@@ -206,13 +529,24 @@ type CircuitBreaker interface {
 	Reset()
 }
 
+// ErrCircuitOpen 熔断器开启，请求被拒绝
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// maxRecentFailures 熔断器保留的最近失败数量
+const maxRecentFailures = 10
+
 // SimpleCircuitBreaker 简单熔断器实现
 type SimpleCircuitBreaker struct {
 	failureThreshold int
 	failureCount     int
 	lastFailureTime  time.Time
+	lastTripped      time.Time
 	timeout          time.Duration
 	state            CircuitBreakerState
+	trialInFlight    bool
+	stats            CircuitBreakerStats
+	recentFailures   []CircuitBreakerFailure
+	onStateChange    func(from, to CircuitBreakerState, err error)
 	mutex            sync.RWMutex
 }
 
@@ -225,6 +559,25 @@ const (
 	CircuitBreakerHalf   CircuitBreakerState = "half-open"
 )
 
+// CircuitBreakerStats 熔断器统计信息
+type CircuitBreakerStats struct {
+	State               CircuitBreakerState     `json:"state"`
+	Requests            int64                   `json:"requests"`
+	Successes           int64                   `json:"successes"`
+	Failures            int64                   `json:"failures"`
+	Rejections          int64                   `json:"rejections"`
+	Fallbacks           int64                   `json:"fallbacks"`
+	ConsecutiveFailures int                     `json:"consecutive_failures"`
+	LastTripped         *time.Time              `json:"last_tripped,omitempty"`
+	RecentFailures      []CircuitBreakerFailure `json:"recent_failures,omitempty"`
+}
+
+// CircuitBreakerFailure 熔断器记录的一次失败
+type CircuitBreakerFailure struct {
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
+}
+
 // NewSimpleCircuitBreaker 创建简单熔断器
 func NewSimpleCircuitBreaker(failureThreshold int, timeout time.Duration) *SimpleCircuitBreaker {
 	return &SimpleCircuitBreaker{
@@ -235,41 +588,84 @@ func NewSimpleCircuitBreaker(failureThreshold int, timeout time.Duration) *Simpl
 }
 
 // Execute 执行操作
+//
+// 熔断器开启时直接返回 ErrCircuitOpen；超时后进入半开状态，只放行一次试探请求，
+// 试探成功则关闭熔断器，失败则重新开启。
 func (cb *SimpleCircuitBreaker) Execute(ctx context.Context, operation func() error) error {
-	cb.mutex.Lock()
-	defer cb.mutex.Unlock()
-
-	switch cb.state {
-	case CircuitBreakerOpen:
-		if time.Since(cb.lastFailureTime) > cb.timeout {
-			cb.state = CircuitBreakerHalf
-		} else {
-			return fmt.Errorf("circuit breaker is open")
-		}
-	case CircuitBreakerHalf:
-		// 允许一次尝试
-		cb.state = CircuitBreakerClosed
+	if err := cb.allow(); err != nil {
+		return err
 	}
 
-	// 执行操作
+	// 执行操作时不持有锁，避免串行化并发请求
 	err := operation()
 
+	cb.mutex.Lock()
+	from := cb.state
+	cb.trialInFlight = false
 	if err != nil {
+		cb.stats.Failures++
 		cb.failureCount++
 		cb.lastFailureTime = time.Now()
+		cb.recentFailures = append(cb.recentFailures, CircuitBreakerFailure{Error: err.Error(), Time: cb.lastFailureTime})
+		if len(cb.recentFailures) > maxRecentFailures {
+			cb.recentFailures = cb.recentFailures[len(cb.recentFailures)-maxRecentFailures:]
+		}
 
-		if cb.failureCount >= cb.failureThreshold {
+		if cb.state == CircuitBreakerHalf || cb.failureCount >= cb.failureThreshold {
 			cb.state = CircuitBreakerOpen
+			if from != CircuitBreakerOpen {
+				cb.lastTripped = cb.lastFailureTime
+			}
 		}
 	} else {
 		// 成功时重置
+		cb.stats.Successes++
 		cb.failureCount = 0
 		cb.state = CircuitBreakerClosed
 	}
+	to, notify := cb.state, cb.onStateChange
+	cb.mutex.Unlock()
 
+	if notify != nil && from != to {
+		notify(from, to, err)
+	}
 	return err
 }
 
+// allow 检查是否放行请求
+func (cb *SimpleCircuitBreaker) allow() error {
+	cb.mutex.Lock()
+	cb.stats.Requests++
+	switch cb.state {
+	case CircuitBreakerOpen:
+		if time.Since(cb.lastFailureTime) <= cb.timeout {
+			cb.stats.Rejections++
+			cb.mutex.Unlock()
+			return ErrCircuitOpen
+		}
+		cb.state = CircuitBreakerHalf
+		cb.trialInFlight = true
+		notify := cb.onStateChange
+		cb.mutex.Unlock()
+
+		// 在锁外通知，监听器中可以安全地读取熔断器状态
+		if notify != nil {
+			notify(CircuitBreakerOpen, CircuitBreakerHalf, nil)
+		}
+		return nil
+	case CircuitBreakerHalf:
+		// 半开状态只允许一次试探请求
+		if cb.trialInFlight {
+			cb.stats.Rejections++
+			cb.mutex.Unlock()
+			return ErrCircuitOpen
+		}
+		cb.trialInFlight = true
+	}
+	cb.mutex.Unlock()
+	return nil
+}
+
 // IsOpen 检查熔断器是否开启
 func (cb *SimpleCircuitBreaker) IsOpen() bool {
 	cb.mutex.RLock()
@@ -280,8 +676,48 @@ func (cb *SimpleCircuitBreaker) IsOpen() bool {
 // Reset 重置熔断器
 func (cb *SimpleCircuitBreaker) Reset() {
 	cb.mutex.Lock()
-	defer cb.mutex.Unlock()
-
+	from := cb.state
 	cb.failureCount = 0
+	cb.trialInFlight = false
 	cb.state = CircuitBreakerClosed
+	notify := cb.onStateChange
+	cb.mutex.Unlock()
+
+	if notify != nil && from != CircuitBreakerClosed {
+		notify(from, CircuitBreakerClosed, nil)
+	}
+}
+
+// OnStateChange 设置状态变化回调，err 为导致状态变化的操作错误
+//
+// 回调在熔断器的锁外同步调用，可以在回调中读取熔断器状态。
+func (cb *SimpleCircuitBreaker) OnStateChange(fn func(from, to CircuitBreakerState, err error)) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.onStateChange = fn
+}
+
+// RecordFallback 记录一次降级响应
+func (cb *SimpleCircuitBreaker) RecordFallback() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.stats.Fallbacks++
+}
+
+// Stats 获取熔断器统计信息
+func (cb *SimpleCircuitBreaker) Stats() CircuitBreakerStats {
+	cb.mutex.RLock()
+	defer cb.mutex.RUnlock()
+
+	stats := cb.stats
+	stats.State = cb.state
+	stats.ConsecutiveFailures = cb.failureCount
+	if !cb.lastTripped.IsZero() {
+		lastTripped := cb.lastTripped
+		stats.LastTripped = &lastTripped
+	}
+	if len(cb.recentFailures) > 0 {
+		stats.RecentFailures = append([]CircuitBreakerFailure(nil), cb.recentFailures...)
+	}
+	return stats
 }
@@ -0,0 +1,52 @@
+package microservice
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestDeadlineHeader 在服务间传递请求截止时间的请求头，值为 RFC 3339 格式的 UTC 时间
+//
+// gRPC 调用通过 context 的截止时间自动传递，不需要该请求头。
+const RequestDeadlineHeader = "X-Request-Deadline"
+
+// FormatRequestDeadline 格式化请求截止时间
+func FormatRequestDeadline(deadline time.Time) string {
+	return deadline.UTC().Format(time.RFC3339Nano)
+}
+
+// RequestDeadline 解析请求中上游服务传递的截止时间
+func RequestDeadline(r *http.Request) (time.Time, bool) {
+	value := r.Header.Get(RequestDeadlineHeader)
+	if value == "" {
+		return time.Time{}, false
+	}
+	deadline, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return deadline, true
+}
+
+// DeadlineMiddleware 将上游传递的截止时间应用到请求的 context 上
+//
+// 处理器使用 r.Context() 调用下游服务时，下游调用共享剩余的时间预算。截止时间已过的请求
+// 直接返回 504，不再执行处理器。
+func DeadlineMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := RequestDeadline(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !time.Now().Before(deadline) {
+			http.Error(w, "request deadline exceeded", http.StatusGatewayTimeout)
+			return
+		}
+
+		ctx, cancel := context.WithDeadline(r.Context(), deadline)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
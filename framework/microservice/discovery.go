@@ -15,7 +15,13 @@ type MemoryServiceDiscovery struct {
 	cacheMutex   sync.RWMutex
 	watchers     map[string]chan ServiceEvent
 	watcherMutex sync.RWMutex
+	cacheWatch   sync.Once
+	stopWatch    context.CancelFunc
 	closed       bool
+
+	localZone         string
+	minLocalInstances int
+	zoneMutex         sync.RWMutex
 }
 
 // NewMemoryServiceDiscovery 创建内存服务发现
@@ -46,6 +52,9 @@ func (d *MemoryServiceDiscovery) Discover(ctx context.Context, serviceName strin
 	}
 	d.cacheMutex.RUnlock()
 
+	// 在读取注册中心之前开始监听，避免错过读取期间发生的变化
+	d.watchCache()
+
 	// 从注册中心获取所有服务
 	allServices, err := d.registry.ListServices(ctx)
 	if err != nil {
@@ -79,7 +88,7 @@ func (d *MemoryServiceDiscovery) DiscoverOne(ctx context.Context, serviceName st
 		return nil, fmt.Errorf("no service found with name: %s", serviceName)
 	}
 
-	selected := d.loadBalancer.Select(services)
+	selected := d.loadBalancer.Select(d.preferLocalZone(services))
 	if selected == nil {
 		return nil, fmt.Errorf("no healthy service available for: %s", serviceName)
 	}
@@ -150,6 +159,12 @@ func (d *MemoryServiceDiscovery) Close() error {
 
 	d.closed = true
 
+	d.cacheMutex.Lock()
+	if d.stopWatch != nil {
+		d.stopWatch()
+	}
+	d.cacheMutex.Unlock()
+
 	// 关闭所有监听器
 	for _, watcher := range d.watchers {
 		close(watcher)
@@ -200,6 +215,35 @@ func (d *MemoryServiceDiscovery) updateCache(event ServiceEvent) {
 	}
 }
 
+// watchCache 监听注册中心的变化，服务注册、更新或注销时使该服务的缓存失效
+func (d *MemoryServiceDiscovery) watchCache() {
+	d.cacheWatch.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		events, err := d.registry.Watch(ctx)
+		if err != nil {
+			cancel()
+			return
+		}
+
+		d.cacheMutex.Lock()
+		d.stopWatch = cancel
+		d.cacheMutex.Unlock()
+
+		go func() {
+			for event := range events {
+				if event.Service == nil {
+					continue
+				}
+				d.cacheMutex.Lock()
+				delete(d.cache, event.Service.Name)
+				d.cacheMutex.Unlock()
+			}
+			// 监听结束后无法再感知变化，丢弃缓存
+			d.ClearCache()
+		}()
+	})
+}
+
 // SetLoadBalancer 设置负载均衡器
 func (d *MemoryServiceDiscovery) SetLoadBalancer(loadBalancer LoadBalancer) {
 	d.loadBalancer = loadBalancer
@@ -0,0 +1,246 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"laravel-go/framework/queue"
+)
+
+// rateLimitExhaustedMessage 限流额度耗尽的集群消息类型
+const rateLimitExhaustedMessage = "rate_limit_exhausted"
+
+// DistributedRateLimiter 基于集群锁的分布式限流器
+//
+// 每一次放行的调用都会在集群中占用一个槽位锁，槽位锁的 TTL 等于限流窗口，
+// 因此任意一个窗口长度内最多只有 limit 次调用被放行。槽位的过期由集群后端
+// 计时，各节点之间的时钟偏差不会影响计数。
+type DistributedRateLimiter struct {
+	cluster      queue.Cluster
+	nodeID       string
+	key          string
+	limit        int
+	window       time.Duration
+	pollInterval time.Duration
+	ctx          context.Context
+	cancel       context.CancelFunc
+
+	mu             sync.Mutex
+	next           int
+	exhaustedUntil time.Time
+}
+
+// DistributedRateLimiterOption 分布式限流器选项
+type DistributedRateLimiterOption func(*DistributedRateLimiter)
+
+// WithRateLimiterPollInterval 设置额度耗尽后的重试间隔
+func WithRateLimiterPollInterval(interval time.Duration) DistributedRateLimiterOption {
+	return func(l *DistributedRateLimiter) {
+		l.pollInterval = interval
+	}
+}
+
+// WithRateLimiterNodeID 设置本节点ID，广播的集群消息带上该ID，用于忽略自己发送的消息
+//
+// 未设置时生成随机ID，通常使用集群注册时的节点ID。
+func WithRateLimiterNodeID(nodeID string) DistributedRateLimiterOption {
+	return func(l *DistributedRateLimiter) {
+		l.nodeID = nodeID
+	}
+}
+
+// NewDistributedRateLimiter 创建分布式限流器
+func NewDistributedRateLimiter(cluster queue.Cluster, key string, limit int, window time.Duration, options ...DistributedRateLimiterOption) *DistributedRateLimiter {
+	if limit <= 0 {
+		limit = 1
+	}
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	limiter := &DistributedRateLimiter{
+		cluster: cluster,
+		nodeID:  uuid.New().String(),
+		key:     key,
+		limit:   limit,
+		window:  window,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	// 默认按平均槽位释放速度重试
+	limiter.pollInterval = window / time.Duration(limit)
+	if limiter.pollInterval < 10*time.Millisecond {
+		limiter.pollInterval = 10 * time.Millisecond
+	}
+
+	// 应用选项
+	for _, option := range options {
+		option(limiter)
+	}
+
+	// 部分集群实现的 Subscribe 会阻塞，支持取消订阅时在 Close 中取消
+	if subscriber, ok := cluster.(queue.ContextSubscriber); ok {
+		go subscriber.SubscribeContext(ctx, limiter.handleClusterMessage)
+	} else {
+		go cluster.Subscribe(limiter.handleClusterMessage)
+	}
+
+	return limiter
+}
+
+// Close 停止接收集群消息
+//
+// 集群实现了 ContextSubscriber 时取消订阅，否则订阅在集群关闭时才结束，
+// 在此之前收到的消息会被忽略。
+func (l *DistributedRateLimiter) Close() error {
+	l.cancel()
+	return nil
+}
+
+// Allow 检查是否允许请求，实现 RateLimiter 接口
+func (l *DistributedRateLimiter) Allow(method string) bool {
+	allowed, err := l.Take()
+	return err == nil && allowed
+}
+
+// Take 尝试占用一个调用额度
+func (l *DistributedRateLimiter) Take() (bool, error) {
+	l.mu.Lock()
+	// 每次从不同的槽位开始尝试，减少节点间的锁竞争
+	start := l.next
+	l.next = (l.next + 1) % l.limit
+	l.mu.Unlock()
+
+	var lastErr error
+	for i := 0; i < l.limit; i++ {
+		// 其他调用或节点已标记额度耗尽时不再继续竞争槽位
+		if l.exhausted() {
+			return false, nil
+		}
+
+		slot := (start + i) % l.limit
+		acquired, err := l.cluster.AcquireLock(l.slotKey(slot), l.window)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if acquired {
+			return true, nil
+		}
+	}
+
+	if lastErr != nil {
+		return false, fmt.Errorf("failed to acquire rate limit slot: %w", lastErr)
+	}
+
+	l.markExhausted(l.pollInterval)
+	l.broadcastExhausted()
+	return false, nil
+}
+
+// Wait 阻塞直到获得调用额度或上下文结束
+func (l *DistributedRateLimiter) Wait(ctx context.Context) error {
+	for {
+		allowed, err := l.Take()
+		if err != nil {
+			return err
+		}
+		if allowed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.retryDelay()):
+		}
+	}
+}
+
+// slotKey 槽位锁的键
+func (l *DistributedRateLimiter) slotKey(slot int) string {
+	return fmt.Sprintf("ratelimit:%s:%d", l.key, slot)
+}
+
+// retryDelay 距离下一次尝试的等待时间
+func (l *DistributedRateLimiter) retryDelay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if delay := time.Until(l.exhaustedUntil); delay > 0 {
+		return delay
+	}
+	return l.pollInterval
+}
+
+// exhausted 检查本地的额度耗尽标记是否有效
+func (l *DistributedRateLimiter) exhausted() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return time.Now().Before(l.exhaustedUntil)
+}
+
+// markExhausted 记录额度耗尽，在 retryAfter 内不再竞争集群锁
+func (l *DistributedRateLimiter) markExhausted(retryAfter time.Duration) {
+	if retryAfter > l.window {
+		retryAfter = l.window
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	until := time.Now().Add(retryAfter)
+	if until.After(l.exhaustedUntil) {
+		l.exhaustedUntil = until
+	}
+}
+
+// rateLimitExhausted 额度耗尽消息内容
+type rateLimitExhausted struct {
+	Key        string        `json:"key"`
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+// broadcastExhausted 通知其他节点额度已耗尽
+func (l *DistributedRateLimiter) broadcastExhausted() {
+	// 使用相对时长而不是绝对时间，避免节点时钟偏差
+	data, err := json.Marshal(rateLimitExhausted{
+		Key:        l.key,
+		RetryAfter: l.pollInterval,
+	})
+	if err != nil {
+		return
+	}
+
+	l.cluster.Broadcast(queue.ClusterMessage{
+		Type:      rateLimitExhaustedMessage,
+		NodeID:    l.nodeID,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+}
+
+// handleClusterMessage 处理集群消息
+func (l *DistributedRateLimiter) handleClusterMessage(msg queue.ClusterMessage) {
+	// 忽略自己发送的消息和关闭后收到的消息
+	if msg.Type != rateLimitExhaustedMessage || msg.NodeID == l.nodeID || l.ctx.Err() != nil {
+		return
+	}
+
+	var payload rateLimitExhausted
+	if err := json.Unmarshal(msg.Data, &payload); err != nil {
+		return
+	}
+	if payload.Key != l.key {
+		return
+	}
+
+	l.markExhausted(payload.RetryAfter)
+}
@@ -16,8 +16,12 @@ import (
 type EtcdServiceRegistry struct {
 	client     *clientv3.Client
 	prefix     string
-	leaseID    clientv3.LeaseID
+	ttl        time.Duration
 	watchers   map[string]chan ServiceEvent
+
+	// 每个服务实例使用独立的租约，注销时撤销租约，不影响同一注册中心中的其他实例
+	leases     map[string]etcdLease
+	leaseMutex sync.Mutex
 	watcherMutex sync.RWMutex
 	closed     bool
 }
@@ -51,23 +55,28 @@ func NewEtcdServiceRegistry(config *EtcdConfig) (*EtcdServiceRegistry, error) {
 		return nil, fmt.Errorf("failed to create etcd client: %w", err)
 	}
 
+	ttl := config.TTL
+	if ttl < time.Second {
+		ttl = 30 * time.Second
+	}
+
 	registry := &EtcdServiceRegistry{
 		client:   client,
 		prefix:   config.Prefix,
+		ttl:      ttl,
 		watchers: make(map[string]chan ServiceEvent),
+		leases:   make(map[string]etcdLease),
 	}
 
-	// 创建租约
-	lease, err := client.Grant(context.Background(), int64(config.TTL.Seconds()))
-	if err != nil {
-		client.Close()
-		return nil, fmt.Errorf("failed to create lease: %w", err)
-	}
-	registry.leaseID = lease.ID
-
 	return registry, nil
 }
 
+// etcdLease 服务实例的租约及其续约协程
+type etcdLease struct {
+	id     clientv3.LeaseID
+	cancel context.CancelFunc
+}
+
 // Register 注册服务
 func (e *EtcdServiceRegistry) Register(ctx context.Context, service *ServiceInfo) error {
 	if e.closed {
@@ -83,14 +92,37 @@ func (e *EtcdServiceRegistry) Register(ctx context.Context, service *ServiceInfo
 		return fmt.Errorf("failed to marshal service: %w", err)
 	}
 
+	// 为服务实例创建租约，进程异常退出时服务在 TTL 后自动过期
+	lease, err := e.client.Grant(ctx, int64(e.ttl.Seconds()))
+	if err != nil {
+		return fmt.Errorf("failed to create lease: %w", err)
+	}
+
 	// 注册服务到 etcd
-	_, err = e.client.Put(ctx, servicePath, string(data), clientv3.WithLease(e.leaseID))
+	_, err = e.client.Put(ctx, servicePath, string(data), clientv3.WithLease(lease.ID))
 	if err != nil {
+		e.client.Revoke(context.Background(), lease.ID)
 		return fmt.Errorf("failed to register service: %w", err)
 	}
 
-	// 保持租约活跃
-	go e.keepAlive()
+	// 保持租约活跃，直到服务注销或注册中心关闭
+	keepAliveCtx, cancel := context.WithCancel(context.Background())
+	keepAliveCh, err := e.client.KeepAlive(keepAliveCtx, lease.ID)
+	if err != nil {
+		cancel()
+		e.client.Revoke(context.Background(), lease.ID)
+		return fmt.Errorf("failed to keep lease alive: %w", err)
+	}
+	go func() {
+		for range keepAliveCh {
+		}
+	}()
+
+	// 重复注册同一实例时撤销旧租约
+	if previous, exists := e.swapLease(service.ID, &etcdLease{id: lease.ID, cancel: cancel}); exists {
+		previous.cancel()
+		e.client.Revoke(context.Background(), previous.id)
+	}
 
 	// 通知监听器
 	e.notifyWatchers(ServiceEvent{
@@ -125,11 +157,18 @@ func (e *EtcdServiceRegistry) Deregister(ctx context.Context, serviceID string)
 		return fmt.Errorf("service not found: %s", serviceID)
 	}
 
-	// 删除服务
-	servicePath := e.getServicePath(targetService.Name, serviceID)
-	_, err = e.client.Delete(ctx, servicePath)
-	if err != nil {
-		return fmt.Errorf("failed to deregister service: %w", err)
+	// 撤销租约会同时删除服务，没有租约（由其他进程注册）时直接删除
+	if lease, exists := e.swapLease(serviceID, nil); exists {
+		lease.cancel()
+		if _, err = e.client.Revoke(ctx, lease.id); err != nil {
+			return fmt.Errorf("failed to revoke lease: %w", err)
+		}
+	} else {
+		servicePath := e.getServicePath(targetService.Name, serviceID)
+		_, err = e.client.Delete(ctx, servicePath)
+		if err != nil {
+			return fmt.Errorf("failed to deregister service: %w", err)
+		}
 	}
 
 	// 通知监听器
@@ -156,8 +195,14 @@ func (e *EtcdServiceRegistry) Update(ctx context.Context, service *ServiceInfo)
 		return fmt.Errorf("failed to marshal service: %w", err)
 	}
 
-	// 更新服务信息
-	_, err = e.client.Put(ctx, servicePath, string(data), clientv3.WithLease(e.leaseID))
+	// 更新服务信息，保留实例的租约
+	var options []clientv3.OpOption
+	e.leaseMutex.Lock()
+	if lease, exists := e.leases[service.ID]; exists {
+		options = append(options, clientv3.WithLease(lease.id))
+	}
+	e.leaseMutex.Unlock()
+	_, err = e.client.Put(ctx, servicePath, string(data), options...)
 	if err != nil {
 		return fmt.Errorf("failed to update service: %w", err)
 	}
@@ -292,6 +337,20 @@ func (e *EtcdServiceRegistry) Close() error {
 	e.watchers = make(map[string]chan ServiceEvent)
 	e.watcherMutex.Unlock()
 
+	// 撤销本进程注册的所有服务的租约，使服务立即从发现结果中移除
+	e.leaseMutex.Lock()
+	leases := e.leases
+	e.leases = make(map[string]etcdLease)
+	e.leaseMutex.Unlock()
+	if len(leases) > 0 && e.client != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		for _, lease := range leases {
+			lease.cancel()
+			e.client.Revoke(ctx, lease.id)
+		}
+		cancel()
+	}
+
 	// 关闭 etcd 客户端
 	if e.client != nil {
 		return e.client.Close()
@@ -300,27 +359,18 @@ func (e *EtcdServiceRegistry) Close() error {
 	return nil
 }
 
-// keepAlive 保持租约活跃
-func (e *EtcdServiceRegistry) keepAlive() {
-	keepAliveCh, err := e.client.KeepAlive(context.Background(), e.leaseID)
-	if err != nil {
-		return
-	}
+// swapLease 替换服务实例的租约，lease 为 nil 时移除，返回原来的租约
+func (e *EtcdServiceRegistry) swapLease(serviceID string, lease *etcdLease) (etcdLease, bool) {
+	e.leaseMutex.Lock()
+	defer e.leaseMutex.Unlock()
 
-	for {
-		select {
-		case <-keepAliveCh:
-			// 租约保持活跃
-		case <-time.After(10 * time.Second):
-			// 超时，重新创建租约
-			lease, err := e.client.Grant(context.Background(), 30)
-			if err != nil {
-				return
-			}
-			e.leaseID = lease.ID
-			return
-		}
+	previous, exists := e.leases[serviceID]
+	if lease != nil {
+		e.leases[serviceID] = *lease
+	} else {
+		delete(e.leases, serviceID)
 	}
+	return previous, exists
 }
 
 // getServicePath 获取服务路径
@@ -0,0 +1,77 @@
+package microservice
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"laravel-go/framework/auth"
+
+	"google.golang.org/grpc/codes"
+	grpcmetadata "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GatewayUserIDMetadataKey 网关转发给后端的已认证用户 ID 的 gRPC 元数据键
+const GatewayUserIDMetadataKey = "x-user-id"
+
+// gatewayUserIDKey 请求 context 中已认证用户 ID 的键
+type gatewayUserIDKey struct{}
+
+// GatewayAuthMiddleware 网关 JWT 认证中间件
+//
+// 校验 Authorization 头中的 Bearer 令牌，缺少令牌、签名无效或已过期时返回 401。认证通过后
+// 用户 ID 写入请求 context，并作为 x-user-id 元数据随 GRPCGateway 的调用转发给后端。
+// publicPaths 中的路径不需要认证，以 /* 结尾的路径匹配该前缀下的所有路径，例如 /public/*。
+//
+// 只使用 JWTGuard 无状态的令牌校验，不会修改守卫中的当前用户，可以在并发请求间共享。
+func GatewayAuthMiddleware(guard *auth.JWTGuard, publicPaths ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPublicPath(r.URL.Path, publicPaths) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, "Bearer ") {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeGatewayError(w, status.Error(codes.Unauthenticated, auth.ErrMissingBearerToken.Error()))
+				return
+			}
+
+			claims, err := guard.ValidateToken(strings.TrimPrefix(header, "Bearer "))
+			if err != nil || claims.UserID == nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				writeGatewayError(w, status.Error(codes.Unauthenticated, auth.ErrInvalidToken.Error()))
+				return
+			}
+			userID := fmt.Sprint(claims.UserID)
+
+			ctx := context.WithValue(r.Context(), gatewayUserIDKey{}, userID)
+			ctx = grpcmetadata.AppendToOutgoingContext(ctx, GatewayUserIDMetadataKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GatewayUserID 获取网关认证中间件写入请求 context 的用户 ID
+func GatewayUserID(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(gatewayUserIDKey{}).(string)
+	return userID, ok
+}
+
+// isPublicPath 判断路径是否在免认证列表中
+func isPublicPath(path string, publicPaths []string) bool {
+	for _, public := range publicPaths {
+		if prefix, ok := strings.CutSuffix(public, "/*"); ok {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				return true
+			}
+		} else if path == public {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,609 @@
+package microservice
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcmetadata "google.golang.org/grpc/metadata"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GRPCRoute 网关路由，把 REST 路径映射到 gRPC 方法
+//
+// Path 中的 {name} 段作为路径参数写入请求消息的同名字段，查询参数同样按字段名写入，
+// 嵌套字段使用点号分隔，例如 ?page.size=10。
+//
+// GET、HEAD 和 DELETE 路由视为幂等，后端返回 Unavailable 时自动重试；其他方法只有设置
+// Idempotent 后才会重试，避免重复创建资源。
+type GRPCRoute struct {
+	HTTPMethod string `json:"http_method" yaml:"http_method"` // 例如 GET
+	Path       string `json:"path" yaml:"path"`               // 例如 /api/v1/users/{id}
+	Service    string `json:"service" yaml:"service"`         // 服务发现中的服务名
+	GRPCMethod string `json:"grpc_method" yaml:"grpc_method"` // 例如 user.UserService/GetUser
+	Idempotent bool   `json:"idempotent" yaml:"idempotent"`   // 是否允许重试非 GET/HEAD/DELETE 请求
+}
+
+// idempotent 判断路由是否可以重试
+func (r GRPCRoute) idempotent() bool {
+	switch r.HTTPMethod {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	default:
+		return r.Idempotent
+	}
+}
+
+// GRPCGateway 通用 gRPC-JSON 网关
+//
+// 网关通过服务端反射获取方法的消息描述，把 JSON 请求体转换为 protobuf 消息调用后端，
+// 新增服务只需增加路由配置，不需要重新生成网关代码。后端服务需要调用 reflection.Register 启用反射。
+//
+// 服务端流式方法通过 SSE（text/event-stream）逐条返回消息；客户端流和双向流方法无法用单个
+// HTTP 请求表达，网关返回 501。
+type GRPCGateway struct {
+	client     *GRPCServiceClient
+	routes     []*grpcGatewayRoute
+	methods    map[string]protoreflect.MethodDescriptor
+	retryCount int
+	retryDelay time.Duration
+	breakers   *CircuitBreakerManager
+	mu         sync.RWMutex
+}
+
+// GRPCGatewayOption gRPC 网关选项
+type GRPCGatewayOption func(*GRPCGateway)
+
+// WithGatewayRetry 设置幂等请求的重试次数和初始退避时间，每次重试退避时间翻倍
+func WithGatewayRetry(count int, delay time.Duration) GRPCGatewayOption {
+	return func(g *GRPCGateway) {
+		g.retryCount = count
+		g.retryDelay = delay
+	}
+}
+
+// WithGatewayCircuitBreakers 为每个后端服务启用熔断器，熔断器开启时请求直接返回 503
+func WithGatewayCircuitBreakers(manager *CircuitBreakerManager) GRPCGatewayOption {
+	return func(g *GRPCGateway) {
+		g.breakers = manager
+	}
+}
+
+// grpcGatewayRoute 解析后的网关路由
+type grpcGatewayRoute struct {
+	GRPCRoute
+	segments []string
+}
+
+// NewGRPCGateway 创建 gRPC 网关，后端连接通过 client 的服务发现建立
+func NewGRPCGateway(client *GRPCServiceClient, routes []GRPCRoute, options ...GRPCGatewayOption) (*GRPCGateway, error) {
+	gateway := &GRPCGateway{
+		client:     client,
+		methods:    make(map[string]protoreflect.MethodDescriptor),
+		retryCount: 2,
+		retryDelay: 100 * time.Millisecond,
+	}
+
+	// 应用选项
+	for _, option := range options {
+		option(gateway)
+	}
+
+	for _, route := range routes {
+		if err := gateway.AddRoute(route); err != nil {
+			return nil, err
+		}
+	}
+
+	return gateway, nil
+}
+
+// AddRoute 添加网关路由
+func (g *GRPCGateway) AddRoute(route GRPCRoute) error {
+	if route.Service == "" {
+		return fmt.Errorf("grpc gateway route %s requires a service", route.Path)
+	}
+	route.GRPCMethod = strings.TrimPrefix(route.GRPCMethod, "/")
+	if _, _, err := splitGRPCMethod(route.GRPCMethod); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(route.Path, "/") {
+		return fmt.Errorf("grpc gateway route path must start with '/': %s", route.Path)
+	}
+	if route.HTTPMethod == "" {
+		route.HTTPMethod = http.MethodPost
+	}
+	route.HTTPMethod = strings.ToUpper(route.HTTPMethod)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.routes = append(g.routes, &grpcGatewayRoute{
+		GRPCRoute: route,
+		segments:  strings.Split(strings.Trim(route.Path, "/"), "/"),
+	})
+	return nil
+}
+
+// Routes 获取网关路由
+func (g *GRPCGateway) Routes() []GRPCRoute {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	routes := make([]GRPCRoute, 0, len(g.routes))
+	for _, route := range g.routes {
+		routes = append(routes, route.GRPCRoute)
+	}
+	return routes
+}
+
+// ServeHTTP 处理 HTTP 请求
+func (g *GRPCGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, params := g.match(r.Method, r.URL.Path)
+	if route == nil {
+		writeGatewayError(w, status.Error(codes.NotFound, "no route matched"))
+		return
+	}
+
+	// 上游传递的截止时间和网关超时时间取较早的一个，gRPC 通过 context 继续向后端传递
+	ctx := r.Context()
+	if deadline, ok := RequestDeadline(r); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	ctx, cancel := context.WithTimeout(ctx, g.client.timeout)
+	defer cancel()
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		ctx = grpcmetadata.AppendToOutgoingContext(ctx, "authorization", auth)
+	}
+
+	conn, err := g.client.getConnection(ctx, route.Service)
+	if err != nil {
+		writeGatewayError(w, status.Error(codes.Unavailable, err.Error()))
+		return
+	}
+
+	method, err := g.resolveMethod(ctx, conn, route.GRPCRoute)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	if method.IsStreamingClient() {
+		writeGatewayError(w, status.Error(codes.Unimplemented, "client streaming methods are not supported by the gateway"))
+		return
+	}
+
+	request, err := buildGatewayRequest(method.Input(), r, params)
+	if err != nil {
+		writeGatewayError(w, status.Error(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	fullMethod := "/" + route.GRPCMethod
+	if method.IsStreamingServer() {
+		g.serveStream(ctx, w, conn, fullMethod, method, request)
+		return
+	}
+
+	response := dynamicpb.NewMessage(method.Output())
+	if err := g.invoke(ctx, conn, route.GRPCRoute, request, response); err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	data, err := protojson.Marshal(response)
+	if err != nil {
+		writeGatewayError(w, status.Error(codes.Internal, err.Error()))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// invoke 调用一元方法，幂等请求遇到 Unavailable 时按指数退避重试
+func (g *GRPCGateway) invoke(ctx context.Context, conn *grpc.ClientConn, route GRPCRoute, request, response proto.Message) error {
+	attempts := 1
+	if route.idempotent() && g.retryCount > 0 {
+		attempts += g.retryCount
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(g.retryDelay << (attempt - 1)):
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+
+		proto.Reset(response)
+		err = g.invokeOnce(ctx, conn, route, request, response)
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+	}
+	return err
+}
+
+// invokeOnce 通过服务的熔断器执行一次调用，只有后端故障计为熔断器失败
+func (g *GRPCGateway) invokeOnce(ctx context.Context, conn *grpc.ClientConn, route GRPCRoute, request, response proto.Message) error {
+	fullMethod := "/" + route.GRPCMethod
+	var breaker CircuitBreaker
+	if g.breakers != nil {
+		breaker = g.breakers.Get(route.Service)
+	}
+	if breaker == nil {
+		return conn.Invoke(ctx, fullMethod, request, response)
+	}
+
+	var callErr error
+	err := breaker.Execute(ctx, func() error {
+		callErr = conn.Invoke(ctx, fullMethod, request, response)
+		switch status.Code(callErr) {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+			return callErr
+		default:
+			return nil
+		}
+	})
+	if errors.Is(err, ErrCircuitOpen) {
+		return err
+	}
+	return callErr
+}
+
+// serveStream 把服务端流式方法的响应以 SSE 事件逐条写出
+func (g *GRPCGateway) serveStream(ctx context.Context, w http.ResponseWriter, conn *grpc.ClientConn, fullMethod string, method protoreflect.MethodDescriptor, request proto.Message) {
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fullMethod)
+	if err == nil {
+		err = stream.SendMsg(request)
+	}
+	if err == nil {
+		err = stream.CloseSend()
+	}
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	for {
+		response := dynamicpb.NewMessage(method.Output())
+		err := stream.RecvMsg(response)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			// 响应头已经写出，错误作为 error 事件发送
+			st, _ := status.FromError(err)
+			data, _ := json.Marshal(map[string]interface{}{"code": st.Code().String(), "message": st.Message()})
+			fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return
+		}
+
+		data, err := protojson.Marshal(response)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+// match 查找与请求匹配的路由，返回路由和路径参数
+func (g *GRPCGateway) match(method, path string) (*grpcGatewayRoute, map[string]string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for _, route := range g.routes {
+		if route.HTTPMethod != method || len(route.segments) != len(segments) {
+			continue
+		}
+
+		params := make(map[string]string)
+		matched := true
+		for i, segment := range route.segments {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				params[segment[1:len(segment)-1]] = segments[i]
+				continue
+			}
+			if segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return route, params
+		}
+	}
+
+	return nil, nil
+}
+
+// resolveMethod 通过服务端反射获取方法描述，结果按服务缓存
+func (g *GRPCGateway) resolveMethod(ctx context.Context, conn *grpc.ClientConn, route GRPCRoute) (protoreflect.MethodDescriptor, error) {
+	key := route.Service + "|" + route.GRPCMethod
+	g.mu.RLock()
+	method, exists := g.methods[key]
+	g.mu.RUnlock()
+	if exists {
+		return method, nil
+	}
+
+	serviceName, methodName, _ := splitGRPCMethod(route.GRPCMethod)
+	files, err := reflectFiles(ctx, conn, serviceName)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to resolve %s via reflection: %v", serviceName, err)
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "service %s not found: %v", serviceName, err)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "%s is not a service", serviceName)
+	}
+	method = service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, status.Errorf(codes.Unimplemented, "method %s not found in %s", methodName, serviceName)
+	}
+
+	g.mu.Lock()
+	g.methods[key] = method
+	g.mu.Unlock()
+
+	return method, nil
+}
+
+// reflectFiles 通过服务端反射获取包含服务定义的文件及其全部依赖
+func reflectFiles(ctx context.Context, conn *grpc.ClientConn, serviceName string) (*protoregistry.Files, error) {
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.CloseSend()
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	request := &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: serviceName},
+	}
+
+	for request != nil {
+		if err := stream.Send(request); err != nil {
+			return nil, err
+		}
+		response, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if errResponse := response.GetErrorResponse(); errResponse != nil {
+			return nil, fmt.Errorf("%s", errResponse.GetErrorMessage())
+		}
+
+		for _, raw := range response.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			file := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, file); err != nil {
+				return nil, err
+			}
+			files[file.GetName()] = file
+		}
+
+		// 继续请求还没有获取到的依赖
+		request = nil
+		for _, file := range files {
+			for _, dependency := range file.GetDependency() {
+				if _, exists := files[dependency]; !exists {
+					request = &rpb.ServerReflectionRequest{
+						MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: dependency},
+					}
+					break
+				}
+			}
+			if request != nil {
+				break
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range files {
+		set.File = append(set.File, file)
+	}
+	return protodesc.NewFiles(set)
+}
+
+// buildGatewayRequest 由请求体、查询参数和路径参数构建请求消息，路径参数优先
+func buildGatewayRequest(descriptor protoreflect.MessageDescriptor, r *http.Request, params map[string]string) (*dynamicpb.Message, error) {
+	message := dynamicpb.NewMessage(descriptor)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := protojson.Unmarshal(body, message); err != nil {
+			return nil, fmt.Errorf("invalid request body: %w", err)
+		}
+	}
+
+	for name, values := range r.URL.Query() {
+		for _, value := range values {
+			if err := setMessageField(message, name, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for name, value := range params {
+		if err := setMessageField(message, name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return message, nil
+}
+
+// setMessageField 按字段名或 JSON 名设置字段，嵌套字段使用点号分隔，重复字段追加
+func setMessageField(message protoreflect.Message, path, value string) error {
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		fields := message.Descriptor().Fields()
+		field := fields.ByName(protoreflect.Name(name))
+		if field == nil {
+			field = fields.ByJSONName(name)
+		}
+		if field == nil {
+			return fmt.Errorf("unknown field %q in %s", path, message.Descriptor().FullName())
+		}
+
+		if i < len(names)-1 {
+			if field.Kind() != protoreflect.MessageKind || field.IsList() || field.IsMap() {
+				return fmt.Errorf("field %q is not a message", name)
+			}
+			message = message.Mutable(field).Message()
+			continue
+		}
+
+		if field.IsMap() {
+			return fmt.Errorf("map field %q cannot be set from a parameter", path)
+		}
+		converted, err := parseFieldValue(field, value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %q: %w", path, err)
+		}
+		if field.IsList() {
+			message.Mutable(field).List().Append(converted)
+		} else {
+			message.Set(field, converted)
+		}
+	}
+	return nil
+}
+
+// parseFieldValue 把字符串参数转换为字段类型的值
+func parseFieldValue(field protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+	case protoreflect.BoolKind:
+		v, err := strconv.ParseBool(value)
+		return protoreflect.ValueOfBool(v), err
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		v, err := strconv.ParseInt(value, 10, 32)
+		return protoreflect.ValueOfInt32(int32(v)), err
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		v, err := strconv.ParseInt(value, 10, 64)
+		return protoreflect.ValueOfInt64(v), err
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		v, err := strconv.ParseUint(value, 10, 32)
+		return protoreflect.ValueOfUint32(uint32(v)), err
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		v, err := strconv.ParseUint(value, 10, 64)
+		return protoreflect.ValueOfUint64(v), err
+	case protoreflect.FloatKind:
+		v, err := strconv.ParseFloat(value, 32)
+		return protoreflect.ValueOfFloat32(float32(v)), err
+	case protoreflect.DoubleKind:
+		v, err := strconv.ParseFloat(value, 64)
+		return protoreflect.ValueOfFloat64(v), err
+	case protoreflect.BytesKind:
+		v, err := base64.StdEncoding.DecodeString(value)
+		return protoreflect.ValueOfBytes(v), err
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByName(protoreflect.Name(value)); enumValue != nil {
+			return protoreflect.ValueOfEnum(enumValue.Number()), nil
+		}
+		v, err := strconv.ParseInt(value, 10, 32)
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(v)), err
+	default:
+		return protoreflect.Value{}, fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+}
+
+// splitGRPCMethod 拆分 package.Service/Method 形式的方法名
+func splitGRPCMethod(fullMethod string) (string, string, error) {
+	index := strings.LastIndex(fullMethod, "/")
+	if index <= 0 || index == len(fullMethod)-1 {
+		return "", "", fmt.Errorf("invalid grpc method %q, expected package.Service/Method", fullMethod)
+	}
+	return fullMethod[:index], fullMethod[index+1:], nil
+}
+
+// writeGatewayError 把 gRPC 错误转换为对应的 HTTP 状态码和 JSON 响应
+func writeGatewayError(w http.ResponseWriter, err error) {
+	st, ok := status.FromError(err)
+	if errors.Is(err, ErrCircuitOpen) {
+		st = status.New(codes.Unavailable, err.Error())
+	} else if !ok {
+		st = status.New(codes.Unknown, err.Error())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(grpcHTTPStatus(st.Code()))
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":    st.Code().String(),
+		"message": st.Message(),
+	})
+}
+
+// grpcHTTPStatus gRPC 状态码对应的 HTTP 状态码
+func grpcHTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,473 @@
+package microservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"laravel-go/framework/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// gatewayTestCalls 记录测试服务按 name 收到的调用次数
+type gatewayTestCalls struct {
+	counts map[string]int
+	mu     sync.Mutex
+}
+
+func (c *gatewayTestCalls) add(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[name]++
+	return c.counts[name]
+}
+
+func (c *gatewayTestCalls) get(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[name]
+}
+
+// startGatewayTestServer 启动只依赖反射描述的 Greeter 测试服务
+//
+// SayHello 对 name 为 invalid 的请求返回 InvalidArgument，对 name 为 flaky 的请求前两次返回 Unavailable，
+// 对 name 为 whoami 的请求返回元数据中的用户 ID。
+func startGatewayTestServer(t *testing.T) (string, int, *gatewayTestCalls) {
+	t.Helper()
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("gatewaytest/greeter.proto"),
+		Package: proto.String("gatewaytest"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("HelloRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("name"), JsonName: proto.String("name"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+					{Name: proto.String("count"), JsonName: proto.String("count"), Number: proto.Int32(2), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()},
+				},
+			},
+			{
+				Name: proto.String("HelloReply"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("message"), JsonName: proto.String("message"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("Greeter"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("SayHello"), InputType: proto.String(".gatewaytest.HelloRequest"), OutputType: proto.String(".gatewaytest.HelloReply")},
+					{Name: proto.String("StreamHello"), InputType: proto.String(".gatewaytest.HelloRequest"), OutputType: proto.String(".gatewaytest.HelloReply"), ServerStreaming: proto.Bool(true)},
+				},
+			},
+		},
+	}
+
+	descriptor, err := protoregistry.GlobalFiles.FindFileByPath(file.GetName())
+	if err != nil {
+		if descriptor, err = protodesc.NewFile(file, protoregistry.GlobalFiles); err != nil {
+			t.Fatalf("Failed to build descriptor: %v", err)
+		}
+		if err := protoregistry.GlobalFiles.RegisterFile(descriptor); err != nil {
+			t.Fatalf("Failed to register descriptor: %v", err)
+		}
+	}
+	requestType := descriptor.Messages().ByName("HelloRequest")
+	replyType := descriptor.Messages().ByName("HelloReply")
+
+	reply := func(request *dynamicpb.Message, index int) *dynamicpb.Message {
+		name := request.Get(requestType.Fields().ByName("name")).String()
+		message := dynamicpb.NewMessage(replyType)
+		message.Set(replyType.Fields().ByName("message"), protoreflect.ValueOfString(fmt.Sprintf("hello %s #%d", name, index)))
+		return message
+	}
+	count := func(request *dynamicpb.Message) int {
+		return int(request.Get(requestType.Fields().ByName("count")).Int())
+	}
+
+	calls := &gatewayTestCalls{counts: make(map[string]int)}
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "gatewaytest.Greeter",
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "SayHello",
+				Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+					request := dynamicpb.NewMessage(requestType)
+					if err := dec(request); err != nil {
+						return nil, err
+					}
+					name := request.Get(requestType.Fields().ByName("name")).String()
+					switch n := calls.add(name); {
+					case name == "invalid":
+						return nil, status.Error(codes.InvalidArgument, "invalid name")
+					case name == "flaky" && n <= 2:
+						return nil, status.Error(codes.Unavailable, "temporarily unavailable")
+					case name == "whoami":
+						md, _ := metadata.FromIncomingContext(ctx)
+						message := dynamicpb.NewMessage(replyType)
+						message.Set(replyType.Fields().ByName("message"), protoreflect.ValueOfString(strings.Join(md.Get(GatewayUserIDMetadataKey), ",")))
+						return message, nil
+					}
+					return reply(request, count(request)), nil
+				},
+			},
+		},
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "StreamHello",
+				ServerStreams: true,
+				Handler: func(srv interface{}, stream grpc.ServerStream) error {
+					request := dynamicpb.NewMessage(requestType)
+					if err := stream.RecvMsg(request); err != nil {
+						return err
+					}
+					for i := 1; i <= count(request); i++ {
+						if err := stream.SendMsg(reply(request, i)); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			},
+		},
+	}, struct{}{})
+	reflection.Register(server)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	address := listener.Addr().(*net.TCPAddr)
+	return address.IP.String(), address.Port, calls
+}
+
+func TestGRPCGateway(t *testing.T) {
+	host, port, _ := startGatewayTestServer(t)
+
+	registry := NewMemoryServiceRegistry()
+	defer registry.Close()
+	registry.Register(context.Background(), &ServiceInfo{
+		ID:       "greeter-1",
+		Name:     "greeter",
+		Address:  host,
+		Port:     port,
+		Protocol: "grpc",
+		Health:   "healthy",
+	})
+
+	client := NewGRPCServiceClient(NewMemoryServiceDiscovery(registry, nil), WithGRPCTimeout(5*time.Second))
+	defer client.Close()
+
+	gateway, err := NewGRPCGateway(client, []GRPCRoute{
+		{HTTPMethod: "POST", Path: "/api/greeters/{name}/hello", Service: "greeter", GRPCMethod: "gatewaytest.Greeter/SayHello"},
+		{HTTPMethod: "GET", Path: "/api/greeters/{name}/stream", Service: "greeter", GRPCMethod: "/gatewaytest.Greeter/StreamHello"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create gateway: %v", err)
+	}
+
+	// 一元调用：请求体和路径参数转换为请求消息
+	recorder := httptest.NewRecorder()
+	gateway.ServeHTTP(recorder, httptest.NewRequest("POST", "/api/greeters/alice/hello", strings.NewReader(`{"count": 3}`)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var body map[string]string
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if body["message"] != "hello alice #3" {
+		t.Errorf("Expected transcoded reply, got %v", body)
+	}
+
+	// 服务端流：通过 SSE 逐条返回
+	recorder = httptest.NewRecorder()
+	gateway.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/greeters/bob/stream?count=2", nil))
+	if recorder.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected SSE response, got %q: %s", recorder.Header().Get("Content-Type"), recorder.Body.String())
+	}
+	if events := strings.Count(recorder.Body.String(), "data: "); events != 2 {
+		t.Errorf("Expected 2 events, got %d: %s", events, recorder.Body.String())
+	}
+	if !strings.Contains(recorder.Body.String(), "hello bob #2") {
+		t.Errorf("Expected streamed replies, got %s", recorder.Body.String())
+	}
+
+	// 参数类型错误
+	recorder = httptest.NewRecorder()
+	gateway.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/greeters/bob/stream?count=many", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid parameter, got %d", recorder.Code)
+	}
+
+	// 未配置的路由
+	recorder = httptest.NewRecorder()
+	gateway.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/unknown", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown route, got %d", recorder.Code)
+	}
+
+	if _, err := NewGRPCGateway(client, []GRPCRoute{{Path: "/api", Service: "greeter", GRPCMethod: "Greeter"}}); err == nil {
+		t.Error("Expected error for invalid grpc method")
+	}
+}
+
+func TestGRPCGatewayRetry(t *testing.T) {
+	host, port, calls := startGatewayTestServer(t)
+
+	registry := NewMemoryServiceRegistry()
+	defer registry.Close()
+	registry.Register(context.Background(), &ServiceInfo{
+		ID:       "greeter-1",
+		Name:     "greeter",
+		Address:  host,
+		Port:     port,
+		Protocol: "grpc",
+		Health:   "healthy",
+	})
+
+	client := NewGRPCServiceClient(NewMemoryServiceDiscovery(registry, nil), WithGRPCTimeout(5*time.Second))
+	defer client.Close()
+
+	breakers := NewCircuitBreakerManager(func(name string) CircuitBreaker {
+		return NewSimpleCircuitBreaker(5, time.Minute)
+	})
+	gateway, err := NewGRPCGateway(client, []GRPCRoute{
+		{HTTPMethod: "GET", Path: "/api/greeters/{name}", Service: "greeter", GRPCMethod: "gatewaytest.Greeter/SayHello"},
+		{HTTPMethod: "POST", Path: "/api/greeters/{name}", Service: "greeter", GRPCMethod: "gatewaytest.Greeter/SayHello"},
+	}, WithGatewayRetry(3, 10*time.Millisecond), WithGatewayCircuitBreakers(breakers))
+	if err != nil {
+		t.Fatalf("Failed to create gateway: %v", err)
+	}
+
+	// 幂等请求遇到临时的 Unavailable 时重试直到成功
+	recorder := httptest.NewRecorder()
+	gateway.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/greeters/flaky", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected retried request to succeed, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if n := calls.get("flaky"); n != 3 {
+		t.Errorf("Expected 3 attempts, got %d", n)
+	}
+
+	// InvalidArgument 不重试，直接返回 400
+	recorder = httptest.NewRecorder()
+	gateway.ServeHTTP(recorder, httptest.NewRequest("GET", "/api/greeters/invalid", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if n := calls.get("invalid"); n != 1 {
+		t.Errorf("Expected InvalidArgument not to be retried, got %d attempts", n)
+	}
+
+	// 非幂等的 POST 不重试，Unavailable 返回 503
+	calls.mu.Lock()
+	calls.counts["flaky"] = 0
+	calls.mu.Unlock()
+	recorder = httptest.NewRecorder()
+	gateway.ServeHTTP(recorder, httptest.NewRequest("POST", "/api/greeters/flaky", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if n := calls.get("flaky"); n != 1 {
+		t.Errorf("Expected POST not to be retried, got %d attempts", n)
+	}
+}
+
+func TestGatewayAuthMiddleware(t *testing.T) {
+	host, port, _ := startGatewayTestServer(t)
+
+	registry := NewMemoryServiceRegistry()
+	defer registry.Close()
+	registry.Register(context.Background(), &ServiceInfo{
+		ID:       "greeter-1",
+		Name:     "greeter",
+		Address:  host,
+		Port:     port,
+		Protocol: "grpc",
+		Health:   "healthy",
+	})
+
+	client := NewGRPCServiceClient(NewMemoryServiceDiscovery(registry, nil), WithGRPCTimeout(5*time.Second))
+	defer client.Close()
+
+	gateway, err := NewGRPCGateway(client, []GRPCRoute{
+		{HTTPMethod: "GET", Path: "/api/greeters/{name}", Service: "greeter", GRPCMethod: "gatewaytest.Greeter/SayHello"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create gateway: %v", err)
+	}
+
+	guard := auth.NewJWTGuard(auth.NewMemoryUserProvider(), "gateway-secret", time.Hour)
+	mux := http.NewServeMux()
+	mux.Handle("/api/", gateway)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := GatewayAuthMiddleware(guard, "/health")(mux)
+
+	request := func(token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/api/greeters/whoami", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	// 有效令牌通过认证，用户 ID 通过元数据转发给后端
+	token, err := guard.GenerateToken(&auth.BaseUser{ID: 42, Email: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	recorder := request(token)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var body map[string]string
+	json.Unmarshal(recorder.Body.Bytes(), &body)
+	if body["message"] != "42" {
+		t.Errorf("Expected user ID 42 in backend metadata, got %v", body)
+	}
+
+	// 缺少令牌、签名无效和过期的令牌返回 401
+	expired, _ := auth.NewJWTGuard(auth.NewMemoryUserProvider(), "gateway-secret", -time.Minute).GenerateToken(&auth.BaseUser{ID: 42})
+	forged, _ := auth.NewJWTGuard(auth.NewMemoryUserProvider(), "other-secret", time.Hour).GenerateToken(&auth.BaseUser{ID: 42})
+	for name, token := range map[string]string{"missing": "", "expired": expired, "forged": forged, "malformed": "not-a-jwt"} {
+		if recorder := request(token); recorder.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401 for %s token, got %d", name, recorder.Code)
+		}
+	}
+
+	// 免认证路径不需要令牌
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/health", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected public path to skip authentication, got %d", recorder.Code)
+	}
+}
+
+func TestGenerateGatewayOpenAPI(t *testing.T) {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	field := func(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{Name: proto.String(name), JsonName: proto.String(name), Number: proto.Int32(number), Label: optional, Type: kind.Enum()}
+	}
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("openapitest/user.proto"),
+		Package: proto.String("openapitest"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("GetUserRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+				field("fields", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+			}},
+			{Name: proto.String("CreateUserRequest"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				field("age", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32),
+			}},
+			{Name: proto.String("User"), Field: []*descriptorpb.FieldDescriptorProto{
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+				field("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				field("active", 3, descriptorpb.FieldDescriptorProto_TYPE_BOOL),
+			}},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("UserService"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("GetUser"), InputType: proto.String(".openapitest.GetUserRequest"), OutputType: proto.String(".openapitest.User")},
+				{Name: proto.String("CreateUser"), InputType: proto.String(".openapitest.CreateUserRequest"), OutputType: proto.String(".openapitest.User")},
+			},
+		}},
+	}
+
+	dir := t.TempDir()
+	data, _ := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	os.WriteFile(filepath.Join(dir, "api.pb"), data, 0644)
+	os.WriteFile(filepath.Join(dir, "routes.json"), []byte(`[
+		{"http_method": "GET", "path": "/api/v1/users/{id}", "service": "user-service", "grpc_method": "openapitest.UserService/GetUser"},
+		{"http_method": "POST", "path": "/api/v1/users", "service": "user-service", "grpc_method": "openapitest.UserService/CreateUser"}
+	]`), 0644)
+
+	files, err := LoadDescriptorSet(filepath.Join(dir, "api.pb"))
+	if err != nil {
+		t.Fatalf("Failed to load descriptor set: %v", err)
+	}
+	routes, err := LoadGatewayRoutes(filepath.Join(dir, "routes.json"))
+	if err != nil {
+		t.Fatalf("Failed to load routes: %v", err)
+	}
+
+	doc, err := GenerateGatewayOpenAPI(files, routes, "User API", "1.0.0")
+	if err != nil {
+		t.Fatalf("Failed to generate OpenAPI: %v", err)
+	}
+	spec := doc.Spec()
+	if len(spec.Paths) != 2 {
+		t.Fatalf("Expected 2 paths, got %d", len(spec.Paths))
+	}
+
+	// GET：路径参数和查询参数取自请求消息，响应引用 User 模式
+	get := spec.Paths["/api/v1/users/{id}"].GET
+	if get == nil {
+		t.Fatal("Expected GET operation for /api/v1/users/{id}")
+	}
+	if get.OperationID != "UserService_GetUser" || get.RequestBody != nil {
+		t.Errorf("Unexpected GET operation: %+v", get)
+	}
+	if len(get.Parameters) != 2 || get.Parameters[0].In != "path" || get.Parameters[0].Schema.Format != "int64" ||
+		get.Parameters[1].Name != "fields" || get.Parameters[1].In != "query" {
+		t.Errorf("Expected id path parameter and fields query parameter, got %+v", get.Parameters)
+	}
+	if ref := get.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/openapitest.User" {
+		t.Errorf("Expected User response schema, got %q", ref)
+	}
+
+	// POST：请求消息作为请求体
+	post := spec.Paths["/api/v1/users"].POST
+	if post == nil || post.RequestBody == nil {
+		t.Fatal("Expected POST operation with request body for /api/v1/users")
+	}
+	if ref := post.RequestBody.Content["application/json"].Schema.Ref; ref != "#/components/schemas/openapitest.CreateUserRequest" {
+		t.Errorf("Expected CreateUserRequest body schema, got %q", ref)
+	}
+
+	user := spec.Components.Schemas["openapitest.User"]
+	if user == nil {
+		t.Fatal("Expected User schema in components")
+	}
+	if user.Properties["id"].Type != "string" || user.Properties["name"].Type != "string" || user.Properties["active"].Type != "boolean" {
+		t.Errorf("Unexpected User schema properties: %+v", user.Properties)
+	}
+	if request := spec.Components.Schemas["openapitest.CreateUserRequest"]; request == nil || request.Properties["age"].Type != "integer" {
+		t.Errorf("Expected CreateUserRequest schema with integer age, got %+v", request)
+	}
+}
@@ -0,0 +1,292 @@
+package microservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"laravel-go/framework/api"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// LoadDescriptorSet 读取 protoc 生成的描述符集合文件
+//
+// 描述符集合由 protoc --include_imports --descriptor_set_out=api.pb user.proto 生成，
+// 需要包含依赖，否则引用其他文件中的消息时无法解析。
+func LoadDescriptorSet(path string) (*protoregistry.Files, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read descriptor set: %w", err)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("failed to parse descriptor set: %w", err)
+	}
+	return protodesc.NewFiles(set)
+}
+
+// LoadGatewayRoutes 读取 JSON 格式的网关路由配置
+func LoadGatewayRoutes(path string) ([]GRPCRoute, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gateway routes: %w", err)
+	}
+
+	var routes []GRPCRoute
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("failed to parse gateway routes: %w", err)
+	}
+	return routes, nil
+}
+
+// GenerateGatewayOpenAPI 根据 proto 描述和网关路由生成 OpenAPI 文档
+//
+// 每条路由生成一个路径操作：路径参数取自请求消息的同名字段，GET、HEAD 和 DELETE 请求的其余
+// 顶层标量字段作为查询参数，其他方法的请求消息作为请求体。消息按 protobuf JSON 映射生成
+// components/schemas 中的模式，服务端流式方法的响应类型为 text/event-stream。
+func GenerateGatewayOpenAPI(files *protoregistry.Files, routes []GRPCRoute, title, version string) (*api.APIDocumentation, error) {
+	doc := api.NewAPIDocumentation(title, version, "").SetBasePath("")
+	generator := &openAPIGenerator{doc: doc, schemas: make(map[protoreflect.FullName]bool)}
+
+	tags := make(map[string]bool)
+	for _, route := range routes {
+		method, err := findGatewayMethod(files, route.GRPCMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		tag := string(method.Parent().Name())
+		if !tags[tag] {
+			tags[tag] = true
+			doc.AddTag(tag, string(method.Parent().FullName()))
+		}
+
+		httpMethod := strings.ToUpper(route.HTTPMethod)
+		if httpMethod == "" {
+			httpMethod = http.MethodPost
+		}
+		doc.AddPath(openAPIPath(route.Path), httpMethod, generator.operation(route, httpMethod, method, tag))
+	}
+
+	return doc, nil
+}
+
+// findGatewayMethod 查找 package.Service/Method 对应的方法描述
+func findGatewayMethod(files *protoregistry.Files, fullMethod string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := splitGRPCMethod(strings.TrimPrefix(fullMethod, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found: %w", serviceName, err)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", serviceName)
+	}
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("method %s not found in %s", methodName, serviceName)
+	}
+	return method, nil
+}
+
+// openAPIPath 转换路由路径，OpenAPI 与网关路由使用相同的 {name} 参数形式
+func openAPIPath(path string) string {
+	return "/" + strings.Trim(path, "/")
+}
+
+// openAPIGenerator 生成路径操作和消息模式
+type openAPIGenerator struct {
+	doc     *api.APIDocumentation
+	schemas map[protoreflect.FullName]bool
+}
+
+// operation 生成路由对应的路径操作
+func (g *openAPIGenerator) operation(route GRPCRoute, httpMethod string, method protoreflect.MethodDescriptor, tag string) *api.Operation {
+	operation := api.NewOperation(string(method.Name()), string(method.FullName()))
+	operation.OperationID = string(method.Parent().Name()) + "_" + string(method.Name())
+	operation.Tags = []string{tag}
+
+	input := method.Input()
+	used := make(map[protoreflect.FullName]bool)
+	for _, segment := range strings.Split(route.Path, "/") {
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			continue
+		}
+		name := segment[1 : len(segment)-1]
+		parameter := api.NewParameter(name, "path", "", true)
+		parameter.Schema = &api.Schema{Type: "string"}
+		if field := findField(input, name); field != nil {
+			used[field.FullName()] = true
+			parameter.Schema = g.fieldSchema(field)
+		}
+		operation.Parameters = append(operation.Parameters, parameter)
+	}
+
+	switch httpMethod {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		fields := input.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			field := fields.Get(i)
+			if used[field.FullName()] || field.IsMap() || field.Kind() == protoreflect.MessageKind || field.Kind() == protoreflect.GroupKind {
+				continue
+			}
+			parameter := api.NewParameter(field.JSONName(), "query", "", false)
+			parameter.Schema = g.fieldSchema(field)
+			operation.Parameters = append(operation.Parameters, parameter)
+		}
+	default:
+		operation.RequestBody = &api.RequestBody{
+			Required: true,
+			Content: map[string]*api.MediaType{
+				"application/json": {Schema: g.messageSchema(input)},
+			},
+		}
+	}
+
+	contentType := "application/json"
+	if method.IsStreamingServer() {
+		contentType = "text/event-stream"
+	}
+	response := api.NewResponse("OK")
+	response.Content = map[string]*api.MediaType{contentType: {Schema: g.messageSchema(method.Output())}}
+	operation.Responses["200"] = response
+
+	errorResponse := api.NewResponse("Error")
+	errorResponse.Content = map[string]*api.MediaType{"application/json": {Schema: g.errorSchema()}}
+	operation.Responses["default"] = errorResponse
+
+	return operation
+}
+
+// findField 按字段名或 JSON 名查找字段
+func findField(message protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	if field := message.Fields().ByName(protoreflect.Name(name)); field != nil {
+		return field
+	}
+	return message.Fields().ByJSONName(name)
+}
+
+// messageSchema 生成消息的模式引用，消息模式注册到 components/schemas 中
+func (g *openAPIGenerator) messageSchema(message protoreflect.MessageDescriptor) *api.Schema {
+	if schema := wellKnownSchema(message.FullName()); schema != nil {
+		return schema
+	}
+
+	name := message.FullName()
+	ref := &api.Schema{Ref: "#/components/schemas/" + string(name)}
+	if g.schemas[name] {
+		return ref
+	}
+	// 先登记再生成字段，递归引用自身的消息不会无限展开
+	g.schemas[name] = true
+
+	schema := &api.Schema{Type: "object", Properties: make(map[string]*api.Schema)}
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		schema.Properties[field.JSONName()] = g.fieldSchema(field)
+	}
+	g.doc.AddSchema(string(name), schema)
+
+	return ref
+}
+
+// fieldSchema 生成字段的模式
+func (g *openAPIGenerator) fieldSchema(field protoreflect.FieldDescriptor) *api.Schema {
+	if field.IsMap() {
+		return &api.Schema{Type: "object", AdditionalProperties: g.singularSchema(field.MapValue())}
+	}
+	if field.IsList() {
+		return &api.Schema{Type: "array", Items: g.singularSchema(field)}
+	}
+	return g.singularSchema(field)
+}
+
+// singularSchema 按 protobuf JSON 映射生成单个值的模式，64 位整数编码为字符串
+func (g *openAPIGenerator) singularSchema(field protoreflect.FieldDescriptor) *api.Schema {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return &api.Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &api.Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &api.Schema{Type: "integer", Format: "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return &api.Schema{Type: "string", Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &api.Schema{Type: "string", Format: "uint64"}
+	case protoreflect.FloatKind:
+		return &api.Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &api.Schema{Type: "number", Format: "double"}
+	case protoreflect.BytesKind:
+		return &api.Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		schema := &api.Schema{Type: "string"}
+		values := field.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			schema.Enum = append(schema.Enum, string(values.Get(i).Name()))
+		}
+		return schema
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return g.messageSchema(field.Message())
+	default:
+		return &api.Schema{Type: "string"}
+	}
+}
+
+// errorSchema 网关错误响应的模式
+func (g *openAPIGenerator) errorSchema() *api.Schema {
+	const name = "GatewayError"
+	if !g.schemas[name] {
+		g.schemas[name] = true
+		g.doc.AddSchema(name, &api.Schema{
+			Type: "object",
+			Properties: map[string]*api.Schema{
+				"code":    {Type: "string"},
+				"message": {Type: "string"},
+			},
+		})
+	}
+	return &api.Schema{Ref: "#/components/schemas/" + name}
+}
+
+// wellKnownSchema protobuf 常用类型在 JSON 映射中的模式
+func wellKnownSchema(name protoreflect.FullName) *api.Schema {
+	switch name {
+	case "google.protobuf.Timestamp":
+		return &api.Schema{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration":
+		return &api.Schema{Type: "string", Example: "1.5s"}
+	case "google.protobuf.Empty":
+		return &api.Schema{Type: "object"}
+	case "google.protobuf.Struct":
+		return &api.Schema{Type: "object", AdditionalProperties: &api.Schema{}}
+	case "google.protobuf.Value":
+		return &api.Schema{}
+	case "google.protobuf.StringValue", "google.protobuf.BytesValue", "google.protobuf.FieldMask":
+		return &api.Schema{Type: "string"}
+	case "google.protobuf.BoolValue":
+		return &api.Schema{Type: "boolean"}
+	case "google.protobuf.Int32Value", "google.protobuf.UInt32Value":
+		return &api.Schema{Type: "integer"}
+	case "google.protobuf.Int64Value", "google.protobuf.UInt64Value":
+		return &api.Schema{Type: "string", Format: "int64"}
+	case "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return &api.Schema{Type: "number"}
+	default:
+		return nil
+	}
+}
@@ -2,10 +2,21 @@ package microservice
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"laravel-go/framework/event"
+	"laravel-go/framework/queue"
 )
 
 func TestServiceInfo(t *testing.T) {
@@ -356,6 +367,132 @@ func TestCircuitBreaker(t *testing.T) {
 	}
 }
 
+func TestCircuitBreakerStatusHandler(t *testing.T) {
+	manager := NewCircuitBreakerManager(func(name string) CircuitBreaker {
+		return NewSimpleCircuitBreaker(2, 20*time.Millisecond)
+	})
+	dispatcher := event.NewEventDispatcher(event.NewMemoryEventQueue())
+	defer dispatcher.Close()
+	manager.SetDispatcher(dispatcher)
+
+	var mu sync.Mutex
+	var changes []CircuitBreakerStateChange
+	dispatcher.Listen(EventCircuitBreakerStateChanged, event.NewListener("breaker-alerts", func(e event.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, e.GetPayload().(CircuitBreakerStateChange))
+		return nil
+	}))
+
+	status := func() CircuitBreakerStatus {
+		recorder := httptest.NewRecorder()
+		BreakerStatusHandler(manager).ServeHTTP(recorder, httptest.NewRequest("GET", "/breakers", nil))
+		var body struct {
+			Breakers []CircuitBreakerStatus `json:"breakers"`
+		}
+		if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil || len(body.Breakers) != 1 {
+			t.Fatalf("Unexpected status response: %+v, %v", body, err)
+		}
+		return body.Breakers[0]
+	}
+
+	ctx := context.Background()
+	breaker := manager.Get("payment-service")
+	breaker.Execute(ctx, func() error { return nil })
+	if s := status(); s.Name != "payment-service" || s.State != CircuitBreakerClosed || s.LastTripped != nil {
+		t.Errorf("Expected closed breaker, got %+v", s)
+	}
+
+	// 连续失败后熔断器开启并分发事件
+	for i := 1; i <= 2; i++ {
+		breaker.Execute(ctx, func() error { return fmt.Errorf("timeout %d", i) })
+	}
+	s := status()
+	if s.State != CircuitBreakerOpen || s.ConsecutiveFailures != 2 || s.Failures != 2 || s.LastTripped == nil {
+		t.Errorf("Expected open breaker with failure counts, got %+v", s)
+	}
+	if len(s.RecentFailures) != 2 || s.RecentFailures[1].Error != "timeout 2" {
+		t.Errorf("Expected recent failure messages, got %+v", s.RecentFailures)
+	}
+
+	mu.Lock()
+	if len(changes) != 1 || changes[0].Name != "payment-service" || changes[0].From != CircuitBreakerClosed ||
+		changes[0].To != CircuitBreakerOpen || changes[0].Error != "timeout 2" {
+		t.Errorf("Expected closed -> open event, got %+v", changes)
+	}
+	mu.Unlock()
+
+	// 超时后试探成功，经半开状态关闭
+	time.Sleep(30 * time.Millisecond)
+	if err := breaker.Execute(ctx, func() error { return nil }); err != nil {
+		t.Fatalf("Expected trial request to pass: %v", err)
+	}
+	if s := status(); s.State != CircuitBreakerClosed || s.ConsecutiveFailures != 0 || s.LastTripped == nil {
+		t.Errorf("Expected closed breaker after recovery, got %+v", s)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 3 || changes[1].To != CircuitBreakerHalf || changes[2].From != CircuitBreakerHalf || changes[2].To != CircuitBreakerClosed {
+		t.Errorf("Expected open -> half-open -> closed events, got %+v", changes)
+	}
+}
+
+func TestServiceClientFallback(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	host, portText, _ := net.SplitHostPort(strings.TrimPrefix(failing.URL, "http://"))
+	port, _ := strconv.Atoi(portText)
+
+	registry := NewMemoryServiceRegistry()
+	discovery := NewMemoryServiceDiscovery(registry, NewRoundRobinLoadBalancer())
+	ctx := context.Background()
+	registry.Register(ctx, &ServiceInfo{ID: "product-1", Name: "product-service", Address: host, Port: port, Protocol: "http", Health: "healthy"})
+
+	client := NewServiceClient(discovery, WithRetry(0, 0), WithCircuitBreaker(2, time.Minute))
+	client.WithFallback("product-service", func(ctx context.Context) ([]byte, error) {
+		return []byte(`{"products":[]}`), nil
+	})
+	client.WithMethodFallback("product-service", "GET", "/products/featured", func(ctx context.Context) ([]byte, error) {
+		return []byte(`{"featured":"cached"}`), nil
+	})
+
+	// 熔断器开启前返回下游错误
+	for i := 0; i < 2; i++ {
+		if _, err := client.Get(ctx, "product-service", "/products"); err == nil {
+			t.Fatal("Expected downstream error before the breaker opens")
+		}
+	}
+
+	// 熔断器开启后使用降级响应
+	body, err := client.Get(ctx, "product-service", "/products")
+	if err != nil || string(body) != `{"products":[]}` {
+		t.Errorf("Expected service fallback, got %s, %v", body, err)
+	}
+	body, err = client.Get(ctx, "product-service", "/products/featured")
+	if err != nil || string(body) != `{"featured":"cached"}` {
+		t.Errorf("Expected method fallback, got %s, %v", body, err)
+	}
+
+	stats := client.CircuitBreaker("product-service").(*SimpleCircuitBreaker).Stats()
+	if stats.State != CircuitBreakerOpen || stats.Failures != 2 || stats.Rejections != 2 || stats.Fallbacks != 2 {
+		t.Errorf("Unexpected breaker stats: %+v", stats)
+	}
+
+	// 没有可用实例时同样使用降级响应，没有降级函数时返回错误
+	client.WithFallback("inventory-service", func(ctx context.Context) ([]byte, error) {
+		return []byte(`{"stock":0}`), nil
+	})
+	if body, err := client.Get(ctx, "inventory-service", "/stock/1"); err != nil || string(body) != `{"stock":0}` {
+		t.Errorf("Expected fallback when no instances are available, got %s, %v", body, err)
+	}
+	if _, err := client.Get(ctx, "order-service", "/orders"); err == nil {
+		t.Error("Expected error for a service without fallback")
+	}
+}
+
 func TestServiceRegistryCleanup(t *testing.T) {
 	registry := NewMemoryServiceRegistry()
 	ctx := context.Background()
@@ -483,3 +620,616 @@ func TestServiceRegistryConcurrency(t *testing.T) {
 		t.Errorf("Expected %d services, got %d", expectedCount, len(services))
 	}
 }
+
+func TestDistributedRateLimiter(t *testing.T) {
+	hub := queue.NewMemoryClusterHub()
+	cluster1 := queue.NewMemoryCluster(queue.MemoryClusterConfig{NodeID: "node-1", Hub: hub})
+	cluster2 := queue.NewMemoryCluster(queue.MemoryClusterConfig{NodeID: "node-2", Hub: hub})
+
+	const limit = 10
+	limiter1 := NewDistributedRateLimiter(cluster1, "third-party-api", limit, time.Minute, WithRateLimiterNodeID("node-1"))
+	limiter2 := NewDistributedRateLimiter(cluster2, "third-party-api", limit, time.Minute, WithRateLimiterNodeID("node-2"))
+
+	// 两个节点并发调用，总放行次数不能超过全局限制
+	var allowed int64
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, limiter := range []*DistributedRateLimiter{limiter1, limiter2} {
+		wg.Add(1)
+		go func(limiter *DistributedRateLimiter) {
+			defer wg.Done()
+			for i := 0; i < 15; i++ {
+				if limiter.Allow("call") {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}
+		}(limiter)
+	}
+
+	wg.Wait()
+
+	if allowed != limit {
+		t.Errorf("Expected %d allowed calls across the cluster, got %d", limit, allowed)
+	}
+
+	// 额度耗尽后 Wait 应在上下文结束时返回
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limiter1.Wait(ctx); err == nil {
+		t.Error("Expected Wait to fail while the limit is exhausted")
+	}
+}
+
+func TestDistributedRateLimiterWindowExpiry(t *testing.T) {
+	cluster := queue.NewMemoryCluster(queue.MemoryClusterConfig{NodeID: "node-1"})
+	limiter := NewDistributedRateLimiter(cluster, "window-expiry", 2, 100*time.Millisecond)
+
+	if !limiter.Allow("call") || !limiter.Allow("call") {
+		t.Fatal("Expected the first two calls to be allowed")
+	}
+	if limiter.Allow("call") {
+		t.Error("Expected the third call to be rejected")
+	}
+
+	// 窗口过期后槽位释放，Wait 应成功
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Errorf("Expected Wait to succeed after the window expires, got %v", err)
+	}
+}
+
+func TestDistributedRateLimiterIgnoresOwnMessages(t *testing.T) {
+	cluster := queue.NewMemoryCluster(queue.MemoryClusterConfig{NodeID: "node-1"})
+	limiter := NewDistributedRateLimiter(cluster, "own-messages", 2, time.Minute, WithRateLimiterNodeID("node-1"))
+
+	data, _ := json.Marshal(rateLimitExhausted{Key: "own-messages", RetryAfter: time.Minute})
+
+	// 自己广播的耗尽消息不影响本节点
+	limiter.handleClusterMessage(queue.ClusterMessage{Type: rateLimitExhaustedMessage, NodeID: "node-1", Data: data})
+	if !limiter.Allow("call") {
+		t.Error("Expected own exhausted message to be ignored")
+	}
+
+	// 其他节点的耗尽消息使本节点暂停竞争槽位
+	limiter.handleClusterMessage(queue.ClusterMessage{Type: rateLimitExhaustedMessage, NodeID: "node-2", Data: data})
+	if limiter.Allow("call") {
+		t.Error("Expected exhausted message from another node to pause the limiter")
+	}
+}
+
+// exhaustingCluster 第一次竞争槽位时收到其他节点耗尽消息的集群
+type exhaustingCluster struct {
+	queue.Cluster
+	limiter  *DistributedRateLimiter
+	acquires int
+}
+
+func (c *exhaustingCluster) AcquireLock(key string, ttl time.Duration) (bool, error) {
+	c.acquires++
+	if c.acquires == 1 {
+		data, _ := json.Marshal(rateLimitExhausted{Key: c.limiter.key, RetryAfter: time.Minute})
+		c.limiter.handleClusterMessage(queue.ClusterMessage{Type: rateLimitExhaustedMessage, NodeID: "node-2", Data: data})
+	}
+	return false, nil
+}
+
+func TestDistributedRateLimiterStopsWhenExhausted(t *testing.T) {
+	cluster := &exhaustingCluster{Cluster: queue.NewMemoryCluster(queue.MemoryClusterConfig{NodeID: "node-1"})}
+	limiter := NewDistributedRateLimiter(cluster, "stop-walk", 5, time.Minute, WithRateLimiterNodeID("node-1"))
+	defer limiter.Close()
+	cluster.limiter = limiter
+
+	if limiter.Allow("call") {
+		t.Fatal("Expected the call to be rejected")
+	}
+	// 额度被标记耗尽后不再尝试剩余的槽位
+	if cluster.acquires != 1 {
+		t.Errorf("Expected 1 lock attempt, got %d", cluster.acquires)
+	}
+
+	limiter.Allow("call")
+	if cluster.acquires != 1 {
+		t.Errorf("Expected no lock attempts while exhausted, got %d", cluster.acquires)
+	}
+}
+
+func TestDistributedRateLimiterClose(t *testing.T) {
+	hub := queue.NewMemoryClusterHub()
+	cluster1 := queue.NewMemoryCluster(queue.MemoryClusterConfig{NodeID: "node-1", Hub: hub})
+	cluster2 := queue.NewMemoryCluster(queue.MemoryClusterConfig{NodeID: "node-2", Hub: hub})
+
+	limiter := NewDistributedRateLimiter(cluster1, "close", 2, time.Minute, WithRateLimiterNodeID("node-1"))
+	limiter.Close()
+
+	// 关闭后其他节点的耗尽消息不再影响本节点
+	data, _ := json.Marshal(rateLimitExhausted{Key: "close", RetryAfter: time.Minute})
+	cluster2.Broadcast(queue.ClusterMessage{Type: rateLimitExhaustedMessage, NodeID: "node-2", Data: data})
+	if !limiter.Allow("call") {
+		t.Error("Expected messages received after Close to be ignored")
+	}
+}
+
+// preferLoadBalancer 总是优先选择指定实例的负载均衡器
+type preferLoadBalancer struct {
+	id string
+}
+
+func (b preferLoadBalancer) Select(services []*ServiceInfo) *ServiceInfo {
+	for _, service := range services {
+		if service.ID == b.id {
+			return service
+		}
+	}
+	return nil
+}
+
+func TestServiceClientHedging(t *testing.T) {
+	var slowCancelled sync.WaitGroup
+	slowCancelled.Add(1)
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+			w.Write([]byte("slow"))
+		case <-r.Context().Done():
+			slowCancelled.Done()
+		}
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fast"))
+	}))
+	defer fast.Close()
+
+	registry := NewMemoryServiceRegistry()
+	ctx := context.Background()
+	for id, server := range map[string]*httptest.Server{"slow": slow, "fast": fast} {
+		host, portText, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+		port, _ := strconv.Atoi(portText)
+		registry.Register(ctx, &ServiceInfo{ID: id, Name: "search-service", Address: host, Port: port, Protocol: "http", Health: "healthy"})
+	}
+	discovery := NewMemoryServiceDiscovery(registry, preferLoadBalancer{id: "slow"})
+
+	client := NewServiceClient(discovery, WithRetry(1, 0), WithHedging(20*time.Millisecond))
+	start := time.Now()
+	body, err := client.Get(ctx, "search-service", "/search?q=go")
+	if err != nil || string(body) != "fast" {
+		t.Fatalf("Expected hedged response from fast instance, got %s, %v", body, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected hedged request to finish quickly, took %s", elapsed)
+	}
+
+	// 较慢的请求会被取消
+	done := make(chan struct{})
+	go func() {
+		slowCancelled.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("Expected slow request to be cancelled")
+	}
+
+	// 没有重试次数时不发送对冲请求
+	client = NewServiceClient(discovery, WithRetry(0, 0), WithHedging(20*time.Millisecond), WithTimeout(100*time.Millisecond))
+	if _, err := client.Get(ctx, "search-service", "/search?q=go"); err == nil {
+		t.Error("Expected timeout without hedging budget")
+	}
+}
+
+func TestServiceClientDeadlinePropagation(t *testing.T) {
+	var mu sync.Mutex
+	var inventoryDeadline string
+	var inventoryCalls int
+	inventory := httptest.NewServer(DeadlineMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inventoryCalls++
+		inventoryDeadline = r.Header.Get(RequestDeadlineHeader)
+		mu.Unlock()
+		select {
+		case <-time.After(2 * time.Second):
+			w.Write([]byte("stock"))
+		case <-r.Context().Done():
+		}
+	})))
+	defer inventory.Close()
+
+	registry := NewMemoryServiceRegistry()
+	ctx := context.Background()
+	discovery := NewMemoryServiceDiscovery(registry, NewRoundRobinLoadBalancer())
+	client := NewServiceClient(discovery, WithTimeout(5*time.Second), WithRetry(2, 50*time.Millisecond))
+
+	// 中间服务使用入站请求的 context 调用下游，共享剩余的时间预算
+	orders := httptest.NewServer(DeadlineMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := client.Get(r.Context(), "inventory-service", "/stock/1")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Write(body)
+	})))
+	defer orders.Close()
+
+	for name, server := range map[string]*httptest.Server{"inventory-service": inventory, "order-service": orders} {
+		host, portText, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+		port, _ := strconv.Atoi(portText)
+		registry.Register(ctx, &ServiceInfo{ID: name, Name: name, Address: host, Port: port, Protocol: "http", Health: "healthy"})
+	}
+
+	deadlineCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	deadline, _ := deadlineCtx.Deadline()
+
+	start := time.Now()
+	if _, err := client.Get(deadlineCtx, "order-service", "/orders/1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the whole chain to abort with the inbound deadline, took %s", elapsed)
+	}
+
+	mu.Lock()
+	if inventoryCalls != 1 || inventoryDeadline != FormatRequestDeadline(deadline) {
+		t.Errorf("Expected inbound deadline %s to reach the last hop once, got %q after %d calls", FormatRequestDeadline(deadline), inventoryDeadline, inventoryCalls)
+	}
+	mu.Unlock()
+
+	// 截止时间已过时不再发送请求
+	expired, cancelExpired := context.WithDeadline(ctx, time.Now().Add(-time.Millisecond))
+	defer cancelExpired()
+	if _, err := client.Get(expired, "inventory-service", "/stock/1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected immediate deadline error, got %v", err)
+	}
+	request := httptest.NewRequest("GET", "/stock/1", nil)
+	request.Header.Set(RequestDeadlineHeader, FormatRequestDeadline(time.Now().Add(-time.Second)))
+	recorder := httptest.NewRecorder()
+	DeadlineMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected handler not to run for an expired deadline")
+	})).ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504 for expired deadline, got %d", recorder.Code)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if inventoryCalls != 1 {
+		t.Errorf("Expected no downstream call after the deadline, got %d calls", inventoryCalls)
+	}
+}
+
+func TestServiceClientBulkhead(t *testing.T) {
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	registry := NewMemoryServiceRegistry()
+	ctx := context.Background()
+	host, portText, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	port, _ := strconv.Atoi(portText)
+	registry.Register(ctx, &ServiceInfo{ID: "report-1", Name: "report-service", Address: host, Port: port, Protocol: "http", Health: "healthy"})
+
+	client := NewServiceClient(NewMemoryServiceDiscovery(registry, preferLoadBalancer{id: "report-1"}), WithRetry(0, 0))
+	client.WithBulkhead("report-service", 2)
+
+	var wg sync.WaitGroup
+	call := func() {
+		defer wg.Done()
+		if _, err := client.Get(ctx, "report-service", "/reports"); err != nil {
+			t.Errorf("Expected call within the bulkhead to succeed: %v", err)
+		}
+	}
+	wg.Add(2)
+	go call()
+	go call()
+	<-started
+	<-started
+
+	// 2 个调用进行中时第 3 个调用立即被拒绝
+	start := time.Now()
+	if _, err := client.Get(ctx, "report-service", "/reports"); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Expected ErrBulkheadFull, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected immediate rejection, took %s", elapsed)
+	}
+	stats, ok := client.BulkheadStats("report-service")
+	if !ok || stats.InFlight != 2 || stats.MaxConcurrent != 2 || stats.Rejected != 1 || stats.Utilization != 1 {
+		t.Errorf("Unexpected bulkhead stats: %+v", stats)
+	}
+
+	// 舱壁已满时使用降级响应
+	client.WithFallback("report-service", func(ctx context.Context) ([]byte, error) {
+		return []byte("cached"), nil
+	})
+	if body, err := client.Get(ctx, "report-service", "/reports"); err != nil || string(body) != "cached" {
+		t.Errorf("Expected fallback when the bulkhead is full, got %s, %v", body, err)
+	}
+
+	close(release)
+	wg.Wait()
+	if stats, _ := client.BulkheadStats("report-service"); stats.InFlight != 0 || stats.Utilization != 0 {
+		t.Errorf("Expected slots to be released, got %+v", stats)
+	}
+	if _, ok := client.BulkheadStats("other-service"); ok {
+		t.Error("Expected no bulkhead for other-service")
+	}
+
+	// 排队等待的调用在名额释放后执行
+	bulkhead := NewBulkhead(1, time.Second)
+	if err := bulkhead.Acquire(ctx); err != nil {
+		t.Fatalf("Failed to acquire slot: %v", err)
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		bulkhead.Release()
+	}()
+	if err := bulkhead.Acquire(ctx); err != nil {
+		t.Errorf("Expected queued call to acquire the released slot: %v", err)
+	}
+	short := NewBulkhead(1, 20*time.Millisecond)
+	short.Acquire(ctx)
+	if err := short.Acquire(ctx); !errors.Is(err, ErrBulkheadFull) {
+		t.Errorf("Expected ErrBulkheadFull after waiting, got %v", err)
+	}
+}
+
+func TestServiceClientRetryBudget(t *testing.T) {
+	var hits int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	registry := NewMemoryServiceRegistry()
+	ctx := context.Background()
+	host, portText, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	port, _ := strconv.Atoi(portText)
+	registry.Register(ctx, &ServiceInfo{ID: "billing-1", Name: "billing-service", Address: host, Port: port, Protocol: "http", Health: "healthy"})
+
+	client := NewServiceClient(NewMemoryServiceDiscovery(registry, preferLoadBalancer{id: "billing-1"}),
+		WithRetry(3, 0), WithRetryBudget(0.2, 5, time.Minute))
+
+	// 持续失败时，重试总数不超过 minRetries + 20% 的请求数
+	for i := 0; i < 20; i++ {
+		if _, err := client.Get(ctx, "billing-service", "/invoices"); err == nil {
+			t.Fatal("Expected failing call")
+		}
+	}
+	if total := atomic.LoadInt64(&hits); total > 20+5+4 {
+		t.Errorf("Expected retries to be bounded by the budget, got %d requests for 20 calls", total)
+	}
+
+	// 预算耗尽后不再重试
+	before := atomic.LoadInt64(&hits)
+	client.Get(ctx, "billing-service", "/invoices")
+	if delta := atomic.LoadInt64(&hits) - before; delta != 1 {
+		t.Errorf("Expected a single attempt once the budget is exhausted, got %d", delta)
+	}
+
+	state, ok := client.RetryBudgetState("billing-service")
+	if !ok || !state.Exhausted || state.Requests != 21 || state.Retries > 9 || state.Denied == 0 {
+		t.Errorf("Unexpected retry budget state: %+v", state)
+	}
+	if _, ok := client.RetryBudgetState("other-service"); ok {
+		t.Error("Expected no retry budget for a service that was never called")
+	}
+
+	// 窗口滑过后预算恢复
+	budget := client.retryBudget("billing-service")
+	budget.mu.Lock()
+	budget.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	budget.mu.Unlock()
+	if state := budget.State(); state.Exhausted || state.Requests != 0 || state.Available != 5 {
+		t.Errorf("Expected budget to recover after the window, got %+v", state)
+	}
+}
+
+func TestRegisterWithShutdown(t *testing.T) {
+	registry := NewMemoryServiceRegistry()
+	defer registry.Close()
+	discovery := NewMemoryServiceDiscovery(registry, NewRoundRobinLoadBalancer())
+	defer discovery.Close()
+
+	discovered := func() map[string]bool {
+		services, err := discovery.Discover(context.Background(), "checkout-service")
+		if err != nil {
+			t.Fatalf("Failed to discover services: %v", err)
+		}
+		ids := make(map[string]bool, len(services))
+		for _, service := range services {
+			ids[service.ID] = true
+		}
+		return ids
+	}
+	waitForDiscovery := func(expected int) map[string]bool {
+		deadline := time.Now().Add(time.Second)
+		for {
+			ids := discovered()
+			if len(ids) == expected || time.Now().After(deadline) {
+				return ids
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first, err := RegisterWithShutdown(ctx, registry, &ServiceInfo{ID: "checkout-1", Name: "checkout-service", Address: "10.0.0.1", Port: 8080})
+	if err != nil {
+		t.Fatalf("Failed to register service: %v", err)
+	}
+	second, err := RegisterWithShutdown(context.Background(), registry, &ServiceInfo{ID: "checkout-2", Name: "checkout-service", Address: "10.0.0.2", Port: 8080})
+	if err != nil {
+		t.Fatalf("Failed to register service: %v", err)
+	}
+	if ids := waitForDiscovery(2); !ids["checkout-1"] || !ids["checkout-2"] {
+		t.Fatalf("Expected both instances to be discovered, got %v", ids)
+	}
+
+	// 关闭时自动注销，发现结果中不再包含该实例
+	cancel()
+	select {
+	case <-first.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Expected instance to be deregistered on shutdown")
+	}
+	if err := first.Err(); err != nil {
+		t.Errorf("Unexpected deregister error: %v", err)
+	}
+	if ids := waitForDiscovery(1); ids["checkout-1"] || !ids["checkout-2"] {
+		t.Errorf("Expected deregistered instance to be removed from discovery, got %v", ids)
+	}
+
+	// 作为关闭钩子手动注销，多次调用只执行一次
+	if err := second.Deregister(context.Background()); err != nil {
+		t.Errorf("Unexpected deregister error: %v", err)
+	}
+	if err := second.Deregister(context.Background()); err != nil {
+		t.Errorf("Expected repeated deregister to be a no-op, got %v", err)
+	}
+	if ids := waitForDiscovery(0); len(ids) != 0 {
+		t.Errorf("Expected no instances after deregistration, got %v", ids)
+	}
+}
+
+func TestCanaryLoadBalancer(t *testing.T) {
+	services := []*ServiceInfo{
+		{ID: "v1-a", Version: "v1", Health: "healthy"},
+		{ID: "v1-b", Version: "v1", Health: "healthy"},
+		{ID: "v2-a", Metadata: map[string]string{"version": "v2"}, Health: "healthy"},
+		{ID: "v3-a", Version: "v3", Health: "unhealthy"},
+	}
+	lb := NewCanaryLoadBalancer(map[string]int{"v1": 95, "v2": 5})
+
+	distribution := func(lb LoadBalancer, services []*ServiceInfo, n int) map[string]int {
+		counts := make(map[string]int)
+		for i := 0; i < n; i++ {
+			service := lb.Select(services)
+			if service == nil {
+				t.Fatal("Expected a service to be selected")
+			}
+			counts[service.ID]++
+		}
+		return counts
+	}
+
+	// 大量选择后的分布接近配置的比例
+	counts := distribution(lb, services, 20000)
+	if canary := float64(counts["v2-a"]) / 20000; canary < 0.04 || canary > 0.06 {
+		t.Errorf("Expected about 5%% of traffic to v2, got %.3f", canary)
+	}
+	if counts["v1-a"] == 0 || counts["v1-b"] == 0 || counts["v3-a"] != 0 {
+		t.Errorf("Expected v1 traffic spread over healthy instances only, got %v", counts)
+	}
+
+	// 调整权重后按新比例分配
+	lb.SetWeights(map[string]int{"v1": 50, "v2": 50})
+	counts = distribution(lb, services, 20000)
+	if canary := float64(counts["v2-a"]) / 20000; canary < 0.47 || canary > 0.53 {
+		t.Errorf("Expected about 50%% of traffic to v2, got %.3f", canary)
+	}
+
+	// 版本没有健康实例时，流量按比例分给其余版本
+	lb.SetWeights(map[string]int{"v1": 60, "v2": 20, "v3": 20})
+	counts = distribution(lb, services, 20000)
+	if canary := float64(counts["v2-a"]) / 20000; canary < 0.22 || canary > 0.28 {
+		t.Errorf("Expected v3 traffic to be redistributed proportionally (v2 about 25%%), got %.3f", canary)
+	}
+
+	// 配置的版本都没有健康实例时使用其余健康实例
+	lb.SetWeights(map[string]int{"v3": 100})
+	if counts := distribution(lb, services, 100); counts["v3-a"] != 0 || len(counts) == 0 {
+		t.Errorf("Expected fallback to other healthy instances, got %v", counts)
+	}
+
+	// 按自定义元数据字段分流
+	tracks := []*ServiceInfo{
+		{ID: "stable", Metadata: map[string]string{"track": "stable"}, Health: "healthy"},
+		{ID: "canary", Metadata: map[string]string{"track": "canary"}, Health: "healthy"},
+	}
+	keyed := NewCanaryLoadBalancer(map[string]int{"stable": 100, "canary": 0}).WithKey("track")
+	if counts := distribution(keyed, tracks, 100); counts["canary"] != 0 {
+		t.Errorf("Expected zero-weight track to receive no traffic, got %v", counts)
+	}
+	if lb.Select(nil) != nil {
+		t.Error("Expected nil when there are no services")
+	}
+}
+
+func TestServiceDiscoveryLocalZone(t *testing.T) {
+	registry := NewMemoryServiceRegistry()
+	defer registry.Close()
+
+	ctx := context.Background()
+	services := []*ServiceInfo{
+		{ID: "local-1", Name: "user-service", Health: "healthy", Metadata: map[string]string{"zone": "us-west-1a"}},
+		{ID: "local-2", Name: "user-service", Health: "healthy", Metadata: map[string]string{"region": "us-west-1a"}},
+		{ID: "remote-1", Name: "user-service", Health: "healthy", Metadata: map[string]string{"zone": "us-east-1a"}},
+		{ID: "remote-2", Name: "user-service", Health: "healthy", Metadata: map[string]string{"zone": "us-east-1b"}},
+	}
+	for _, service := range services {
+		if err := registry.Register(ctx, service); err != nil {
+			t.Fatalf("Failed to register service: %v", err)
+		}
+	}
+
+	discovery := NewMemoryServiceDiscovery(registry, NewRandomLoadBalancer()).WithLocalZone("us-west-1a")
+	defer discovery.Close()
+
+	selectAll := func(n int) map[string]int {
+		counts := make(map[string]int)
+		for i := 0; i < n; i++ {
+			service, err := discovery.DiscoverOne(ctx, "user-service")
+			if err != nil {
+				t.Fatalf("Failed to discover service: %v", err)
+			}
+			counts[service.ID]++
+		}
+		return counts
+	}
+
+	// 本地实例健康时只选择本地实例
+	counts := selectAll(200)
+	if counts["remote-1"] != 0 || counts["remote-2"] != 0 {
+		t.Errorf("Expected only local instances to be selected, got %v", counts)
+	}
+	if counts["local-1"] == 0 || counts["local-2"] == 0 {
+		t.Errorf("Expected both local instances to be selected, got %v", counts)
+	}
+
+	// 本地健康实例少于最少数量时扩展到其他可用区
+	discovery.WithMinLocalInstances(3)
+	if counts := selectAll(200); counts["remote-1"] == 0 && counts["remote-2"] == 0 {
+		t.Errorf("Expected remote instances when local count is below minimum, got %v", counts)
+	}
+	discovery.WithMinLocalInstances(1)
+
+	// 本地实例都不健康时故障转移到其他可用区
+	for _, id := range []string{"local-1", "local-2"} {
+		service, _ := registry.GetService(ctx, id)
+		updated := *service
+		updated.Health = "unhealthy"
+		if err := registry.Update(ctx, &updated); err != nil {
+			t.Fatalf("Failed to update service: %v", err)
+		}
+	}
+	discovery.ClearCache()
+	counts = selectAll(200)
+	if counts["local-1"] != 0 || counts["local-2"] != 0 {
+		t.Errorf("Expected unhealthy local instances to be skipped, got %v", counts)
+	}
+	if counts["remote-1"] == 0 || counts["remote-2"] == 0 {
+		t.Errorf("Expected failover to remote zones, got %v", counts)
+	}
+}
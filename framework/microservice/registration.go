@@ -0,0 +1,95 @@
+package microservice
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DeregisterTimeout 收到关闭信号后注销服务的超时时间
+var DeregisterTimeout = 10 * time.Second
+
+// ServiceRegistration 已注册的服务实例，进程关闭时负责注销
+type ServiceRegistration struct {
+	registry ServiceRegistry
+	service  *ServiceInfo
+	once     sync.Once
+	done     chan struct{}
+	err      error
+	stop     func()
+}
+
+// RegisterWithShutdown 注册服务，并在收到 SIGINT/SIGTERM 或 ctx 结束时自动注销
+//
+// 服务注销后发现结果中立即不再包含该实例，不必等待 TTL 过期。进程退出前应等待 Done 返回，
+// 或者把 Deregister 注册为关闭钩子，例如 shutdownManager.Register("registry", registration.Deregister)，
+// Deregister 多次调用只执行一次。
+func RegisterWithShutdown(ctx context.Context, registry ServiceRegistry, service *ServiceInfo) (*ServiceRegistration, error) {
+	if err := registry.Register(ctx, service); err != nil {
+		return nil, err
+	}
+
+	registration := &ServiceRegistration{
+		registry: registry,
+		service:  service,
+		done:     make(chan struct{}),
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	stopped := make(chan struct{})
+	registration.stop = func() {
+		signal.Stop(signals)
+		close(stopped)
+	}
+
+	go func() {
+		select {
+		case <-signals:
+		case <-ctx.Done():
+		case <-stopped:
+			return
+		}
+
+		// ctx 可能已经结束，注销使用独立的超时时间
+		deregisterCtx, cancel := context.WithTimeout(context.Background(), DeregisterTimeout)
+		defer cancel()
+		registration.Deregister(deregisterCtx)
+	}()
+
+	return registration, nil
+}
+
+// Service 获取注册的服务信息
+func (r *ServiceRegistration) Service() *ServiceInfo {
+	return r.service
+}
+
+// Deregister 注销服务，多次调用只执行一次
+func (r *ServiceRegistration) Deregister(ctx context.Context) error {
+	r.once.Do(func() {
+		r.stop()
+		r.err = r.registry.Deregister(ctx, r.service.ID)
+		close(r.done)
+	})
+	<-r.done
+	return r.err
+}
+
+// Done 服务注销完成后关闭的通道
+func (r *ServiceRegistration) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err 获取注销结果，注销完成前返回 nil
+func (r *ServiceRegistration) Err() error {
+	select {
+	case <-r.done:
+		return r.err
+	default:
+		return nil
+	}
+}
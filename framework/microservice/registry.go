@@ -159,8 +159,11 @@ func (r *MemoryServiceRegistry) Watch(ctx context.Context) (<-chan ServiceEvent,
 	go func() {
 		<-ctx.Done()
 		r.mutex.Lock()
-		delete(r.watchers, watcherID)
-		close(eventChan)
+		// 注册中心关闭时已经关闭了所有监听通道
+		if _, exists := r.watchers[watcherID]; exists {
+			delete(r.watchers, watcherID)
+			close(eventChan)
+		}
 		r.mutex.Unlock()
 	}()
 
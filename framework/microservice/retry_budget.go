@@ -0,0 +1,168 @@
+package microservice
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudgetBuckets 重试预算滑动窗口的分桶数量
+const retryBudgetBuckets = 10
+
+// RetryBudget 重试预算，限制重试在请求总数中所占的比例
+//
+// 滑动窗口内的重试次数不超过 minRetries + ratio × 请求数时才允许重试，预算耗尽后调用直接失败
+// 而不再重试，避免下游故障时重试成倍放大负载。这与 Finagle 和 Linkerd 的重试预算相同。
+type RetryBudget struct {
+	ratio      float64
+	minRetries int
+	bucketSize time.Duration
+	buckets    [retryBudgetBuckets]retryBudgetBucket
+	denied     int64
+	now        func() time.Time
+	mu         sync.Mutex
+}
+
+// retryBudgetBucket 滑动窗口中的一个分桶
+type retryBudgetBucket struct {
+	start    time.Time
+	requests int64
+	retries  int64
+}
+
+// RetryBudgetState 重试预算的当前状态
+type RetryBudgetState struct {
+	Ratio     float64 `json:"ratio"`
+	Requests  int64   `json:"requests"`
+	Retries   int64   `json:"retries"`
+	Available int64   `json:"available"`
+	Denied    int64   `json:"denied"`
+	Exhausted bool    `json:"exhausted"`
+}
+
+// NewRetryBudget 创建重试预算
+//
+// ratio 为窗口内允许的重试比例，例如 0.2 表示重试不超过请求数的 20%；minRetries 为窗口内
+// 始终允许的重试次数，保证低流量时也能重试。
+func NewRetryBudget(ratio float64, minRetries int, window time.Duration) *RetryBudget {
+	if window <= 0 {
+		window = 10 * time.Second
+	}
+	return &RetryBudget{
+		ratio:      ratio,
+		minRetries: minRetries,
+		bucketSize: window / retryBudgetBuckets,
+		now:        time.Now,
+	}
+}
+
+// RecordRequest 记录一次请求
+func (b *RetryBudget) RecordRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current().requests++
+}
+
+// TryRetry 尝试消耗一次重试预算，预算不足时返回 false
+func (b *RetryBudget) TryRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bucket := b.current()
+	requests, retries := b.totals()
+	if retries >= b.allowed(requests) {
+		b.denied++
+		return false
+	}
+	bucket.retries++
+	return true
+}
+
+// State 获取重试预算的当前状态
+func (b *RetryBudget) State() RetryBudgetState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	requests, retries := b.totals()
+	available := b.allowed(requests) - retries
+	if available < 0 {
+		available = 0
+	}
+	return RetryBudgetState{
+		Ratio:     b.ratio,
+		Requests:  requests,
+		Retries:   retries,
+		Available: available,
+		Denied:    b.denied,
+		Exhausted: available == 0,
+	}
+}
+
+// allowed 窗口内允许的重试次数
+func (b *RetryBudget) allowed(requests int64) int64 {
+	return int64(b.minRetries) + int64(b.ratio*float64(requests))
+}
+
+// current 获取当前时间所在的分桶，过期的分桶会被清空，调用方需持有锁
+func (b *RetryBudget) current() *retryBudgetBucket {
+	now := b.now()
+	start := now.Truncate(b.bucketSize)
+	bucket := &b.buckets[(start.UnixNano()/int64(b.bucketSize))%retryBudgetBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = retryBudgetBucket{start: start}
+	}
+	return bucket
+}
+
+// totals 统计窗口内的请求数和重试数，调用方需持有锁
+func (b *RetryBudget) totals() (requests, retries int64) {
+	cutoff := b.now().Add(-b.bucketSize * retryBudgetBuckets)
+	for _, bucket := range b.buckets {
+		if bucket.start.After(cutoff) {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	return requests, retries
+}
+
+// WithRetryBudget 为每个服务启用独立的重试预算，window 为统计重试比例的滑动窗口
+func WithRetryBudget(ratio float64, minRetries int, window time.Duration) ServiceClientOption {
+	return func(c *ServiceClient) {
+		c.retryBudgetFactory = func() *RetryBudget {
+			return NewRetryBudget(ratio, minRetries, window)
+		}
+	}
+}
+
+// RetryBudgetState 获取服务重试预算的当前状态，未启用重试预算或服务还没有被调用过时返回 false
+func (c *ServiceClient) RetryBudgetState(serviceName string) (RetryBudgetState, bool) {
+	c.mu.RLock()
+	budget, exists := c.retryBudgets[serviceName]
+	c.mu.RUnlock()
+	if !exists {
+		return RetryBudgetState{}, false
+	}
+	return budget.State(), true
+}
+
+// retryBudget 获取服务的重试预算，未启用重试预算时返回 nil
+func (c *ServiceClient) retryBudget(serviceName string) *RetryBudget {
+	if c.retryBudgetFactory == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	budget, exists := c.retryBudgets[serviceName]
+	c.mu.RUnlock()
+	if exists {
+		return budget
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if budget, exists = c.retryBudgets[serviceName]; !exists {
+		budget = c.retryBudgetFactory()
+		c.retryBudgets[serviceName] = budget
+	}
+	return budget
+}
@@ -0,0 +1,67 @@
+package microservice
+
+// ZoneMetadataKey 服务元数据中表示可用区的字段，没有时使用 region 字段
+const ZoneMetadataKey = "zone"
+
+// ServiceZone 获取服务实例所在的可用区
+func ServiceZone(service *ServiceInfo) string {
+	if zone := service.Metadata[ZoneMetadataKey]; zone != "" {
+		return zone
+	}
+	return service.Metadata["region"]
+}
+
+// WithLocalZone 设置调用方所在的可用区，DiscoverOne 优先选择同一可用区的实例
+//
+// 本地健康实例不足 WithMinLocalInstances 设置的数量（默认 1）时，才在所有可用区的健康实例中
+// 选择，减少跨可用区调用的延迟和流量费用。
+func (d *MemoryServiceDiscovery) WithLocalZone(zone string) *MemoryServiceDiscovery {
+	d.zoneMutex.Lock()
+	defer d.zoneMutex.Unlock()
+	d.localZone = zone
+	return d
+}
+
+// WithMinLocalInstances 设置优先使用本地可用区所需的最少健康实例数
+func (d *MemoryServiceDiscovery) WithMinLocalInstances(count int) *MemoryServiceDiscovery {
+	if count < 1 {
+		count = 1
+	}
+
+	d.zoneMutex.Lock()
+	defer d.zoneMutex.Unlock()
+	d.minLocalInstances = count
+	return d
+}
+
+// LocalZone 获取调用方所在的可用区
+func (d *MemoryServiceDiscovery) LocalZone() string {
+	d.zoneMutex.RLock()
+	defer d.zoneMutex.RUnlock()
+	return d.localZone
+}
+
+// preferLocalZone 本地可用区的健康实例足够时只返回这些实例，否则返回全部实例
+func (d *MemoryServiceDiscovery) preferLocalZone(services []*ServiceInfo) []*ServiceInfo {
+	d.zoneMutex.RLock()
+	zone, minimum := d.localZone, d.minLocalInstances
+	d.zoneMutex.RUnlock()
+
+	if zone == "" {
+		return services
+	}
+	if minimum < 1 {
+		minimum = 1
+	}
+
+	local := make([]*ServiceInfo, 0, len(services))
+	for _, service := range services {
+		if service.Health == "healthy" && ServiceZone(service) == zone {
+			local = append(local, service)
+		}
+	}
+	if len(local) < minimum {
+		return services
+	}
+	return local
+}
@@ -0,0 +1,186 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"laravel-go/framework/mail"
+)
+
+// MailChannel 邮件通知渠道
+type MailChannel struct {
+	mailer mail.Mailer
+}
+
+// NewMailChannel 创建邮件通知渠道
+func NewMailChannel(mailer mail.Mailer) *MailChannel {
+	return &MailChannel{
+		mailer: mailer,
+	}
+}
+
+// Send 发送邮件通知，邮件未指定收件人时使用接收者的 mail 路由
+func (c *MailChannel) Send(notifiable Notifiable, notification Notification) error {
+	payload, ok := notification.(MailNotification)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingPayload, ChannelMail)
+	}
+
+	message := payload.ToMail(notifiable)
+	if message == nil {
+		return nil
+	}
+	if len(message.GetTo()) == 0 {
+		address := notifiable.RouteNotificationFor(ChannelMail)
+		if address == "" {
+			return fmt.Errorf("%w: %s", ErrMissingRoute, ChannelMail)
+		}
+		message.To(address)
+	}
+
+	return c.mailer.Send(message)
+}
+
+// SlackMessage Slack 消息
+type SlackMessage struct {
+	Text        string            `json:"text"`
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	IconEmoji   string            `json:"icon_emoji,omitempty"`
+	Attachments []SlackAttachment `json:"attachments,omitempty"`
+}
+
+// SlackAttachment Slack 消息附件
+type SlackAttachment struct {
+	Title  string       `json:"title,omitempty"`
+	Text   string       `json:"text,omitempty"`
+	Color  string       `json:"color,omitempty"`
+	Fields []SlackField `json:"fields,omitempty"`
+}
+
+// SlackField Slack 附件字段
+type SlackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short,omitempty"`
+}
+
+// SlackChannel Slack 通知渠道，通过 Incoming Webhook 发送消息
+type SlackChannel struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackChannel 创建 Slack 通知渠道，webhookURL 为接收者未设置 slack 路由时使用的默认地址
+func NewSlackChannel(webhookURL string) *SlackChannel {
+	return &SlackChannel{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SetHTTPClient 设置发送请求使用的 HTTP 客户端
+func (c *SlackChannel) SetHTTPClient(client *http.Client) {
+	c.client = client
+}
+
+// Send 发送 Slack 通知
+func (c *SlackChannel) Send(notifiable Notifiable, notification Notification) error {
+	payload, ok := notification.(SlackNotification)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingPayload, ChannelSlack)
+	}
+
+	message := payload.ToSlack(notifiable)
+	if message == nil {
+		return nil
+	}
+
+	webhookURL := notifiable.RouteNotificationFor(ChannelSlack)
+	if webhookURL == "" {
+		webhookURL = c.webhookURL
+	}
+	if webhookURL == "" {
+		return fmt.Errorf("%w: %s", ErrMissingRoute, ChannelSlack)
+	}
+
+	return c.post(webhookURL, message)
+}
+
+// post 将消息发送到 Webhook
+func (c *SlackChannel) post(webhookURL string, message *SlackMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("slack webhook returned %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
+
+// SMSSender 短信发送器，由具体的短信服务商实现
+type SMSSender interface {
+	SendSMS(to, message string) error
+}
+
+// SMSChannel 短信通知渠道
+type SMSChannel struct {
+	sender SMSSender
+}
+
+// NewSMSChannel 创建短信通知渠道
+func NewSMSChannel(sender SMSSender) *SMSChannel {
+	return &SMSChannel{
+		sender: sender,
+	}
+}
+
+// Send 发送短信通知，接收者通过 sms 路由提供手机号
+func (c *SMSChannel) Send(notifiable Notifiable, notification Notification) error {
+	payload, ok := notification.(SMSNotification)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingPayload, ChannelSMS)
+	}
+
+	to := notifiable.RouteNotificationFor(ChannelSMS)
+	if to == "" {
+		return fmt.Errorf("%w: %s", ErrMissingRoute, ChannelSMS)
+	}
+
+	return c.sender.SendSMS(to, payload.ToSMS(notifiable))
+}
+
+// DatabaseChannel 数据库通知渠道，保存通知用于站内通知列表
+type DatabaseChannel struct {
+	store Store
+}
+
+// NewDatabaseChannel 创建数据库通知渠道
+func NewDatabaseChannel(store Store) *DatabaseChannel {
+	return &DatabaseChannel{
+		store: store,
+	}
+}
+
+// Send 保存通知
+func (c *DatabaseChannel) Send(notifiable Notifiable, notification Notification) error {
+	payload, ok := notification.(DatabaseNotification)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMissingPayload, ChannelDatabase)
+	}
+
+	return c.store.Save(NewStoredNotification(notifiable, notification, payload.ToDatabase(notifiable)))
+}
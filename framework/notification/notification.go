@@ -0,0 +1,221 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"laravel-go/framework/event"
+	"laravel-go/framework/mail"
+)
+
+// 内置通知渠道名称
+const (
+	ChannelMail     = "mail"
+	ChannelSlack    = "slack"
+	ChannelSMS      = "sms"
+	ChannelDatabase = "database"
+)
+
+// 通知发送完成后分发的事件名称
+const (
+	EventNotificationSent   = "notification.sent"
+	EventNotificationFailed = "notification.failed"
+)
+
+// 通知相关错误
+var (
+	ErrChannelNotFound = errors.New("notification channel not found")
+	ErrMissingPayload  = errors.New("notification does not support channel")
+	ErrMissingRoute    = errors.New("notifiable has no route for channel")
+)
+
+// Notifiable 可接收通知的对象，例如用户
+type Notifiable interface {
+	// NotifiableID 通知接收者标识，数据库渠道按此保存通知
+	NotifiableID() string
+	// RouteNotificationFor 返回接收者在指定渠道的地址，例如邮箱、手机号或 Slack Webhook
+	RouteNotificationFor(channel string) string
+}
+
+// Notification 通知接口，Via 声明通知需要发送的渠道
+type Notification interface {
+	Via() []string
+}
+
+// MailNotification 支持邮件渠道的通知
+type MailNotification interface {
+	ToMail(notifiable Notifiable) *mail.Message
+}
+
+// SlackNotification 支持 Slack 渠道的通知
+type SlackNotification interface {
+	ToSlack(notifiable Notifiable) *SlackMessage
+}
+
+// SMSNotification 支持短信渠道的通知
+type SMSNotification interface {
+	ToSMS(notifiable Notifiable) string
+}
+
+// DatabaseNotification 支持数据库渠道的通知
+type DatabaseNotification interface {
+	ToDatabase(notifiable Notifiable) map[string]interface{}
+}
+
+// Channel 通知渠道
+type Channel interface {
+	Send(notifiable Notifiable, notification Notification) error
+}
+
+// ChannelFunc 函数形式的通知渠道
+type ChannelFunc func(notifiable Notifiable, notification Notification) error
+
+// Send 发送通知
+func (f ChannelFunc) Send(notifiable Notifiable, notification Notification) error {
+	return f(notifiable, notification)
+}
+
+// SendError 通知发送错误，记录每个失败渠道的错误
+type SendError struct {
+	Errors map[string]error
+}
+
+func (e *SendError) Error() string {
+	channels := make([]string, 0, len(e.Errors))
+	for channel := range e.Errors {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+
+	messages := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		messages = append(messages, fmt.Sprintf("%s: %v", channel, e.Errors[channel]))
+	}
+	return "notification failed on channels: " + strings.Join(messages, "; ")
+}
+
+// Notifier 通知发送器，将通知分发到其声明的所有渠道
+type Notifier struct {
+	channels   map[string]Channel
+	dispatcher event.Dispatcher
+	mu         sync.RWMutex
+}
+
+// NewNotifier 创建通知发送器
+func NewNotifier() *Notifier {
+	return &Notifier{
+		channels: make(map[string]Channel),
+	}
+}
+
+// Extend 注册通知渠道，同名渠道会被替换
+func (n *Notifier) Extend(name string, channel Channel) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.channels[name] = channel
+}
+
+// Channel 获取通知渠道
+func (n *Notifier) Channel(name string) (Channel, bool) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	channel, exists := n.channels[name]
+	return channel, exists
+}
+
+// SetDispatcher 设置事件分发器，每个渠道发送完成后分发 notification.sent 或 notification.failed 事件
+func (n *Notifier) SetDispatcher(dispatcher event.Dispatcher) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.dispatcher = dispatcher
+}
+
+// Send 将通知并发发送到所有渠道
+//
+// 单个渠道失败（包括 panic）不会影响其他渠道，所有渠道完成后
+// 返回汇总了失败渠道的 *SendError。
+func (n *Notifier) Send(notifiable Notifiable, notification Notification) error {
+	channels := notification.Via()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failures := make(map[string]error)
+
+	for _, name := range channels {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			if err := n.sendToChannel(name, notifiable, notification); err != nil {
+				mu.Lock()
+				failures[name] = err
+				mu.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return &SendError{Errors: failures}
+	}
+	return nil
+}
+
+// SendToMany 将通知发送给多个接收者，返回第一个接收者的发送错误
+func (n *Notifier) SendToMany(notifiables []Notifiable, notification Notification) error {
+	var firstErr error
+	for _, notifiable := range notifiables {
+		if err := n.Send(notifiable, notification); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// sendToChannel 通过单个渠道发送通知并分发结果事件
+func (n *Notifier) sendToChannel(name string, notifiable Notifiable, notification Notification) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("channel panicked: %v", r)
+		}
+		n.dispatch(name, notifiable, notification, err)
+	}()
+
+	channel, exists := n.Channel(name)
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrChannelNotFound, name)
+	}
+	return channel.Send(notifiable, notification)
+}
+
+// dispatch 分发通知发送结果事件
+func (n *Notifier) dispatch(channel string, notifiable Notifiable, notification Notification, err error) {
+	n.mu.RLock()
+	dispatcher := n.dispatcher
+	n.mu.RUnlock()
+	if dispatcher == nil {
+		return
+	}
+
+	name := EventNotificationSent
+	if err != nil {
+		name = EventNotificationFailed
+	}
+
+	e := event.NewEvent(name, notification)
+	e.SetData("channel", channel)
+	e.SetData("notifiable_id", notifiable.NotifiableID())
+	e.SetData("type", notificationType(notification))
+	if err != nil {
+		e.SetData("error", err.Error())
+	}
+	dispatcher.Dispatch(e)
+}
+
+// notificationType 返回通知类型名称
+func notificationType(notification Notification) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", notification), "*")
+}
@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"laravel-go/framework/event"
+	"laravel-go/framework/mail"
+)
+
+type testUser struct {
+	id    string
+	email string
+	slack string
+}
+
+func (u *testUser) NotifiableID() string {
+	return u.id
+}
+
+func (u *testUser) RouteNotificationFor(channel string) string {
+	switch channel {
+	case ChannelMail:
+		return u.email
+	case ChannelSlack:
+		return u.slack
+	}
+	return ""
+}
+
+// orderShipped 测试用通知
+type orderShipped struct {
+	orderID  string
+	channels []string
+}
+
+func (n *orderShipped) Via() []string {
+	return n.channels
+}
+
+func (n *orderShipped) ToMail(notifiable Notifiable) *mail.Message {
+	return mail.NewMessage().Subject("订单已发货").Text("订单 " + n.orderID + " 已发货")
+}
+
+func (n *orderShipped) ToSlack(notifiable Notifiable) *SlackMessage {
+	return &SlackMessage{Text: "订单 " + n.orderID + " 已发货"}
+}
+
+func (n *orderShipped) ToDatabase(notifiable Notifiable) map[string]interface{} {
+	return map[string]interface{}{"order_id": n.orderID}
+}
+
+func TestNotifierSendsToMultipleChannels(t *testing.T) {
+	mailer := mail.NewArrayMailer("shop@example.com")
+	store := NewMemoryStore()
+
+	notifier := NewNotifier()
+	notifier.Extend(ChannelMail, NewMailChannel(mailer))
+	notifier.Extend(ChannelDatabase, NewDatabaseChannel(store))
+
+	user := &testUser{id: "42", email: "alice@example.com"}
+	if err := notifier.Send(user, &orderShipped{orderID: "A100", channels: []string{ChannelMail, ChannelDatabase}}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	sent := mailer.Messages()
+	if len(sent) != 1 || sent[0].GetTo()[0] != "alice@example.com" || sent[0].GetSubject() != "订单已发货" {
+		t.Errorf("Expected mail routed to the notifiable, got %+v", sent)
+	}
+
+	stored, _ := store.ForNotifiable("42", true)
+	if len(stored) != 1 || stored[0].Data["order_id"] != "A100" || stored[0].Type != "notification.orderShipped" {
+		t.Fatalf("Expected stored notification, got %+v", stored)
+	}
+	if err := store.MarkAsRead(stored[0].ID); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if unread, _ := store.ForNotifiable("42", true); len(unread) != 0 {
+		t.Error("Expected no unread notifications")
+	}
+	if all, _ := store.ForNotifiable("42", false); len(all) != 1 || !all[0].IsRead() {
+		t.Error("Expected notification to be marked as read")
+	}
+}
+
+func TestNotifierFailingChannelDoesNotBlockOthers(t *testing.T) {
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer slack.Close()
+
+	mailer := mail.NewArrayMailer("shop@example.com")
+	store := NewMemoryStore()
+
+	dispatcher := event.NewEventDispatcher(nil)
+	defer dispatcher.Close()
+	var mu sync.Mutex
+	failed := make(map[string]bool)
+	dispatcher.Listen(EventNotificationFailed, event.NewListener("record", func(e event.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		failed[e.GetDataByKey("channel").(string)] = true
+		return nil
+	}))
+
+	notifier := NewNotifier()
+	notifier.SetDispatcher(dispatcher)
+	notifier.Extend(ChannelMail, NewMailChannel(mailer))
+	notifier.Extend(ChannelSlack, NewSlackChannel(slack.URL))
+	notifier.Extend(ChannelDatabase, NewDatabaseChannel(store))
+	notifier.Extend("broken", ChannelFunc(func(Notifiable, Notification) error {
+		panic("boom")
+	}))
+
+	user := &testUser{id: "42", email: "alice@example.com"}
+	err := notifier.Send(user, &orderShipped{orderID: "A100", channels: []string{"broken", ChannelSlack, ChannelMail, ChannelDatabase, "missing"}})
+
+	var sendErr *SendError
+	if !errors.As(err, &sendErr) {
+		t.Fatalf("Expected SendError, got: %v", err)
+	}
+	if len(sendErr.Errors) != 3 || !errors.Is(sendErr.Errors["missing"], ErrChannelNotFound) {
+		t.Errorf("Expected broken, slack and missing channels to fail, got: %v", err)
+	}
+	if len(mailer.Messages()) != 1 {
+		t.Error("Expected mail to be sent despite other channels failing")
+	}
+	if stored, _ := store.ForNotifiable("42", false); len(stored) != 1 {
+		t.Error("Expected notification to be stored despite other channels failing")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !failed["broken"] || !failed[ChannelSlack] || failed[ChannelMail] {
+		t.Errorf("Unexpected failure events: %v", failed)
+	}
+}
+
+func TestSlackChannel(t *testing.T) {
+	var received SlackMessage
+	defaultHook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected notifiable route to override the default webhook")
+	}))
+	defer defaultHook.Close()
+	userHook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer userHook.Close()
+
+	channel := NewSlackChannel(defaultHook.URL)
+	user := &testUser{id: "42", slack: userHook.URL}
+	if err := channel.Send(user, &orderShipped{orderID: "A100"}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if received.Text != "订单 A100 已发货" {
+		t.Errorf("Unexpected Slack message: %+v", received)
+	}
+}
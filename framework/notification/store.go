@@ -0,0 +1,210 @@
+package notification
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"laravel-go/framework/database"
+)
+
+// ErrNotificationNotFound 通知不存在
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// StoredNotification 保存的站内通知
+type StoredNotification struct {
+	ID           string                 `json:"id"`
+	Type         string                 `json:"type"`
+	NotifiableID string                 `json:"notifiable_id"`
+	Data         map[string]interface{} `json:"data"`
+	ReadAt       *time.Time             `json:"read_at,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+// NewStoredNotification 创建待保存的站内通知
+func NewStoredNotification(notifiable Notifiable, notification Notification, data map[string]interface{}) *StoredNotification {
+	return &StoredNotification{
+		ID:           uuid.New().String(),
+		Type:         notificationType(notification),
+		NotifiableID: notifiable.NotifiableID(),
+		Data:         data,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// IsRead 是否已读
+func (n *StoredNotification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+// Store 站内通知存储
+type Store interface {
+	// Save 保存通知
+	Save(notification *StoredNotification) error
+	// ForNotifiable 按创建时间倒序获取接收者的通知，unreadOnly 为 true 时只返回未读通知
+	ForNotifiable(notifiableID string, unreadOnly bool) ([]*StoredNotification, error)
+	// MarkAsRead 标记通知为已读
+	MarkAsRead(id string) error
+}
+
+// MemoryStore 内存通知存储，适用于开发和测试
+type MemoryStore struct {
+	notifications map[string]*StoredNotification
+	mu            sync.RWMutex
+}
+
+// NewMemoryStore 创建内存通知存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		notifications: make(map[string]*StoredNotification),
+	}
+}
+
+// Save 保存通知
+func (s *MemoryStore) Save(notification *StoredNotification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *notification
+	s.notifications[notification.ID] = &copied
+	return nil
+}
+
+// ForNotifiable 获取接收者的通知
+func (s *MemoryStore) ForNotifiable(notifiableID string, unreadOnly bool) ([]*StoredNotification, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*StoredNotification, 0)
+	for _, notification := range s.notifications {
+		if notification.NotifiableID != notifiableID || (unreadOnly && notification.IsRead()) {
+			continue
+		}
+		copied := *notification
+		result = append(result, &copied)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// MarkAsRead 标记通知为已读
+func (s *MemoryStore) MarkAsRead(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	notification, exists := s.notifications[id]
+	if !exists {
+		return ErrNotificationNotFound
+	}
+	if notification.ReadAt == nil {
+		now := time.Now()
+		notification.ReadAt = &now
+	}
+	return nil
+}
+
+// DatabaseStore 数据库通知存储
+type DatabaseStore struct {
+	connection database.Connection
+	table      string
+}
+
+// NewDatabaseStore 创建数据库通知存储，table 为空时使用 notifications 表
+func NewDatabaseStore(connection database.Connection, table string) *DatabaseStore {
+	if table == "" {
+		table = "notifications"
+	}
+	return &DatabaseStore{
+		connection: connection,
+		table:      table,
+	}
+}
+
+// CreateTable 创建通知表
+func (s *DatabaseStore) CreateTable() error {
+	_, err := s.connection.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		id VARCHAR(36) PRIMARY KEY,
+		type VARCHAR(255) NOT NULL,
+		notifiable_id VARCHAR(255) NOT NULL,
+		data TEXT NOT NULL,
+		read_at TIMESTAMP NULL,
+		created_at TIMESTAMP NOT NULL
+	)`, s.table))
+	return err
+}
+
+// Save 保存通知
+func (s *DatabaseStore) Save(notification *StoredNotification) error {
+	data, err := json.Marshal(notification.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.connection.Exec(
+		fmt.Sprintf("INSERT INTO %s (id, type, notifiable_id, data, read_at, created_at) VALUES (?, ?, ?, ?, ?, ?)", s.table),
+		notification.ID, notification.Type, notification.NotifiableID, string(data), notification.ReadAt, notification.CreatedAt,
+	)
+	return err
+}
+
+// ForNotifiable 获取接收者的通知
+func (s *DatabaseStore) ForNotifiable(notifiableID string, unreadOnly bool) ([]*StoredNotification, error) {
+	query := fmt.Sprintf("SELECT id, type, notifiable_id, data, read_at, created_at FROM %s WHERE notifiable_id = ?", s.table)
+	if unreadOnly {
+		query += " AND read_at IS NULL"
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := s.connection.Query(query, notifiableID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*StoredNotification, 0)
+	for rows.Next() {
+		var notification StoredNotification
+		var data string
+		var readAt sql.NullTime
+		if err := rows.Scan(&notification.ID, &notification.Type, &notification.NotifiableID, &data, &readAt, &notification.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(data), &notification.Data); err != nil {
+			return nil, err
+		}
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+		result = append(result, &notification)
+	}
+	return result, rows.Err()
+}
+
+// MarkAsRead 标记通知为已读
+func (s *DatabaseStore) MarkAsRead(id string) error {
+	result, err := s.connection.Exec(
+		fmt.Sprintf("UPDATE %s SET read_at = ? WHERE id = ? AND read_at IS NULL", s.table),
+		time.Now(), id,
+	)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		var exists int
+		if err := s.connection.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE id = ?", s.table), id).Scan(&exists); err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrNotificationNotFound
+		}
+	}
+	return nil
+}
@@ -101,10 +101,14 @@ func NewDatabaseMetrics(monitor Monitor) *DatabaseMetrics {
 // DatabaseMonitor 数据库监控器
 type DatabaseMonitor struct {
 	metrics            *DatabaseMetrics
-	slowQueryThreshold time.Duration
+	slowQueryThreshold int64 // time.Duration，原子读写以便运行时调整
 	mu                 sync.RWMutex
 	queryHistory       []QueryRecord
+	slowQueries        slowQueryLog
+	queryPlanner       *queryPlanner
 	maxHistorySize     int
+	querySampler       *Sampler
+	transactionSampler *Sampler
 }
 
 // QueryRecord 查询记录
@@ -121,17 +125,31 @@ type QueryRecord struct {
 func NewDatabaseMonitor(monitor Monitor, slowQueryThreshold time.Duration) *DatabaseMonitor {
 	return &DatabaseMonitor{
 		metrics:            NewDatabaseMetrics(monitor),
-		slowQueryThreshold: slowQueryThreshold,
+		slowQueryThreshold: int64(slowQueryThreshold),
 		queryHistory:       make([]QueryRecord, 0),
+		slowQueries:        slowQueryLog{size: defaultSlowQueryLogSize},
 		maxHistorySize:     1000,
+		querySampler:       NewSampler(1),
+		transactionSampler: NewSampler(1),
 	}
 }
 
+// SetSampleRate 设置采样率，可在运行时调整
+//
+// 采样率为 N 时计数器仍记录每个查询，只有 1/N 的查询记录耗时直方图和查询历史，
+// 慢查询和失败的查询始终记录到历史中。
+func (dm *DatabaseMonitor) SetSampleRate(rate int) {
+	dm.querySampler.SetRate(rate)
+	dm.transactionSampler.SetRate(rate)
+}
+
+// SampleRate 获取采样率
+func (dm *DatabaseMonitor) SampleRate() int {
+	return dm.querySampler.Rate()
+}
+
 // RecordQuery 记录查询
 func (dm *DatabaseMonitor) RecordQuery(sql string, duration time.Duration, success bool, err error) {
-	dm.mu.Lock()
-	defer dm.mu.Unlock()
-
 	// 解析查询类型
 	queryType := dm.parseQueryType(sql)
 
@@ -139,7 +157,10 @@ func (dm *DatabaseMonitor) RecordQuery(sql string, duration time.Duration, succe
 	dm.metrics.queryCounter.Increment(1)
 
 	// 记录查询时间
-	dm.metrics.queryTimeHistogram.Observe(float64(duration.Milliseconds()))
+	sampled := dm.querySampler.Sample() > 0
+	if sampled {
+		dm.metrics.queryTimeHistogram.Observe(float64(duration.Milliseconds()))
+	}
 
 	// 记录查询类型
 	switch queryType {
@@ -154,7 +175,8 @@ func (dm *DatabaseMonitor) RecordQuery(sql string, duration time.Duration, succe
 	}
 
 	// 检查慢查询
-	if duration > dm.slowQueryThreshold {
+	slow := duration > dm.SlowQueryThreshold()
+	if slow {
 		dm.metrics.slowQueryCounter.Increment(1)
 	}
 
@@ -163,6 +185,10 @@ func (dm *DatabaseMonitor) RecordQuery(sql string, duration time.Duration, succe
 		dm.metrics.errorCounter.Increment(1)
 	}
 
+	if !sampled && !slow && success {
+		return
+	}
+
 	// 添加到历史记录
 	record := QueryRecord{
 		SQL:       sql,
@@ -175,19 +201,34 @@ func (dm *DatabaseMonitor) RecordQuery(sql string, duration time.Duration, succe
 		record.Error = err.Error()
 	}
 
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
 	dm.addToHistory(record)
+
+	// 慢查询以归一化后的形式记录到慢查询日志
+	if slow {
+		normalized := NormalizeQuery(sql)
+		dm.slowQueries.add(SlowQueryEntry{
+			Query:     normalized,
+			Duration:  duration,
+			Timestamp: record.Timestamp,
+			Error:     record.Error,
+		})
+		if success && dm.queryPlanner != nil {
+			dm.queryPlanner.capture(sql, normalized)
+		}
+	}
 }
 
 // RecordTransaction 记录事务
 func (dm *DatabaseMonitor) RecordTransaction(duration time.Duration, success bool) {
-	dm.mu.Lock()
-	defer dm.mu.Unlock()
-
 	// 增加事务计数器
 	dm.metrics.transactionCounter.Increment(1)
 
 	// 记录事务时间
-	dm.metrics.transactionTimeHistogram.Observe(float64(duration.Milliseconds()))
+	if dm.transactionSampler.Sample() > 0 {
+		dm.metrics.transactionTimeHistogram.Observe(float64(duration.Milliseconds()))
+	}
 
 	// 记录错误
 	if !success {
@@ -227,7 +268,7 @@ func (dm *DatabaseMonitor) GetSlowQueries() []QueryRecord {
 
 	var slowQueries []QueryRecord
 	for _, record := range dm.queryHistory {
-		if record.Duration > dm.slowQueryThreshold {
+		if record.Duration > dm.SlowQueryThreshold() {
 			slowQueries = append(slowQueries, record)
 		}
 	}
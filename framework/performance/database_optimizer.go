@@ -2,7 +2,10 @@ package performance
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"math"
+	"strings"
 	"sync"
 	"time"
 )
@@ -28,6 +31,37 @@ type DatabaseOptimizationResult struct {
 	Metrics     map[string]interface{}   `json:"metrics"`
 	Timestamp   time.Time                `json:"timestamp"`
 	Duration    time.Duration            `json:"duration"`
+
+	// PoolSizing 连接池容量建议，只有连接池优化的结果包含
+	PoolSizing *PoolSizingRecommendation `json:"pool_sizing,omitempty"`
+}
+
+// ConnectionPool 可调整容量的连接池，*sql.DB 实现了该接口
+type ConnectionPool interface {
+	Stats() sql.DBStats
+	SetMaxOpenConns(n int)
+	SetMaxIdleConns(n int)
+}
+
+// PoolSizingAction 连接池容量调整动作
+type PoolSizingAction string
+
+const (
+	PoolSizingIncrease PoolSizingAction = "increase"
+	PoolSizingDecrease PoolSizingAction = "decrease"
+	PoolSizingKeep     PoolSizingAction = "keep"
+)
+
+// PoolSizingRecommendation 连接池容量建议
+//
+// Before 和 After 中 MaxConnections 对应最大打开连接数，MinConnections 对应最大空闲连接数。
+type PoolSizingRecommendation struct {
+	Action  PoolSizingAction     `json:"action"`
+	Before  ConnectionPoolConfig `json:"before"`
+	After   ConnectionPoolConfig `json:"after"`
+	Usage   ConnectionPoolUsage  `json:"usage"`
+	Applied bool                 `json:"applied"`
+	Reasons []string             `json:"reasons"`
 }
 
 // DatabaseOptimizer 数据库优化器
@@ -36,6 +70,14 @@ type DatabaseOptimizer struct {
 	config     *DatabaseOptimizerConfig
 	queryStats *QueryStatistics
 	mu         sync.RWMutex
+
+	// 连接池容量调整
+	pool             ConnectionPool
+	dbMonitor        *DatabaseMonitor
+	poolMaxOpen      int
+	poolMaxIdle      int
+	lastWaitCount    int64
+	lastWaitDuration time.Duration
 }
 
 // DatabaseOptimizerConfig 数据库优化器配置
@@ -61,6 +103,13 @@ type DatabaseOptimizerConfig struct {
 	ConnectionTimeout time.Duration `json:"connection_timeout"`
 	IdleTimeout       time.Duration `json:"idle_timeout"`
 
+	// 连接池容量调整的滞后区间：平均等待时间超过 PoolWaitThreshold 或使用率不低于 PoolHighUtilization 时扩容，
+	// 没有等待且使用率不高于 PoolLowUtilization 时缩容，介于两者之间时保持不变，避免来回调整。
+	// 容量在 MinConnections 和 MaxConnections 之间调整。
+	PoolWaitThreshold   time.Duration `json:"pool_wait_threshold"`
+	PoolHighUtilization float64       `json:"pool_high_utilization"`
+	PoolLowUtilization  float64       `json:"pool_low_utilization"`
+
 	// 查询缓存配置
 	QueryCacheSize int           `json:"query_cache_size"`
 	QueryCacheTTL  time.Duration `json:"query_cache_ttl"`
@@ -114,6 +163,9 @@ func NewDatabaseOptimizer(monitor Monitor) *DatabaseOptimizer {
 			MinConnections:           10,
 			ConnectionTimeout:        30 * time.Second,
 			IdleTimeout:              300 * time.Second,
+			PoolWaitThreshold:        5 * time.Millisecond,
+			PoolHighUtilization:      0.8,
+			PoolLowUtilization:       0.3,
 			QueryCacheSize:           1000,
 			QueryCacheTTL:            5 * time.Minute,
 			PartitionStrategy:        "range",
@@ -132,6 +184,22 @@ func (do *DatabaseOptimizer) SetConfig(config *DatabaseOptimizerConfig) {
 	do.config = config
 }
 
+// SetConnectionPool 设置要调整容量的连接池，例如 database.Connection 的 DB()
+func (do *DatabaseOptimizer) SetConnectionPool(pool ConnectionPool) {
+	do.mu.Lock()
+	defer do.mu.Unlock()
+	do.pool = pool
+	do.lastWaitCount = 0
+	do.lastWaitDuration = 0
+}
+
+// SetDatabaseMonitor 设置数据库监控器，用于读取查询延迟和连接数
+func (do *DatabaseOptimizer) SetDatabaseMonitor(monitor *DatabaseMonitor) {
+	do.mu.Lock()
+	defer do.mu.Unlock()
+	do.dbMonitor = monitor
+}
+
 // GetConfig 获取配置
 func (do *DatabaseOptimizer) GetConfig() *DatabaseOptimizerConfig {
 	do.mu.RLock()
@@ -266,33 +334,67 @@ func (do *DatabaseOptimizer) optimizeIndexes(ctx context.Context) (*DatabaseOpti
 }
 
 // optimizeConnectionPool 连接池优化
+//
+// 根据上次分析以来的连接等待情况、连接使用率和查询延迟给出连接池容量建议，
+// 设置了连接池时直接应用。
 func (do *DatabaseOptimizer) optimizeConnectionPool(ctx context.Context) (*DatabaseOptimizationResult, error) {
 	start := time.Now()
 
+	do.mu.Lock()
+	defer do.mu.Unlock()
+
+	if do.pool == nil && do.dbMonitor == nil {
+		return &DatabaseOptimizationResult{
+			Type:      DatabaseOptimizationTypeConnectionPool,
+			Success:   false,
+			Message:   "Connection pool optimization skipped: no connection pool or database monitor configured",
+			Metrics:   map[string]interface{}{},
+			Timestamp: time.Now(),
+			Duration:  time.Since(start),
+		}, nil
+	}
+
 	// 分析连接池使用情况
-	poolUsage := do.analyzeConnectionPoolUsage()
+	usage := do.analyzeConnectionPoolUsage()
 
 	// 优化连接池配置
-	optimizedConfig := do.optimizeConnectionPoolConfig(poolUsage)
+	recommendation := do.optimizeConnectionPoolConfig(usage)
 
 	// 应用优化配置
-	do.applyConnectionPoolConfig(optimizedConfig)
+	if recommendation.Action != PoolSizingKeep {
+		recommendation.Applied = do.applyConnectionPoolConfig(&recommendation.After)
+	}
 
-	duration := time.Since(start)
+	message := fmt.Sprintf("Connection pool: keep max open connections at %d", recommendation.Before.MaxConnections)
+	if recommendation.Action != PoolSizingKeep {
+		message = fmt.Sprintf("Connection pool: %s max open connections from %d to %d and max idle connections from %d to %d",
+			recommendation.Action, recommendation.Before.MaxConnections, recommendation.After.MaxConnections,
+			recommendation.Before.MinConnections, recommendation.After.MinConnections)
+		if !recommendation.Applied {
+			message += " (recommended, not applied)"
+		}
+	}
+	message += ": " + strings.Join(recommendation.Reasons, "; ")
 
 	return &DatabaseOptimizationResult{
-		Type:        DatabaseOptimizationTypeConnectionPool,
-		Success:     true,
-		Message:     "Connection pool optimization completed",
-		Improvement: 25.0, // 预估25%的连接性能提升
+		Type:    DatabaseOptimizationTypeConnectionPool,
+		Success: true,
+		Message: message,
 		Metrics: map[string]interface{}{
-			"max_connections":    optimizedConfig.MaxConnections,
-			"min_connections":    optimizedConfig.MinConnections,
-			"connection_timeout": optimizedConfig.ConnectionTimeout.String(),
-			"idle_timeout":       optimizedConfig.IdleTimeout.String(),
+			"action":             string(recommendation.Action),
+			"max_open_before":    recommendation.Before.MaxConnections,
+			"max_open_after":     recommendation.After.MaxConnections,
+			"max_idle_before":    recommendation.Before.MinConnections,
+			"max_idle_after":     recommendation.After.MinConnections,
+			"utilization":        usage.Utilization,
+			"wait_count":         usage.WaitCount,
+			"average_wait_time":  usage.ConnectionWaitTime.String(),
+			"average_query_time": usage.AverageQueryTime.String(),
+			"applied":            recommendation.Applied,
 		},
-		Timestamp: time.Now(),
-		Duration:  duration,
+		Timestamp:  time.Now(),
+		Duration:   time.Since(start),
+		PoolSizing: recommendation,
 	}, nil
 }
 
@@ -468,31 +570,141 @@ func (do *DatabaseOptimizer) createSuggestedIndexes(missing []*MissingIndex) []*
 	return created
 }
 
-// analyzeConnectionPoolUsage 分析连接池使用情况
+// analyzeConnectionPoolUsage 分析连接池使用情况，等待次数和等待时间为上次分析以来的增量，调用方需持有锁
 func (do *DatabaseOptimizer) analyzeConnectionPoolUsage() *ConnectionPoolUsage {
-	return &ConnectionPoolUsage{
-		ActiveConnections:  50,
-		IdleConnections:    30,
-		MaxConnections:     100,
-		ConnectionWaitTime: 100 * time.Millisecond,
-		ConnectionTimeout:  5 * time.Second,
+	if do.poolMaxOpen == 0 {
+		do.poolMaxOpen = do.config.MaxConnections
+		do.poolMaxIdle = do.config.MinConnections
+	}
+	usage := &ConnectionPoolUsage{
+		MaxConnections:    do.poolMaxOpen,
+		ConnectionTimeout: do.config.ConnectionTimeout,
 	}
+
+	if do.pool != nil {
+		stats := do.pool.Stats()
+		if stats.MaxOpenConnections > 0 {
+			do.poolMaxOpen = stats.MaxOpenConnections
+			usage.MaxConnections = stats.MaxOpenConnections
+		}
+		usage.ActiveConnections = stats.InUse
+		usage.IdleConnections = stats.Idle
+
+		usage.WaitCount = stats.WaitCount - do.lastWaitCount
+		waitDuration := stats.WaitDuration - do.lastWaitDuration
+		if usage.WaitCount > 0 {
+			usage.ConnectionWaitTime = waitDuration / time.Duration(usage.WaitCount)
+		}
+		do.lastWaitCount = stats.WaitCount
+		do.lastWaitDuration = stats.WaitDuration
+	}
+
+	if do.dbMonitor != nil {
+		metrics := do.dbMonitor.GetMetrics()
+		if active := int(metrics.activeConnections.Value().(float64)); active > usage.ActiveConnections {
+			usage.ActiveConnections = active
+		}
+		if do.pool == nil {
+			usage.IdleConnections = int(metrics.idleConnections.Value().(float64))
+		}
+
+		// 使用耗时直方图计算平均值，查询历史在采样时偏向慢查询
+		if _, sum, count := metrics.queryTimeHistogram.snapshot(); count > 0 {
+			usage.AverageQueryTime = time.Duration(sum / float64(count) * float64(time.Millisecond))
+		}
+	}
+
+	if usage.MaxConnections > 0 {
+		usage.Utilization = float64(usage.ActiveConnections) / float64(usage.MaxConnections)
+	}
+	return usage
 }
 
-// optimizeConnectionPoolConfig 优化连接池配置
-func (do *DatabaseOptimizer) optimizeConnectionPoolConfig(usage *ConnectionPoolUsage) *ConnectionPoolConfig {
-	return &ConnectionPoolConfig{
-		MaxConnections:    120,
-		MinConnections:    20,
-		ConnectionTimeout: 30 * time.Second,
-		IdleTimeout:       300 * time.Second,
+// optimizeConnectionPoolConfig 根据使用情况计算连接池容量建议，调用方需持有锁
+//
+// 扩容每次增加 50%，缩容每次减少 25%，最大空闲连接数为最大打开连接数的一半。
+func (do *DatabaseOptimizer) optimizeConnectionPoolConfig(usage *ConnectionPoolUsage) *PoolSizingRecommendation {
+	config := do.config
+	before := ConnectionPoolConfig{
+		MaxConnections:    usage.MaxConnections,
+		MinConnections:    do.poolMaxIdle,
+		ConnectionTimeout: config.ConnectionTimeout,
+		IdleTimeout:       config.IdleTimeout,
 	}
+	recommendation := &PoolSizingRecommendation{
+		Action: PoolSizingKeep,
+		Before: before,
+		After:  before,
+		Usage:  *usage,
+	}
+
+	waiting := usage.WaitCount > 0 && usage.ConnectionWaitTime >= config.PoolWaitThreshold
+	switch {
+	case waiting || usage.Utilization >= config.PoolHighUtilization:
+		if waiting {
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf("%d connection waits averaging %s exceed the %s threshold",
+				usage.WaitCount, usage.ConnectionWaitTime, config.PoolWaitThreshold))
+		} else {
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf("utilization %.0f%% is at or above %.0f%%",
+				usage.Utilization*100, config.PoolHighUtilization*100))
+		}
+		if usage.AverageQueryTime > config.QueryAnalysisThreshold {
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf("average query time %s exceeds %s, slow queries may be holding connections",
+				usage.AverageQueryTime, config.QueryAnalysisThreshold))
+		}
+
+		target := int(math.Ceil(float64(before.MaxConnections) * 1.5))
+		if target > config.MaxConnections {
+			target = config.MaxConnections
+		}
+		if target <= before.MaxConnections {
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf("already at the configured maximum of %d connections", config.MaxConnections))
+			return recommendation
+		}
+		recommendation.Action = PoolSizingIncrease
+		recommendation.After.MaxConnections = target
+
+	case usage.WaitCount == 0 && usage.Utilization <= config.PoolLowUtilization:
+		recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf("no connection waits and utilization %.0f%% is at or below %.0f%%",
+			usage.Utilization*100, config.PoolLowUtilization*100))
+
+		// 缩容后仍为当前使用的连接保留余量
+		target := int(math.Floor(float64(before.MaxConnections) * 0.75))
+		if floor := usage.ActiveConnections * 2; target < floor {
+			target = floor
+		}
+		if target < config.MinConnections {
+			target = config.MinConnections
+		}
+		if target >= before.MaxConnections {
+			recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf("already at the configured minimum of %d connections", config.MinConnections))
+			return recommendation
+		}
+		recommendation.Action = PoolSizingDecrease
+		recommendation.After.MaxConnections = target
+
+	default:
+		recommendation.Reasons = append(recommendation.Reasons, fmt.Sprintf("utilization %.0f%% and %d connection waits are within the %.0f%%-%.0f%% band",
+			usage.Utilization*100, usage.WaitCount, config.PoolLowUtilization*100, config.PoolHighUtilization*100))
+		return recommendation
+	}
+
+	idle := (recommendation.After.MaxConnections + 1) / 2
+	recommendation.After.MinConnections = idle
+	return recommendation
 }
 
-// applyConnectionPoolConfig 应用连接池配置
-func (do *DatabaseOptimizer) applyConnectionPoolConfig(config *ConnectionPoolConfig) {
-	// 模拟应用配置
-	_ = config
+// applyConnectionPoolConfig 将容量建议应用到连接池，没有设置连接池时只给出建议，调用方需持有锁
+func (do *DatabaseOptimizer) applyConnectionPoolConfig(config *ConnectionPoolConfig) bool {
+	if do.pool == nil {
+		return false
+	}
+
+	do.pool.SetMaxOpenConns(config.MaxConnections)
+	do.pool.SetMaxIdleConns(config.MinConnections)
+	do.poolMaxOpen = config.MaxConnections
+	do.poolMaxIdle = config.MinConnections
+	return true
 }
 
 // analyzeQueryCacheStats 分析查询缓存统计
@@ -617,8 +829,11 @@ type ConnectionPoolUsage struct {
 	ActiveConnections  int           `json:"active_connections"`
 	IdleConnections    int           `json:"idle_connections"`
 	MaxConnections     int           `json:"max_connections"`
-	ConnectionWaitTime time.Duration `json:"connection_wait_time"`
+	ConnectionWaitTime time.Duration `json:"connection_wait_time"` // 平均每次等待的时间
 	ConnectionTimeout  time.Duration `json:"connection_timeout"`
+	WaitCount          int64         `json:"wait_count"`
+	Utilization        float64       `json:"utilization"`
+	AverageQueryTime   time.Duration `json:"average_query_time"`
 }
 
 // ConnectionPoolConfig 连接池配置
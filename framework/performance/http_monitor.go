@@ -22,6 +22,11 @@ type HTTPMetrics struct {
 	// 请求大小和响应大小
 	requestSizeHistogram  *Histogram
 	responseSizeHistogram *Histogram
+
+	// 最近一分钟的请求和错误
+	requestWindow      *SlidingWindow
+	errorWindow        *SlidingWindow
+	requestFixedWindow *FixedWindow
 }
 
 // NewHTTPMetrics 创建HTTP指标
@@ -55,7 +60,7 @@ func NewHTTPMetrics(monitor Monitor) *HTTPMetrics {
 	activeConnections := NewGauge("http_active_connections", map[string]string{"type": "count"})
 	monitor.RegisterMetric(activeConnections)
 	
-	return &HTTPMetrics{
+	metrics := &HTTPMetrics{
 		requestCounter:        requestCounter,
 		responseCounter:       responseCounter,
 		errorCounter:          errorCounter,
@@ -63,69 +68,214 @@ func NewHTTPMetrics(monitor Monitor) *HTTPMetrics {
 		activeConnections:     activeConnections,
 		requestSizeHistogram:  requestSizeHistogram,
 		responseSizeHistogram: responseSizeHistogram,
+		requestWindow:         NewSlidingWindow(time.Minute, 60),
+		errorWindow:           NewSlidingWindow(time.Minute, 60),
+		requestFixedWindow:    NewFixedWindow(time.Minute),
+	}
+
+	// 创建时间窗口指标，读取时按当前时间计算
+	monitor.RegisterMetric(NewGaugeFunc("http_requests_per_minute", map[string]string{"window": "sliding"}, metrics.RequestsPerMinute))
+	monitor.RegisterMetric(NewGaugeFunc("http_requests_last_minute", map[string]string{"window": "fixed"}, metrics.RequestsLastMinute))
+	monitor.RegisterMetric(NewGaugeFunc("http_error_rate", map[string]string{"window": "sliding", "unit": "percent"}, metrics.ErrorRate))
+
+	return metrics
+}
+
+// RequestsPerMinute 最近一分钟（滑动窗口）的请求数
+func (m *HTTPMetrics) RequestsPerMinute() float64 {
+	return float64(m.requestWindow.Sum())
+}
+
+// RequestsLastMinute 上一个完整自然分钟（固定窗口）的请求数
+func (m *HTTPMetrics) RequestsLastMinute() float64 {
+	return float64(m.requestFixedWindow.Previous())
+}
+
+// ErrorRate 最近一分钟（滑动窗口）的错误率，单位为百分比
+func (m *HTTPMetrics) ErrorRate() float64 {
+	requests := m.requestWindow.Sum()
+	if requests == 0 {
+		return 0
 	}
+	return float64(m.errorWindow.Sum()) / float64(requests) * 100.0
+}
+
+// SetClock 设置时间窗口使用的时钟，用于测试
+func (m *HTTPMetrics) SetClock(clock func() time.Time) {
+	m.requestWindow.SetClock(clock)
+	m.errorWindow.SetClock(clock)
+	m.requestFixedWindow.SetClock(clock)
+}
+
+// recordWindow 记录一个已结束的请求
+func (m *HTTPMetrics) recordWindow() {
+	m.requestWindow.Add(1)
+	m.requestFixedWindow.Add(1)
+}
+
+// recordError 记录一次错误
+func (m *HTTPMetrics) recordError() {
+	m.errorCounter.Increment(1)
+	m.errorWindow.Add(1)
 }
 
 // HTTPMonitor HTTP监控器
 type HTTPMonitor struct {
-	metrics *HTTPMetrics
-	mu      sync.RWMutex
+	metrics      *HTTPMetrics
+	routes       map[string]*routeRecorder
+	pathTemplate func(path string) string
+	// 请求和响应分别计数采样，避免交替调用时总是采样到同一类操作
+	requestSampler  *Sampler
+	responseSampler *Sampler
+	mu              sync.RWMutex
 }
 
 // NewHTTPMonitor 创建HTTP监控器
 func NewHTTPMonitor(monitor Monitor) *HTTPMonitor {
 	return &HTTPMonitor{
 		metrics: NewHTTPMetrics(monitor),
+		routes:          make(map[string]*routeRecorder),
+		requestSampler:  NewSampler(1),
+		responseSampler: NewSampler(1),
 	}
 }
 
-// RecordRequest 记录请求
-func (hm *HTTPMonitor) RecordRequest(method, path string, size int64) {
+// SetSampleRate 设置采样率，可在运行时调整
+//
+// 采样率为 N 时计数器和时间窗口仍记录每个请求，只有 1/N 的请求记录大小和响应时间直方图，
+// 路由统计按采样权重放大请求数和错误数，百分位数使用采样的子集计算。
+func (hm *HTTPMonitor) SetSampleRate(rate int) {
+	hm.requestSampler.SetRate(rate)
+	hm.responseSampler.SetRate(rate)
+}
+
+// SampleRate 获取采样率
+func (hm *HTTPMonitor) SampleRate() int {
+	return hm.responseSampler.Rate()
+}
+
+// SetPathTemplate 设置路径模板函数，用于折叠带ID等高基数路径，例如 IDPathTemplate
+func (hm *HTTPMonitor) SetPathTemplate(template func(path string) string) {
 	hm.mu.Lock()
 	defer hm.mu.Unlock()
-	
+	hm.pathTemplate = template
+}
+
+// TemplatePath 使用路径模板函数转换路径
+func (hm *HTTPMonitor) TemplatePath(path string) string {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+	return hm.templatePath(path)
+}
+
+// templatePath 使用路径模板函数转换路径，调用方需持有锁
+func (hm *HTTPMonitor) templatePath(path string) string {
+	if hm.pathTemplate == nil {
+		return path
+	}
+	return hm.pathTemplate(path)
+}
+
+// route 获取路由记录器，不存在时创建，调用方需持有写锁
+func (hm *HTTPMonitor) route(method, path string) *routeRecorder {
+	path = hm.templatePath(path)
+	key := routeKey(method, path)
+
+	route, exists := hm.routes[key]
+	if !exists {
+		route = &routeRecorder{method: method, path: path}
+		hm.routes[key] = route
+	}
+	return route
+}
+
+// RouteStats 获取按路由（方法+路径）统计的请求数、错误数和响应时间百分位数
+func (hm *HTTPMonitor) RouteStats() map[string]RouteStat {
+	hm.mu.RLock()
+	defer hm.mu.RUnlock()
+
+	stats := make(map[string]RouteStat, len(hm.routes))
+	for key, route := range hm.routes {
+		stats[key] = route.stat()
+	}
+	return stats
+}
+
+// RecordRequest 记录请求
+func (hm *HTTPMonitor) RecordRequest(method, path string, size int64) {
 	// 增加请求计数器
 	hm.metrics.requestCounter.Increment(1)
 	
-	// 记录请求大小
-	hm.metrics.requestSizeHistogram.Observe(float64(size))
-	
 	// 增加活跃连接数
 	hm.metrics.activeConnections.Add(1)
+
+	// 记录请求大小
+	if hm.requestSampler.Sample() > 0 {
+		hm.metrics.requestSizeHistogram.Observe(float64(size))
+	}
 }
 
-// RecordResponse 记录响应
+// RecordResponse 记录响应，每个请求结束时调用一次
+//
+// 4xx/5xx 响应计入错误数，同一个请求只计一次错误。
 func (hm *HTTPMonitor) RecordResponse(method, path string, statusCode int, size int64, duration time.Duration) {
-	hm.mu.Lock()
-	defer hm.mu.Unlock()
-	
+	clientError := statusCode >= 400 && statusCode < 500
+
 	// 增加响应计数器
 	hm.metrics.responseCounter.Increment(1)
 	
+	// 减少活跃连接数
+	hm.metrics.activeConnections.Add(-1)
+
+	hm.metrics.recordWindow()
+
+	// 如果是错误响应，增加错误计数器
+	if statusCode >= 500 {
+		hm.recordError(method, path)
+	} else if clientError {
+		hm.metrics.recordError()
+	}
+
+	// 未被采样的响应不记录直方图和路由统计
+	weight := hm.responseSampler.Sample()
+	if weight == 0 {
+		return
+	}
+
 	// 记录响应时间（毫秒）
 	hm.metrics.responseTimeHistogram.Observe(float64(duration.Milliseconds()))
 	
 	// 记录响应大小
 	hm.metrics.responseSizeHistogram.Observe(float64(size))
-	
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	hm.route(method, path).observe(duration, clientError, weight)
+}
+
+// RecordError 记录以错误结束、没有响应的请求
+//
+// 与 RecordRequest 配对使用，代替 RecordResponse 结束请求：计入请求数和错误数，并减少活跃连接数。
+// 已调用 RecordResponse 的请求不要再调用，状态码 4xx/5xx 的响应已由 RecordResponse 计入错误数。
+func (hm *HTTPMonitor) RecordError(method, path string) {
 	// 减少活跃连接数
 	hm.metrics.activeConnections.Add(-1)
-	
-	// 如果是错误响应，增加错误计数器
-	if statusCode >= 400 {
-		hm.metrics.errorCounter.Increment(1)
-	}
+
+	hm.metrics.recordWindow()
+	hm.metrics.recordError()
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	hm.route(method, path).abort()
 }
 
-// RecordError 记录错误
-func (hm *HTTPMonitor) RecordError(method, path string) {
+// recordError 记录 5xx 响应的错误，请求数由 RecordResponse 计入
+func (hm *HTTPMonitor) recordError(method, path string) {
+	hm.metrics.recordError()
+
 	hm.mu.Lock()
 	defer hm.mu.Unlock()
-	
-	hm.metrics.errorCounter.Increment(1)
-	
-	// 减少活跃连接数
-	hm.metrics.activeConnections.Add(-1)
+	hm.route(method, path).fail()
 }
 
 // GetMetrics 获取指标
@@ -2,6 +2,8 @@ package performance
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -33,17 +35,20 @@ type Metric interface {
 // Counter 计数器指标
 type Counter struct {
 	name      string
+	help      string
 	value     int64
 	labels    map[string]string
 	timestamp time.Time
 	mu        sync.RWMutex
 }
 
-// NewCounter 创建计数器
-func NewCounter(name string, labels map[string]string) *Counter {
+// NewCounter 创建计数器，名称和标签名称中的非法字符会被替换为下划线
+func NewCounter(name string, labels map[string]string, options ...MetricOption) *Counter {
+	o := applyMetricOptions(options)
 	return &Counter{
-		name:      name,
-		labels:    labels,
+		name:      SanitizeMetricName(name),
+		help:      o.help,
+		labels:    sanitizeLabels(labels),
 		timestamp: time.Now(),
 	}
 }
@@ -62,6 +67,11 @@ func (c *Counter) Value() interface{} {
 	return c.value
 }
 
+// Help 获取帮助说明
+func (c *Counter) Help() string {
+	return c.help
+}
+
 func (c *Counter) Labels() map[string]string {
 	return c.labels
 }
@@ -89,17 +99,20 @@ func (c *Counter) Reset() {
 // Gauge 仪表指标
 type Gauge struct {
 	name      string
+	help      string
 	value     float64
 	labels    map[string]string
 	timestamp time.Time
 	mu        sync.RWMutex
 }
 
-// NewGauge 创建仪表
-func NewGauge(name string, labels map[string]string) *Gauge {
+// NewGauge 创建仪表，名称和标签名称中的非法字符会被替换为下划线
+func NewGauge(name string, labels map[string]string, options ...MetricOption) *Gauge {
+	o := applyMetricOptions(options)
 	return &Gauge{
-		name:      name,
-		labels:    labels,
+		name:      SanitizeMetricName(name),
+		help:      o.help,
+		labels:    sanitizeLabels(labels),
 		timestamp: time.Now(),
 	}
 }
@@ -118,6 +131,11 @@ func (g *Gauge) Value() interface{} {
 	return g.value
 }
 
+// Help 获取帮助说明
+func (g *Gauge) Help() string {
+	return g.help
+}
+
 func (g *Gauge) Labels() map[string]string {
 	return g.labels
 }
@@ -145,6 +163,7 @@ func (g *Gauge) Add(delta float64) {
 // Histogram 直方图指标
 type Histogram struct {
 	name      string
+	help      string
 	buckets   map[float64]int64
 	sum       float64
 	count     int64
@@ -153,17 +172,19 @@ type Histogram struct {
 	mu        sync.RWMutex
 }
 
-// NewHistogram 创建直方图
-func NewHistogram(name string, buckets []float64, labels map[string]string) *Histogram {
+// NewHistogram 创建直方图，名称和标签名称中的非法字符会被替换为下划线
+func NewHistogram(name string, buckets []float64, labels map[string]string, options ...MetricOption) *Histogram {
+	o := applyMetricOptions(options)
 	bucketMap := make(map[float64]int64)
 	for _, bucket := range buckets {
 		bucketMap[bucket] = 0
 	}
 	
 	return &Histogram{
-		name:      name,
+		name:      SanitizeMetricName(name),
+		help:      o.help,
 		buckets:   bucketMap,
-		labels:    labels,
+		labels:    sanitizeLabels(labels),
 		timestamp: time.Now(),
 	}
 }
@@ -187,6 +208,11 @@ func (h *Histogram) Value() interface{} {
 	}
 }
 
+// Help 获取帮助说明
+func (h *Histogram) Help() string {
+	return h.help
+}
+
 func (h *Histogram) Labels() map[string]string {
 	return h.labels
 }
@@ -211,10 +237,44 @@ func (h *Histogram) Observe(value float64) {
 	}
 }
 
+// Reset 重置直方图
+func (h *Histogram) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for bucket := range h.buckets {
+		h.buckets[bucket] = 0
+	}
+	h.sum = 0
+	h.count = 0
+	h.timestamp = time.Now()
+}
+
+// histogramBucket 直方图桶
+type histogramBucket struct {
+	upperBound float64
+	count      int64
+}
+
+// snapshot 按上界升序获取累计的桶计数、总和和观察次数
+func (h *Histogram) snapshot() ([]histogramBucket, float64, int64) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	buckets := make([]histogramBucket, 0, len(h.buckets))
+	for upperBound, count := range h.buckets {
+		buckets = append(buckets, histogramBucket{upperBound: upperBound, count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].upperBound < buckets[j].upperBound
+	})
+	return buckets, h.sum, h.count
+}
+
 // Monitor 性能监控器接口
 type Monitor interface {
 	// RegisterMetric 注册指标
-	RegisterMetric(metric Metric)
+	RegisterMetric(metric Metric) error
 	// GetMetric 获取指标
 	GetMetric(name string) Metric
 	// GetAllMetrics 获取所有指标
@@ -223,6 +283,10 @@ type Monitor interface {
 	Collect() []Metric
 	// Reset 重置所有指标
 	Reset()
+	// ResetMetric 重置单个指标
+	ResetMetric(name string) error
+	// Snapshot 获取所有指标的时间点快照
+	Snapshot() *MetricsSnapshot
 	// Start 启动监控
 	Start(ctx context.Context) error
 	// Stop 停止监控
@@ -246,10 +310,23 @@ func NewPerformanceMonitor() *PerformanceMonitor {
 }
 
 // RegisterMetric 注册指标
-func (pm *PerformanceMonitor) RegisterMetric(metric Metric) {
+//
+// 名称或标签名称不符合 Prometheus 规则时返回错误；同名指标已注册时，
+// 类型和标签名称一致则替换，否则返回 ErrMetricConflict。
+func (pm *PerformanceMonitor) RegisterMetric(metric Metric) error {
+	if err := validateMetric(metric); err != nil {
+		return err
+	}
+
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
+	if existing, ok := pm.metrics[metric.Name()]; ok {
+		if existing.Type() != metric.Type() || !sameLabelNames(existing.Labels(), metric.Labels()) {
+			return fmt.Errorf("%w: %s", ErrMetricConflict, metric.Name())
+		}
+	}
 	pm.metrics[metric.Name()] = metric
+	return nil
 }
 
 // GetMetric 获取指标
@@ -289,19 +366,49 @@ func (pm *PerformanceMonitor) Reset() {
 	defer pm.mu.Unlock()
 	
 	for _, metric := range pm.metrics {
-		switch m := metric.(type) {
-		case *Counter:
-			m.Reset()
-		case *Gauge:
-			m.Set(0)
-		case *Histogram:
-			// 重置直方图
-			for bucket := range m.buckets {
-				m.buckets[bucket] = 0
-			}
-			m.sum = 0
-			m.count = 0
-		}
+		resetMetric(metric)
+	}
+}
+
+// ResetMetric 重置单个指标，适用于需要定期清零的计数器和仪表
+func (pm *PerformanceMonitor) ResetMetric(name string) error {
+	pm.mu.RLock()
+	metric, ok := pm.metrics[name]
+	pm.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrMetricNotFound, name)
+	}
+	resetMetric(metric)
+	return nil
+}
+
+// Snapshot 获取所有指标的时间点快照，快照中的值是副本，不随指标更新变化
+func (pm *PerformanceMonitor) Snapshot() *MetricsSnapshot {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	snapshot := &MetricsSnapshot{
+		Timestamp: time.Now(),
+		Metrics:   make(map[string]MetricSample, len(pm.metrics)),
+	}
+	for name, metric := range pm.metrics {
+		snapshot.Metrics[name] = sampleMetric(metric)
+	}
+	return snapshot
+}
+
+// resetMetric 重置指标，读取时计算数值的指标无需重置
+func resetMetric(metric Metric) {
+	switch m := metric.(type) {
+	case *Counter:
+		m.Reset()
+	case *Gauge:
+		m.Set(0)
+	case *Histogram:
+		m.Reset()
+	case *Summary:
+		m.Reset()
 	}
 }
 
@@ -2,8 +2,18 @@ package performance
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"laravel-go/framework/event"
 )
 
 func TestCounter(t *testing.T) {
@@ -385,4 +395,720 @@ func BenchmarkPerformanceMonitorCollect(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		monitor.Collect()
 	}
-} 
\ No newline at end of file
+} 
+func TestHTTPMonitorWindowRates(t *testing.T) {
+	monitor := NewPerformanceMonitor()
+	httpMonitor := NewHTTPMonitor(monitor)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	httpMonitor.GetMetrics().SetClock(func() time.Time { return now })
+
+	// 第一分钟内：8 个成功请求，2 个错误请求
+	for i := 0; i < 8; i++ {
+		httpMonitor.RecordRequest("GET", "/api/users", 100)
+		httpMonitor.RecordResponse("GET", "/api/users", 200, 500, 10*time.Millisecond)
+	}
+	now = now.Add(30 * time.Second)
+	for i := 0; i < 2; i++ {
+		httpMonitor.RecordRequest("GET", "/api/users", 100)
+		httpMonitor.RecordResponse("GET", "/api/users", 500, 100, 10*time.Millisecond)
+	}
+
+	rpm := monitor.GetMetric("http_requests_per_minute")
+	errorRate := monitor.GetMetric("http_error_rate")
+	if rpm == nil || errorRate == nil {
+		t.Fatal("Expected window metrics to be registered")
+	}
+	if rpm.Value().(float64) != 10 {
+		t.Errorf("Expected 10 requests per minute, got %v", rpm.Value())
+	}
+	if errorRate.Value().(float64) != 20 {
+		t.Errorf("Expected error rate 20%%, got %v", errorRate.Value())
+	}
+
+	// 45 秒后前 8 个请求滑出窗口，只剩 2 个错误请求
+	now = now.Add(45 * time.Second)
+	if rpm.Value().(float64) != 2 {
+		t.Errorf("Expected 2 requests in sliding window, got %v", rpm.Value())
+	}
+	if errorRate.Value().(float64) != 100 {
+		t.Errorf("Expected error rate 100%%, got %v", errorRate.Value())
+	}
+
+	// 固定窗口统计上一个完整分钟
+	if last := monitor.GetMetric("http_requests_last_minute").Value().(float64); last != 10 {
+		t.Errorf("Expected 10 requests in last full minute, got %v", last)
+	}
+
+	// 窗口完全过期后错误率归零，累计计数器不受影响
+	now = now.Add(2 * time.Minute)
+	if rpm.Value().(float64) != 0 || errorRate.Value().(float64) != 0 {
+		t.Errorf("Expected empty window, got rpm=%v error_rate=%v", rpm.Value(), errorRate.Value())
+	}
+	if total := monitor.GetMetric("http_requests_total").Value().(int64); total != 10 {
+		t.Errorf("Expected 10 total requests, got %d", total)
+	}
+}
+
+func TestHTTPMonitorCountsEachRequestOnce(t *testing.T) {
+	monitor := NewPerformanceMonitor()
+	httpMonitor := NewHTTPMonitor(monitor)
+
+	// 错误响应只由 RecordResponse 计数
+	httpMonitor.RecordRequest("GET", "/orders", 100)
+	httpMonitor.RecordResponse("GET", "/orders", 500, 100, 10*time.Millisecond)
+	// 没有响应的请求由 RecordError 结束，计入请求数和错误数并释放活跃连接
+	httpMonitor.RecordRequest("GET", "/orders", 100)
+	httpMonitor.RecordError("GET", "/orders")
+	httpMonitor.RecordRequest("GET", "/orders", 100)
+	httpMonitor.RecordResponse("GET", "/orders", 200, 100, 10*time.Millisecond)
+	httpMonitor.RecordRequest("GET", "/orders", 100)
+	httpMonitor.RecordResponse("GET", "/orders", 404, 100, 10*time.Millisecond)
+
+	if rpm := monitor.GetMetric("http_requests_per_minute").Value().(float64); rpm != 4 {
+		t.Errorf("Expected 4 requests per minute, got %v", rpm)
+	}
+	if errorRate := monitor.GetMetric("http_error_rate").Value().(float64); errorRate != 75 {
+		t.Errorf("Expected error rate 75%%, got %v", errorRate)
+	}
+	if errors := monitor.GetMetric("http_errors_total").Value().(int64); errors != 3 {
+		t.Errorf("Expected 3 errors, got %d", errors)
+	}
+	if responses := monitor.GetMetric("http_responses_total").Value().(int64); responses != 3 {
+		t.Errorf("Expected 3 responses, got %d", responses)
+	}
+	if active := monitor.GetMetric("http_active_connections").Value().(float64); active != 0 {
+		t.Errorf("Expected no active connections, got %v", active)
+	}
+	if route := httpMonitor.RouteStats()["GET /orders"]; route.Count != 4 || route.ErrorCount != 3 {
+		t.Errorf("Unexpected route stats: %+v", route)
+	}
+}
+
+func TestHTTPMonitorRouteStats(t *testing.T) {
+	monitor := NewPerformanceMonitor()
+	httpMonitor := NewHTTPMonitor(monitor)
+	httpMonitor.SetPathTemplate(IDPathTemplate)
+
+	// 快速路由：1ms ~ 100ms
+	for i := 1; i <= 100; i++ {
+		httpMonitor.RecordResponse("GET", fmt.Sprintf("/users/%d", i), 200, 100, time.Duration(i)*time.Millisecond)
+	}
+	// 慢速路由：固定 1s，其中一次错误
+	for i := 0; i < 10; i++ {
+		status := 200
+		if i == 0 {
+			status = 500
+		}
+		httpMonitor.RecordResponse("POST", "/reports", status, 100, time.Second)
+	}
+
+	stats := httpMonitor.RouteStats()
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 routes after templating, got %d: %v", len(stats), stats)
+	}
+
+	users := stats["GET /users/:id"]
+	if users.Count != 100 || users.ErrorCount != 0 {
+		t.Errorf("Unexpected users stats: %+v", users)
+	}
+	if users.P50 != 50*time.Millisecond || users.P95 != 95*time.Millisecond || users.P99 != 99*time.Millisecond {
+		t.Errorf("Unexpected users percentiles: p50=%v p95=%v p99=%v", users.P50, users.P95, users.P99)
+	}
+
+	reports := stats["POST /reports"]
+	if reports.Count != 10 || reports.ErrorCount != 1 {
+		t.Errorf("Unexpected reports stats: %+v", reports)
+	}
+	if reports.P50 != time.Second || reports.P99 != time.Second {
+		t.Errorf("Unexpected reports percentiles: p50=%v p99=%v", reports.P50, reports.P99)
+	}
+
+	// 报告中包含路由延迟
+	generator := NewReportGenerator(monitor, httpMonitor, nil, nil, nil)
+	report, err := generator.GenerateReport(ReportTypeDetailed, ReportPeriod{Duration: time.Minute})
+	if err != nil {
+		t.Fatalf("Failed to generate report: %v", err)
+	}
+	slowest := report.Details.HTTPMetrics.SlowestEndpoints
+	if len(slowest) != 2 || slowest[0].Path != "/reports" {
+		t.Errorf("Expected /reports to be the slowest endpoint, got %+v", slowest)
+	}
+
+	text, err := generator.ExportReport(report, "text")
+	if err != nil {
+		t.Fatalf("Failed to export report: %v", err)
+	}
+	if !strings.Contains(string(text), "GET /users/:id") {
+		t.Errorf("Expected text report to include route latency, got:\n%s", text)
+	}
+}
+
+func TestPrometheusExporter(t *testing.T) {
+	// 名称和标签名称中的非法字符被替换
+	counter := NewCounter("http.requests-total", map[string]string{"status-code": "200"}, WithHelp("Total requests.\nAll routes."))
+	if counter.Name() != "http_requests_total" {
+		t.Errorf("Expected sanitized name http_requests_total, got %s", counter.Name())
+	}
+	if counter.Labels()["status_code"] != "200" {
+		t.Errorf("Expected sanitized label status_code, got %v", counter.Labels())
+	}
+	if name := SanitizeMetricName("5xx:errors"); name != "_5xx:errors" {
+		t.Errorf("Expected leading digit to be prefixed, got %s", name)
+	}
+	if name := SanitizeLabelName("route:name"); name != "route_name" {
+		t.Errorf("Expected colon to be replaced in label name, got %s", name)
+	}
+	if err := ValidateLabelName("__name__"); err == nil {
+		t.Error("Expected reserved label name to be rejected")
+	}
+
+	monitor := NewPerformanceMonitor()
+	if err := monitor.RegisterMetric(counter); err != nil {
+		t.Fatalf("Failed to register counter: %v", err)
+	}
+	counter.Increment(3)
+
+	histogram := NewHistogram("response_time", []float64{100, 10}, map[string]string{"unit": "ms"}, WithHelp("Response time."))
+	monitor.RegisterMetric(histogram)
+	histogram.Observe(5)
+	histogram.Observe(50)
+	histogram.Observe(500)
+
+	monitor.RegisterMetric(NewGaugeFunc("queue_depth", nil, func() float64 { return 2.5 }))
+
+	// 同名指标标签名称不同或类型不同时拒绝注册
+	conflict := NewCounter("http_requests_total", map[string]string{"method": "GET"})
+	if err := monitor.RegisterMetric(conflict); !errors.Is(err, ErrMetricConflict) {
+		t.Errorf("Expected ErrMetricConflict for different label set, got %v", err)
+	}
+	if err := monitor.RegisterMetric(NewGauge("response_time", map[string]string{"unit": "ms"})); !errors.Is(err, ErrMetricConflict) {
+		t.Errorf("Expected ErrMetricConflict for different type, got %v", err)
+	}
+	if err := monitor.RegisterMetric(NewCounter("http_requests_total", map[string]string{"status_code": "500"})); err != nil {
+		t.Errorf("Expected same label names to be accepted, got %v", err)
+	}
+	monitor.RegisterMetric(counter)
+
+	// 自定义指标的非法标签名称被拒绝
+	invalid := &GaugeFunc{name: "custom", labels: map[string]string{"bad-label": "x"}, fn: func() float64 { return 0 }}
+	if err := monitor.RegisterMetric(invalid); err == nil {
+		t.Error("Expected invalid label name to be rejected")
+	}
+
+	output := NewPrometheusExporter(monitor).Export()
+	expected := []string{
+		"# HELP http_requests_total Total requests.\\nAll routes.\n# TYPE http_requests_total counter\nhttp_requests_total{status_code=\"200\"} 3\n",
+		"# TYPE queue_depth gauge\nqueue_depth 2.5\n",
+		"# HELP response_time Response time.\n# TYPE response_time histogram\n",
+		"response_time_bucket{unit=\"ms\",le=\"10\"} 1\nresponse_time_bucket{unit=\"ms\",le=\"100\"} 2\nresponse_time_bucket{unit=\"ms\",le=\"+Inf\"} 3\n",
+		"response_time_sum{unit=\"ms\"} 555\nresponse_time_count{unit=\"ms\"} 3\n",
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("Expected export to contain %q, got:\n%s", fragment, output)
+		}
+	}
+	if strings.Contains(output, "# HELP queue_depth") {
+		t.Errorf("Expected no HELP line for metric without help, got:\n%s", output)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	objectives := map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
+	summary := NewSummary("request_latency", objectives, map[string]string{"unit": "ms"}, WithHelp("Request latency."))
+
+	now := time.Unix(1700000000, 0)
+	summary.SetClock(func() time.Time { return now })
+
+	if !math.IsNaN(summary.Quantile(0.5)) {
+		t.Errorf("Expected NaN quantile without observations, got %v", summary.Quantile(0.5))
+	}
+
+	// 1..10000 的均匀分布，乱序写入
+	const n = 10000
+	random := rand.New(rand.NewSource(1))
+	for _, i := range random.Perm(n) {
+		summary.Observe(float64(i + 1))
+	}
+
+	for q, epsilon := range objectives {
+		got := summary.Quantile(q)
+		if math.Abs(got-q*n) > epsilon*n {
+			t.Errorf("Quantile %v: expected %v within %v, got %v", q, q*n, epsilon*n, got)
+		}
+	}
+
+	value := summary.Value().(map[string]interface{})
+	if value["count"].(int64) != n || value["sum"].(float64) != n*(n+1)/2 {
+		t.Errorf("Unexpected count or sum: %v", value)
+	}
+
+	monitor := NewPerformanceMonitor()
+	if err := monitor.RegisterMetric(summary); err != nil {
+		t.Fatalf("Failed to register summary: %v", err)
+	}
+	output := NewPrometheusExporter(monitor).Export()
+	for _, fragment := range []string{
+		"# HELP request_latency Request latency.\n# TYPE request_latency summary\n",
+		"request_latency{unit=\"ms\",quantile=\"0.5\"} ",
+		"request_latency{unit=\"ms\",quantile=\"0.99\"} ",
+		"request_latency_sum{unit=\"ms\"} 5.0005e+07\nrequest_latency_count{unit=\"ms\"} 10000\n",
+	} {
+		if !strings.Contains(output, fragment) {
+			t.Errorf("Expected export to contain %q, got:\n%s", fragment, output)
+		}
+	}
+
+	// 超过时间窗口后旧的观察值不再参与分位数计算，累计值保留
+	now = now.Add(DefaultSummaryMaxAge + time.Second)
+	for i := 0; i < 100; i++ {
+		summary.Observe(1000000)
+	}
+	if got := summary.Quantile(0.5); got != 1000000 {
+		t.Errorf("Expected decayed median 1000000, got %v", got)
+	}
+	if value := summary.Value().(map[string]interface{}); value["count"].(int64) != n+100 {
+		t.Errorf("Expected cumulative count %d, got %v", n+100, value["count"])
+	}
+
+	monitor.Reset()
+	if !math.IsNaN(summary.Quantile(0.5)) {
+		t.Errorf("Expected NaN quantile after reset, got %v", summary.Quantile(0.5))
+	}
+}
+
+func TestMetricsSnapshotDiff(t *testing.T) {
+	monitor := NewPerformanceMonitor()
+	requests := NewCounter("jobs_processed_total", nil)
+	inflight := NewGauge("jobs_inflight", nil)
+	latency := NewHistogram("job_duration", []float64{10, 100}, nil)
+	monitor.RegisterMetric(requests)
+	monitor.RegisterMetric(inflight)
+	monitor.RegisterMetric(latency)
+
+	requests.Increment(10)
+	inflight.Set(4)
+	latency.Observe(5)
+
+	// 快照期间指标持续更新
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				monitor.GetMetric("jobs_inflight").(*Gauge).Add(0)
+			}
+		}
+	}()
+
+	before := monitor.Snapshot()
+	requests.Increment(30)
+	inflight.Set(1)
+	latency.Observe(50)
+	latency.Observe(150)
+	close(stop)
+	<-done
+	after := monitor.Snapshot()
+	after.Timestamp = before.Timestamp.Add(10 * time.Second)
+
+	// 快照是副本，不随指标更新变化
+	if sample, _ := before.Get("jobs_processed_total"); sample.Value != 10 {
+		t.Errorf("Expected snapshot value 10, got %v", sample.Value)
+	}
+
+	diff := after.Diff(before)
+	if diff.Duration != 10*time.Second {
+		t.Errorf("Expected diff duration 10s, got %v", diff.Duration)
+	}
+	if delta := diff.Delta("jobs_processed_total"); delta != 30 {
+		t.Errorf("Expected counter delta 30, got %v", delta)
+	}
+	if rate := diff.Rate("jobs_processed_total"); rate != 3 {
+		t.Errorf("Expected rate 3/s, got %v", rate)
+	}
+	if delta := diff.Delta("jobs_inflight"); delta != -3 {
+		t.Errorf("Expected gauge delta -3, got %v", delta)
+	}
+	if delta, _ := diff.Get("job_duration"); delta.CountDelta != 2 || delta.SumDelta != 200 {
+		t.Errorf("Expected histogram delta count 2 sum 200, got %+v", delta)
+	}
+
+	// 计数器被重置后，变化量按重置后的值计算
+	if err := monitor.ResetMetric("jobs_processed_total"); err != nil {
+		t.Fatalf("Failed to reset metric: %v", err)
+	}
+	requests.Increment(5)
+	if delta := monitor.Snapshot().Diff(after).Delta("jobs_processed_total"); delta != 5 {
+		t.Errorf("Expected delta 5 after reset, got %v", delta)
+	}
+	if err := monitor.ResetMetric("missing"); !errors.Is(err, ErrMetricNotFound) {
+		t.Errorf("Expected ErrMetricNotFound, got %v", err)
+	}
+
+	// 报告基于与上一份报告之间的变化量
+	httpMonitor := NewHTTPMonitor(monitor)
+	generator := NewReportGenerator(monitor, httpMonitor, nil, nil, nil)
+	httpMonitor.RecordRequest("GET", "/", 10)
+	httpMonitor.RecordResponse("GET", "/", 200, 10, 20*time.Millisecond)
+	first, _ := generator.GenerateReport(ReportTypeSummary, ReportPeriod{Duration: time.Minute})
+	if first.Summary.TotalRequests != 1 {
+		t.Errorf("Expected first report to count 1 request, got %d", first.Summary.TotalRequests)
+	}
+	httpMonitor.RecordRequest("GET", "/", 10)
+	httpMonitor.RecordResponse("GET", "/", 200, 10, 40*time.Millisecond)
+	httpMonitor.RecordRequest("GET", "/", 10)
+	httpMonitor.RecordResponse("GET", "/", 500, 10, 60*time.Millisecond)
+	second, _ := generator.GenerateReport(ReportTypeSummary, ReportPeriod{Duration: time.Minute})
+	if second.Summary.TotalRequests != 2 || second.Summary.ErrorRate != 50 {
+		t.Errorf("Expected second report to cover 2 requests with 50%% errors, got %+v", second.Summary)
+	}
+	if second.Summary.AverageResponseTime != 50*time.Millisecond {
+		t.Errorf("Expected average response time 50ms, got %v", second.Summary.AverageResponseTime)
+	}
+}
+
+func TestMonitorSampling(t *testing.T) {
+	monitor := NewPerformanceMonitor()
+	httpMonitor := NewHTTPMonitor(monitor)
+	httpMonitor.SetSampleRate(10)
+
+	for i := 0; i < 1000; i++ {
+		status := 200
+		if i%4 == 0 {
+			status = 500
+		}
+		httpMonitor.RecordRequest("GET", "/orders", 100)
+		httpMonitor.RecordResponse("GET", "/orders", status, 100, 20*time.Millisecond)
+	}
+
+	// 计数器记录每个请求，直方图只记录采样的子集
+	metrics := httpMonitor.GetMetrics()
+	if requests := metrics.requestCounter.Value().(int64); requests != 1000 {
+		t.Errorf("Expected 1000 requests, got %d", requests)
+	}
+	if errorCount := metrics.errorCounter.Value().(int64); errorCount != 250 {
+		t.Errorf("Expected 250 errors, got %d", errorCount)
+	}
+	if count := metrics.responseTimeHistogram.Value().(map[string]interface{})["count"].(int64); count != 100 {
+		t.Errorf("Expected 100 sampled response times, got %d", count)
+	}
+	if count := metrics.requestSizeHistogram.Value().(map[string]interface{})["count"].(int64); count != 100 {
+		t.Errorf("Expected 100 sampled request sizes, got %d", count)
+	}
+
+	// 路由统计按采样权重放大
+	route := httpMonitor.RouteStats()["GET /orders"]
+	if route.Count != 1000 || route.AverageTime != 20*time.Millisecond {
+		t.Errorf("Expected scaled route count 1000 with 20ms average, got %+v", route)
+	}
+
+	// 运行时调整采样率
+	httpMonitor.SetSampleRate(1)
+	if httpMonitor.SampleRate() != 1 {
+		t.Errorf("Expected sample rate 1, got %d", httpMonitor.SampleRate())
+	}
+	httpMonitor.RecordResponse("GET", "/orders", 200, 100, 20*time.Millisecond)
+	if count := metrics.responseTimeHistogram.Value().(map[string]interface{})["count"].(int64); count != 101 {
+		t.Errorf("Expected every response to be recorded after rate change, got %d", count)
+	}
+
+	// 数据库查询计数准确，慢查询始终进入历史记录
+	dbMonitor := NewDatabaseMonitor(monitor, 100*time.Millisecond)
+	dbMonitor.SetSampleRate(50)
+	for i := 0; i < 500; i++ {
+		dbMonitor.RecordQuery("SELECT * FROM users", time.Millisecond, true, nil)
+	}
+	dbMonitor.RecordQuery("SELECT * FROM orders", time.Second, true, nil)
+
+	dbMetrics := dbMonitor.GetMetrics()
+	if queries := dbMetrics.queryCounter.Value().(int64); queries != 501 {
+		t.Errorf("Expected 501 queries, got %d", queries)
+	}
+	if count := dbMetrics.queryTimeHistogram.Value().(map[string]interface{})["count"].(int64); count != 10 {
+		t.Errorf("Expected 10 sampled query times, got %d", count)
+	}
+	if slow := dbMonitor.GetSlowQueries(); len(slow) != 1 {
+		t.Errorf("Expected slow query to be kept in history, got %d", len(slow))
+	}
+	if history := dbMonitor.GetQueryHistory(); len(history) != 11 {
+		t.Errorf("Expected 10 sampled queries plus the slow query in history, got %d", len(history))
+	}
+}
+
+func TestMonitorServerPprof(t *testing.T) {
+	monitor := NewPerformanceMonitor()
+	monitor.RegisterMetric(NewCounter("jobs_total", nil))
+
+	get := func(handler http.Handler, path, token string) int {
+		req := httptest.NewRequest("GET", path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// 默认关闭
+	disabled := NewMonitorServer(monitor, MonitorServerConfig{}).Handler()
+	if code := get(disabled, "/metrics", ""); code != http.StatusOK {
+		t.Errorf("Expected /metrics to be served, got %d", code)
+	}
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/allocs"} {
+		if code := get(disabled, path, ""); code != http.StatusNotFound {
+			t.Errorf("Expected %s to be absent when pprof is disabled, got %d", path, code)
+		}
+	}
+
+	enabled := NewMonitorServer(monitor, MonitorServerConfig{
+		EnablePprof: true,
+		PprofGuard:  PprofTokenGuard("secret"),
+	}).Handler()
+	for _, path := range []string{"/debug/pprof/", "/debug/pprof/allocs?debug=1", "/debug/pprof/goroutine?debug=1"} {
+		if code := get(enabled, path, ""); code != http.StatusForbidden {
+			t.Errorf("Expected %s to require auth, got %d", path, code)
+		}
+		if code := get(enabled, path, "secret"); code != http.StatusOK {
+			t.Errorf("Expected %s to be served with token, got %d", path, code)
+		}
+	}
+	if code := get(enabled, "/status", ""); code != http.StatusOK {
+		t.Errorf("Expected /status to be served without auth, got %d", code)
+	}
+}
+
+// fakeConnectionPool 可设置统计信息的连接池
+type fakeConnectionPool struct {
+	stats   sql.DBStats
+	maxIdle int
+}
+
+func (p *fakeConnectionPool) Stats() sql.DBStats { return p.stats }
+
+func (p *fakeConnectionPool) SetMaxOpenConns(n int) { p.stats.MaxOpenConnections = n }
+
+func (p *fakeConnectionPool) SetMaxIdleConns(n int) { p.maxIdle = n }
+
+func TestDatabaseOptimizerPoolSizing(t *testing.T) {
+	optimizer := NewDatabaseOptimizer(NewPerformanceMonitor())
+	config := optimizer.GetConfig()
+	config.MaxConnections = 100
+	config.MinConnections = 5
+	pool := &fakeConnectionPool{stats: sql.DBStats{MaxOpenConnections: 20, InUse: 20, WaitCount: 50, WaitDuration: 2500 * time.Millisecond}}
+	optimizer.SetConnectionPool(pool)
+
+	optimizePool := func() *PoolSizingRecommendation {
+		result, err := optimizer.optimizeConnectionPool(context.Background())
+		if err != nil || !result.Success {
+			t.Fatalf("Expected pool optimization to succeed, got %+v, %v", result, err)
+		}
+		return result.PoolSizing
+	}
+
+	// 等待时间过长时扩容
+	recommendation := optimizePool()
+	if recommendation.Action != PoolSizingIncrease || recommendation.Before.MaxConnections != 20 || recommendation.After.MaxConnections != 30 {
+		t.Fatalf("Expected increase from 20 to 30, got %+v", recommendation)
+	}
+	if !recommendation.Applied || pool.stats.MaxOpenConnections != 30 || pool.maxIdle != 15 {
+		t.Errorf("Expected new limits to be applied, got max open %d idle %d", pool.stats.MaxOpenConnections, pool.maxIdle)
+	}
+	if recommendation.Usage.ConnectionWaitTime != 50*time.Millisecond || len(recommendation.Reasons) == 0 {
+		t.Errorf("Expected average wait 50ms with reasons, got %+v", recommendation)
+	}
+
+	// 使用率处于滞后区间且没有新的等待时保持不变
+	pool.stats.InUse = 15
+	if recommendation := optimizePool(); recommendation.Action != PoolSizingKeep || pool.stats.MaxOpenConnections != 30 {
+		t.Errorf("Expected pool size to be kept within the band, got %+v", recommendation)
+	}
+
+	// 使用率低且没有等待时缩容
+	pool.stats.InUse = 2
+	recommendation = optimizePool()
+	if recommendation.Action != PoolSizingDecrease || recommendation.After.MaxConnections != 22 || pool.stats.MaxOpenConnections != 22 {
+		t.Errorf("Expected decrease from 30 to 22, got %+v", recommendation)
+	}
+
+	// 只有监控器时只给出建议
+	monitor := NewPerformanceMonitor()
+	dbMonitor := NewDatabaseMonitor(monitor, time.Second)
+	dbMonitor.UpdateConnectionPool(95, 5, 100)
+	advisor := NewDatabaseOptimizer(monitor)
+	advisor.GetConfig().MaxConnections = 100
+	advisor.SetDatabaseMonitor(dbMonitor)
+	if recommendation := func() *PoolSizingRecommendation {
+		result, _ := advisor.optimizeConnectionPool(context.Background())
+		return result.PoolSizing
+	}(); recommendation.Action != PoolSizingKeep || len(recommendation.Reasons) != 2 {
+		t.Errorf("Expected keep at the configured maximum, got %+v", recommendation)
+	}
+}
+
+func TestDatabaseSlowQueryLog(t *testing.T) {
+	monitor := NewPerformanceMonitor()
+	dbMonitor := NewDatabaseMonitor(monitor, 100*time.Millisecond)
+
+	dbMonitor.RecordQuery("SELECT * FROM users WHERE id = 1", 10*time.Millisecond, true, nil)
+	dbMonitor.RecordQuery("SELECT * FROM users WHERE id = 1", 150*time.Millisecond, true, nil)
+	if entries := dbMonitor.SlowQueries(0); len(entries) != 1 || entries[0].Duration != 150*time.Millisecond {
+		t.Fatalf("Expected only the slow query to be captured, got %+v", entries)
+	}
+
+	// 同一模式的查询归一化后聚合在一起
+	dbMonitor.RecordQuery("SELECT *  FROM users WHERE id = 2", 250*time.Millisecond, true, nil)
+	dbMonitor.RecordQuery("SELECT * FROM users WHERE id = $1", 200*time.Millisecond, true, nil)
+	dbMonitor.RecordQuery("SELECT * FROM orders WHERE status IN ('paid', 'shipped')", 120*time.Millisecond, true, nil)
+
+	stats := dbMonitor.SlowQueryStats(0)
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 query patterns, got %+v", stats)
+	}
+	if stats[0].Query != "SELECT * FROM users WHERE id = ?" || stats[0].Count != 3 || stats[0].MaxDuration != 250*time.Millisecond || stats[0].AverageDuration != 200*time.Millisecond {
+		t.Errorf("Unexpected aggregated stat: %+v", stats[0])
+	}
+	if stats[1].Query != "SELECT * FROM orders WHERE status IN (?)" {
+		t.Errorf("Expected IN list to be collapsed, got %q", stats[1].Query)
+	}
+
+	// 阈值可以在运行时调整
+	dbMonitor.SetSlowQueryThreshold(time.Second)
+	dbMonitor.RecordQuery("SELECT 1", 500*time.Millisecond, true, nil)
+	if entries := dbMonitor.SlowQueries(0); len(entries) != 4 {
+		t.Errorf("Expected query under the new threshold to be skipped, got %d entries", len(entries))
+	}
+
+	// 日志容量有限，保留最近的条目
+	dbMonitor.SetSlowQueryLogSize(2)
+	dbMonitor.RecordQuery("DELETE FROM sessions WHERE id = 9", 2*time.Second, true, nil)
+	entries := dbMonitor.SlowQueries(0)
+	if len(entries) != 2 || entries[0].Query != "DELETE FROM sessions WHERE id = ?" || entries[1].Duration != 120*time.Millisecond {
+		t.Errorf("Expected the two most recent entries, got %+v", entries)
+	}
+	if entries := dbMonitor.SlowQueries(1); len(entries) != 1 {
+		t.Errorf("Expected limit to be applied, got %d entries", len(entries))
+	}
+
+	// 报告中给出慢查询优化建议
+	generator := NewReportGenerator(monitor, nil, dbMonitor, nil, nil)
+	report, err := generator.GenerateReport(ReportTypeSummary, ReportPeriod{})
+	if err != nil {
+		t.Fatalf("Failed to generate report: %v", err)
+	}
+	found := false
+	for _, recommendation := range report.Recommendations {
+		if recommendation.Title == "优化慢查询" {
+			found = len(recommendation.Actions) == 3
+		}
+	}
+	if !found {
+		t.Errorf("Expected slow query recommendation, got %+v", report.Recommendations)
+	}
+}
+
+func TestScalingAdvisor(t *testing.T) {
+	monitor := NewPerformanceMonitor()
+	cpu := NewGauge("cpu_usage", nil)
+	queueDepth := 0.0
+	monitor.RegisterMetric(cpu)
+	monitor.RegisterMetric(NewGaugeFunc("queue_depth", nil, func() float64 { return queueDepth }))
+
+	advisor := NewScalingAdvisor(monitor, ScalingConfig{
+		Policies: []ScalingPolicy{
+			{Metric: "cpu_usage", ScaleUpThreshold: 80, ScaleDownThreshold: 20},
+			{Metric: "queue_depth", ScaleUpThreshold: 1000, ScaleDownThreshold: 10},
+		},
+		Sustain:     3,
+		Cooldown:    5 * time.Minute,
+		MinReplicas: 2,
+		MaxReplicas: 4,
+	})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	advisor.now = func() time.Time { return now }
+
+	dispatcher := event.NewEventDispatcher(event.NewMemoryEventQueue())
+	defer dispatcher.Close()
+	var events []string
+	dispatcher.Listen(ScalingRecommendedEvent, event.NewListener("record", func(e event.Event) error {
+		events = append(events, e.GetDataByKey("direction").(string))
+		return nil
+	}))
+	advisor.SetDispatcher(dispatcher)
+
+	var scaled []*ScalingRecommendation
+	advisor.SetScaler(ScalerFunc(func(r *ScalingRecommendation) error {
+		scaled = append(scaled, r)
+		return nil
+	}))
+
+	// 短暂的高负载不触发扩容
+	cpu.Set(95)
+	advisor.Evaluate()
+	cpu.Set(50)
+	if r := advisor.Evaluate(); r != nil {
+		t.Fatalf("Expected no recommendation for a short spike, got %+v", r)
+	}
+
+	// 持续高负载触发扩容
+	cpu.Set(95)
+	var recommendation *ScalingRecommendation
+	for i := 0; i < 3; i++ {
+		recommendation = advisor.Evaluate()
+		now = now.Add(30 * time.Second)
+	}
+	if recommendation == nil || recommendation.Direction != ScaleUp || recommendation.Current != 2 || recommendation.Desired != 3 {
+		t.Fatalf("Expected scale-up recommendation, got %+v", recommendation)
+	}
+	if !recommendation.Applied || advisor.Replicas() != 3 || len(scaled) != 1 {
+		t.Errorf("Expected scaler to apply the recommendation, got %+v", recommendation)
+	}
+	if recommendation.Metrics["cpu_usage"] != 95 || !strings.Contains(recommendation.Reason, "cpu_usage") {
+		t.Errorf("Unexpected recommendation details: %+v", recommendation)
+	}
+
+	// 冷却期内不重复建议
+	for i := 0; i < 6; i++ {
+		if r := advisor.Evaluate(); r != nil {
+			t.Fatalf("Expected cooldown to suppress recommendation, got %+v", r)
+		}
+		now = now.Add(30 * time.Second)
+	}
+
+	// 冷却期内条件持续满足，冷却期结束后立即扩容，队列积压同样触发扩容
+	now = now.Add(5 * time.Minute)
+	cpu.Set(50)
+	queueDepth = 5000
+	recommendation = advisor.Evaluate()
+	if recommendation == nil || recommendation.Desired != 4 || !strings.Contains(recommendation.Reason, "queue_depth") {
+		t.Fatalf("Expected second scale-up for queue depth, got %+v", recommendation)
+	}
+
+	// 达到最大副本数后不再扩容
+	now = now.Add(10 * time.Minute)
+	for i := 0; i < 5; i++ {
+		if r := advisor.Evaluate(); r != nil {
+			t.Fatalf("Expected no recommendation at max replicas, got %+v", r)
+		}
+	}
+
+	// 所有指标都低时缩容
+	cpu.Set(5)
+	queueDepth = 0
+	for i := 0; i < 3; i++ {
+		recommendation = advisor.Evaluate()
+	}
+	if recommendation == nil || recommendation.Direction != ScaleDown || recommendation.Desired != 3 {
+		t.Fatalf("Expected scale-down recommendation, got %+v", recommendation)
+	}
+	if len(advisor.Recommendations()) != 3 {
+		t.Errorf("Expected 3 recommendations in history, got %d", len(advisor.Recommendations()))
+	}
+	if strings.Join(events, ",") != "scale_up,scale_up,scale_down" {
+		t.Errorf("Expected recommendations to be dispatched as events, got %v", events)
+	}
+}
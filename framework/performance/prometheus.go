@@ -0,0 +1,279 @@
+package performance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrMetricConflict 同名指标的类型或标签集合与已注册的指标不一致
+var ErrMetricConflict = errors.New("metric already registered with a different type or label set")
+
+// MetricOption 指标选项
+type MetricOption func(*metricOptions)
+
+// metricOptions 指标的可选配置
+type metricOptions struct {
+	help       string
+	maxAge     time.Duration
+	ageBuckets int
+}
+
+// WithHelp 设置指标的帮助说明，导出为 Prometheus 的 # HELP 行
+func WithHelp(help string) MetricOption {
+	return func(o *metricOptions) {
+		o.help = help
+	}
+}
+
+// applyMetricOptions 应用指标选项
+func applyMetricOptions(options []MetricOption) metricOptions {
+	var o metricOptions
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}
+
+// HelpProvider 提供帮助说明的指标
+type HelpProvider interface {
+	Help() string
+}
+
+// SanitizeMetricName 将指标名称转换为符合 Prometheus 规则的名称
+//
+// 名称只能包含字母、数字、下划线和冒号且不能以数字开头，其他字符替换为下划线，
+// 以数字开头时添加下划线前缀。
+func SanitizeMetricName(name string) string {
+	return sanitizeName(name, true)
+}
+
+// SanitizeLabelName 将标签名称转换为符合 Prometheus 规则的名称
+//
+// 标签名称只能包含字母、数字和下划线且不能以数字开头。
+func SanitizeLabelName(name string) string {
+	return sanitizeName(name, false)
+}
+
+// sanitizeName 替换名称中的非法字符
+func sanitizeName(name string, allowColon bool) string {
+	if name == "" {
+		return "_"
+	}
+
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case i == 0 && r >= '0' && r <= '9':
+			b.WriteByte('_')
+			b.WriteRune(r)
+		case validNameChar(r, allowColon):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// validNameChar 是否为名称允许的字符
+func validNameChar(r rune, allowColon bool) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || allowColon && r == ':'
+}
+
+// ValidateMetricName 校验指标名称是否符合 Prometheus 规则
+func ValidateMetricName(name string) error {
+	if name == "" || SanitizeMetricName(name) != name {
+		return fmt.Errorf("invalid metric name %q", name)
+	}
+	return nil
+}
+
+// ValidateLabelName 校验标签名称是否符合 Prometheus 规则，双下划线开头的名称为保留名称
+func ValidateLabelName(name string) error {
+	if name == "" || SanitizeLabelName(name) != name {
+		return fmt.Errorf("invalid label name %q", name)
+	}
+	if strings.HasPrefix(name, "__") {
+		return fmt.Errorf("label name %q is reserved", name)
+	}
+	return nil
+}
+
+// sanitizeLabels 复制标签并替换标签名称中的非法字符
+func sanitizeLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+
+	result := make(map[string]string, len(labels))
+	for name, value := range labels {
+		result[SanitizeLabelName(name)] = value
+	}
+	return result
+}
+
+// validateMetric 校验指标名称和标签名称，直方图和摘要不能使用保留的 le 和 quantile 标签
+func validateMetric(metric Metric) error {
+	if err := ValidateMetricName(metric.Name()); err != nil {
+		return err
+	}
+	for name := range metric.Labels() {
+		if err := ValidateLabelName(name); err != nil {
+			return fmt.Errorf("metric %s: %w", metric.Name(), err)
+		}
+		if name == "le" && metric.Type() == MetricTypeHistogram {
+			return fmt.Errorf("metric %s: label name \"le\" is reserved for histograms", metric.Name())
+		}
+		if name == "quantile" && metric.Type() == MetricTypeSummary {
+			return fmt.Errorf("metric %s: label name \"quantile\" is reserved for summaries", metric.Name())
+		}
+	}
+	return nil
+}
+
+// sameLabelNames 两个标签集合的名称是否一致
+func sameLabelNames(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// PrometheusExporter 将监控器中的指标导出为 Prometheus 文本格式
+type PrometheusExporter struct {
+	monitor Monitor
+}
+
+// NewPrometheusExporter 创建 Prometheus 导出器
+func NewPrometheusExporter(monitor Monitor) *PrometheusExporter {
+	return &PrometheusExporter{monitor: monitor}
+}
+
+// Export 按名称排序导出所有指标
+func (e *PrometheusExporter) Export() string {
+	metrics := e.monitor.Collect()
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name() < metrics[j].Name()
+	})
+
+	var b strings.Builder
+	for _, metric := range metrics {
+		writePrometheusMetric(&b, metric)
+	}
+	return b.String()
+}
+
+// ServeHTTP 以 Prometheus 文本格式响应
+func (e *PrometheusExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(e.Export()))
+}
+
+// writePrometheusMetric 写入单个指标
+func writePrometheusMetric(b *strings.Builder, metric Metric) {
+	name := metric.Name()
+	if provider, ok := metric.(HelpProvider); ok && provider.Help() != "" {
+		fmt.Fprintf(b, "# HELP %s %s\n", name, escapeHelp(provider.Help()))
+	}
+
+	switch metric.Type() {
+	case MetricTypeHistogram:
+		fmt.Fprintf(b, "# TYPE %s histogram\n", name)
+		histogram, ok := metric.(*Histogram)
+		if !ok {
+			return
+		}
+		buckets, sum, count := histogram.snapshot()
+		for _, bucket := range buckets {
+			fmt.Fprintf(b, "%s_bucket%s %d\n", name, formatLabels(metric.Labels(), "le", formatFloat(bucket.upperBound)), bucket.count)
+		}
+		fmt.Fprintf(b, "%s_bucket%s %d\n", name, formatLabels(metric.Labels(), "le", "+Inf"), count)
+		fmt.Fprintf(b, "%s_sum%s %s\n", name, formatLabels(metric.Labels()), formatFloat(sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", name, formatLabels(metric.Labels()), count)
+	case MetricTypeSummary:
+		fmt.Fprintf(b, "# TYPE %s summary\n", name)
+		summary, ok := metric.(*Summary)
+		if !ok {
+			return
+		}
+		quantiles, sum, count := summary.snapshot()
+		for _, q := range summary.Objectives() {
+			fmt.Fprintf(b, "%s%s %s\n", name, formatLabels(metric.Labels(), "quantile", formatFloat(q)), formatFloat(quantiles[q]))
+		}
+		fmt.Fprintf(b, "%s_sum%s %s\n", name, formatLabels(metric.Labels()), formatFloat(sum))
+		fmt.Fprintf(b, "%s_count%s %d\n", name, formatLabels(metric.Labels()), count)
+	default:
+		fmt.Fprintf(b, "# TYPE %s %s\n", name, metric.Type())
+		fmt.Fprintf(b, "%s%s %s\n", name, formatLabels(metric.Labels()), formatValue(metric.Value()))
+	}
+}
+
+// formatLabels 格式化标签，extra 为追加的名称和值
+func formatLabels(labels map[string]string, extra ...string) string {
+	if len(labels) == 0 && len(extra) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names)+len(extra)/2)
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", name, escapeLabelValue(labels[name])))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extra[i], escapeLabelValue(extra[i+1])))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue 格式化指标值
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return formatFloat(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// formatFloat 格式化浮点数，无穷大和 NaN 使用 Prometheus 的表示方式
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escapeHelp 转义帮助说明中的反斜杠和换行
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// escapeLabelValue 转义标签值中的反斜杠、双引号和换行
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
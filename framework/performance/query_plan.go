@@ -0,0 +1,295 @@
+package performance
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExplainDialect EXPLAIN 语法方言
+type ExplainDialect string
+
+const (
+	// ExplainMySQL MySQL：EXPLAIN <query>，type 列为 ALL 表示全表扫描
+	ExplainMySQL ExplainDialect = "mysql"
+	// ExplainPostgres PostgreSQL：EXPLAIN <query>，计划中出现 Seq Scan 表示全表扫描
+	ExplainPostgres ExplainDialect = "postgres"
+	// ExplainSQLite SQLite：EXPLAIN QUERY PLAN <query>，detail 以 SCAN 开头表示全表扫描
+	ExplainSQLite ExplainDialect = "sqlite"
+)
+
+// QueryPlanQueryer 执行 EXPLAIN 的数据库句柄，*sql.DB 和 *sql.Conn 实现了该接口
+type QueryPlanQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// QueryPlanConfig 慢查询执行计划采集配置
+type QueryPlanConfig struct {
+	// DB 执行 EXPLAIN 的数据库句柄
+	DB QueryPlanQueryer
+	// Dialect EXPLAIN 语法方言
+	Dialect ExplainDialect
+	// MaxPerMinute 每分钟最多执行的 EXPLAIN 次数，默认 10
+	MaxPerMinute int
+	// MaxPlans 最多保留的执行计划数量，默认 100
+	MaxPlans int
+	// Timeout 单次 EXPLAIN 的超时时间，默认 5 秒
+	Timeout time.Duration
+}
+
+// QueryPlan 慢查询的执行计划
+type QueryPlan struct {
+	Query      string         `json:"query"`
+	Dialect    ExplainDialect `json:"dialect"`
+	Columns    []string       `json:"columns,omitempty"`
+	Rows       [][]string     `json:"rows,omitempty"`
+	FullScan   bool           `json:"full_scan"`
+	Error      string         `json:"error,omitempty"`
+	CapturedAt time.Time      `json:"captured_at"`
+}
+
+// String 以文本形式输出执行计划
+func (p *QueryPlan) String() string {
+	if p.Error != "" {
+		return "explain failed: " + p.Error
+	}
+	lines := make([]string, 0, len(p.Rows)+1)
+	if p.Dialect != ExplainPostgres {
+		lines = append(lines, strings.Join(p.Columns, " | "))
+	}
+	for _, row := range p.Rows {
+		lines = append(lines, strings.Join(row, " | "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// queryPlanner 在后台对慢查询执行 EXPLAIN 并按归一化查询缓存执行计划
+//
+// 每种查询模式只采集一次，并且受每分钟次数上限约束，避免 EXPLAIN 本身加重数据库负载。
+type queryPlanner struct {
+	config      QueryPlanConfig
+	mu          sync.Mutex
+	plans       map[string]*QueryPlan
+	pending     map[string]bool
+	windowStart time.Time
+	windowCount int
+}
+
+// newQueryPlanner 创建执行计划采集器
+func newQueryPlanner(config QueryPlanConfig) *queryPlanner {
+	if config.MaxPerMinute <= 0 {
+		config.MaxPerMinute = 10
+	}
+	if config.MaxPlans <= 0 {
+		config.MaxPlans = 100
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Second
+	}
+	return &queryPlanner{
+		config:  config,
+		plans:   make(map[string]*QueryPlan),
+		pending: make(map[string]bool),
+	}
+}
+
+// capture 在后台采集查询的执行计划，非 SELECT、多语句、已采集过或超出次数上限时忽略
+func (p *queryPlanner) capture(query, normalized string) {
+	if !explainable(query) {
+		return
+	}
+
+	p.mu.Lock()
+	if _, ok := p.plans[normalized]; ok || p.pending[normalized] {
+		p.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	if now.Sub(p.windowStart) >= time.Minute {
+		p.windowStart = now
+		p.windowCount = 0
+	}
+	if p.windowCount >= p.config.MaxPerMinute {
+		p.mu.Unlock()
+		return
+	}
+	p.windowCount++
+	p.pending[normalized] = true
+	p.mu.Unlock()
+
+	go func() {
+		plan := p.explain(query, normalized)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		delete(p.pending, normalized)
+		if len(p.plans) >= p.config.MaxPlans {
+			p.evictOldest()
+		}
+		p.plans[normalized] = plan
+	}()
+}
+
+// plan 获取查询模式的执行计划
+func (p *queryPlanner) plan(normalized string) *QueryPlan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.plans[normalized]
+}
+
+// evictOldest 移除最早采集的执行计划，调用方需持有锁
+func (p *queryPlanner) evictOldest() {
+	var oldest string
+	var oldestAt time.Time
+	for query, plan := range p.plans {
+		if oldest == "" || plan.CapturedAt.Before(oldestAt) {
+			oldest, oldestAt = query, plan.CapturedAt
+		}
+	}
+	delete(p.plans, oldest)
+}
+
+// explain 执行 EXPLAIN 并解析结果
+func (p *queryPlanner) explain(query, normalized string) *QueryPlan {
+	plan := &QueryPlan{
+		Query:      normalized,
+		Dialect:    p.config.Dialect,
+		CapturedAt: time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.config.Timeout)
+	defer cancel()
+
+	rows, err := p.config.DB.QueryContext(ctx, explainStatement(p.config.Dialect, query))
+	if err != nil {
+		plan.Error = err.Error()
+		return plan
+	}
+	defer rows.Close()
+
+	if plan.Columns, err = rows.Columns(); err != nil {
+		plan.Error = err.Error()
+		return plan
+	}
+	values := make([]interface{}, len(plan.Columns))
+	pointers := make([]interface{}, len(plan.Columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			plan.Error = err.Error()
+			return plan
+		}
+		row := make([]string, len(values))
+		for i, value := range values {
+			switch v := value.(type) {
+			case nil:
+			case []byte:
+				row[i] = string(v)
+			default:
+				row[i] = fmt.Sprint(v)
+			}
+		}
+		plan.Rows = append(plan.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		plan.Error = err.Error()
+		return plan
+	}
+
+	plan.FullScan = detectFullScan(plan)
+	return plan
+}
+
+// explainable 判断查询是否可以安全地执行 EXPLAIN：只允许单条 SELECT 语句
+func explainable(query string) bool {
+	query = strings.TrimSpace(query)
+	query = strings.TrimSuffix(query, ";")
+	if !strings.HasPrefix(strings.ToUpper(query), "SELECT") {
+		return false
+	}
+	// 去掉字符串字面量后仍包含分号说明是多条语句，EXPLAIN 只作用于第一条，其余语句会被真正执行
+	return !strings.Contains(queryStringLiteral.ReplaceAllString(query, "?"), ";")
+}
+
+// explainStatement 按方言构造 EXPLAIN 语句
+func explainStatement(dialect ExplainDialect, query string) string {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	if dialect == ExplainSQLite {
+		return "EXPLAIN QUERY PLAN " + query
+	}
+	return "EXPLAIN " + query
+}
+
+// detectFullScan 根据方言判断执行计划中是否存在全表扫描
+func detectFullScan(plan *QueryPlan) bool {
+	column := func(name string) int {
+		for i, c := range plan.Columns {
+			if strings.EqualFold(c, name) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	switch plan.Dialect {
+	case ExplainMySQL:
+		if i := column("type"); i >= 0 {
+			for _, row := range plan.Rows {
+				if strings.EqualFold(row[i], "ALL") {
+					return true
+				}
+			}
+		}
+	case ExplainPostgres:
+		for _, row := range plan.Rows {
+			if len(row) > 0 && strings.Contains(row[0], "Seq Scan") {
+				return true
+			}
+		}
+	case ExplainSQLite:
+		if i := column("detail"); i >= 0 {
+			for _, row := range plan.Rows {
+				detail := strings.ToUpper(row[i])
+				if strings.HasPrefix(detail, "SCAN ") && !strings.Contains(detail, " USING ") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// EnableQueryPlans 开启慢查询执行计划采集
+//
+// 开启后，超过慢查询阈值且执行成功的 SELECT 会在后台执行一次 EXPLAIN，执行计划按归一化查询缓存，
+// 通过 SlowQueries 和 SlowQueryStats 返回。使用 $1、? 等占位符的查询在 MySQL 和 PostgreSQL 上
+// 无法在没有参数的情况下 EXPLAIN，此时执行计划只记录错误信息。
+func (dm *DatabaseMonitor) EnableQueryPlans(config QueryPlanConfig) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.queryPlanner = newQueryPlanner(config)
+}
+
+// DisableQueryPlans 关闭慢查询执行计划采集并清除已采集的执行计划
+func (dm *DatabaseMonitor) DisableQueryPlans() {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.queryPlanner = nil
+}
+
+// QueryPlan 获取查询的执行计划，query 可以是原始查询或归一化后的查询
+func (dm *DatabaseMonitor) QueryPlan(query string) (*QueryPlan, bool) {
+	dm.mu.RLock()
+	planner := dm.queryPlanner
+	dm.mu.RUnlock()
+	if planner == nil {
+		return nil, false
+	}
+	plan := planner.plan(NormalizeQuery(query))
+	return plan, plan != nil
+}
@@ -0,0 +1,77 @@
+package performance
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestDatabaseQueryPlanCapture(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Skipf("SQLite driver not available: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT, name TEXT)"); err != nil {
+		t.Skipf("SQLite not available: %v", err)
+	}
+
+	monitor := NewPerformanceMonitor()
+	dbMonitor := NewDatabaseMonitor(monitor, 10*time.Millisecond)
+	dbMonitor.EnableQueryPlans(QueryPlanConfig{DB: db, Dialect: ExplainSQLite, MaxPerMinute: 2})
+
+	waitForPlan := func(query string) *QueryPlan {
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if plan, ok := dbMonitor.QueryPlan(query); ok {
+				return plan
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		return nil
+	}
+
+	// 没有索引的条件查询是全表扫描
+	dbMonitor.RecordQuery("SELECT * FROM users WHERE email = 'bob@example.com'", 50*time.Millisecond, true, nil)
+	plan := waitForPlan("SELECT * FROM users WHERE email = 'alice@example.com'")
+	if plan == nil {
+		t.Fatal("Expected plan to be captured for slow SELECT")
+	}
+	if plan.Error != "" || !plan.FullScan || len(plan.Rows) == 0 {
+		t.Errorf("Expected full scan plan, got %+v", plan)
+	}
+
+	// 主键查询走索引
+	dbMonitor.RecordQuery("SELECT name FROM users WHERE id = 1", 50*time.Millisecond, true, nil)
+	if plan := waitForPlan("SELECT name FROM users WHERE id = 2"); plan == nil || plan.FullScan {
+		t.Errorf("Expected primary key lookup plan, got %+v", plan)
+	}
+
+	// 执行计划随慢查询一起返回
+	entries := dbMonitor.SlowQueries(0)
+	if len(entries) != 2 || entries[1].Plan == nil || !entries[1].Plan.FullScan {
+		t.Errorf("Expected plans alongside slow queries, got %+v", entries)
+	}
+
+	// 非 SELECT、多语句、快查询不执行 EXPLAIN
+	dbMonitor.RecordQuery("UPDATE users SET name = 'x' WHERE email = 'y'", 50*time.Millisecond, true, nil)
+	dbMonitor.RecordQuery("SELECT 1; DELETE FROM users", 50*time.Millisecond, true, nil)
+	dbMonitor.RecordQuery("SELECT id FROM users", time.Millisecond, true, nil)
+	time.Sleep(50 * time.Millisecond)
+	for _, query := range []string{"UPDATE users SET name = 'x' WHERE email = 'y'", "SELECT 1; DELETE FROM users", "SELECT id FROM users"} {
+		if _, ok := dbMonitor.QueryPlan(query); ok {
+			t.Errorf("Expected no plan for %q", query)
+		}
+	}
+
+	// 超出每分钟次数上限后不再执行 EXPLAIN
+	dbMonitor.RecordQuery("SELECT email FROM users WHERE name = 'bob'", 50*time.Millisecond, true, nil)
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := dbMonitor.QueryPlan("SELECT email FROM users WHERE name = 'bob'"); ok {
+		t.Error("Expected EXPLAIN to be capped at 2 per minute")
+	}
+}
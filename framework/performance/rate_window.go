@@ -0,0 +1,201 @@
+package performance
+
+import (
+	"sync"
+	"time"
+)
+
+// SlidingWindow 滑动窗口计数器
+//
+// 窗口被划分为固定数量的时间桶并存放在环形缓冲区中，只保留每个桶的计数，
+// 不保留单个请求，内存占用与请求量无关。
+type SlidingWindow struct {
+	mu         sync.Mutex
+	size       time.Duration
+	bucketSize time.Duration
+	counts     []int64
+	epochs     []int64
+	clock      func() time.Time
+}
+
+// NewSlidingWindow 创建滑动窗口计数器，buckets 越多精度越高
+func NewSlidingWindow(size time.Duration, buckets int) *SlidingWindow {
+	if buckets <= 0 {
+		buckets = 60
+	}
+	bucketSize := size / time.Duration(buckets)
+	if bucketSize <= 0 {
+		bucketSize = time.Millisecond
+	}
+
+	return &SlidingWindow{
+		size:       size,
+		bucketSize: bucketSize,
+		counts:     make([]int64, buckets),
+		epochs:     make([]int64, buckets),
+		clock:      time.Now,
+	}
+}
+
+// SetClock 设置时钟，用于测试
+func (w *SlidingWindow) SetClock(clock func() time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.clock = clock
+}
+
+// Add 在当前时间桶中增加计数
+func (w *SlidingWindow) Add(delta int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	epoch := w.epoch()
+	index := int(epoch % int64(len(w.counts)))
+
+	// 桶属于更早的轮次时先清零
+	if w.epochs[index] != epoch {
+		w.epochs[index] = epoch
+		w.counts[index] = 0
+	}
+	w.counts[index] += delta
+}
+
+// Sum 获取窗口内的总计数
+func (w *SlidingWindow) Sum() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	epoch := w.epoch()
+	buckets := int64(len(w.counts))
+
+	var sum int64
+	for i, count := range w.counts {
+		if epoch-w.epochs[i] < buckets {
+			sum += count
+		}
+	}
+	return sum
+}
+
+// Rate 获取窗口内每秒的平均计数
+func (w *SlidingWindow) Rate() float64 {
+	return float64(w.Sum()) / w.size.Seconds()
+}
+
+// epoch 当前时间所在的桶序号
+func (w *SlidingWindow) epoch() int64 {
+	return w.clock().UnixNano() / int64(w.bucketSize)
+}
+
+// FixedWindow 固定窗口计数器，按对齐的时间窗口计数
+type FixedWindow struct {
+	mu       sync.Mutex
+	size     time.Duration
+	start    int64
+	current  int64
+	previous int64
+	clock    func() time.Time
+}
+
+// NewFixedWindow 创建固定窗口计数器
+func NewFixedWindow(size time.Duration) *FixedWindow {
+	return &FixedWindow{
+		size:  size,
+		clock: time.Now,
+	}
+}
+
+// SetClock 设置时钟，用于测试
+func (w *FixedWindow) SetClock(clock func() time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.clock = clock
+}
+
+// Add 在当前窗口中增加计数
+func (w *FixedWindow) Add(delta int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.advance()
+	w.current += delta
+}
+
+// Current 获取当前窗口的计数
+func (w *FixedWindow) Current() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.advance()
+	return w.current
+}
+
+// Previous 获取上一个完整窗口的计数
+func (w *FixedWindow) Previous() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.advance()
+	return w.previous
+}
+
+// advance 切换到当前时间所在的窗口
+func (w *FixedWindow) advance() {
+	start := w.clock().UnixNano() / int64(w.size)
+	if start == w.start {
+		return
+	}
+
+	// 紧邻的上一个窗口保留计数，更早的窗口视为没有请求
+	if start == w.start+1 {
+		w.previous = w.current
+	} else {
+		w.previous = 0
+	}
+	w.current = 0
+	w.start = start
+}
+
+// GaugeFunc 读取时计算数值的仪表指标
+type GaugeFunc struct {
+	name   string
+	help   string
+	labels map[string]string
+	fn     func() float64
+}
+
+// NewGaugeFunc 创建读取时计算数值的仪表指标
+func NewGaugeFunc(name string, labels map[string]string, fn func() float64, options ...MetricOption) *GaugeFunc {
+	o := applyMetricOptions(options)
+	return &GaugeFunc{
+		name:   SanitizeMetricName(name),
+		help:   o.help,
+		labels: sanitizeLabels(labels),
+		fn:     fn,
+	}
+}
+
+func (g *GaugeFunc) Name() string {
+	return g.name
+}
+
+func (g *GaugeFunc) Type() MetricType {
+	return MetricTypeGauge
+}
+
+func (g *GaugeFunc) Value() interface{} {
+	return g.fn()
+}
+
+// Help 获取帮助说明
+func (g *GaugeFunc) Help() string {
+	return g.help
+}
+
+func (g *GaugeFunc) Labels() map[string]string {
+	return g.labels
+}
+
+func (g *GaugeFunc) Timestamp() time.Time {
+	return time.Now()
+}
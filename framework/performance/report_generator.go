@@ -3,7 +3,9 @@ package performance
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -77,6 +79,9 @@ type EndpointStats struct {
 	Method      string        `json:"method"`
 	Count       int64         `json:"count"`
 	AverageTime time.Duration `json:"average_time"`
+	P50         time.Duration `json:"p50"`
+	P95         time.Duration `json:"p95"`
+	P99         time.Duration `json:"p99"`
 	ErrorCount  int64         `json:"error_count"`
 	ErrorRate   float64       `json:"error_rate"`
 }
@@ -85,6 +90,7 @@ type EndpointStats struct {
 type DatabaseReportDetails struct {
 	QueryTypeDistribution map[string]int64    `json:"query_type_distribution"`
 	SlowQueries           []QueryRecord       `json:"slow_queries"`
+	SlowQueryStats        []SlowQueryStat     `json:"slow_query_stats"`
 	ErrorQueries          []QueryRecord       `json:"error_queries"`
 	AverageQueryTime      time.Duration       `json:"average_query_time"`
 	ConnectionPoolStats   ConnectionPoolStats `json:"connection_pool_stats"`
@@ -185,6 +191,8 @@ type ReportGenerator struct {
 	dbMonitor    *DatabaseMonitor
 	cacheMonitor *CacheMonitor
 	alertSystem  *AlertSystem
+	baseline     *MetricsSnapshot
+	mu           sync.Mutex
 }
 
 // NewReportGenerator 创建报告生成器
@@ -198,8 +206,32 @@ func NewReportGenerator(monitor Monitor, httpMonitor *HTTPMonitor, dbMonitor *Da
 	}
 }
 
+// SetBaseline 设置下一份报告的起始快照，为 nil 时下一份报告使用启动以来的累计值
+func (rg *ReportGenerator) SetBaseline(snapshot *MetricsSnapshot) {
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+	rg.baseline = snapshot
+}
+
+// advanceBaseline 获取当前快照与上一份报告快照的差异，并将当前快照作为下一份报告的起点
+func (rg *ReportGenerator) advanceBaseline() *SnapshotDiff {
+	current := rg.monitor.Snapshot()
+
+	rg.mu.Lock()
+	defer rg.mu.Unlock()
+	previous := rg.baseline
+	rg.baseline = current
+	if previous == nil {
+		return nil
+	}
+	return current.Diff(previous)
+}
+
 // GenerateReport 生成性能报告
+//
+// 首份报告的请求数、错误率和吞吐量基于启动以来的累计值，之后的报告基于与上一份报告之间的快照差异。
 func (rg *ReportGenerator) GenerateReport(reportType ReportType, period ReportPeriod) (*PerformanceReport, error) {
+	diff := rg.advanceBaseline()
 	report := &PerformanceReport{
 		ID:          fmt.Sprintf("report_%s_%d", reportType, time.Now().Unix()),
 		Type:        reportType,
@@ -212,27 +244,27 @@ func (rg *ReportGenerator) GenerateReport(reportType ReportType, period ReportPe
 	case ReportTypeSummary:
 		report.Title = "性能监控摘要报告"
 		report.Description = "应用程序性能监控摘要"
-		report.Summary = rg.generateSummary(period)
+		report.Summary = rg.generateSummary(period, diff)
 		report.Recommendations = rg.generateRecommendations(report.Summary)
 
 	case ReportTypeDetailed:
 		report.Title = "性能监控详细报告"
 		report.Description = "应用程序性能监控详细分析"
-		report.Summary = rg.generateSummary(period)
+		report.Summary = rg.generateSummary(period, diff)
 		report.Details = rg.generateDetails(period)
 		report.Recommendations = rg.generateRecommendations(report.Summary)
 
 	case ReportTypeTrend:
 		report.Title = "性能趋势分析报告"
 		report.Description = "应用程序性能趋势分析"
-		report.Summary = rg.generateSummary(period)
+		report.Summary = rg.generateSummary(period, diff)
 		report.Metadata["trends"] = rg.generateTrends(period)
 		report.Recommendations = rg.generateRecommendations(report.Summary)
 
 	case ReportTypeComparison:
 		report.Title = "性能对比分析报告"
 		report.Description = "应用程序性能对比分析"
-		report.Summary = rg.generateSummary(period)
+		report.Summary = rg.generateSummary(period, diff)
 		report.Metadata["comparison"] = rg.generateComparison(period)
 		report.Recommendations = rg.generateRecommendations(report.Summary)
 	}
@@ -240,17 +272,29 @@ func (rg *ReportGenerator) GenerateReport(reportType ReportType, period ReportPe
 	return report, nil
 }
 
-// generateSummary 生成摘要
-func (rg *ReportGenerator) generateSummary(period ReportPeriod) ReportSummary {
+// generateSummary 生成摘要，diff 不为 nil 时 HTTP 指标按报告周期内的变化量计算
+func (rg *ReportGenerator) generateSummary(period ReportPeriod, diff *SnapshotDiff) ReportSummary {
 	summary := ReportSummary{}
 
 	// HTTP指标
 	if rg.httpMonitor != nil {
 		metrics := rg.httpMonitor.GetMetrics()
-		summary.TotalRequests = metrics.requestCounter.Value().(int64)
-		summary.ErrorRate = rg.calculateErrorRate(metrics)
-		summary.Throughput = float64(summary.TotalRequests) / period.Duration.Seconds()
-		summary.AverageResponseTime = rg.calculateAverageResponseTime(metrics)
+		if diff != nil {
+			requests := diff.Delta(metrics.requestCounter.Name())
+			summary.TotalRequests = int64(requests)
+			summary.Throughput = diff.Rate(metrics.requestCounter.Name())
+			if requests > 0 {
+				summary.ErrorRate = diff.Delta(metrics.errorCounter.Name()) / requests * 100.0
+			}
+			if delta, ok := diff.Get(metrics.responseTimeHistogram.Name()); ok && delta.CountDelta > 0 {
+				summary.AverageResponseTime = time.Duration(delta.SumDelta / float64(delta.CountDelta) * float64(time.Millisecond))
+			}
+		} else {
+			summary.TotalRequests = metrics.requestCounter.Value().(int64)
+			summary.ErrorRate = rg.calculateErrorRate(metrics)
+			summary.Throughput = float64(summary.TotalRequests) / period.Duration.Seconds()
+			summary.AverageResponseTime = rg.calculateAverageResponseTime(metrics)
+		}
 	}
 
 	// 数据库指标
@@ -321,16 +365,55 @@ func (rg *ReportGenerator) generateHTTPDetails() HTTPReportDetails {
 		StatusCodeDistribution:   make(map[int]int64),
 	}
 
-	// 获取请求历史
-	collector := rg.httpMonitor.GetMetrics()
-	if collector != nil {
-		// 这里需要根据实际的HTTP监控器实现来获取详细信息
-		// 由于当前实现限制，这里提供基础结构
+	// 按路由汇总
+	var endpoints []EndpointStats
+	for key, route := range rg.httpMonitor.RouteStats() {
+		details.RequestDistribution[key] = route.Count
+
+		endpoint := EndpointStats{
+			Path:        route.Path,
+			Method:      route.Method,
+			Count:       route.Count,
+			AverageTime: route.AverageTime,
+			P50:         route.P50,
+			P95:         route.P95,
+			P99:         route.P99,
+			ErrorCount:  route.ErrorCount,
+		}
+		if route.Count > 0 {
+			endpoint.ErrorRate = float64(route.ErrorCount) / float64(route.Count) * 100.0
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	details.TopEndpoints = topEndpoints(endpoints, func(a, b EndpointStats) bool { return a.Count > b.Count })
+	details.SlowestEndpoints = topEndpoints(endpoints, func(a, b EndpointStats) bool { return a.P95 > b.P95 })
+	for _, endpoint := range topEndpoints(endpoints, func(a, b EndpointStats) bool { return a.ErrorRate > b.ErrorRate }) {
+		if endpoint.ErrorCount > 0 {
+			details.ErrorEndpoints = append(details.ErrorEndpoints, endpoint)
+		}
 	}
 
 	return details
 }
 
+// topEndpoints 按指定顺序排序并返回前 10 个端点
+func topEndpoints(endpoints []EndpointStats, less func(a, b EndpointStats) bool) []EndpointStats {
+	sorted := make([]EndpointStats, len(endpoints))
+	copy(sorted, endpoints)
+	sort.Slice(sorted, func(i, j int) bool {
+		if less(sorted[i], sorted[j]) == less(sorted[j], sorted[i]) {
+			return sorted[i].Method+sorted[i].Path < sorted[j].Method+sorted[j].Path
+		}
+		return less(sorted[i], sorted[j])
+	})
+
+	if len(sorted) > 10 {
+		sorted = sorted[:10]
+	}
+	return sorted
+}
+
 // generateDatabaseDetails 生成数据库详情
 func (rg *ReportGenerator) generateDatabaseDetails() DatabaseReportDetails {
 	details := DatabaseReportDetails{
@@ -346,6 +429,7 @@ func (rg *ReportGenerator) generateDatabaseDetails() DatabaseReportDetails {
 
 		// 获取慢查询
 		details.SlowQueries = rg.dbMonitor.GetSlowQueries()
+		details.SlowQueryStats = rg.dbMonitor.SlowQueryStats(10)
 
 		// 获取错误查询
 		details.ErrorQueries = rg.dbMonitor.GetErrorQueries()
@@ -555,6 +639,30 @@ func (rg *ReportGenerator) generateRecommendations(summary ReportSummary) []Reco
 		})
 	}
 
+	// 慢查询建议
+	if rg.dbMonitor != nil {
+		if stats := rg.dbMonitor.SlowQueryStats(3); len(stats) > 0 {
+			actions := make([]string, 0, len(stats)+1)
+			for _, stat := range stats {
+				action := fmt.Sprintf("优化查询（%d 次，平均 %s）: %s", stat.Count, stat.AverageDuration, stat.Query)
+				if stat.Plan != nil && stat.Plan.FullScan {
+					action += "（执行计划显示全表扫描）"
+				}
+				actions = append(actions, action)
+			}
+			actions = append(actions, "为过滤和排序字段添加索引")
+			recommendations = append(recommendations, Recommendation{
+				Type:        "database",
+				Title:       "优化慢查询",
+				Description: fmt.Sprintf("慢查询日志中耗时最多的查询累计耗时 %s，建议优先优化", stats[0].TotalDuration),
+				Priority:    "high",
+				Impact:      30.0,
+				Effort:      "medium",
+				Actions:     actions,
+			})
+		}
+	}
+
 	return recommendations
 }
 
@@ -614,6 +722,18 @@ func (rg *ReportGenerator) exportAsText(report *PerformanceReport) ([]byte, erro
 	builder.WriteString(fmt.Sprintf("缓存命中率: %.2f%%\n", report.Summary.CacheHitRate))
 	builder.WriteString(fmt.Sprintf("活跃告警: %d\n\n", report.Summary.ActiveAlerts))
 
+	// 路由延迟
+	if endpoints := report.Details.HTTPMetrics.SlowestEndpoints; len(endpoints) > 0 {
+		builder.WriteString("路由延迟\n")
+		builder.WriteString("--------\n")
+		for _, endpoint := range endpoints {
+			builder.WriteString(fmt.Sprintf("%s %s: 请求 %d, 错误 %d, p50 %v, p95 %v, p99 %v\n",
+				endpoint.Method, endpoint.Path, endpoint.Count, endpoint.ErrorCount,
+				endpoint.P50, endpoint.P95, endpoint.P99))
+		}
+		builder.WriteString("\n")
+	}
+
 	// 建议
 	if len(report.Recommendations) > 0 {
 		builder.WriteString("优化建议\n")
@@ -0,0 +1,119 @@
+package performance
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxRouteSamples 每个路由保留的最近响应时间样本数
+const maxRouteSamples = 1024
+
+// RouteStat 路由统计
+type RouteStat struct {
+	Method      string        `json:"method"`
+	Path        string        `json:"path"`
+	Count       int64         `json:"count"`
+	ErrorCount  int64         `json:"error_count"`
+	AverageTime time.Duration `json:"average_time"`
+	P50         time.Duration `json:"p50"`
+	P95         time.Duration `json:"p95"`
+	P99         time.Duration `json:"p99"`
+}
+
+// routeRecorder 路由响应时间记录器，使用环形缓冲区保留最近的样本
+type routeRecorder struct {
+	method    string
+	path      string
+	count     int64
+	errors    int64
+	timed     int64
+	totalTime time.Duration
+	samples   []time.Duration
+	next      int
+}
+
+// observe 记录一次响应，weight 为采样时这次响应代表的响应数量
+func (r *routeRecorder) observe(duration time.Duration, isError bool, weight int64) {
+	r.count += weight
+	r.timed += weight
+	r.totalTime += duration * time.Duration(weight)
+	if isError {
+		r.errors += weight
+	}
+
+	if len(r.samples) < maxRouteSamples {
+		r.samples = append(r.samples, duration)
+		return
+	}
+	r.samples[r.next] = duration
+	r.next = (r.next + 1) % maxRouteSamples
+}
+
+// fail 记录一次错误，请求数由 observe 计入
+func (r *routeRecorder) fail() {
+	r.errors++
+}
+
+// abort 记录一次没有响应的失败请求，不计入响应时间
+func (r *routeRecorder) abort() {
+	r.count++
+	r.errors++
+}
+
+// stat 计算路由统计
+func (r *routeRecorder) stat() RouteStat {
+	stat := RouteStat{
+		Method:     r.method,
+		Path:       r.path,
+		Count:      r.count,
+		ErrorCount: r.errors,
+	}
+	if r.timed > 0 {
+		stat.AverageTime = r.totalTime / time.Duration(r.timed)
+	}
+
+	if len(r.samples) > 0 {
+		sorted := make([]time.Duration, len(r.samples))
+		copy(sorted, r.samples)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		stat.P50 = percentile(sorted, 50)
+		stat.P95 = percentile(sorted, 95)
+		stat.P99 = percentile(sorted, 99)
+	}
+
+	return stat
+}
+
+// percentile 计算已排序样本的百分位数（最近秩法）
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p/100*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// routeKey 路由统计的键
+func routeKey(method, path string) string {
+	return method + " " + path
+}
+
+var idSegmentPattern = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{24,})$`)
+
+// IDPathTemplate 将路径中的数字、UUID 和长十六进制段折叠为 :id
+// 例如 /users/42/orders/9f8e... 会被折叠为 /users/:id/orders/:id
+func IDPathTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if idSegmentPattern.MatchString(segment) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
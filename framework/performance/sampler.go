@@ -0,0 +1,43 @@
+package performance
+
+import "sync/atomic"
+
+// Sampler 按 1/N 比例采样的采样器，采样率可在运行时调整
+//
+// 采样按调用次数确定性地选择每第 N 次操作，不依赖随机数，高并发下开销只有一次原子操作。
+type Sampler struct {
+	rate    int64
+	counter uint64
+}
+
+// NewSampler 创建采样器，rate 不大于 1 时记录所有操作
+func NewSampler(rate int) *Sampler {
+	s := &Sampler{}
+	s.SetRate(rate)
+	return s
+}
+
+// SetRate 设置采样率，每 rate 次操作完整记录一次
+func (s *Sampler) SetRate(rate int) {
+	if rate < 1 {
+		rate = 1
+	}
+	atomic.StoreInt64(&s.rate, int64(rate))
+}
+
+// Rate 获取采样率
+func (s *Sampler) Rate() int {
+	return int(atomic.LoadInt64(&s.rate))
+}
+
+// Sample 判断本次操作是否被采样，被采样时返回它代表的操作数量（即采样率），否则返回 0
+func (s *Sampler) Sample() int64 {
+	rate := atomic.LoadInt64(&s.rate)
+	if rate <= 1 {
+		return 1
+	}
+	if atomic.AddUint64(&s.counter, 1)%uint64(rate) == 0 {
+		return rate
+	}
+	return 0
+}
@@ -0,0 +1,364 @@
+package performance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"laravel-go/framework/event"
+)
+
+// ScalingDirection 扩缩容方向
+type ScalingDirection string
+
+const (
+	ScaleUp   ScalingDirection = "scale_up"
+	ScaleDown ScalingDirection = "scale_down"
+)
+
+// ScalingRecommendedEvent 扩缩容建议事件名称，事件负载为 *ScalingRecommendation
+const ScalingRecommendedEvent = "performance.scaling.recommended"
+
+// ScalingPolicy 扩缩容策略
+//
+// 指标高于 ScaleUpThreshold 时建议扩容；所有策略的指标都低于 ScaleDownThreshold 时建议缩容。
+// 队列积压等框架之外的指标可以通过 NewGaugeFunc 注册到监控器后使用。
+type ScalingPolicy struct {
+	Metric             string  `json:"metric"`
+	ScaleUpThreshold   float64 `json:"scale_up_threshold"`
+	ScaleDownThreshold float64 `json:"scale_down_threshold"`
+}
+
+// ScalingConfig 扩缩容顾问配置
+type ScalingConfig struct {
+	Policies    []ScalingPolicy `json:"policies"`
+	Sustain     int             `json:"sustain"`  // 连续满足条件的检查次数，默认 3
+	Cooldown    time.Duration   `json:"cooldown"` // 两次建议的最小间隔，默认 5 分钟
+	Interval    time.Duration   `json:"interval"` // 检查间隔，默认 30 秒
+	Step        int             `json:"step"`     // 每次增减的副本数，默认 1
+	MinReplicas int             `json:"min_replicas"`
+	MaxReplicas int             `json:"max_replicas"`
+}
+
+// DefaultScalingConfig 默认配置：CPU 使用率持续高于 75% 扩容，低于 25% 缩容，副本数 1-10
+func DefaultScalingConfig() ScalingConfig {
+	return ScalingConfig{
+		Policies: []ScalingPolicy{
+			{Metric: "cpu_usage", ScaleUpThreshold: 75, ScaleDownThreshold: 25},
+		},
+		Sustain:     3,
+		Cooldown:    5 * time.Minute,
+		Interval:    30 * time.Second,
+		Step:        1,
+		MinReplicas: 1,
+		MaxReplicas: 10,
+	}
+}
+
+// ScalingRecommendation 扩缩容建议
+type ScalingRecommendation struct {
+	Direction ScalingDirection   `json:"direction"`
+	Current   int                `json:"current"`
+	Desired   int                `json:"desired"`
+	Reason    string             `json:"reason"`
+	Metrics   map[string]float64 `json:"metrics"`
+	Timestamp time.Time          `json:"timestamp"`
+	Applied   bool               `json:"applied"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// Scaler 执行扩缩容建议
+type Scaler interface {
+	Scale(recommendation *ScalingRecommendation) error
+}
+
+// ScalerFunc 函数形式的 Scaler
+type ScalerFunc func(recommendation *ScalingRecommendation) error
+
+// Scale 执行扩缩容建议
+func (f ScalerFunc) Scale(recommendation *ScalingRecommendation) error {
+	return f(recommendation)
+}
+
+// ScalingAdvisor 扩缩容顾问
+//
+// 定期读取监控器中的指标，条件连续满足 Sustain 次后给出扩缩容建议，
+// 建议之后的 Cooldown 时间内不再给出新建议，避免副本数来回抖动。
+// 建议以 ScalingRecommendedEvent 事件分发，设置了 Scaler 时同时执行。
+type ScalingAdvisor struct {
+	monitor    Monitor
+	config     ScalingConfig
+	scaler     Scaler
+	dispatcher event.Dispatcher
+	replicas   int
+	upCount    int
+	downCount  int
+	lastScaled time.Time
+	history    []*ScalingRecommendation
+	now        func() time.Time
+	mu         sync.Mutex
+	running    bool
+	cancel     context.CancelFunc
+}
+
+// NewScalingAdvisor 创建扩缩容顾问，当前副本数默认为 MinReplicas
+func NewScalingAdvisor(monitor Monitor, config ScalingConfig) *ScalingAdvisor {
+	defaults := DefaultScalingConfig()
+	if config.Sustain <= 0 {
+		config.Sustain = defaults.Sustain
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaults.Cooldown
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaults.Interval
+	}
+	if config.Step <= 0 {
+		config.Step = defaults.Step
+	}
+	if config.MinReplicas <= 0 {
+		config.MinReplicas = 1
+	}
+	if config.MaxReplicas < config.MinReplicas {
+		config.MaxReplicas = config.MinReplicas
+	}
+
+	return &ScalingAdvisor{
+		monitor:  monitor,
+		config:   config,
+		replicas: config.MinReplicas,
+		now:      time.Now,
+	}
+}
+
+// SetScaler 设置执行建议的 Scaler
+func (sa *ScalingAdvisor) SetScaler(scaler Scaler) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	sa.scaler = scaler
+}
+
+// SetDispatcher 设置分发建议事件的分发器，未设置时使用 event 包的全局分发器
+func (sa *ScalingAdvisor) SetDispatcher(dispatcher event.Dispatcher) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	sa.dispatcher = dispatcher
+}
+
+// SetReplicas 设置当前副本数，外部扩缩容后用于同步
+func (sa *ScalingAdvisor) SetReplicas(replicas int) {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	sa.replicas = replicas
+}
+
+// Replicas 获取当前副本数
+func (sa *ScalingAdvisor) Replicas() int {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	return sa.replicas
+}
+
+// Recommendations 获取历史建议
+func (sa *ScalingAdvisor) Recommendations() []*ScalingRecommendation {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	return append([]*ScalingRecommendation(nil), sa.history...)
+}
+
+// Evaluate 检查一次指标，满足条件时返回建议，否则返回 nil
+func (sa *ScalingAdvisor) Evaluate() *ScalingRecommendation {
+	sa.mu.Lock()
+
+	values := make(map[string]float64, len(sa.config.Policies))
+	var high []string
+	low := len(sa.config.Policies) > 0
+	for _, policy := range sa.config.Policies {
+		metric := sa.monitor.GetMetric(policy.Metric)
+		if metric == nil {
+			low = false
+			continue
+		}
+		value, ok := metricFloat(metric)
+		if !ok {
+			low = false
+			continue
+		}
+		values[policy.Metric] = value
+		if value > policy.ScaleUpThreshold {
+			high = append(high, fmt.Sprintf("%s %.2f > %.2f", policy.Metric, value, policy.ScaleUpThreshold))
+		}
+		if value >= policy.ScaleDownThreshold {
+			low = false
+		}
+	}
+
+	// 条件中断时重新计数
+	if len(high) > 0 {
+		sa.upCount++
+		sa.downCount = 0
+	} else if low {
+		sa.downCount++
+		sa.upCount = 0
+	} else {
+		sa.upCount, sa.downCount = 0, 0
+	}
+
+	now := sa.now()
+	if !sa.lastScaled.IsZero() && now.Sub(sa.lastScaled) < sa.config.Cooldown {
+		sa.mu.Unlock()
+		return nil
+	}
+
+	var recommendation *ScalingRecommendation
+	switch {
+	case sa.upCount >= sa.config.Sustain && sa.replicas < sa.config.MaxReplicas:
+		recommendation = &ScalingRecommendation{
+			Direction: ScaleUp,
+			Desired:   min(sa.replicas+sa.config.Step, sa.config.MaxReplicas),
+			Reason:    strings.Join(high, ", "),
+		}
+	case sa.downCount >= sa.config.Sustain && sa.replicas > sa.config.MinReplicas:
+		recommendation = &ScalingRecommendation{
+			Direction: ScaleDown,
+			Desired:   max(sa.replicas-sa.config.Step, sa.config.MinReplicas),
+			Reason:    "all metrics below scale-down thresholds",
+		}
+	default:
+		sa.mu.Unlock()
+		return nil
+	}
+
+	recommendation.Current = sa.replicas
+	recommendation.Metrics = values
+	recommendation.Timestamp = now
+	sa.upCount, sa.downCount = 0, 0
+	sa.lastScaled = now
+	sa.history = append(sa.history, recommendation)
+	scaler, dispatcher := sa.scaler, sa.dispatcher
+	sa.mu.Unlock()
+
+	if scaler != nil {
+		if err := scaler.Scale(recommendation); err != nil {
+			recommendation.Error = err.Error()
+		} else {
+			recommendation.Applied = true
+			sa.SetReplicas(recommendation.Desired)
+		}
+	}
+	sa.dispatch(dispatcher, recommendation)
+	return recommendation
+}
+
+// dispatch 分发建议事件
+func (sa *ScalingAdvisor) dispatch(dispatcher event.Dispatcher, recommendation *ScalingRecommendation) {
+	e := event.NewEvent(ScalingRecommendedEvent, recommendation)
+	e.SetData("direction", string(recommendation.Direction))
+	e.SetData("desired", recommendation.Desired)
+	if dispatcher != nil {
+		dispatcher.Dispatch(e)
+		return
+	}
+	if event.HasListeners(ScalingRecommendedEvent) {
+		event.Dispatch(e)
+	}
+}
+
+// Start 启动定期检查
+func (sa *ScalingAdvisor) Start(ctx context.Context) error {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	if sa.running {
+		return fmt.Errorf("scaling advisor is already running")
+	}
+
+	ctx, sa.cancel = context.WithCancel(ctx)
+	sa.running = true
+
+	go func() {
+		ticker := time.NewTicker(sa.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sa.Evaluate()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop 停止定期检查
+func (sa *ScalingAdvisor) Stop() error {
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+
+	if !sa.running {
+		return fmt.Errorf("scaling advisor is not running")
+	}
+
+	sa.cancel()
+	sa.running = false
+
+	return nil
+}
+
+// metricFloat 读取仪表或计数器的数值
+func metricFloat(metric Metric) (float64, bool) {
+	switch v := metric.Value().(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// ShellScaler 执行命令的 Scaler，例如调用 kubectl scale 或写入 HPA 注解的脚本
+//
+// 建议通过环境变量 SCALE_DIRECTION、SCALE_CURRENT、SCALE_DESIRED 和 SCALE_REASON 传给命令。
+type ShellScaler struct {
+	Command string
+	Args    []string
+	Timeout time.Duration
+}
+
+// NewShellScaler 创建执行命令的 Scaler
+func NewShellScaler(command string, args ...string) *ShellScaler {
+	return &ShellScaler{Command: command, Args: args, Timeout: time.Minute}
+}
+
+// Scale 执行命令，命令失败时返回包含输出的错误
+func (s *ShellScaler) Scale(recommendation *ScalingRecommendation) error {
+	ctx := context.Background()
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, s.Command, s.Args...)
+	cmd.Env = append(os.Environ(),
+		"SCALE_DIRECTION="+string(recommendation.Direction),
+		"SCALE_CURRENT="+strconv.Itoa(recommendation.Current),
+		"SCALE_DESIRED="+strconv.Itoa(recommendation.Desired),
+		"SCALE_REASON="+recommendation.Reason,
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("scaler command failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package performance
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+// PprofGuard pprof 端点的鉴权函数，返回 false 时拒绝访问
+type PprofGuard func(r *http.Request) bool
+
+// RegisterPprof 在 mux 上挂载 net/http/pprof 的处理器
+//
+// 除标准的 /debug/pprof/ 索引、cmdline、profile、symbol、trace 外，还单独挂载了 allocs、heap、
+// goroutine 等常用剖析。guard 不为 nil 时，每个请求先经过鉴权，未通过返回 403。
+func RegisterPprof(mux *http.ServeMux, guard PprofGuard) {
+	handle := func(pattern string, handler http.Handler) {
+		if guard != nil {
+			next := handler
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !guard(r) {
+					http.Error(w, "Forbidden", http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		}
+		mux.Handle(pattern, handler)
+	}
+
+	handle("/debug/pprof/", http.HandlerFunc(pprof.Index))
+	handle("/debug/pprof/cmdline", http.HandlerFunc(pprof.Cmdline))
+	handle("/debug/pprof/profile", http.HandlerFunc(pprof.Profile))
+	handle("/debug/pprof/symbol", http.HandlerFunc(pprof.Symbol))
+	handle("/debug/pprof/trace", http.HandlerFunc(pprof.Trace))
+	for _, profile := range []string{"allocs", "heap", "goroutine", "block", "mutex", "threadcreate"} {
+		handle("/debug/pprof/"+profile, pprof.Handler(profile))
+	}
+}
+
+// PprofTokenGuard 校验 Authorization: Bearer <token> 请求头的 pprof 鉴权函数
+func PprofTokenGuard(token string) PprofGuard {
+	return func(r *http.Request) bool {
+		return token != "" && r.Header.Get("Authorization") == "Bearer "+token
+	}
+}
+
+// MonitorServerConfig 监控服务器配置
+type MonitorServerConfig struct {
+	// Addr 监听地址，默认为 :8088
+	Addr string
+	// EnablePprof 是否挂载 pprof 端点，默认关闭
+	EnablePprof bool
+	// PprofGuard pprof 端点的鉴权函数，为 nil 时不鉴权，生产环境应当设置
+	PprofGuard PprofGuard
+}
+
+// MonitorServer 监控服务器
+//
+// 提供 /metrics（Prometheus 文本格式）、/status（指标快照）和 /health 端点，
+// 开启 EnablePprof 后额外提供 /debug/pprof/ 端点。
+type MonitorServer struct {
+	config     MonitorServerConfig
+	monitor    Monitor
+	mux        *http.ServeMux
+	httpServer *http.Server
+}
+
+// NewMonitorServer 创建监控服务器
+func NewMonitorServer(monitor Monitor, config MonitorServerConfig) *MonitorServer {
+	if config.Addr == "" {
+		config.Addr = ":8088"
+	}
+
+	s := &MonitorServer{
+		config:  config,
+		monitor: monitor,
+		mux:     http.NewServeMux(),
+	}
+
+	s.mux.Handle("/metrics", NewPrometheusExporter(monitor))
+	s.mux.HandleFunc("/status", s.handleStatus)
+	s.mux.HandleFunc("/health", s.handleHealth)
+	if config.EnablePprof {
+		RegisterPprof(s.mux, config.PprofGuard)
+	}
+	return s
+}
+
+// Handler 获取监控服务器的处理器，可以挂载到已有的服务器上
+func (s *MonitorServer) Handler() http.Handler {
+	return s.mux
+}
+
+// Start 启动监控服务器
+func (s *MonitorServer) Start() error {
+	// 不设置写超时，CPU 剖析和 trace 默认需要持续采集 30 秒
+	s.httpServer = &http.Server{
+		Addr:              s.config.Addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+	return s.httpServer.ListenAndServe()
+}
+
+// Stop 停止监控服务器
+func (s *MonitorServer) Stop(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Shutdown(ctx)
+}
+
+// handleStatus 返回指标快照
+func (s *MonitorServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.monitor.Snapshot())
+}
+
+// handleHealth 健康检查
+func (s *MonitorServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
@@ -0,0 +1,181 @@
+package performance
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// defaultSlowQueryLogSize 慢查询日志默认保留的条目数
+const defaultSlowQueryLogSize = 200
+
+var (
+	queryStringLiteral = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'`)
+	queryNamedParam    = regexp.MustCompile(`(^|[^:\w]):[A-Za-z_]\w*`)
+	queryPositional    = regexp.MustCompile(`\$\d+|@\w+`)
+	queryNumber        = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	queryInList        = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	queryValuesList    = regexp.MustCompile(`(\(\s*\?(?:\s*,\s*\?)*\s*\))(?:\s*,\s*\(\s*\?(?:\s*,\s*\?)*\s*\))+`)
+	queryWhitespace    = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeQuery 将查询中的字面量和各种参数占位符统一替换为 ?，使同一模式的查询可以聚合
+//
+// 例如 "SELECT * FROM users WHERE id = 42 AND name = 'bob'" 和
+// "SELECT * FROM users WHERE id = $1 AND name = $2" 都归一化为
+// "SELECT * FROM users WHERE id = ? AND name = ?"，IN 列表和批量插入的多组值折叠为一组。
+func NormalizeQuery(sql string) string {
+	sql = queryStringLiteral.ReplaceAllString(sql, "?")
+	sql = queryNamedParam.ReplaceAllString(sql, "$1?")
+	sql = queryPositional.ReplaceAllString(sql, "?")
+	sql = queryNumber.ReplaceAllString(sql, "?")
+	sql = queryWhitespace.ReplaceAllString(sql, " ")
+	sql = queryInList.ReplaceAllString(sql, "IN (?)")
+	sql = queryValuesList.ReplaceAllString(sql, "$1")
+	return strings.TrimSpace(sql)
+}
+
+// SlowQueryEntry 慢查询日志条目，只保存归一化后的查询，不包含参数值
+type SlowQueryEntry struct {
+	Query     string        `json:"query"`
+	Duration  time.Duration `json:"duration"`
+	Timestamp time.Time     `json:"timestamp"`
+	Error     string        `json:"error,omitempty"`
+	Plan      *QueryPlan    `json:"plan,omitempty"`
+}
+
+// SlowQueryStat 按归一化查询聚合的慢查询统计
+type SlowQueryStat struct {
+	Query           string        `json:"query"`
+	Count           int64         `json:"count"`
+	TotalDuration   time.Duration `json:"total_duration"`
+	AverageDuration time.Duration `json:"average_duration"`
+	MaxDuration     time.Duration `json:"max_duration"`
+	LastSeen        time.Time     `json:"last_seen"`
+	Plan            *QueryPlan    `json:"plan,omitempty"`
+}
+
+// slowQueryLog 慢查询环形缓冲区，调用方需持有 DatabaseMonitor 的锁
+type slowQueryLog struct {
+	entries []SlowQueryEntry
+	next    int
+	size    int
+}
+
+// add 添加条目，缓冲区满时覆盖最早的条目
+func (l *slowQueryLog) add(entry SlowQueryEntry) {
+	if len(l.entries) < l.size {
+		l.entries = append(l.entries, entry)
+		return
+	}
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % l.size
+}
+
+// recent 按时间倒序获取最近的条目
+func (l *slowQueryLog) recent(limit int) []SlowQueryEntry {
+	if limit <= 0 || limit > len(l.entries) {
+		limit = len(l.entries)
+	}
+
+	result := make([]SlowQueryEntry, 0, limit)
+	newest := l.next - 1
+	if len(l.entries) < l.size {
+		newest = len(l.entries) - 1
+	}
+	for i := 0; i < limit; i++ {
+		index := (newest - i + len(l.entries)) % len(l.entries)
+		result = append(result, l.entries[index])
+	}
+	return result
+}
+
+// resize 调整容量，保留最近的条目
+func (l *slowQueryLog) resize(size int) {
+	if size <= 0 {
+		size = defaultSlowQueryLogSize
+	}
+
+	recent := l.recent(size)
+	l.entries = make([]SlowQueryEntry, 0, size)
+	for i := len(recent) - 1; i >= 0; i-- {
+		l.entries = append(l.entries, recent[i])
+	}
+	l.next = 0
+	l.size = size
+}
+
+// SetSlowQueryThreshold 设置慢查询阈值，可在运行时调整
+func (dm *DatabaseMonitor) SetSlowQueryThreshold(threshold time.Duration) {
+	atomic.StoreInt64(&dm.slowQueryThreshold, int64(threshold))
+}
+
+// SlowQueryThreshold 获取慢查询阈值
+func (dm *DatabaseMonitor) SlowQueryThreshold() time.Duration {
+	return time.Duration(atomic.LoadInt64(&dm.slowQueryThreshold))
+}
+
+// SetSlowQueryLogSize 设置慢查询日志保留的条目数，默认 200
+func (dm *DatabaseMonitor) SetSlowQueryLogSize(size int) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.slowQueries.resize(size)
+}
+
+// SlowQueries 按时间倒序获取最近的慢查询，limit 不大于 0 时返回全部
+func (dm *DatabaseMonitor) SlowQueries(limit int) []SlowQueryEntry {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	entries := dm.slowQueries.recent(limit)
+	if dm.queryPlanner != nil {
+		for i := range entries {
+			entries[i].Plan = dm.queryPlanner.plan(entries[i].Query)
+		}
+	}
+	return entries
+}
+
+// SlowQueryStats 按归一化查询聚合慢查询日志，按总耗时降序排列，limit 不大于 0 时返回全部
+func (dm *DatabaseMonitor) SlowQueryStats(limit int) []SlowQueryStat {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+
+	byQuery := make(map[string]*SlowQueryStat)
+	for _, entry := range dm.slowQueries.entries {
+		stat, ok := byQuery[entry.Query]
+		if !ok {
+			stat = &SlowQueryStat{Query: entry.Query}
+			byQuery[entry.Query] = stat
+		}
+		stat.Count++
+		stat.TotalDuration += entry.Duration
+		if entry.Duration > stat.MaxDuration {
+			stat.MaxDuration = entry.Duration
+		}
+		if entry.Timestamp.After(stat.LastSeen) {
+			stat.LastSeen = entry.Timestamp
+		}
+	}
+
+	stats := make([]SlowQueryStat, 0, len(byQuery))
+	for _, stat := range byQuery {
+		stat.AverageDuration = stat.TotalDuration / time.Duration(stat.Count)
+		if dm.queryPlanner != nil {
+			stat.Plan = dm.queryPlanner.plan(stat.Query)
+		}
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalDuration != stats[j].TotalDuration {
+			return stats[i].TotalDuration > stats[j].TotalDuration
+		}
+		return stats[i].Query < stats[j].Query
+	})
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats
+}
@@ -0,0 +1,150 @@
+package performance
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrMetricNotFound 指标未注册
+var ErrMetricNotFound = errors.New("metric not found")
+
+// MetricSample 指标在快照时刻的值
+//
+// 计数器和仪表使用 Value；直方图和摘要使用 Sum、Count 以及 Buckets 或 Quantiles。
+type MetricSample struct {
+	Name      string              `json:"name"`
+	Type      MetricType          `json:"type"`
+	Labels    map[string]string   `json:"labels,omitempty"`
+	Value     float64             `json:"value"`
+	Sum       float64             `json:"sum,omitempty"`
+	Count     int64               `json:"count,omitempty"`
+	Buckets   map[float64]int64   `json:"-"`
+	Quantiles map[float64]float64 `json:"-"`
+}
+
+// MetricsSnapshot 所有指标在某一时刻的只读副本
+type MetricsSnapshot struct {
+	Timestamp time.Time               `json:"timestamp"`
+	Metrics   map[string]MetricSample `json:"metrics"`
+}
+
+// Get 获取指标的值
+func (s *MetricsSnapshot) Get(name string) (MetricSample, bool) {
+	sample, ok := s.Metrics[name]
+	return sample, ok
+}
+
+// Diff 计算自 previous 快照以来各指标的变化量，只包含两个快照中都存在的指标
+func (s *MetricsSnapshot) Diff(previous *MetricsSnapshot) *SnapshotDiff {
+	diff := &SnapshotDiff{
+		Start:    previous.Timestamp,
+		End:      s.Timestamp,
+		Duration: s.Timestamp.Sub(previous.Timestamp),
+		Deltas:   make(map[string]MetricDelta, len(s.Metrics)),
+	}
+
+	for name, current := range s.Metrics {
+		before, ok := previous.Metrics[name]
+		if !ok || before.Type != current.Type {
+			continue
+		}
+
+		delta := MetricDelta{
+			Name:       name,
+			Type:       current.Type,
+			Delta:      current.Value - before.Value,
+			SumDelta:   current.Sum - before.Sum,
+			CountDelta: current.Count - before.Count,
+		}
+		// 累计值变小说明期间被重置过，变化量按重置后的值计算
+		if current.Type == MetricTypeCounter && delta.Delta < 0 {
+			delta.Delta = current.Value
+		}
+		if delta.CountDelta < 0 {
+			delta.SumDelta = current.Sum
+			delta.CountDelta = current.Count
+		}
+		diff.Deltas[name] = delta
+	}
+	return diff
+}
+
+// MetricDelta 指标在两个快照之间的变化量
+type MetricDelta struct {
+	Name       string     `json:"name"`
+	Type       MetricType `json:"type"`
+	Delta      float64    `json:"delta"`
+	SumDelta   float64    `json:"sum_delta,omitempty"`
+	CountDelta int64      `json:"count_delta,omitempty"`
+}
+
+// SnapshotDiff 两个快照之间的差异
+type SnapshotDiff struct {
+	Start    time.Time              `json:"start"`
+	End      time.Time              `json:"end"`
+	Duration time.Duration          `json:"duration"`
+	Deltas   map[string]MetricDelta `json:"deltas"`
+}
+
+// Get 获取指标的变化量
+func (d *SnapshotDiff) Get(name string) (MetricDelta, bool) {
+	delta, ok := d.Deltas[name]
+	return delta, ok
+}
+
+// Delta 获取计数器或仪表的变化量，直方图和摘要返回观察次数的变化量
+func (d *SnapshotDiff) Delta(name string) float64 {
+	delta, ok := d.Deltas[name]
+	if !ok {
+		return 0
+	}
+	if delta.Type == MetricTypeHistogram || delta.Type == MetricTypeSummary {
+		return float64(delta.CountDelta)
+	}
+	return delta.Delta
+}
+
+// Rate 获取指标在两个快照之间每秒的平均变化量
+func (d *SnapshotDiff) Rate(name string) float64 {
+	if d.Duration <= 0 {
+		return 0
+	}
+	return d.Delta(name) / d.Duration.Seconds()
+}
+
+// sampleMetric 读取指标的当前值，各指标在自身的锁内读取，与并发更新互不干扰
+func sampleMetric(metric Metric) MetricSample {
+	sample := MetricSample{
+		Name: metric.Name(),
+		Type: metric.Type(),
+	}
+	if labels := metric.Labels(); labels != nil {
+		sample.Labels = make(map[string]string, len(labels))
+		for name, value := range labels {
+			sample.Labels[name] = value
+		}
+	}
+
+	switch m := metric.(type) {
+	case *Histogram:
+		buckets, sum, count := m.snapshot()
+		sample.Sum = sum
+		sample.Count = count
+		sample.Buckets = make(map[float64]int64, len(buckets))
+		for _, bucket := range buckets {
+			sample.Buckets[bucket.upperBound] = bucket.count
+		}
+	case *Summary:
+		sample.Quantiles, sample.Sum, sample.Count = m.snapshot()
+	default:
+		switch value := metric.Value().(type) {
+		case int64:
+			sample.Value = float64(value)
+		case int:
+			sample.Value = float64(value)
+		case float64:
+			sample.Value = value
+		}
+	}
+	return sample
+}
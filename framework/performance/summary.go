@@ -0,0 +1,351 @@
+package performance
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultSummaryObjectives 默认的分位数及其允许的秩误差
+var DefaultSummaryObjectives = map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
+
+const (
+	// DefaultSummaryMaxAge 默认的分位数统计时间窗口
+	DefaultSummaryMaxAge = 10 * time.Minute
+	// DefaultSummaryAgeBuckets 默认的时间窗口分段数
+	DefaultSummaryAgeBuckets = 5
+
+	// summaryBufferSize 批量合并到分位数流前缓冲的观察值数量
+	summaryBufferSize = 500
+)
+
+// WithSummaryMaxAge 设置摘要分位数的统计时间窗口，超过窗口的观察值不再参与分位数计算
+func WithSummaryMaxAge(maxAge time.Duration) MetricOption {
+	return func(o *metricOptions) {
+		o.maxAge = maxAge
+	}
+}
+
+// WithSummaryAgeBuckets 设置时间窗口的分段数，分段越多旧数据淘汰越平滑
+func WithSummaryAgeBuckets(buckets int) MetricOption {
+	return func(o *metricOptions) {
+		o.ageBuckets = buckets
+	}
+}
+
+// Summary 摘要指标
+//
+// 与直方图不同，摘要不需要预先定义桶，而是以流式方式近似计算分位数（CKMS 算法），
+// 适合分布未知的延迟等数据。分位数只统计最近 maxAge 内的观察值：窗口被划分为若干分段，
+// 每个观察值写入所有分段的分位数流，查询时使用最早的分段；分段依次间隔 maxAge/分段数 到期，
+// 到期的分段被清空并轮换到队尾。
+// 总和与观察次数与 Prometheus 的语义一致，为累计值。
+type Summary struct {
+	name        string
+	help        string
+	labels      map[string]string
+	objectives  []float64
+	streams     []*quantileStream
+	head        int
+	headExpires time.Time
+	segment     time.Duration
+	sum         float64
+	count       int64
+	timestamp   time.Time
+	clock       func() time.Time
+	mu          sync.Mutex
+}
+
+// NewSummary 创建摘要，objectives 为分位数及其允许的秩误差，为空时使用 DefaultSummaryObjectives
+func NewSummary(name string, objectives map[float64]float64, labels map[string]string, options ...MetricOption) *Summary {
+	o := applyMetricOptions(options)
+	if len(objectives) == 0 {
+		objectives = DefaultSummaryObjectives
+	}
+	maxAge := o.maxAge
+	if maxAge <= 0 {
+		maxAge = DefaultSummaryMaxAge
+	}
+	ageBuckets := o.ageBuckets
+	if ageBuckets <= 0 {
+		ageBuckets = DefaultSummaryAgeBuckets
+	}
+
+	targets := make([]quantileTarget, 0, len(objectives))
+	quantiles := make([]float64, 0, len(objectives))
+	for quantile, epsilon := range objectives {
+		targets = append(targets, quantileTarget{quantile: quantile, epsilon: epsilon})
+		quantiles = append(quantiles, quantile)
+	}
+	sort.Float64s(quantiles)
+
+	streams := make([]*quantileStream, ageBuckets)
+	for i := range streams {
+		streams[i] = newQuantileStream(targets)
+	}
+
+	now := time.Now()
+	return &Summary{
+		name:        SanitizeMetricName(name),
+		help:        o.help,
+		labels:      sanitizeLabels(labels),
+		objectives:  quantiles,
+		streams:     streams,
+		headExpires: now.Add(maxAge / time.Duration(ageBuckets)),
+		segment:     maxAge / time.Duration(ageBuckets),
+		timestamp:   now,
+		clock:       time.Now,
+	}
+}
+
+// SetClock 设置时钟，用于测试
+func (s *Summary) SetClock(clock func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = clock
+	s.headExpires = clock().Add(s.segment)
+}
+
+func (s *Summary) Name() string {
+	return s.name
+}
+
+func (s *Summary) Type() MetricType {
+	return MetricTypeSummary
+}
+
+// Help 获取帮助说明
+func (s *Summary) Help() string {
+	return s.help
+}
+
+func (s *Summary) Value() interface{} {
+	quantiles, sum, count := s.snapshot()
+	return map[string]interface{}{
+		"quantiles": quantiles,
+		"sum":       sum,
+		"count":     count,
+	}
+}
+
+func (s *Summary) Labels() map[string]string {
+	return s.labels
+}
+
+func (s *Summary) Timestamp() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.timestamp
+}
+
+// Objectives 获取配置的分位数，按升序排列
+func (s *Summary) Objectives() []float64 {
+	return append([]float64(nil), s.objectives...)
+}
+
+// Observe 观察值
+func (s *Summary) Observe(value float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock()
+	s.rotate(now)
+	for _, stream := range s.streams {
+		stream.insert(value)
+	}
+	s.sum += value
+	s.count++
+	s.timestamp = now
+}
+
+// Quantile 获取时间窗口内的分位数，窗口内没有观察值时返回 NaN
+func (s *Summary) Quantile(q float64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rotate(s.clock())
+	return s.streams[s.head].query(q)
+}
+
+// Reset 清空所有观察值
+func (s *Summary) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stream := range s.streams {
+		stream.reset()
+	}
+	s.sum = 0
+	s.count = 0
+	s.headExpires = s.clock().Add(s.segment)
+	s.timestamp = s.clock()
+}
+
+// snapshot 获取配置的分位数、总和和观察次数
+func (s *Summary) snapshot() (map[float64]float64, float64, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rotate(s.clock())
+	quantiles := make(map[float64]float64, len(s.objectives))
+	for _, q := range s.objectives {
+		quantiles[q] = s.streams[s.head].query(q)
+	}
+	return quantiles, s.sum, s.count
+}
+
+// rotate 清空已到期的最早分段并轮换，长时间没有观察时所有分段都会被清空
+func (s *Summary) rotate(now time.Time) {
+	for rotated := 0; !now.Before(s.headExpires); rotated++ {
+		if rotated == len(s.streams) {
+			s.headExpires = now.Add(s.segment)
+			return
+		}
+		s.streams[s.head].reset()
+		s.head = (s.head + 1) % len(s.streams)
+		s.headExpires = s.headExpires.Add(s.segment)
+	}
+}
+
+// quantileTarget 目标分位数及其允许的秩误差
+type quantileTarget struct {
+	quantile float64
+	epsilon  float64
+}
+
+// quantileSample 分位数流中的样本，width 为样本代表的观察值数量，delta 为秩的不确定范围
+type quantileSample struct {
+	value float64
+	width float64
+	delta float64
+}
+
+// quantileStream 针对目标分位数的 CKMS 流式分位数估计
+//
+// 只保留满足误差约束所需的样本，内存占用与观察值数量近似呈对数关系。
+type quantileStream struct {
+	targets []quantileTarget
+	samples []quantileSample
+	buffer  []float64
+	n       float64
+}
+
+// newQuantileStream 创建分位数流
+func newQuantileStream(targets []quantileTarget) *quantileStream {
+	return &quantileStream{
+		targets: targets,
+		buffer:  make([]float64, 0, summaryBufferSize),
+	}
+}
+
+// insert 插入观察值，缓冲区满时批量合并
+func (s *quantileStream) insert(value float64) {
+	s.buffer = append(s.buffer, value)
+	if len(s.buffer) == cap(s.buffer) {
+		s.flush()
+	}
+}
+
+// query 查询分位数
+func (s *quantileStream) query(q float64) float64 {
+	s.flush()
+	if len(s.samples) == 0 {
+		return math.NaN()
+	}
+
+	rank := math.Ceil(q * s.n)
+	rank += math.Ceil(s.invariant(rank) / 2)
+	previous := s.samples[0]
+	var r float64
+	for _, sample := range s.samples[1:] {
+		r += previous.width
+		if r+sample.width+sample.delta > rank {
+			return previous.value
+		}
+		previous = sample
+	}
+	return previous.value
+}
+
+// reset 清空样本
+func (s *quantileStream) reset() {
+	s.samples = s.samples[:0]
+	s.buffer = s.buffer[:0]
+	s.n = 0
+}
+
+// invariant 秩为 r 处允许的误差，取所有目标分位数约束中最严格的一个
+func (s *quantileStream) invariant(r float64) float64 {
+	bound := math.MaxFloat64
+	for _, target := range s.targets {
+		var f float64
+		if target.quantile*s.n <= r {
+			f = 2 * target.epsilon * r / target.quantile
+		} else {
+			f = 2 * target.epsilon * (s.n - r) / (1 - target.quantile)
+		}
+		if f < bound {
+			bound = f
+		}
+	}
+	return bound
+}
+
+// flush 将缓冲区中排序后的观察值合并到样本中并压缩
+func (s *quantileStream) flush() {
+	if len(s.buffer) == 0 {
+		return
+	}
+	sort.Float64s(s.buffer)
+
+	var r float64
+	i := 0
+	for _, value := range s.buffer {
+		inserted := false
+		for ; i < len(s.samples); i++ {
+			if s.samples[i].value > value {
+				s.samples = append(s.samples, quantileSample{})
+				copy(s.samples[i+1:], s.samples[i:])
+				s.samples[i] = quantileSample{value: value, width: 1, delta: math.Max(0, math.Floor(s.invariant(r))-1)}
+				inserted = true
+				break
+			}
+			r += s.samples[i].width
+		}
+		if !inserted {
+			s.samples = append(s.samples, quantileSample{value: value, width: 1})
+		}
+		i++
+		s.n++
+		r++
+	}
+	s.buffer = s.buffer[:0]
+	s.compress()
+}
+
+// compress 合并误差约束允许合并的相邻样本
+func (s *quantileStream) compress() {
+	if len(s.samples) < 2 {
+		return
+	}
+
+	last := len(s.samples) - 1
+	x := s.samples[last]
+	xi := last
+	r := s.n - 1 - x.width
+	for i := last - 1; i >= 0; i-- {
+		c := s.samples[i]
+		if c.width+x.width+x.delta <= s.invariant(r) {
+			x.width += c.width
+			s.samples[xi] = x
+			copy(s.samples[i:], s.samples[i+1:])
+			s.samples = s.samples[:len(s.samples)-1]
+			xi--
+		} else {
+			x = c
+			xi = i
+		}
+		r -= c.width
+	}
+}
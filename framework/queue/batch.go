@@ -0,0 +1,130 @@
+package queue
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// JobBatch 任务批次，所有任务结束后触发回调
+//
+// 批次ID随任务标签传递，完成计数保存在 BatchStore 中，任何进程的工作进程都可以更新批次；
+// Then 和 Catch 回调只在发起批次的进程中触发，其他进程可以通过 GetStats 查询进度。
+type JobBatch struct {
+	id      string
+	jobs    []Job
+	queue   Queue
+	onThen  func(*JobBatch)
+	onCatch func(*JobBatch, Job, error)
+}
+
+// BatchStats 批次统计
+type BatchStats struct {
+	ID          string `json:"id"`
+	TotalJobs   int    `json:"total_jobs"`
+	PendingJobs int    `json:"pending_jobs"`
+	FailedJobs  int    `json:"failed_jobs"`
+	Finished    bool   `json:"finished"`
+}
+
+// Batch 创建任务批次
+func Batch(jobs []Job) *JobBatch {
+	return &JobBatch{
+		id:   uuid.New().String(),
+		jobs: jobs,
+	}
+}
+
+// OnQueue 设置批次推送的队列，默认使用全局队列管理器的默认队列
+func (b *JobBatch) OnQueue(queue Queue) *JobBatch {
+	b.queue = queue
+	return b
+}
+
+// Then 设置所有任务成功完成后的回调
+func (b *JobBatch) Then(callback func(*JobBatch)) *JobBatch {
+	b.onThen = callback
+	return b
+}
+
+// Catch 设置首个任务失败时的回调
+func (b *JobBatch) Catch(callback func(*JobBatch, Job, error)) *JobBatch {
+	b.onCatch = callback
+	return b
+}
+
+// ID 获取批次ID
+func (b *JobBatch) ID() string {
+	return b.id
+}
+
+// Dispatch 推送批次中的所有任务
+func (b *JobBatch) Dispatch() error {
+	if err := getBatchStore().Create(b.id, len(b.jobs)); err != nil {
+		return fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	if len(b.jobs) == 0 {
+		if b.onThen != nil {
+			b.onThen(b)
+		}
+		return nil
+	}
+
+	for _, job := range b.jobs {
+		setJobTag(job, batchTag, b.id)
+	}
+
+	callbacks.mu.Lock()
+	callbacks.batches[b.id] = b
+	callbacks.mu.Unlock()
+
+	for _, job := range b.jobs {
+		if err := b.push(job); err != nil {
+			callbacks.remove("", b.id)
+			getBatchStore().Delete(b.id)
+			return err
+		}
+	}
+	return nil
+}
+
+// GetStats 获取批次统计
+func (b *JobBatch) GetStats() BatchStats {
+	stats, err := getBatchStore().Get(b.id)
+	if err != nil {
+		return BatchStats{ID: b.id, TotalJobs: len(b.jobs), PendingJobs: len(b.jobs)}
+	}
+	return stats
+}
+
+// push 推送任务
+func (b *JobBatch) push(job Job) error {
+	if b.queue != nil {
+		return b.queue.Push(job)
+	}
+	return Push(job)
+}
+
+// finishBatchJob 记录批次中的任务结束，在本进程注册了回调时触发回调
+func finishBatchJob(batchID string, job Job, err error) {
+	progress, storeErr := getBatchStore().Finish(batchID, job.GetID(), err != nil)
+	if storeErr != nil || !progress.Counted {
+		return
+	}
+
+	batch := callbacks.batch(batchID)
+	if progress.Finished {
+		callbacks.remove("", batchID)
+	}
+	if batch == nil {
+		return
+	}
+
+	if progress.FirstFailure && batch.onCatch != nil {
+		batch.onCatch(batch, job, err)
+	}
+	if progress.Finished && progress.FailedJobs == 0 && batch.onThen != nil {
+		batch.onThen(batch)
+	}
+}
@@ -0,0 +1,264 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrBatchNotFound 批次不存在或已过期
+var ErrBatchNotFound = errors.New("batch not found")
+
+// defaultBatchRetention 批次计数在存储中的保留时间
+const defaultBatchRetention = 24 * time.Hour
+
+// BatchProgress 一次任务结束后的批次进度
+type BatchProgress struct {
+	BatchStats
+	// Counted 本次调用计入了任务，同一任务重复上报时为 false
+	Counted bool
+	// FirstFailure 本次调用记录了批次中的首个失败
+	FirstFailure bool
+}
+
+// BatchStore 批次计数存储，任务链和批次的完成计数保存在这里
+//
+// 发起批次的进程和执行任务的工作进程需要使用同一个存储，多进程部署时使用 RedisBatchStore。
+type BatchStore interface {
+	// Create 创建批次计数，total 为任务数
+	Create(batchID string, total int) error
+	// Finish 记录任务结束，同一任务只计数一次
+	Finish(batchID, jobID string, failed bool) (BatchProgress, error)
+	// Get 获取批次统计
+	Get(batchID string) (BatchStats, error)
+	// Delete 删除批次计数
+	Delete(batchID string) error
+}
+
+// MemoryBatchStore 内存批次计数存储，适用于发起批次和执行任务在同一进程的场景
+type MemoryBatchStore struct {
+	mu        sync.Mutex
+	batches   map[string]*memoryBatch
+	retention time.Duration
+}
+
+// memoryBatch 内存中的批次计数
+type memoryBatch struct {
+	stats     BatchStats
+	processed map[string]bool
+	expiresAt time.Time
+}
+
+// NewMemoryBatchStore 创建内存批次计数存储
+func NewMemoryBatchStore() *MemoryBatchStore {
+	return &MemoryBatchStore{
+		batches:   make(map[string]*memoryBatch),
+		retention: defaultBatchRetention,
+	}
+}
+
+// Create 创建批次计数
+func (s *MemoryBatchStore) Create(batchID string, total int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 清理过期的批次
+	now := time.Now()
+	for id, batch := range s.batches {
+		if now.After(batch.expiresAt) {
+			delete(s.batches, id)
+		}
+	}
+
+	s.batches[batchID] = &memoryBatch{
+		stats: BatchStats{
+			ID:          batchID,
+			TotalJobs:   total,
+			PendingJobs: total,
+			Finished:    total == 0,
+		},
+		processed: make(map[string]bool),
+		expiresAt: now.Add(s.retention),
+	}
+	return nil
+}
+
+// Finish 记录任务结束
+func (s *MemoryBatchStore) Finish(batchID, jobID string, failed bool) (BatchProgress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch, exists := s.batches[batchID]
+	if !exists {
+		return BatchProgress{}, ErrBatchNotFound
+	}
+	if batch.processed[jobID] || batch.stats.PendingJobs == 0 {
+		return BatchProgress{BatchStats: batch.stats}, nil
+	}
+
+	batch.processed[jobID] = true
+	batch.stats.PendingJobs--
+	batch.stats.Finished = batch.stats.PendingJobs == 0
+	progress := BatchProgress{Counted: true}
+	if failed {
+		batch.stats.FailedJobs++
+		progress.FirstFailure = batch.stats.FailedJobs == 1
+	}
+	progress.BatchStats = batch.stats
+	return progress, nil
+}
+
+// Get 获取批次统计
+func (s *MemoryBatchStore) Get(batchID string) (BatchStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	batch, exists := s.batches[batchID]
+	if !exists {
+		return BatchStats{}, ErrBatchNotFound
+	}
+	return batch.stats, nil
+}
+
+// Delete 删除批次计数
+func (s *MemoryBatchStore) Delete(batchID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.batches, batchID)
+	return nil
+}
+
+// 计数在同一个脚本中原子更新，已处理的任务记录在集合中，重复上报不会重复计数
+var redisBatchFinishScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+local counted = redis.call("SADD", KEYS[2], ARGV[1])
+redis.call("PEXPIRE", KEYS[2], ARGV[3])
+local pending = tonumber(redis.call("HGET", KEYS[1], "pending"))
+if counted == 0 or pending == 0 then
+	return 0
+end
+redis.call("HINCRBY", KEYS[1], "pending", -1)
+if ARGV[2] == "1" then
+	if redis.call("HINCRBY", KEYS[1], "failed", 1) == 1 then
+		return 2
+	end
+end
+return 1`)
+
+// RedisBatchStore 基于 Redis 的批次计数存储，多个进程的工作进程共享批次进度
+type RedisBatchStore struct {
+	client    redis.UniversalClient
+	prefix    string
+	retention time.Duration
+}
+
+// NewRedisBatchStore 创建 Redis 批次计数存储，prefix 会添加到所有键前
+func NewRedisBatchStore(client redis.UniversalClient, prefix string) *RedisBatchStore {
+	return &RedisBatchStore{
+		client:    client,
+		prefix:    prefix,
+		retention: defaultBatchRetention,
+	}
+}
+
+// Create 创建批次计数
+func (s *RedisBatchStore) Create(batchID string, total int) error {
+	ctx := context.Background()
+	key := s.key(batchID)
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "total", total, "pending", total, "failed", 0)
+		pipe.PExpire(ctx, key, s.retention)
+		return nil
+	})
+	return err
+}
+
+// Finish 记录任务结束
+func (s *RedisBatchStore) Finish(batchID, jobID string, failed bool) (BatchProgress, error) {
+	ctx := context.Background()
+	failedFlag := "0"
+	if failed {
+		failedFlag = "1"
+	}
+
+	keys := []string{s.key(batchID), s.key(batchID) + ":jobs"}
+	result, err := redisBatchFinishScript.Run(ctx, s.client, keys, jobID, failedFlag, s.retention.Milliseconds()).Int()
+	if err != nil {
+		return BatchProgress{}, fmt.Errorf("failed to update batch %s: %w", batchID, err)
+	}
+	if result < 0 {
+		return BatchProgress{}, ErrBatchNotFound
+	}
+
+	stats, err := s.Get(batchID)
+	if err != nil {
+		return BatchProgress{}, err
+	}
+	return BatchProgress{
+		BatchStats:   stats,
+		Counted:      result > 0,
+		FirstFailure: result == 2,
+	}, nil
+}
+
+// Get 获取批次统计
+func (s *RedisBatchStore) Get(batchID string) (BatchStats, error) {
+	values, err := s.client.HGetAll(context.Background(), s.key(batchID)).Result()
+	if err != nil {
+		return BatchStats{}, err
+	}
+	if len(values) == 0 {
+		return BatchStats{}, ErrBatchNotFound
+	}
+
+	total, _ := strconv.Atoi(values["total"])
+	pending, _ := strconv.Atoi(values["pending"])
+	failed, _ := strconv.Atoi(values["failed"])
+	return BatchStats{
+		ID:          batchID,
+		TotalJobs:   total,
+		PendingJobs: pending,
+		FailedJobs:  failed,
+		Finished:    pending == 0,
+	}, nil
+}
+
+// Delete 删除批次计数
+func (s *RedisBatchStore) Delete(batchID string) error {
+	return s.client.Del(context.Background(), s.key(batchID), s.key(batchID)+":jobs").Err()
+}
+
+// key 批次计数的键
+func (s *RedisBatchStore) key(batchID string) string {
+	return s.prefix + "batch:" + batchID
+}
+
+var (
+	defaultBatchStore   BatchStore
+	defaultBatchStoreMu sync.Mutex
+)
+
+// SetBatchStore 设置任务链和批次使用的计数存储
+func SetBatchStore(store BatchStore) {
+	defaultBatchStoreMu.Lock()
+	defer defaultBatchStoreMu.Unlock()
+	defaultBatchStore = store
+}
+
+// getBatchStore 获取计数存储，未设置时使用内存存储
+func getBatchStore() BatchStore {
+	defaultBatchStoreMu.Lock()
+	defer defaultBatchStoreMu.Unlock()
+	if defaultBatchStore == nil {
+		defaultBatchStore = NewMemoryBatchStore()
+	}
+	return defaultBatchStore
+}
@@ -31,6 +31,10 @@ func NewBeanstalkdQueue(config BeanstalkdConfig) (*BeanstalkdQueue, error) {
 
 // Push 推送任务
 func (bq *BeanstalkdQueue) Push(job Job) error {
+	if err := rejectEncryptedJob(job, "beanstalkd"); err != nil {
+		return err
+	}
+
 	// TODO: 实现 Beanstalkd 任务推送
 	bq.stats.TotalJobs++
 	bq.stats.LastJobAt = time.Now()
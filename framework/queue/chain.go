@@ -0,0 +1,281 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// 任务链和批次使用的任务标签
+const (
+	chainTag     = "chain_id"
+	chainNextTag = "chain_next"
+	batchTag     = "batch_id"
+)
+
+// jobPusher 推进任务链时推送下一个任务的队列
+type jobPusher interface {
+	Push(job Job) error
+}
+
+// workflowCallbacks 任务链和批次的回调
+//
+// 进度保存在任务标签和 BatchStore 中，任何进程的工作进程都可以推进任务链和更新批次计数；
+// 回调函数无法序列化，只在注册回调的进程中触发。
+type workflowCallbacks struct {
+	mu      sync.Mutex
+	chains  map[string]*JobChain
+	batches map[string]*JobBatch
+}
+
+// callbacks 本进程注册的任务链和批次回调
+var callbacks = &workflowCallbacks{
+	chains:  make(map[string]*JobChain),
+	batches: make(map[string]*JobBatch),
+}
+
+// chain 获取本进程注册的任务链
+func (r *workflowCallbacks) chain(id string) *JobChain {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.chains[id]
+}
+
+// batch 获取本进程注册的批次
+func (r *workflowCallbacks) batch(id string) *JobBatch {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.batches[id]
+}
+
+// remove 移除已结束的任务链或批次的回调
+func (r *workflowCallbacks) remove(chainID, batchID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if chainID != "" {
+		delete(r.chains, chainID)
+	}
+	if batchID != "" {
+		delete(r.batches, batchID)
+	}
+}
+
+// completeWorkflowJob 任务成功完成，推进任务链并更新批次，下一个任务推送到 queue
+func completeWorkflowJob(queue jobPusher, job Job) {
+	tags := job.GetTags()
+	if chainID := tags[chainTag]; chainID != "" {
+		advanceChain(queue, chainID, job)
+	}
+	if batchID := tags[batchTag]; batchID != "" {
+		finishBatchJob(batchID, job, nil)
+	}
+}
+
+// failWorkflowJob 任务执行失败，终止任务链并更新批次
+func failWorkflowJob(job Job, err error) {
+	tags := job.GetTags()
+	if chainID := tags[chainTag]; chainID != "" {
+		failChain(chainID, job, err)
+	}
+	if batchID := tags[batchTag]; batchID != "" {
+		finishBatchJob(batchID, job, err)
+	}
+}
+
+// advanceChain 记录任务完成并推送任务标签中携带的下一个任务
+func advanceChain(queue jobPusher, chainID string, job Job) {
+	progress, err := getBatchStore().Finish(chainID, job.GetID(), false)
+	if err == nil && !progress.Counted {
+		// 重复上报的任务，下一个任务已经推送过
+		return
+	}
+
+	remaining, err := decodeChainJobs(job.GetTags()[chainNextTag])
+	if err != nil {
+		failChain(chainID, job, err)
+		return
+	}
+	if len(remaining) == 0 {
+		callbacks.remove(chainID, "")
+		return
+	}
+
+	next, err := restoreChainJob(remaining)
+	if err != nil {
+		failChain(chainID, job, err)
+		return
+	}
+	if err := queue.Push(next); err != nil {
+		failChain(chainID, next, err)
+	}
+}
+
+// failChain 记录任务失败，任务链在首个失败时终止
+func failChain(chainID string, job Job, err error) {
+	progress, storeErr := getBatchStore().Finish(chainID, job.GetID(), true)
+	if storeErr == nil && !progress.FirstFailure {
+		return
+	}
+
+	chain := callbacks.chain(chainID)
+	callbacks.remove(chainID, "")
+	if chain != nil && chain.onCatch != nil {
+		chain.onCatch(job, err)
+	}
+}
+
+// encodeChainJobs 序列化任务链中剩余的任务，保存在任务标签中
+//
+// 需要加密的任务先加密载荷，标签中只保存密文，nonce 和加密标记随任务一起序列化。
+func encodeChainJobs(jobs []Job) (string, error) {
+	encoded := make([]json.RawMessage, 0, len(jobs))
+	for _, job := range jobs {
+		if err := encryptJob(job); err != nil {
+			return "", err
+		}
+		data, err := job.Serialize()
+		if err != nil {
+			return "", err
+		}
+		encoded = append(encoded, data)
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode chain jobs: %w", err)
+	}
+	return string(data), nil
+}
+
+// decodeChainJobs 解析任务标签中的剩余任务
+func decodeChainJobs(value string) ([]json.RawMessage, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var jobs []json.RawMessage
+	if err := json.Unmarshal([]byte(value), &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode chain jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// restoreChainJob 还原剩余任务中的第一个任务，其余任务写入它的标签
+//
+// 已加密的任务保持密文和加密标记，由工作进程使用配置的加密器解密。
+func restoreChainJob(remaining []json.RawMessage) (*BaseJob, error) {
+	next := &BaseJob{}
+	if err := next.Deserialize(remaining[0]); err != nil {
+		return nil, err
+	}
+
+	next.RemoveTag(chainNextTag)
+	if len(remaining) > 1 {
+		data, err := json.Marshal(remaining[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode chain jobs: %w", err)
+		}
+		next.AddTag(chainNextTag, string(data))
+	}
+	return next, nil
+}
+
+// setJobTag 设置任务标签
+func setJobTag(job Job, key, value string) {
+	if tagged, ok := job.(interface{ AddTag(key, value string) }); ok {
+		tagged.AddTag(key, value)
+		return
+	}
+	if tags := job.GetTags(); tags != nil {
+		tags[key] = value
+	}
+}
+
+// JobChain 任务链，前一个任务成功完成后才推送下一个任务
+//
+// 链ID和剩余的任务随任务标签传递，完成计数保存在 BatchStore 中，任何进程的工作进程都可以推进任务链，
+// 后续任务推送到执行前一个任务的工作进程所在的队列。后续任务以 BaseJob 的形式还原。
+type JobChain struct {
+	id      string
+	jobs    []Job
+	queue   Queue
+	onCatch func(Job, error)
+}
+
+// Chain 创建任务链
+func Chain(jobs ...Job) *JobChain {
+	return &JobChain{
+		id:   uuid.New().String(),
+		jobs: jobs,
+	}
+}
+
+// OnQueue 设置任务链推送的队列，默认使用全局队列管理器的默认队列
+func (c *JobChain) OnQueue(queue Queue) *JobChain {
+	c.queue = queue
+	return c
+}
+
+// Catch 设置任务失败回调，任务链在失败时终止，回调只在发起任务链的进程中触发
+func (c *JobChain) Catch(callback func(Job, error)) *JobChain {
+	c.onCatch = callback
+	return c
+}
+
+// ID 获取任务链ID
+func (c *JobChain) ID() string {
+	return c.id
+}
+
+// Dispatch 推送任务链的第一个任务
+func (c *JobChain) Dispatch() error {
+	if len(c.jobs) == 0 {
+		return nil
+	}
+
+	for _, job := range c.jobs {
+		setJobTag(job, chainTag, c.id)
+	}
+	if len(c.jobs) > 1 {
+		remaining, err := encodeChainJobs(c.jobs[1:])
+		if err != nil {
+			return err
+		}
+		setJobTag(c.jobs[0], chainNextTag, remaining)
+	}
+
+	if err := getBatchStore().Create(c.id, len(c.jobs)); err != nil {
+		return fmt.Errorf("failed to create chain: %w", err)
+	}
+
+	callbacks.mu.Lock()
+	callbacks.chains[c.id] = c
+	callbacks.mu.Unlock()
+
+	if err := c.push(c.jobs[0]); err != nil {
+		callbacks.remove(c.id, "")
+		getBatchStore().Delete(c.id)
+		return err
+	}
+	return nil
+}
+
+// IsFinished 检查任务链是否已结束（全部完成或因失败终止）
+func (c *JobChain) IsFinished() bool {
+	stats, err := getBatchStore().Get(c.id)
+	if err != nil {
+		return false
+	}
+	return stats.Finished || stats.FailedJobs > 0
+}
+
+// push 推送任务
+func (c *JobChain) push(job Job) error {
+	if c.queue != nil {
+		return c.queue.Push(job)
+	}
+	return Push(job)
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/consul/api"
+
+	"laravel-go/framework/cluster"
 )
 
 // ConsulCluster Consul集群实现（复用定时器的实现）
@@ -18,6 +21,9 @@ type ConsulCluster struct {
 	stopChan     chan struct{}
 	electionChan chan bool
 	sessionID    string
+	nodeSession  string
+	membership   cluster.Membership
+	watchOnce    sync.Once
 }
 
 // ConsulClusterConfig Consul集群配置
@@ -64,30 +70,29 @@ func (cc *ConsulCluster) Register(nodeID string, info NodeInfo) error {
 
 	key := fmt.Sprintf("queue/nodes/%s", nodeID)
 
-	// 创建会话
-	session, _, err := cc.client.Session().Create(&api.SessionEntry{
-		Name:     fmt.Sprintf("queue-node-%s", nodeID),
-		Behavior: "delete",
-		TTL:      "30s",
-	}, nil)
-	if err != nil {
-		return err
+	// 创建会话，心跳重复注册时复用同一个会话
+	if cc.nodeSession == "" {
+		session, _, err := cc.client.Session().Create(&api.SessionEntry{
+			Name:     fmt.Sprintf("queue-node-%s", nodeID),
+			Behavior: "delete",
+			TTL:      "30s",
+		}, nil)
+		if err != nil {
+			return err
+		}
+		cc.nodeSession = session
+
+		// 保持会话活跃
+		go cc.keepSessionAlive(session)
 	}
 
 	// 设置节点信息，使用会话
 	_, err = cc.client.KV().Put(&api.KVPair{
 		Key:     key,
 		Value:   data,
-		Session: session,
+		Session: cc.nodeSession,
 	}, nil)
-	if err != nil {
-		return err
-	}
-
-	// 保持会话活跃
-	go cc.keepSessionAlive(session)
-
-	return nil
+	return err
 }
 
 // Unregister 注销节点
@@ -339,7 +344,8 @@ func (cc *ConsulCluster) Subscribe(callback func(ClusterMessage)) error {
 	}
 
 	go func() {
-		plan.Run("localhost:8500")
+		// 复用集群的客户端，监听使用配置的 Consul 地址和凭据
+		plan.RunWithClientAndHclog(cc.client, nil)
 	}()
 
 	return nil
@@ -349,6 +355,12 @@ func (cc *ConsulCluster) Subscribe(callback func(ClusterMessage)) error {
 func (cc *ConsulCluster) Close() error {
 	cc.cancel()
 	close(cc.stopChan)
+
+	// 销毁节点会话，节点键随之删除
+	if cc.nodeSession != "" {
+		cc.client.Session().Destroy(cc.nodeSession, nil)
+	}
+
 	return nil
 }
 
@@ -395,3 +407,63 @@ func (cc *ConsulCluster) GetClusterInfo() (map[string]interface{}, error) {
 
 	return info, nil
 }
+
+// OnNodeJoin 注册节点加入回调
+func (cc *ConsulCluster) OnNodeJoin(callback func(NodeInfo)) error {
+	cc.membership.AddJoin(callback)
+	return cc.startNodeWatch()
+}
+
+// OnNodeLeave 注册节点离开回调
+func (cc *ConsulCluster) OnNodeLeave(callback func(NodeInfo)) error {
+	cc.membership.AddLeave(callback)
+	return cc.startNodeWatch()
+}
+
+// startNodeWatch 监听节点变更，会话过期时 Consul 会删除节点键
+func (cc *ConsulCluster) startNodeWatch() error {
+	var watchErr error
+	cc.watchOnce.Do(func() {
+		watchErr = cc.watchNodes()
+	})
+	return watchErr
+}
+
+// watchNodes 监听节点列表
+func (cc *ConsulCluster) watchNodes() error {
+	plan, err := api.Watch(&api.WatchParams{
+		Type:   "keyprefix",
+		Prefix: "queue/nodes/",
+		Handler: func(idx uint64, raw interface{}) {
+			pairs, ok := raw.(api.KVPairs)
+			if !ok {
+				return
+			}
+
+			cc.membership.Sync(decodeConsulNodes(pairs))
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		// 复用集群的客户端，监听使用配置的 Consul 地址和凭据
+		plan.RunWithClientAndHclog(cc.client, nil)
+	}()
+
+	return nil
+}
+
+// decodeConsulNodes 解析节点列表
+func decodeConsulNodes(pairs api.KVPairs) []NodeInfo {
+	nodes := make([]NodeInfo, 0, len(pairs))
+	for _, pair := range pairs {
+		var node NodeInfo
+		if err := json.Unmarshal(pair.Value, &node); err != nil {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
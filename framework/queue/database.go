@@ -43,6 +43,10 @@ func NewDatabaseQueue(config DatabaseConfig) (*DatabaseQueue, error) {
 
 // Push 推送任务
 func (dq *DatabaseQueue) Push(job Job) error {
+	if err := rejectEncryptedJob(job, "database"); err != nil {
+		return err
+	}
+
 	// TODO: 实现数据库任务插入
 	dq.stats.TotalJobs++
 	dq.stats.LastJobAt = time.Now()
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
+
+	"laravel-go/framework/cluster"
 )
 
 // DistributedQueue 分布式队列
@@ -18,45 +21,47 @@ type DistributedQueue struct {
 	electionMu   sync.Mutex
 	stopChan     chan struct{}
 	workerPool   *DistributedWorkerPool
-}
-
-// Cluster 集群接口（复用定时器的集群接口）
-type Cluster interface {
-	// 节点管理
-	Register(nodeID string, info NodeInfo) error
-	Unregister(nodeID string) error
-	GetNodes() ([]NodeInfo, error)
-	
-	// 分布式锁
-	AcquireLock(key string, ttl time.Duration) (bool, error)
-	ReleaseLock(key string) error
-	
-	// 选举
-	StartElection(callback func(bool)) error
-	StopElection() error
-	
-	// 消息广播
-	Broadcast(msg ClusterMessage) error
-	Subscribe(callback func(ClusterMessage)) error
-}
+	nodeInfo     NodeInfo
+	nodeMu       sync.Mutex
+
+	heartbeatInterval time.Duration
+
+	// 任务窃取
+	workStealing   bool
+	stealBatchSize int
+	stealThreshold int
+	stealInterval  time.Duration
+	stolen         *MemoryQueue
+	lent           map[string]*BaseJob
+	lentMu         sync.Mutex
+}
+
+// 集群接口和内存集群实现与队列、调度器共用，定义在 cluster 包中
+type (
+	// Cluster 集群接口
+	Cluster = cluster.Cluster
+	// ContextSubscriber 支持取消订阅的集群
+	ContextSubscriber = cluster.ContextSubscriber
+	// NodeInfo 节点信息
+	NodeInfo = cluster.NodeInfo
+	// ClusterMessage 集群消息
+	ClusterMessage = cluster.ClusterMessage
+	// MemoryCluster 内存集群实现，适用于单进程多节点场景和测试
+	MemoryCluster = cluster.MemoryCluster
+	// MemoryClusterHub 内存集群共享状态
+	MemoryClusterHub = cluster.MemoryClusterHub
+	// MemoryClusterConfig 内存集群配置
+	MemoryClusterConfig = cluster.MemoryClusterConfig
+)
 
-// NodeInfo 节点信息
-type NodeInfo struct {
-	ID        string            `json:"id"`
-	Address   string            `json:"address"`
-	Port      int               `json:"port"`
-	Status    string            `json:"status"` // online, offline, leader
-	StartedAt time.Time         `json:"started_at"`
-	LastSeen  time.Time         `json:"last_seen"`
-	Metadata  map[string]string `json:"metadata"`
+// NewMemoryClusterHub 创建内存集群共享状态
+func NewMemoryClusterHub() *MemoryClusterHub {
+	return cluster.NewMemoryClusterHub()
 }
 
-// ClusterMessage 集群消息
-type ClusterMessage struct {
-	Type      string    `json:"type"`
-	NodeID    string    `json:"node_id"`
-	Timestamp time.Time `json:"timestamp"`
-	Data      []byte    `json:"data"`
+// NewMemoryCluster 创建内存集群
+func NewMemoryCluster(config MemoryClusterConfig) *MemoryCluster {
+	return cluster.NewMemoryCluster(config)
 }
 
 // JobExecution 任务执行记录
@@ -80,6 +85,12 @@ type DistributedConfig struct {
 	EnableJobDistribution  bool
 	WorkerCount            int
 	MaxConcurrency         int
+
+	// 任务窃取：空闲的非领导者节点从积压节点批量领取任务
+	EnableWorkStealing bool
+	StealBatchSize     int
+	StealThreshold     int
+	StealInterval      time.Duration
 }
 
 // NewDistributedQueue 创建分布式队列
@@ -99,12 +110,28 @@ func NewDistributedQueue(config DistributedConfig) *DistributedQueue {
 	if config.MaxConcurrency == 0 {
 		config.MaxConcurrency = 10
 	}
+	if config.StealBatchSize == 0 {
+		config.StealBatchSize = 5
+	}
+	if config.StealThreshold == 0 {
+		config.StealThreshold = 10
+	}
+	if config.StealInterval == 0 {
+		config.StealInterval = time.Second
+	}
 
 	dq := &DistributedQueue{
-		MemoryQueue: NewMemoryQueue(),
-		nodeID:      config.NodeID,
-		cluster:     config.Cluster,
-		stopChan:    make(chan struct{}),
+		MemoryQueue:       NewMemoryQueue(),
+		nodeID:            config.NodeID,
+		cluster:           config.Cluster,
+		stopChan:          make(chan struct{}),
+		heartbeatInterval: config.HeartbeatInterval,
+		workStealing:      config.EnableWorkStealing,
+		stealBatchSize:    config.StealBatchSize,
+		stealThreshold:    config.StealThreshold,
+		stealInterval:     config.StealInterval,
+		stolen:            NewMemoryQueue(),
+		lent:              make(map[string]*BaseJob),
 	}
 
 	// 创建工作进程池
@@ -131,6 +158,11 @@ func (dq *DistributedQueue) Start() error {
 	// 启动消息订阅
 	go dq.subscribeMessages()
 
+	// 启动任务窃取
+	if dq.workStealing {
+		go dq.stealLoop()
+	}
+
 	// 启动工作进程池
 	if err := dq.workerPool.Start(); err != nil {
 		return fmt.Errorf("failed to start worker pool: %w", err)
@@ -146,8 +178,8 @@ func (dq *DistributedQueue) Stop() error {
 		return fmt.Errorf("failed to stop worker pool: %w", err)
 	}
 
-	// 停止选举
-	dq.stopChan <- struct{}{}
+	// 停止心跳和任务窃取
+	close(dq.stopChan)
 
 	// 注销节点
 	if err := dq.cluster.Unregister(dq.nodeID); err != nil {
@@ -164,11 +196,51 @@ func (dq *DistributedQueue) IsLeader() bool {
 	return dq.leader
 }
 
+// GetWorkerPool 获取工作进程池
+func (dq *DistributedQueue) GetWorkerPool() *DistributedWorkerPool {
+	return dq.workerPool
+}
+
+// PauseCluster 暂停集群中所有节点的工作进程
+func (dq *DistributedQueue) PauseCluster() error {
+	if err := dq.workerPool.PauseAll(); err != nil {
+		return err
+	}
+	return dq.broadcastControl("workers_pause")
+}
+
+// ResumeCluster 恢复集群中所有节点的工作进程
+func (dq *DistributedQueue) ResumeCluster() error {
+	if err := dq.workerPool.ResumeAll(); err != nil {
+		return err
+	}
+	return dq.broadcastControl("workers_resume")
+}
+
+// broadcastControl 广播控制消息
+func (dq *DistributedQueue) broadcastControl(msgType string) error {
+	return dq.cluster.Broadcast(ClusterMessage{
+		Type:      msgType,
+		NodeID:    dq.nodeID,
+		Timestamp: time.Now(),
+	})
+}
+
 // GetClusterNodes 获取集群节点
 func (dq *DistributedQueue) GetClusterNodes() ([]NodeInfo, error) {
 	return dq.cluster.GetNodes()
 }
 
+// OnNodeJoin 注册节点加入集群的回调
+func (dq *DistributedQueue) OnNodeJoin(callback func(NodeInfo)) error {
+	return dq.cluster.OnNodeJoin(callback)
+}
+
+// OnNodeLeave 注册节点离开集群的回调，包括正常关闭和心跳超时
+func (dq *DistributedQueue) OnNodeLeave(callback func(NodeInfo)) error {
+	return dq.cluster.OnNodeLeave(callback)
+}
+
 // Push 推送任务（分布式版本）
 func (dq *DistributedQueue) Push(job Job) error {
 	// 如果是领导者，直接推送
@@ -227,6 +299,10 @@ func (dq *DistributedQueue) registerNode() error {
 		},
 	}
 
+	dq.nodeMu.Lock()
+	dq.nodeInfo = info
+	dq.nodeMu.Unlock()
+
 	return dq.cluster.Register(dq.nodeID, info)
 }
 
@@ -267,19 +343,38 @@ func (dq *DistributedQueue) onLoseLeadership() {
 
 // updateNodeStatus 更新节点状态
 func (dq *DistributedQueue) updateNodeStatus(status string) {
-	// 这里应该更新节点信息到集群
-	// 具体实现取决于集群接口
+	// 上报本地积压任务数，供其他节点判断是否需要窃取任务
+	pending, _ := dq.MemoryQueue.Size()
+
+	dq.nodeMu.Lock()
+	metadata := make(map[string]string, len(dq.nodeInfo.Metadata)+1)
+	for key, value := range dq.nodeInfo.Metadata {
+		metadata[key] = value
+	}
+	metadata["pending_jobs"] = strconv.Itoa(pending)
+	dq.nodeInfo.Metadata = metadata
+	dq.nodeInfo.Status = status
+	dq.nodeInfo.LastSeen = time.Now()
+	info := dq.nodeInfo
+	dq.nodeMu.Unlock()
+
+	// 重新注册以刷新节点信息，集群据此判断节点心跳是否超时
+	dq.cluster.Register(dq.nodeID, info)
 }
 
 // heartbeat 心跳
 func (dq *DistributedQueue) heartbeat() {
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(dq.heartbeatInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			dq.updateNodeStatus("online")
+			if dq.IsLeader() {
+				dq.updateNodeStatus("leader")
+			} else {
+				dq.updateNodeStatus("online")
+			}
 		case <-dq.stopChan:
 			return
 		}
@@ -304,6 +399,14 @@ func (dq *DistributedQueue) handleClusterMessage(msg ClusterMessage) {
 		dq.handleJobExecutionComplete(msg)
 	case "leader_changed":
 		dq.handleLeaderChanged(msg)
+	case "workers_pause":
+		dq.workerPool.PauseAll()
+	case "workers_resume":
+		dq.workerPool.ResumeAll()
+	case "steal_request":
+		dq.handleStealRequest(msg)
+	case "steal_grant":
+		dq.handleStealGrant(msg)
 	}
 }
 
@@ -323,6 +426,8 @@ func (dq *DistributedQueue) handleJobPush(msg ClusterMessage) {
 	for key, value := range jobData.Tags {
 		job.AddTag(key, value)
 	}
+	job.EncryptedPayload = jobData.Encrypted
+	job.Nonce = jobData.Nonce
 
 	// 添加到本地队列
 	dq.MemoryQueue.Push(job)
@@ -357,6 +462,11 @@ func (dq *DistributedQueue) handleLeaderChanged(msg ClusterMessage) {
 
 // broadcastJob 广播任务
 func (dq *DistributedQueue) broadcastJob(job Job) error {
+	// 广播前加密，避免明文载荷在集群中传输
+	if err := encryptJob(job); err != nil {
+		return err
+	}
+
 	jobData := JobData{
 		ID:      job.GetID(),
 		Payload: job.GetPayload(),
@@ -366,6 +476,10 @@ func (dq *DistributedQueue) broadcastJob(job Job) error {
 		Priority: job.GetPriority(),
 		Tags:    job.GetTags(),
 	}
+	if baseJob, ok := job.(*BaseJob); ok {
+		jobData.Encrypted = baseJob.EncryptedPayload
+		jobData.Nonce = baseJob.Nonce
+	}
 
 	data, err := json.Marshal(jobData)
 	if err != nil {
@@ -412,8 +526,15 @@ func (dq *DistributedQueue) recordJobExecution(execution JobExecution) {
 
 // updateJobExecution 更新任务执行状态
 func (dq *DistributedQueue) updateJobExecution(execution JobExecution) {
-	// 更新任务执行状态
-	// 可以用于统计和监控
+	// 被其他节点窃取的任务执行完成后，删除本地保留
+	dq.lentMu.Lock()
+	job, exists := dq.lent[execution.JobID]
+	delete(dq.lent, execution.JobID)
+	dq.lentMu.Unlock()
+
+	if exists {
+		dq.MemoryQueue.Delete(job)
+	}
 }
 
 // GetDistributedStats 获取分布式统计
@@ -450,6 +571,9 @@ type JobData struct {
 	Timeout  time.Duration     `json:"timeout"`
 	Priority int               `json:"priority"`
 	Tags     map[string]string `json:"tags"`
+
+	Encrypted bool   `json:"encrypted,omitempty"`
+	Nonce     []byte `json:"nonce,omitempty"`
 }
 
 // countOnlineNodes 统计在线节点
@@ -26,7 +26,10 @@ type DistributedWorker struct {
 	queue        *DistributedQueue
 	ctx          context.Context
 	cancel       context.CancelFunc
-	status       string // idle, processing, stopped
+	status       string // idle, processing, paused, stopped
+	paused       bool
+	resumeChan   chan struct{}
+	popCancel    context.CancelFunc
 	currentJob   Job
 	processed    int64
 	failed       int64
@@ -34,6 +37,7 @@ type DistributedWorker struct {
 	lastJobAt    time.Time
 	onCompleted  func(Job)
 	onFailed     func(Job, error)
+	encryptor    Encryptor
 	mu           sync.RWMutex
 }
 
@@ -94,6 +98,9 @@ func (pool *DistributedWorkerPool) Stop() error {
 
 	pool.status = "stopped"
 	pool.cancel()
+	for _, worker := range pool.workers {
+		worker.Stop()
+	}
 
 	// 等待所有工作进程停止
 	pool.wg.Wait()
@@ -120,6 +127,11 @@ func (pool *DistributedWorkerPool) Pause() error {
 	return nil
 }
 
+// PauseAll 暂停所有工作进程，工作进程保持存活但不再消费任务
+func (pool *DistributedWorkerPool) PauseAll() error {
+	return pool.Pause()
+}
+
 // Resume 恢复工作进程池
 func (pool *DistributedWorkerPool) Resume() error {
 	pool.mu.Lock()
@@ -139,6 +151,11 @@ func (pool *DistributedWorkerPool) Resume() error {
 	return nil
 }
 
+// ResumeAll 恢复所有工作进程
+func (pool *DistributedWorkerPool) ResumeAll() error {
+	return pool.Resume()
+}
+
 // GetStatus 获取工作进程池状态
 func (pool *DistributedWorkerPool) GetStatus() string {
 	pool.mu.RLock()
@@ -173,6 +190,18 @@ func (pool *DistributedWorkerPool) GetStats() WorkerPoolStats {
 	}
 }
 
+// GetWorkerStats 获取每个工作进程的统计
+func (pool *DistributedWorkerPool) GetWorkerStats() []WorkerStats {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	stats := make([]WorkerStats, 0, len(pool.workers))
+	for _, worker := range pool.workers {
+		stats = append(stats, worker.GetStats())
+	}
+	return stats
+}
+
 // SetOnCompleted 设置任务完成回调
 func (pool *DistributedWorkerPool) SetOnCompleted(callback func(Job)) {
 	for _, worker := range pool.workers {
@@ -187,6 +216,13 @@ func (pool *DistributedWorkerPool) SetOnFailed(callback func(Job, error)) {
 	}
 }
 
+// SetEncryptor 设置解密任务载荷的加密器
+func (pool *DistributedWorkerPool) SetEncryptor(encryptor Encryptor) {
+	for _, worker := range pool.workers {
+		worker.encryptor = encryptor
+	}
+}
+
 // WorkerPoolStats 工作进程池统计
 type WorkerPoolStats struct {
 	TotalWorkers   int   `json:"total_workers"`
@@ -225,21 +261,32 @@ func (w *DistributedWorker) Start() {
 			w.mu.Unlock()
 			return
 		default:
+			w.mu.Lock()
+			if w.paused {
+				// 等待恢复信号
+				resumeChan := w.resumeChan
+				w.mu.Unlock()
+				select {
+				case <-resumeChan:
+				case <-w.ctx.Done():
+				}
+				continue
+			}
+			w.mu.Unlock()
+
 			w.processNextJob()
 		}
 	}
 }
 
+// Stop 停止工作进程
+func (w *DistributedWorker) Stop() {
+	w.cancel()
+}
+
 // processNextJob 处理下一个任务
 func (w *DistributedWorker) processNextJob() {
-	// 检查是否为领导者（只有领导者才处理任务）
-	if !w.queue.IsLeader() {
-		time.Sleep(1 * time.Second)
-		return
-	}
-
-	// 尝试获取任务
-	job, err := w.queue.Pop(w.ctx)
+	job, err := w.nextJob()
 	if err != nil {
 		// 没有任务，等待一段时间
 		time.Sleep(100 * time.Millisecond)
@@ -261,15 +308,23 @@ func (w *DistributedWorker) processNextJob() {
 	}
 	w.queue.broadcastJobExecution(execution)
 
-	// 处理任务
-	err = w.processJob(job)
+	// 解密并处理任务
+	err = decryptJob(job, w.encryptor)
+	if err == nil {
+		err = w.processJob(job)
+	}
+	w.queue.completeJob(job)
 
 	// 更新统计
 	w.mu.Lock()
 	w.processed++
 	w.lastJobAt = time.Now()
 	w.currentJob = nil
-	w.status = "idle"
+	if w.paused {
+		w.status = "paused"
+	} else {
+		w.status = "idle"
+	}
 	w.mu.Unlock()
 
 	// 广播任务执行完成
@@ -291,13 +346,41 @@ func (w *DistributedWorker) processNextJob() {
 		if w.onFailed != nil {
 			w.onFailed(job, err)
 		}
+		failWorkflowJob(job, err)
 	} else {
 		if w.onCompleted != nil {
 			w.onCompleted(job)
 		}
+		completeWorkflowJob(w.queue, job)
 	}
 }
 
+// nextJob 获取下一个任务，领导者处理本地队列，开启任务窃取的节点处理窃取到的任务
+func (w *DistributedWorker) nextJob() (Job, error) {
+	// 暂停时取消正在等待的出队
+	ctx, cancel := context.WithCancel(w.ctx)
+	defer cancel()
+
+	w.mu.Lock()
+	if w.paused {
+		w.mu.Unlock()
+		return nil, ErrWorkerStopped
+	}
+	w.popCancel = cancel
+	w.mu.Unlock()
+
+	if w.queue.IsLeader() {
+		return w.queue.Pop(ctx)
+	}
+
+	if w.queue.workStealing {
+		return w.queue.popStolen(ctx)
+	}
+
+	time.Sleep(1 * time.Second)
+	return nil, ErrJobNotFound
+}
+
 // processJob 处理单个任务
 func (w *DistributedWorker) processJob(job Job) error {
 	// 这里应该调用任务处理器
@@ -312,11 +395,20 @@ func (w *DistributedWorker) processJob(job Job) error {
 	return nil
 }
 
-// Pause 暂停工作进程
+// Pause 暂停工作进程，正在处理的任务会继续执行完成
 func (w *DistributedWorker) Pause() {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	if w.status == "processing" {
+	if w.paused {
+		return
+	}
+
+	w.paused = true
+	w.resumeChan = make(chan struct{})
+	if w.popCancel != nil {
+		w.popCancel()
+	}
+	if w.status != "processing" {
 		w.status = "paused"
 	}
 }
@@ -325,6 +417,12 @@ func (w *DistributedWorker) Pause() {
 func (w *DistributedWorker) Resume() {
 	w.mu.Lock()
 	defer w.mu.Unlock()
+	if !w.paused {
+		return
+	}
+
+	w.paused = false
+	close(w.resumeChan)
 	if w.status == "paused" {
 		w.status = "idle"
 	}
@@ -0,0 +1,128 @@
+package queue
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrPayloadDecryption 任务载荷解密失败
+var ErrPayloadDecryption = errors.New("job payload decryption failed")
+
+// ErrEncryptionUnsupported 队列驱动不支持加密载荷
+var ErrEncryptionUnsupported = errors.New("queue driver does not support encrypted payloads")
+
+// Encryptor 任务载荷加密器，可对接 KMS 等密钥管理服务
+type Encryptor interface {
+	Encrypt(plaintext []byte) (ciphertext, nonce []byte, err error)
+	Decrypt(ciphertext, nonce []byte) ([]byte, error)
+}
+
+// AESEncryptor AES-GCM 加密器
+type AESEncryptor struct {
+	key []byte
+}
+
+// NewAESEncryptor 创建 AES-GCM 加密器，key 长度必须为 16、24 或 32 字节
+func NewAESEncryptor(key []byte) *AESEncryptor {
+	return &AESEncryptor{key: key}
+}
+
+// Encrypt 加密
+func (e *AESEncryptor) Encrypt(plaintext []byte) ([]byte, []byte, error) {
+	gcm, err := e.aead()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return gcm.Seal(nil, nonce, plaintext, nil), nonce, nil
+}
+
+// Decrypt 解密，密文被篡改时返回 ErrPayloadDecryption
+func (e *AESEncryptor) Decrypt(ciphertext, nonce []byte) ([]byte, error) {
+	gcm, err := e.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, ErrPayloadDecryption
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrPayloadDecryption
+	}
+	return plaintext, nil
+}
+
+// aead 创建 GCM 实例
+func (e *AESEncryptor) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(e.key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+var (
+	defaultEncryptor   Encryptor
+	defaultEncryptorMu sync.RWMutex
+)
+
+// SetDefaultEncryptor 设置工作进程解密时使用的默认加密器
+func SetDefaultEncryptor(encryptor Encryptor) {
+	defaultEncryptorMu.Lock()
+	defer defaultEncryptorMu.Unlock()
+	defaultEncryptor = encryptor
+}
+
+// getDefaultEncryptor 获取默认加密器
+func getDefaultEncryptor() Encryptor {
+	defaultEncryptorMu.RLock()
+	defer defaultEncryptorMu.RUnlock()
+	return defaultEncryptor
+}
+
+// encryptJob 推送前加密任务载荷
+func encryptJob(job Job) error {
+	baseJob, ok := job.(*BaseJob)
+	if !ok {
+		return nil
+	}
+	return baseJob.encryptPayload()
+}
+
+// rejectEncryptedJob 尚未实现载荷加密的驱动在推送需要加密的任务时返回错误，避免载荷以明文存储
+func rejectEncryptedJob(job Job, driver string) error {
+	baseJob, ok := job.(*BaseJob)
+	if ok && (baseJob.encryptor != nil || baseJob.EncryptedPayload) {
+		return fmt.Errorf("%w: %s", ErrEncryptionUnsupported, driver)
+	}
+	return nil
+}
+
+// decryptJob 处理前解密任务载荷，encryptor 为空时使用默认加密器
+func decryptJob(job Job, encryptor Encryptor) error {
+	baseJob, ok := job.(*BaseJob)
+	if !ok || !baseJob.IsEncrypted() {
+		return nil
+	}
+
+	if encryptor == nil {
+		encryptor = getDefaultEncryptor()
+	}
+	if encryptor == nil {
+		// 同一进程内推送的任务可以使用推送时的加密器
+		encryptor = baseJob.encryptor
+	}
+	return baseJob.DecryptPayload(encryptor)
+}
@@ -0,0 +1,65 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// handlerTag 标记任务处理器名称的任务标签
+const handlerTag = "handler"
+
+// JobHandler 任务处理器
+type JobHandler func(job Job) error
+
+// ContextJobHandler 接收 context 的任务处理器，context 中带有入队时的追踪上下文
+type ContextJobHandler func(ctx context.Context, job Job) error
+
+// handlerRegistry 任务处理器注册表
+type handlerRegistry struct {
+	mu       sync.RWMutex
+	handlers map[string]ContextJobHandler
+}
+
+// handlers 全局任务处理器注册表
+var handlers = &handlerRegistry{
+	handlers: make(map[string]ContextJobHandler),
+}
+
+// RegisterHandler 注册任务处理器，工作进程根据任务的处理器名称调用
+func RegisterHandler(name string, handler JobHandler) {
+	RegisterContextHandler(name, func(ctx context.Context, job Job) error {
+		return handler(job)
+	})
+}
+
+// RegisterContextHandler 注册接收 context 的任务处理器
+func RegisterContextHandler(name string, handler ContextJobHandler) {
+	handlers.mu.Lock()
+	defer handlers.mu.Unlock()
+	handlers.handlers[name] = handler
+}
+
+// NewHandlerJob 创建由指定处理器执行的任务
+func NewHandlerJob(name string, payload []byte, queue string) *BaseJob {
+	job := NewJob(payload, queue)
+	job.AddTag(handlerTag, name)
+	return job
+}
+
+// dispatch 调用任务对应的处理器，任务没有指定处理器时返回 false
+func (r *handlerRegistry) dispatch(ctx context.Context, job Job) (bool, error) {
+	name := job.GetTags()[handlerTag]
+	if name == "" {
+		return false, nil
+	}
+
+	r.mu.RLock()
+	handler, exists := r.handlers[name]
+	r.mu.RUnlock()
+	if !exists {
+		return true, fmt.Errorf("no handler registered for job %s", name)
+	}
+
+	return true, handler(ctx, job)
+}
@@ -25,6 +25,11 @@ type BaseJob struct {
 	CompletedAt *time.Time        `json:"completed_at"`
 	FailedAt    *time.Time        `json:"failed_at"`
 	Error       string            `json:"error"`
+
+	// 载荷加密
+	EncryptedPayload bool      `json:"encrypted"`
+	Nonce            []byte    `json:"nonce,omitempty"`
+	encryptor        Encryptor `json:"-"`
 }
 
 // NewJob 创建新任务
@@ -166,6 +171,59 @@ func (j *BaseJob) RemoveTag(key string) {
 	}
 }
 
+// Encrypted 推送时使用 AES-GCM 加密任务载荷
+func (j *BaseJob) Encrypted(key []byte) *BaseJob {
+	return j.EncryptWith(NewAESEncryptor(key))
+}
+
+// EncryptWith 推送时使用指定的加密器加密任务载荷
+func (j *BaseJob) EncryptWith(encryptor Encryptor) *BaseJob {
+	j.encryptor = encryptor
+	return j
+}
+
+// IsEncrypted 检查载荷是否已加密
+func (j *BaseJob) IsEncrypted() bool {
+	return j.EncryptedPayload
+}
+
+// DecryptPayload 解密任务载荷
+func (j *BaseJob) DecryptPayload(encryptor Encryptor) error {
+	if !j.EncryptedPayload {
+		return nil
+	}
+	if encryptor == nil {
+		return fmt.Errorf("no encryptor configured for encrypted job %s", j.ID)
+	}
+
+	payload, err := encryptor.Decrypt(j.Payload, j.Nonce)
+	if err != nil {
+		return err
+	}
+
+	j.Payload = payload
+	j.Nonce = nil
+	j.EncryptedPayload = false
+	return nil
+}
+
+// encryptPayload 加密任务载荷
+func (j *BaseJob) encryptPayload() error {
+	if j.encryptor == nil || j.EncryptedPayload {
+		return nil
+	}
+
+	ciphertext, nonce, err := j.encryptor.Encrypt(j.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt job payload: %w", err)
+	}
+
+	j.Payload = ciphertext
+	j.Nonce = nonce
+	j.EncryptedPayload = true
+	return nil
+}
+
 // Serialize 序列化任务
 func (j *BaseJob) Serialize() ([]byte, error) {
 	data, err := json.Marshal(j)
@@ -30,6 +30,10 @@ func NewKafkaQueue(config KafkaConfig) (*KafkaQueue, error) {
 
 // Push 推送任务
 func (kq *KafkaQueue) Push(job Job) error {
+	if err := rejectEncryptedJob(job, "kafka"); err != nil {
+		return err
+	}
+
 	// TODO: 实现 Kafka 消息发送
 	kq.stats.TotalJobs++
 	kq.stats.LastJobAt = time.Now()
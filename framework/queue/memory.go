@@ -41,6 +41,10 @@ func (q *MemoryQueue) Push(job Job) error {
 		return ErrInvalidJob
 	}
 
+	if err := baseJob.encryptPayload(); err != nil {
+		return err
+	}
+
 	// 设置可用时间
 	if baseJob.GetDelay() > 0 {
 		baseJob.SetDelay(baseJob.GetDelay())
@@ -69,6 +73,10 @@ func (q *MemoryQueue) PushBatch(jobs []Job) error {
 			return ErrInvalidJob
 		}
 
+		if err := baseJob.encryptPayload(); err != nil {
+			return err
+		}
+
 		if baseJob.GetDelay() > 0 {
 			baseJob.SetDelay(baseJob.GetDelay())
 		}
@@ -170,6 +178,7 @@ func (q *MemoryQueue) Delete(job Job) error {
 	if _, exists := q.reservedJobs[jobID]; exists {
 		delete(q.reservedJobs, jobID)
 		q.stats.ReservedJobs--
+		q.stats.CompletedJobs++
 		return nil
 	}
 
@@ -344,6 +353,47 @@ func (q *MemoryQueue) cleanupExpiredJobs() {
 	}
 }
 
+// sizeByQueue 按队列名称统计待处理任务数
+func (q *MemoryQueue) sizeByQueue() map[string]int {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	sizes := make(map[string]int)
+	for _, job := range q.jobs {
+		sizes[job.GetQueue()]++
+	}
+	return sizes
+}
+
+// reserveAvailable 立即保留最多 count 个可用任务，不等待新任务
+func (q *MemoryQueue) reserveAvailable(count int) []*BaseJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil
+	}
+
+	q.cleanupExpiredJobs()
+
+	reserved := make([]*BaseJob, 0, count)
+	remaining := q.jobs[:0]
+	for _, job := range q.jobs {
+		if len(reserved) < count && job.IsAvailable() && !job.IsReserved() {
+			job.MarkAsReserved()
+			q.reservedJobs[job.GetID()] = job
+			reserved = append(reserved, job)
+			continue
+		}
+		remaining = append(remaining, job)
+	}
+	q.jobs = remaining
+	q.stats.PendingJobs -= int64(len(reserved))
+	q.stats.ReservedJobs += int64(len(reserved))
+
+	return reserved
+}
+
 // sortJobs 按优先级排序任务
 func (q *MemoryQueue) sortJobs() {
 	sort.Slice(q.jobs, func(i, j int) bool {
@@ -0,0 +1,134 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QueueMetrics 分布式队列指标快照
+type QueueMetrics struct {
+	NodeID      string             `json:"node_id"`
+	IsLeader    bool               `json:"is_leader"`
+	LeaderID    string             `json:"leader_id"`
+	TotalNodes  int                `json:"total_nodes"`
+	OnlineNodes int                `json:"online_nodes"`
+	QueueStats  QueueStats         `json:"queue_stats"`
+	PendingJobs map[string]int     `json:"pending_jobs"`
+	WorkerPool  WorkerPoolStats    `json:"worker_pool"`
+	Workers     []WorkerThroughput `json:"workers"`
+	CollectedAt time.Time          `json:"collected_at"`
+}
+
+// WorkerThroughput 工作进程吞吐量指标
+type WorkerThroughput struct {
+	WorkerStats
+	Throughput float64 `json:"throughput"` // 每秒处理任务数
+}
+
+// GetMetrics 获取分布式队列指标快照
+func (dq *DistributedQueue) GetMetrics() QueueMetrics {
+	stats := dq.GetDistributedStats()
+	now := time.Now()
+
+	metrics := QueueMetrics{
+		NodeID:      stats.NodeID,
+		IsLeader:    stats.IsLeader,
+		LeaderID:    stats.LeaderID,
+		TotalNodes:  stats.TotalNodes,
+		OnlineNodes: stats.OnlineNodes,
+		QueueStats:  stats.QueueStats,
+		PendingJobs: dq.MemoryQueue.sizeByQueue(),
+		WorkerPool:  dq.workerPool.GetStats(),
+		CollectedAt: now,
+	}
+
+	for _, worker := range dq.workerPool.GetWorkerStats() {
+		var throughput float64
+		if elapsed := now.Sub(worker.StartedAt).Seconds(); elapsed > 0 {
+			throughput = float64(worker.Processed) / elapsed
+		}
+		metrics.Workers = append(metrics.Workers, WorkerThroughput{
+			WorkerStats: worker,
+			Throughput:  throughput,
+		})
+	}
+
+	return metrics
+}
+
+// MetricsHandler 创建队列指标的 HTTP 处理器
+// 默认返回 JSON，请求参数 format=prometheus 时返回 Prometheus 文本格式
+func MetricsHandler(dq *DistributedQueue) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics := dq.GetMetrics()
+
+		if r.URL.Query().Get("format") == "prometheus" {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+			w.Write([]byte(metrics.Prometheus()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metrics)
+	})
+}
+
+// Prometheus 将指标转换为 Prometheus 文本格式
+func (m QueueMetrics) Prometheus() string {
+	var b strings.Builder
+	node := fmt.Sprintf(`node="%s"`, m.NodeID)
+
+	writeMetric := func(name, metricType, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	}
+
+	leader := 0
+	if m.IsLeader {
+		leader = 1
+	}
+	writeMetric("queue_leader", "gauge", "Whether this node is the queue leader.")
+	fmt.Fprintf(&b, "queue_leader{%s} %d\n", node, leader)
+
+	writeMetric("queue_nodes_online", "gauge", "Number of online queue nodes.")
+	fmt.Fprintf(&b, "queue_nodes_online{%s} %d\n", node, m.OnlineNodes)
+
+	writeMetric("queue_jobs_pending", "gauge", "Number of pending jobs per queue.")
+	names := make([]string, 0, len(m.PendingJobs))
+	for name := range m.PendingJobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "queue_jobs_pending{%s,queue=\"%s\"} %d\n", node, name, m.PendingJobs[name])
+	}
+
+	writeMetric("queue_jobs_reserved", "gauge", "Number of reserved jobs.")
+	fmt.Fprintf(&b, "queue_jobs_reserved{%s} %d\n", node, m.QueueStats.ReservedJobs)
+
+	writeMetric("queue_jobs_completed_total", "counter", "Total number of completed jobs.")
+	fmt.Fprintf(&b, "queue_jobs_completed_total{%s} %d\n", node, m.QueueStats.CompletedJobs)
+
+	writeMetric("queue_jobs_failed_total", "counter", "Total number of failed jobs.")
+	fmt.Fprintf(&b, "queue_jobs_failed_total{%s} %d\n", node, m.QueueStats.FailedJobs)
+
+	writeMetric("queue_worker_processed_total", "counter", "Total number of jobs processed by each worker.")
+	for _, worker := range m.Workers {
+		fmt.Fprintf(&b, "queue_worker_processed_total{%s,worker=\"%s\"} %d\n", node, worker.ID, worker.Processed)
+	}
+
+	writeMetric("queue_worker_failed_total", "counter", "Total number of jobs failed by each worker.")
+	for _, worker := range m.Workers {
+		fmt.Fprintf(&b, "queue_worker_failed_total{%s,worker=\"%s\"} %d\n", node, worker.ID, worker.Failed)
+	}
+
+	writeMetric("queue_worker_throughput", "gauge", "Jobs processed per second by each worker.")
+	for _, worker := range m.Workers {
+		fmt.Fprintf(&b, "queue_worker_throughput{%s,worker=\"%s\"} %g\n", node, worker.ID, worker.Throughput)
+	}
+
+	return b.String()
+}
@@ -151,6 +151,15 @@ func (m *Manager) Push(job Job) error {
 	return queue.Push(job)
 }
 
+// PushContext 推送任务到默认队列并携带 context 中的追踪上下文
+func (m *Manager) PushContext(ctx context.Context, job Job) error {
+	queue, err := m.GetQueue("")
+	if err != nil {
+		return err
+	}
+	return PushContext(ctx, queue, job)
+}
+
 // PushTo 推送任务到指定队列
 func (m *Manager) PushTo(queueName string, job Job) error {
 	queue, err := m.GetQueue(queueName)
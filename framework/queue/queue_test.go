@@ -1,7 +1,16 @@
 package queue
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -384,4 +393,589 @@ func TestGlobalFunctions(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to clear globally: %v", err)
 	}
-} 
\ No newline at end of file
+} 
+
+func TestMemoryClusterMembershipCallbacks(t *testing.T) {
+	hub := NewMemoryClusterHub()
+	observer := NewMemoryCluster(MemoryClusterConfig{NodeID: "observer", Hub: hub, NodeTimeout: 100 * time.Millisecond})
+
+	var mu sync.Mutex
+	var joined, left []string
+	observer.OnNodeJoin(func(node NodeInfo) {
+		mu.Lock()
+		joined = append(joined, node.ID)
+		mu.Unlock()
+	})
+	observer.OnNodeLeave(func(node NodeInfo) {
+		mu.Lock()
+		left = append(left, node.ID)
+		mu.Unlock()
+	})
+
+	node1 := NewMemoryCluster(MemoryClusterConfig{NodeID: "node-1", Hub: hub})
+	node2 := NewMemoryCluster(MemoryClusterConfig{NodeID: "node-2", Hub: hub})
+	node1.Register("node-1", NodeInfo{Status: "online"})
+	node2.Register("node-2", NodeInfo{Status: "online"})
+
+	// 重复注册（心跳）不应触发加入事件
+	node1.Register("node-1", NodeInfo{Status: "online"})
+
+	mu.Lock()
+	if len(joined) != 2 || joined[0] != "node-1" || joined[1] != "node-2" {
+		t.Errorf("Expected joins [node-1 node-2], got %v", joined)
+	}
+	mu.Unlock()
+
+	// 正常关闭
+	node1.Close()
+
+	mu.Lock()
+	if len(left) != 1 || left[0] != "node-1" {
+		t.Errorf("Expected node-1 to leave on close, got %v", left)
+	}
+	mu.Unlock()
+
+	// 心跳超时
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		count := len(left)
+		mu.Unlock()
+		if count == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(left) != 2 || left[1] != "node-2" {
+		t.Errorf("Expected node-2 to leave on heartbeat timeout, got %v", left)
+	}
+}
+
+func TestDistributedQueueWorkStealing(t *testing.T) {
+	hub := NewMemoryClusterHub()
+
+	busy := NewDistributedQueue(DistributedConfig{
+		NodeID:            "busy",
+		Cluster:           NewMemoryCluster(MemoryClusterConfig{NodeID: "busy", Hub: hub, ElectionInterval: 50 * time.Millisecond}),
+		HeartbeatInterval: 50 * time.Millisecond,
+		WorkerCount:       1,
+	})
+	if err := busy.Start(); err != nil {
+		t.Fatalf("Failed to start busy node: %v", err)
+	}
+	defer busy.Stop()
+
+	// 等待 busy 成为领导者后再启动空闲节点
+	deadline := time.Now().Add(time.Second)
+	for !busy.IsLeader() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !busy.IsLeader() {
+		t.Fatal("Expected busy node to become leader")
+	}
+
+	idle := NewDistributedQueue(DistributedConfig{
+		NodeID:             "idle",
+		Cluster:            NewMemoryCluster(MemoryClusterConfig{NodeID: "idle", Hub: hub, ElectionInterval: 50 * time.Millisecond}),
+		HeartbeatInterval:  50 * time.Millisecond,
+		WorkerCount:        2,
+		EnableWorkStealing: true,
+		StealBatchSize:     3,
+		StealThreshold:     2,
+		StealInterval:      50 * time.Millisecond,
+	})
+	if err := idle.Start(); err != nil {
+		t.Fatalf("Failed to start idle node: %v", err)
+	}
+	defer idle.Stop()
+
+	for i := 0; i < 40; i++ {
+		if err := busy.Push(NewJob([]byte(fmt.Sprintf("job-%d", i)), "default")); err != nil {
+			t.Fatalf("Failed to push job: %v", err)
+		}
+	}
+
+	deadline = time.Now().Add(3 * time.Second)
+	for idle.GetWorkerPool().GetStats().TotalProcessed == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if idle.GetWorkerPool().GetStats().TotalProcessed == 0 {
+		t.Error("Expected idle node to process stolen jobs")
+	}
+	if idle.IsLeader() {
+		t.Error("Expected idle node not to be leader")
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	dq := NewDistributedQueue(DistributedConfig{
+		NodeID:      "metrics",
+		Cluster:     NewMemoryCluster(MemoryClusterConfig{NodeID: "metrics"}),
+		WorkerCount: 2,
+	})
+
+	// 未启动的节点不是领导者，直接写入本地队列
+	dq.MemoryQueue.Push(NewJob([]byte("a"), "emails"))
+	dq.MemoryQueue.Push(NewJob([]byte("b"), "emails"))
+	dq.MemoryQueue.Push(NewJob([]byte("c"), "reports"))
+
+	handler := MetricsHandler(dq)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", recorder.Code)
+	}
+
+	var metrics QueueMetrics
+	if err := json.Unmarshal(recorder.Body.Bytes(), &metrics); err != nil {
+		t.Fatalf("Failed to decode metrics: %v", err)
+	}
+	if metrics.NodeID != "metrics" {
+		t.Errorf("Expected node ID metrics, got %s", metrics.NodeID)
+	}
+	if metrics.IsLeader {
+		t.Error("Expected node not to be leader")
+	}
+	if metrics.QueueStats.PendingJobs != 3 {
+		t.Errorf("Expected 3 pending jobs, got %d", metrics.QueueStats.PendingJobs)
+	}
+	if metrics.PendingJobs["emails"] != 2 || metrics.PendingJobs["reports"] != 1 {
+		t.Errorf("Unexpected pending jobs per queue: %v", metrics.PendingJobs)
+	}
+	if len(metrics.Workers) != 2 {
+		t.Errorf("Expected 2 workers, got %d", len(metrics.Workers))
+	}
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics?format=prometheus", nil))
+
+	body := recorder.Body.String()
+	if !strings.Contains(body, `queue_jobs_pending{node="metrics",queue="emails"} 2`) {
+		t.Errorf("Expected emails pending gauge, got:\n%s", body)
+	}
+	if !strings.Contains(body, `queue_leader{node="metrics"} 0`) {
+		t.Errorf("Expected leader gauge, got:\n%s", body)
+	}
+}
+
+func TestWorkerPauseResume(t *testing.T) {
+	queue := NewMemoryQueue()
+	pool := NewWorkerPool(queue, "test-queue", 2)
+	if err := pool.Start(); err != nil {
+		t.Fatalf("Failed to start worker pool: %v", err)
+	}
+	defer pool.Stop()
+
+	if err := pool.PauseAll(); err != nil {
+		t.Fatalf("Failed to pause worker pool: %v", err)
+	}
+	if pool.GetStatus() != "paused" {
+		t.Errorf("Expected pool status 'paused', got %s", pool.GetStatus())
+	}
+
+	for i := 0; i < 3; i++ {
+		queue.Push(NewJob([]byte("payload"), "test-queue"))
+	}
+
+	// 暂停期间任务保持在队列中
+	time.Sleep(300 * time.Millisecond)
+	if size, _ := queue.Size(); size != 3 {
+		t.Errorf("Expected paused workers to leave 3 jobs, got %d", size)
+	}
+
+	if err := pool.ResumeAll(); err != nil {
+		t.Fatalf("Failed to resume worker pool: %v", err)
+	}
+	if pool.GetStatus() != "running" {
+		t.Errorf("Expected pool status 'running', got %s", pool.GetStatus())
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		var processed int64
+		for _, worker := range pool.GetWorkers() {
+			processed += worker.GetStatus().Processed
+		}
+		if processed == 3 {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Error("Expected resumed workers to process all jobs")
+}
+
+func TestDistributedQueuePauseCluster(t *testing.T) {
+	hub := NewMemoryClusterHub()
+
+	leader := NewDistributedQueue(DistributedConfig{
+		NodeID:      "leader",
+		Cluster:     NewMemoryCluster(MemoryClusterConfig{NodeID: "leader", Hub: hub, ElectionInterval: 50 * time.Millisecond}),
+		WorkerCount: 1,
+	})
+	if err := leader.Start(); err != nil {
+		t.Fatalf("Failed to start leader: %v", err)
+	}
+	defer leader.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for !leader.IsLeader() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !leader.IsLeader() {
+		t.Fatal("Expected leader node to become leader")
+	}
+
+	operator := NewDistributedQueue(DistributedConfig{
+		NodeID:      "operator",
+		Cluster:     NewMemoryCluster(MemoryClusterConfig{NodeID: "operator", Hub: hub, ElectionInterval: 50 * time.Millisecond}),
+		WorkerCount: 1,
+	})
+	if err := operator.Start(); err != nil {
+		t.Fatalf("Failed to start operator: %v", err)
+	}
+	defer operator.Stop()
+
+	// 等待消息订阅生效
+	time.Sleep(100 * time.Millisecond)
+
+	if err := operator.PauseCluster(); err != nil {
+		t.Fatalf("Failed to pause cluster: %v", err)
+	}
+	if status := leader.GetWorkerPool().GetStats().Status; status != "paused" {
+		t.Errorf("Expected leader pool status 'paused', got %s", status)
+	}
+
+	leader.MemoryQueue.Push(NewJob([]byte("payload"), "default"))
+	time.Sleep(300 * time.Millisecond)
+	if processed := leader.GetWorkerPool().GetStats().TotalProcessed; processed != 0 {
+		t.Errorf("Expected paused cluster to process no jobs, got %d", processed)
+	}
+
+	if err := operator.ResumeCluster(); err != nil {
+		t.Fatalf("Failed to resume cluster: %v", err)
+	}
+
+	deadline = time.Now().Add(3 * time.Second)
+	for leader.GetWorkerPool().GetStats().TotalProcessed == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if leader.GetWorkerPool().GetStats().TotalProcessed == 0 {
+		t.Error("Expected resumed cluster to process the pending job")
+	}
+}
+
+func TestJobChainAbortsOnFailure(t *testing.T) {
+	queue := NewMemoryQueue()
+	worker := NewWorker(queue, "chain")
+
+	var mu sync.Mutex
+	var completed []string
+	worker.SetOnCompleted(func(job Job) {
+		mu.Lock()
+		completed = append(completed, string(job.GetPayload()))
+		mu.Unlock()
+	})
+
+	caught := make(chan Job, 1)
+	// 空载荷的任务会处理失败
+	first := NewJob([]byte("first"), "chain")
+	second := NewJob([]byte{}, "chain")
+	third := NewJob([]byte("third"), "chain")
+
+	chain := Chain(first, second, third).OnQueue(queue).Catch(func(job Job, err error) {
+		caught <- job
+	})
+	if err := chain.Dispatch(); err != nil {
+		t.Fatalf("Failed to dispatch chain: %v", err)
+	}
+
+	// 后续任务在前一个任务完成前不会入队
+	if size, _ := queue.Size(); size != 1 {
+		t.Errorf("Expected only the first job to be queued, got %d", size)
+	}
+
+	if err := worker.Start(); err != nil {
+		t.Fatalf("Failed to start worker: %v", err)
+	}
+	defer worker.Stop()
+
+	select {
+	case job := <-caught:
+		if job.GetID() != second.GetID() {
+			t.Errorf("Expected chain to fail on second job, got %s", job.GetID())
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected chain to abort on failure")
+	}
+
+	// 确认第三个任务没有被推送
+	time.Sleep(300 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(completed) != 1 || completed[0] != "first" {
+		t.Errorf("Expected only first job to complete, got %v", completed)
+	}
+	if size, _ := queue.Size(); size != 0 {
+		t.Errorf("Expected third job not to be queued, got %d pending", size)
+	}
+	if !chain.IsFinished() {
+		t.Error("Expected chain to be finished")
+	}
+}
+
+func TestJobChainProgressInJobMetadata(t *testing.T) {
+	queue := NewMemoryQueue()
+	chain := Chain(
+		NewJob([]byte("first"), "chain"),
+		NewJob([]byte("second"), "chain"),
+		NewJob([]byte("third"), "chain"),
+	).OnQueue(queue)
+	if err := chain.Dispatch(); err != nil {
+		t.Fatalf("Failed to dispatch chain: %v", err)
+	}
+
+	// 模拟由其他进程执行：本进程没有任务链的回调，任务以序列化形式传递
+	callbacks.remove(chain.ID(), "")
+
+	var payloads []string
+	var completed []*BaseJob
+	for i := 0; i < 3; i++ {
+		job, err := queue.Pop(context.Background())
+		if err != nil {
+			t.Fatalf("Expected chain job %d to be queued: %v", i+1, err)
+		}
+		data, _ := job.Serialize()
+		restored := &BaseJob{}
+		if err := restored.Deserialize(data); err != nil {
+			t.Fatalf("Failed to deserialize job: %v", err)
+		}
+		payloads = append(payloads, string(restored.GetPayload()))
+		completed = append(completed, restored)
+		completeWorkflowJob(queue, restored)
+	}
+
+	if strings.Join(payloads, ",") != "first,second,third" {
+		t.Errorf("Unexpected chain order: %v", payloads)
+	}
+	if !chain.IsFinished() {
+		t.Error("Expected chain to be finished")
+	}
+
+	// 重复上报已完成的任务不会再次推送后续任务
+	completeWorkflowJob(queue, completed[0])
+	if size, _ := queue.Size(); size != 0 {
+		t.Errorf("Expected no jobs to be queued again, got %d", size)
+	}
+}
+
+func TestJobChainKeepsPayloadEncryption(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	queue := NewMemoryQueue()
+	chain := Chain(
+		NewJob([]byte("first"), "chain"),
+		NewJob([]byte("secret payload"), "chain").Encrypted(key),
+	).OnQueue(queue)
+	if err := chain.Dispatch(); err != nil {
+		t.Fatalf("Failed to dispatch chain: %v", err)
+	}
+
+	first, err := queue.Pop(context.Background())
+	if err != nil {
+		t.Fatalf("Expected first chain job to be queued: %v", err)
+	}
+	// 后续任务在标签中只保存密文
+	if strings.Contains(first.GetTags()[chainNextTag], base64.StdEncoding.EncodeToString([]byte("secret payload"))) {
+		t.Error("Expected chained job payload not to be stored in plaintext")
+	}
+	completeWorkflowJob(queue, first)
+
+	second, err := queue.Pop(context.Background())
+	if err != nil {
+		t.Fatalf("Expected second chain job to be queued: %v", err)
+	}
+	if !second.(*BaseJob).IsEncrypted() || bytes.Contains(second.GetPayload(), []byte("secret")) {
+		t.Fatal("Expected restored chain job to stay encrypted")
+	}
+	if err := decryptJob(second, NewAESEncryptor(key)); err != nil {
+		t.Fatalf("Failed to decrypt chained job: %v", err)
+	}
+	if string(second.GetPayload()) != "secret payload" {
+		t.Errorf("Expected decrypted payload, got %s", second.GetPayload())
+	}
+}
+
+func TestJobBatchThen(t *testing.T) {
+	queue := NewMemoryQueue()
+	pool := NewWorkerPool(queue, "batch", 2)
+
+	done := make(chan BatchStats, 1)
+	jobs := []Job{
+		NewJob([]byte("a"), "batch"),
+		NewJob([]byte("b"), "batch"),
+		NewJob([]byte("c"), "batch"),
+	}
+	batch := Batch(jobs).OnQueue(queue).Then(func(b *JobBatch) {
+		done <- b.GetStats()
+	}).Catch(func(b *JobBatch, job Job, err error) {
+		t.Errorf("Unexpected batch failure: %v", err)
+	})
+	if err := batch.Dispatch(); err != nil {
+		t.Fatalf("Failed to dispatch batch: %v", err)
+	}
+
+	if err := pool.Start(); err != nil {
+		t.Fatalf("Failed to start worker pool: %v", err)
+	}
+	defer pool.Stop()
+
+	select {
+	case stats := <-done:
+		if stats.PendingJobs != 0 || stats.TotalJobs != 3 || !stats.Finished {
+			t.Errorf("Unexpected batch stats: %+v", stats)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected batch Then callback to fire")
+	}
+
+	// Then 只触发一次
+	select {
+	case <-done:
+		t.Error("Expected Then callback to fire once")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestEncryptedJobPayload(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	queue := NewMemoryQueue()
+
+	job := NewJob([]byte("secret payload"), "default").Encrypted(key)
+	if err := queue.Push(job); err != nil {
+		t.Fatalf("Failed to push encrypted job: %v", err)
+	}
+
+	if !job.IsEncrypted() || len(job.Nonce) == 0 {
+		t.Fatal("Expected job to be encrypted with a nonce")
+	}
+	if bytes.Contains(job.GetPayload(), []byte("secret")) {
+		t.Error("Expected payload not to contain plaintext")
+	}
+
+	// 加密标记和 nonce 随任务一起序列化
+	data, err := job.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize job: %v", err)
+	}
+	restored := &BaseJob{}
+	if err := restored.Deserialize(data); err != nil {
+		t.Fatalf("Failed to deserialize job: %v", err)
+	}
+	if err := restored.DecryptPayload(NewAESEncryptor(key)); err != nil {
+		t.Fatalf("Failed to decrypt payload: %v", err)
+	}
+	if string(restored.GetPayload()) != "secret payload" {
+		t.Errorf("Expected decrypted payload, got %s", restored.GetPayload())
+	}
+
+	// 篡改密文后认证失败
+	tampered := &BaseJob{}
+	tampered.Deserialize(data)
+	tampered.Payload[0] ^= 0xff
+	if err := tampered.DecryptPayload(NewAESEncryptor(key)); !errors.Is(err, ErrPayloadDecryption) {
+		t.Errorf("Expected ErrPayloadDecryption for tampered payload, got %v", err)
+	}
+
+	// 工作进程透明解密
+	worker := NewWorker(queue, "default")
+	worker.SetEncryptor(NewAESEncryptor(key))
+	payloads := make(chan string, 1)
+	worker.SetOnCompleted(func(job Job) {
+		payloads <- string(job.GetPayload())
+	})
+	if err := worker.Start(); err != nil {
+		t.Fatalf("Failed to start worker: %v", err)
+	}
+	defer worker.Stop()
+
+	select {
+	case payload := <-payloads:
+		if payload != "secret payload" {
+			t.Errorf("Expected worker to see plaintext payload, got %s", payload)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Expected worker to process encrypted job")
+	}
+
+	// 不支持加密的驱动拒绝推送需要加密的任务
+	kafka, _ := NewKafkaQueue(KafkaConfig{})
+	if err := kafka.Push(NewJob([]byte("secret payload"), "default").Encrypted(key)); !errors.Is(err, ErrEncryptionUnsupported) {
+		t.Errorf("Expected ErrEncryptionUnsupported, got %v", err)
+	}
+	if err := kafka.Push(NewJob([]byte("plain payload"), "default")); err != nil {
+		t.Errorf("Expected plain job to be accepted, got %v", err)
+	}
+}
+
+func TestTraceContextPropagation(t *testing.T) {
+	queue := NewMemoryQueue()
+
+	// 模拟 HTTP 请求中的追踪上下文
+	parent, err := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if err != nil {
+		t.Fatalf("Failed to parse traceparent: %v", err)
+	}
+	parent.TraceState = "vendor=value"
+	ctx := ContextWithSpanContext(context.Background(), parent)
+
+	traced := make(chan SpanContext, 1)
+	RegisterContextHandler("trace-test", func(ctx context.Context, job Job) error {
+		sc, ok := SpanContextFromContext(ctx)
+		if !ok {
+			return fmt.Errorf("missing trace context")
+		}
+		traced <- sc
+		return nil
+	})
+
+	if err := PushContext(ctx, queue, NewHandlerJob("trace-test", []byte("payload"), "default")); err != nil {
+		t.Fatalf("Failed to push job: %v", err)
+	}
+
+	// 任务经过序列化后仍然携带追踪上下文
+	job, err := queue.Pop(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to pop job: %v", err)
+	}
+	data, _ := job.Serialize()
+	restored := &BaseJob{}
+	if err := restored.Deserialize(data); err != nil {
+		t.Fatalf("Failed to deserialize job: %v", err)
+	}
+
+	if err := NewWorker(queue, "default").Process(restored); err != nil {
+		t.Fatalf("Failed to process job: %v", err)
+	}
+	sc := <-traced
+	if sc.TraceID != parent.TraceID || sc.SpanID != parent.SpanID || !sc.Remote || !sc.IsSampled() || sc.TraceState != "vendor=value" {
+		t.Errorf("Expected originating trace context, got %+v", sc)
+	}
+
+	// 没有追踪上下文时不写入标签
+	untraced := NewJob([]byte("payload"), "default")
+	InjectTraceContext(context.Background(), untraced)
+	if _, exists := untraced.GetTags()["traceparent"]; exists {
+		t.Error("Expected no traceparent without trace context")
+	}
+
+	for _, invalid := range []string{"", "00-abc-def-01", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"} {
+		if _, err := ParseTraceParent(invalid); err == nil {
+			t.Errorf("Expected %q to be invalid", invalid)
+		}
+	}
+}
@@ -108,6 +108,10 @@ func NewRabbitMQQueue(config RabbitMQConfig) (*RabbitMQQueue, error) {
 
 // Push 推送任务
 func (rq *RabbitMQQueue) Push(job Job) error {
+	if err := encryptJob(job); err != nil {
+		return err
+	}
+
 	payload, err := job.Serialize()
 	if err != nil {
 		return fmt.Errorf("failed to serialize job: %w", err)
@@ -31,6 +31,10 @@ func NewSQSQueue(config SQSConfig) (*SQSQueue, error) {
 
 // Push 推送任务
 func (sq *SQSQueue) Push(job Job) error {
+	if err := rejectEncryptedJob(job, "sqs"); err != nil {
+		return err
+	}
+
 	// TODO: 实现 SQS 消息发送
 	sq.stats.TotalJobs++
 	sq.stats.LastJobAt = time.Now()
@@ -0,0 +1,178 @@
+package queue
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 追踪上下文使用的任务标签，与 W3C Trace Context 的请求头名称一致
+const (
+	traceParentTag = "traceparent"
+	traceStateTag  = "tracestate"
+)
+
+// SpanContext W3C Trace Context 的追踪上下文
+type SpanContext struct {
+	TraceID    string
+	SpanID     string
+	Flags      byte
+	TraceState string
+	Remote     bool
+}
+
+// IsValid 检查追踪上下文是否有效
+func (sc SpanContext) IsValid() bool {
+	return isHexID(sc.TraceID, 32) && isHexID(sc.SpanID, 16)
+}
+
+// IsSampled 检查是否被采样
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&0x01 == 0x01
+}
+
+// TraceParent 格式化为 traceparent 头
+func (sc SpanContext) TraceParent() string {
+	return fmt.Sprintf("00-%s-%s-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// ParseTraceParent 解析 traceparent 头
+func ParseTraceParent(traceParent string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("invalid traceparent: %q", traceParent)
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil || len(flags) != 1 {
+		return SpanContext{}, fmt.Errorf("invalid traceparent flags: %q", traceParent)
+	}
+
+	sc := SpanContext{
+		TraceID: strings.ToLower(parts[1]),
+		SpanID:  strings.ToLower(parts[2]),
+		Flags:   flags[0],
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("invalid traceparent ids: %q", traceParent)
+	}
+	return sc, nil
+}
+
+// isHexID 检查是否为指定长度且不全为 0 的十六进制 ID
+func isHexID(id string, length int) bool {
+	if len(id) != length || strings.Trim(id, "0") == "" {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// spanContextKey 追踪上下文在 context 中的键
+type spanContextKey struct{}
+
+// ContextWithSpanContext 将追踪上下文存入 context
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext 从 context 获取追踪上下文
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// TracePropagator 在 context 和任务标签之间传播追踪上下文
+//
+// 接入 OpenTelemetry 时可以用 propagation.TraceContext 和 propagation.MapCarrier
+// 实现该接口，使任务处理器中创建的 span 与入队时的 span 属于同一条链路。
+type TracePropagator interface {
+	// Inject 将 context 中的追踪上下文写入 carrier
+	Inject(ctx context.Context, carrier map[string]string)
+
+	// Extract 从 carrier 恢复追踪上下文
+	Extract(ctx context.Context, carrier map[string]string) context.Context
+}
+
+// W3CTracePropagator 基于 traceparent/tracestate 的追踪上下文传播器
+type W3CTracePropagator struct{}
+
+// Inject 写入 traceparent 和 tracestate
+func (W3CTracePropagator) Inject(ctx context.Context, carrier map[string]string) {
+	sc, ok := SpanContextFromContext(ctx)
+	if !ok {
+		return
+	}
+	carrier[traceParentTag] = sc.TraceParent()
+	if sc.TraceState != "" {
+		carrier[traceStateTag] = sc.TraceState
+	}
+}
+
+// Extract 解析 traceparent 和 tracestate，恢复为远程追踪上下文
+func (W3CTracePropagator) Extract(ctx context.Context, carrier map[string]string) context.Context {
+	sc, err := ParseTraceParent(carrier[traceParentTag])
+	if err != nil {
+		return ctx
+	}
+	sc.TraceState = carrier[traceStateTag]
+	sc.Remote = true
+	return ContextWithSpanContext(ctx, sc)
+}
+
+var (
+	tracePropagator   TracePropagator = W3CTracePropagator{}
+	tracePropagatorMu sync.RWMutex
+)
+
+// SetTracePropagator 设置全局的追踪上下文传播器
+func SetTracePropagator(propagator TracePropagator) {
+	tracePropagatorMu.Lock()
+	defer tracePropagatorMu.Unlock()
+	tracePropagator = propagator
+}
+
+// getTracePropagator 获取全局的追踪上下文传播器
+func getTracePropagator() TracePropagator {
+	tracePropagatorMu.RLock()
+	defer tracePropagatorMu.RUnlock()
+	return tracePropagator
+}
+
+// InjectTraceContext 将 context 中的追踪上下文写入任务标签
+func InjectTraceContext(ctx context.Context, job Job) {
+	tags := job.GetTags()
+	if tags == nil {
+		baseJob, ok := job.(*BaseJob)
+		if !ok {
+			return
+		}
+		baseJob.Tags = make(map[string]string)
+		tags = baseJob.Tags
+	}
+	getTracePropagator().Inject(ctx, tags)
+}
+
+// ExtractTraceContext 从任务标签恢复追踪上下文
+func ExtractTraceContext(ctx context.Context, job Job) context.Context {
+	tags := job.GetTags()
+	if len(tags) == 0 {
+		return ctx
+	}
+	return getTracePropagator().Extract(ctx, tags)
+}
+
+// PushContext 推送任务并携带 context 中的追踪上下文
+func PushContext(ctx context.Context, q Queue, job Job) error {
+	InjectTraceContext(ctx, job)
+	return q.Push(job)
+}
+
+// LaterContext 推送延迟任务并携带 context 中的追踪上下文
+func LaterContext(ctx context.Context, q Queue, job Job, delay time.Duration) error {
+	InjectTraceContext(ctx, job)
+	return q.Later(job, delay)
+}
@@ -0,0 +1,189 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// stealRequest 任务窃取请求
+type stealRequest struct {
+	Thief  string `json:"thief"`
+	Victim string `json:"victim"`
+	Count  int    `json:"count"`
+}
+
+// stealGrant 任务窃取响应
+type stealGrant struct {
+	Thief string    `json:"thief"`
+	Jobs  []JobData `json:"jobs"`
+}
+
+// stealLoop 定期检查本节点是否空闲，空闲时向积压最多的节点请求任务
+func (dq *DistributedQueue) stealLoop() {
+	ticker := time.NewTicker(dq.stealInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			dq.trySteal()
+		case <-dq.stopChan:
+			return
+		}
+	}
+}
+
+// trySteal 尝试发起一次任务窃取
+func (dq *DistributedQueue) trySteal() {
+	// 领导者直接处理本地队列
+	if dq.IsLeader() {
+		return
+	}
+
+	// 窃取到的任务还没处理完时不再请求
+	if size, _ := dq.stolen.Size(); size > 0 {
+		return
+	}
+
+	victim := dq.findVictim()
+	if victim == "" {
+		return
+	}
+
+	data, err := json.Marshal(stealRequest{
+		Thief:  dq.nodeID,
+		Victim: victim,
+		Count:  dq.stealBatchSize,
+	})
+	if err != nil {
+		return
+	}
+
+	dq.cluster.Broadcast(ClusterMessage{
+		Type:      "steal_request",
+		NodeID:    dq.nodeID,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+}
+
+// findVictim 根据集群节点上报的积压任务数选择窃取目标
+func (dq *DistributedQueue) findVictim() string {
+	nodes, err := dq.cluster.GetNodes()
+	if err != nil {
+		return ""
+	}
+
+	victim := ""
+	maxPending := dq.stealThreshold - 1
+	for _, node := range nodes {
+		if node.ID == dq.nodeID {
+			continue
+		}
+		pending, err := strconv.Atoi(node.Metadata["pending_jobs"])
+		if err != nil {
+			continue
+		}
+		if pending > maxPending {
+			victim = node.ID
+			maxPending = pending
+		}
+	}
+
+	return victim
+}
+
+// handleStealRequest 处理任务窃取请求
+func (dq *DistributedQueue) handleStealRequest(msg ClusterMessage) {
+	var request stealRequest
+	if err := json.Unmarshal(msg.Data, &request); err != nil {
+		return
+	}
+	if request.Victim != dq.nodeID || !dq.IsLeader() {
+		return
+	}
+
+	// 保留而不是删除任务：窃取方在保留超时前未完成时，任务会重新回到本地队列
+	jobs := dq.MemoryQueue.reserveAvailable(request.Count)
+	if len(jobs) == 0 {
+		return
+	}
+
+	grant := stealGrant{
+		Thief: request.Thief,
+		Jobs:  make([]JobData, 0, len(jobs)),
+	}
+
+	dq.lentMu.Lock()
+	for _, job := range jobs {
+		dq.lent[job.GetID()] = job
+		grant.Jobs = append(grant.Jobs, JobData{
+			ID:        job.GetID(),
+			Payload:   job.GetPayload(),
+			Queue:     job.GetQueue(),
+			Delay:     job.GetDelay(),
+			Timeout:   job.GetTimeout(),
+			Priority:  job.GetPriority(),
+			Tags:      job.GetTags(),
+			Encrypted: job.EncryptedPayload,
+			Nonce:     job.Nonce,
+		})
+	}
+	dq.lentMu.Unlock()
+
+	data, err := json.Marshal(grant)
+	if err != nil {
+		return
+	}
+
+	dq.cluster.Broadcast(ClusterMessage{
+		Type:      "steal_grant",
+		NodeID:    dq.nodeID,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+}
+
+// handleStealGrant 处理任务窃取响应
+func (dq *DistributedQueue) handleStealGrant(msg ClusterMessage) {
+	var grant stealGrant
+	if err := json.Unmarshal(msg.Data, &grant); err != nil {
+		return
+	}
+	if grant.Thief != dq.nodeID {
+		return
+	}
+
+	for _, jobData := range grant.Jobs {
+		// 保留原任务ID，完成时原节点据此删除保留的任务
+		job := NewJob(jobData.Payload, jobData.Queue)
+		job.ID = jobData.ID
+		job.SetTimeout(jobData.Timeout)
+		job.SetPriority(jobData.Priority)
+		for key, value := range jobData.Tags {
+			job.AddTag(key, value)
+		}
+		job.EncryptedPayload = jobData.Encrypted
+		job.Nonce = jobData.Nonce
+
+		dq.stolen.Push(job)
+	}
+}
+
+// popStolen 弹出窃取到的任务，最多等待 1 秒
+func (dq *DistributedQueue) popStolen(ctx context.Context) (Job, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	return dq.stolen.Pop(ctx)
+}
+
+// completeJob 任务处理结束后删除保留，避免保留超时后被重复处理
+func (dq *DistributedQueue) completeJob(job Job) {
+	if err := dq.stolen.Delete(job); err == nil {
+		return
+	}
+	dq.MemoryQueue.Delete(job)
+}
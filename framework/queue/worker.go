@@ -22,12 +22,15 @@ type QueueWorker struct {
 	failed       int64
 	currentJob   *Job
 	stopChan     chan struct{}
-	pauseChan    chan struct{}
 	resumeChan   chan struct{}
+	done         chan struct{}
+	popCancel    context.CancelFunc
 	onFailed     func(Job, error)
 	onCompleted  func(Job)
 	timeout      time.Duration
 	maxAttempts  int
+	sleep        time.Duration
+	encryptor    Encryptor
 	metrics      *WorkerMetrics
 }
 
@@ -39,10 +42,10 @@ func NewWorker(queue Queue, queueName string) *QueueWorker {
 		workerID:    uuid.New().String(),
 		status:      "stopped",
 		stopChan:    make(chan struct{}),
-		pauseChan:   make(chan struct{}),
 		resumeChan:  make(chan struct{}),
 		timeout:     30 * time.Second,
 		maxAttempts: 3,
+		sleep:       100 * time.Millisecond,
 		metrics: &WorkerMetrics{
 			LastJobTime: time.Now(),
 		},
@@ -61,8 +64,8 @@ func (w *QueueWorker) Start() error {
 	w.status = "running"
 	w.startedAt = time.Now()
 	w.stopChan = make(chan struct{})
-	w.pauseChan = make(chan struct{})
 	w.resumeChan = make(chan struct{})
+	w.done = make(chan struct{})
 
 	go w.run()
 	return nil
@@ -79,9 +82,33 @@ func (w *QueueWorker) Stop() error {
 
 	w.status = "stopped"
 	close(w.stopChan)
+	w.cancelPop()
 	return nil
 }
 
+// Drain 停止领取新任务，等待正在处理的任务完成
+//
+// ctx 结束时不再等待并返回 ctx 的错误，正在处理的任务仍会在后台完成。
+func (w *QueueWorker) Drain(ctx context.Context) error {
+	w.mu.RLock()
+	done := w.done
+	w.mu.RUnlock()
+
+	if err := w.Stop(); err != nil {
+		return err
+	}
+	if done == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Pause 暂停工作进程
 func (w *QueueWorker) Pause() error {
 	w.mu.Lock()
@@ -91,8 +118,10 @@ func (w *QueueWorker) Pause() error {
 		return fmt.Errorf("worker is not running")
 	}
 
+	// 取消正在等待的出队，暂停期间不再消费任务
 	w.status = "paused"
-	w.pauseChan <- struct{}{}
+	w.resumeChan = make(chan struct{})
+	w.cancelPop()
 	return nil
 }
 
@@ -106,10 +135,18 @@ func (w *QueueWorker) Resume() error {
 	}
 
 	w.status = "running"
-	w.resumeChan <- struct{}{}
+	close(w.resumeChan)
 	return nil
 }
 
+// cancelPop 取消正在等待的出队
+func (w *QueueWorker) cancelPop() {
+	if w.popCancel != nil {
+		w.popCancel()
+		w.popCancel = nil
+	}
+}
+
 // Process 处理任务
 func (w *QueueWorker) Process(job Job) error {
 	startTime := time.Now()
@@ -139,6 +176,12 @@ func (w *QueueWorker) Process(job Job) error {
 		return err
 	}
 
+	// 解密任务载荷
+	if err := decryptJob(job, w.encryptor); err != nil {
+		w.handleFailed(job, err)
+		return err
+	}
+
 	// 处理任务
 	err := w.processJob(job)
 	if err != nil {
@@ -206,24 +249,53 @@ func (w *QueueWorker) SetMaxAttempts(maxAttempts int) {
 	w.maxAttempts = maxAttempts
 }
 
+// SetSleep 设置队列为空时的等待时间
+func (w *QueueWorker) SetSleep(sleep time.Duration) {
+	w.sleep = sleep
+}
+
+// SetEncryptor 设置解密任务载荷的加密器
+func (w *QueueWorker) SetEncryptor(encryptor Encryptor) {
+	w.encryptor = encryptor
+}
+
 // run 运行工作进程
 func (w *QueueWorker) run() {
-	ctx := context.Background()
-	
+	w.mu.RLock()
+	stopChan := w.stopChan
+	done := w.done
+	w.mu.RUnlock()
+	defer close(done)
+
 	for {
 		select {
-		case <-w.stopChan:
+		case <-stopChan:
 			return
-		case <-w.pauseChan:
-			// 等待恢复信号
-			<-w.resumeChan
-			continue
 		default:
+			w.mu.Lock()
+			if w.status == "paused" {
+				// 等待恢复信号
+				resumeChan := w.resumeChan
+				w.mu.Unlock()
+				select {
+				case <-resumeChan:
+				case <-stopChan:
+				}
+				continue
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			w.popCancel = cancel
+			w.mu.Unlock()
+
 			// 弹出任务
 			job, err := w.queue.Pop(ctx)
+			cancel()
 			if err != nil {
 				// 没有任务，等待一段时间
-				time.Sleep(100 * time.Millisecond)
+				select {
+				case <-time.After(w.sleep):
+				case <-stopChan:
+				}
 				continue
 			}
 
@@ -235,8 +307,11 @@ func (w *QueueWorker) run() {
 
 // processJob 处理单个任务
 func (w *QueueWorker) processJob(job Job) error {
-	// 这里应该根据任务类型调用相应的处理器
-	// 目前只是一个示例实现
+	// 恢复入队时的追踪上下文后调用注册的任务处理器
+	ctx := ExtractTraceContext(context.Background(), job)
+	if handled, err := handlers.dispatch(ctx, job); handled {
+		return err
+	}
 	
 	// 模拟任务处理
 	time.Sleep(10 * time.Millisecond)
@@ -267,6 +342,9 @@ func (w *QueueWorker) handleFailed(job Job, err error) {
 		w.onFailed(job, err)
 	}
 
+	// 终止任务链并更新批次
+	failWorkflowJob(job, err)
+
 	// 记录日志
 	log.Printf("Worker %s failed to process job %s: %v", w.workerID, job.GetID(), err)
 }
@@ -282,6 +360,9 @@ func (w *QueueWorker) handleCompleted(job Job) {
 		w.onCompleted(job)
 	}
 
+	// 推进任务链并更新批次
+	completeWorkflowJob(w.queue, job)
+
 	// 记录日志
 	log.Printf("Worker %s completed job %s", w.workerID, job.GetID())
 }
@@ -309,6 +390,7 @@ type WorkerPool struct {
 	queue   Queue
 	queueName string
 	poolSize int
+	status  string // running, paused, stopped
 	mu      sync.RWMutex
 }
 
@@ -318,6 +400,7 @@ func NewWorkerPool(queue Queue, queueName string, poolSize int) *WorkerPool {
 		queue:     queue,
 		queueName: queueName,
 		poolSize:  poolSize,
+		status:    "stopped",
 		workers:   make([]*QueueWorker, 0, poolSize),
 	}
 }
@@ -336,6 +419,7 @@ func (wp *WorkerPool) Start() error {
 		}
 	}
 
+	wp.status = "running"
 	return nil
 }
 
@@ -350,9 +434,55 @@ func (wp *WorkerPool) Stop() error {
 		}
 	}
 
+	wp.status = "stopped"
 	return nil
 }
 
+// PauseAll 暂停所有工作进程，工作进程保持存活但不再消费任务
+func (wp *WorkerPool) PauseAll() error {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.status != "running" {
+		return fmt.Errorf("worker pool is not running")
+	}
+
+	for _, worker := range wp.workers {
+		if err := worker.Pause(); err != nil {
+			return err
+		}
+	}
+
+	wp.status = "paused"
+	return nil
+}
+
+// ResumeAll 恢复所有工作进程
+func (wp *WorkerPool) ResumeAll() error {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.status != "paused" {
+		return fmt.Errorf("worker pool is not paused")
+	}
+
+	for _, worker := range wp.workers {
+		if err := worker.Resume(); err != nil {
+			return err
+		}
+	}
+
+	wp.status = "running"
+	return nil
+}
+
+// GetStatus 获取工作进程池状态
+func (wp *WorkerPool) GetStatus() string {
+	wp.mu.RLock()
+	defer wp.mu.RUnlock()
+	return wp.status
+}
+
 // GetWorkers 获取所有工作进程
 func (wp *WorkerPool) GetWorkers() []*QueueWorker {
 	wp.mu.RLock()
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-zookeeper/zk"
+
+	"laravel-go/framework/cluster"
 )
 
 // ZookeeperCluster ZooKeeper集群实现（复用定时器的实现）
@@ -18,6 +21,8 @@ type ZookeeperCluster struct {
 	stopChan     chan struct{}
 	electionChan chan bool
 	leaderPath   string
+	membership   cluster.Membership
+	watchOnce    sync.Once
 }
 
 // ZookeeperClusterConfig ZooKeeper集群配置
@@ -74,11 +79,12 @@ func (zc *ZookeeperCluster) Register(nodeID string, info NodeInfo) error {
 
 	// 创建临时节点
 	_, err = zc.conn.Create(path, data, zk.FlagEphemeral, zk.WorldACL(zk.PermAll))
-	if err != nil && err != zk.ErrNodeExists {
-		return err
+	if err == zk.ErrNodeExists {
+		// 已注册时更新节点信息
+		_, err = zc.conn.Set(path, data, -1)
 	}
 
-	return nil
+	return err
 }
 
 // Unregister 注销节点
@@ -377,3 +383,46 @@ func (zc *ZookeeperCluster) GetClusterInfo() (map[string]interface{}, error) {
 
 	return info, nil
 }
+
+// OnNodeJoin 注册节点加入回调
+func (zc *ZookeeperCluster) OnNodeJoin(callback func(NodeInfo)) error {
+	zc.membership.AddJoin(callback)
+	zc.watchOnce.Do(func() {
+		go zc.watchNodes()
+	})
+	return nil
+}
+
+// OnNodeLeave 注册节点离开回调
+func (zc *ZookeeperCluster) OnNodeLeave(callback func(NodeInfo)) error {
+	zc.membership.AddLeave(callback)
+	zc.watchOnce.Do(func() {
+		go zc.watchNodes()
+	})
+	return nil
+}
+
+// watchNodes 监听节点变更，会话过期时 ZooKeeper 会删除临时节点
+func (zc *ZookeeperCluster) watchNodes() {
+	for {
+		_, _, events, err := zc.conn.ChildrenW("/queue/nodes")
+		if err != nil {
+			select {
+			case <-time.After(1 * time.Second):
+				continue
+			case <-zc.stopChan:
+				return
+			}
+		}
+
+		if nodes, err := zc.GetNodes(); err == nil {
+			zc.membership.Sync(nodes)
+		}
+
+		select {
+		case <-events:
+		case <-zc.stopChan:
+			return
+		}
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/consul/api"
+
+	"laravel-go/framework/cluster"
 )
 
 // ConsulCluster Consul集群实现
@@ -18,6 +21,8 @@ type ConsulCluster struct {
 	stopChan     chan struct{}
 	electionChan chan bool
 	sessionID    string
+	membership   cluster.Membership
+	watchOnce    sync.Once
 }
 
 // ConsulClusterConfig Consul集群配置
@@ -268,7 +273,8 @@ func (cc *ConsulCluster) Subscribe(callback func(ClusterMessage)) error {
 	}
 
 	go func() {
-		plan.Run("localhost:8500")
+		// 复用集群的客户端，监听使用配置的 Consul 地址和凭据
+		plan.RunWithClientAndHclog(cc.client, nil)
 	}()
 
 	return nil
@@ -335,3 +341,65 @@ func (cc *ConsulCluster) GetClusterInfo() (map[string]interface{}, error) {
 
 	return info, nil
 }
+
+// OnNodeJoin 注册节点加入回调
+func (cc *ConsulCluster) OnNodeJoin(callback func(NodeInfo)) error {
+	cc.membership.AddJoin(callback)
+	return cc.startNodeWatch()
+}
+
+// OnNodeLeave 注册节点离开回调
+func (cc *ConsulCluster) OnNodeLeave(callback func(NodeInfo)) error {
+	cc.membership.AddLeave(callback)
+	return cc.startNodeWatch()
+}
+
+// startNodeWatch 监听节点变更，会话过期时 Consul 会删除节点键
+func (cc *ConsulCluster) startNodeWatch() error {
+	var watchErr error
+	cc.watchOnce.Do(func() {
+		watchErr = cc.watchNodes()
+	})
+	return watchErr
+}
+
+// watchNodes 监听节点列表
+func (cc *ConsulCluster) watchNodes() error {
+	params := map[string]interface{}{
+		"type":   "keyprefix",
+		"prefix": "scheduler/nodes/",
+		"handler": func(idx uint64, raw interface{}) {
+			pairs, ok := raw.(api.KVPairs)
+			if !ok {
+				return
+			}
+
+			cc.membership.Sync(decodeConsulNodes(pairs))
+		},
+	}
+
+	plan, err := api.Watch(params, nil)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		// 复用集群的客户端，监听使用配置的 Consul 地址和凭据
+		plan.RunWithClientAndHclog(cc.client, nil)
+	}()
+
+	return nil
+}
+
+// decodeConsulNodes 解析节点列表
+func decodeConsulNodes(pairs api.KVPairs) []NodeInfo {
+	nodes := make([]NodeInfo, 0, len(pairs))
+	for _, pair := range pairs {
+		var node NodeInfo
+		if err := json.Unmarshal(pair.Value, &node); err != nil {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
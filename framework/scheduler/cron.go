@@ -18,24 +18,66 @@ type CronExpression struct {
 	Year       []int `json:"year"`
 }
 
-// ParseSchedule 解析调度表达式
+// ParseSchedule 解析调度表达式，按服务器本地时区计算下次运行时间
 func ParseSchedule(schedule string) (time.Time, error) {
+	return NextRunAfter(schedule, time.Now())
+}
+
+// ParseScheduleIn 解析调度表达式，按 loc 时区计算下次运行时间
+func ParseScheduleIn(schedule string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	return NextRunAfter(schedule, time.Now().In(loc))
+}
+
+// NextRunAfter 计算 from 之后的下次运行时间
+//
+// 调度表达式按 from 所在的时区解释，例如 from 位于 Asia/Tokyo 时 "0 0 9 * * *" 表示东京时间 09:00。
+func NextRunAfter(schedule string, from time.Time) (time.Time, error) {
 	// 支持多种格式
 	if strings.HasPrefix(schedule, "@") {
-		return parseSpecialSchedule(schedule)
+		return parseSpecialSchedule(schedule, from)
 	}
 
 	// 标准 Cron 表达式
 	if strings.Count(schedule, " ") >= 5 {
-		return parseCronExpression(schedule)
+		return parseCronExpression(schedule, from)
 	}
 
 	// 简单时间格式
-	return parseSimpleSchedule(schedule)
+	return parseSimpleSchedule(schedule, from)
+}
+
+// specialCronExpressions 特殊调度表达式对应的 Cron 表达式，用于按分钟判断是否到期
+var specialCronExpressions = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// IsDue 检查调度表达式在 now 所在的这一分钟内是否有运行时间，表达式按 now 所在的时区解释
+//
+// 与保存的下次运行时间无关，适合每分钟由系统 cron 启动一次的进程判断任务是否到期。
+func IsDue(schedule string, now time.Time) (bool, error) {
+	if expression, ok := specialCronExpressions[schedule]; ok {
+		schedule = expression
+	}
+
+	minute := now.Truncate(time.Minute)
+	next, err := NextRunAfter(schedule, minute.Add(-time.Nanosecond))
+	if err != nil {
+		return false, err
+	}
+	return next.Before(minute.Add(time.Minute)), nil
 }
 
 // parseCronExpression 解析标准 Cron 表达式
-func parseCronExpression(expression string) (time.Time, error) {
+func parseCronExpression(expression string, from time.Time) (time.Time, error) {
 	parts := strings.Fields(expression)
 	if len(parts) < 5 || len(parts) > 7 {
 		return time.Time{}, ErrInvalidCronExpression
@@ -76,8 +118,8 @@ func parseCronExpression(expression string) (time.Time, error) {
 		}
 	}
 
-	// 计算下次运行时间
-	return cron.NextRun(time.Now())
+	// 计算下次运行时间，从下一秒开始匹配，避免刚执行完的任务在同一秒再次到期
+	return cron.NextRun(from.Add(time.Second - time.Duration(from.Nanosecond())))
 }
 
 // parseField 解析单个字段
@@ -200,6 +242,9 @@ func parseStep(stepStr string, values *[]int, min, max int) error {
 }
 
 // NextRun 计算下次运行时间
+//
+// 时间按 from 所在的时区匹配。夏令时开始时跳过的时刻不会匹配；夏令时结束时重复出现的时刻，
+// 指定了小时的表达式只在第一次出现时匹配，避免每天的任务执行两次。
 func (c *CronExpression) NextRun(from time.Time) (time.Time, error) {
 	now := from
 
@@ -232,27 +277,33 @@ func (c *CronExpression) NextRun(from time.Time) (time.Time, error) {
 			continue
 		}
 
+		// 小时、分钟和秒按绝对时间推进，夏令时切换时不会回到已经检查过的时刻
 		// 检查小时
 		if len(c.Hour) > 0 && !contains(c.Hour, now.Hour()) {
-			nextHour := now.Add(time.Hour)
-			now = time.Date(nextHour.Year(), nextHour.Month(), nextHour.Day(), nextHour.Hour(), 0, 0, 0, now.Location())
+			now = now.Add(time.Hour - sinceHour(now))
 			continue
 		}
 
 		// 检查分钟
 		if len(c.Minute) > 0 && !contains(c.Minute, now.Minute()) {
-			nextMinute := now.Add(time.Minute)
-			now = time.Date(nextMinute.Year(), nextMinute.Month(), nextMinute.Day(), nextMinute.Hour(), nextMinute.Minute(), 0, 0, now.Location())
+			now = now.Add(time.Minute - sinceMinute(now))
 			continue
 		}
 
 		// 检查秒
 		if len(c.Second) > 0 && !contains(c.Second, now.Second()) {
-			nextSecond := now.Add(time.Second)
-			now = time.Date(nextSecond.Year(), nextSecond.Month(), nextSecond.Day(), nextSecond.Hour(), nextSecond.Minute(), nextSecond.Second(), 0, now.Location())
+			now = now.Add(time.Second - time.Duration(now.Nanosecond()))
 			continue
 		}
 
+		// 夏令时结束时重复出现的时刻，跳过整个重复时段
+		if len(c.Hour) > 0 && len(c.Hour) < 24 {
+			if shift := repeatedShift(now); shift > 0 {
+				now = now.Add(shift)
+				continue
+			}
+		}
+
 		// 找到匹配的时间
 		return now, nil
 	}
@@ -260,6 +311,28 @@ func (c *CronExpression) NextRun(from time.Time) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("could not find next run time within 1000 iterations")
 }
 
+// sinceHour 获取 t 距离所在小时开始的时长
+func sinceHour(t time.Time) time.Duration {
+	return time.Duration(t.Minute())*time.Minute + sinceMinute(t)
+}
+
+// sinceMinute 获取 t 距离所在分钟开始的时长
+func sinceMinute(t time.Time) time.Duration {
+	return time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// repeatedShift 检查 t 的本地时间是否在更早的时刻已经出现过（夏令时结束时时钟回拨），
+// 返回两次出现之间的间隔，没有重复时返回 0
+func repeatedShift(t time.Time) time.Duration {
+	_, offset := t.Zone()
+	for _, shift := range []time.Duration{30 * time.Minute, time.Hour, 2 * time.Hour} {
+		if _, earlier := t.Add(-shift).Zone(); time.Duration(earlier-offset)*time.Second == shift {
+			return shift
+		}
+	}
+	return 0
+}
+
 // contains 检查切片是否包含指定值
 func contains(slice []int, value int) bool {
 	for _, v := range slice {
@@ -271,9 +344,7 @@ func contains(slice []int, value int) bool {
 }
 
 // parseSpecialSchedule 解析特殊调度表达式
-func parseSpecialSchedule(schedule string) (time.Time, error) {
-	now := time.Now()
-
+func parseSpecialSchedule(schedule string, now time.Time) (time.Time, error) {
 	switch schedule {
 	case "@yearly", "@annually":
 		return time.Date(now.Year()+1, 1, 1, 0, 0, 0, 0, now.Location()), nil
@@ -292,9 +363,7 @@ func parseSpecialSchedule(schedule string) (time.Time, error) {
 }
 
 // parseSimpleSchedule 解析简单时间格式
-func parseSimpleSchedule(schedule string) (time.Time, error) {
-	now := time.Now()
-
+func parseSimpleSchedule(schedule string, now time.Time) (time.Time, error) {
 	// 尝试解析时间格式
 	layouts := []string{
 		"15:04",
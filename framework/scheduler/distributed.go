@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"laravel-go/framework/cluster"
 )
 
 // DistributedScheduler 分布式调度器
@@ -17,45 +19,39 @@ type DistributedScheduler struct {
 	leaderMu     sync.RWMutex
 	electionMu   sync.Mutex
 	stopElection chan struct{}
-}
-
-// Cluster 集群接口
-type Cluster interface {
-	// 节点管理
-	Register(nodeID string, info NodeInfo) error
-	Unregister(nodeID string) error
-	GetNodes() ([]NodeInfo, error)
-
-	// 分布式锁
-	AcquireLock(key string, ttl time.Duration) (bool, error)
-	ReleaseLock(key string) error
+	nodeInfo     NodeInfo
+	nodeMu       sync.Mutex
 
-	// 选举
-	StartElection(callback func(bool)) error
-	StopElection() error
-
-	// 消息广播
-	Broadcast(msg ClusterMessage) error
-	Subscribe(callback func(ClusterMessage)) error
+	// 任务分配，启用 EnableTaskDistribution 时按一致性哈希把任务分配给存活节点
+	distribute  bool
+	ring        *hashRing
+	lockTimeout time.Duration
 }
 
-// NodeInfo 节点信息
-type NodeInfo struct {
-	ID        string            `json:"id"`
-	Address   string            `json:"address"`
-	Port      int               `json:"port"`
-	Status    string            `json:"status"` // online, offline, leader
-	StartedAt time.Time         `json:"started_at"`
-	LastSeen  time.Time         `json:"last_seen"`
-	Metadata  map[string]string `json:"metadata"`
+// 集群接口和内存集群实现与队列、调度器共用，定义在 cluster 包中
+type (
+	// Cluster 集群接口
+	Cluster = cluster.Cluster
+	// NodeInfo 节点信息
+	NodeInfo = cluster.NodeInfo
+	// ClusterMessage 集群消息
+	ClusterMessage = cluster.ClusterMessage
+	// MemoryCluster 内存集群实现，适用于单进程多节点场景和测试
+	MemoryCluster = cluster.MemoryCluster
+	// MemoryClusterHub 内存集群共享状态
+	MemoryClusterHub = cluster.MemoryClusterHub
+	// MemoryClusterConfig 内存集群配置
+	MemoryClusterConfig = cluster.MemoryClusterConfig
+)
+
+// NewMemoryClusterHub 创建内存集群共享状态
+func NewMemoryClusterHub() *MemoryClusterHub {
+	return cluster.NewMemoryClusterHub()
 }
 
-// ClusterMessage 集群消息
-type ClusterMessage struct {
-	Type      string    `json:"type"`
-	NodeID    string    `json:"node_id"`
-	Timestamp time.Time `json:"timestamp"`
-	Data      []byte    `json:"data"`
+// NewMemoryCluster 创建内存集群
+func NewMemoryCluster(config MemoryClusterConfig) *MemoryCluster {
+	return cluster.NewMemoryCluster(config)
 }
 
 // TaskExecution 任务执行记录
@@ -69,6 +65,8 @@ type TaskExecution struct {
 }
 
 // DistributedConfig 分布式配置
+//
+// EnableTaskDistribution 启用后按一致性哈希把任务分配给存活节点，每个任务只由所有者执行。
 type DistributedConfig struct {
 	NodeID                 string
 	Cluster                Cluster
@@ -96,7 +94,26 @@ func NewDistributedScheduler(store Store, config DistributedConfig) *Distributed
 		nodeID:           config.NodeID,
 		cluster:          config.Cluster,
 		stopElection:     make(chan struct{}),
+		distribute:       config.EnableTaskDistribution && config.Cluster != nil,
+		ring:             newHashRing(defaultRingReplicas),
+		lockTimeout:      config.LockTimeout,
 	}
+	ds.ring.add(ds.nodeID)
+
+	// 节点加入或离开时调整哈希环，只有受影响的任务会换所有者
+	if ds.distribute {
+		ds.cluster.OnNodeJoin(func(node NodeInfo) {
+			ds.ring.add(node.ID)
+		})
+		ds.cluster.OnNodeLeave(func(node NodeInfo) {
+			if node.ID != ds.nodeID {
+				ds.ring.remove(node.ID)
+			}
+		})
+	}
+
+	// 每次触发时重新判断领导权和任务所有者
+	ds.DefaultScheduler.executionGuard = ds.shouldExecute
 
 	return ds
 }
@@ -108,6 +125,13 @@ func (ds *DistributedScheduler) Start() error {
 		return fmt.Errorf("failed to register node: %w", err)
 	}
 
+	// 以当前的节点列表初始化哈希环，之后由成员变更回调维护
+	if ds.distribute {
+		if err := ds.Rebalance(); err != nil {
+			return fmt.Errorf("failed to build task assignment: %w", err)
+		}
+	}
+
 	// 启动选举
 	if err := ds.startElection(); err != nil {
 		return fmt.Errorf("failed to start election: %w", err)
@@ -144,11 +168,117 @@ func (ds *DistributedScheduler) IsLeader() bool {
 	return ds.leader
 }
 
+// shouldExecute 判断本节点是否应执行任务
+//
+// 仅领导者执行的任务在非领导者节点上跳过；启用任务分配时其他任务只由所有者执行。
+// 领导者交接期间集群中没有存活的领导者，此时仅领导者执行的任务由其所有者代为执行，避免漏跑。
+// 启用任务分配时执行前还会按本次计划运行时间抢占集群锁，节点对集群成员的视图暂时不一致时也不会重复执行。
+func (ds *DistributedScheduler) shouldExecute(task Task) bool {
+	if isLeaderOnly(task) {
+		switch {
+		case ds.IsLeader():
+		case ds.distribute && ds.OwnsTask(task.GetID()) && !ds.hasLiveLeader():
+		default:
+			return false
+		}
+	} else if ds.distribute && !ds.OwnsTask(task.GetID()) {
+		return false
+	}
+
+	if !ds.distribute {
+		return true
+	}
+	return ds.claimRun(task)
+}
+
+// isLeaderOnly 检查任务是否实现 LeaderOnlyTask 并要求仅在领导者节点执行
+func isLeaderOnly(task Task) bool {
+	leaderOnly, ok := task.(LeaderOnlyTask)
+	return ok && leaderOnly.IsLeaderOnly()
+}
+
+// Rebalance 按集群当前的在线节点重建任务分配
+func (ds *DistributedScheduler) Rebalance() error {
+	nodes, err := ds.cluster.GetNodes()
+	if err != nil {
+		return err
+	}
+
+	ids := []string{ds.nodeID}
+	for _, node := range nodes {
+		if node.ID != ds.nodeID && (node.Status == "online" || node.Status == "leader") {
+			ids = append(ids, node.ID)
+		}
+	}
+	ds.ring.set(ids)
+	return nil
+}
+
+// TaskOwner 获取任务的所有者节点
+func (ds *DistributedScheduler) TaskOwner(taskID string) string {
+	return ds.ring.owner(taskID)
+}
+
+// OwnsTask 检查本节点是否为任务的所有者
+func (ds *DistributedScheduler) OwnsTask(taskID string) bool {
+	return ds.TaskOwner(taskID) == ds.nodeID
+}
+
+// OwnedTasks 获取分配给本节点的启用任务
+func (ds *DistributedScheduler) OwnedTasks() []Task {
+	tasks := make([]Task, 0)
+	for _, task := range ds.GetEnabled() {
+		if ds.OwnsTask(task.GetID()) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// hasLiveLeader 检查集群中是否有其他存活的领导者
+func (ds *DistributedScheduler) hasLiveLeader() bool {
+	nodes, err := ds.cluster.GetNodes()
+	if err != nil {
+		// 无法确认时视为存在领导者，交由领导者执行
+		return true
+	}
+	return ds.getLeaderID(nodes) != ""
+}
+
+// claimRun 按任务的计划运行时间抢占集群锁，同一次运行只有一个节点能抢到
+//
+// 锁不主动释放，由过期时间回收，执行较快时其他节点也无法再次抢到同一次运行。
+func (ds *DistributedScheduler) claimRun(task Task) bool {
+	scheduled := time.Now().Truncate(time.Second)
+	if next := task.GetNextRunAt(); next != nil {
+		scheduled = *next
+	}
+
+	ttl := ds.lockTimeout
+	if timeout := task.GetTimeout(); timeout > ttl {
+		ttl = timeout
+	}
+
+	key := fmt.Sprintf("task_run_%s_%d", task.GetID(), scheduled.Unix())
+	acquired, err := ds.cluster.AcquireLock(key, ttl)
+	return err == nil && acquired
+}
+
 // GetClusterNodes 获取集群节点
 func (ds *DistributedScheduler) GetClusterNodes() ([]NodeInfo, error) {
 	return ds.cluster.GetNodes()
 }
 
+// OnNodeJoin 注册节点加入集群的回调
+func (ds *DistributedScheduler) OnNodeJoin(callback func(NodeInfo)) error {
+	return ds.cluster.OnNodeJoin(callback)
+}
+
+// OnNodeLeave 注册节点离开集群的回调，包括正常关闭和心跳超时
+func (ds *DistributedScheduler) OnNodeLeave(callback func(NodeInfo)) error {
+	return ds.cluster.OnNodeLeave(callback)
+}
+
 // registerNode 注册节点
 func (ds *DistributedScheduler) registerNode() error {
 	info := NodeInfo{
@@ -162,6 +292,10 @@ func (ds *DistributedScheduler) registerNode() error {
 		},
 	}
 
+	ds.nodeMu.Lock()
+	ds.nodeInfo = info
+	ds.nodeMu.Unlock()
+
 	return ds.cluster.Register(ds.nodeID, info)
 }
 
@@ -202,8 +336,14 @@ func (ds *DistributedScheduler) onLoseLeadership() {
 
 // updateNodeStatus 更新节点状态
 func (ds *DistributedScheduler) updateNodeStatus(status string) {
-	// 这里应该更新节点信息到集群
-	// 具体实现取决于集群接口
+	ds.nodeMu.Lock()
+	ds.nodeInfo.Status = status
+	ds.nodeInfo.LastSeen = time.Now()
+	info := ds.nodeInfo
+	ds.nodeMu.Unlock()
+
+	// 重新注册以刷新节点信息，集群据此判断节点心跳是否超时
+	ds.cluster.Register(ds.nodeID, info)
 }
 
 // heartbeat 心跳
@@ -214,7 +354,11 @@ func (ds *DistributedScheduler) heartbeat() {
 	for {
 		select {
 		case <-ticker.C:
-			ds.updateNodeStatus("online")
+			if ds.IsLeader() {
+				ds.updateNodeStatus("leader")
+			} else {
+				ds.updateNodeStatus("online")
+			}
 		case <-ds.stopElection:
 			return
 		}
@@ -269,6 +413,11 @@ func (ds *DistributedScheduler) handleLeaderChanged(msg ClusterMessage) {
 
 // executeTask 执行任务（分布式版本）
 func (ds *DistributedScheduler) executeTask(task Task) {
+	if !ds.shouldExecute(task) {
+		ds.DefaultScheduler.skipTask(task)
+		return
+	}
+
 	// 获取分布式锁
 	lockKey := fmt.Sprintf("task_execution_%s", task.GetID())
 	acquired, err := ds.cluster.AcquireLock(lockKey, 30*time.Second)
@@ -357,6 +506,7 @@ func (ds *DistributedScheduler) GetDistributedStats() DistributedStats {
 		TotalNodes:  len(nodes),
 		OnlineNodes: ds.countOnlineNodes(nodes),
 		LeaderID:    ds.getLeaderID(nodes),
+		OwnedTasks:  len(ds.OwnedTasks()),
 	}
 }
 
@@ -367,6 +517,7 @@ type DistributedStats struct {
 	TotalNodes  int    `json:"total_nodes"`
 	OnlineNodes int    `json:"online_nodes"`
 	LeaderID    string `json:"leader_id"`
+	OwnedTasks  int    `json:"owned_tasks"`
 }
 
 // countOnlineNodes 统计在线节点
@@ -4,20 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"laravel-go/framework/cluster"
+	"laravel-go/framework/lock"
 )
 
 // EtcdCluster etcd集群实现
 type EtcdCluster struct {
 	client       *clientv3.Client
+	locker       *lock.EtcdLocker
 	nodeID       string
 	ctx          context.Context
 	cancel       context.CancelFunc
 	leaseID      clientv3.LeaseID
 	stopChan     chan struct{}
 	electionChan chan bool
+	membership   cluster.Membership
+	watchOnce    sync.Once
 }
 
 // EtcdClusterConfig etcd集群配置
@@ -44,6 +52,7 @@ func NewEtcdCluster(config EtcdClusterConfig) (*EtcdCluster, error) {
 
 	ec := &EtcdCluster{
 		client:   client,
+		locker:   lock.NewEtcdLocker(client, "/scheduler/locks/"),
 		nodeID:   config.NodeID,
 		ctx:      ctx,
 		cancel:   cancel,
@@ -100,34 +109,17 @@ func (ec *EtcdCluster) GetNodes() ([]NodeInfo, error) {
 
 // AcquireLock 获取分布式锁
 func (ec *EtcdCluster) AcquireLock(key string, ttl time.Duration) (bool, error) {
-	lockKey := fmt.Sprintf("/scheduler/locks/%s", key)
-	value := fmt.Sprintf("%s:%d", ec.nodeID, time.Now().UnixNano())
-
-	// 创建租约
-	lease, err := ec.client.Grant(ec.ctx, int64(ttl.Seconds()))
-	if err != nil {
-		return false, err
-	}
-
-	// 尝试获取锁
-	txn := ec.client.Txn(ec.ctx)
-	txn.If(clientv3.Compare(clientv3.CreateRevision(lockKey), "=", 0)).
-		Then(clientv3.OpPut(lockKey, value, clientv3.WithLease(lease.ID))).
-		Else(clientv3.OpGet(lockKey))
-
-	resp, err := txn.Commit()
-	if err != nil {
-		return false, err
-	}
-
-	return resp.Succeeded, nil
+	return ec.locker.TryLock(ec.ctx, key, ec.nodeID, ttl)
 }
 
 // ReleaseLock 释放分布式锁
 func (ec *EtcdCluster) ReleaseLock(key string) error {
-	lockKey := fmt.Sprintf("/scheduler/locks/%s", key)
-	_, err := ec.client.Delete(ec.ctx, lockKey)
-	return err
+	return ec.locker.ForceUnlock(ec.ctx, key)
+}
+
+// Locker 获取集群使用的锁驱动
+func (ec *EtcdCluster) Locker() lock.Locker {
+	return ec.locker
 }
 
 // StartElection 启动选举
@@ -268,6 +260,9 @@ func (ec *EtcdCluster) Subscribe(callback func(ClusterMessage)) error {
 
 // Close 关闭集群连接
 func (ec *EtcdCluster) Close() error {
+	// 撤销租约，让其他节点立即感知到节点离开
+	ec.client.Revoke(ec.ctx, ec.leaseID)
+
 	ec.cancel()
 	close(ec.stopChan)
 	return ec.client.Close()
@@ -321,3 +316,58 @@ func (ec *EtcdCluster) GetClusterInfo() (map[string]interface{}, error) {
 
 	return info, nil
 }
+
+// OnNodeJoin 注册节点加入回调
+func (ec *EtcdCluster) OnNodeJoin(callback func(NodeInfo)) error {
+	ec.membership.AddJoin(callback)
+	ec.watchOnce.Do(func() {
+		go ec.watchNodes()
+	})
+	return nil
+}
+
+// OnNodeLeave 注册节点离开回调
+func (ec *EtcdCluster) OnNodeLeave(callback func(NodeInfo)) error {
+	ec.membership.AddLeave(callback)
+	ec.watchOnce.Do(func() {
+		go ec.watchNodes()
+	})
+	return nil
+}
+
+// watchNodes 监听节点变更，节点租约过期时 etcd 会删除节点键
+func (ec *EtcdCluster) watchNodes() {
+	prefix := "/scheduler/nodes/"
+	watchChan := ec.client.Watch(ec.ctx, prefix, clientv3.WithPrefix(), clientv3.WithPrevKV())
+
+	for {
+		select {
+		case resp, ok := <-watchChan:
+			if !ok {
+				return
+			}
+			for _, ev := range resp.Events {
+				switch ev.Type {
+				case clientv3.EventTypePut:
+					// 心跳更新节点信息时不触发加入事件
+					if !ev.IsCreate() {
+						continue
+					}
+					var node NodeInfo
+					if err := json.Unmarshal(ev.Kv.Value, &node); err != nil {
+						continue
+					}
+					ec.membership.EmitJoin(node)
+				case clientv3.EventTypeDelete:
+					node := NodeInfo{ID: strings.TrimPrefix(string(ev.Kv.Key), prefix)}
+					if ev.PrevKv != nil {
+						json.Unmarshal(ev.PrevKv.Value, &node)
+					}
+					ec.membership.EmitLeave(node)
+				}
+			}
+		case <-ec.stopChan:
+			return
+		}
+	}
+}
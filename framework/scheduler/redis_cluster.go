@@ -8,11 +8,15 @@ import (
 	"time"
 
 	"github.com/go-redis/redis/v8"
+
+	"laravel-go/framework/cluster"
+	"laravel-go/framework/lock"
 )
 
 // RedisCluster Redis集群实现
 type RedisCluster struct {
 	client       *redis.Client
+	locker       *lock.RedisLocker
 	nodeID       string
 	ctx          context.Context
 	cancel       context.CancelFunc
@@ -20,6 +24,8 @@ type RedisCluster struct {
 	subMu        sync.RWMutex
 	electionChan chan bool
 	stopChan     chan struct{}
+	membership   cluster.Membership
+	watchOnce    sync.Once
 }
 
 // RedisClusterConfig Redis集群配置
@@ -42,6 +48,7 @@ func NewRedisCluster(config RedisClusterConfig) (*RedisCluster, error) {
 
 	rc := &RedisCluster{
 		client:      client,
+		locker:      lock.NewRedisLocker(client, "scheduler:lock:"),
 		nodeID:      config.NodeID,
 		ctx:         ctx,
 		cancel:      cancel,
@@ -102,22 +109,17 @@ func (rc *RedisCluster) GetNodes() ([]NodeInfo, error) {
 
 // AcquireLock 获取分布式锁
 func (rc *RedisCluster) AcquireLock(key string, ttl time.Duration) (bool, error) {
-	lockKey := fmt.Sprintf("scheduler:lock:%s", key)
-	value := fmt.Sprintf("%s:%d", rc.nodeID, time.Now().UnixNano())
-
-	// 使用SET NX EX命令实现分布式锁
-	result, err := rc.client.SetNX(rc.ctx, lockKey, value, ttl).Result()
-	if err != nil {
-		return false, err
-	}
-
-	return result, nil
+	return rc.locker.TryLock(rc.ctx, key, rc.nodeID, ttl)
 }
 
 // ReleaseLock 释放分布式锁
 func (rc *RedisCluster) ReleaseLock(key string) error {
-	lockKey := fmt.Sprintf("scheduler:lock:%s", key)
-	return rc.client.Del(rc.ctx, lockKey).Err()
+	return rc.locker.ForceUnlock(rc.ctx, key)
+}
+
+// Locker 获取集群使用的锁驱动
+func (rc *RedisCluster) Locker() lock.Locker {
+	return rc.locker
 }
 
 // StartElection 启动选举
@@ -219,10 +221,15 @@ func (rc *RedisCluster) Broadcast(msg ClusterMessage) error {
 	return rc.client.Publish(rc.ctx, channel, data).Err()
 }
 
-// Subscribe 订阅消息
+// Subscribe 订阅消息，阻塞直到集群关闭
 func (rc *RedisCluster) Subscribe(callback func(ClusterMessage)) error {
+	return rc.SubscribeContext(rc.ctx, callback)
+}
+
+// SubscribeContext 订阅消息，阻塞直到 ctx 结束或集群关闭
+func (rc *RedisCluster) SubscribeContext(ctx context.Context, callback func(ClusterMessage)) error {
 	channel := "scheduler:messages"
-	pubsub := rc.client.Subscribe(rc.ctx, channel)
+	pubsub := rc.client.Subscribe(ctx, channel)
 	defer pubsub.Close()
 
 	for {
@@ -239,6 +246,8 @@ func (rc *RedisCluster) Subscribe(callback func(ClusterMessage)) error {
 			}
 
 			callback(clusterMsg)
+		case <-ctx.Done():
+			return nil
 		case <-rc.stopChan:
 			return nil
 		}
@@ -247,6 +256,9 @@ func (rc *RedisCluster) Subscribe(callback func(ClusterMessage)) error {
 
 // Close 关闭集群连接
 func (rc *RedisCluster) Close() error {
+	// 删除节点键，让其他节点尽快感知到节点离开
+	rc.client.Del(rc.ctx, fmt.Sprintf("scheduler:nodes:%s", rc.nodeID))
+
 	rc.cancel()
 	close(rc.stopChan)
 	return rc.client.Close()
@@ -296,3 +308,42 @@ func (rc *RedisCluster) GetClusterInfo() (map[string]interface{}, error) {
 
 	return info, nil
 }
+
+// OnNodeJoin 注册节点加入回调
+func (rc *RedisCluster) OnNodeJoin(callback func(NodeInfo)) error {
+	rc.membership.AddJoin(callback)
+	rc.watchOnce.Do(func() {
+		go rc.watchNodes()
+	})
+	return nil
+}
+
+// OnNodeLeave 注册节点离开回调
+func (rc *RedisCluster) OnNodeLeave(callback func(NodeInfo)) error {
+	rc.membership.AddLeave(callback)
+	rc.watchOnce.Do(func() {
+		go rc.watchNodes()
+	})
+	return nil
+}
+
+// watchNodes 轮询节点列表，节点键在心跳超时后过期
+func (rc *RedisCluster) watchNodes() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	if nodes, err := rc.GetNodes(); err == nil {
+		rc.membership.Sync(nodes)
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			if nodes, err := rc.GetNodes(); err == nil {
+				rc.membership.Sync(nodes)
+			}
+		case <-rc.stopChan:
+			return
+		}
+	}
+}
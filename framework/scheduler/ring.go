@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// defaultRingReplicas 每个节点在哈希环上的虚拟节点数
+const defaultRingReplicas = 128
+
+// hashRing 一致性哈希环，用于把任务分配给存活节点
+//
+// 节点变更时只有落在该节点虚拟节点上的任务会迁移，其他任务的所有者保持不变。
+type hashRing struct {
+	mu       sync.RWMutex
+	replicas int
+	hashes   []uint32
+	owners   map[uint32]string
+	nodes    map[string]struct{}
+}
+
+// newHashRing 创建一致性哈希环
+func newHashRing(replicas int) *hashRing {
+	if replicas <= 0 {
+		replicas = defaultRingReplicas
+	}
+	return &hashRing{
+		replicas: replicas,
+		owners:   make(map[uint32]string),
+		nodes:    make(map[string]struct{}),
+	}
+}
+
+// add 添加节点，返回环是否发生变化
+func (r *hashRing) add(nodeID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.nodes[nodeID]; exists || nodeID == "" {
+		return false
+	}
+	r.nodes[nodeID] = struct{}{}
+	r.place(nodeID)
+	r.rebuild()
+	return true
+}
+
+// remove 移除节点，返回环是否发生变化
+func (r *hashRing) remove(nodeID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.nodes[nodeID]; !exists {
+		return false
+	}
+	delete(r.nodes, nodeID)
+
+	// 重新计算全部虚拟节点，避免冲突的虚拟节点在移除后丢失
+	r.owners = make(map[uint32]string)
+	for id := range r.nodes {
+		r.place(id)
+	}
+	r.rebuild()
+	return true
+}
+
+// set 使用节点列表替换环上的节点，返回环是否发生变化
+func (r *hashRing) set(nodeIDs []string) bool {
+	wanted := make(map[string]struct{}, len(nodeIDs))
+	for _, id := range nodeIDs {
+		wanted[id] = struct{}{}
+	}
+
+	changed := false
+	for _, id := range r.members() {
+		if _, ok := wanted[id]; !ok {
+			changed = r.remove(id) || changed
+		}
+	}
+	for id := range wanted {
+		changed = r.add(id) || changed
+	}
+	return changed
+}
+
+// owner 获取 key 的所有者节点，环为空时返回空字符串
+func (r *hashRing) owner(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	hash := ringHash(key)
+	i := sort.Search(len(r.hashes), func(i int) bool {
+		return r.hashes[i] >= hash
+	})
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.owners[r.hashes[i]]
+}
+
+// members 获取环上的节点，按 ID 排序
+func (r *hashRing) members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := make([]string, 0, len(r.nodes))
+	for id := range r.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// place 放置节点的虚拟节点，调用方需持有写锁
+func (r *hashRing) place(nodeID string) {
+	for i := 0; i < r.replicas; i++ {
+		hash := ringHash(nodeID + "#" + strconv.Itoa(i))
+		// 哈希冲突时按节点 ID 取较小者，保证所有节点得到相同的环
+		if owner, exists := r.owners[hash]; exists && owner < nodeID {
+			continue
+		}
+		r.owners[hash] = nodeID
+	}
+}
+
+// rebuild 重建有序的哈希列表，调用方需持有写锁
+func (r *hashRing) rebuild() {
+	r.hashes = r.hashes[:0]
+	for hash := range r.owners {
+		r.hashes = append(r.hashes, hash)
+	}
+	sort.Slice(r.hashes, func(i, j int) bool {
+		return r.hashes[i] < r.hashes[j]
+	})
+}
+
+// ringHash 计算哈希值
+func ringHash(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -46,6 +47,16 @@ type Task interface {
 	Deserialize(data []byte) error
 }
 
+// LeaderOnlyTask 可选接口，IsLeaderOnly 返回 true 的任务在分布式调度器中仅由领导者节点执行
+type LeaderOnlyTask interface {
+	IsLeaderOnly() bool
+}
+
+// DueRunner 可选接口，运行当前这一分钟到期的任务，schedule:run 命令使用
+type DueRunner interface {
+	RunDue() (int, error)
+}
+
 // TaskHandler 任务处理器接口
 type TaskHandler interface {
 	Handle(ctx context.Context) error
@@ -94,6 +105,7 @@ type SchedulerStats struct {
 	DisabledTasks int64     `json:"disabled_tasks"`
 	TotalRuns     int64     `json:"total_runs"`
 	TotalFailed   int64     `json:"total_failed"`
+	TotalSkipped  int64     `json:"total_skipped"`
 	SuccessRate   float64   `json:"success_rate"`
 	LastRunAt     time.Time `json:"last_run_at"`
 	CreatedAt     time.Time `json:"created_at"`
@@ -149,6 +161,9 @@ type DefaultScheduler struct {
 	resumeChan chan struct{}
 	ctx        context.Context
 	cancel     context.CancelFunc
+
+	// executionGuard 在任务触发时判断本节点是否应执行该任务
+	executionGuard func(Task) bool
 }
 
 // NewScheduler 创建新的调度器
@@ -386,6 +401,51 @@ func (s *DefaultScheduler) RunAll() error {
 	return nil
 }
 
+// RunDue 运行当前这一分钟到期的任务并等待执行完成，返回实际运行的任务数量
+//
+// 按任务时区判断调度表达式是否匹配当前这一分钟，不依赖保存的下次运行时间，
+// 适合由系统 cron 每分钟调用一次（schedule:run）。被执行守卫跳过的任务不计入数量。
+func (s *DefaultScheduler) RunDue() (int, error) {
+	if err := s.loadTasks(); err != nil {
+		return 0, err
+	}
+
+	tasks := s.tasksDueInMinute(time.Now())
+
+	var ran int64
+	var wg sync.WaitGroup
+	for _, task := range tasks {
+		wg.Add(1)
+		go func(task Task) {
+			defer wg.Done()
+			if s.executeTask(task) {
+				atomic.AddInt64(&ran, 1)
+			}
+		}(task)
+	}
+	wg.Wait()
+
+	return int(ran), nil
+}
+
+// loadTasks 从存储加载内存中还没有的任务
+func (s *DefaultScheduler) loadTasks() error {
+	tasks, err := s.store.GetAll()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, task := range tasks {
+		if _, exists := s.tasks[task.GetID()]; !exists {
+			s.tasks[task.GetID()] = task
+		}
+	}
+	return nil
+}
+
 // GetStatus 获取调度器状态
 func (s *DefaultScheduler) GetStatus() SchedulerStatus {
 	s.mu.RLock()
@@ -458,25 +518,53 @@ func (s *DefaultScheduler) scheduleLoop() {
 
 // checkAndRunTasks 检查并运行任务
 func (s *DefaultScheduler) checkAndRunTasks() {
+	for _, task := range s.dueTasks(time.Now()) {
+		go s.executeTask(task)
+	}
+}
+
+// dueTasks 获取在 now 时到期的启用任务
+func (s *DefaultScheduler) dueTasks(now time.Time) []Task {
 	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	tasks := make([]Task, 0)
 	for _, task := range s.tasks {
-		if task.GetEnabled() {
+		if task.GetEnabled() && task.GetNextRunAt() != nil && now.After(*task.GetNextRunAt()) {
 			tasks = append(tasks, task)
 		}
 	}
-	s.mu.RUnlock()
+	return tasks
+}
 
-	now := time.Now()
-	for _, task := range tasks {
-		if task.GetNextRunAt() != nil && now.After(*task.GetNextRunAt()) {
-			go s.executeTask(task)
+// tasksDueInMinute 获取调度表达式在 now 所在的这一分钟内到期的启用任务，按任务时区判断
+func (s *DefaultScheduler) tasksDueInMinute(now time.Time) []Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := make([]Task, 0)
+	for _, task := range s.tasks {
+		if !task.GetEnabled() {
+			continue
+		}
+		loc := time.Local
+		if located, ok := task.(interface{ GetLocation() *time.Location }); ok {
+			loc = located.GetLocation()
+		}
+		if due, err := IsDue(task.GetSchedule(), now.In(loc)); err == nil && due {
+			tasks = append(tasks, task)
 		}
 	}
+	return tasks
 }
 
-// executeTask 执行任务
-func (s *DefaultScheduler) executeTask(task Task) {
+// executeTask 执行任务，被执行守卫跳过时返回 false
+func (s *DefaultScheduler) executeTask(task Task) bool {
+	if s.executionGuard != nil && !s.executionGuard(task) {
+		s.skipTask(task)
+		return false
+	}
+
 	ctx, cancel := context.WithTimeout(s.ctx, task.GetTimeout())
 	defer cancel()
 
@@ -502,4 +590,15 @@ func (s *DefaultScheduler) executeTask(task Task) {
 	// 保存到存储
 	s.store.Save(task)
 	s.mu.Unlock()
+	return true
+}
+
+// skipTask 跳过本次执行，只推进下次运行时间，不计入运行或失败次数
+func (s *DefaultScheduler) skipTask(task Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task.UpdateNextRun()
+	s.stats.TotalSkipped++
+	s.store.Save(task)
 }
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -299,3 +300,337 @@ func TestTaskBuilder(t *testing.T) {
 		t.Errorf("Expected tag 'test'='true', got '%s'", tags["test"])
 	}
 }
+
+func TestLeaderOnlyTask(t *testing.T) {
+	var runs int64
+	handler := NewFuncHandler("leader-only", func(ctx context.Context) error {
+		runs++
+		return nil
+	})
+
+	leader := NewDistributedScheduler(NewMemoryStore(), DistributedConfig{NodeID: "node-1"})
+	follower := NewDistributedScheduler(NewMemoryStore(), DistributedConfig{NodeID: "node-2"})
+	leader.leader = true
+
+	leaderTask := NewTask("cleanup", "Cleanup task", "0 * * * * *", handler).LeaderOnly()
+	followerTask := leaderTask.Clone()
+
+	if !leaderTask.IsLeaderOnly() || !followerTask.IsLeaderOnly() {
+		t.Fatal("Expected task to be leader-only")
+	}
+
+	leader.Add(leaderTask)
+	follower.Add(followerTask)
+
+	// 领导者执行，跟随者跳过
+	leader.DefaultScheduler.executeTask(leaderTask)
+	follower.DefaultScheduler.executeTask(followerTask)
+
+	if runs != 1 {
+		t.Errorf("Expected 1 run, got %d", runs)
+	}
+
+	followerStats := follower.GetStats()
+	if followerStats.TotalFailed != 0 {
+		t.Errorf("Expected no failures on follower, got %d", followerStats.TotalFailed)
+	}
+	if followerStats.TotalSkipped != 1 {
+		t.Errorf("Expected 1 skipped run on follower, got %d", followerStats.TotalSkipped)
+	}
+	if followerTask.GetFailedCount() != 0 || followerTask.GetRunCount() != 0 {
+		t.Error("Skipped run should not be counted on the task")
+	}
+
+	// 领导权在触发时重新判断
+	leader.leader = false
+	follower.leader = true
+	leader.DefaultScheduler.executeTask(leaderTask)
+	follower.DefaultScheduler.executeTask(followerTask)
+
+	if runs != 2 {
+		t.Errorf("Expected 2 runs, got %d", runs)
+	}
+	if followerTask.GetRunCount() != 1 {
+		t.Errorf("Expected new leader to run the task once, got %d", followerTask.GetRunCount())
+	}
+	if leader.GetStats().TotalSkipped != 1 {
+		t.Errorf("Expected former leader to skip the task, got %d", leader.GetStats().TotalSkipped)
+	}
+}
+
+func TestDistributedSchedulerNodeCallbacks(t *testing.T) {
+	hub := NewMemoryClusterHub()
+	ds := NewDistributedScheduler(NewMemoryStore(), DistributedConfig{
+		NodeID:  "node-1",
+		Cluster: NewMemoryCluster(MemoryClusterConfig{NodeID: "node-1", Hub: hub}),
+	})
+
+	var joined, left string
+	ds.OnNodeJoin(func(node NodeInfo) {
+		joined = node.ID
+	})
+	ds.OnNodeLeave(func(node NodeInfo) {
+		left = node.ID
+	})
+
+	peer := NewMemoryCluster(MemoryClusterConfig{NodeID: "node-2", Hub: hub})
+	peer.Register("node-2", NodeInfo{Status: "online"})
+	if joined != "node-2" {
+		t.Errorf("Expected node-2 to join, got %q", joined)
+	}
+
+	peer.Close()
+	if left != "node-2" {
+		t.Errorf("Expected node-2 to leave, got %q", left)
+	}
+}
+
+func TestDistributedTaskRebalancing(t *testing.T) {
+	hub := NewMemoryClusterHub()
+	runs := make(map[string]int)
+	handler := NewFuncHandler("count", func(ctx context.Context) error {
+		return nil
+	})
+
+	var tasks []*DefaultTask
+	for i := 0; i < 30; i++ {
+		tasks = append(tasks, NewTask(fmt.Sprintf("task-%d", i), "", "0 0 * * * *", handler))
+	}
+
+	nodes := make(map[string]*DistributedScheduler)
+	clusters := make(map[string]*MemoryCluster)
+	for _, id := range []string{"node-1", "node-2", "node-3"} {
+		cluster := NewMemoryCluster(MemoryClusterConfig{NodeID: id, Hub: hub})
+		ds := NewDistributedScheduler(NewMemoryStore(), DistributedConfig{
+			NodeID:                 id,
+			Cluster:                cluster,
+			EnableTaskDistribution: true,
+		})
+		for _, task := range tasks {
+			// 各节点加载同一个任务，ID 相同
+			clone := task.Clone()
+			clone.ID = task.ID
+			clone.Handler = NewFuncHandler("count", func(ctx context.Context) error {
+				runs[clone.ID]++
+				return nil
+			})
+			ds.Add(clone)
+		}
+		nodes[id] = ds
+		clusters[id] = cluster
+	}
+	for id, cluster := range clusters {
+		cluster.Register(id, NodeInfo{Status: "online"})
+	}
+
+	// 每个任务恰好有一个所有者，所有节点的视图一致
+	owners := make(map[string]string)
+	for _, task := range tasks {
+		count := 0
+		for id, ds := range nodes {
+			if ds.OwnsTask(task.ID) {
+				owners[task.ID] = id
+				count++
+			}
+			if ds.TaskOwner(task.ID) != nodes["node-1"].TaskOwner(task.ID) {
+				t.Fatalf("Nodes disagree on owner of %s", task.Name)
+			}
+		}
+		if count != 1 {
+			t.Fatalf("Expected exactly one owner for %s, got %d", task.Name, count)
+		}
+	}
+
+	// 模拟所有节点在 at 时触发全部任务
+	runAll := func(at time.Time) {
+		for _, ds := range nodes {
+			for _, task := range ds.GetEnabled() {
+				task.(*DefaultTask).NextRunAt = &at
+				ds.DefaultScheduler.executeTask(task)
+			}
+		}
+	}
+
+	first := time.Now().Truncate(time.Hour)
+	runAll(first)
+	for _, task := range tasks {
+		if runs[task.ID] != 1 {
+			t.Errorf("Expected %s to run once, got %d", task.Name, runs[task.ID])
+		}
+	}
+
+	// 移除 node-3，其任务迁移到剩余节点，其他任务的所有者不变
+	clusters["node-3"].Close()
+	delete(nodes, "node-3")
+
+	moved := 0
+	for _, task := range tasks {
+		owner := nodes["node-1"].TaskOwner(task.ID)
+		if owner == "node-3" {
+			t.Fatalf("Task %s still assigned to removed node", task.Name)
+		}
+		if owners[task.ID] != "node-3" && owner != owners[task.ID] {
+			t.Errorf("Task %s moved from %s to %s", task.Name, owners[task.ID], owner)
+		}
+		if owners[task.ID] == "node-3" {
+			moved++
+		}
+	}
+	if moved == 0 {
+		t.Fatal("Expected node-3 to own some tasks")
+	}
+
+	runAll(first.Add(time.Hour))
+	for _, task := range tasks {
+		if runs[task.ID] != 2 {
+			t.Errorf("Expected %s to run twice without duplication, got %d", task.Name, runs[task.ID])
+		}
+	}
+
+	// 没有存活的领导者时，仅领导者执行的任务由所有者代为执行
+	leaderTask := NewTask("leader", "", "0 0 * * * *", handler).LeaderOnly()
+	executed := 0
+	for _, ds := range nodes {
+		clone := leaderTask.Clone()
+		clone.ID = leaderTask.ID
+		clone.Handler = NewFuncHandler("leader", func(ctx context.Context) error {
+			executed++
+			return nil
+		})
+		ds.Add(clone)
+		ds.DefaultScheduler.executeTask(clone)
+	}
+	if executed != 1 {
+		t.Errorf("Expected leader-only task to run once during handoff, got %d", executed)
+	}
+}
+
+func TestTaskTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("Timezone data unavailable: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("Timezone data unavailable: %v", err)
+	}
+
+	// 东京时间 09:00 对应 UTC 00:00
+	from := time.Date(2025, 2, 28, 22, 0, 0, 0, time.UTC)
+	next, err := NextRunAfter("0 0 9 * * *", from.In(tokyo))
+	if err != nil {
+		t.Fatalf("Failed to compute next run: %v", err)
+	}
+	if expected := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC); !next.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, next.UTC())
+	}
+
+	// 调度器在该 UTC 时刻之后才认为任务到期
+	handler := NewFuncHandler("report", func(ctx context.Context) error {
+		return nil
+	})
+	task := NewTask("report", "Daily report", "0 0 9 * * *", handler).InTimezone(tokyo)
+	if local := task.GetNextRunAt().In(tokyo); local.Hour() != 9 || local.Minute() != 0 {
+		t.Errorf("Expected next run at 09:00 Tokyo time, got %s", local)
+	}
+	task.NextRunAt = &next
+	scheduler := NewScheduler(NewMemoryStore())
+	scheduler.Add(task)
+	if len(scheduler.dueTasks(next.Add(-time.Second))) != 0 {
+		t.Error("Task should not be due before 09:00 Tokyo time")
+	}
+	if len(scheduler.dueTasks(next.Add(time.Millisecond))) != 1 {
+		t.Error("Task should be due after 09:00 Tokyo time")
+	}
+
+	// 时区随任务持久化
+	data, err := task.Serialize()
+	if err != nil {
+		t.Fatalf("Failed to serialize task: %v", err)
+	}
+	restored := &DefaultTask{}
+	if err := restored.Deserialize(data); err != nil {
+		t.Fatalf("Failed to deserialize task: %v", err)
+	}
+	if restored.GetLocation().String() != "Asia/Tokyo" {
+		t.Errorf("Expected timezone to persist, got %s", restored.GetLocation())
+	}
+
+	// 夏令时开始前后都在纽约时间 09:00 执行，对应的 UTC 时刻从 14:00 变为 13:00
+	first, _ := NextRunAfter("0 0 9 * * *", time.Date(2025, 3, 7, 12, 0, 0, 0, newYork))
+	second, _ := NextRunAfter("0 0 9 * * *", first)
+	if expected := time.Date(2025, 3, 8, 14, 0, 0, 0, time.UTC); !first.Equal(expected) {
+		t.Errorf("Expected %s before DST, got %s", expected, first.UTC())
+	}
+	if expected := time.Date(2025, 3, 9, 13, 0, 0, 0, time.UTC); !second.Equal(expected) {
+		t.Errorf("Expected %s after DST, got %s", expected, second.UTC())
+	}
+
+	// 夏令时开始时不存在的 02:30 当天跳过
+	skipped, _ := NextRunAfter("0 30 2 * * *", time.Date(2025, 3, 9, 0, 0, 0, 0, newYork))
+	if expected := time.Date(2025, 3, 10, 2, 30, 0, 0, newYork); !skipped.Equal(expected) {
+		t.Errorf("Expected nonexistent time to be skipped, got %s", skipped)
+	}
+
+	// 夏令时结束时重复出现的 01:30 只执行一次
+	first, _ = NextRunAfter("0 30 1 * * *", time.Date(2025, 11, 2, 0, 0, 0, 0, newYork))
+	second, _ = NextRunAfter("0 30 1 * * *", first)
+	if expected := time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC); !first.Equal(expected) {
+		t.Errorf("Expected %s, got %s", expected, first.UTC())
+	}
+	if expected := time.Date(2025, 11, 3, 6, 30, 0, 0, time.UTC); !second.Equal(expected) {
+		t.Errorf("Expected repeated 01:30 to run once, next run %s, got %s", expected, second.UTC())
+	}
+}
+
+func TestRunDueMatchesCurrentMinute(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	handler := NewFuncHandler("noop", func(ctx context.Context) error {
+		return nil
+	})
+
+	// 新建任务的下次运行时间在未来，到期按任务时区的调度表达式判断
+	scheduler := NewScheduler(NewMemoryStore())
+	report := NewTask("report", "Tokyo report", "0 0 9 * * *", handler).InTimezone(tokyo)
+	hourly := NewTask("hourly", "Hourly task", "@hourly", handler).InTimezone(time.UTC)
+	scheduler.Add(report)
+	scheduler.Add(hourly)
+
+	// 东京时间 09:00 即 UTC 00:00，这一分钟内两个任务都到期
+	if due := scheduler.tasksDueInMinute(time.Date(2025, 3, 1, 0, 0, 40, 0, time.UTC)); len(due) != 2 {
+		t.Errorf("Expected 2 tasks due at 09:00 Tokyo time, got %d", len(due))
+	}
+	if due := scheduler.tasksDueInMinute(time.Date(2025, 3, 1, 0, 1, 0, 0, time.UTC)); len(due) != 0 {
+		t.Errorf("Expected no tasks due a minute later, got %d", len(due))
+	}
+	if due := scheduler.tasksDueInMinute(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)); len(due) != 1 || due[0].GetID() != hourly.GetID() {
+		t.Errorf("Expected only the hourly task due at 09:00 UTC, got %d", len(due))
+	}
+
+	// 被执行守卫跳过的任务不计入运行数量
+	var runs int64
+	counting := NewFuncHandler("counting", func(ctx context.Context) error {
+		atomic.AddInt64(&runs, 1)
+		return nil
+	})
+	runner := NewScheduler(NewMemoryStore())
+	allowed := NewTask("allowed", "", "0 * * * * *", counting)
+	skipped := NewTask("skipped", "", "0 * * * * *", counting)
+	runner.Add(allowed)
+	runner.Add(skipped)
+	runner.executionGuard = func(task Task) bool {
+		return task.GetID() == allowed.GetID()
+	}
+
+	count, err := runner.RunDue()
+	if err != nil {
+		t.Fatalf("Failed to run due tasks: %v", err)
+	}
+	if count != 1 || atomic.LoadInt64(&runs) != 1 {
+		t.Errorf("Expected 1 task to run, got count=%d runs=%d", count, runs)
+	}
+}
@@ -32,6 +32,13 @@ type DefaultTask struct {
 	RetryDelay time.Duration     `json:"retry_delay"`
 	MaxRetries int               `json:"max_retries"`
 	Tags       map[string]string `json:"tags"`
+
+	// 仅在领导者节点执行
+	OnlyOnLeader bool `json:"leader_only"`
+
+	// 调度表达式使用的时区，为空时使用服务器本地时区
+	Timezone string `json:"timezone,omitempty"`
+	location *time.Location
 }
 
 // NewTask 创建新任务
@@ -149,6 +156,11 @@ func (t *DefaultTask) GetTags() map[string]string {
 	return t.Tags
 }
 
+// IsLeaderOnly 是否仅在领导者节点执行
+func (t *DefaultTask) IsLeaderOnly() bool {
+	return t.OnlyOnLeader
+}
+
 // Enable 启用任务
 func (t *DefaultTask) Enable() {
 	t.Enabled = true
@@ -161,9 +173,22 @@ func (t *DefaultTask) Disable() {
 	t.UpdatedAt = time.Now()
 }
 
+// GetLocation 获取调度表达式使用的时区
+func (t *DefaultTask) GetLocation() *time.Location {
+	if t.location != nil {
+		return t.location
+	}
+	if t.Timezone != "" {
+		if loc, err := time.LoadLocation(t.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
 // UpdateNextRun 更新下次运行时间
 func (t *DefaultTask) UpdateNextRun() {
-	nextRun, err := ParseSchedule(t.Schedule)
+	nextRun, err := ParseScheduleIn(t.Schedule, t.GetLocation())
 	if err == nil {
 		t.NextRunAt = &nextRun
 	}
@@ -211,6 +236,25 @@ func (t *DefaultTask) SetRetryDelay(retryDelay time.Duration) {
 	t.UpdatedAt = time.Now()
 }
 
+// LeaderOnly 设置任务仅在领导者节点执行，非领导者节点会跳过该任务
+func (t *DefaultTask) LeaderOnly() *DefaultTask {
+	t.OnlyOnLeader = true
+	t.UpdatedAt = time.Now()
+	return t
+}
+
+// InTimezone 设置调度表达式使用的时区，例如 "0 0 9 * * *" 表示该时区的 09:00，夏令时切换后仍按当地时间执行
+func (t *DefaultTask) InTimezone(loc *time.Location) *DefaultTask {
+	t.location = loc
+	t.Timezone = ""
+	if loc != nil && loc != time.Local {
+		t.Timezone = loc.String()
+	}
+	t.UpdatedAt = time.Now()
+	t.UpdateNextRun()
+	return t
+}
+
 // AddTag 添加标签
 func (t *DefaultTask) AddTag(key, value string) {
 	if t.Tags == nil {
@@ -235,7 +279,19 @@ func (t *DefaultTask) Serialize() ([]byte, error) {
 
 // Deserialize 反序列化任务
 func (t *DefaultTask) Deserialize(data []byte) error {
-	return json.Unmarshal(data, t)
+	if err := json.Unmarshal(data, t); err != nil {
+		return err
+	}
+
+	t.location = nil
+	if t.Timezone != "" {
+		loc, err := time.LoadLocation(t.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", t.Timezone, err)
+		}
+		t.location = loc
+	}
+	return nil
 }
 
 // Validate 验证任务
@@ -252,6 +308,13 @@ func (t *DefaultTask) Validate() error {
 		return ErrInvalidSchedule
 	}
 
+	// 验证时区
+	if t.Timezone != "" {
+		if _, err := time.LoadLocation(t.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+
 	// 验证调度表达式
 	_, err := ParseSchedule(t.Schedule)
 	if err != nil {
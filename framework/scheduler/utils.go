@@ -264,6 +264,18 @@ func (b *TaskBuilder) AddTag(key, value string) *TaskBuilder {
 	return b
 }
 
+// LeaderOnly 仅在领导者节点执行
+func (b *TaskBuilder) LeaderOnly() *TaskBuilder {
+	b.task.LeaderOnly()
+	return b
+}
+
+// InTimezone 设置调度表达式使用的时区
+func (b *TaskBuilder) InTimezone(loc *time.Location) *TaskBuilder {
+	b.task.InTimezone(loc)
+	return b
+}
+
 // Disable 禁用任务
 func (b *TaskBuilder) Disable() *TaskBuilder {
 	b.task.Disable()
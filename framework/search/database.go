@@ -0,0 +1,173 @@
+package search
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"laravel-go/framework/database"
+)
+
+// DatabaseDriver 基于数据库 LIKE 查询的搜索驱动，适合没有搜索服务的小型应用
+//
+// 文档保存在单独的索引表中，content 列为文档所有字段值的小写拼接。
+// 查询按空白拆分为词，匹配任意一个词的文档都会返回，按词出现的次数排序。
+type DatabaseDriver struct {
+	connection database.Connection
+	table      string
+}
+
+// NewDatabaseDriver 创建数据库搜索驱动，table 为空时使用 search_index 表
+func NewDatabaseDriver(connection database.Connection, table string) *DatabaseDriver {
+	if table == "" {
+		table = "search_index"
+	}
+	return &DatabaseDriver{
+		connection: connection,
+		table:      table,
+	}
+}
+
+// CreateTable 创建索引表
+func (d *DatabaseDriver) CreateTable() error {
+	_, err := d.connection.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		index_name VARCHAR(191) NOT NULL,
+		document_id VARCHAR(191) NOT NULL,
+		content TEXT NOT NULL,
+		document TEXT NOT NULL,
+		PRIMARY KEY (index_name, document_id)
+	)`, d.table))
+	return err
+}
+
+// Index 写入或替换文档
+func (d *DatabaseDriver) Index(index, id string, document map[string]interface{}) error {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	content := documentContent(document)
+
+	result, err := d.statement(
+		fmt.Sprintf("UPDATE %s SET content = ?, document = ? WHERE index_name = ? AND document_id = ?", d.table),
+		content, string(data), index, id,
+	)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected > 0 {
+		return nil
+	}
+
+	_, err = d.statement(
+		fmt.Sprintf("INSERT INTO %s (index_name, document_id, content, document) VALUES (?, ?, ?, ?)", d.table),
+		index, id, content, string(data),
+	)
+	return err
+}
+
+// Delete 删除文档
+func (d *DatabaseDriver) Delete(index, id string) error {
+	_, err := d.statement(fmt.Sprintf("DELETE FROM %s WHERE index_name = ? AND document_id = ?", d.table), index, id)
+	return err
+}
+
+// Flush 清空索引
+func (d *DatabaseDriver) Flush(index string) error {
+	_, err := d.statement(fmt.Sprintf("DELETE FROM %s WHERE index_name = ?", d.table), index)
+	return err
+}
+
+// Search 搜索文档，空查询返回索引中的所有文档
+func (d *DatabaseDriver) Search(index, query string, options Options) (*Results, error) {
+	terms := strings.Fields(strings.ToLower(query))
+
+	sqlQuery := fmt.Sprintf("SELECT document_id, content, document FROM %s WHERE index_name = ?", d.table)
+	args := []interface{}{index}
+	if len(terms) > 0 {
+		conditions := make([]string, len(terms))
+		for i, term := range terms {
+			conditions[i] = "content LIKE ? ESCAPE '!'"
+			args = append(args, "%"+escapeLike(term)+"%")
+		}
+		sqlQuery += " AND (" + strings.Join(conditions, " OR ") + ")"
+	}
+	sqlQuery += " ORDER BY document_id"
+
+	rows, err := database.NewQueryBuilder(d.connection).Raw(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, 0, len(rows))
+	for _, row := range rows {
+		content := fmt.Sprint(row["content"])
+		var document map[string]interface{}
+		if err := json.Unmarshal([]byte(fmt.Sprint(row["document"])), &document); err != nil {
+			return nil, err
+		}
+		if !matchesFilters(document, options.Filters) {
+			continue
+		}
+
+		score := 0
+		for _, term := range terms {
+			score += strings.Count(content, term)
+		}
+		hits = append(hits, Hit{ID: fmt.Sprint(row["document_id"]), Score: float64(score), Document: document})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+
+	total := len(hits)
+	if options.Offset >= len(hits) {
+		hits = hits[:0]
+	} else {
+		hits = hits[options.Offset:]
+	}
+	if options.Limit > 0 && len(hits) > options.Limit {
+		hits = hits[:options.Limit]
+	}
+	return &Results{Hits: hits, Total: total}, nil
+}
+
+// statement 执行命令，占位符按连接的驱动转换
+func (d *DatabaseDriver) statement(query string, args ...interface{}) (sql.Result, error) {
+	return database.NewQueryBuilder(d.connection).Statement(query, args...)
+}
+
+// documentContent 拼接文档的字段值作为搜索内容
+func documentContent(document map[string]interface{}) string {
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if value := document[key]; value != nil {
+			values = append(values, fmt.Sprint(value))
+		}
+	}
+	return strings.ToLower(strings.Join(values, " "))
+}
+
+// matchesFilters 检查文档字段是否与过滤条件相等
+func matchesFilters(document map[string]interface{}, filters map[string]interface{}) bool {
+	for field, expected := range filters {
+		if fmt.Sprint(document[field]) != fmt.Sprint(expected) {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeLike 转义 LIKE 通配符，转义字符为 !
+func escapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
@@ -0,0 +1,219 @@
+package search
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// httpClient 搜索服务的 JSON HTTP 客户端
+type httpClient struct {
+	host    string
+	headers map[string]string
+	client  *http.Client
+}
+
+// newHTTPClient 创建 HTTP 客户端
+func newHTTPClient(host string, headers map[string]string) *httpClient {
+	return &httpClient{
+		host:    strings.TrimRight(host, "/"),
+		headers: headers,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// do 发送请求，out 不为 nil 时解码响应；notFoundOK 为 true 时忽略 404
+func (c *httpClient) do(method, path string, body interface{}, out interface{}, notFoundOK bool) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.host+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if notFoundOK && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("search server returned %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// MeilisearchDriver Meilisearch 搜索驱动
+//
+// 文档以 id 字段作为主键写入；Options.Filters 需要先在索引上配置为 filterableAttributes。
+type MeilisearchDriver struct {
+	client *httpClient
+}
+
+// NewMeilisearchDriver 创建 Meilisearch 搜索驱动，apiKey 为空时不发送认证头
+func NewMeilisearchDriver(host, apiKey string) *MeilisearchDriver {
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+	return &MeilisearchDriver{client: newHTTPClient(host, headers)}
+}
+
+// Index 写入或替换文档，写入为异步任务，完成前可能搜索不到
+func (d *MeilisearchDriver) Index(index, id string, document map[string]interface{}) error {
+	doc := make(map[string]interface{}, len(document)+1)
+	for key, value := range document {
+		doc[key] = value
+	}
+	doc["id"] = id
+	return d.client.do(http.MethodPost, "/indexes/"+url.PathEscape(index)+"/documents?primaryKey=id", []interface{}{doc}, nil, false)
+}
+
+// Delete 删除文档
+func (d *MeilisearchDriver) Delete(index, id string) error {
+	return d.client.do(http.MethodDelete, "/indexes/"+url.PathEscape(index)+"/documents/"+url.PathEscape(id), nil, nil, true)
+}
+
+// Flush 清空索引
+func (d *MeilisearchDriver) Flush(index string) error {
+	return d.client.do(http.MethodDelete, "/indexes/"+url.PathEscape(index)+"/documents", nil, nil, true)
+}
+
+// Search 搜索文档
+func (d *MeilisearchDriver) Search(index, query string, options Options) (*Results, error) {
+	body := map[string]interface{}{
+		"q":                query,
+		"limit":            options.Limit,
+		"offset":           options.Offset,
+		"showRankingScore": true,
+	}
+	if len(options.Filters) > 0 {
+		body["filter"] = meilisearchFilter(options.Filters)
+	}
+
+	var response struct {
+		Hits               []map[string]interface{} `json:"hits"`
+		EstimatedTotalHits int                      `json:"estimatedTotalHits"`
+	}
+	if err := d.client.do(http.MethodPost, "/indexes/"+url.PathEscape(index)+"/search", body, &response, false); err != nil {
+		return nil, err
+	}
+
+	results := &Results{Hits: make([]Hit, 0, len(response.Hits)), Total: response.EstimatedTotalHits}
+	for _, document := range response.Hits {
+		score, _ := document["_rankingScore"].(float64)
+		delete(document, "_rankingScore")
+		results.Hits = append(results.Hits, Hit{ID: fmt.Sprint(document["id"]), Score: score, Document: document})
+	}
+	return results, nil
+}
+
+// meilisearchFilter 将过滤条件转换为 Meilisearch 过滤表达式
+func meilisearchFilter(filters map[string]interface{}) string {
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	expressions := make([]string, len(fields))
+	for i, field := range fields {
+		value, _ := json.Marshal(fmt.Sprint(filters[field]))
+		expressions[i] = fmt.Sprintf("%s = %s", field, value)
+	}
+	return strings.Join(expressions, " AND ")
+}
+
+// ElasticsearchDriver Elasticsearch 搜索驱动
+type ElasticsearchDriver struct {
+	client *httpClient
+}
+
+// NewElasticsearchDriver 创建 Elasticsearch 搜索驱动，username 为空时不使用基本认证
+func NewElasticsearchDriver(host, username, password string) *ElasticsearchDriver {
+	headers := map[string]string{}
+	if username != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+	return &ElasticsearchDriver{client: newHTTPClient(host, headers)}
+}
+
+// Index 写入或替换文档
+func (d *ElasticsearchDriver) Index(index, id string, document map[string]interface{}) error {
+	return d.client.do(http.MethodPut, "/"+url.PathEscape(index)+"/_doc/"+url.PathEscape(id), document, nil, false)
+}
+
+// Delete 删除文档
+func (d *ElasticsearchDriver) Delete(index, id string) error {
+	return d.client.do(http.MethodDelete, "/"+url.PathEscape(index)+"/_doc/"+url.PathEscape(id), nil, nil, true)
+}
+
+// Flush 清空索引
+func (d *ElasticsearchDriver) Flush(index string) error {
+	body := map[string]interface{}{"query": map[string]interface{}{"match_all": map[string]interface{}{}}}
+	return d.client.do(http.MethodPost, "/"+url.PathEscape(index)+"/_delete_by_query", body, nil, true)
+}
+
+// Search 搜索文档，查询匹配所有字段
+func (d *ElasticsearchDriver) Search(index, query string, options Options) (*Results, error) {
+	must := map[string]interface{}{"match_all": map[string]interface{}{}}
+	if strings.TrimSpace(query) != "" {
+		must = map[string]interface{}{"multi_match": map[string]interface{}{"query": query, "fields": []string{"*"}}}
+	}
+	filters := make([]interface{}, 0, len(options.Filters))
+	for field, value := range options.Filters {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{field: value}})
+	}
+	body := map[string]interface{}{
+		"from":  options.Offset,
+		"size":  options.Limit,
+		"query": map[string]interface{}{"bool": map[string]interface{}{"must": must, "filter": filters}},
+	}
+
+	var response struct {
+		Hits struct {
+			Total struct {
+				Value int `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID     string                 `json:"_id"`
+				Score  float64                `json:"_score"`
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := d.client.do(http.MethodPost, "/"+url.PathEscape(index)+"/_search", body, &response, false); err != nil {
+		return nil, err
+	}
+
+	results := &Results{Hits: make([]Hit, 0, len(response.Hits.Hits)), Total: response.Hits.Total.Value}
+	for _, hit := range response.Hits.Hits {
+		results.Hits = append(results.Hits, Hit{ID: hit.ID, Score: hit.Score, Document: hit.Source})
+	}
+	return results, nil
+}
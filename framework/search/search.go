@@ -0,0 +1,196 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"laravel-go/framework/database"
+	"laravel-go/framework/event"
+)
+
+// 搜索相关错误
+var (
+	ErrNoDriver   = errors.New("search driver not configured")
+	ErrMissingKey = errors.New("searchable model has no search key")
+)
+
+// Searchable 可搜索的模型
+type Searchable interface {
+	// SearchableAs 索引名称
+	SearchableAs() string
+	// ToSearchArray 写入索引的文档内容
+	ToSearchArray() map[string]interface{}
+}
+
+// SearchKeyer 自定义文档标识，未实现时使用 ToSearchArray 中的 id
+type SearchKeyer interface {
+	SearchKey() string
+}
+
+// Options 搜索选项
+type Options struct {
+	Limit   int                    // 返回数量，默认 20
+	Offset  int                    // 跳过数量
+	Filters map[string]interface{} // 字段精确匹配条件
+}
+
+// Hit 搜索命中的文档
+type Hit struct {
+	ID       string                 `json:"id"`
+	Score    float64                `json:"score"`
+	Document map[string]interface{} `json:"document"`
+}
+
+// Results 搜索结果，Hits 按相关度从高到低排列
+type Results struct {
+	Hits  []Hit `json:"hits"`
+	Total int   `json:"total"`
+}
+
+// Driver 搜索驱动
+type Driver interface {
+	// Index 写入或替换文档
+	Index(index, id string, document map[string]interface{}) error
+	// Delete 删除文档，文档不存在时不返回错误
+	Delete(index, id string) error
+	// Search 搜索文档
+	Search(index, query string, options Options) (*Results, error)
+	// Flush 清空索引
+	Flush(index string) error
+}
+
+// Engine 搜索引擎，将模型转换为文档后交给驱动
+type Engine struct {
+	driver Driver
+}
+
+// NewEngine 创建搜索引擎
+func NewEngine(driver Driver) *Engine {
+	return &Engine{driver: driver}
+}
+
+// Index 索引模型
+func (e *Engine) Index(model Searchable) error {
+	document := model.ToSearchArray()
+	id, err := searchKey(model, document)
+	if err != nil {
+		return err
+	}
+	return e.driver.Index(model.SearchableAs(), id, document)
+}
+
+// Delete 从索引中删除模型
+func (e *Engine) Delete(model Searchable) error {
+	id, err := searchKey(model, model.ToSearchArray())
+	if err != nil {
+		return err
+	}
+	return e.driver.Delete(model.SearchableAs(), id)
+}
+
+// Search 搜索索引
+func (e *Engine) Search(index, query string, options Options) (*Results, error) {
+	if options.Limit <= 0 {
+		options.Limit = 20
+	}
+	if options.Offset < 0 {
+		options.Offset = 0
+	}
+	return e.driver.Search(index, query, options)
+}
+
+// Flush 清空索引
+func (e *Engine) Flush(index string) error {
+	return e.driver.Flush(index)
+}
+
+// Observe 监听模型事件：创建和更新后索引模型，删除（包括软删除）后从索引中移除
+//
+// dispatcher 应与 database.SetEventDispatcher 使用的分发器一致，传入 nil 时使用 event 包的全局分发器。
+// 模型事件按模型类型区分，需要为每种可搜索模型调用一次。
+func (e *Engine) Observe(dispatcher event.Dispatcher, models ...Searchable) {
+	listen := event.Listen
+	if dispatcher != nil {
+		listen = dispatcher.Listen
+	}
+
+	indexer := event.NewListener("search.index", func(ev event.Event) error {
+		if model, ok := ev.GetPayload().(Searchable); ok {
+			return e.Index(model)
+		}
+		return nil
+	})
+	remover := event.NewListener("search.delete", func(ev event.Event) error {
+		if model, ok := ev.GetPayload().(Searchable); ok {
+			return e.Delete(model)
+		}
+		return nil
+	})
+
+	for _, model := range models {
+		listen(database.ModelEventName(model, database.ModelCreated), indexer)
+		listen(database.ModelEventName(model, database.ModelUpdated), indexer)
+		listen(database.ModelEventName(model, database.ModelDeleted), remover)
+	}
+}
+
+// searchKey 获取文档标识
+func searchKey(model Searchable, document map[string]interface{}) (string, error) {
+	if k, ok := model.(SearchKeyer); ok {
+		if key := k.SearchKey(); key != "" {
+			return key, nil
+		}
+		return "", ErrMissingKey
+	}
+	if id, ok := document["id"]; ok && id != nil {
+		return fmt.Sprint(id), nil
+	}
+	return "", ErrMissingKey
+}
+
+var (
+	defaultEngine *Engine
+	defaultMutex  sync.RWMutex
+)
+
+// SetDefault 设置全局搜索引擎
+func SetDefault(engine *Engine) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultEngine = engine
+}
+
+// Default 获取全局搜索引擎，未设置时返回 nil
+func Default() *Engine {
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultEngine
+}
+
+// Index 使用全局搜索引擎索引模型
+func Index(model Searchable) error {
+	engine := Default()
+	if engine == nil {
+		return ErrNoDriver
+	}
+	return engine.Index(model)
+}
+
+// Delete 使用全局搜索引擎删除模型
+func Delete(model Searchable) error {
+	engine := Default()
+	if engine == nil {
+		return ErrNoDriver
+	}
+	return engine.Delete(model)
+}
+
+// Search 使用全局搜索引擎搜索
+func Search(index, query string, options Options) (*Results, error) {
+	engine := Default()
+	if engine == nil {
+		return nil, ErrNoDriver
+	}
+	return engine.Search(index, query, options)
+}
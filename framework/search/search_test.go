@@ -0,0 +1,154 @@
+package search
+
+import (
+	"path/filepath"
+	"testing"
+
+	"laravel-go/framework/database"
+	"laravel-go/framework/event"
+)
+
+type Article struct {
+	database.Model
+	Title string `db:"title"`
+	Body  string `db:"body"`
+}
+
+func (a *Article) TableName() string {
+	return "articles"
+}
+
+func (a *Article) SearchableAs() string {
+	return "articles"
+}
+
+func (a *Article) ToSearchArray() map[string]interface{} {
+	return map[string]interface{}{"id": a.ID, "title": a.Title, "body": a.Body}
+}
+
+func newTestDriver(t *testing.T) (database.Connection, *DatabaseDriver) {
+	conn, err := database.NewConnection(&database.ConnectionConfig{
+		Driver:   database.SQLite,
+		Database: filepath.Join(t.TempDir(), "search.db"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	driver := NewDatabaseDriver(conn, "")
+	if err := driver.CreateTable(); err != nil {
+		t.Fatalf("Failed to create search table: %v", err)
+	}
+	return conn, driver
+}
+
+func TestDatabaseDriverSearch(t *testing.T) {
+	_, driver := newTestDriver(t)
+	engine := NewEngine(driver)
+
+	articles := []*Article{
+		{Model: database.Model{ID: 1}, Title: "Go generics", Body: "Type parameters in Go"},
+		{Model: database.Model{ID: 2}, Title: "Go concurrency", Body: "Channels and goroutines in Go, go go"},
+		{Model: database.Model{ID: 3}, Title: "Rust ownership", Body: "Borrowing rules"},
+		{Model: database.Model{ID: 4}, Title: "100% coverage", Body: "Testing"},
+	}
+	for _, article := range articles {
+		if err := engine.Index(article); err != nil {
+			t.Fatalf("Failed to index article: %v", err)
+		}
+	}
+
+	results, err := engine.Search("articles", "Go", Options{})
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+	if results.Total != 2 || results.Hits[0].ID != "2" || results.Hits[1].ID != "1" {
+		t.Errorf("Expected articles 2 and 1 ranked by relevance, got %+v", results)
+	}
+	if results.Hits[0].Score <= results.Hits[1].Score || results.Hits[0].Document["title"] != "Go concurrency" {
+		t.Errorf("Unexpected hit: %+v", results.Hits[0])
+	}
+
+	results, _ = engine.Search("articles", "go", Options{Limit: 1, Offset: 1})
+	if results.Total != 2 || len(results.Hits) != 1 || results.Hits[0].ID != "1" {
+		t.Errorf("Expected second page with article 1, got %+v", results)
+	}
+
+	results, _ = engine.Search("articles", "go", Options{Filters: map[string]interface{}{"title": "Go generics"}})
+	if results.Total != 1 || results.Hits[0].ID != "1" {
+		t.Errorf("Expected filter to keep article 1, got %+v", results)
+	}
+
+	// LIKE 通配符按字面匹配
+	results, _ = engine.Search("articles", "%", Options{})
+	if results.Total != 1 || results.Hits[0].ID != "4" {
+		t.Errorf("Expected only article 4 to match %%, got %+v", results)
+	}
+
+	// 重新索引替换文档
+	articles[2].Title = "Rust and Go"
+	if err := engine.Index(articles[2]); err != nil {
+		t.Fatalf("Failed to reindex article: %v", err)
+	}
+	if err := engine.Delete(articles[0]); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	results, _ = engine.Search("articles", "go", Options{})
+	if results.Total != 2 || results.Hits[1].ID != "3" {
+		t.Errorf("Expected articles 2 and 3 after reindexing, got %+v", results)
+	}
+}
+
+func TestObserveIndexesSavedModels(t *testing.T) {
+	conn, driver := newTestDriver(t)
+	if _, err := conn.Exec(`CREATE TABLE articles (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title TEXT,
+		body TEXT,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	dispatcher := event.NewEventDispatcher(event.NewMemoryEventQueue())
+	defer dispatcher.Close()
+	database.SetEventDispatcher(dispatcher)
+	defer database.SetEventDispatcher(nil)
+
+	engine := NewEngine(driver)
+	engine.Observe(dispatcher, &Article{})
+	SetDefault(engine)
+	defer SetDefault(nil)
+
+	model := &database.Model{}
+	article := &Article{Title: "Observed article", Body: "Indexed on save"}
+	if err := model.Save(conn, article); err != nil {
+		t.Fatalf("Failed to save article: %v", err)
+	}
+
+	results, err := Search("articles", "observed", Options{})
+	if err != nil {
+		t.Fatalf("Failed to search: %v", err)
+	}
+	if results.Total != 1 || results.Hits[0].ID != "1" {
+		t.Fatalf("Expected saved article to be indexed, got %+v", results)
+	}
+
+	article.Title = "Renamed article"
+	if err := model.Save(conn, article); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	if results, _ := Search("articles", "renamed", Options{}); results.Total != 1 {
+		t.Errorf("Expected updated article to be reindexed, got %+v", results)
+	}
+
+	if err := model.Delete(conn, article); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	if results, _ := Search("articles", "renamed", Options{}); results.Total != 0 {
+		t.Errorf("Expected deleted article to be removed from the index, got %+v", results)
+	}
+}
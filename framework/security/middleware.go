@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -431,14 +432,20 @@ func (rlm *RateLimitMiddleware) Process(w http.ResponseWriter, r *http.Request,
 
 // MemoryRateLimitStore 内存速率限制存储
 type MemoryRateLimitStore struct {
-	store map[string]*rateLimitEntry
+	store      map[string]*rateLimitEntry
+	increments int
+	mu         sync.Mutex
 }
 
 type rateLimitEntry struct {
 	count     int
 	lastReset time.Time
+	window    time.Duration
 }
 
+// memoryRateLimitSweepInterval 每隔多少次计数清理一次过期记录
+const memoryRateLimitSweepInterval = 1000
+
 // NewMemoryRateLimitStore 创建内存速率限制存储
 func NewMemoryRateLimitStore() *MemoryRateLimitStore {
 	return &MemoryRateLimitStore{
@@ -448,6 +455,8 @@ func NewMemoryRateLimitStore() *MemoryRateLimitStore {
 
 // Get 获取当前计数
 func (mrs *MemoryRateLimitStore) Get(key string) (int, error) {
+	mrs.mu.Lock()
+	defer mrs.mu.Unlock()
 	if entry, exists := mrs.store[key]; exists {
 		return entry.count, nil
 	}
@@ -456,13 +465,21 @@ func (mrs *MemoryRateLimitStore) Get(key string) (int, error) {
 
 // Increment 增加计数
 func (mrs *MemoryRateLimitStore) Increment(key string, window time.Duration) (int, error) {
+	mrs.mu.Lock()
+	defer mrs.mu.Unlock()
+
 	now := time.Now()
+	mrs.increments++
+	if mrs.increments%memoryRateLimitSweepInterval == 0 {
+		mrs.sweep(now)
+	}
 	entry, exists := mrs.store[key]
 
 	if !exists || now.Sub(entry.lastReset) >= window {
 		entry = &rateLimitEntry{
 			count:     1,
 			lastReset: now,
+			window:    window,
 		}
 		mrs.store[key] = entry
 		return 1, nil
@@ -474,6 +491,17 @@ func (mrs *MemoryRateLimitStore) Increment(key string, window time.Duration) (in
 
 // Reset 重置计数
 func (mrs *MemoryRateLimitStore) Reset(key string) error {
+	mrs.mu.Lock()
+	defer mrs.mu.Unlock()
 	delete(mrs.store, key)
 	return nil
 }
+
+// sweep 清理窗口已结束的记录
+func (mrs *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, entry := range mrs.store {
+		if now.Sub(entry.lastReset) >= entry.window {
+			delete(mrs.store, key)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"laravel-go/framework/auth"
+)
+
+// QuotaPeriod 配额周期，周期按日历对齐，例如每天在零点重置
+type QuotaPeriod string
+
+// 配额周期
+const (
+	QuotaHourly  QuotaPeriod = "hourly"
+	QuotaDaily   QuotaPeriod = "daily"
+	QuotaMonthly QuotaPeriod = "monthly"
+)
+
+// QuotaTier 配额等级
+type QuotaTier struct {
+	Name string
+	// Limit 每个周期允许的请求数，小于等于 0 表示不限制
+	Limit  int
+	Period QuotaPeriod
+}
+
+// QuotaTierUser 可以声明自身配额等级的认证用户
+type QuotaTierUser interface {
+	QuotaTier() string
+}
+
+// ErrInvalidAPIKey 未注册的 API Key
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// QuotaIdentityFunc 从请求中识别配额主体，返回主体标识和配额等级
+//
+// 返回空标识表示请求没有可识别的主体，不做配额限制；返回错误时拒绝请求。
+type QuotaIdentityFunc func(r *http.Request) (identity string, tier string, err error)
+
+// QuotaMiddleware 按认证用户或 API Key 限制请求配额的中间件
+//
+// 主体优先从认证守卫写入请求上下文的用户获取，其次从 X-API-Key 请求头获取。
+// 计数按日历周期划分，存储可以替换为分布式实现以支持多实例部署。
+type QuotaMiddleware struct {
+	tiers        map[string]QuotaTier
+	defaultTier  string
+	apiKeys      map[string]string
+	apiKeyHeader string
+	identityFunc QuotaIdentityFunc
+	store        RateLimitStore
+	location     *time.Location
+	now          func() time.Time
+}
+
+// NewQuotaMiddleware 创建配额中间件，defaultTier 为未指定等级的主体使用的等级
+func NewQuotaMiddleware(defaultTier string, tiers ...QuotaTier) *QuotaMiddleware {
+	qm := &QuotaMiddleware{
+		tiers:        make(map[string]QuotaTier),
+		defaultTier:  defaultTier,
+		apiKeys:      make(map[string]string),
+		apiKeyHeader: "X-API-Key",
+		store:        NewMemoryRateLimitStore(),
+		location:     time.UTC,
+		now:          time.Now,
+	}
+	for _, tier := range tiers {
+		qm.AddTier(tier)
+	}
+	qm.identityFunc = qm.defaultIdentity
+	return qm
+}
+
+// AddTier 添加配额等级，未指定周期时按天计算
+func (qm *QuotaMiddleware) AddTier(tier QuotaTier) *QuotaMiddleware {
+	if tier.Period == "" {
+		tier.Period = QuotaDaily
+	}
+	qm.tiers[tier.Name] = tier
+	return qm
+}
+
+// AddAPIKey 注册 API Key 及其配额等级
+func (qm *QuotaMiddleware) AddAPIKey(key, tier string) *QuotaMiddleware {
+	qm.apiKeys[key] = tier
+	return qm
+}
+
+// SetAPIKeyHeader 设置 API Key 请求头名称
+func (qm *QuotaMiddleware) SetAPIKeyHeader(header string) *QuotaMiddleware {
+	qm.apiKeyHeader = header
+	return qm
+}
+
+// SetIdentityFunc 设置配额主体识别函数
+func (qm *QuotaMiddleware) SetIdentityFunc(identityFunc QuotaIdentityFunc) *QuotaMiddleware {
+	qm.identityFunc = identityFunc
+	return qm
+}
+
+// SetStore 设置计数存储，多实例部署时使用共享存储，例如 RedisRateLimitStore
+func (qm *QuotaMiddleware) SetStore(store RateLimitStore) *QuotaMiddleware {
+	qm.store = store
+	return qm
+}
+
+// SetLocation 设置周期对齐使用的时区，默认 UTC
+func (qm *QuotaMiddleware) SetLocation(location *time.Location) *QuotaMiddleware {
+	qm.location = location
+	return qm
+}
+
+// GetName 获取中间件名称
+func (qm *QuotaMiddleware) GetName() string {
+	return "quota"
+}
+
+// Process 处理请求
+func (qm *QuotaMiddleware) Process(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	identity, tierName, err := qm.identityFunc(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if identity == "" {
+		next(w, r)
+		return
+	}
+	if tierName == "" {
+		tierName = qm.defaultTier
+	}
+
+	tier, exists := qm.tiers[tierName]
+	if !exists {
+		http.Error(w, "Invalid quota tier", http.StatusForbidden)
+		return
+	}
+	if tier.Limit <= 0 {
+		next(w, r)
+		return
+	}
+
+	now := qm.now().In(qm.location)
+	start, reset := quotaPeriodBounds(tier.Period, now)
+	key := fmt.Sprintf("quota:%s:%s:%s", identity, tier.Period, start.Format("2006010215"))
+
+	count, err := qm.store.Increment(key, reset.Sub(now))
+	if err != nil {
+		http.Error(w, "Quota error", http.StatusInternalServerError)
+		return
+	}
+
+	remaining := tier.Limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	w.Header().Set("X-Quota-Tier", tier.Name)
+	w.Header().Set("X-Quota-Limit", strconv.Itoa(tier.Limit))
+	w.Header().Set("X-Quota-Remaining", strconv.Itoa(remaining))
+	w.Header().Set("X-Quota-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+	if count > tier.Limit {
+		retryAfter := int(reset.Sub(now).Seconds())
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		http.Error(w, "Quota exceeded", http.StatusTooManyRequests)
+		return
+	}
+
+	next(w, r)
+}
+
+// defaultIdentity 从认证用户或 API Key 识别配额主体
+func (qm *QuotaMiddleware) defaultIdentity(r *http.Request) (string, string, error) {
+	if user := auth.UserFromContext(r.Context()); user != nil {
+		tier := ""
+		if tiered, ok := user.(QuotaTierUser); ok {
+			tier = tiered.QuotaTier()
+		}
+		return fmt.Sprintf("user:%v", user.GetAuthIdentifier()), tier, nil
+	}
+
+	if key := r.Header.Get(qm.apiKeyHeader); key != "" {
+		tier, exists := qm.apiKeys[key]
+		if !exists {
+			return "", "", ErrInvalidAPIKey
+		}
+		// 存储中只保存 API Key 的摘要
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:8]), tier, nil
+	}
+
+	return "", "", nil
+}
+
+// quotaPeriodBounds 返回 now 所在日历周期的开始时间和重置时间
+func quotaPeriodBounds(period QuotaPeriod, now time.Time) (time.Time, time.Time) {
+	year, month, day := now.Date()
+	location := now.Location()
+
+	switch period {
+	case QuotaHourly:
+		start := time.Date(year, month, day, now.Hour(), 0, 0, 0, location)
+		return start, start.Add(time.Hour)
+	case QuotaMonthly:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, location)
+		return start, start.AddDate(0, 1, 0)
+	default:
+		start := time.Date(year, month, day, 0, 0, 0, 0, location)
+		return start, start.AddDate(0, 0, 1)
+	}
+}
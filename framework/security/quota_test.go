@@ -0,0 +1,136 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"laravel-go/framework/auth"
+)
+
+// proUser 声明 pro 配额等级的用户
+type proUser struct {
+	*auth.BaseUser
+}
+
+func (u *proUser) QuotaTier() string {
+	return "pro"
+}
+
+func TestQuotaMiddlewareTiers(t *testing.T) {
+	quota := NewQuotaMiddleware("free",
+		QuotaTier{Name: "free", Limit: 2},
+		QuotaTier{Name: "pro", Limit: 5},
+	).AddAPIKey("free-key", "free").AddAPIKey("pro-key", "pro")
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		quota.Process(w, r, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+	}
+	call := func(configure func(r *http.Request) *http.Request) *httptest.ResponseRecorder {
+		request := configure(httptest.NewRequest("GET", "/api/items", nil))
+		recorder := httptest.NewRecorder()
+		handler(recorder, request)
+		return recorder
+	}
+	withKey := func(key string) func(r *http.Request) *http.Request {
+		return func(r *http.Request) *http.Request {
+			r.Header.Set("X-API-Key", key)
+			return r
+		}
+	}
+
+	// free 等级每天 2 次
+	for i := 0; i < 2; i++ {
+		if recorder := call(withKey("free-key")); recorder.Code != http.StatusOK {
+			t.Fatalf("Expected free request %d to pass, got %d", i+1, recorder.Code)
+		}
+	}
+	recorder := call(withKey("free-key"))
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 after free quota, got %d", recorder.Code)
+	}
+	if recorder.Header().Get("X-Quota-Limit") != "2" || recorder.Header().Get("X-Quota-Remaining") != "0" || recorder.Header().Get("Retry-After") == "" {
+		t.Errorf("Unexpected quota headers: %v", recorder.Header())
+	}
+
+	// pro 等级的 API Key 独立计数
+	for i := 0; i < 5; i++ {
+		if recorder := call(withKey("pro-key")); recorder.Code != http.StatusOK {
+			t.Fatalf("Expected pro request %d to pass, got %d", i+1, recorder.Code)
+		}
+	}
+	if recorder := call(withKey("pro-key")); recorder.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429 after pro quota, got %d", recorder.Code)
+	}
+
+	// 认证用户按用户计数并使用用户声明的等级
+	withUser := func(r *http.Request) *http.Request {
+		user := &proUser{BaseUser: &auth.BaseUser{ID: "42"}}
+		return r.WithContext(auth.WithUser(r.Context(), "api", user))
+	}
+	recorder = call(withUser)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("X-Quota-Tier") != "pro" || recorder.Header().Get("X-Quota-Remaining") != "4" {
+		t.Errorf("Expected authenticated user on pro tier, got %d %v", recorder.Code, recorder.Header())
+	}
+
+	if recorder := call(withKey("unknown")); recorder.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for unknown API key, got %d", recorder.Code)
+	}
+	if recorder := call(func(r *http.Request) *http.Request { return r }); recorder.Code != http.StatusOK || recorder.Header().Get("X-Quota-Limit") != "" {
+		t.Errorf("Expected anonymous request to bypass quota, got %d", recorder.Code)
+	}
+}
+
+func TestQuotaMiddlewareDailyReset(t *testing.T) {
+	now := time.Date(2024, 3, 10, 23, 59, 0, 0, time.UTC)
+	quota := NewQuotaMiddleware("free", QuotaTier{Name: "free", Limit: 1}).AddAPIKey("key", "free")
+	quota.now = func() time.Time { return now }
+
+	call := func() *httptest.ResponseRecorder {
+		request := httptest.NewRequest("GET", "/api/items", nil)
+		request.Header.Set("X-API-Key", "key")
+		recorder := httptest.NewRecorder()
+		quota.Process(recorder, request, func(w http.ResponseWriter, r *http.Request) {})
+		return recorder
+	}
+
+	first := call()
+	if first.Code != http.StatusOK {
+		t.Fatalf("Expected first request to pass, got %d", first.Code)
+	}
+	// 重置时间对齐到次日零点
+	midnight := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	if first.Header().Get("X-Quota-Reset") != strconv.FormatInt(midnight.Unix(), 10) {
+		t.Errorf("Expected reset at midnight, got %s", first.Header().Get("X-Quota-Reset"))
+	}
+	if recorder := call(); recorder.Code != http.StatusTooManyRequests || recorder.Header().Get("Retry-After") != "60" {
+		t.Errorf("Expected 429 retrying after 60s, got %d %s", recorder.Code, recorder.Header().Get("Retry-After"))
+	}
+
+	// 跨过零点后配额重置
+	now = midnight.Add(time.Second)
+	if recorder := call(); recorder.Code != http.StatusOK {
+		t.Errorf("Expected quota to reset after midnight, got %d", recorder.Code)
+	}
+}
+
+func TestQuotaPeriodBounds(t *testing.T) {
+	location := time.FixedZone("UTC+8", 8*3600)
+	now := time.Date(2024, 1, 31, 15, 30, 0, 0, location)
+
+	cases := map[QuotaPeriod][2]time.Time{
+		QuotaHourly:  {time.Date(2024, 1, 31, 15, 0, 0, 0, location), time.Date(2024, 1, 31, 16, 0, 0, 0, location)},
+		QuotaDaily:   {time.Date(2024, 1, 31, 0, 0, 0, 0, location), time.Date(2024, 2, 1, 0, 0, 0, 0, location)},
+		QuotaMonthly: {time.Date(2024, 1, 1, 0, 0, 0, 0, location), time.Date(2024, 2, 1, 0, 0, 0, 0, location)},
+	}
+	for period, expected := range cases {
+		start, reset := quotaPeriodBounds(period, now)
+		if !start.Equal(expected[0]) || !reset.Equal(expected[1]) {
+			t.Errorf("%s: expected %v - %v, got %v - %v", period, expected[0], expected[1], start, reset)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package security
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// 计数和设置过期时间需要在同一个脚本中原子执行，键没有过期时间时重新设置，避免计数永不重置
+var redisRateLimitIncrementScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 or redis.call("PTTL", KEYS[1]) == -1 then
+	redis.call("PEXPIREAT", KEYS[1], ARGV[1])
+end
+return count`)
+
+// RedisRateLimitStore 基于 Redis 的速率限制存储，多个实例共享计数
+//
+// 每个键对应一个 INCR 计数器，第一次计数时把过期时间设置为当前时间加窗口长度。
+// QuotaMiddleware 的键按日历周期划分，窗口长度为距离周期结束的时长，计数器在周期结束时过期。
+type RedisRateLimitStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisRateLimitStore 创建 Redis 速率限制存储，prefix 会添加到所有计数键前
+func NewRedisRateLimitStore(client redis.UniversalClient, prefix string) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+// Get 获取当前计数
+func (s *RedisRateLimitStore) Get(key string) (int, error) {
+	count, err := s.client.Get(context.Background(), s.prefix+key).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+// Increment 增加计数并返回增加后的值
+func (s *RedisRateLimitStore) Increment(key string, window time.Duration) (int, error) {
+	expiresAt := time.Now().Add(window).UnixMilli()
+	return redisRateLimitIncrementScript.Run(context.Background(), s.client, []string{s.prefix + key}, expiresAt).Int()
+}
+
+// Reset 重置计数
+func (s *RedisRateLimitStore) Reset(key string) error {
+	return s.client.Del(context.Background(), s.prefix+key).Err()
+}
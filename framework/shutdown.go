@@ -0,0 +1,176 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ShutdownHook 关闭钩子，应在 ctx 结束前完成清理
+type ShutdownHook func(ctx context.Context) error
+
+// namedHook 带名称的关闭钩子
+type namedHook struct {
+	name string
+	hook ShutdownHook
+}
+
+// ShutdownManager 优雅关闭管理器
+//
+// 监控器、调度器、队列和 HTTP 服务器等子系统注册关闭钩子后，由管理器统一
+// 按注册的相反顺序执行。先启动的子系统后关闭，例如先注册队列再注册 HTTP
+// 服务器，关闭时 HTTP 服务器先停止接收请求，队列随后停止。
+type ShutdownManager struct {
+	mu       sync.Mutex
+	hooks    []namedHook
+	timeout  time.Duration
+	trigger  chan struct{}
+	once     sync.Once
+	shutdown sync.Once
+	report   ShutdownReport
+}
+
+// ShutdownResult 单个钩子的执行结果
+type ShutdownResult struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Error    error         `json:"error,omitempty"`
+	TimedOut bool          `json:"timed_out"`
+}
+
+// ShutdownReport 关闭报告
+type ShutdownReport struct {
+	Results []ShutdownResult `json:"results"`
+}
+
+// TimedOut 获取超时的钩子名称
+func (r ShutdownReport) TimedOut() []string {
+	var names []string
+	for _, result := range r.Results {
+		if result.TimedOut {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
+
+// Err 汇总失败或超时的钩子，全部成功时返回 nil
+func (r ShutdownReport) Err() error {
+	var messages []string
+	for _, result := range r.Results {
+		switch {
+		case result.TimedOut:
+			messages = append(messages, fmt.Sprintf("%s: timed out", result.Name))
+		case result.Error != nil:
+			messages = append(messages, fmt.Sprintf("%s: %v", result.Name, result.Error))
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("shutdown failed: %s", strings.Join(messages, "; "))
+}
+
+// NewShutdownManager 创建优雅关闭管理器，timeout 为所有钩子共享的截止时间
+func NewShutdownManager(timeout time.Duration) *ShutdownManager {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	return &ShutdownManager{
+		timeout: timeout,
+		trigger: make(chan struct{}),
+	}
+}
+
+// Register 注册关闭钩子
+func (m *ShutdownManager) Register(name string, hook ShutdownHook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, namedHook{name: name, hook: hook})
+}
+
+// RegisterStopper 注册带 Stop 方法的子系统，例如队列和调度器
+func (m *ShutdownManager) RegisterStopper(name string, stopper interface{ Stop() error }) {
+	m.Register(name, func(ctx context.Context) error {
+		return stopper.Stop()
+	})
+}
+
+// Trigger 主动触发关闭，Wait 会随之返回
+func (m *ShutdownManager) Trigger() {
+	m.once.Do(func() {
+		close(m.trigger)
+	})
+}
+
+// Wait 等待 SIGINT/SIGTERM 或 Trigger，然后执行所有关闭钩子
+func (m *ShutdownManager) Wait() ShutdownReport {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case <-signals:
+	case <-m.trigger:
+	}
+
+	return m.Shutdown()
+}
+
+// Shutdown 按注册的相反顺序执行关闭钩子，多次调用只执行一次
+func (m *ShutdownManager) Shutdown() ShutdownReport {
+	m.shutdown.Do(func() {
+		m.report = m.runHooks()
+	})
+	return m.report
+}
+
+// runHooks 在共享截止时间内依次执行钩子
+func (m *ShutdownManager) runHooks() ShutdownReport {
+	m.mu.Lock()
+	hooks := make([]namedHook, len(m.hooks))
+	copy(hooks, m.hooks)
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	report := ShutdownReport{Results: make([]ShutdownResult, 0, len(hooks))}
+	for i := len(hooks) - 1; i >= 0; i-- {
+		report.Results = append(report.Results, runHook(ctx, hooks[i]))
+	}
+	return report
+}
+
+// runHook 执行单个钩子，截止时间到达后不再等待
+func runHook(ctx context.Context, h namedHook) ShutdownResult {
+	start := time.Now()
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- h.hook(ctx)
+	}()
+
+	result := ShutdownResult{Name: h.name}
+	select {
+	case err := <-done:
+		result.Error = err
+	case <-ctx.Done():
+		result.TimedOut = true
+	}
+	result.Duration = time.Since(start)
+
+	return result
+}
@@ -0,0 +1,85 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdownManagerLIFO(t *testing.T) {
+	manager := NewShutdownManager(time.Second)
+
+	var mu sync.Mutex
+	var order []string
+	for _, name := range []string{"monitor", "queue", "http"} {
+		name := name
+		manager.Register(name, func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	go manager.Trigger()
+	report := manager.Wait()
+
+	expected := []string{"http", "queue", "monitor"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %d hooks to run, got %v", len(expected), order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Expected hooks in order %v, got %v", expected, order)
+			break
+		}
+	}
+	if err := report.Err(); err != nil {
+		t.Errorf("Expected clean shutdown, got %v", err)
+	}
+
+	// 重复调用不会再次执行钩子
+	manager.Shutdown()
+	if len(order) != 3 {
+		t.Errorf("Expected hooks to run once, got %v", order)
+	}
+}
+
+func TestShutdownManagerDeadline(t *testing.T) {
+	manager := NewShutdownManager(100 * time.Millisecond)
+
+	// 按相反顺序执行：fast、failing、slow
+	manager.Register("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	manager.Register("failing", func(ctx context.Context) error {
+		return errors.New("close failed")
+	})
+	manager.Register("fast", func(ctx context.Context) error {
+		return nil
+	})
+
+	start := time.Now()
+	report := manager.Shutdown()
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected deadline to be enforced, took %v", elapsed)
+	}
+
+	timedOut := report.TimedOut()
+	if len(timedOut) != 1 || timedOut[0] != "slow" {
+		t.Errorf("Expected slow hook to time out, got %v", timedOut)
+	}
+
+	if len(report.Results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(report.Results))
+	}
+	if report.Results[1].Name != "failing" || report.Results[1].Error == nil {
+		t.Errorf("Expected failing hook to report an error, got %+v", report.Results[1])
+	}
+	if report.Err() == nil {
+		t.Error("Expected report error")
+	}
+}
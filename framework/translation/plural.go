@@ -0,0 +1,78 @@
+package translation
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// intervalPattern 匹配复数形式开头的 {n} 或 [min,max] 区间
+var intervalPattern = regexp.MustCompile(`^\s*(\{\s*(-?\d+)\s*\}|\[\s*(-?\d+|\*)\s*,\s*(-?\d+|\*)\s*\])\s*`)
+
+// selectPlural 按数量选择复数形式
+func selectPlural(message string, count int, locale string) string {
+	segments := strings.Split(message, "|")
+
+	// 先匹配显式指定的数量和区间
+	stripped := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		match := intervalPattern.FindStringSubmatch(segment)
+		if match == nil {
+			stripped = append(stripped, strings.TrimSpace(segment))
+			continue
+		}
+		if inInterval(match, count) {
+			return strings.TrimSpace(segment[len(match[0]):])
+		}
+		stripped = append(stripped, strings.TrimSpace(segment[len(match[0]):]))
+	}
+
+	index := pluralIndex(count, locale)
+	if index >= len(stripped) {
+		index = len(stripped) - 1
+	}
+	return stripped[index]
+}
+
+// inInterval 检查数量是否在 {n} 或 [min,max] 中，* 表示不限
+func inInterval(match []string, count int) bool {
+	if match[2] != "" {
+		n, _ := strconv.Atoi(match[2])
+		return count == n
+	}
+	if match[3] != "*" {
+		if min, _ := strconv.Atoi(match[3]); count < min {
+			return false
+		}
+	}
+	if match[4] != "*" {
+		if max, _ := strconv.Atoi(match[4]); count > max {
+			return false
+		}
+	}
+	return true
+}
+
+// pluralIndex 按语言的复数规则返回复数形式的序号
+//
+// 中文、日文、韩文等没有复数变化的语言始终使用第一种形式，其他语言单数使用第一种，复数使用第二种。
+func pluralIndex(count int, locale string) int {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "-_"); i > 0 {
+		language = language[:i]
+	}
+
+	switch language {
+	case "zh", "ja", "ko", "th", "vi", "id", "ms", "tr":
+		return 0
+	case "fr", "pt":
+		if count == 0 || count == 1 {
+			return 0
+		}
+		return 1
+	}
+	if count == 1 {
+		return 0
+	}
+	return 1
+}
@@ -0,0 +1,248 @@
+package translation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Translator 翻译器
+//
+// 每种语言的消息以点号分隔的键保存，例如 validation.required。
+// 当前语言缺少某个键时使用回退语言，两者都缺少时返回键本身。
+type Translator struct {
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+	locale   string
+	fallback string
+}
+
+// NewTranslator 创建翻译器，locale 为默认语言，fallback 为回退语言
+func NewTranslator(locale, fallback string) *Translator {
+	return &Translator{
+		messages: make(map[string]map[string]string),
+		locale:   locale,
+		fallback: fallback,
+	}
+}
+
+// Locale 获取默认语言
+func (t *Translator) Locale() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.locale
+}
+
+// SetLocale 设置默认语言
+func (t *Translator) SetLocale(locale string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.locale = locale
+}
+
+// Fallback 获取回退语言
+func (t *Translator) Fallback() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.fallback
+}
+
+// AddMessages 添加语言消息，嵌套的对象展开为点号分隔的键，已有的键会被覆盖
+func (t *Translator) AddMessages(locale string, messages map[string]interface{}) {
+	flat := make(map[string]string)
+	flatten("", messages, flat)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.messages[locale] == nil {
+		t.messages[locale] = make(map[string]string)
+	}
+	for key, message := range flat {
+		t.messages[locale][key] = message
+	}
+}
+
+// LoadFile 从 JSON 文件加载语言消息
+func (t *Translator) LoadFile(locale, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var messages map[string]interface{}
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("failed to parse translation file %s: %w", path, err)
+	}
+	t.AddMessages(locale, messages)
+	return nil
+}
+
+// LoadDir 加载目录中的语言文件，文件名（不含扩展名）为语言，例如 lang/zh-CN.json
+func (t *Translator) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		locale := strings.TrimSuffix(filepath.Base(path), ".json")
+		if err := t.LoadFile(locale, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Has 检查键是否存在，包括回退语言，locale 为空时使用默认语言
+func (t *Translator) Has(key, locale string) bool {
+	_, ok := t.lookup(key, locale)
+	return ok
+}
+
+// Get 翻译键并替换 :name 形式的占位符，locale 为空时使用默认语言
+//
+// 占位符按替换值原样、首字母大写（:Name）和全大写（:NAME）三种形式替换。
+func (t *Translator) Get(key string, replacements map[string]interface{}, locale string) string {
+	message, ok := t.lookup(key, locale)
+	if !ok {
+		return key
+	}
+	return replace(message, replacements)
+}
+
+// Choice 按数量选择复数形式并翻译，:count 占位符替换为 count
+//
+// 消息用 | 分隔各个形式，可以用 {0}、{1} 指定精确数量，用 [2,*]、[2,10] 指定区间，
+// 例如 "{0} 没有苹果|{1} 一个苹果|[2,*] :count 个苹果"。没有匹配的区间时按语言的复数规则选择。
+func (t *Translator) Choice(key string, count int, replacements map[string]interface{}, locale string) string {
+	message, ok := t.lookup(key, locale)
+	if !ok {
+		return key
+	}
+	if locale == "" {
+		locale = t.Locale()
+	}
+
+	withCount := make(map[string]interface{}, len(replacements)+1)
+	for name, value := range replacements {
+		withCount[name] = value
+	}
+	if _, exists := withCount["count"]; !exists {
+		withCount["count"] = count
+	}
+	return replace(selectPlural(message, count, locale), withCount)
+}
+
+// lookup 查找键，当前语言缺少时使用回退语言
+func (t *Translator) lookup(key, locale string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if locale == "" {
+		locale = t.locale
+	}
+	if message, ok := t.messages[locale][key]; ok {
+		return message, true
+	}
+	// zh-CN 缺少时先尝试 zh
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		if message, ok := t.messages[locale[:i]][key]; ok {
+			return message, true
+		}
+	}
+	if message, ok := t.messages[t.fallback][key]; ok {
+		return message, true
+	}
+	return "", false
+}
+
+// flatten 将嵌套的消息展开为点号分隔的键
+func flatten(prefix string, messages map[string]interface{}, result map[string]string) {
+	for key, value := range messages {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(key, v, result)
+		case string:
+			result[key] = v
+		default:
+			result[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// replace 替换消息中的占位符，较长的占位符先替换，避免 :name 替换 :names 的前缀
+func replace(message string, replacements map[string]interface{}) string {
+	if len(replacements) == 0 || !strings.Contains(message, ":") {
+		return message
+	}
+
+	names := make([]string, 0, len(replacements))
+	for name := range replacements {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return len(names[i]) > len(names[j])
+	})
+
+	pairs := make([]string, 0, len(names)*6)
+	for _, name := range names {
+		value := fmt.Sprint(replacements[name])
+		pairs = append(pairs,
+			":"+name, value,
+			":"+capitalize(name), capitalize(value),
+			":"+strings.ToUpper(name), strings.ToUpper(value),
+		)
+	}
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+// capitalize 首字母大写
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = []rune(strings.ToUpper(string(runes[0])))[0]
+	return string(runes)
+}
+
+var (
+	defaultTranslator *Translator
+	defaultMutex      sync.Mutex
+)
+
+// SetDefault 设置全局翻译器
+func SetDefault(translator *Translator) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultTranslator = translator
+}
+
+// Default 获取全局翻译器，未设置时创建默认语言和回退语言都为 APP_LOCALE（默认 en）的翻译器
+func Default() *Translator {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	if defaultTranslator == nil {
+		locale := os.Getenv("APP_LOCALE")
+		if locale == "" {
+			locale = "en"
+		}
+		defaultTranslator = NewTranslator(locale, locale)
+	}
+	return defaultTranslator
+}
+
+// Trans 使用全局翻译器翻译
+func Trans(key string, replacements map[string]interface{}, locale string) string {
+	return Default().Get(key, replacements, locale)
+}
+
+// TransChoice 使用全局翻译器按数量翻译
+func TransChoice(key string, count int, replacements map[string]interface{}, locale string) string {
+	return Default().Choice(key, count, replacements, locale)
+}
@@ -0,0 +1,111 @@
+package translation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestTranslator(t *testing.T) *Translator {
+	dir := t.TempDir()
+	files := map[string]string{
+		"en.json": `{
+			"welcome": "Welcome, :name!",
+			"greeting": ":name and :names",
+			"apples": "{0} no apples|{1} one apple|[2,*] :count apples",
+			"messages": "one message|:count messages",
+			"nav": {"home": "Home", "about": "About"}
+		}`,
+		"zh-CN.json": `{
+			"welcome": "欢迎，:name！",
+			"apples": "{0} 没有苹果|{1} 一个苹果|[2,*] :count 个苹果",
+			"messages": ":count 条消息"
+		}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write translation file: %v", err)
+		}
+	}
+
+	translator := NewTranslator("en", "en")
+	if err := translator.LoadDir(dir); err != nil {
+		t.Fatalf("Failed to load translations: %v", err)
+	}
+	return translator
+}
+
+func TestPlaceholderReplacement(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	tests := []struct {
+		key          string
+		replacements map[string]interface{}
+		locale       string
+		expected     string
+	}{
+		{"welcome", map[string]interface{}{"name": "taylor"}, "", "Welcome, taylor!"},
+		{"welcome", map[string]interface{}{"name": "小明"}, "zh-CN", "欢迎，小明！"},
+		{"greeting", map[string]interface{}{"name": "a", "names": "b"}, "", "a and b"},
+		{"nav.home", nil, "", "Home"},
+	}
+	for _, tt := range tests {
+		if got := translator.Get(tt.key, tt.replacements, tt.locale); got != tt.expected {
+			t.Errorf("Get(%s, %s) = %q, expected %q", tt.key, tt.locale, got, tt.expected)
+		}
+	}
+
+	translator.AddMessages("en", map[string]interface{}{"shout": ":Name says :NAME"})
+	if got := translator.Get("shout", map[string]interface{}{"name": "bob"}, ""); got != "Bob says BOB" {
+		t.Errorf("Unexpected capitalized replacement: %q", got)
+	}
+}
+
+func TestPluralSelection(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	tests := []struct {
+		key      string
+		count    int
+		locale   string
+		expected string
+	}{
+		{"apples", 0, "", "no apples"},
+		{"apples", 1, "", "one apple"},
+		{"apples", 5, "", "5 apples"},
+		{"apples", 5, "zh-CN", "5 个苹果"},
+		{"messages", 1, "", "one message"},
+		{"messages", 0, "", "0 messages"},
+		{"messages", 5, "", "5 messages"},
+		{"messages", 1, "zh-CN", "1 条消息"},
+	}
+	for _, tt := range tests {
+		if got := translator.Choice(tt.key, tt.count, nil, tt.locale); got != tt.expected {
+			t.Errorf("Choice(%s, %d, %s) = %q, expected %q", tt.key, tt.count, tt.locale, got, tt.expected)
+		}
+	}
+}
+
+func TestFallbackLocale(t *testing.T) {
+	translator := newTestTranslator(t)
+
+	if got := translator.Get("nav.about", nil, "zh-CN"); got != "About" {
+		t.Errorf("Expected fallback translation, got %q", got)
+	}
+	if got := translator.Get("missing.key", nil, "zh-CN"); got != "missing.key" {
+		t.Errorf("Expected missing key to be returned as is, got %q", got)
+	}
+	if !translator.Has("nav.about", "zh-CN") || translator.Has("missing.key", "") {
+		t.Error("Unexpected Has result")
+	}
+
+	SetDefault(translator)
+	defer SetDefault(nil)
+	translator.SetLocale("zh-CN")
+	if got := Trans("welcome", map[string]interface{}{"name": "小红"}, ""); got != "欢迎，小红！" {
+		t.Errorf("Unexpected global translation: %q", got)
+	}
+	if got := TransChoice("apples", 0, nil, "en"); got != "no apples" {
+		t.Errorf("Unexpected global plural translation: %q", got)
+	}
+}
@@ -0,0 +1,173 @@
+package url
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"laravel-go/framework/crypto"
+)
+
+const (
+	// ExpiresParam 过期时间参数名，值为 Unix 秒数
+	ExpiresParam = "expires"
+	// SignatureParam 签名参数名
+	SignatureParam = "signature"
+)
+
+var (
+	// ErrInvalidSignature 签名缺失或不匹配
+	ErrInvalidSignature = errors.New("invalid url signature")
+	// ErrExpiredSignature 链接已过期
+	ErrExpiredSignature = errors.New("url signature has expired")
+	// ErrMissingKey 未配置签名密钥
+	ErrMissingKey = errors.New("url signing key is not set")
+)
+
+// Signer 签名 URL 生成器和校验器
+//
+// 签名内容为路径和除 signature 外按参数名排序的查询字符串，不包含协议和主机，
+// 因此经过反向代理改写主机名的请求也能通过校验。
+type Signer struct {
+	key []byte
+}
+
+// NewSigner 使用密钥创建签名器
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign 为 base 追加 params、过期时间和签名，ttl 小于等于 0 时链接永不过期
+func (s *Signer) Sign(base string, params map[string]string, ttl time.Duration) (string, error) {
+	if len(s.key) == 0 {
+		return "", ErrMissingKey
+	}
+	u, err := neturl.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid url %q: %w", base, err)
+	}
+
+	query := u.Query()
+	query.Del(SignatureParam)
+	query.Del(ExpiresParam)
+	for name, value := range params {
+		query.Set(name, value)
+	}
+	if ttl > 0 {
+		query.Set(ExpiresParam, strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+	}
+
+	query.Set(SignatureParam, hex.EncodeToString(s.mac(u.EscapedPath(), query)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Validate 校验 URL 的签名和过期时间
+func (s *Signer) Validate(u *neturl.URL) error {
+	if len(s.key) == 0 {
+		return ErrMissingKey
+	}
+	query := u.Query()
+	signature, err := hex.DecodeString(query.Get(SignatureParam))
+	if err != nil || len(signature) == 0 {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal(s.mac(u.EscapedPath(), query), signature) {
+		return ErrInvalidSignature
+	}
+
+	if expires := query.Get(ExpiresParam); expires != "" {
+		seconds, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		if time.Now().Unix() > seconds {
+			return ErrExpiredSignature
+		}
+	}
+	return nil
+}
+
+// ValidateRequest 校验请求 URL 的签名和过期时间
+func (s *Signer) ValidateRequest(r *http.Request) error {
+	return s.Validate(r.URL)
+}
+
+// Middleware 签名校验中间件，签名无效或已过期时返回 403
+func (s *Signer) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch err := s.ValidateRequest(r); err {
+			case nil:
+				next.ServeHTTP(w, r)
+			case ErrExpiredSignature:
+				http.Error(w, "Link has expired", http.StatusForbidden)
+			default:
+				http.Error(w, "Invalid signature", http.StatusForbidden)
+			}
+		})
+	}
+}
+
+// mac 计算路径和查询字符串的 HMAC，查询字符串由 Encode 按参数名排序
+func (s *Signer) mac(path string, query neturl.Values) []byte {
+	unsigned := make(neturl.Values, len(query))
+	for name, values := range query {
+		if name != SignatureParam {
+			unsigned[name] = values
+		}
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(path + "?" + unsigned.Encode()))
+	return mac.Sum(nil)
+}
+
+var (
+	defaultSigner *Signer
+	defaultMutex  sync.Mutex
+)
+
+// SetDefault 设置全局签名器
+func SetDefault(signer *Signer) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultSigner = signer
+}
+
+// Default 获取全局签名器，未设置时使用 APP_KEY 作为密钥
+func Default() *Signer {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	if defaultSigner == nil {
+		key, _ := crypto.ParseKey(os.Getenv("APP_KEY"))
+		defaultSigner = NewSigner(key)
+	}
+	return defaultSigner
+}
+
+// SignedURL 使用全局签名器生成签名 URL
+func SignedURL(base string, params map[string]string, ttl time.Duration) (string, error) {
+	return Default().Sign(base, params, ttl)
+}
+
+// ValidateSignedURL 使用全局签名器校验请求 URL 的签名和过期时间
+func ValidateSignedURL(r *http.Request) error {
+	return Default().ValidateRequest(r)
+}
+
+// ValidateSignature 使用全局签名器的签名校验中间件
+func ValidateSignature() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Default().Middleware()(next).ServeHTTP(w, r)
+		})
+	}
+}
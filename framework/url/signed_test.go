@@ -0,0 +1,104 @@
+package url
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignedURL(t *testing.T) {
+	t.Setenv("APP_KEY", "base64:"+base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	SetDefault(nil)
+	defer SetDefault(nil)
+
+	signed, err := SignedURL("https://example.com/downloads/report.pdf?format=pdf", map[string]string{"user": "42"}, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to sign url: %v", err)
+	}
+	if !strings.Contains(signed, "user=42") || !strings.Contains(signed, "format=pdf") || !strings.Contains(signed, "expires=") {
+		t.Errorf("Expected params and expiry in signed url, got %s", signed)
+	}
+
+	// 有效链接通过，主机名不参与签名
+	if err := ValidateSignedURL(httptest.NewRequest(http.MethodGet, signed, nil)); err != nil {
+		t.Errorf("Expected valid link to pass, got %v", err)
+	}
+	internal := strings.Replace(signed, "https://example.com", "http://10.0.0.5:8080", 1)
+	if err := ValidateSignedURL(httptest.NewRequest(http.MethodGet, internal, nil)); err != nil {
+		t.Errorf("Expected link behind proxy to pass, got %v", err)
+	}
+
+	// 篡改参数、路径或过期时间都会使签名失效
+	for _, tampered := range []string{
+		strings.Replace(signed, "user=42", "user=43", 1),
+		strings.Replace(signed, "report.pdf", "other.pdf", 1),
+		signed + "&admin=1",
+		replaceParam(t, signed, ExpiresParam, strconv.FormatInt(time.Now().Add(24*time.Hour).Unix(), 10)),
+		replaceParam(t, signed, SignatureParam, ""),
+	} {
+		if err := ValidateSignedURL(httptest.NewRequest(http.MethodGet, tampered, nil)); err != ErrInvalidSignature {
+			t.Errorf("Expected ErrInvalidSignature for %s, got %v", tampered, err)
+		}
+	}
+
+	// 其他密钥签名的链接无效
+	forged, _ := NewSigner([]byte("another-key")).Sign("https://example.com/downloads/report.pdf", map[string]string{"user": "42"}, time.Hour)
+	if err := ValidateSignedURL(httptest.NewRequest(http.MethodGet, forged, nil)); err != ErrInvalidSignature {
+		t.Errorf("Expected link signed with another key to fail, got %v", err)
+	}
+
+	// 永不过期的链接没有 expires 参数
+	permanent, _ := SignedURL("/verify-email", map[string]string{"id": "7"}, 0)
+	if strings.Contains(permanent, ExpiresParam) {
+		t.Errorf("Expected no expiry, got %s", permanent)
+	}
+	if err := ValidateSignedURL(httptest.NewRequest(http.MethodGet, permanent, nil)); err != nil {
+		t.Errorf("Expected permanent link to pass, got %v", err)
+	}
+}
+
+func TestExpiredSignedURL(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	// 写入已过去的过期时间，签名本身有效
+	past := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	expired, err := signer.Sign("https://example.com/verify", map[string]string{"id": "7", ExpiresParam: past}, 0)
+	if err != nil {
+		t.Fatalf("Failed to sign url: %v", err)
+	}
+
+	if err := signer.ValidateRequest(httptest.NewRequest(http.MethodGet, expired, nil)); err != ErrExpiredSignature {
+		t.Fatalf("Expected ErrExpiredSignature, got %v", err)
+	}
+
+	handler := signer.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, expired, nil))
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected expired link to be rejected, got %d", recorder.Code)
+	}
+
+	valid, _ := signer.Sign("https://example.com/verify", map[string]string{"id": "7"}, time.Minute)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, valid, nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Expected valid link to pass, got %d", recorder.Code)
+	}
+}
+
+func replaceParam(t *testing.T, raw, name, value string) string {
+	u, err := neturl.Parse(raw)
+	if err != nil {
+		t.Fatalf("Failed to parse url: %v", err)
+	}
+	query := u.Query()
+	query.Set(name, value)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
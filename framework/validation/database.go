@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+
+	"laravel-go/framework/database"
+)
+
+// identifierPattern 允许的表名和列名
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SetConnection 设置 unique 和 exists 规则使用的数据库连接
+func (v *Validator) SetConnection(conn database.Connection) {
+	v.connection = conn
+}
+
+// AllowTables 设置 unique 和 exists 规则允许查询的表
+//
+// 未设置时允许查询任意表，表名和列名始终只能包含字母、数字和下划线。
+func (v *Validator) AllowTables(tables ...string) {
+	if v.allowedTables == nil {
+		v.allowedTables = make(map[string]bool)
+	}
+	for _, table := range tables {
+		v.allowedTables[table] = true
+	}
+}
+
+// checkIdentifier 检查表名或列名，防止通过规则参数注入 SQL
+func (v *Validator) checkIdentifier(table string, columns ...string) error {
+	if !identifierPattern.MatchString(table) {
+		return fmt.Errorf("invalid table name: %s", table)
+	}
+	if v.allowedTables != nil && !v.allowedTables[table] {
+		return fmt.Errorf("table %s is not allowed", table)
+	}
+	for _, column := range columns {
+		if !identifierPattern.MatchString(column) {
+			return fmt.Errorf("invalid column name: %s", column)
+		}
+	}
+	return nil
+}
+
+// countRecords 统计表中列值等于 value 的记录数，ignore 不为空时排除 idColumn 等于 ignore 的记录
+func (v *Validator) countRecords(value interface{}, params []string, ignore string, idColumn string) (int64, error) {
+	if v.connection == nil {
+		return 0, fmt.Errorf("database connection not configured")
+	}
+	if len(params) < 2 {
+		return 0, fmt.Errorf("rule requires table and column parameters")
+	}
+
+	table, column := params[0], params[1]
+	if err := v.checkIdentifier(table, column, idColumn); err != nil {
+		return 0, err
+	}
+
+	query := database.NewQueryBuilder(v.connection).Table(table).WithTrashed().WhereEq(column, value)
+	if ignore != "" {
+		query.WhereNe(idColumn, ignore)
+	}
+	return query.Count()
+}
+
+// registerDatabaseRules 注册需要数据库连接的规则
+func (v *Validator) registerDatabaseRules() {
+	// unique:table,column[,ignoreID[,idColumn]]，更新时通过 ignoreID 排除当前记录
+	v.RegisterRule("unique", ParamRuleFunc(func(value interface{}, params []string) error {
+		if value == nil || value == "" {
+			return nil
+		}
+
+		ignore, idColumn := "", "id"
+		if len(params) > 2 {
+			ignore = params[2]
+		}
+		if len(params) > 3 && params[3] != "" {
+			idColumn = params[3]
+		}
+
+		count, err := v.countRecords(value, params, ignore, idColumn)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("field has already been taken")
+		}
+		return nil
+	}))
+
+	// exists:table,column
+	v.RegisterRule("exists", ParamRuleFunc(func(value interface{}, params []string) error {
+		if value == nil || value == "" {
+			return nil
+		}
+
+		count, err := v.countRecords(value, params, "", "id")
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("selected field is invalid")
+		}
+		return nil
+	}))
+}
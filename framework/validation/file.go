@@ -0,0 +1,122 @@
+package validation
+
+import (
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// extensionMimeTypes 扩展名对应的文件内容类型，用于校验文件内容与扩展名一致
+var extensionMimeTypes = map[string][]string{
+	"png":  {"image/png"},
+	"jpg":  {"image/jpeg"},
+	"jpeg": {"image/jpeg"},
+	"gif":  {"image/gif"},
+	"webp": {"image/webp"},
+	"bmp":  {"image/bmp"},
+	"pdf":  {"application/pdf"},
+	"zip":  {"application/zip"},
+	"docx": {"application/zip"},
+	"xlsx": {"application/zip"},
+	"pptx": {"application/zip"},
+	"mp3":  {"audio/mpeg"},
+	"mp4":  {"video/mp4"},
+	"txt":  {"text/plain"},
+	"csv":  {"text/plain"},
+	"json": {"text/plain"},
+}
+
+// DetectMimeType 根据文件内容检测类型
+func DetectMimeType(header *multipart.FileHeader) (string, error) {
+	file, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	buffer := make([]byte, 512)
+	n, err := file.Read(buffer)
+	if err != nil && n == 0 {
+		return "", err
+	}
+
+	mimeType := http.DetectContentType(buffer[:n])
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return mimeType, nil
+}
+
+// MatchesMimes 检查文件扩展名和内容是否属于允许的类型
+//
+// 扩展名必须在 extensions 中；已知扩展名还会检查文件内容，防止修改扩展名绕过校验。
+func MatchesMimes(header *multipart.FileHeader, extensions []string) bool {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), "."))
+	if ext == "" {
+		return false
+	}
+
+	allowed := false
+	for _, candidate := range extensions {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if candidate == ext || (candidate == "jpg" && ext == "jpeg") || (candidate == "jpeg" && ext == "jpg") {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return false
+	}
+
+	expected, known := extensionMimeTypes[ext]
+	if !known {
+		return true
+	}
+
+	detected, err := DetectMimeType(header)
+	if err != nil {
+		return false
+	}
+	for _, mimeType := range expected {
+		if detected == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// measure 获取 min/max 规则比较的大小：文件为 KB，字符串为字符数，集合为元素个数
+func measure(value interface{}, params []string) (float64, float64, bool) {
+	if value == nil || len(params) == 0 {
+		return 0, 0, false
+	}
+
+	limit, err := strconv.ParseFloat(params[0], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	switch v := value.(type) {
+	case *multipart.FileHeader:
+		return float64(v.Size) / 1024, limit, true
+	case string:
+		return float64(utf8.RuneCountInString(v)), limit, true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), limit, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), limit, true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), limit, true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(rv.Len()), limit, true
+	}
+	return 0, 0, false
+}
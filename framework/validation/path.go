@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fieldValue 展开后的字段
+type fieldValue struct {
+	path    string
+	value   interface{}
+	present bool
+}
+
+// expandField 按点号和通配符展开字段
+//
+// address.zip 取嵌套对象中的字段，products.*.price 对数组或对象中的每个元素取字段，
+// 展开后的路径使用实际的下标，例如 products.0.price。通配符对应的数组不存在或为空时
+// 不产生任何字段。
+func expandField(data map[string]interface{}, field string) []fieldValue {
+	if !strings.Contains(field, ".") {
+		value, present := data[field]
+		return []fieldValue{{path: field, value: value, present: present}}
+	}
+
+	var result []fieldValue
+	walkField(data, true, "", strings.Split(field, "."), &result)
+	return result
+}
+
+// walkField 递归展开路径的剩余部分
+func walkField(value interface{}, present bool, prefix string, segments []string, result *[]fieldValue) {
+	if len(segments) == 0 {
+		*result = append(*result, fieldValue{path: prefix, value: value, present: present})
+		return
+	}
+
+	segment := segments[0]
+	if segment == "*" {
+		for _, child := range children(value) {
+			walkField(child.value, true, joinPath(prefix, child.path), segments[1:], result)
+		}
+		return
+	}
+
+	child, ok := lookup(value, segment)
+	walkField(child, ok, joinPath(prefix, segment), segments[1:], result)
+}
+
+// children 列出数组或对象的所有元素，对象按键排序
+func children(value interface{}) []fieldValue {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]fieldValue, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			items[i] = fieldValue{path: strconv.Itoa(i), value: rv.Index(i).Interface()}
+		}
+		return items
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, key := range rv.MapKeys() {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		items := make([]fieldValue, len(keys))
+		for i, key := range keys {
+			items[i] = fieldValue{path: key, value: rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key())).Interface()}
+		}
+		return items
+	}
+	return nil
+}
+
+// lookup 在对象中按键或在数组中按下标取值
+func lookup(value interface{}, segment string) (interface{}, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		child := rv.MapIndex(reflect.ValueOf(segment).Convert(rv.Type().Key()))
+		if !child.IsValid() {
+			return nil, false
+		}
+		return child.Interface(), true
+	case reflect.Slice, reflect.Array:
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 || index >= rv.Len() {
+			return nil, false
+		}
+		return rv.Index(index).Interface(), true
+	}
+	return nil, false
+}
+
+// joinPath 拼接字段路径
+func joinPath(prefix, segment string) string {
+	if prefix == "" {
+		return segment
+	}
+	return prefix + "." + segment
+}
@@ -0,0 +1,149 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"laravel-go/framework/errors"
+)
+
+// Transformer 转换规则接口，在验证之前清理输入的值
+type Transformer interface {
+	Transform(value interface{}, params []string) (interface{}, error)
+}
+
+// TransformerFunc 转换规则函数
+type TransformerFunc func(value interface{}, params []string) (interface{}, error)
+
+// Transform 实现Transformer接口
+func (f TransformerFunc) Transform(value interface{}, params []string) (interface{}, error) {
+	return f(value, params)
+}
+
+// RegisterTransformer 注册转换规则
+func (v *Validator) RegisterTransformer(name string, transformer Transformer) {
+	v.transformers[name] = transformer
+}
+
+// isTransformer 检查规则是否为转换规则
+func (v *Validator) isTransformer(name string) bool {
+	_, ok := v.transformers[name]
+	return ok
+}
+
+// Transform 按规则转换数据，返回转换后的副本
+//
+// 规则中的转换规则（trim、lowercase、cast:int 等）按书写顺序执行，验证规则被忽略，
+// 因此同一组规则可以同时用于 Transform 和 Validate。输入中不存在或为 nil 的字段不转换。
+func (v *Validator) Transform(data map[string]interface{}, rules map[string]string) (map[string]interface{}, error) {
+	var validationErrors errors.ValidationErrors
+
+	result := make(map[string]interface{}, len(data))
+	for field, value := range data {
+		result[field] = value
+	}
+
+	for field, ruleString := range rules {
+		value, present := result[field]
+		if !present || value == nil {
+			continue
+		}
+
+		for _, rulePart := range strings.Split(ruleString, "|") {
+			ruleName, params := parseRule(rulePart)
+			transformer, ok := v.transformers[ruleName]
+			if !ok {
+				continue
+			}
+
+			transformed, err := transformer.Transform(value, params)
+			if err != nil {
+				validationErrors.AddRuleError(field, err.Error(), ruleName, value)
+				break
+			}
+			value = transformed
+		}
+		result[field] = value
+	}
+
+	if validationErrors.HasErrors() {
+		return result, validationErrors
+	}
+
+	return result, nil
+}
+
+// TransformAndValidate 先转换数据再验证，返回转换后的数据
+func (v *Validator) TransformAndValidate(data map[string]interface{}, rules map[string]string) (map[string]interface{}, error) {
+	result, err := v.Transform(data, rules)
+	if err != nil {
+		return result, err
+	}
+	return result, v.Validate(result, rules)
+}
+
+// registerDefaultTransformers 注册默认转换规则
+func (v *Validator) registerDefaultTransformers() {
+	// trim 去除字符串首尾空白
+	v.RegisterTransformer("trim", TransformerFunc(func(value interface{}, params []string) (interface{}, error) {
+		if s, ok := value.(string); ok {
+			return strings.TrimSpace(s), nil
+		}
+		return value, nil
+	}))
+
+	// lowercase 转换为小写
+	v.RegisterTransformer("lowercase", TransformerFunc(func(value interface{}, params []string) (interface{}, error) {
+		if s, ok := value.(string); ok {
+			return strings.ToLower(s), nil
+		}
+		return value, nil
+	}))
+
+	// uppercase 转换为大写
+	v.RegisterTransformer("uppercase", TransformerFunc(func(value interface{}, params []string) (interface{}, error) {
+		if s, ok := value.(string); ok {
+			return strings.ToUpper(s), nil
+		}
+		return value, nil
+	}))
+
+	// cast:int|float|bool|string 转换类型
+	v.RegisterTransformer("cast", TransformerFunc(func(value interface{}, params []string) (interface{}, error) {
+		if len(params) == 0 {
+			return nil, fmt.Errorf("cast requires a type parameter")
+		}
+		return castValue(value, params[0])
+	}))
+}
+
+// castValue 将值转换为指定类型
+func castValue(value interface{}, typ string) (interface{}, error) {
+	s := strings.TrimSpace(fmt.Sprint(value))
+
+	switch typ {
+	case "int":
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("field must be an integer")
+		}
+		return i, nil
+	case "float":
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field must be a number")
+		}
+		return f, nil
+	case "bool":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("field must be a boolean")
+		}
+		return b, nil
+	case "string":
+		return fmt.Sprint(value), nil
+	default:
+		return nil, fmt.Errorf("unsupported cast type: %s", typ)
+	}
+}
@@ -2,17 +2,33 @@ package validation
 
 import (
 	"fmt"
+	"mime/multipart"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
+	"laravel-go/framework/database"
 	"laravel-go/framework/errors"
+	"laravel-go/framework/translation"
 )
 
 // Validator 验证器
 type Validator struct {
-	rules map[string]Rule
+	rules        map[string]Rule
+	transformers map[string]Transformer
+	
+	// unique 和 exists 规则使用的数据库连接和允许查询的表
+	connection    database.Connection
+	allowedTables map[string]bool
+	
+	// stopOnFirstFailure 遇到第一个失败的字段后停止验证
+	stopOnFirstFailure bool
+	
+	// 翻译错误消息使用的翻译器和语言，未设置时使用全局翻译器的默认语言
+	translator *translation.Translator
+	locale     string
 }
 
 // Rule 验证规则接口
@@ -28,14 +44,35 @@ func (f RuleFunc) Validate(value interface{}) error {
 	return f(value)
 }
 
+// ParamRule 带参数的验证规则接口，例如 max:2048、mimes:png,jpg
+type ParamRule interface {
+	Rule
+	ValidateWithParams(value interface{}, params []string) error
+}
+
+// ParamRuleFunc 带参数的验证规则函数
+type ParamRuleFunc func(value interface{}, params []string) error
+
+// Validate 实现Rule接口
+func (f ParamRuleFunc) Validate(value interface{}) error {
+	return f(value, nil)
+}
+
+// ValidateWithParams 实现ParamRule接口
+func (f ParamRuleFunc) ValidateWithParams(value interface{}, params []string) error {
+	return f(value, params)
+}
+
 // NewValidator 创建新的验证器
 func NewValidator() *Validator {
 	v := &Validator{
-		rules: make(map[string]Rule),
+		rules:        make(map[string]Rule),
+		transformers: make(map[string]Transformer),
 	}
 	
 	// 注册默认规则
 	v.registerDefaultRules()
+	v.registerDefaultTransformers()
 	
 	return v
 }
@@ -45,36 +82,45 @@ func (v *Validator) RegisterRule(name string, rule Rule) {
 	v.rules[name] = rule
 }
 
+// StopOnFirstFailure 遇到第一个失败的字段后停止验证，返回的错误最多只有一条
+func (v *Validator) StopOnFirstFailure() *Validator {
+	v.stopOnFirstFailure = true
+	return v
+}
+
+// SetTranslator 设置翻译错误消息使用的翻译器和语言，locale 为空时使用翻译器的默认语言
+//
+// 错误消息的键为 validation.<rule>，例如 validation.required，可以使用以下占位符：
+// :attribute 字段名（存在 validation.attributes.<field> 时使用其翻译）、:value 字段值、
+// 规则第一个参数（以规则名作为占位符，例如 :min、:max）和 :values（所有参数以逗号连接）。
+// 没有翻译的规则使用规则返回的错误消息。
+func (v *Validator) SetTranslator(translator *translation.Translator, locale string) *Validator {
+	v.translator = translator
+	v.locale = locale
+	return v
+}
+
 // Validate 验证数据
+//
+// 字段按名称顺序验证，开启 StopOnFirstFailure 时在第一个失败的字段处停止。
 func (v *Validator) Validate(data map[string]interface{}, rules map[string]string) error {
 	var validationErrors errors.ValidationErrors
 	
-	for field, ruleString := range rules {
-		value, _ := data[field]
-		
+	fields := make([]string, 0, len(rules))
+	for field := range rules {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	
+	for _, field := range fields {
 		// 解析规则
-		ruleParts := strings.Split(ruleString, "|")
-		
-		for _, rulePart := range ruleParts {
-			ruleName := rulePart
-			
-			// 检查是否有参数
-			if strings.Contains(rulePart, ":") {
-				parts := strings.SplitN(rulePart, ":", 2)
-				ruleName = parts[0]
-				// 暂时不使用参数
-			}
-			
-			// 获取规则
-			rule, exists := v.rules[ruleName]
-			if !exists {
-				validationErrors.Add(field, fmt.Sprintf("Unknown validation rule: %s", ruleName), value)
-				continue
-			}
-			
-			// 执行验证
-			if err := rule.Validate(value); err != nil {
-				validationErrors.Add(field, err.Error(), value)
+		ruleParts := strings.Split(rules[field], "|")
+		
+		// 展开点号和通配符路径，例如 products.*.price
+		for _, item := range expandField(data, field) {
+			failed := v.validateField(item.path, item.value, item.present, ruleParts, &validationErrors)
+			if failed && v.stopOnFirstFailure {
+				return validationErrors
 			}
 		}
 	}
@@ -86,8 +132,121 @@ func (v *Validator) Validate(data map[string]interface{}, rules map[string]strin
 	return nil
 }
 
+// validateField 使用规则验证单个字段，返回字段是否验证失败
+func (v *Validator) validateField(field string, value interface{}, present bool, ruleParts []string, validationErrors *errors.ValidationErrors) bool {
+	// sometimes：字段不存在时跳过该字段的所有规则
+	if hasRule(ruleParts, "sometimes") && !present {
+		return false
+	}
+	// nullable：字段为 null 时只检查 required
+	nullable := hasRule(ruleParts, "nullable") && present && value == nil
+	// bail：字段第一个规则失败后不再验证后续规则
+	bail := hasRule(ruleParts, "bail") || v.stopOnFirstFailure
+	
+	failed := false
+	for _, rulePart := range ruleParts {
+		ruleName, params := parseRule(rulePart)
+		
+		if nullable && ruleName != "required" {
+			continue
+		}
+		
+		// 转换规则由 Transform 处理
+		if v.isTransformer(ruleName) {
+			continue
+		}
+		
+		// 获取规则
+		rule, exists := v.rules[ruleName]
+		if !exists {
+			validationErrors.AddRuleError(field, fmt.Sprintf("Unknown validation rule: %s", ruleName), "unknown_rule", value)
+			failed = true
+			if bail {
+				break
+			}
+			continue
+		}
+		
+		// 执行验证
+		var err error
+		if paramRule, ok := rule.(ParamRule); ok {
+			err = paramRule.ValidateWithParams(value, params)
+		} else {
+			err = rule.Validate(value)
+		}
+		if err != nil {
+			validationErrors.AddRuleError(field, v.message(field, ruleName, params, value, err), ruleName, value)
+			failed = true
+			if bail {
+				break
+			}
+		}
+	}
+	
+	return failed
+}
+
+// message 翻译规则的错误消息，没有翻译时使用规则返回的错误消息
+func (v *Validator) message(field, rule string, params []string, value interface{}, err error) string {
+	translator := v.translator
+	if translator == nil {
+		translator = translation.Default()
+	}
+	
+	key := "validation." + rule
+	if !translator.Has(key, v.locale) {
+		return err.Error()
+	}
+	
+	attribute := field
+	if attributeKey := "validation.attributes." + field; translator.Has(attributeKey, v.locale) {
+		attribute = translator.Get(attributeKey, nil, v.locale)
+	}
+	replacements := map[string]interface{}{
+		"attribute": attribute,
+		"values":    strings.Join(params, ", "),
+	}
+	if value != nil {
+		replacements["value"] = value
+	}
+	if len(params) > 0 {
+		replacements[rule] = params[0]
+	}
+	return translator.Get(key, replacements, v.locale)
+}
+
+// parseRule 解析规则名称和参数，例如 max:2048
+func parseRule(rulePart string) (string, []string) {
+	if !strings.Contains(rulePart, ":") {
+		return rulePart, nil
+	}
+	parts := strings.SplitN(rulePart, ":", 2)
+	return parts[0], strings.Split(parts[1], ",")
+}
+
+// hasRule 检查规则列表中是否包含指定的规则
+func hasRule(ruleParts []string, name string) bool {
+	for _, rulePart := range ruleParts {
+		if rulePart == name {
+			return true
+		}
+	}
+	return false
+}
+
 // registerDefaultRules 注册默认规则
 func (v *Validator) registerDefaultRules() {
+	// sometimes、nullable 和 bail 是控制规则，由 Validate 处理
+	v.RegisterRule("sometimes", RuleFunc(func(value interface{}) error {
+		return nil
+	}))
+	v.RegisterRule("nullable", RuleFunc(func(value interface{}) error {
+		return nil
+	}))
+	v.RegisterRule("bail", RuleFunc(func(value interface{}) error {
+		return nil
+	}))
+	
 	// required 规则
 	v.RegisterRule("required", RuleFunc(func(value interface{}) error {
 		if value == nil {
@@ -182,33 +341,57 @@ func (v *Validator) registerDefaultRules() {
 		return nil
 	}))
 	
-	// min 规则
-	v.RegisterRule("min", RuleFunc(func(value interface{}) error {
-		if value == nil {
+	// min 规则，文件按 KB 计算，字符串按字符数计算
+	v.RegisterRule("min", ParamRuleFunc(func(value interface{}, params []string) error {
+		size, limit, ok := measure(value, params)
+		if !ok {
 			return nil
 		}
 		
-		// 这里需要参数，暂时跳过
+		if size < limit {
+			return fmt.Errorf("field must be at least %v", params[0])
+		}
 		return nil
 	}))
 	
-	// max 规则
-	v.RegisterRule("max", RuleFunc(func(value interface{}) error {
-		if value == nil {
+	// max 规则，文件按 KB 计算，字符串按字符数计算
+	v.RegisterRule("max", ParamRuleFunc(func(value interface{}, params []string) error {
+		size, limit, ok := measure(value, params)
+		if !ok {
 			return nil
 		}
 		
-		// 这里需要参数，暂时跳过
+		if size > limit {
+			return fmt.Errorf("field may not be greater than %v", params[0])
+		}
 		return nil
 	}))
 	
-	// unique 规则
-	v.RegisterRule("unique", RuleFunc(func(value interface{}) error {
+	// file 规则
+	v.RegisterRule("file", RuleFunc(func(value interface{}) error {
 		if value == nil {
 			return nil
 		}
 		
-		// 这里需要数据库查询，暂时跳过
+		if _, ok := value.(*multipart.FileHeader); !ok {
+			return fmt.Errorf("field must be a file")
+		}
 		return nil
 	}))
+	
+	// mimes 规则，同时检查扩展名和文件内容
+	v.RegisterRule("mimes", ParamRuleFunc(func(value interface{}, params []string) error {
+		header, ok := value.(*multipart.FileHeader)
+		if !ok || len(params) == 0 {
+			return nil
+		}
+		
+		if !MatchesMimes(header, params) {
+			return fmt.Errorf("field must be a file of type: %s", strings.Join(params, ", "))
+		}
+		return nil
+	}))
+	
+	// unique 和 exists 规则
+	v.registerDatabaseRules()
 } 
\ No newline at end of file
@@ -2,7 +2,12 @@ package validation
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
+
+	"laravel-go/framework/database"
+	"laravel-go/framework/errors"
+	"laravel-go/framework/translation"
 )
 
 func TestNewValidator(t *testing.T) {
@@ -278,3 +283,244 @@ func TestValidationErrors(t *testing.T) {
 		t.Error("Expected non-empty error message")
 	}
 }
+
+func TestMinMaxRules(t *testing.T) {
+	validator := NewValidator()
+
+	rules := map[string]string{"name": "min:2|max:5", "tags": "max:2", "age": "min:18"}
+	if err := validator.Validate(map[string]interface{}{"name": "张三", "tags": []string{"a"}, "age": 20}, rules); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	err := validator.Validate(map[string]interface{}{"name": "abcdef", "tags": []string{"a", "b", "c"}, "age": 17}, rules)
+	validationErrors, ok := err.(errors.ValidationErrors)
+	if !ok || len(validationErrors) != 3 {
+		t.Errorf("Expected 3 validation errors, got: %v", err)
+	}
+}
+
+func TestSometimesAndNullableRules(t *testing.T) {
+	validator := NewValidator()
+
+	rules := map[string]string{
+		"name":     "sometimes|required|string|max:5",
+		"email":    "sometimes|email",
+		"nickname": "nullable|string|min:2",
+	}
+
+	// 部分更新：省略的字段跳过校验，显式 null 允许通过
+	if err := validator.Validate(map[string]interface{}{"nickname": nil}, rules); err != nil {
+		t.Errorf("Expected no error for partial update, got: %v", err)
+	}
+
+	// 存在但无效的字段仍然校验失败
+	err := validator.Validate(map[string]interface{}{"name": "", "email": "invalid", "nickname": "a"}, rules)
+	validationErrors, ok := err.(errors.ValidationErrors)
+	if !ok || len(validationErrors) != 3 {
+		t.Errorf("Expected 3 validation errors, got: %v", err)
+	}
+
+	// nullable 不影响 required
+	err = validator.Validate(map[string]interface{}{"title": nil}, map[string]string{"title": "required|nullable|string"})
+	if err == nil {
+		t.Error("Expected required to fail for null value")
+	}
+}
+
+func TestNestedAndArrayRules(t *testing.T) {
+	validator := NewValidator()
+
+	rules := map[string]string{
+		"address.zip":      "required",
+		"products.*.name":  "required|string",
+		"products.*.price": "required|int",
+	}
+
+	order := map[string]interface{}{
+		"address": map[string]interface{}{"zip": "100000"},
+		"products": []interface{}{
+			map[string]interface{}{"name": "book", "price": 10},
+			map[string]interface{}{"name": "pen", "price": "cheap"},
+		},
+	}
+
+	err := validator.Validate(order, rules)
+	validationErrors, ok := err.(errors.ValidationErrors)
+	if !ok || len(validationErrors) != 1 {
+		t.Fatalf("Expected 1 validation error, got: %v", err)
+	}
+	if validationErrors[0].Field != "products.1.price" {
+		t.Errorf("Expected error on products.1.price, got: %s", validationErrors[0].Field)
+	}
+
+	// 缺少嵌套字段
+	err = validator.Validate(map[string]interface{}{"address": map[string]interface{}{}}, rules)
+	validationErrors, ok = err.(errors.ValidationErrors)
+	if !ok || len(validationErrors) != 1 || validationErrors[0].Field != "address.zip" {
+		t.Errorf("Expected error on address.zip, got: %v", err)
+	}
+}
+
+func TestDatabaseRules(t *testing.T) {
+	conn, err := database.NewConnection(&database.ConnectionConfig{
+		Driver:   database.SQLite,
+		Database: filepath.Join(t.TempDir(), "validation.db"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create connection: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT)"); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := conn.Exec("INSERT INTO users (id, email) VALUES (?, ?)", 1, "taken@example.com"); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	validator := NewValidator()
+	validator.SetConnection(conn)
+	validator.AllowTables("users")
+
+	// 重复的邮箱校验失败
+	if err := validator.Validate(map[string]interface{}{"email": "taken@example.com"}, map[string]string{"email": "unique:users,email"}); err == nil {
+		t.Error("Expected unique to fail on duplicate email")
+	}
+	if err := validator.Validate(map[string]interface{}{"email": "new@example.com"}, map[string]string{"email": "unique:users,email"}); err != nil {
+		t.Errorf("Expected unique to pass, got: %v", err)
+	}
+
+	// 更新时排除当前记录
+	if err := validator.Validate(map[string]interface{}{"email": "taken@example.com"}, map[string]string{"email": "unique:users,email,1"}); err != nil {
+		t.Errorf("Expected unique to ignore current record, got: %v", err)
+	}
+
+	// exists
+	if err := validator.Validate(map[string]interface{}{"user_id": 1}, map[string]string{"user_id": "exists:users,id"}); err != nil {
+		t.Errorf("Expected exists to pass, got: %v", err)
+	}
+	if err := validator.Validate(map[string]interface{}{"user_id": 2}, map[string]string{"user_id": "exists:users,id"}); err == nil {
+		t.Error("Expected exists to fail for missing user")
+	}
+
+	// 不允许的表名和列名
+	if err := validator.Validate(map[string]interface{}{"user_id": 1}, map[string]string{"user_id": "exists:orders,id"}); err == nil {
+		t.Error("Expected exists to reject table outside allowlist")
+	}
+	if err := validator.Validate(map[string]interface{}{"email": "x"}, map[string]string{"email": "unique:users,email;DROP TABLE users"}); err == nil {
+		t.Error("Expected unique to reject invalid column name")
+	}
+}
+
+func TestTransform(t *testing.T) {
+	validator := NewValidator()
+
+	rules := map[string]string{
+		"email": "trim|lowercase|required|email",
+		"age":   "cast:int|int|min:18",
+	}
+
+	data, err := validator.TransformAndValidate(map[string]interface{}{"email": "  John@Example.COM ", "age": "20"}, rules)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if data["email"] != "john@example.com" {
+		t.Errorf("Expected normalized email, got: %q", data["email"])
+	}
+	if data["age"] != 20 {
+		t.Errorf("Expected age cast to int, got: %#v", data["age"])
+	}
+
+	// 转换失败
+	if _, err := validator.Transform(map[string]interface{}{"age": "abc"}, rules); err == nil {
+		t.Error("Expected cast to fail for non-numeric string")
+	}
+}
+
+func TestBailAndStopOnFirstFailure(t *testing.T) {
+	data := map[string]interface{}{"email": 123, "name": ""}
+	rules := map[string]string{"email": "bail|string|email", "name": "required|min:2"}
+
+	// bail 在字段第一个失败的规则处停止
+	err := NewValidator().Validate(data, rules)
+	validationErrors, ok := err.(errors.ValidationErrors)
+	if !ok || len(validationErrors) != 3 {
+		t.Fatalf("Expected 3 validation errors, got: %v", err)
+	}
+	for _, validationError := range validationErrors {
+		if validationError.Field == "email" && validationError.Message != "field must be a string" {
+			t.Errorf("Expected bail to stop after string rule, got: %s", validationError.Message)
+		}
+	}
+
+	// 全局模式在第一个失败的字段处停止
+	err = NewValidator().StopOnFirstFailure().Validate(data, rules)
+	validationErrors, ok = err.(errors.ValidationErrors)
+	if !ok || len(validationErrors) != 1 || validationErrors[0].Field != "email" {
+		t.Errorf("Expected 1 validation error on email, got: %v", err)
+	}
+}
+
+func TestValidationErrorCodes(t *testing.T) {
+	validator := NewValidator()
+
+	err := validator.Validate(map[string]interface{}{"password": 123}, map[string]string{"password": "string|min:200"})
+	validationErrors, ok := err.(errors.ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected validation errors, got: %v", err)
+	}
+
+	codes := validationErrors.ToCodeMap()["password"]
+	if len(codes) != 2 || codes[0] != "VALIDATION_STRING" || codes[1] != "VALIDATION_MIN" {
+		t.Errorf("Expected coded entries for string and min, got: %v", codes)
+	}
+	if validationErrors[1].Rule != "min" {
+		t.Errorf("Expected rule min, got: %s", validationErrors[1].Rule)
+	}
+
+	body, err := validationErrors.ToJSON()
+	if err != nil {
+		t.Fatalf("Failed to encode errors: %v", err)
+	}
+	expected := `{"errors":{"password":["field must be a string","field must be at least 200"]}}`
+	if string(body) != expected {
+		t.Errorf("Expected %s, got %s", expected, body)
+	}
+}
+
+func TestTranslatedMessages(t *testing.T) {
+	translator := translation.NewTranslator("zh-CN", "en")
+	translator.AddMessages("en", map[string]interface{}{
+		"validation": map[string]interface{}{
+			"required": "The :attribute field is required.",
+			"min":      "The :attribute must be at least :min.",
+		},
+	})
+	translator.AddMessages("zh-CN", map[string]interface{}{
+		"validation": map[string]interface{}{
+			"required":   ":attribute不能为空",
+			"attributes": map[string]interface{}{"name": "姓名"},
+		},
+	})
+
+	data := map[string]interface{}{"name": "", "password": "abc", "email": "bad"}
+	rules := map[string]string{"name": "required", "password": "min:8", "email": "email"}
+	err := NewValidator().SetTranslator(translator, "").Validate(data, rules)
+	validationErrors, ok := err.(errors.ValidationErrors)
+	if !ok {
+		t.Fatalf("Expected validation errors, got: %v", err)
+	}
+
+	messages := validationErrors.ToMap()
+	if messages["name"][0] != "姓名不能为空" {
+		t.Errorf("Expected translated message with attribute name, got: %s", messages["name"][0])
+	}
+	// 当前语言缺少的消息使用回退语言
+	if messages["password"][0] != "The password must be at least 8." {
+		t.Errorf("Expected fallback message, got: %s", messages["password"][0])
+	}
+	// 没有翻译的规则使用规则返回的消息
+	if messages["email"][0] != "field must be a valid email address" {
+		t.Errorf("Expected rule message, got: %s", messages["email"][0])
+	}
+}
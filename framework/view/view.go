@@ -0,0 +1,419 @@
+package view
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrViewNotFound 视图文件不存在
+var ErrViewNotFound = errors.New("view not found")
+
+// extendsPattern 匹配视图开头的 {{ extends "layouts/app" }} 指令
+var extendsPattern = regexp.MustCompile(`^\s*\{\{-?\s*extends\s+"([^"]+)"\s*-?\}\}`)
+
+// Config 视图配置
+type Config struct {
+	Path      string   // 视图目录，默认 resources/views
+	Extension string   // 视图文件扩展名，默认 .html
+	Partials  []string // 自动加载的局部视图目录（相对 Path），默认 partials
+	Cache     bool     // 缓存解析后的模板，生产环境开启；关闭时每次渲染都重新读取文件
+}
+
+// DefaultConfig 默认配置，APP_ENV 为 production 时开启缓存
+func DefaultConfig() Config {
+	return Config{
+		Path:      filepath.Join("resources", "views"),
+		Extension: ".html",
+		Partials:  []string{"partials"},
+		Cache:     os.Getenv("APP_ENV") == "production",
+	}
+}
+
+// Engine 视图引擎，基于 html/template，输出自动转义
+//
+// 视图名称为相对视图目录、不带扩展名的路径，例如 pages/home。视图可以在开头声明
+// {{ extends "layouts/app" }} 继承布局：布局用 {{ block "content" . }}{{ end }} 定义可覆盖的块，
+// 视图用 {{ define "content" }}...{{ end }} 覆盖，布局可以继续继承其他布局。
+// 局部视图目录中的文件会加载到每个视图中，通过 {{ template "partials/nav" . }} 引用。
+type Engine struct {
+	config Config
+
+	mu         sync.RWMutex
+	cache      map[string]*template.Template
+	funcs      template.FuncMap
+	components map[string]*template.Template
+	routes     map[string]string
+}
+
+// New 创建视图引擎，未设置的配置项使用默认值
+func New(config Config) *Engine {
+	defaults := DefaultConfig()
+	if config.Path == "" {
+		config.Path = defaults.Path
+	}
+	if config.Extension == "" {
+		config.Extension = defaults.Extension
+	}
+	if config.Partials == nil {
+		config.Partials = defaults.Partials
+	}
+
+	return &Engine{
+		config:     config,
+		cache:      make(map[string]*template.Template),
+		funcs:      make(template.FuncMap),
+		components: make(map[string]*template.Template),
+		routes:     make(map[string]string),
+	}
+}
+
+// AddFunc 注册模板函数，需要在第一次渲染前注册
+func (e *Engine) AddFunc(name string, fn interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.funcs[name] = fn
+	e.cache = make(map[string]*template.Template)
+}
+
+// Component 注册组件，模板中通过 {{ component "alert" (dict "type" "error") }} 渲染
+//
+// 组件以 props 作为数据渲染。未注册的组件从视图目录的 components 目录加载。
+func (e *Engine) Component(name, source string) error {
+	tmpl, err := template.New(name).Funcs(e.templateFuncs()).Parse(source)
+	if err != nil {
+		return fmt.Errorf("failed to parse component %s: %w", name, err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.components[name] = tmpl
+	return nil
+}
+
+// Route 注册命名路由，pattern 中的 {param} 在生成 URL 时替换
+func (e *Engine) Route(name, pattern string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.routes[name] = pattern
+}
+
+// URL 生成命名路由的 URL，模式中没有的参数作为查询字符串
+func (e *Engine) URL(name string, params map[string]interface{}) (string, error) {
+	e.mu.RLock()
+	pattern, exists := e.routes[name]
+	e.mu.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("route %s is not defined", name)
+	}
+
+	query := url.Values{}
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := fmt.Sprint(params[key])
+		placeholder := "{" + key + "}"
+		if strings.Contains(pattern, placeholder) {
+			pattern = strings.ReplaceAll(pattern, placeholder, url.PathEscape(value))
+		} else {
+			query.Set(key, value)
+		}
+	}
+	if strings.Contains(pattern, "{") {
+		return "", fmt.Errorf("missing parameters for route %s: %s", name, pattern)
+	}
+	if len(query) > 0 {
+		pattern += "?" + query.Encode()
+	}
+	return pattern, nil
+}
+
+// Render 渲染视图到 w，w 为 http.ResponseWriter 且未设置 Content-Type 时设置为 text/html
+//
+// 先渲染到缓冲区，出错时不会向 w 写入不完整的内容。
+func (e *Engine) Render(w io.Writer, name string, data interface{}) error {
+	var buf bytes.Buffer
+	if err := e.render(&buf, name, data); err != nil {
+		return err
+	}
+
+	if rw, ok := w.(http.ResponseWriter); ok && rw.Header().Get("Content-Type") == "" {
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// RenderString 渲染视图为字符串
+func (e *Engine) RenderString(name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := e.render(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ClearCache 清除已解析的模板
+func (e *Engine) ClearCache() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.cache = make(map[string]*template.Template)
+}
+
+// render 执行视图，最外层布局为执行入口
+func (e *Engine) render(w io.Writer, name string, data interface{}) error {
+	tmpl, err := e.load(name)
+	if err != nil {
+		return err
+	}
+	if err := tmpl.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render view %s: %w", name, err)
+	}
+	return nil
+}
+
+// load 获取解析后的视图，开启缓存时只解析一次
+func (e *Engine) load(name string) (*template.Template, error) {
+	if e.config.Cache {
+		e.mu.RLock()
+		tmpl, exists := e.cache[name]
+		e.mu.RUnlock()
+		if exists {
+			return tmpl, nil
+		}
+	}
+
+	tmpl, err := e.parse(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.config.Cache {
+		e.mu.Lock()
+		e.cache[name] = tmpl
+		e.mu.Unlock()
+	}
+	return tmpl, nil
+}
+
+// parse 解析视图及其继承的布局和局部视图
+//
+// 先解析最外层布局和局部视图，再沿继承链向内解析，后解析的 define 覆盖先解析的 block。
+func (e *Engine) parse(name string) (*template.Template, error) {
+	var chain []string
+	var sources []string
+	seen := make(map[string]bool)
+	for current := name; current != ""; {
+		if seen[current] {
+			return nil, fmt.Errorf("view %s extends itself through %s", name, current)
+		}
+		seen[current] = true
+
+		source, err := e.read(current)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, current)
+		sources = append(sources, source)
+
+		current = ""
+		if match := extendsPattern.FindStringSubmatch(source); match != nil {
+			current = match[1]
+		}
+	}
+
+	last := len(chain) - 1
+	root, err := template.New(chain[last]).Funcs(e.templateFuncs()).Parse(sources[last])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse view %s: %w", chain[last], err)
+	}
+
+	partials, err := e.partials()
+	if err != nil {
+		return nil, err
+	}
+	for _, partial := range partials {
+		if seen[partial.name] {
+			continue
+		}
+		if _, err := root.New(partial.name).Parse(partial.source); err != nil {
+			return nil, fmt.Errorf("failed to parse partial %s: %w", partial.name, err)
+		}
+	}
+
+	for i := last - 1; i >= 0; i-- {
+		if _, err := root.New(chain[i]).Parse(sources[i]); err != nil {
+			return nil, fmt.Errorf("failed to parse view %s: %w", chain[i], err)
+		}
+	}
+	return root, nil
+}
+
+type partialSource struct {
+	name   string
+	source string
+}
+
+// partials 读取局部视图目录中的所有视图
+func (e *Engine) partials() ([]partialSource, error) {
+	var partials []partialSource
+	for _, dir := range e.config.Partials {
+		root := filepath.Join(e.config.Path, dir)
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+		err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !strings.HasSuffix(path, e.config.Extension) {
+				return nil
+			}
+
+			source, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(e.config.Path, path)
+			if err != nil {
+				return err
+			}
+			name := filepath.ToSlash(strings.TrimSuffix(rel, e.config.Extension))
+			partials = append(partials, partialSource{name: name, source: string(source)})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load partials from %s: %w", root, err)
+		}
+	}
+	return partials, nil
+}
+
+// read 读取视图文件
+func (e *Engine) read(name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || strings.HasPrefix(clean, "..") {
+		return "", fmt.Errorf("%w: %s", ErrViewNotFound, name)
+	}
+
+	source, err := os.ReadFile(filepath.Join(e.config.Path, clean+e.config.Extension))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrViewNotFound, name)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(source), nil
+}
+
+// renderComponent 渲染组件，输出已经转义，作为 template.HTML 插入
+func (e *Engine) renderComponent(name string, props interface{}) (template.HTML, error) {
+	// 组件文件与视图共用缓存，键带 components/ 前缀
+	e.mu.RLock()
+	tmpl, exists := e.components[name]
+	if !exists {
+		tmpl, exists = e.cache["components/"+name]
+	}
+	e.mu.RUnlock()
+
+	if !exists {
+		source, err := e.read("components/" + name)
+		if err != nil {
+			return "", fmt.Errorf("component %s is not registered: %w", name, err)
+		}
+		if tmpl, err = template.New(name).Funcs(e.templateFuncs()).Parse(source); err != nil {
+			return "", fmt.Errorf("failed to parse component %s: %w", name, err)
+		}
+		if e.config.Cache {
+			e.mu.Lock()
+			e.cache["components/"+name] = tmpl
+			e.mu.Unlock()
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, props); err != nil {
+		return "", fmt.Errorf("failed to render component %s: %w", name, err)
+	}
+	return template.HTML(buf.String()), nil
+}
+
+// templateFuncs 模板函数：内置函数和注册的函数
+func (e *Engine) templateFuncs() template.FuncMap {
+	funcs := template.FuncMap{
+		// extends 只作为继承声明，渲染时不输出内容
+		"extends":   func(string) string { return "" },
+		"component": e.renderComponent,
+		"dict":      dict,
+		"route": func(name string, pairs ...interface{}) (string, error) {
+			params, err := dict(pairs...)
+			if err != nil {
+				return "", err
+			}
+			return e.URL(name, params)
+		},
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for name, fn := range e.funcs {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// dict 将键值对转换为 map，用于向组件和局部视图传递多个参数
+func dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict requires key-value pairs")
+	}
+	result := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+		}
+		result[key] = pairs[i+1]
+	}
+	return result, nil
+}
+
+var (
+	defaultEngine *Engine
+	defaultMutex  sync.Mutex
+)
+
+// SetDefault 设置全局视图引擎
+func SetDefault(engine *Engine) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	defaultEngine = engine
+}
+
+// Default 获取全局视图引擎，未设置时使用默认配置创建
+func Default() *Engine {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+	if defaultEngine == nil {
+		defaultEngine = New(DefaultConfig())
+	}
+	return defaultEngine
+}
+
+// Render 使用全局视图引擎渲染视图
+func Render(w io.Writer, name string, data interface{}) error {
+	return Default().Render(w, name, data)
+}
@@ -0,0 +1,114 @@
+package view
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeViews(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create view directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write view: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestLayoutComposition(t *testing.T) {
+	dir := writeViews(t, map[string]string{
+		"layouts/base.html":     `<html><title>{{ block "title" . }}App{{ end }}</title><body>{{ block "body" . }}{{ end }}</body></html>`,
+		"layouts/app.html":      `{{ extends "layouts/base" }}{{ define "body" }}<main>{{ block "content" . }}empty{{ end }}</main>{{ template "partials/footer" . }}{{ end }}`,
+		"pages/home.html":       `{{ extends "layouts/app" }}{{ define "title" }}Home{{ end }}{{ define "content" }}<h1>Hello {{ .Name }}</h1>{{ template "partials/nav" . }}{{ end }}`,
+		"pages/plain.html":      `{{ extends "layouts/app" }}`,
+		"partials/nav.html":     `<nav><a href="{{ route "users.show" "id" .ID "tab" "posts" }}">{{ .Name }}</a></nav>`,
+		"partials/footer.html":  `<footer>{{ component "badge" (dict "Label" .Name) }}</footer>`,
+		"components/badge.html": `<span class="badge">{{ .Label }}</span>`,
+	})
+
+	engine := New(Config{Path: dir})
+	engine.Route("users.show", "/users/{id}")
+
+	html, err := engine.RenderString("pages/home", map[string]interface{}{"Name": "<Bob>", "ID": 7})
+	if err != nil {
+		t.Fatalf("Failed to render view: %v", err)
+	}
+	expected := `<html><title>Home</title><body><main><h1>Hello &lt;Bob&gt;</h1>` +
+		`<nav><a href="/users/7?tab=posts">&lt;Bob&gt;</a></nav></main>` +
+		`<footer><span class="badge">&lt;Bob&gt;</span></footer></body></html>`
+	if html != expected {
+		t.Errorf("Unexpected output:\n%s\nexpected:\n%s", html, expected)
+	}
+
+	// 未覆盖的块使用布局中的默认内容
+	html, err = engine.RenderString("pages/plain", map[string]interface{}{"Name": "x"})
+	if err != nil {
+		t.Fatalf("Failed to render view: %v", err)
+	}
+	if !strings.Contains(html, "<title>App</title>") || !strings.Contains(html, "<main>empty</main>") {
+		t.Errorf("Expected layout defaults, got %s", html)
+	}
+
+	recorder := httptest.NewRecorder()
+	if err := engine.Render(recorder, "pages/plain", map[string]interface{}{"Name": "x"}); err != nil {
+		t.Fatalf("Failed to render to response: %v", err)
+	}
+	if recorder.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Errorf("Unexpected content type: %s", recorder.Header().Get("Content-Type"))
+	}
+
+	if _, err := engine.RenderString("pages/missing", nil); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("Expected ErrViewNotFound, got %v", err)
+	}
+}
+
+func TestPartialRendersWithinParent(t *testing.T) {
+	dir := writeViews(t, map[string]string{
+		"pages/list.html":    `<ul>{{ range .Items }}{{ template "partials/item" . }}{{ end }}</ul>`,
+		"partials/item.html": `<li>{{ . }}</li>`,
+	})
+
+	engine := New(Config{Path: dir})
+	html, err := engine.RenderString("pages/list", map[string]interface{}{"Items": []string{"a", "<b>"}})
+	if err != nil {
+		t.Fatalf("Failed to render view: %v", err)
+	}
+	if html != "<ul><li>a</li><li>&lt;b&gt;</li></ul>" {
+		t.Errorf("Unexpected output: %s", html)
+	}
+}
+
+func TestViewCache(t *testing.T) {
+	dir := writeViews(t, map[string]string{"page.html": `v1`})
+
+	cached := New(Config{Path: dir, Cache: true})
+	reloading := New(Config{Path: dir})
+	for _, engine := range []*Engine{cached, reloading} {
+		if html, _ := engine.RenderString("page", nil); html != "v1" {
+			t.Fatalf("Unexpected output: %s", html)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(`v2`), 0644); err != nil {
+		t.Fatalf("Failed to update view: %v", err)
+	}
+	if html, _ := cached.RenderString("page", nil); html != "v1" {
+		t.Errorf("Expected cached view, got %s", html)
+	}
+	if html, _ := reloading.RenderString("page", nil); html != "v2" {
+		t.Errorf("Expected reloaded view, got %s", html)
+	}
+
+	cached.ClearCache()
+	if html, _ := cached.RenderString("page", nil); html != "v2" {
+		t.Errorf("Expected view to reload after ClearCache, got %s", html)
+	}
+}